@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/audit"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+var (
+	auditListCommand string
+	auditListSince   time.Duration
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Query the audit log of the minikube commands run",
+	Long: `Queries the audit log of the minikube commands run, by who, when and with what arguments.
+The audit log is rotated once it reaches the size set with 'minikube config set audit.max-size', 5MB by default, keeping the number of rotated logs set with audit.max-backups, 3 by default.
+The audit events can also be forwarded as they are logged, with 'minikube config set audit.forward': to the local syslog with "syslog", to a remote syslog with a udp:// or tcp:// URL, or to a webhook with a http:// or https:// URL.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube audit [list]")
+	},
+}
+
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the commands of the audit log",
+	Long: `Lists the commands of the audit log and of its rotated logs, from the oldest.
+The commands can be selected by the user and the profile they were run with, using the --user and --profile flags, by command and by how long ago they were started.`,
+	Example: `minikube audit list --user=ci --since=24h
+minikube audit list -p foo --command=start --output=json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		f := audit.Filter{Command: auditListCommand}
+		if cmd.Flags().Changed(config.UserFlag) {
+			f.User = viper.GetString(config.UserFlag)
+		}
+		if cmd.Flags().Changed(config.ProfileName) {
+			f.Profile = ClusterFlagValue()
+		}
+		if auditListSince < 0 {
+			exit.Message(reason.Usage, "--since must be a positive duration, such as 24h")
+		}
+		if auditListSince > 0 {
+			f.Since = time.Now().Add(-auditListSince)
+		}
+
+		r, err := audit.Query(f)
+		if err != nil {
+			exit.Error(reason.HostAuditLog, "Unable to query the audit log", err)
+		}
+		if outputFormat == "json" {
+			printJSON(r.Rows())
+			return
+		}
+		if n := r.Skipped(); n > 0 {
+			out.WarningT("Skipped {{.count}} malformed lines of the audit log", out.V{"count": n})
+		}
+		out.String("%s", r.ASCIITable())
+	},
+}
+
+func init() {
+	auditListCmd.Flags().StringVar(&auditListCommand, "command", "", "List only the runs of this command, such as start")
+	auditListCmd.Flags().DurationVar(&auditListSince, "since", 0, "List only the commands started within this duration, such as 24h")
+	auditCmd.AddCommand(auditListCmd)
+}
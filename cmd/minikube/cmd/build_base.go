@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	buildBaseFrom     string
+	buildBasePackages []string
+	buildBaseFiles    []string
+	buildBaseTag      string
+	buildBaseDriver   string
+)
+
+// buildBaseCmd represents the build-base command
+var buildBaseCmd = &cobra.Command{
+	Use:   "build-base",
+	Short: "Builds a custom base image for the docker and podman drivers",
+	Long: `Builds a base image for the docker and podman drivers, adding packages and files to the minikube one.
+The image records what it was built from, so that 'minikube start --base-image' can use it, and 'minikube update-check' warns when it falls behind the base image of minikube.`,
+	Example: `minikube build-base --add-pkg=nfs-common --add-file=corp-ca.crt:/usr/local/share/ca-certificates/corp-ca.crt --tag=my-kicbase:v1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if buildBaseTag == "" {
+			exit.Message(reason.Usage, "Please specify the tag of the image to build, using --tag")
+		}
+		if buildBaseDriver != oci.Docker && buildBaseDriver != oci.Podman {
+			exit.Message(reason.Usage, "The {{.driver}} driver does not use base images, supported drivers are docker and podman", out.V{"driver": buildBaseDriver})
+		}
+
+		b := kic.CustomBase{From: buildBaseFrom, Packages: buildBasePackages}
+		for _, f := range buildBaseFiles {
+			cf, err := parseCustomFile(f)
+			if err != nil {
+				exit.Message(reason.Usage, "Invalid --add-file {{.file}}: {{.error}}", out.V{"file": f, "error": err})
+			}
+			b.Files = append(b.Files, cf)
+		}
+
+		out.Step(style.Waiting, "Building {{.tag}} from {{.from}} ...", out.V{"tag": buildBaseTag, "from": buildBaseFrom})
+		if err := kic.BuildCustomBase(buildBaseDriver, buildBaseTag, b, version.GetVersion()); err != nil {
+			exit.Error(reason.HostBaseImageBuild, "Failed to build the base image", err)
+		}
+		out.Step(style.Success, "Built {{.tag}}", out.V{"tag": buildBaseTag})
+		out.Styled(style.Tip, "To start a cluster with it, run: minikube start --driver={{.driver}} --base-image={{.tag}}", out.V{"driver": buildBaseDriver, "tag": buildBaseTag})
+	},
+}
+
+// parseCustomFile parses a file to add to a base image, given as <host path>:<absolute path in the image>
+func parseCustomFile(s string) (kic.CustomFile, error) {
+	i := strings.LastIndex(s, ":")
+	if i <= 0 || i == len(s)-1 {
+		return kic.CustomFile{}, errors.New("expected <host path>:<path in the image>")
+	}
+	src, dst := s[:i], s[i+1:]
+	if !path.IsAbs(dst) {
+		return kic.CustomFile{}, errors.Errorf("%s is not an absolute path", dst)
+	}
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return kic.CustomFile{}, err
+	}
+	st, err := os.Stat(src)
+	if err != nil {
+		return kic.CustomFile{}, err
+	}
+	if st.IsDir() {
+		return kic.CustomFile{}, errors.Errorf("%s is a directory", src)
+	}
+	return kic.CustomFile{Src: src, Dst: dst}, nil
+}
+
+func init() {
+	buildBaseCmd.Flags().StringVar(&buildBaseFrom, "from", kic.BaseImage, "The base image to build the image from")
+	buildBaseCmd.Flags().StringArrayVar(&buildBasePackages, "add-pkg", nil, "A package to install in the image, can be given multiple times")
+	buildBaseCmd.Flags().StringArrayVar(&buildBaseFiles, "add-file", nil, "A file to add to the image, as <host path>:<absolute path in the image>, can be given multiple times. Certificates added under /usr/local/share/ca-certificates are trusted")
+	buildBaseCmd.Flags().StringVarP(&buildBaseTag, "tag", "t", "", "The tag of the image to build (required)")
+	buildBaseCmd.Flags().StringVar(&buildBaseDriver, "driver", oci.Docker, "The driver to build the image for, docker or podman")
+}
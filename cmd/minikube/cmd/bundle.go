@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/bundle"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	bundleK8sVersion string
+	bundleDriver     string
+	bundleRuntime    string
+	bundleAddons     []string
+	bundleFile       string
+)
+
+// bundleCmd represents the set of bundle subcommands
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Manage offline bundles",
+	Long:  "Operations on offline bundles, single files with everything 'minikube start' needs to start a cluster on a host without network access",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube bundle [create]")
+	},
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Creates an offline bundle for 'minikube start --bundle'",
+	Long: `Downloads the boot image, driver, Kubernetes binaries and images, and the images of the default and given addons, and packs them into a single file.
+'minikube start --bundle' then starts a cluster from this file, without network access. The bundle must be used with this minikube version, on hosts of the same OS and architecture.`,
+	Example: "minikube bundle create --kubernetes-version=v1.21.2 --driver=docker -f bundle.tar.zst",
+	Run: func(cmd *cobra.Command, args []string) {
+		if bundleDriver == "" {
+			exit.Message(reason.Usage, "Please specify the driver of the clusters to start with the bundle, using --driver")
+		}
+		if !bundle.Supported(bundleDriver) {
+			exit.Message(reason.Usage, "The {{.driver}} driver is not supported by offline bundles, supported drivers are docker and the VM drivers", out.V{"driver": bundleDriver})
+		}
+		version, err := util.ParseKubernetesVersion(bundleK8sVersion)
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid Kubernetes version {{.version}}: {{.error}}", out.V{"version": bundleK8sVersion, "error": err})
+		}
+		k8sVersion := "v" + version.String()
+
+		m, err := bundle.Create(bundle.Options{
+			KubernetesVersion: k8sVersion,
+			Driver:            bundleDriver,
+			ContainerRuntime:  validContainerRuntime(bundleRuntime),
+			Addons:            bundleAddons,
+		}, bundleFile)
+		if err != nil {
+			exit.Error(reason.HostBundleCreate, "Failed to create the bundle", err)
+		}
+		out.Step(style.Success, "Created {{.path}} with {{.files}} files and {{.images}} images", out.V{"path": bundleFile, "files": len(m.Files), "images": len(m.Images)})
+		out.Styled(style.Tip, "Copy it to the offline host, and run: minikube start --bundle={{.path}}", out.V{"path": bundleFile})
+	},
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVar(&bundleK8sVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version of the clusters to start with the bundle")
+	bundleCreateCmd.Flags().StringVar(&bundleDriver, "driver", "", "The driver of the clusters to start with the bundle (required)")
+	bundleCreateCmd.Flags().StringVar(&bundleRuntime, "container-runtime", constants.DefaultContainerRuntime, "The container runtime of the clusters to start with the bundle. Valid options: docker, cri-o, containerd")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleAddons, "addons", nil, "Addons to include the images of, in addition to the addons enabled by default")
+	bundleCreateCmd.Flags().StringVarP(&bundleFile, "file", "f", "minikube-bundle.tar.zst", "The file to write the bundle to")
+	bundleCmd.AddCommand(bundleCreateCmd)
+}
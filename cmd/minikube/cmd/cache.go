@@ -34,7 +34,8 @@ import (
 const cacheImageConfigKey = "cache"
 
 var (
-	all string
+	all           string
+	cachePlatform string
 )
 
 // cacheCmd represents the cache command
@@ -52,7 +53,7 @@ var addCacheCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		out.WarningT("\"minikube cache\" will be deprecated in upcoming versions, please switch to \"minikube image load\"")
 		// Cache and load images into docker daemon
-		if err := machine.CacheAndLoadImages(args, cacheAddProfiles(), false); err != nil {
+		if err := machine.CacheAndLoadImages(args, cacheAddProfiles(), false, platformFlag(cachePlatform)); err != nil {
 			exit.Error(reason.InternalCacheLoad, "Failed to cache and load images", err)
 		}
 		// Add images to config file
@@ -64,6 +65,7 @@ var addCacheCmd = &cobra.Command{
 
 func addCacheCmdFlags() {
 	addCacheCmd.Flags().Bool(all, false, "Add image to cache for all running minikube clusters")
+	addCacheCmd.Flags().StringVar(&cachePlatform, "platform", "", "Platform of the image to cache and load into every node, for example linux/arm64. Defaults to the platform of each node")
 }
 
 func cacheAddProfiles() []*config.Profile {
@@ -0,0 +1,293 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+var certsWarnWithin time.Duration
+
+// certListing is a certificate as listed by 'minikube certs check'
+type certListing struct {
+	Name     string
+	Location string
+	NotAfter time.Time
+	Status   string
+}
+
+// controlPlaneComponents are the static pods which read the Kubernetes certificates when they start
+var controlPlaneComponents = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"}
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage the certificates of a cluster",
+	Long: `Manage the certificates of a cluster: trust additional CAs, check when certificates expire and renew them.
+
+minikube signs the client and apiserver certificates of a cluster for one year, and kubeadm signs the certificates it manages for one year as well.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+var certsAddCmd = &cobra.Command{
+	Use:   "add FILE",
+	Short: "Adds a CA certificate trusted by the nodes and their container runtime",
+	Long: `Adds a PEM encoded CA certificate to ~/.minikube/certs, which minikube installs into the trust store of the nodes of every cluster it starts.
+If the cluster is running, the certificate is installed on its running nodes right away, and their container runtime is restarted to trust it.`,
+	Example: `$ minikube certs add ~/corporate-proxy-ca.pem`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube certs add FILE")
+		}
+
+		dst, err := bootstrapper.AddCACert(args[0])
+		if err != nil {
+			exit.Error(reason.HostCertAdd, "Failed to add certificate", err)
+		}
+		out.Step(style.Permissions, "Added {{.cert}}, clusters started from now on will trust it", out.V{"cert": dst})
+
+		cname := ClusterFlagValue()
+		if !config.ProfileExists(cname) {
+			return
+		}
+		api, cc := mustload.Partial(cname)
+		cr := cc.KubernetesConfig.ContainerRuntime
+		for _, n := range cc.Nodes {
+			machineName := config.MachineName(*cc, n)
+			if st, err := machine.Status(api, machineName); err != nil || st != state.Running.String() {
+				klog.Infof("skipping node %s (state=%s): %v", machineName, st, err)
+				continue
+			}
+			h, err := machine.LoadHost(api, machineName)
+			if err != nil {
+				exit.Error(reason.GuestLoadHost, "Unable to load host", err)
+			}
+			r, err := machine.CommandRunner(h)
+			if err != nil {
+				exit.Error(reason.InternalCommandRunner, "Unable to get command runner", err)
+			}
+
+			out.Step(style.Permissions, "Installing the certificate on {{.node}} and restarting {{.runtime}} ...", out.V{"node": machineName, "runtime": cr})
+			if err := bootstrapper.InstallCACerts(r); err != nil {
+				exit.Error(reason.GuestCert, "Failed to install certificate", err)
+			}
+			if err := restartRuntime(cc, r); err != nil {
+				exit.Error(reason.RuntimeRestart, "Failed to restart container runtime", err)
+			}
+		}
+	},
+}
+
+var certsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Reports when the certificates of a cluster expire",
+	Long: `Reports when the certificates of a cluster expire: the shared CAs and the certificates minikube signs for the cluster on the host,
+and every certificate in the certificates directory of its running control plane nodes, including the ones signed by kubeadm.`,
+	Example: `$ minikube certs check
+$ minikube certs check --warn-within=2160h --output=json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		api, cc := mustload.Partial(cname)
+
+		certs, err := bootstrapper.HostCertExpiry(cname)
+		if err != nil {
+			exit.Error(reason.GuestCertCheck, "Failed to read certificates", err)
+		}
+		for _, n := range config.ControlPlanes(*cc) {
+			machineName := config.MachineName(*cc, n)
+			if st, err := machine.Status(api, machineName); err != nil || st != state.Running.String() {
+				out.WarningT("Skipping node {{.node}}, which is not running", out.V{"node": machineName})
+				continue
+			}
+			h, err := machine.LoadHost(api, machineName)
+			if err != nil {
+				exit.Error(reason.GuestLoadHost, "Unable to load host", err)
+			}
+			r, err := machine.CommandRunner(h)
+			if err != nil {
+				exit.Error(reason.InternalCommandRunner, "Unable to get command runner", err)
+			}
+			nc, err := bootstrapper.GuestCertExpiry(r, machineName)
+			if err != nil {
+				exit.Error(reason.GuestCertCheck, "Failed to read certificates", err)
+			}
+			certs = append(certs, nc...)
+		}
+
+		now := time.Now()
+		listings := []certListing{}
+		expiring := 0
+		for _, c := range certs {
+			status := certStatus(c, now, certsWarnWithin)
+			if status != "OK" {
+				expiring++
+			}
+			listings = append(listings, certListing{Name: c.Name, Location: c.Location, NotAfter: c.NotAfter, Status: status})
+		}
+
+		if outputFormat == "json" {
+			printJSON(listings)
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Certificate", "Location", "Expires", "Status"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, l := range listings {
+			table.Append([]string{l.Name, l.Location, l.NotAfter.Local().Format(time.RFC1123), l.Status})
+		}
+		table.Render()
+
+		if expiring > 0 {
+			out.WarningT("{{.count}} certificates expire within {{.within}} or have expired", out.V{"count": expiring, "within": certsWarnWithin})
+			out.Styled(style.Tip, "To renew them, run: minikube certs renew -p {{.profile}}", out.V{"profile": cname})
+		}
+	},
+}
+
+var certsRenewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "Renews the certificates of a cluster",
+	Long: `Renews the certificates minikube signs for a cluster and the certificates kubeadm manages on its control plane nodes, then restarts the control plane components so that they use them.
+The shared CAs are kept, so kubeconfig files and other clusters keep working.`,
+	Example: `$ minikube certs renew`,
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		cc := co.Config
+
+		renew, err := bsutil.RenewCertsCmd(cc.KubernetesConfig.KubernetesVersion)
+		if err != nil {
+			exit.Message(reason.GuestCertRenew, "Unable to renew certificates: {{.error}}", out.V{"error": err})
+		}
+		if err := bootstrapper.RemoveProfileCerts(cc.Name); err != nil {
+			exit.Error(reason.GuestCertRenew, "Failed to remove certificates", err)
+		}
+
+		for _, n := range config.ControlPlanes(*cc) {
+			machineName := config.MachineName(*cc, n)
+			r, err := nodeRunner(co, n)
+			if err != nil {
+				exit.Error(reason.InternalCommandRunner, "Unable to get command runner", err)
+			}
+
+			out.Step(style.Permissions, "Renewing the certificates of {{.node}} ...", out.V{"node": machineName})
+			// kubeadm renews the apiserver certificate as well, so the one signed by minikube is copied over afterwards
+			if rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", renew)); err != nil {
+				exit.Error(reason.GuestCertRenew, "Failed to renew kubeadm certificates", fmt.Errorf("%v: %s", err, rr.Output()))
+			}
+			if err := bootstrapper.SetupCerts(r, cc.KubernetesConfig, n); err != nil {
+				exit.Error(reason.GuestCertRenew, "Failed to renew certificates", err)
+			}
+
+			out.Step(style.Restarting, "Restarting the control plane components of {{.node}} ...", out.V{"node": machineName})
+			if err := restartControlPlaneComponents(cc, r); err != nil {
+				exit.Error(reason.GuestCertRenew, "Failed to restart control plane", err)
+			}
+		}
+
+		if _, err := kubeconfig.RefreshEmbeddedCerts(cc.Name, kubeconfig.PathFromEnv()); err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "Failed to update kubeconfig", err)
+		}
+
+		healthy := func() error {
+			st, err := kverify.APIServerStatus(co.CP.Runner, co.CP.Hostname, co.CP.Port)
+			if err != nil {
+				return err
+			}
+			if st != state.Running {
+				return fmt.Errorf("apiserver is %s", st)
+			}
+			return nil
+		}
+		if err := retry.Expo(healthy, time.Second, 2*time.Minute); err != nil {
+			exit.Error(reason.GuestCertRenew, "apiserver did not come back after renewing certificates", err)
+		}
+		out.Step(style.Happy, "Successfully renewed the certificates of {{.profile}}!", out.V{"profile": cc.Name})
+	},
+}
+
+// certStatus summarizes when a certificate expires relative to now
+func certStatus(c bootstrapper.CertExpiry, now time.Time, within time.Duration) string {
+	if !c.NotAfter.After(now) {
+		return "EXPIRED"
+	}
+	if c.ExpiresWithin(now, within) {
+		return fmt.Sprintf("expires in %d days", int(c.NotAfter.Sub(now).Hours()/24))
+	}
+	return "OK"
+}
+
+// restartRuntime restarts the container runtime of a node, so that it trusts newly installed CAs
+func restartRuntime(cc *config.ClusterConfig, r command.Runner) error {
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: r})
+	if err != nil {
+		return err
+	}
+	return cr.Restart()
+}
+
+// restartControlPlaneComponents stops the control plane containers of a node, which the kubelet restarts with the current certificates
+func restartControlPlaneComponents(cc *config.ClusterConfig, r command.Runner) error {
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: r})
+	if err != nil {
+		return err
+	}
+	for _, name := range controlPlaneComponents {
+		ids, err := cr.ListContainers(cruntime.ListContainersOptions{State: cruntime.Running, Name: name, Namespaces: []string{"kube-system"}})
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if err := cr.StopContainers(ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	certsCheckCmd.Flags().DurationVar(&certsWarnWithin, "warn-within", 30*24*time.Hour, "Report certificates which expire within this duration")
+	certsCmd.AddCommand(certsAddCmd)
+	certsCmd.AddCommand(certsCheckCmd)
+	certsCmd.AddCommand(certsRenewCmd)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+)
+
+func TestCertStatus(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	tests := []struct {
+		notAfter time.Time
+		want     string
+	}{
+		{notAfter: now.Add(365 * day), want: "OK"},
+		{notAfter: now.Add(30*day + time.Hour), want: "OK"},
+		{notAfter: now.Add(12*day + time.Hour), want: "expires in 12 days"},
+		{notAfter: now.Add(time.Hour), want: "expires in 0 days"},
+		{notAfter: now, want: "EXPIRED"},
+		{notAfter: now.Add(-day), want: "EXPIRED"},
+	}
+	for _, tc := range tests {
+		got := certStatus(bootstrapper.CertExpiry{Name: "apiserver.crt", NotAfter: tc.notAfter}, now, 30*day)
+		if got != tc.want {
+			t.Errorf("certStatus(%s) = %q, want %q", tc.notAfter, got, tc.want)
+		}
+	}
+}
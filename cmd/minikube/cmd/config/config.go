@@ -21,8 +21,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/audit"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/hooks"
 	"k8s.io/minikube/pkg/minikube/localpath"
 )
 
@@ -90,6 +92,12 @@ var settings = []Setting{
 		set:         SetString,
 		validations: []setFn{IsValidCIDR},
 	},
+	{
+		name:        "loadbalancer-ip-range",
+		set:         SetString,
+		validations: []setFn{IsValidCIDR},
+		callbacks:   []setFn{RequiresRestartMsg},
+	},
 	{
 		name:        "memory",
 		set:         SetString,
@@ -159,6 +167,55 @@ var settings = []Setting{
 		name: "native-ssh",
 		set:  SetBool,
 	},
+	{
+		name: config.Rootless,
+		set:  SetBool,
+	},
+	{
+		name:        hooks.ConfigKey(hooks.PreStart),
+		set:         SetString,
+		validations: []setFn{IsValidHook},
+	},
+	{
+		name:        hooks.ConfigKey(hooks.PostStart),
+		set:         SetString,
+		validations: []setFn{IsValidHook},
+	},
+	{
+		name:        hooks.ConfigKey(hooks.PreStop),
+		set:         SetString,
+		validations: []setFn{IsValidHook},
+	},
+	{
+		name:        hooks.ConfigKey(hooks.PostDelete),
+		set:         SetString,
+		validations: []setFn{IsValidHook},
+	},
+	{
+		name:        hooks.ConfigKey(hooks.NodeAdded),
+		set:         SetString,
+		validations: []setFn{IsValidHook},
+	},
+	{
+		name:        hooks.ConfigKey(hooks.AddonEnabled),
+		set:         SetString,
+		validations: []setFn{IsValidHook},
+	},
+	{
+		name:        audit.MaxSizeKey,
+		set:         SetString,
+		validations: []setFn{IsValidDiskSize},
+	},
+	{
+		name:        audit.MaxBackupsKey,
+		set:         SetInt,
+		validations: []setFn{IsPositive},
+	},
+	{
+		name:        audit.ForwardKey,
+		set:         SetString,
+		validations: []setFn{IsValidAuditForward},
+	},
 }
 
 // ConfigCmd represents the config command
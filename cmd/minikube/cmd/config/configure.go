@@ -17,230 +17,191 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net"
 	"regexp"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
-	"k8s.io/minikube/pkg/minikube/service"
-	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	settingsFile  string
+	settingValues []string
 )
 
 var addonsConfigureCmd = &cobra.Command{
 	Use:   "configure ADDON_NAME",
 	Short: "Configures the addon w/ADDON_NAME within minikube (example: minikube addons configure registry-creds). For a list of available addons use: minikube addons list",
-	Long:  "Configures the addon w/ADDON_NAME within minikube (example: minikube addons configure registry-creds). For a list of available addons use: minikube addons list",
+	Long: `Configures the addon w/ADDON_NAME within minikube (example: minikube addons configure registry-creds). For a list of available addons use: minikube addons list
+
+The settings are asked for, unless they are given with --from-file or --set. They are stored in the profile, and applied again every time the addon is enabled.`,
+	Example: `minikube addons configure metallb --set loadBalancerStartIP=192.168.49.100 --set loadBalancerEndIP=192.168.49.120
+minikube addons configure registry-creds --from-file=registry-creds.yaml`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
 			exit.Message(reason.Usage, "usage: minikube addons configure ADDON_NAME")
 		}
 
 		addon := args[0]
-		// allows for additional prompting of information when enabling addons
-		switch addon {
-		case "registry-creds":
-			posResponses := []string{"yes", "y"}
-			negResponses := []string{"no", "n"}
-
-			// Default values
-			awsAccessID := "changeme"
-			awsAccessKey := "changeme"
-			awsSessionToken := ""
-			awsRegion := "changeme"
-			awsAccount := "changeme"
-			awsRole := "changeme"
-			gcrApplicationDefaultCredentials := "changeme"
-			dockerServer := "changeme"
-			dockerUser := "changeme"
-			dockerPass := "changeme"
-			gcrURL := "https://gcr.io"
-			acrURL := "changeme"
-			acrClientID := "changeme"
-			acrPassword := "changeme"
-
-			enableAWSECR := AskForYesNoConfirmation("\nDo you want to enable AWS Elastic Container Registry?", posResponses, negResponses)
-			if enableAWSECR {
-				awsAccessID = AskForStaticValue("-- Enter AWS Access Key ID: ")
-				awsAccessKey = AskForStaticValue("-- Enter AWS Secret Access Key: ")
-				awsSessionToken = AskForStaticValueOptional("-- (Optional) Enter AWS Session Token: ")
-				awsRegion = AskForStaticValue("-- Enter AWS Region: ")
-				awsAccount = AskForStaticValue("-- Enter 12 digit AWS Account ID (Comma separated list): ")
-				awsRole = AskForStaticValueOptional("-- (Optional) Enter ARN of AWS role to assume: ")
-			}
-
-			enableGCR := AskForYesNoConfirmation("\nDo you want to enable Google Container Registry?", posResponses, negResponses)
-			if enableGCR {
-				gcrPath := AskForStaticValue("-- Enter path to credentials (e.g. /home/user/.config/gcloud/application_default_credentials.json):")
-				gcrchangeURL := AskForYesNoConfirmation("-- Do you want to change the GCR URL (Default https://gcr.io)?", posResponses, negResponses)
-
-				if gcrchangeURL {
-					gcrURL = AskForStaticValue("-- Enter GCR URL (e.g. https://asia.gcr.io):")
-				}
+		if len(addons.Settings(addon)) == 0 {
+			out.FailureT("{{.name}} has no available configuration options", out.V{"name": addon})
+			return
+		}
 
-				// Read file from disk
-				dat, err := ioutil.ReadFile(gcrPath)
+		profile := ClusterFlagValue()
+		_, cfg := mustload.Partial(profile)
 
-				if err != nil {
-					out.FailureT("Error reading {{.path}}: {{.error}}", out.V{"path": gcrPath, "error": err})
-				} else {
-					gcrApplicationDefaultCredentials = string(dat)
-				}
-			}
+		values, err := settingsFromFlags()
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid settings: {{.error}}", out.V{"error": err})
+		}
+		if values == nil {
+			values = askForSettings(addon, cfg)
+		}
 
-			enableDR := AskForYesNoConfirmation("\nDo you want to enable Docker Registry?", posResponses, negResponses)
-			if enableDR {
-				dockerServer = AskForStaticValue("-- Enter docker registry server url: ")
-				dockerUser = AskForStaticValue("-- Enter docker registry username: ")
-				dockerPass = AskForPasswordValue("-- Enter docker registry password: ")
-			}
+		if err := addons.Configure(cfg, addon, values); err != nil {
+			exit.Error(reason.AddonConfigure, "Failed to configure addon", err)
+		}
+		out.SuccessT("{{.name}} was successfully configured", out.V{"name": addon})
+	},
+}
 
-			enableACR := AskForYesNoConfirmation("\nDo you want to enable Azure Container Registry?", posResponses, negResponses)
-			if enableACR {
-				acrURL = AskForStaticValue("-- Enter Azure Container Registry (ACR) URL: ")
-				acrClientID = AskForStaticValue("-- Enter client ID (service principal ID) to access ACR: ")
-				acrPassword = AskForPasswordValue("-- Enter service principal password to access Azure Container Registry: ")
-			}
+// settingsFromFlags returns the settings given with --from-file and --set, the latter taking
+// precedence, or nil if there are none so that they are asked for
+func settingsFromFlags() (map[string]string, error) {
+	if settingsFile == "" && len(settingValues) == 0 {
+		return nil, nil
+	}
+
+	values := map[string]string{}
+	if settingsFile != "" {
+		data, err := ioutil.ReadFile(settingsFile)
+		if err != nil {
+			return nil, err
+		}
+		m := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("parsing %s: %v", settingsFile, err)
+		}
+		values = addons.SettingsFromMap(m)
+	}
+
+	set, err := addons.ParseSettings(settingValues)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range set {
+		values[k] = v
+	}
+	return values, nil
+}
 
-			cname := ClusterFlagValue()
-			namespace := "kube-system"
-
-			// Create ECR Secret
-			err := service.CreateSecret(
-				cname,
-				namespace,
-				"registry-creds-ecr",
-				map[string]string{
-					"AWS_ACCESS_KEY_ID":     awsAccessID,
-					"AWS_SECRET_ACCESS_KEY": awsAccessKey,
-					"AWS_SESSION_TOKEN":     awsSessionToken,
-					"aws-account":           awsAccount,
-					"aws-region":            awsRegion,
-					"aws-assume-role":       awsRole,
-				},
-				map[string]string{
-					"app":                           "registry-creds",
-					"cloud":                         "ecr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
-			if err != nil {
-				out.FailureT("ERROR creating `registry-creds-ecr` secret: {{.error}}", out.V{"error": err})
-			}
+// askForSettings asks for the settings of an addon interactively
+func askForSettings(addon string, cfg *config.ClusterConfig) map[string]string {
+	values := map[string]string{}
+
+	switch addon {
+	case "registry-creds":
+		posResponses := []string{"yes", "y"}
+		negResponses := []string{"no", "n"}
+
+		enableAWSECR := AskForYesNoConfirmation("\nDo you want to enable AWS Elastic Container Registry?", posResponses, negResponses)
+		if enableAWSECR {
+			values["awsAccessID"] = AskForStaticValue("-- Enter AWS Access Key ID: ")
+			values["awsAccessKey"] = AskForStaticValue("-- Enter AWS Secret Access Key: ")
+			values["awsSessionToken"] = AskForStaticValueOptional("-- (Optional) Enter AWS Session Token: ")
+			values["awsRegion"] = AskForStaticValue("-- Enter AWS Region: ")
+			values["awsAccount"] = AskForStaticValue("-- Enter 12 digit AWS Account ID (Comma separated list): ")
+			values["awsRole"] = AskForStaticValueOptional("-- (Optional) Enter ARN of AWS role to assume: ")
+		}
 
-			// Create GCR Secret
-			err = service.CreateSecret(
-				cname,
-				namespace,
-				"registry-creds-gcr",
-				map[string]string{
-					"application_default_credentials.json": gcrApplicationDefaultCredentials,
-					"gcrurl":                               gcrURL,
-				},
-				map[string]string{
-					"app":                           "registry-creds",
-					"cloud":                         "gcr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
+		enableGCR := AskForYesNoConfirmation("\nDo you want to enable Google Container Registry?", posResponses, negResponses)
+		if enableGCR {
+			gcrPath := AskForStaticValue("-- Enter path to credentials (e.g. /home/user/.config/gcloud/application_default_credentials.json):")
+			gcrchangeURL := AskForYesNoConfirmation("-- Do you want to change the GCR URL (Default https://gcr.io)?", posResponses, negResponses)
 
-			if err != nil {
-				out.FailureT("ERROR creating `registry-creds-gcr` secret: {{.error}}", out.V{"error": err})
+			if gcrchangeURL {
+				values["gcrURL"] = AskForStaticValue("-- Enter GCR URL (e.g. https://asia.gcr.io):")
 			}
 
-			// Create Docker Secret
-			err = service.CreateSecret(
-				cname,
-				namespace,
-				"registry-creds-dpr",
-				map[string]string{
-					"DOCKER_PRIVATE_REGISTRY_SERVER":   dockerServer,
-					"DOCKER_PRIVATE_REGISTRY_USER":     dockerUser,
-					"DOCKER_PRIVATE_REGISTRY_PASSWORD": dockerPass,
-				},
-				map[string]string{
-					"app":                           "registry-creds",
-					"cloud":                         "dpr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
+			// Read file from disk
+			dat, err := ioutil.ReadFile(gcrPath)
 
 			if err != nil {
-				out.WarningT("ERROR creating `registry-creds-dpr` secret")
+				out.FailureT("Error reading {{.path}}: {{.error}}", out.V{"path": gcrPath, "error": err})
+			} else {
+				values["gcrApplicationDefaultCredentials"] = string(dat)
 			}
+		}
 
-			// Create Azure Container Registry Secret
-			err = service.CreateSecret(
-				cname,
-				namespace,
-				"registry-creds-acr",
-				map[string]string{
-					"ACR_URL":       acrURL,
-					"ACR_CLIENT_ID": acrClientID,
-					"ACR_PASSWORD":  acrPassword,
-				},
-				map[string]string{
-					"app":                           "registry-creds",
-					"cloud":                         "acr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
-
-			if err != nil {
-				out.WarningT("ERROR creating `registry-creds-acr` secret")
-			}
+		enableDR := AskForYesNoConfirmation("\nDo you want to enable Docker Registry?", posResponses, negResponses)
+		if enableDR {
+			values["dockerServer"] = AskForStaticValue("-- Enter docker registry server url: ")
+			values["dockerUser"] = AskForStaticValue("-- Enter docker registry username: ")
+			values["dockerPass"] = AskForPasswordValue("-- Enter docker registry password: ")
+		}
 
-		case "metallb":
-			profile := ClusterFlagValue()
-			_, cfg := mustload.Partial(profile)
+		enableACR := AskForYesNoConfirmation("\nDo you want to enable Azure Container Registry?", posResponses, negResponses)
+		if enableACR {
+			values["acrURL"] = AskForStaticValue("-- Enter Azure Container Registry (ACR) URL: ")
+			values["acrClientID"] = AskForStaticValue("-- Enter client ID (service principal ID) to access ACR: ")
+			values["acrPassword"] = AskForPasswordValue("-- Enter service principal password to access Azure Container Registry: ")
+		}
 
-			validator := func(s string) bool {
-				return net.ParseIP(s) != nil
-			}
+	case "metallb":
+		validator := func(s string) bool {
+			return net.ParseIP(s) != nil
+		}
 
-			if cfg.KubernetesConfig.LoadBalancerStartIP == "" {
-				cfg.KubernetesConfig.LoadBalancerStartIP = AskForStaticValidatedValue("-- Enter Load Balancer Start IP: ", validator)
-			}
+		if cfg.KubernetesConfig.LoadBalancerStartIP == "" {
+			values["loadBalancerStartIP"] = AskForStaticValidatedValue("-- Enter Load Balancer Start IP: ", validator)
+		}
 
-			if cfg.KubernetesConfig.LoadBalancerEndIP == "" {
-				cfg.KubernetesConfig.LoadBalancerEndIP = AskForStaticValidatedValue("-- Enter Load Balancer End IP: ", validator)
-			}
+		if cfg.KubernetesConfig.LoadBalancerEndIP == "" {
+			values["loadBalancerEndIP"] = AskForStaticValidatedValue("-- Enter Load Balancer End IP: ", validator)
+		}
 
-			if err := config.SaveProfile(profile, cfg); err != nil {
-				out.ErrT(style.Fatal, "Failed to save config {{.profile}}", out.V{"profile": profile})
-			}
+	case "ingress":
+		validator := func(s string) bool {
+			format := regexp.MustCompile("^.+/.+$")
+			return format.MatchString(s)
+		}
 
-			// Re-enable metallb addon in order to generate template manifest files with Load Balancer Start/End IP
-			if err := addons.EnableOrDisableAddon(cfg, "metallb", "true"); err != nil {
-				out.ErrT(style.Fatal, "Failed to configure metallb IP {{.profile}}", out.V{"profile": profile})
-			}
-		case "ingress":
-			profile := ClusterFlagValue()
-			_, cfg := mustload.Partial(profile)
+		if cfg.KubernetesConfig.CustomIngressCert == "" {
+			values["customCert"] = AskForStaticValidatedValue("-- Enter custom cert(format is \"namespace/secret\"): ", validator)
+		}
 
-			validator := func(s string) bool {
-				format := regexp.MustCompile("^.+/.+$")
-				return format.MatchString(s)
+	default:
+		// an addon installed with 'minikube addons install'
+		a := assets.Addons[addon]
+		for _, p := range a.Bundle.Parameters {
+			current, ok := cfg.AddonValues[addon][p.Name]
+			if !ok {
+				current = p.Default
 			}
-
-			if cfg.KubernetesConfig.CustomIngressCert == "" {
-				cfg.KubernetesConfig.CustomIngressCert = AskForStaticValidatedValue("-- Enter custom cert(format is \"namespace/secret\"): ", validator)
+			prompt := fmt.Sprintf("-- Enter %s (%s) [%s]: ", p.Name, p.Description, current)
+			if p.Description == "" {
+				prompt = fmt.Sprintf("-- Enter %s [%s]: ", p.Name, current)
 			}
-
-			if err := config.SaveProfile(profile, cfg); err != nil {
-				out.ErrT(style.Fatal, "Failed to save config {{.profile}}", out.V{"profile": profile})
+			if v := AskForStaticValueOptional(prompt); v != "" {
+				values[p.Name] = v
 			}
-
-		default:
-			out.FailureT("{{.name}} has no available configuration options", out.V{"name": addon})
-			return
 		}
-
-		out.SuccessT("{{.name}} was successfully configured", out.V{"name": addon})
-	},
+	}
+	return values
 }
 
 func init() {
+	addonsConfigureCmd.Flags().StringVar(&settingsFile, "from-file", "", "YAML file with the settings of the addon, instead of asking for them")
+	addonsConfigureCmd.Flags().StringArrayVar(&settingValues, "set", []string{}, "A setting of the addon as key=value, instead of asking for it. Can be repeated, and takes precedence over --from-file")
 	AddonsCmd.AddCommand(addonsConfigureCmd)
 }
@@ -25,6 +25,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/hooks"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
@@ -65,6 +66,7 @@ var addonsEnableCmd = &cobra.Command{
 		}
 
 		out.Step(style.AddonEnable, "The '{{.addonName}}' addon is enabled", out.V{"addonName": addon})
+		hooks.Notify(hooks.Payload{Event: hooks.AddonEnabled, Profile: ClusterFlagValue(), Addon: addon})
 	},
 }
 
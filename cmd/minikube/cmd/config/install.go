@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	addonKey      string
+	allowUnsigned bool
+)
+
+var addonsInstallCmd = &cobra.Command{
+	Use:   "install oci://REGISTRY/REPOSITORY:TAG",
+	Short: "Installs a third-party addon from an OCI registry",
+	Long: `Installs a third-party addon bundle from an OCI registry, after which it can be enabled, disabled and configured like the built-in addons.
+
+The bundle is an image holding an addon.yaml describing the addon and a manifests directory with its manifests, signed with 'cosign sign --key'.`,
+	Example: `minikube addons install oci://ghcr.io/acme/my-addon:1.2 --key cosign.pub
+minikube addons enable my-addon`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "usage: minikube addons install oci://REGISTRY/REPOSITORY:TAG")
+		}
+		if addonKey == "" && !allowUnsigned {
+			exit.Message(reason.Usage, "Specify the public key to verify the addon with --key, or pass --allow-unsigned to install it without verification")
+		}
+
+		b, err := addons.Install(args[0], addonKey, allowUnsigned)
+		if err != nil {
+			exit.Error(reason.AddonInstall, "Failed to install addon", err)
+		}
+		if !b.Signed {
+			out.WarningT("The signature of the '{{.name}}' addon was not verified", out.V{"name": b.Name})
+		}
+		out.Step(style.Check, "Installed the '{{.name}}' addon {{.version}}", out.V{"name": b.Name, "version": b.Version})
+		out.Styled(style.Tip, "To enable it, run: minikube addons enable {{.name}}", out.V{"name": b.Name})
+	},
+}
+
+var addonsUninstallCmd = &cobra.Command{
+	Use:   "uninstall ADDON_NAME",
+	Short: "Uninstalls a third-party addon",
+	Long:  "Uninstalls a third-party addon installed with 'minikube addons install'. It has to be disabled first in every profile it is enabled in.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "usage: minikube addons uninstall ADDON_NAME")
+		}
+		name := args[0]
+
+		validProfiles, _, err := config.ListProfiles()
+		if err != nil {
+			exit.Error(reason.InternalListConfig, "Error getting profiles", err)
+		}
+		for _, p := range validProfiles {
+			if p.Config != nil && p.Config.Addons[name] {
+				exit.Message(reason.AddonUninstall, "The '{{.name}}' addon is enabled in profile {{.profile}}, disable it first with: minikube addons disable {{.name}} -p {{.profile}}", out.V{"name": name, "profile": p.Name})
+			}
+		}
+
+		if err := addons.Uninstall(name); err != nil {
+			exit.Error(reason.AddonUninstall, "Failed to uninstall addon", err)
+		}
+		out.Step(style.Deleted, "Uninstalled the '{{.name}}' addon", out.V{"name": name})
+	},
+}
+
+func init() {
+	addonsInstallCmd.Flags().StringVar(&addonKey, "key", "", "Path to the PEM encoded public key the addon bundle is signed with, as generated by 'cosign generate-key-pair'")
+	addonsInstallCmd.Flags().BoolVar(&allowUnsigned, "allow-unsigned", false, "Install the addon bundle without verifying its signature")
+	AddonsCmd.AddCommand(addonsInstallCmd)
+	AddonsCmd.AddCommand(addonsUninstallCmd)
+}
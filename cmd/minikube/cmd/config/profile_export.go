@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/archive"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// cacheImageConfigKey is the config field name used to store which images we have previously cached
+const cacheImageConfigKey = "cache"
+
+var (
+	exportFile        string
+	exportIncludeDisk bool
+)
+
+var profileExportCmd = &cobra.Command{
+	Use:     "export [MINIKUBE_PROFILE_NAME]",
+	Short:   "Packages a profile into a single file",
+	Long:    "Packages the configuration and certificates of a profile, along with the images added with 'minikube cache add', into a zstd-compressed tarball which can be imported on another host with 'minikube profile import'. The machines of the cluster are not included: it is provisioned again on the first start after the import.",
+	Example: "minikube profile export minikube -f profile.tar.zst",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube profile export [MINIKUBE_PROFILE_NAME] -f [FILE]")
+		}
+		if exportFile == "" {
+			exit.Message(reason.Usage, "Please specify the file to write to with --file")
+		}
+
+		api, cc := mustload.Partial(args[0])
+		if exportIncludeDisk {
+			if !snapshot.Supported(cc.Driver) {
+				exit.Message(reason.DrvUnsupportedSnapshot, "--include-disk is not supported by the {{.driver}} driver, only by docker, podman and kvm2", out.V{"driver": cc.Driver})
+			}
+			// the disk can only be captured consistently from a running cluster
+			cc = mustload.Running(args[0]).Config
+		}
+
+		images, err := ListConfigMap(cacheImageConfigKey)
+		if err != nil {
+			exit.Error(reason.InternalCacheList, "Failed to get image map", err)
+		}
+
+		out.Step(style.Copying, "Exporting profile {{.profile}} to {{.file}} ...", out.V{"profile": cc.Name, "file": exportFile})
+		m, err := archive.Export(api, *cc, exportFile, archive.ExportOptions{Images: images, IncludeDisk: exportIncludeDisk})
+		if err != nil {
+			exit.Error(reason.HostProfileExport, "exporting profile", err)
+		}
+		out.Step(style.Success, "Exported profile {{.profile}} with {{.count}} cached image(s) to {{.file}}", out.V{"profile": m.Profile, "count": len(m.Images), "file": exportFile})
+	},
+}
+
+func init() {
+	profileExportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "The file to write the profile archive to, conventionally ending in .tar.zst")
+	profileExportCmd.Flags().BoolVar(&exportIncludeDisk, "include-disk", false, "Include a snapshot of the node disks, which can be applied after import with 'minikube snapshot restore'. Only supported by the docker, podman and kvm2 drivers.")
+	ProfileCmd.AddCommand(profileExportCmd)
+}
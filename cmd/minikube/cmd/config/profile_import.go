@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/archive"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var profileImportCmd = &cobra.Command{
+	Use:     "import [FILE]",
+	Short:   "Imports a profile packaged with 'minikube profile export'",
+	Long:    "Imports a profile packaged with 'minikube profile export'. Machines are not exported, so the cluster is provisioned again from the imported configuration on the next 'minikube start'. The import is refused if a machine of the same name is left on this host.",
+	Example: "minikube profile import profile.tar.zst",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube profile import [FILE]")
+		}
+
+		res, err := archive.Import(args[0])
+		if err != nil {
+			exit.Error(reason.HostProfileImport, "importing profile", err)
+		}
+		if len(res.Images) > 0 {
+			if err := AddToConfigMap(cacheImageConfigKey, res.Images); err != nil {
+				exit.Error(reason.InternalAddConfig, "Failed to update config", err)
+			}
+		}
+
+		out.Step(style.Success, "Imported profile {{.profile}} exported by minikube {{.version}}", out.V{"profile": res.Profile, "version": res.MinikubeVersion})
+		if len(res.ConflictingCerts) > 0 {
+			out.WarningT("Kept the existing {{.certs}}: the certificates of {{.profile}} will be signed again when it starts", out.V{"certs": strings.Join(res.ConflictingCerts, ", "), "profile": res.Profile})
+		}
+		out.Step(style.Tip, "To start the cluster, run: {{.cmd}}", out.V{"cmd": mustload.ExampleCmd(res.Profile, "start")})
+		if res.Snapshot != "" {
+			out.Step(style.Tip, "To then restore the exported disks, run: {{.cmd}}", out.V{"cmd": mustload.ExampleCmd(res.Profile, "snapshot restore "+res.Snapshot)})
+		}
+	},
+}
+
+func init() {
+	ProfileCmd.AddCommand(profileImportCmd)
+}
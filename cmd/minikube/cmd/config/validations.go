@@ -25,8 +25,10 @@ import (
 	"strings"
 
 	units "github.com/docker/go-units"
+	"k8s.io/minikube/pkg/minikube/audit"
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/hooks"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
@@ -135,6 +137,29 @@ func IsValidPath(name string, path string) error {
 	return nil
 }
 
+// IsValidHook checks if a hook is a webhook URL or an executable file
+func IsValidHook(name string, hook string) error {
+	if hooks.IsWebhook(hook) {
+		return IsValidURL(name, hook)
+	}
+	fi, err := os.Stat(hook)
+	if err != nil {
+		return fmt.Errorf("%s hook is not valid: %v", name, err)
+	}
+	if fi.IsDir() {
+		return fmt.Errorf("%s hook %s is a directory, not an executable", name, hook)
+	}
+	return nil
+}
+
+// IsValidAuditForward checks if a string is somewhere audit events can be forwarded to
+func IsValidAuditForward(name string, target string) error {
+	if err := audit.ValidateForward(target); err != nil {
+		return fmt.Errorf("%s is not valid: %v", name, err)
+	}
+	return nil
+}
+
 // IsValidRuntime checks if a string is a valid runtime
 func IsValidRuntime(name string, runtime string) error {
 	_, err := cruntime.New(cruntime.Config{Type: runtime})
@@ -146,3 +146,50 @@ func TestIsURLExists(t *testing.T) {
 
 	runValidations(t, tests, "url", IsURLExists)
 }
+
+func TestIsValidHook(t *testing.T) {
+	self, err := os.Executable()
+	if err != nil {
+		t.Error(err)
+	}
+
+	tests := []validationTest{
+		{
+			value:     self,
+			shouldErr: false,
+		},
+		{
+			value:     "https://chat.example.com/webhook",
+			shouldErr: false,
+		},
+		{
+			value:     self + "-missing",
+			shouldErr: true,
+		},
+		{
+			value:     os.TempDir(),
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "hooks.post-start", IsValidHook)
+}
+
+func TestIsValidAuditForward(t *testing.T) {
+	tests := []validationTest{
+		{
+			value:     "syslog",
+			shouldErr: false,
+		},
+		{
+			value:     "https://audit.example.com/events",
+			shouldErr: false,
+		},
+		{
+			value:     "udp://logs.example.com",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "audit.forward", IsValidAuditForward)
+}
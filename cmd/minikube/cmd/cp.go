@@ -17,117 +17,345 @@ limitations under the License.
 package cmd
 
 import (
-	"github.com/pkg/errors"
-	"github.com/spf13/cobra"
-
 	"fmt"
+	"io"
 	"os"
-	pt "path"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/cheggaaa/pb/v3"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
+
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
-	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
 )
 
-// placeholders for flag values
-var (
-	srcPath string
-	dstPath string
-	dstNode string
-)
+// largeCopy is the size from which copies show a progress bar
+const largeCopy = 10 * 1024 * 1024
+
+// nodePath is a path on a node, or on the host when node is nil
+type nodePath struct {
+	node *config.Node
+	path string
+}
+
+// cpFile is a regular file to copy
+type cpFile struct {
+	// src is the path of the file at the source
+	src string
+	// base is the name of the file or directory matching the source argument the file was found in
+	base string
+	// rel is the slash separated path of the file in that directory, empty when the file matched itself
+	rel  string
+	size int64
+	mode os.FileMode
+}
 
 // cpCmd represents the cp command, similar to docker cp
 var cpCmd = &cobra.Command{
-	Use:   "cp <source file path> <target node name>:<target file absolute path>",
-	Short: "Copy the specified file into minikube",
-	Long: "Copy the specified file into minikube, it will be saved at path <target file absolute path> in your minikube.\n" +
-		"Example Command : \"minikube cp a.txt /home/docker/b.txt\"\n" +
-		"                  \"minikube cp a.txt minikube-m02:/home/docker/b.txt\"\n",
+	Use:   "cp [NODE:]SOURCE [NODE:]TARGET",
+	Short: "Copy files and directories between the host and the nodes",
+	Long: `Copy files and directories between the host and the nodes, or between two nodes.
+NODE is the name of a node, as listed by 'minikube node list'. Paths on nodes must be absolute.
+Without any NODE, the source is on the host and the target on the primary control plane.
+
+SOURCE may be a glob pattern. Directories are copied recursively, and the permissions of the files are preserved.
+A single source is copied to TARGET, while several sources, or a TARGET ending with a slash, are copied into the TARGET directory.
+The files are copied by minikube itself, as root on the nodes, with any driver. Use 'minikube scp' to copy as the SSH user of the nodes with the command line 'scp' command instead, which also preserves modification times.`,
+	Example: `minikube cp a.txt /home/docker/b.txt
+minikube cp a.txt minikube-m02:/home/docker/b.txt
+minikube cp ./manifests minikube:/home/docker/manifests
+minikube cp 'minikube-m02:/var/log/*.log' ./logs/
+minikube cp minikube:/etc/kubernetes/manifests minikube-m02:/tmp/manifests`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 2 {
-			exit.Message(reason.Usage, `Please specify the path to copy: 
-	minikube cp <source file path> <target file absolute path> (example: "minikube cp a/b.txt /copied.txt")`)
+			exit.Message(reason.Usage, `Please specify the path to copy:
+	minikube cp [NODE:]SOURCE [NODE:]TARGET (example: "minikube cp a/b.txt /copied.txt")`)
 		}
 
-		srcPath = args[0]
-		dstPath = args[1]
-
-		// if destination path is not a absolute path, trying to parse with <node>:<abs path> format
-		if !strings.HasPrefix(dstPath, "/") {
-			if sp := strings.SplitN(dstPath, ":", 2); len(sp) == 2 {
-				dstNode = sp[0]
-				dstPath = sp[1]
-			}
+		co := mustload.Running(ClusterFlagValue())
+		src := parseNodePath(*co.Config, args[0])
+		dst := parseNodePath(*co.Config, args[1])
+		if src.node == nil && dst.node == nil {
+			dst.node = co.CP.Node
 		}
+		validateArgs(src, dst)
 
-		validateArgs(srcPath, dstPath)
+		srcRunner := cpRunner(co, src)
+		dstRunner := cpRunner(co, dst)
 
-		fa, err := assets.NewFileAsset(srcPath, pt.Dir(dstPath), pt.Base(dstPath), "0644")
+		files, err := listCopy(srcRunner, src.path)
 		if err != nil {
-			out.ErrLn("%v", errors.Wrap(err, "getting file asset"))
-			os.Exit(1)
+			exit.Error(reason.InternalCommandRunner, fmt.Sprintf("Failed to list %s", args[0]), err)
+		}
+		if len(files) == 0 {
+			exit.Message(reason.HostPathMissing, "Cannot find any file matching {{.path}} for copy", out.V{"path": args[0]})
 		}
-		defer func() {
-			if err := fa.Close(); err != nil {
-				klog.Warningf("error closing the file %s: %v", fa.GetSourcePath(), err)
-			}
-		}()
 
-		co := mustload.Running(ClusterFlagValue())
-		var runner command.Runner
-		if dstNode == "" {
-			runner = co.CP.Runner
-		} else {
-			n, _, err := node.Retrieve(*co.Config, dstNode)
-			if err != nil {
-				exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": dstNode})
-			}
+		intoDir := strings.HasSuffix(dst.path, "/") || countSources(files) > 1
+		if err := copyFiles(srcRunner, dstRunner, files, dst.path, intoDir); err != nil {
+			exit.Error(reason.InternalCommandRunner, fmt.Sprintf("Fail to copy %s", args[0]), err)
+		}
+	},
+}
+
+// parseNodePath returns the path of a [NODE:]PATH argument, which is on the host unless NODE is a node of the cluster
+func parseNodePath(cc config.ClusterConfig, arg string) nodePath {
+	if sp := strings.SplitN(arg, ":", 2); len(sp) == 2 {
+		if n, _, err := node.Retrieve(cc, sp[0]); err == nil {
+			return nodePath{node: n, path: sp[1]}
+		}
+	}
+	return nodePath{path: arg}
+}
+
+func validateArgs(src nodePath, dst nodePath) {
+	if src.path == "" {
+		exit.Message(reason.Usage, "Source {{.path}} can not be empty", out.V{"path": src.path})
+	}
+
+	if dst.path == "" {
+		exit.Message(reason.Usage, "Target {{.path}} can not be empty", out.V{"path": dst.path})
+	}
+
+	for _, p := range []nodePath{src, dst} {
+		if p.node != nil && !strings.HasPrefix(p.path, "/") {
+			exit.Message(reason.Usage, `Paths on nodes must be absolute. Relative Path is not allowed (example: "/home/docker/copied.txt")`)
+		}
+	}
+}
+
+// cpRunner returns the runner of the node a path is on, or nil for the host
+func cpRunner(co mustload.ClusterController, p nodePath) command.Runner {
+	if p.node == nil {
+		return nil
+	}
+	r, err := nodeRunner(co, *p.node)
+	if err != nil {
+		exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+	}
+	return r
+}
+
+// countSources returns how many files or directories matched the source argument
+func countSources(files []cpFile) int {
+	bases := map[string]bool{}
+	for _, f := range files {
+		bases[f.base] = true
+	}
+	return len(bases)
+}
 
-			h, err := machine.GetHost(co.API, *co.Config, *n)
+// listCopy lists the regular files matching a glob pattern, and in the directories matching it, on the host if r is nil
+func listCopy(r command.Runner, pattern string) ([]cpFile, error) {
+	if r == nil {
+		return listHost(pattern)
+	}
+	return listNode(r, pattern)
+}
+
+func listHost(pattern string) ([]cpFile, error) {
+	roots, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	files := []cpFile{}
+	for _, root := range roots {
+		err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
 			if err != nil {
-				exit.Error(reason.GuestLoadHost, "Error getting host", err)
+				return err
 			}
-
-			runner, err = machine.CommandRunner(h)
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, p)
 			if err != nil {
-				exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+				return err
 			}
+			if rel == "." {
+				rel = ""
+			}
+			files = append(files, cpFile{src: p, base: filepath.Base(root), rel: filepath.ToSlash(rel), size: info.Size(), mode: info.Mode().Perm()})
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "walk %s", root)
 		}
+	}
+	return files, nil
+}
 
-		if err = runner.Copy(fa); err != nil {
-			exit.Error(reason.InternalCommandRunner, fmt.Sprintf("Fail to copy file %s", fa.GetSourcePath()), err)
+func listNode(r command.Runner, pattern string) ([]cpFile, error) {
+	// the shell of the node expands the pattern
+	script := fmt.Sprintf(`for p in %s; do [ -e "$p" ] && echo "$p"; done; true`, globEscape(pattern))
+	rr, err := r.RunCmd(exec.Command("sudo", "sh", "-c", script))
+	if err != nil {
+		return nil, errors.Wrap(err, "expand pattern")
+	}
+	files := []cpFile{}
+	for _, root := range strings.Split(strings.TrimSpace(rr.Stdout.String()), "\n") {
+		if root == "" {
+			continue
 		}
-	},
+		rr, err := r.RunCmd(exec.Command("sudo", "find", root, "-type", "f", "-exec", "stat", "-c", "%a %s %n", "{}", "+"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "find %s", root)
+		}
+		found, err := parseNodeFiles(root, rr.Stdout.String())
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+	return files, nil
 }
 
-func init() {
+// parseNodeFiles parses the 'stat -c "%a %s %n"' output of the files found in root
+func parseNodeFiles(root string, output string) ([]cpFile, error) {
+	files := []cpFile{}
+	root = path.Clean(root)
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected stat output: %q", line)
+		}
+		mode, err := strconv.ParseUint(fields[0], 8, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "mode of %s", fields[2])
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "size of %s", fields[2])
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(path.Clean(fields[2]), root), "/")
+		files = append(files, cpFile{src: fields[2], base: path.Base(root), rel: rel, size: size, mode: os.FileMode(mode).Perm()})
+	}
+	return files, nil
 }
 
-func validateArgs(srcPath string, dstPath string) {
-	if srcPath == "" {
-		exit.Message(reason.Usage, "Source {{.path}} can not be empty", out.V{"path": srcPath})
+// globEscape escapes a path for a shell, except for its glob characters
+func globEscape(p string) string {
+	var sb strings.Builder
+	for _, c := range p {
+		if !strings.ContainsRune("*?[]/._-+,:@%", c) && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !('0' <= c && c <= '9') {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(c)
 	}
+	return sb.String()
+}
 
-	if dstPath == "" {
-		exit.Message(reason.Usage, "Target {{.path}} can not be empty", out.V{"path": dstPath})
+// cpTarget returns where a file is copied to
+func cpTarget(f cpFile, dst string, intoDir bool, onHost bool) string {
+	rel := f.rel
+	if intoDir {
+		rel = path.Join(f.base, rel)
 	}
+	if onHost {
+		return filepath.Join(dst, filepath.FromSlash(rel))
+	}
+	return path.Join(dst, rel)
+}
 
-	if _, err := os.Stat(srcPath); err != nil {
-		if os.IsNotExist(err) {
-			exit.Message(reason.HostPathMissing, "Cannot find directory {{.path}} for copy", out.V{"path": srcPath})
-		} else {
-			exit.Error(reason.HostPathStat, "stat failed", err)
+// copyFiles copies files from the host or a node to the host or another node, showing a progress bar for large copies
+func copyFiles(srcRunner command.Runner, dstRunner command.Runner, files []cpFile, dst string, intoDir bool) error {
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	var bar *pb.ProgressBar
+	if total >= largeCopy && !out.JSON {
+		bar = pb.Full.Start64(total)
+		bar.Set(pb.Bytes, true)
+		bar.SetWidth(79)
+	}
+
+	for _, f := range files {
+		target := cpTarget(f, dst, intoDir, dstRunner == nil)
+		klog.Infof("copying %s (%d bytes) to %s", f.src, f.size, target)
+		if err := copyFile(srcRunner, dstRunner, f, target, bar); err != nil {
+			return errors.Wrapf(err, "copy %s to %s", f.src, target)
+		}
+	}
+	if bar != nil {
+		bar.Finish()
+	}
+	out.Step(style.Copying, "Copied {{.count}} files ({{.size}}) to {{.target}}", out.V{"count": len(files), "size": units.HumanSize(float64(total)), "target": dst})
+	return nil
+}
+
+func copyFile(srcRunner command.Runner, dstRunner command.Runner, f cpFile, target string, bar *pb.ProgressBar) error {
+	perms := fmt.Sprintf("%#o", f.mode)
+
+	var fa assets.CopyableFile
+	if srcRunner == nil {
+		a, err := assets.NewFileAsset(f.src, path.Dir(target), path.Base(target), perms)
+		if err != nil {
+			return errors.Wrap(err, "getting file asset")
 		}
+		fa = a
+	} else {
+		// files on nodes are streamed from the runner as they are read
+		fa = assets.NewStreamAsset(func(w io.Writer) error {
+			return srcRunner.CopyFrom(f.src, w)
+		}, int(f.size), path.Dir(target), path.Base(target), perms)
+	}
+	defer func() {
+		if err := fa.Close(); err != nil {
+			klog.Warningf("error closing the file %s: %v", fa.GetSourcePath(), err)
+		}
+	}()
+	if bar != nil {
+		fa = &progressAsset{CopyableFile: fa, r: bar.NewProxyReader(fa)}
 	}
 
-	if !strings.HasPrefix(dstPath, "/") {
-		exit.Message(reason.Usage, `<target file absolute path> must be an absolute Path. Relative Path is not allowed (example: "/home/docker/copied.txt")`)
+	if dstRunner == nil {
+		return writeHostFile(target, fa, f.mode)
 	}
+	return dstRunner.Copy(fa)
+}
+
+// writeHostFile writes the content of an asset to a file on the host
+func writeHostFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	w, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return errors.Wrap(err, "write")
+	}
+	return w.Close()
+}
+
+// progressAsset reports the progress of reading an asset to a progress bar
+type progressAsset struct {
+	assets.CopyableFile
+	r io.Reader
+}
+
+func (a *progressAsset) Read(p []byte) (int, error) {
+	return a.r.Read(p)
+}
+
+func init() {
 }
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestParseNodePath(t *testing.T) {
+	cc := config.ClusterConfig{
+		Name:   "minikube",
+		Driver: "docker",
+		Nodes:  []config.Node{{Name: "", ControlPlane: true}, {Name: "m02"}},
+	}
+	tests := []struct {
+		arg      string
+		wantNode string
+		onHost   bool
+		wantPath string
+	}{
+		{arg: "./manifests", onHost: true, wantPath: "./manifests"},
+		{arg: "minikube:/home/docker", wantNode: "", wantPath: "/home/docker"},
+		{arg: "m02:/var/log", wantNode: "m02", wantPath: "/var/log"},
+		{arg: "minikube-m02:/var/log", wantNode: "m02", wantPath: "/var/log"},
+		{arg: `C:\Users\me\logs`, onHost: true, wantPath: `C:\Users\me\logs`},
+		{arg: "unknown:/tmp", onHost: true, wantPath: "unknown:/tmp"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.arg, func(t *testing.T) {
+			got := parseNodePath(cc, tc.arg)
+			if got.path != tc.wantPath {
+				t.Errorf("path = %q, want %q", got.path, tc.wantPath)
+			}
+			if tc.onHost {
+				if got.node != nil {
+					t.Errorf("node = %q, want the host", got.node.Name)
+				}
+				return
+			}
+			if got.node == nil || got.node.Name != tc.wantNode {
+				t.Errorf("node = %v, want %q", got.node, tc.wantNode)
+			}
+		})
+	}
+}
+
+func TestParseNodeFiles(t *testing.T) {
+	output := "644 12 /var/log/pods/a.log\n600 0 /var/log/pods/sub dir/b.log\n"
+	got, err := parseNodeFiles("/var/log/pods/", output)
+	if err != nil {
+		t.Fatalf("parseNodeFiles: %v", err)
+	}
+	want := []cpFile{
+		{src: "/var/log/pods/a.log", base: "pods", rel: "a.log", size: 12, mode: 0644},
+		{src: "/var/log/pods/sub dir/b.log", base: "pods", rel: "sub dir/b.log", size: 0, mode: 0600},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNodeFiles() = %+v, want %+v", got, want)
+	}
+
+	got, err = parseNodeFiles("/etc/hosts", "644 10 /etc/hosts\n")
+	if err != nil {
+		t.Fatalf("parseNodeFiles: %v", err)
+	}
+	if len(got) != 1 || got[0].rel != "" || got[0].base != "hosts" {
+		t.Errorf("parseNodeFiles() of a file = %+v, want it to match itself", got)
+	}
+
+	if _, err := parseNodeFiles("/etc", "garbage\n"); err == nil {
+		t.Errorf("parseNodeFiles() of unexpected output succeeded, want error")
+	}
+}
+
+func TestCpTarget(t *testing.T) {
+	file := cpFile{base: "b.txt"}
+	nested := cpFile{base: "logs", rel: "pods/a.log"}
+	tests := []struct {
+		name    string
+		f       cpFile
+		dst     string
+		intoDir bool
+		want    string
+	}{
+		{"file", file, "/home/docker/c.txt", false, "/home/docker/c.txt"},
+		{"file into directory", file, "/home/docker/", true, "/home/docker/b.txt"},
+		{"directory", nested, "/tmp/copy", false, "/tmp/copy/pods/a.log"},
+		{"directory into directory", nested, "/tmp/copy", true, "/tmp/copy/logs/pods/a.log"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cpTarget(tc.f, tc.dst, tc.intoDir, false); got != tc.want {
+				t.Errorf("cpTarget() = %q, want %q", got, tc.want)
+			}
+			if got, want := cpTarget(tc.f, filepath.FromSlash(tc.dst), tc.intoDir, true), filepath.FromSlash(tc.want); got != want {
+				t.Errorf("cpTarget() on the host = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGlobEscape(t *testing.T) {
+	tests := map[string]string{
+		"/var/log/*.log":        "/var/log/*.log",
+		"/home/docker/my file":  `/home/docker/my\ file`,
+		"/tmp/$(reboot)":        `/tmp/\$\(reboot\)`,
+		"/data/[ab]?-1.0_x+y,z": "/data/[ab]?-1.0_x+y,z",
+	}
+	for in, want := range tests {
+		if got := globEscape(in); got != want {
+			t.Errorf("globEscape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestListHost(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "cp")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, f := range []string{"a.log", "b.log", "c.txt", filepath.Join("dir.log", "nested", "d.txt")} {
+		p := filepath.Join(tmp, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(f), 0640); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	files, err := listHost(filepath.Join(tmp, "*.log"))
+	if err != nil {
+		t.Fatalf("listHost: %v", err)
+	}
+	got := []string{}
+	for _, f := range files {
+		got = append(got, f.base+":"+f.rel)
+		if runtime.GOOS != "windows" && f.mode != 0640 {
+			t.Errorf("mode of %s = %v, want 0640", f.src, f.mode)
+		}
+	}
+	sort.Strings(got)
+	want := []string{"a.log:", "b.log:", "dir.log:nested/d.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("listHost() = %v, want %v", got, want)
+	}
+	if n := countSources(files); n != 3 {
+		t.Errorf("countSources() = %d, want 3", n)
+	}
+}
+
+func TestCopyFileFromNode(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "cp")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	r := command.NewFakeCommandRunner()
+	r.SetFileToContents(map[string]string{"/var/log/a.log": "hello"})
+	f := cpFile{src: "/var/log/a.log", base: "a.log", size: 5, mode: 0640}
+	target := filepath.Join(tmp, "logs", "a.log")
+	if err := copyFile(r, nil, f, target, nil); err != nil {
+		t.Fatalf("copyFile: %v", err)
+	}
+	got, err := ioutil.ReadFile(target)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("copied %q, want %q", got, "hello")
+	}
+}
@@ -20,10 +20,13 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	neturl "net/url"
 	"os/exec"
 	"os/user"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -34,6 +37,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/style"
 
 	"k8s.io/minikube/pkg/minikube/browser"
+	"k8s.io/minikube/pkg/minikube/dashboard"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/out"
@@ -44,7 +48,13 @@ import (
 )
 
 var (
-	dashboardURLMode bool
+	dashboardURLMode  bool
+	dashboardExpose   bool
+	dashboardAddress  string
+	dashboardPort     int
+	dashboardTLSCert  string
+	dashboardTLSKey   string
+	dashboardReadOnly bool
 	// Matches: 127.0.0.1:8001
 	// TODO(tstromberg): Get kubectl to implement a stable supported output format.
 	hostPortRe = regexp.MustCompile(`127.0.0.1:\d{4,}`)
@@ -54,8 +64,11 @@ var (
 var dashboardCmd = &cobra.Command{
 	Use:   "dashboard",
 	Short: "Access the Kubernetes dashboard running within the minikube cluster",
-	Long:  `Access the Kubernetes dashboard running within the minikube cluster`,
+	Long: `Access the Kubernetes dashboard running within the minikube cluster.
+By default, the dashboard is only accessible from this host. With --expose, it is served over TLS on the given address, to the clients passing the generated token.`,
+	Example: `minikube dashboard --expose --address=0.0.0.0 --port=8443 --read-only`,
 	Run: func(cmd *cobra.Command, args []string) {
+		validateDashboardFlags(cmd)
 		cname := ClusterFlagValue()
 		co := mustload.Healthy(cname)
 
@@ -104,6 +117,11 @@ var dashboardCmd = &cobra.Command{
 			exit.Message(reason.SvcURLTimeout, "{{.url}} is not accessible: {{.error}}", out.V{"url": url, "error": err})
 		}
 
+		if dashboardExpose {
+			exposeDashboard(p, cname, url)
+			return
+		}
+
 		// check if current user is root
 		user, err := user.Current()
 		if err != nil {
@@ -125,6 +143,88 @@ var dashboardCmd = &cobra.Command{
 	},
 }
 
+// validateDashboardFlags validates the flags exposing the dashboard
+func validateDashboardFlags(cmd *cobra.Command) {
+	if !dashboardExpose {
+		for _, f := range []string{"address", "port", "tls-cert", "tls-key", "read-only"} {
+			if cmd.Flags().Changed(f) {
+				exit.Message(reason.Usage, "--{{.flag}} can only be used with --expose", out.V{"flag": f})
+			}
+		}
+		return
+	}
+	if (dashboardTLSCert == "") != (dashboardTLSKey == "") {
+		exit.Message(reason.Usage, "Please specify both --tls-cert and --tls-key, or none to use a generated certificate")
+	}
+	if dashboardPort < 0 || dashboardPort > 65535 {
+		exit.Message(reason.Usage, "Invalid port {{.port}}", out.V{"port": dashboardPort})
+	}
+}
+
+// exposeDashboard serves the dashboard proxied by kubectl at proxyURL over TLS on the address given with --address,
+// to the clients passing a generated token, until kubectl proxy exits
+func exposeDashboard(p *exec.Cmd, cname string, proxyURL string) {
+	target, err := neturl.Parse(proxyURL)
+	if err != nil {
+		exit.Error(reason.HostDashboardExpose, "Invalid dashboard URL", err)
+	}
+	token, err := dashboard.GenerateToken()
+	if err != nil {
+		exit.Error(reason.HostDashboardExpose, "Failed to generate a token", err)
+	}
+	certFile, keyFile, caFile, err := dashboard.Certs(cname, dashboardAddress, dashboardTLSCert, dashboardTLSKey)
+	if err != nil {
+		exit.Error(reason.HostDashboardExpose, "Failed to generate a TLS certificate", err)
+	}
+
+	l, err := net.Listen("tcp", net.JoinHostPort(dashboardAddress, strconv.Itoa(dashboardPort)))
+	if err != nil {
+		exit.Error(reason.HostDashboardExpose, "Failed to listen", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	srv := &http.Server{Handler: dashboard.Handler(target, token, dashboardReadOnly)}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ServeTLS(l, certFile, keyFile)
+	}()
+	go func() {
+		errc <- errors.Wrap(p.Wait(), "kubectl proxy exited")
+	}()
+
+	var urls []string
+	ips, names := dashboard.Hosts(dashboardAddress)
+	for _, ip := range ips {
+		urls = append(urls, fmt.Sprintf("https://%s/?%s=%s", net.JoinHostPort(ip.String(), strconv.Itoa(port)), dashboard.TokenParam, token))
+	}
+	if len(ips) == 0 {
+		for _, n := range names {
+			urls = append(urls, fmt.Sprintf("https://%s/?%s=%s", net.JoinHostPort(n, strconv.Itoa(port)), dashboard.TokenParam, token))
+		}
+	}
+	if dashboardURLMode {
+		for _, u := range urls {
+			out.Ln(u)
+		}
+	} else {
+		out.Step(style.Celebrate, "The dashboard is exposed at:")
+		for _, u := range urls {
+			out.Infof("{{.url}}", out.V{"url": u})
+		}
+		out.Styled(style.Tip, "Clients can also pass the token as a bearer token: {{.token}}", out.V{"token": token})
+		if caFile != "" {
+			out.Styled(style.Tip, "To trust the dashboard certificate, add {{.ca}} to the certificate authorities of the clients", out.V{"ca": caFile})
+		}
+		if dashboardReadOnly {
+			out.Styled(style.Notice, "The dashboard is read-only: changes to the cluster are denied")
+		}
+	}
+
+	klog.Infof("Serving the dashboard on %s until kubectl proxy exits", l.Addr())
+	if err := <-errc; err != nil {
+		klog.Errorf("dashboard: %v", err)
+	}
+}
+
 // kubectlProxy runs "kubectl proxy", returning host:port
 func kubectlProxy(kubectlVersion string, contextName string) (*exec.Cmd, string, error) {
 	// port=0 picks a random system port
@@ -217,4 +317,10 @@ func checkURL(url string) error {
 
 func init() {
 	dashboardCmd.Flags().BoolVar(&dashboardURLMode, "url", false, "Display dashboard URL instead of opening a browser")
+	dashboardCmd.Flags().BoolVar(&dashboardExpose, "expose", false, "Serve the dashboard over TLS on --address, to the clients passing a generated token, instead of only on localhost")
+	dashboardCmd.Flags().StringVar(&dashboardAddress, "address", "0.0.0.0", "The address to expose the dashboard on, with --expose")
+	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 0, "The port to expose the dashboard on, with --expose. Defaults to a free port")
+	dashboardCmd.Flags().StringVar(&dashboardTLSCert, "tls-cert", "", "The TLS certificate to expose the dashboard with, with --expose. Defaults to a certificate signed by a CA generated in the profile directory")
+	dashboardCmd.Flags().StringVar(&dashboardTLSKey, "tls-key", "", "The key of the TLS certificate given with --tls-cert")
+	dashboardCmd.Flags().BoolVar(&dashboardReadOnly, "read-only", false, "Deny the changes to the cluster through the exposed dashboard, with --expose")
 }
@@ -41,15 +41,19 @@ import (
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/delete"
+	"k8s.io/minikube/pkg/minikube/dns"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/hooks"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mounts"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/tunnel"
 )
 
 var (
@@ -257,6 +261,7 @@ func deleteProfile(ctx context.Context, profile *config.Profile) error {
 	register.Reg.SetStep(register.Deleting)
 
 	viper.Set(config.ProfileName, profile.Name)
+	useProfileDockerHost(profile.Config)
 	if profile.Config != nil {
 		klog.Infof("%s configuration: %+v", profile.Name, profile.Config)
 
@@ -300,11 +305,27 @@ func deleteProfile(ctx context.Context, profile *config.Profile) error {
 		}
 	}
 
+	// the background tunnel would otherwise keep routes to the deleted cluster
+	if _, err := tunnel.StopDaemon(profile.Name); err != nil {
+		klog.Warningf("failed to stop the tunnel of %s: %v", profile.Name, err)
+	}
+	if _, err := mounts.StopDaemon(profile.Name); err != nil {
+		klog.Warningf("failed to stop the mount manager of %s: %v", profile.Name, err)
+	}
+	stopSSHForward(profile.Name)
+	if _, err := dns.StopDaemon(profile.Name); err != nil {
+		klog.Warningf("failed to stop the resolver of %s: %v", profile.Name, err)
+	}
+	if err := dns.UnconfigureHost(profile.Name); err != nil {
+		klog.Warningf("failed to remove the dns configuration of %s: %v", profile.Name, err)
+	}
+
 	if err := hostAndDirsDeleter(api, cc, profile.Name); err != nil {
 		return err
 	}
 
 	out.Step(style.Deleted, `Removed all traces of the "{{.name}}" cluster.`, out.V{"name": profile.Name})
+	hooks.Notify(hooks.Payload{Event: hooks.PostDelete, Profile: profile.Name})
 	return nil
 }
 
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/dns"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// placeholders for flag values
+var (
+	dnsPort    int
+	dnsDomains []string
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Resolves the hostnames of ingresses and LoadBalancer services on the host",
+	Long: `Resolves the hostnames of the ingresses and LoadBalancer services of the cluster on the host, without editing /etc/hosts.
+
+Once enabled, a resolver running in the background answers for:
+- the hosts of the ingresses in the --domain domains, "test" by default, such as hello-john.test
+- <service>.<namespace>.<profile>.minikube for the LoadBalancer services with an external IP, see 'minikube tunnel'
+- any other name ending in .<profile>.minikube, which resolves to the node so that ingresses can be reached under it
+
+The queries of the host for these names are sent to the resolver through systemd-resolved on Linux, and /etc/resolver on macOS.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+var dnsEnableCmd = &cobra.Command{
+	Use:     "enable",
+	Short:   "Starts resolving the hostnames of the cluster on the host",
+	Long:    "Starts the resolver of the cluster in the background, and configures the resolver of the host to send it the queries for the names of the cluster. Configuring the host requires sudo.",
+	Example: "minikube dns enable --domain test --domain example",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube dns enable")
+		}
+
+		cname := ClusterFlagValue()
+		mustload.Partial(cname)
+		if _, err := dns.StopDaemon(cname); err != nil {
+			exit.Error(reason.HostDNS, "Failed to stop the resolver", err)
+		}
+		d, err := dns.StartDaemon(cname, dnsDomains, dnsPort)
+		if err != nil {
+			exit.Error(reason.HostDNS, "Failed to start the resolver", err)
+		}
+		names := append([]string{dns.Zone(cname)}, dnsDomains...)
+		out.Step(style.Running, "Resolving {{.names}} on 127.0.0.1:{{.port}} (pid {{.pid}})", out.V{"names": strings.Join(names, ", "), "port": d.Port, "pid": d.Pid})
+
+		if err := dns.UnconfigureHost(cname); err != nil {
+			klog.Warningf("unable to remove the previous host configuration: %v", err)
+		}
+		if err := dns.ConfigureHost(cname, dnsDomains, dnsPort); err != nil {
+			out.WarningT("Unable to configure the resolver of the host: {{.error}}", out.V{"error": err})
+			return
+		}
+		out.Styled(style.Tip, "Ingress hosts such as hello.{{.domain}} now resolve on the host, disable it with: minikube dns disable -p {{.profile}}", out.V{"domain": names[len(names)-1], "profile": cname})
+	},
+}
+
+var dnsDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stops resolving the hostnames of the cluster on the host",
+	Long:  "Stops the resolver started by 'minikube dns enable', and removes the host configuration sending it queries.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube dns disable")
+		}
+
+		cname := ClusterFlagValue()
+		found, err := dns.StopDaemon(cname)
+		if err != nil {
+			exit.Error(reason.HostDNS, "Failed to stop the resolver", err)
+		}
+		if err := dns.UnconfigureHost(cname); err != nil {
+			exit.Error(reason.HostDNS, "Failed to remove the host configuration", err)
+		}
+		if !found {
+			out.Step(style.Empty, "DNS is not enabled for {{.profile}}", out.V{"profile": cname})
+			return
+		}
+		out.Step(style.Stopped, "Stopped resolving the hostnames of {{.profile}}", out.V{"profile": cname})
+	},
+}
+
+var dnsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the status of the resolver of the cluster",
+	Long:  "Shows whether the resolver started by 'minikube dns enable' is running, and the names it resolves.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube dns status")
+		}
+
+		cname := ClusterFlagValue()
+		d, err := dns.LoadDaemon(cname)
+		if err != nil {
+			exit.Error(reason.HostDNS, "Failed to load the resolver state", err)
+		}
+		switch {
+		case d == nil:
+			out.Step(style.Empty, "DNS is not enabled for {{.profile}}, enable it with: minikube dns enable -p {{.profile}}", out.V{"profile": cname})
+		case d.Running():
+			names := append([]string{dns.Zone(cname)}, d.Domains...)
+			out.Step(style.Running, "Resolving {{.names}} on 127.0.0.1:{{.port}} (pid {{.pid}}), started at {{.started}}", out.V{"names": strings.Join(names, ", "), "port": d.Port, "pid": d.Pid, "started": d.Started.Format("2006-01-02 15:04:05")})
+		default:
+			out.WarningT("The resolver of {{.profile}} exited, see {{.log}}", out.V{"profile": cname, "log": d.LogFile})
+		}
+	},
+}
+
+// dnsServeCmd is the resolver started in the background by dns.StartDaemon
+var dnsServeCmd = &cobra.Command{
+	Use:    "serve",
+	Short:  "Resolves the hostnames of the cluster",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := dns.Run(ctx, cname, dnsDomains, dnsPort); err != nil {
+			exit.Error(reason.HostDNS, "resolver failed", err)
+		}
+	},
+}
+
+// restartDNS starts the resolver of a cluster again if DNS is enabled but it exited, for instance after the host restarted
+func restartDNS(cc *config.ClusterConfig) {
+	d, err := dns.LoadDaemon(cc.Name)
+	if err != nil || d == nil || d.Running() {
+		return
+	}
+	if _, err := dns.StartDaemon(cc.Name, d.Domains, d.Port); err != nil {
+		out.WarningT("Unable to restart the resolver: {{.error}}", out.V{"error": err})
+	}
+}
+
+func init() {
+	for _, c := range []*cobra.Command{dnsEnableCmd, dnsServeCmd} {
+		c.Flags().IntVar(&dnsPort, "port", dns.DefaultPort, "The port the resolver listens on, on 127.0.0.1")
+		c.Flags().StringSliceVar(&dnsDomains, "domain", dns.DefaultDomains, "The domains of the ingress hosts to resolve")
+	}
+	dnsCmd.AddCommand(dnsEnableCmd)
+	dnsCmd.AddCommand(dnsDisableCmd)
+	dnsCmd.AddCommand(dnsStatusCmd)
+	dnsCmd.AddCommand(dnsServeCmd)
+}
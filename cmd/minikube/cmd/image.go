@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"io"
 	"io/ioutil"
 	"net/url"
@@ -25,13 +26,17 @@ import (
 	"runtime"
 	"strings"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/image"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
 	docker "k8s.io/minikube/third_party/go-dockerclient"
 )
 
@@ -42,15 +47,22 @@ var imageCmd = &cobra.Command{
 }
 
 var (
-	pull       bool
-	imgDaemon  bool
-	imgRemote  bool
-	overwrite  bool
-	tag        string
-	push       bool
-	dockerFile string
-	buildEnv   []string
-	buildOpt   []string
+	pull          bool
+	imgDaemon     bool
+	imgRemote     bool
+	overwrite     bool
+	loadPlatform  string
+	tag           string
+	push          bool
+	dockerFile    string
+	buildEnv      []string
+	buildOpt      []string
+	buildPlatform string
+	buildSecrets  []string
+
+	scanner      string
+	scanSeverity string
+	scanOutput   string
 )
 
 func saveFile(r io.Reader) (string, error) {
@@ -74,7 +86,7 @@ var loadImageCmd = &cobra.Command{
 	Use:     "load IMAGE | ARCHIVE | -",
 	Short:   "Load a image into minikube",
 	Long:    "Load a image into minikube",
-	Example: "minikube image load image\nminikube image load image.tar",
+	Example: "minikube image load image\nminikube image load image.tar\nminikube image load --platform linux/amd64 image",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			exit.Message(reason.Usage, "Please provide an image in your local daemon to load into minikube via <minikube image load IMAGE_NAME>")
@@ -131,7 +143,7 @@ var loadImageCmd = &cobra.Command{
 		if imgDaemon || imgRemote {
 			image.UseDaemon(imgDaemon)
 			image.UseRemote(imgRemote)
-			if err := machine.CacheAndLoadImages(args, []*config.Profile{profile}, overwrite); err != nil {
+			if err := machine.CacheAndLoadImages(args, []*config.Profile{profile}, overwrite, platformFlag(loadPlatform)); err != nil {
 				exit.Error(reason.GuestImageLoad, "Failed to load image", err)
 			}
 		} else if local {
@@ -165,6 +177,18 @@ $ minikube image unload image busybox
 	},
 }
 
+// platformFlag returns the platform given to --platform, or nil to use the platform of each node
+func platformFlag(s string) *v1.Platform {
+	if s == "" {
+		return nil
+	}
+	p, err := image.ParsePlatform(s)
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid --platform: {{.error}}", out.V{"error": err})
+	}
+	return &p
+}
+
 func createTar(dir string) (string, error) {
 	tar, err := docker.CreateTarStream(dir, dockerFile)
 	if err != nil {
@@ -217,7 +241,12 @@ var buildImageCmd = &cobra.Command{
 				// Otherwise, assume it's a tar
 			}
 		}
-		if err := machine.BuildImage(img, dockerFile, tag, push, buildEnv, buildOpt, []*config.Profile{profile}); err != nil {
+		for _, secret := range buildSecrets {
+			if _, _, err := machine.ParseSecret(secret); err != nil {
+				exit.Error(reason.Usage, "Invalid --secret", err)
+			}
+		}
+		if err := machine.BuildImage(img, dockerFile, tag, push, buildEnv, buildOpt, buildPlatform, buildSecrets, []*config.Profile{profile}); err != nil {
 			exit.Error(reason.GuestImageBuild, "Failed to build image", err)
 		}
 		if tmp != "" {
@@ -231,6 +260,8 @@ var listImageCmd = &cobra.Command{
 	Short: "List images",
 	Example: `
 $ minikube image ls
+
+$ minikube image ls --output json
 `,
 	Aliases: []string{"list"},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -239,9 +270,70 @@ $ minikube image ls
 			exit.Error(reason.Usage, "loading profile", err)
 		}
 
-		if err := machine.ListImages(profile); err != nil {
+		images, err := machine.ListImages(profile)
+		if err != nil {
 			exit.Error(reason.GuestImageList, "Failed to list images", err)
 		}
+		if outputFormat == "json" {
+			printJSON(images)
+			return
+		}
+		for _, img := range images {
+			out.String("%s\n", img)
+		}
+	},
+}
+
+var scanImageCmd = &cobra.Command{
+	Use:   "scan IMAGE [IMAGE...]",
+	Short: "Scan images for vulnerabilities",
+	Long:  "Scan images in minikube, or in the minikube cache, for known vulnerabilities using trivy or grype, which must be installed on the host.",
+	Example: `
+$ minikube image scan my-app:dev
+
+$ minikube image scan my-app:dev --severity high --output json
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if scanSeverity != "" && !image.ValidSeverity(scanSeverity) {
+			exit.Message(reason.Usage, "Sorry, please set the --severity flag to one of the following valid options: [unknown,negligible,low,medium,high,critical]")
+		}
+		if scanOutput != "table" && scanOutput != "json" {
+			exit.Message(reason.Usage, "Sorry, please set the --output flag to one of the following valid options: [table,json]")
+		}
+
+		profile, err := config.LoadProfile(viper.GetString(config.ProfileName))
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+
+		vulns, err := machine.ScanImages(args, profile, scanner)
+		if err != nil {
+			exit.Error(reason.GuestImageScan, "Failed to scan images", err)
+		}
+		vulns = image.FilterVulnerabilities(vulns, scanSeverity)
+
+		if scanOutput == "json" {
+			b, err := json.Marshal(vulns)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "json encoding failure", err)
+			}
+			out.String(string(b))
+			return
+		}
+		if len(vulns) == 0 {
+			out.Step(style.Check, "No vulnerabilities found")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Image", "Vulnerability", "Severity", "Package", "Installed", "Fixed In"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, v := range vulns {
+			table.Append([]string{v.Image, v.ID, v.Severity, v.Package, v.InstalledVersion, v.FixedVersion})
+		}
+		table.Render()
 	},
 }
 
@@ -250,6 +342,7 @@ func init() {
 	loadImageCmd.Flags().BoolVar(&imgDaemon, "daemon", false, "Cache image from docker daemon")
 	loadImageCmd.Flags().BoolVar(&imgRemote, "remote", false, "Cache image from remote registry")
 	loadImageCmd.Flags().BoolVar(&overwrite, "overwrite", true, "Overwrite image even if same image:tag name exists")
+	loadImageCmd.Flags().StringVar(&loadPlatform, "platform", "", "Platform of the image to load into every node, for example linux/arm64. Defaults to the platform of each node")
 	imageCmd.AddCommand(loadImageCmd)
 	imageCmd.AddCommand(removeImageCmd)
 	buildImageCmd.Flags().StringVarP(&tag, "tag", "t", "", "Tag to apply to the new image (optional)")
@@ -257,6 +350,12 @@ func init() {
 	buildImageCmd.Flags().StringVarP(&dockerFile, "file", "f", "", "Path to the Dockerfile to use (optional)")
 	buildImageCmd.Flags().StringArrayVar(&buildEnv, "build-env", nil, "Environment variables to pass to the build. (format: key=value)")
 	buildImageCmd.Flags().StringArrayVar(&buildOpt, "build-opt", nil, "Specify arbitrary flags to pass to the build. (format: key=value)")
+	buildImageCmd.Flags().StringVar(&buildPlatform, "platform", "", "Target platform of the build, for example linux/arm64. Several comma separated platforms are supported by the containerd runtime")
+	buildImageCmd.Flags().StringArrayVar(&buildSecrets, "secret", nil, "Secret file to expose to the build, without it being stored in the image. (format: id=mysecret,src=/local/secret)")
 	imageCmd.AddCommand(buildImageCmd)
 	imageCmd.AddCommand(listImageCmd)
+	scanImageCmd.Flags().StringVar(&scanner, "scanner", "", "Vulnerability scanner to use: trivy or grype. Defaults to the first one found in your path")
+	scanImageCmd.Flags().StringVar(&scanSeverity, "severity", "", "Only report vulnerabilities of at least this severity: unknown, negligible, low, medium, high or critical")
+	scanImageCmd.Flags().StringVarP(&scanOutput, "output", "o", "table", "Output format. Accepted values: [table, json]")
+	imageCmd.AddCommand(scanImageCmd)
 }
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	kubeconfigExportAll      bool
+	kubeconfigExportMerge    bool
+	kubeconfigExportSeparate bool
+	kubeconfigExportEmbed    bool
+	kubeconfigExportDest     string
+)
+
+// kubeconfigCmd represents the kubeconfig command
+var kubeconfigCmd = &cobra.Command{
+	Use:   "kubeconfig",
+	Short: "Manage the kubeconfig entries of clusters",
+	Long:  "Operations on the kubeconfig entries of clusters, kept up to date by 'minikube start' and 'minikube update-context'",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube kubeconfig [export]")
+	},
+}
+
+var kubeconfigExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the kubeconfig entries of clusters to standalone files",
+	Long: `Exports the context, cluster and user of clusters from the kubeconfig to standalone kubeconfig files, for tooling which needs its own.
+With --merge, the clusters are exported to a single file, written to the standard output unless --dest is given. With --separate, each cluster is exported to <profile>.kubeconfig, in the directory given with --dest.`,
+	Example: `minikube kubeconfig export -p foo --dest=foo.kubeconfig
+minikube kubeconfig export --all --separate --dest=kubeconfigs --embed-certs`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if kubeconfigExportMerge && kubeconfigExportSeparate {
+			exit.Message(reason.Usage, "Please specify either --merge or --separate, not both")
+		}
+
+		profiles := []string{ClusterFlagValue()}
+		if kubeconfigExportAll {
+			valid, _, err := config.ListProfiles()
+			if err != nil {
+				exit.Error(reason.HostConfigLoad, "Unable to list profiles", err)
+			}
+			profiles = nil
+			for _, p := range valid {
+				if kubeconfig.HasContext(p.Name, kubeconfig.PathFromEnv()) {
+					profiles = append(profiles, p.Name)
+				}
+			}
+			if len(profiles) == 0 {
+				exit.Message(reason.Usage, "No cluster has a context in {{.path}}", out.V{"path": kubeconfig.PathFromEnv()})
+			}
+		}
+
+		var cfgs []*api.Config
+		for _, p := range profiles {
+			cfg, err := kubeconfig.Extract(p, kubeconfigExportEmbed, kubeconfig.PathFromEnv())
+			if err != nil {
+				exit.Message(reason.HostKubeconfigUpdate, "Unable to export the kubeconfig of {{.profile}}: {{.error}}. To recreate it, run: minikube update-context -p {{.profile}}", out.V{"profile": p, "error": err})
+			}
+			cfgs = append(cfgs, cfg)
+		}
+
+		if kubeconfigExportSeparate {
+			dir := kubeconfigExportDest
+			if dir == "" {
+				dir = "."
+			}
+			for i, p := range profiles {
+				path := filepath.Join(dir, p+".kubeconfig")
+				if err := kubeconfig.Write(cfgs[i], path); err != nil {
+					exit.Error(reason.HostKubeconfigUpdate, "Unable to write the kubeconfig", err)
+				}
+				out.Step(style.Kubectl, "Exported {{.profile}} to {{.path}}", out.V{"profile": p, "path": path})
+			}
+			return
+		}
+
+		merged := kubeconfig.Merge(cfgs...)
+		if kubeconfigExportDest == "" {
+			data, err := kubeconfig.Encode(merged)
+			if err != nil {
+				exit.Error(reason.HostKubeconfigUpdate, "Unable to encode the kubeconfig", err)
+			}
+			if _, err := os.Stdout.Write(data); err != nil {
+				exit.Error(reason.HostKubeconfigUpdate, "Unable to write the kubeconfig", err)
+			}
+			return
+		}
+		if err := kubeconfig.Write(merged, kubeconfigExportDest); err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "Unable to write the kubeconfig", err)
+		}
+		out.Step(style.Kubectl, "Exported {{.count}} clusters to {{.path}}", out.V{"count": len(cfgs), "path": kubeconfigExportDest})
+	},
+}
+
+func init() {
+	kubeconfigExportCmd.Flags().BoolVar(&kubeconfigExportAll, "all", false, "Export all the clusters, instead of the one of --profile")
+	kubeconfigExportCmd.Flags().BoolVar(&kubeconfigExportMerge, "merge", false, "Export the clusters to a single file (default)")
+	kubeconfigExportCmd.Flags().BoolVar(&kubeconfigExportSeparate, "separate", false, "Export each cluster to its own <profile>.kubeconfig file")
+	kubeconfigExportCmd.Flags().BoolVar(&kubeconfigExportEmbed, "embed-certs", false, "Embed the certificates and keys, instead of referencing the files in the minikube home directory")
+	kubeconfigExportCmd.Flags().StringVar(&kubeconfigExportDest, "dest", "", "The file to export to with --merge, the standard output by default, or the directory to export to with --separate, the current directory by default")
+	kubeconfigCmd.AddCommand(kubeconfigExportCmd)
+}
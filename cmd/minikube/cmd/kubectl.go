@@ -21,6 +21,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/detect"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
@@ -45,13 +47,15 @@ var kubectlCmd = &cobra.Command{
 	Short: "Run a kubectl binary matching the cluster version",
 	Long: `Run the Kubernetes client, download it if necessary. Remember -- after kubectl!
 
-This will run the Kubernetes client (kubectl) with the same version as the cluster
+This will run the Kubernetes client (kubectl) with the same version as the cluster, on the context of the cluster,
+so that each profile is managed with a matching kubectl whatever the current context is: minikube kubectl -p foo -- get pods.
+Binaries are cached in the minikube home directory, one per Kubernetes version.
 
 Normally it will download a binary matching the host operating system and architecture,
 but optionally you can also run it directly on the control plane over the ssh connection.
 This can be useful if you cannot run kubectl locally for some reason, like unsupported
 host. Please be aware that when using --ssh all paths will apply to the remote machine.`,
-	Example: "minikube kubectl -- --help\nminikube kubectl -- get pods --namespace kube-system",
+	Example: "minikube kubectl -- --help\nminikube kubectl -- get pods --namespace kube-system\nminikube kubectl -p foo -- get pods",
 	Run: func(cmd *cobra.Command, args []string) {
 		cc, err := config.Load(ClusterFlagValue())
 
@@ -73,7 +77,7 @@ host. Please be aware that when using --ssh all paths will apply to the remote m
 			args = append(kc, args...)
 
 			klog.Infof("Running SSH %v", args)
-			err := machine.CreateSSHShell(co.API, *co.Config, *n, args, false)
+			err := machine.CreateSSHShell(co.API, *co.Config, *n, args, false, machine.SSHForwards{})
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error running kubectl: %v", err)
 				os.Exit(1)
@@ -94,9 +98,10 @@ host. Please be aware that when using --ssh all paths will apply to the remote m
 			os.Exit(1)
 		}
 
-		if len(args) > 1 && args[0] != "--help" {
-			cluster := []string{"--cluster", cname}
-			args = append(cluster, args...)
+		if kubeconfig.HasContext(cname, kubeconfig.PathFromEnv()) {
+			args = contextArgs(cname, args)
+		} else {
+			klog.Warningf("no %q context in %s, running kubectl with its current context", cname, kubeconfig.PathFromEnv())
 		}
 
 		c, err := KubectlCommand(version, args...)
@@ -123,6 +128,22 @@ host. Please be aware that when using --ssh all paths will apply to the remote m
 	},
 }
 
+// contextArgs returns the arguments of kubectl selecting the context of a cluster, unless they already select
+// a context, a cluster, a user or a kubeconfig file
+func contextArgs(context string, args []string) []string {
+	for _, a := range args {
+		if a == "--" {
+			break
+		}
+		for _, f := range []string{"--context", "--cluster", "--user", "--kubeconfig"} {
+			if a == f || strings.HasPrefix(a, f+"=") {
+				return args
+			}
+		}
+	}
+	return append([]string{"--context", context}, args...)
+}
+
 // kubectlPath returns the path to kubectl
 func kubectlPath(cfg config.ClusterConfig) string {
 	return path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubectl")
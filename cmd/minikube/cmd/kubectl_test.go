@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContextArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"get", "pods"}, []string{"--context", "foo", "get", "pods"}},
+		{nil, []string{"--context", "foo"}},
+		{[]string{"--context", "bar", "get", "pods"}, []string{"--context", "bar", "get", "pods"}},
+		{[]string{"get", "pods", "--context=bar"}, []string{"get", "pods", "--context=bar"}},
+		{[]string{"--kubeconfig=other", "get", "pods"}, []string{"--kubeconfig=other", "get", "pods"}},
+		{[]string{"--cluster", "bar", "get", "pods"}, []string{"--cluster", "bar", "get", "pods"}},
+		{[]string{"exec", "pod", "--", "kubectl", "--context", "bar"}, []string{"--context", "foo", "exec", "pod", "--", "kubectl", "--context", "bar"}},
+	}
+	for _, tc := range tests {
+		if got := contextArgs("foo", tc.args); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("contextArgs(%v) = %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
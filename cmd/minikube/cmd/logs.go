@@ -29,6 +29,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/logs"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
 )
 
@@ -47,6 +48,8 @@ var (
 	showProblems bool
 	// fileOutput is where to write logs to. If omitted, writes to stdout.
 	fileOutput string
+	// components are the components to show the logs of, all when empty
+	components []string
 )
 
 // logsCmd represents the logs command
@@ -54,6 +57,9 @@ var logsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "Returns logs to debug a local Kubernetes cluster",
 	Long:  `Gets the logs of the running instance, used for debugging minikube, not user code.`,
+	Example: `
+$ minikube logs --follow --component=kubelet,apiserver
+`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var logOutput *os.File = os.Stdout
 		var err error
@@ -71,7 +77,17 @@ var logsCmd = &cobra.Command{
 			}
 		}
 
-		logs.OutputOffline(numberOfLines, logOutput)
+		if outputFormat == "json" {
+			if showProblems {
+				exit.Message(reason.Usage, "--output=json can not be used with --problems")
+			}
+			register.SetOutputFile(logOutput)
+		}
+
+		// the offline logs are not those of a component
+		if len(components) == 0 {
+			logs.OutputOffline(numberOfLines, logOutput)
+		}
 
 		co := mustload.Running(ClusterFlagValue())
 
@@ -85,11 +101,12 @@ var logsCmd = &cobra.Command{
 			exit.Error(reason.InternalNewRuntime, "Unable to get runtime", err)
 		}
 
+		if err := logs.ValidateComponents(cr, bs, *co.Config, components); err != nil {
+			exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+		}
+
 		if followLogs {
-			err := logs.Follow(cr, bs, *co.Config, co.CP.Runner, logOutput)
-			if err != nil {
-				exit.Error(reason.InternalLogFollow, "Follow", err)
-			}
+			logs.Follow(cr, bs, *co.Config, co.CP.Runner, logOutput, numberOfLines, components)
 			return
 		}
 		if showProblems {
@@ -97,7 +114,7 @@ var logsCmd = &cobra.Command{
 			logs.OutputProblems(problems, numberOfProblems, logOutput)
 			return
 		}
-		err = logs.Output(cr, bs, *co.Config, co.CP.Runner, numberOfLines, logOutput)
+		err = logs.Output(cr, bs, *co.Config, co.CP.Runner, numberOfLines, logOutput, components)
 		if err != nil {
 			out.Ln("")
 			// Avoid exit.Error, since it outputs the issue URL
@@ -108,7 +125,8 @@ var logsCmd = &cobra.Command{
 }
 
 func init() {
-	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
+	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Show only the most recent log entries, and continuously print new entries as they are appended, prefixed with their time and component.")
+	logsCmd.Flags().StringSliceVar(&components, "component", nil, "Comma separated list of the components to show the logs of, such as kubelet,apiserver,storage-provisioner. Defaults to all.")
 	logsCmd.Flags().BoolVar(&showProblems, "problems", false, "Show only log entries which point to known problems")
 	logsCmd.Flags().IntVarP(&numberOfLines, "length", "n", 60, "Number of lines back to go within the log")
 	logsCmd.Flags().StringVar(&nodeName, "node", "", "The node to get logs from. Defaults to the primary control plane.")
@@ -80,23 +80,7 @@ var mountCmd = &cobra.Command{
 			exit.Message(reason.Usage, `Please specify the directory to be mounted: 
 	minikube mount <source directory>:<target directory>   (example: "/host-home:/vm-home")`)
 		}
-		mountString := args[0]
-		idx := strings.LastIndex(mountString, ":")
-		if idx == -1 { // no ":" was present
-			exit.Message(reason.Usage, `mount argument "{{.value}}" must be in form: <source directory>:<target directory>`, out.V{"value": mountString})
-		}
-		hostPath := mountString[:idx]
-		vmPath := mountString[idx+1:]
-		if _, err := os.Stat(hostPath); err != nil {
-			if os.IsNotExist(err) {
-				exit.Message(reason.HostPathMissing, "Cannot find directory {{.path}} for mount", out.V{"path": hostPath})
-			} else {
-				exit.Error(reason.HostPathStat, "stat failed", err)
-			}
-		}
-		if len(vmPath) == 0 || !strings.HasPrefix(vmPath, "/") {
-			exit.Message(reason.Usage, "Target directory {{.path}} must be an absolute path", out.V{"path": vmPath})
-		}
+		hostPath, vmPath := mountPaths(args[0])
 		var debugVal int
 		if klog.V(1).Enabled() {
 			debugVal = 1 // ufs.StartServer takes int debug param
@@ -108,9 +92,11 @@ var mountCmd = &cobra.Command{
 		}
 
 		var ip net.IP
+		var bindIP string // the ip to listen on the user's host machine
+		var forward bool
 		var err error
 		if mountIP == "" {
-			ip, err = cluster.HostIP(co.CP.Host, co.Config.Name)
+			ip, bindIP, forward, err = cluster.MountIPs(co.CP.Host, co.Config.Name)
 			if err != nil {
 				exit.Error(reason.IfHostIP, "Error getting the host IP address to use from within the VM", err)
 			}
@@ -119,6 +105,10 @@ var mountCmd = &cobra.Command{
 			if ip == nil {
 				exit.Message(reason.IfMountIP, "error parsing the input ip address for mount")
 			}
+			bindIP = ip.String()
+			if driver.IsKIC(co.CP.Host.Driver.DriverName()) && runtime.GOOS != "linux" {
+				bindIP = "127.0.0.1"
+			}
 		}
 		port, err := getPort()
 		if err != nil {
@@ -150,10 +140,6 @@ var mountCmd = &cobra.Command{
 			out.WarningT("{{.type}} is not yet a supported filesystem. We will try anyways!", out.V{"type": cfg.Type})
 		}
 
-		bindIP := ip.String() // the ip to listen on the user's host machine
-		if driver.IsKIC(co.CP.Host.Driver.DriverName()) && runtime.GOOS != "linux" {
-			bindIP = "127.0.0.1"
-		}
 		out.Step(style.Mounting, "Mounting host path {{.sourcePath}} into VM as {{.destinationPath}} ...", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
 		out.Infof("Mount type:   {{.name}}", out.V{"type": cfg.Type})
 		out.Infof("User ID:      {{.userID}}", out.V{"userID": cfg.UID})
@@ -189,6 +175,14 @@ var mountCmd = &cobra.Command{
 			}
 		}()
 
+		if forward {
+			f, err := cluster.ForwardToGuest(co.CP.Host.Driver, port)
+			if err != nil {
+				exit.Error(reason.GuestMount, "Error forwarding the file server port to the guest", err)
+			}
+			defer f.Close()
+		}
+
 		err = cluster.Mount(co.CP.Runner, ip.String(), vmPath, cfg)
 		if err != nil {
 			exit.Error(reason.GuestMount, "mount failed", err)
@@ -202,14 +196,40 @@ var mountCmd = &cobra.Command{
 
 func init() {
 	mountCmd.Flags().StringVar(&mountIP, "ip", "", "Specify the ip that the mount should be setup on")
-	mountCmd.Flags().StringVar(&mountType, "type", nineP, "Specify the mount filesystem type (supported types: 9p)")
-	mountCmd.Flags().StringVar(&mountVersion, "9p-version", defaultMountVersion, "Specify the 9p version that the mount should use")
 	mountCmd.Flags().BoolVar(&isKill, "kill", false, "Kill the mount process spawned by minikube start")
-	mountCmd.Flags().StringVar(&uid, "uid", "docker", "Default user id used for the mount")
-	mountCmd.Flags().StringVar(&gid, "gid", "docker", "Default group id used for the mount")
-	mountCmd.Flags().UintVar(&mode, "mode", 0o755, "File permissions used for the mount")
-	mountCmd.Flags().StringSliceVar(&options, "options", []string{}, "Additional mount options, such as cache=fscache")
-	mountCmd.Flags().IntVar(&mSize, "msize", defaultMsize, "The number of bytes to use for 9p packet payload")
+	addMountOptionFlags(mountCmd)
+}
+
+// addMountOptionFlags adds the flags configuring how a directory is mounted to a command
+func addMountOptionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&mountType, "type", nineP, "Specify the mount filesystem type (supported types: 9p)")
+	cmd.Flags().StringVar(&mountVersion, "9p-version", defaultMountVersion, "Specify the 9p version that the mount should use")
+	cmd.Flags().StringVar(&uid, "uid", "docker", "Default user id used for the mount")
+	cmd.Flags().StringVar(&gid, "gid", "docker", "Default group id used for the mount")
+	cmd.Flags().UintVar(&mode, "mode", 0o755, "File permissions used for the mount")
+	cmd.Flags().StringSliceVar(&options, "options", []string{}, "Additional mount options, such as cache=fscache")
+	cmd.Flags().IntVar(&mSize, "msize", defaultMsize, "The number of bytes to use for 9p packet payload")
+}
+
+// mountPaths returns the host and guest paths of a <source directory>:<target directory> mount argument
+func mountPaths(mountString string) (string, string) {
+	idx := strings.LastIndex(mountString, ":")
+	if idx == -1 { // no ":" was present
+		exit.Message(reason.Usage, `mount argument "{{.value}}" must be in form: <source directory>:<target directory>`, out.V{"value": mountString})
+	}
+	hostPath := mountString[:idx]
+	vmPath := mountString[idx+1:]
+	if _, err := os.Stat(hostPath); err != nil {
+		if os.IsNotExist(err) {
+			exit.Message(reason.HostPathMissing, "Cannot find directory {{.path}} for mount", out.V{"path": hostPath})
+		} else {
+			exit.Error(reason.HostPathStat, "stat failed", err)
+		}
+	}
+	if len(vmPath) == 0 || !strings.HasPrefix(vmPath, "/") {
+		exit.Message(reason.Usage, "Target directory {{.path}} must be an absolute path", out.V{"path": vmPath})
+	}
+	return hostPath, vmPath
 }
 
 // getPort asks the kernel for a free open port that is ready to use
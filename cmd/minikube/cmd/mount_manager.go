@@ -0,0 +1,293 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mounts"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// mountPersistent is the value of 'minikube mount add --persistent'
+var mountPersistent bool
+
+// mountListing is a mount as listed by 'minikube mount ls'
+type mountListing struct {
+	HostPath   string
+	GuestPath  string
+	Persistent bool
+	Status     string
+}
+
+var mountAddCmd = &cobra.Command{
+	Use:   "add <source directory>:<target directory>",
+	Short: "Declares a directory kept mounted into minikube",
+	Long: `Declares a host directory which is kept mounted into the cluster by its mount manager, a background process which is started with the cluster.
+
+The mount manager re-establishes the mount whenever it goes missing, for instance after the cluster restarted.
+Mounts are dropped when the cluster stops, unless they are added with --persistent: persistent mounts are re-established on every 'minikube start'.`,
+	Example: `minikube mount add ~/code:/code --persistent`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube mount add <source directory>:<target directory>")
+		}
+		hostPath, guestPath := mountPaths(args[0])
+		hostPath, err := filepath.Abs(hostPath)
+		if err != nil {
+			exit.Error(reason.HostPathStat, "Unable to get the absolute path of the source directory", err)
+		}
+
+		cname := ClusterFlagValue()
+		api, cc := mustload.Partial(cname)
+		if cc.Driver == driver.None {
+			exit.Message(reason.Usage, `'none' driver does not support 'minikube mount' command`)
+		}
+		if mountType != nineP {
+			exit.Message(reason.Usage, "Only 9p mounts can be kept mounted by the mount manager")
+		}
+
+		m := config.Mount{
+			HostPath:   hostPath,
+			GuestPath:  guestPath,
+			Persistent: mountPersistent,
+			Type:       mountType,
+			UID:        uid,
+			GID:        gid,
+			Version:    mountVersion,
+			MSize:      mSize,
+			Mode:       uint32(mode),
+			Options:    options,
+		}
+		var ms []config.Mount
+		for _, e := range cc.Mounts {
+			if e.GuestPath != guestPath {
+				ms = append(ms, e)
+			}
+		}
+		cc.Mounts = append(ms, m)
+		if err := config.SaveProfile(cname, cc); err != nil {
+			exit.Error(reason.HostSaveProfile, "Failed to save config", err)
+		}
+
+		out.Step(style.Mounting, "Added mount of {{.sourcePath}} as {{.destinationPath}} to {{.profile}}", out.V{"sourcePath": hostPath, "destinationPath": guestPath, "profile": cname})
+		if !clusterRunning(api, cc) {
+			out.Styled(style.Tip, "It will be mounted by the mount manager on the next: minikube start -p {{.profile}}", out.V{"profile": cname})
+			return
+		}
+		d, err := mounts.RestartDaemon(cname)
+		if err != nil {
+			exit.Error(reason.HostMountManager, "Failed to start the mount manager", err)
+		}
+		out.Styled(style.Tip, "The mount manager (pid {{.pid}}) logs to {{.log}}, check the mount with: minikube mount ls -p {{.profile}}", out.V{"pid": d.Pid, "log": d.LogFile, "profile": cname})
+	},
+}
+
+var mountRmCmd = &cobra.Command{
+	Use:     "rm <target directory>",
+	Aliases: []string{"remove"},
+	Short:   "Removes a directory kept mounted into minikube",
+	Long:    "Removes a mount added with 'minikube mount add', and unmounts it.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube mount rm <target directory>")
+		}
+		guestPath := args[0]
+
+		cname := ClusterFlagValue()
+		api, cc := mustload.Partial(cname)
+		var ms []config.Mount
+		for _, m := range cc.Mounts {
+			if m.GuestPath != guestPath {
+				ms = append(ms, m)
+			}
+		}
+		if len(ms) == len(cc.Mounts) {
+			exit.Message(reason.Usage, "{{.path}} is not mounted by the mount manager of {{.profile}}, see: minikube mount ls", out.V{"path": guestPath, "profile": cname})
+		}
+		cc.Mounts = ms
+		if err := config.SaveProfile(cname, cc); err != nil {
+			exit.Error(reason.HostSaveProfile, "Failed to save config", err)
+		}
+
+		// the mount manager unmounts everything as it stops
+		if _, err := mounts.StopDaemon(cname); err != nil {
+			exit.Error(reason.HostMountManager, "Failed to stop the mount manager", err)
+		}
+		if len(ms) > 0 && clusterRunning(api, cc) {
+			if _, err := mounts.StartDaemon(cname); err != nil {
+				exit.Error(reason.HostMountManager, "Failed to start the mount manager", err)
+			}
+		}
+		out.Step(style.Unmount, "Removed mount {{.path}} from {{.profile}}", out.V{"path": guestPath, "profile": cname})
+	},
+}
+
+var mountLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "Lists the directories kept mounted into minikube",
+	Long:    "Lists the mounts added with 'minikube mount add', and whether they are currently mounted.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube mount ls")
+		}
+
+		cname := ClusterFlagValue()
+		api, cc := mustload.Partial(cname)
+		var runner command.Runner
+		status := ""
+		if !clusterRunning(api, cc) {
+			status = "Cluster stopped"
+		} else if !mounts.DaemonRunning(cname) {
+			status = "Manager stopped"
+		} else {
+			cp, err := config.PrimaryControlPlane(cc)
+			if err != nil {
+				exit.Error(reason.GuestCpConfig, "Unable to find control plane", err)
+			}
+			h, err := machine.LoadHost(api, config.MachineName(*cc, cp))
+			if err != nil {
+				exit.Error(reason.GuestLoadHost, "Error getting host", err)
+			}
+			if runner, err = machine.CommandRunner(h); err != nil {
+				exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+			}
+		}
+
+		listings := []mountListing{}
+		for _, m := range cc.Mounts {
+			l := mountListing{HostPath: m.HostPath, GuestPath: m.GuestPath, Persistent: m.Persistent, Status: status}
+			if runner != nil {
+				l.Status = "Not mounted"
+				ok, err := cluster.IsMounted(runner, m.GuestPath)
+				if err != nil {
+					klog.Warningf("unable to check if %s is mounted: %v", m.GuestPath, err)
+				}
+				if ok {
+					l.Status = "Mounted"
+				}
+			}
+			listings = append(listings, l)
+		}
+
+		if outputFormat == "json" {
+			printJSON(listings)
+			return
+		}
+		if len(listings) == 0 {
+			out.Step(style.Empty, "No mounts, add one with: minikube mount add <source directory>:<target directory>")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Host path", "Guest path", "Persistent", "Status"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, l := range listings {
+			table.Append([]string{l.HostPath, l.GuestPath, strconv.FormatBool(l.Persistent), l.Status})
+		}
+		table.Render()
+	},
+}
+
+// mountManagerCmd is the mount manager started in the background by mounts.StartDaemon
+var mountManagerCmd = &cobra.Command{
+	Use:    "manager",
+	Short:  "Keeps the mounts of a cluster established",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, cc := mustload.Partial(ClusterFlagValue())
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := mounts.Run(ctx, api, cc.Name); err != nil {
+			exit.Error(reason.HostMountManager, "mount manager failed", err)
+		}
+	},
+}
+
+// clusterRunning returns whether the primary control plane of a cluster is running
+func clusterRunning(api libmachine.API, cc *config.ClusterConfig) bool {
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return false
+	}
+	st, err := machine.Status(api, config.MachineName(*cc, cp))
+	return err == nil && st == state.Running.String()
+}
+
+// startMountManager starts the mount manager of a cluster if it has mounts, once the cluster is running
+func startMountManager(cc *config.ClusterConfig) {
+	if len(cc.Mounts) == 0 || mounts.DaemonRunning(cc.Name) {
+		return
+	}
+	d, err := mounts.StartDaemon(cc.Name)
+	if err != nil {
+		out.WarningT("Unable to start the mount manager: {{.error}}", out.V{"error": err})
+		return
+	}
+	out.Step(style.Mounting, "Started the mount manager for {{.count}} mounts (pid {{.pid}})", out.V{"count": len(cc.Mounts), "pid": d.Pid})
+}
+
+// stopMountManager stops the mount manager of a cluster, which unmounts everything, and drops the mounts not added
+// with --persistent from it
+func stopMountManager(cc *config.ClusterConfig) {
+	if _, err := mounts.StopDaemon(cc.Name); err != nil {
+		out.WarningT("Unable to stop the mount manager: {{.error}}", out.V{"error": err})
+	}
+	var ms []config.Mount
+	for _, m := range cc.Mounts {
+		if m.Persistent {
+			ms = append(ms, m)
+		}
+	}
+	if len(ms) == len(cc.Mounts) {
+		return
+	}
+	cc.Mounts = ms
+	if err := config.SaveProfile(cc.Name, cc); err != nil {
+		klog.Warningf("unable to save the mounts of %s: %v", cc.Name, err)
+	}
+}
+
+func init() {
+	addMountOptionFlags(mountAddCmd)
+	mountAddCmd.Flags().BoolVar(&mountPersistent, "persistent", false, "Re-establish the mount on every start of the cluster, instead of dropping it when the cluster stops")
+	mountCmd.AddCommand(mountAddCmd)
+	mountCmd.AddCommand(mountRmCmd)
+	mountCmd.AddCommand(mountLsCmd)
+	mountCmd.AddCommand(mountManagerCmd)
+}
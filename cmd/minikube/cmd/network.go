@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/sharednet"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	networkDriver  string
+	networkQemuURI string
+)
+
+// networkListing is a shared network as listed by 'minikube network ls'
+type networkListing struct {
+	Name     string
+	Driver   string
+	Subnet   string
+	Profiles []string
+}
+
+var networkCmd = &cobra.Command{
+	Use:   "network",
+	Short: "Manage networks shared by several clusters",
+	Long: `Manage networks shared by several clusters, for multi-cluster scenarios such as service meshes and federation.
+
+Clusters started with 'minikube start --network=<name>' on a shared network are on the same L2 network, get pod and service CIDRs which do not overlap those of the other clusters on it, and routes to reach their pods and services.
+Shared networks are supported by the docker, podman and kvm2 drivers.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+var networkCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Creates a shared network",
+	Long:  "Creates a network which several clusters can join with 'minikube start --network=NAME'.",
+	Example: `minikube network create shared-net
+minikube start -p east --network=shared-net
+minikube start -p west --network=shared-net`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube network create NAME")
+		}
+
+		n, err := sharednet.Create(args[0], networkDriver, networkQemuURI)
+		if err != nil {
+			exit.Error(reason.HostSharedNetwork, "Failed to create shared network", err)
+		}
+		out.Step(style.Connectivity, "Created shared {{.driver}} network {{.name}} ({{.subnet}})", out.V{"driver": n.Driver, "name": n.Name, "subnet": n.Subnet(networkQemuURI)})
+		out.Styled(style.Tip, "Start clusters on it with: minikube start -p <profile> --driver={{.driver}} --network={{.name}}", out.V{"driver": n.Driver, "name": n.Name})
+	},
+}
+
+var networkListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "Lists the shared networks",
+	Long:    "Lists the shared networks, their subnet and the profiles on them.",
+	Run: func(cmd *cobra.Command, args []string) {
+		ns, err := sharednet.List()
+		if err != nil {
+			exit.Error(reason.HostSharedNetwork, "Failed to list shared networks", err)
+		}
+		var listings []networkListing
+		for _, n := range ns {
+			ps, err := sharednet.Profiles(n.Name)
+			if err != nil {
+				exit.Error(reason.HostSharedNetwork, "Failed to list the profiles on a shared network", err)
+			}
+			l := networkListing{Name: n.Name, Driver: n.Driver, Subnet: n.Subnet(networkQemuURI), Profiles: []string{}}
+			for _, p := range ps {
+				l.Profiles = append(l.Profiles, p.Name)
+			}
+			listings = append(listings, l)
+		}
+
+		if outputFormat == "json" {
+			if listings == nil {
+				listings = []networkListing{}
+			}
+			printJSON(listings)
+			return
+		}
+		if len(listings) == 0 {
+			out.Step(style.Empty, "No shared networks, create one with: minikube network create NAME")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Driver", "Subnet", "Profiles"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, l := range listings {
+			subnet := l.Subnet
+			if subnet == "" {
+				// recreated by the next cluster to start on it
+				subnet = "(not created)"
+			}
+			table.Append([]string{l.Name, l.Driver, subnet, strings.Join(l.Profiles, ", ")})
+		}
+		table.Render()
+	},
+}
+
+var networkDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Deletes a shared network",
+	Long:  "Deletes a shared network. The clusters on it have to be deleted first.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube network delete NAME")
+		}
+
+		n, err := sharednet.Load(args[0])
+		if err != nil {
+			exit.Error(reason.HostSharedNetwork, "Failed to load shared network", err)
+		}
+		if n == nil {
+			exit.Message(reason.Usage, "There is no shared network named {{.name}}, see: minikube network ls", out.V{"name": args[0]})
+		}
+		ps, err := sharednet.Profiles(n.Name)
+		if err != nil {
+			exit.Error(reason.HostSharedNetwork, "Failed to list the profiles on a shared network", err)
+		}
+		if len(ps) > 0 {
+			var names []string
+			for _, p := range ps {
+				names = append(names, p.Name)
+			}
+			exit.Message(reason.HostSharedNetworkInUse, "The shared network {{.name}} is used by {{.profiles}}, delete them first", out.V{"name": n.Name, "profiles": strings.Join(names, ", ")})
+		}
+		if err := sharednet.Delete(n, networkQemuURI); err != nil {
+			exit.Error(reason.HostSharedNetwork, "Failed to delete shared network", err)
+		}
+		out.Step(style.Deleted, "Deleted shared network {{.name}}", out.V{"name": n.Name})
+	},
+}
+
+func init() {
+	networkCreateCmd.Flags().StringVar(&networkDriver, "driver", driver.Docker, "Driver of the clusters which will share the network: docker, podman or kvm2")
+	networkCmd.PersistentFlags().StringVar(&networkQemuURI, "kvm-qemu-uri", "qemu:///system", "The KVM QEMU connection URI. (kvm2 driver only)")
+	networkCmd.AddCommand(networkCreateCmd)
+	networkCmd.AddCommand(networkListCmd)
+	networkCmd.AddCommand(networkDeleteCmd)
+}
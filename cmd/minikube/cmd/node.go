@@ -18,7 +18,11 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/reason"
 )
 
@@ -28,6 +32,18 @@ var nodeCmd = &cobra.Command{
 	Short: "Add, remove, or list additional nodes",
 	Long:  "Operations on nodes",
 	Run: func(cmd *cobra.Command, args []string) {
-		exit.Message(reason.Usage, "Usage: minikube node [add|start|stop|delete|list]")
+		exit.Message(reason.Usage, "Usage: minikube node [add|start|stop|restart|resize|drain|cordon|uncordon|delete|list]")
 	},
 }
+
+// nodeRunner returns a runner on the machine of n
+func nodeRunner(co mustload.ClusterController, n config.Node) (command.Runner, error) {
+	if config.MachineName(*co.Config, n) == config.MachineName(*co.Config, *co.CP.Node) {
+		return co.CP.Runner, nil
+	}
+	h, err := machine.LoadHost(co.API, config.MachineName(*co.Config, n))
+	if err != nil {
+		return nil, err
+	}
+	return machine.CommandRunner(h)
+}
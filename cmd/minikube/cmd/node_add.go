@@ -23,6 +23,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/hooks"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
@@ -47,6 +48,11 @@ var nodeAddCmd = &cobra.Command{
 			out.FailureT("none driver does not support multi-node clusters")
 		}
 
+		if cp && !cc.HA {
+			// without a virtual IP, the new API server isn't reachable through the cluster endpoint
+			exit.Message(reason.Usage, "Control plane nodes can only be added to clusters started with --ha")
+		}
+
 		name := node.Name(len(cc.Nodes) + 1)
 
 		out.Step(style.Happy, "Adding node {{.name}} to cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
@@ -82,6 +88,7 @@ var nodeAddCmd = &cobra.Command{
 		}
 
 		out.Step(style.Ready, "Successfully added {{.name}} to {{.cluster}}!", out.V{"name": name, "cluster": cc.Name})
+		hooks.Notify(hooks.Payload{Event: hooks.NodeAdded, Profile: cc.Name, Node: name})
 	},
 }
 
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var nodeCordonCmd = &cobra.Command{
+	Use:   "cordon",
+	Short: "Marks a node as unschedulable.",
+	Long:  "Marks a node as unschedulable. The pods running on it keep running.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node cordon [name]")
+		}
+
+		name := args[0]
+		co := mustload.Running(ClusterFlagValue())
+
+		if err := node.Cordon(*co.Config, name, true); err != nil {
+			exit.Error(reason.GuestNodeCordon, "Failed to cordon node", err)
+		}
+		out.Step(style.Check, "Node {{.name}} is now unschedulable", out.V{"name": name})
+	},
+}
+
+var nodeUncordonCmd = &cobra.Command{
+	Use:   "uncordon",
+	Short: "Marks a node as schedulable.",
+	Long:  "Marks a cordoned or drained node as schedulable again.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node uncordon [name]")
+		}
+
+		name := args[0]
+		co := mustload.Running(ClusterFlagValue())
+
+		if err := node.Cordon(*co.Config, name, false); err != nil {
+			exit.Error(reason.GuestNodeCordon, "Failed to uncordon node", err)
+		}
+		out.Step(style.Check, "Node {{.name}} is now schedulable", out.V{"name": name})
+	},
+}
+
+func init() {
+	nodeCmd.AddCommand(nodeCordonCmd)
+	nodeCmd.AddCommand(nodeUncordonCmd)
+}
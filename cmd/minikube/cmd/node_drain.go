@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	// drainForce evicts the pods no controller manages
+	drainForce bool
+	// drainGracePeriod overrides the termination grace period of the evicted pods
+	drainGracePeriod int
+	// drainTimeout is how long to wait for the evictions
+	drainTimeout time.Duration
+)
+
+var nodeDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Drains a node.",
+	Long:  "Marks a node as unschedulable, then evicts its pods, honoring their pod disruption budgets.",
+	Example: `
+$ minikube node drain m02
+$ minikube node drain m02 --force --timeout=1m
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node drain [name]")
+		}
+
+		name := args[0]
+		co := mustload.Running(ClusterFlagValue())
+
+		out.Step(style.Waiting, "Draining node {{.name}} ...", out.V{"name": name})
+		if err := node.Drain(*co.Config, name, drainOptions()); err != nil {
+			exit.Error(reason.GuestNodeDrain, "Failed to drain node", err)
+		}
+		out.Step(style.Check, "Successfully drained node {{.name}}", out.V{"name": name})
+	},
+}
+
+// drainOptions returns the drain options set by the flags
+func drainOptions() node.DrainOptions {
+	return node.DrainOptions{Force: drainForce, GracePeriod: drainGracePeriod, Timeout: drainTimeout}
+}
+
+// addDrainFlags adds the flags setting the drain options to cmd
+func addDrainFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&drainForce, "force", false, "Also evict the pods which no controller manages. They are lost.")
+	cmd.Flags().IntVar(&drainGracePeriod, "grace-period", -1, "Seconds the evicted pods have to terminate. If negative, the grace period of the pods is used.")
+	cmd.Flags().DurationVar(&drainTimeout, "timeout", 5*time.Minute, "How long to wait for the evictions, which pod disruption budgets can block. If zero, wait forever.")
+}
+
+func init() {
+	addDrainFlags(nodeDrainCmd)
+	nodeCmd.AddCommand(nodeDrainCmd)
+}
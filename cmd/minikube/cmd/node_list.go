@@ -28,6 +28,14 @@ import (
 	"k8s.io/minikube/pkg/minikube/reason"
 )
 
+// nodeInfo is a node, as listed by 'minikube node list --output=json'
+type nodeInfo struct {
+	Name         string
+	IP           string
+	ControlPlane bool
+	Worker       bool
+}
+
 var nodeListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List nodes.",
@@ -46,6 +54,15 @@ var nodeListCmd = &cobra.Command{
 			klog.Infof("%v", cc.Nodes)
 		}
 
+		if outputFormat == "json" {
+			nodes := []nodeInfo{}
+			for _, n := range cc.Nodes {
+				nodes = append(nodes, nodeInfo{Name: config.MachineName(*cc, n), IP: n.IP, ControlPlane: n.ControlPlane, Worker: n.Worker})
+			}
+			printJSON(nodes)
+			os.Exit(0)
+		}
+
 		for _, n := range cc.Nodes {
 			machineName := config.MachineName(*cc, n)
 			fmt.Printf("%s\t%s\n", machineName, n.IP)
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	resizeCPUs     int
+	resizeMemory   string
+	resizeDiskSize string
+)
+
+var nodeResizeCmd = &cobra.Command{
+	Use:   "resize",
+	Short: "Changes the CPUs, memory or disk size of a node.",
+	Long: `Changes the CPUs, memory or disk size of a node, keeping its state.
+The docker and podman drivers change the limits of the running container. The kvm2 driver hot-plugs CPUs and memory when it can.
+Otherwise, the node is drained and restarted with its new resources. Disks can only grow, and only the kvm2 and hyperkit drivers support it.`,
+	Example: `
+$ minikube node resize m02 --memory=8g --cpus=4
+$ minikube node resize m02 --disk-size=40g
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node resize [name] [--cpus=N] [--memory=size] [--disk-size=size]")
+		}
+
+		co := mustload.Running(ClusterFlagValue())
+		n, _, err := node.Retrieve(*co.Config, args[0])
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+
+		wanted := resizeResources()
+		if wanted == (machine.Resources{}) {
+			exit.Message(reason.Usage, "Nothing to resize: set at least one of --cpus, --memory or --disk-size")
+		}
+		cpus, memory, disk := config.NodeResources(*co.Config, *n)
+		if err := machine.CheckResize(co.Config.Driver, machine.Resources{CPUs: cpus, Memory: memory, DiskSize: disk}, wanted); err != nil {
+			exit.Message(reason.GuestNodeResizeUnsupported, "Unable to resize node: {{.error}}", out.V{"error": err})
+		}
+
+		machineName := config.MachineName(*co.Config, *n)
+		out.Step(style.Provisioning, "Resizing node {{.name}} ...", out.V{"name": machineName})
+		live, err := machine.ResizeLive(*co.Config, *n, wanted)
+		if err != nil {
+			exit.Error(reason.GuestNodeResize, "Failed to resize node", err)
+		}
+
+		if live {
+			saveNodeResources(co.Config, n, wanted)
+			// the kubelet reads the capacity of the machine when it starts, which is the host's in containers
+			if !driver.IsKIC(co.Config.Driver) {
+				r, err := nodeRunner(co, *n)
+				if err != nil {
+					exit.Error(reason.GuestNodeResize, "Failed to get node runner", err)
+				}
+				if err := sysinit.New(r).Restart("kubelet"); err != nil {
+					exit.Error(reason.GuestNodeResize, "Failed to restart kubelet", err)
+				}
+			}
+		} else {
+			restartNode(cmd, co, *n, func() {
+				if err := machine.ResizeStopped(co.API, *co.Config, *n, wanted); err != nil {
+					exit.Error(reason.GuestNodeResize, "Failed to resize node", err)
+				}
+				saveNodeResources(co.Config, n, wanted)
+			})
+		}
+		out.Step(style.Happy, "Successfully resized node {{.name}}!", out.V{"name": machineName})
+	},
+}
+
+// resizeResources returns the resources set by the flags, exiting if they are invalid
+func resizeResources() machine.Resources {
+	r := machine.Resources{CPUs: resizeCPUs}
+	if r.CPUs != 0 && r.CPUs < minimumCPUS {
+		exit.Message(reason.RsrcInsufficientCores, "Requested cpu count {{.requested_cpus}} is less than the minimum allowed of {{.minimum_cpus}}", out.V{"requested_cpus": r.CPUs, "minimum_cpus": minimumCPUS})
+	}
+
+	var err error
+	if resizeMemory != "" {
+		if r.Memory, err = util.CalculateSizeInMB(resizeMemory); err != nil {
+			exit.Message(reason.Usage, "Invalid memory size {{.size}}: {{.error}}", out.V{"size": resizeMemory, "error": err})
+		}
+		if r.Memory < minUsableMem {
+			exit.Message(reason.RsrcInsufficientReqMemory, "Requested memory allocation {{.requested}}MiB is less than the usable minimum of {{.minimum_memory}}MB", out.V{"requested": r.Memory, "minimum_memory": minUsableMem})
+		}
+	}
+	if resizeDiskSize != "" {
+		if r.DiskSize, err = util.CalculateSizeInMB(resizeDiskSize); err != nil {
+			exit.Message(reason.Usage, "Invalid disk size {{.size}}: {{.error}}", out.V{"size": resizeDiskSize, "error": err})
+		}
+	}
+	return r
+}
+
+// saveNodeResources stores the new resources of n in the cluster config
+func saveNodeResources(cc *config.ClusterConfig, n *config.Node, r machine.Resources) {
+	if r.CPUs != 0 {
+		n.CPUs = r.CPUs
+	}
+	if r.Memory != 0 {
+		n.Memory = r.Memory
+	}
+	if r.DiskSize != 0 {
+		n.DiskSize = r.DiskSize
+	}
+	if err := config.SaveNode(cc, n); err != nil {
+		exit.Error(reason.HostSaveProfile, "Failed to save config", err)
+	}
+	klog.Infof("saved resources of node %q: %+v", n.Name, r)
+}
+
+func init() {
+	nodeResizeCmd.Flags().IntVar(&resizeCPUs, "cpus", 0, "Number of CPUs allocated to the node.")
+	nodeResizeCmd.Flags().StringVar(&resizeMemory, "memory", "", "Amount of RAM allocated to the node (format: <number>[<unit>], where unit = b, k, m or g).")
+	nodeResizeCmd.Flags().StringVar(&resizeDiskSize, "disk-size", "", "Disk size allocated to the node (format: <number>[<unit>], where unit = b, k, m or g). Disks can only grow.")
+	addDrainFlags(nodeResizeCmd)
+	nodeCmd.AddCommand(nodeResizeCmd)
+}
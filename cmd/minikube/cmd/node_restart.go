@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// rollingRestart restarts all the nodes, one at a time
+var rollingRestart bool
+
+var nodeRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restarts a node, or all of them one at a time.",
+	Long: `Drains a node, restarts its machine, waits for it to be ready, then marks it as schedulable again.
+With --rolling, restarts all the nodes this way one at a time, the control plane last.`,
+	Example: `
+$ minikube node restart m02
+$ minikube node restart --rolling
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 && !rollingRestart {
+			exit.Message(reason.Usage, "Usage: minikube node restart [name] or minikube node restart --rolling")
+		}
+		if len(args) != 0 && rollingRestart {
+			exit.Message(reason.Usage, "A node name can't be given with --rolling")
+		}
+
+		co := mustload.Running(ClusterFlagValue())
+
+		var nodes []config.Node
+		if rollingRestart {
+			nodes = restartOrder(co.Config.Nodes)
+		} else {
+			n, _, err := node.Retrieve(*co.Config, args[0])
+			if err != nil {
+				exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+			}
+			nodes = []config.Node{*n}
+		}
+
+		for _, n := range nodes {
+			restartNode(cmd, co, n, nil)
+		}
+	},
+}
+
+// restartOrder returns the nodes in the order of a rolling restart: the workers, then the control plane
+func restartOrder(nodes []config.Node) []config.Node {
+	ordered := []config.Node{}
+	for _, n := range nodes {
+		if !n.ControlPlane {
+			ordered = append(ordered, n)
+		}
+	}
+	for _, n := range nodes {
+		if n.ControlPlane {
+			ordered = append(ordered, n)
+		}
+	}
+	return ordered
+}
+
+// restartNode drains the node, unless it is the only one, restarts it, then uncordons it once it is ready.
+// whileStopped, if not nil, is called while the machine of the node is stopped.
+func restartNode(cmd *cobra.Command, co mustload.ClusterController, n config.Node, whileStopped func()) {
+	cc := co.Config
+	machineName := config.MachineName(*cc, n)
+
+	// the pods of a single node cluster have nowhere to go
+	drain := len(cc.Nodes) > 1
+	if drain {
+		out.Step(style.Waiting, "Draining node {{.name}} ...", out.V{"name": machineName})
+		if err := node.Drain(*cc, machineName, drainOptions()); err != nil {
+			exit.Error(reason.GuestNodeDrain, "Failed to drain node", err)
+		}
+	}
+
+	if err := machine.StopHost(co.API, machineName); err != nil {
+		exit.Error(reason.GuestNodeStop, "Failed to stop node", err)
+	}
+	if whileStopped != nil {
+		whileStopped()
+	}
+
+	out.Step(style.Restarting, "Starting node {{.name}} ...", out.V{"name": machineName})
+	startNode(cmd, cc, &n)
+
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		exit.Error(reason.InternalKubernetesClient, "kubernetes client", err)
+	}
+	if err := kverify.WaitNodeCondition(client, machineName, core.NodeReady, kapi.ReasonableStartTime); err != nil {
+		exit.Error(reason.GuestNodeStart, "Node did not become ready", err)
+	}
+
+	if drain {
+		if err := node.Cordon(*cc, machineName, false); err != nil {
+			exit.Error(reason.GuestNodeCordon, "Failed to uncordon node", err)
+		}
+	}
+	out.Step(style.Happy, "Successfully restarted node {{.name}}!", out.V{"name": machineName})
+}
+
+func init() {
+	nodeRestartCmd.Flags().BoolVar(&rollingRestart, "rolling", false, "Restart all the nodes, one at a time, the control plane last.")
+	nodeRestartCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
+	addDrainFlags(nodeRestartCmd)
+	nodeCmd.AddCommand(nodeRestartCmd)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestRestartOrder(t *testing.T) {
+	nodes := []config.Node{
+		{Name: "", ControlPlane: true, Worker: true},
+		{Name: "m02", Worker: true},
+		{Name: "m03", Worker: true},
+	}
+	var got []string
+	for _, n := range restartOrder(nodes) {
+		got = append(got, n.Name)
+	}
+	want := []string{"m02", "m03", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("restartOrder = %q, want %q", got, want)
+	}
+}
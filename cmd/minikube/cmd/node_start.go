@@ -56,31 +56,36 @@ var nodeStartCmd = &cobra.Command{
 		}
 
 		register.Reg.SetStep(register.InitialSetup)
-		r, p, m, h, err := node.Provision(cc, n, n.ControlPlane, viper.GetBool(deleteOnFailure))
-		if err != nil {
-			exit.Error(reason.GuestNodeProvision, "provisioning host for node", err)
-		}
+		startNode(cmd, cc, n)
+		out.Step(style.Happy, "Successfully started node {{.name}}!", out.V{"name": machineName})
+	},
+}
 
-		s := node.Starter{
-			Runner:         r,
-			PreExists:      p,
-			MachineAPI:     m,
-			Host:           h,
-			Cfg:            cc,
-			Node:           n,
-			ExistingAddons: nil,
-		}
+// startNode provisions and starts an existing node, exiting on failure
+func startNode(cmd *cobra.Command, cc *config.ClusterConfig, n *config.Node) {
+	r, p, m, h, err := node.Provision(cc, n, n.ControlPlane, viper.GetBool(deleteOnFailure))
+	if err != nil {
+		exit.Error(reason.GuestNodeProvision, "provisioning host for node", err)
+	}
+
+	s := node.Starter{
+		Runner:         r,
+		PreExists:      p,
+		MachineAPI:     m,
+		Host:           h,
+		Cfg:            cc,
+		Node:           n,
+		ExistingAddons: nil,
+	}
 
-		_, err = node.Start(s, n.ControlPlane)
+	_, err = node.Start(s, n.ControlPlane)
+	if err != nil {
+		_, err := maybeDeleteAndRetry(cmd, *cc, *n, nil, err)
 		if err != nil {
-			_, err := maybeDeleteAndRetry(cmd, *cc, *n, nil, err)
-			if err != nil {
-				node.ExitIfFatal(err)
-				exit.Error(reason.GuestNodeStart, "failed to start node", err)
-			}
+			node.ExitIfFatal(err)
+			exit.Error(reason.GuestNodeStart, "failed to start node", err)
 		}
-		out.Step(style.Happy, "Successfully started node {{.name}}!", out.V{"name": machineName})
-	},
+	}
 }
 
 func init() {
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	preloadgen "k8s.io/minikube/pkg/minikube/preload"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+// preloadContainer is the name of the temporary container preloads are generated in
+const preloadContainer = "minikube-preload-create"
+
+var (
+	preloadK8sVersion      string
+	preloadRuntime         string
+	preloadArch            string
+	preloadImageRepository string
+	preloadForce           bool
+)
+
+// preloadCmd represents the set of preload subcommands
+var preloadCmd = &cobra.Command{
+	Use:   "preload",
+	Short: "Manage preloaded images tarballs",
+	Long:  "Operations on the tarballs of Kubernetes images and binaries which 'minikube start' extracts into new nodes, instead of pulling the images",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube preload [create]")
+	},
+}
+
+var preloadCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Generates a preloaded images tarball for 'minikube start' to use",
+	Long: `Generates the preloaded images tarball of a Kubernetes version and container runtime in a temporary docker container, and registers it in the minikube cache, so that 'minikube start' uses it rather than downloading one.
+This allows using Kubernetes versions for which minikube publishes no preload, and preparing hosts without internet access.`,
+	Example: "minikube preload create --kubernetes-version=v1.23.0-alpha.1 --container-runtime=containerd",
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := util.ParseKubernetesVersion(preloadK8sVersion)
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid Kubernetes version {{.version}}: {{.error}}", out.V{"version": preloadK8sVersion, "error": err})
+		}
+		k8sVersion := "v" + version.String()
+
+		rt := validContainerRuntime(preloadRuntime)
+		if preloadArch != runtime.GOARCH {
+			exit.Message(reason.Usage, "A preload for {{.arch}} must be created on a {{.arch}} host, this host is {{.host}}", out.V{"arch": preloadArch, "host": runtime.GOARCH})
+		}
+
+		dest := download.TarballPath(k8sVersion, rt)
+		if _, err := os.Stat(dest); err == nil && !preloadForce {
+			out.Step(style.Check, "A preload for Kubernetes {{.version}} and {{.runtime}} already exists: {{.path}}", out.V{"version": k8sVersion, "runtime": rt, "path": dest})
+			out.Styled(style.Tip, "Use --force to generate it again")
+			return
+		}
+
+		out.Step(style.Waiting, "Generating the preload for Kubernetes {{.version}} and {{.runtime}}, this may take several minutes ...", out.V{"version": k8sVersion, "runtime": rt})
+		err = preloadgen.Generate(preloadgen.Options{
+			KubernetesVersion: k8sVersion,
+			ContainerRuntime:  rt,
+			Arch:              preloadArch,
+			ImageRepository:   preloadImageRepository,
+			Name:              preloadContainer,
+			Output:            dest,
+		})
+		if err != nil {
+			exit.Error(reason.HostPreloadCreate, "Failed to generate the preload", err)
+		}
+		out.Step(style.Success, "Created {{.path}}, 'minikube start --kubernetes-version={{.version}} --container-runtime={{.runtime}}' will use it", out.V{"path": dest, "version": k8sVersion, "runtime": rt})
+	},
+}
+
+// validContainerRuntime returns the canonical name of a container runtime, or exits if it is not supported
+func validContainerRuntime(name string) string {
+	rt := strings.ToLower(name)
+	if rt == "crio" {
+		rt = "cri-o"
+	}
+	for _, r := range cruntime.ValidRuntimes() {
+		if r == rt {
+			return rt
+		}
+	}
+	exit.Message(reason.Usage, `Invalid Container Runtime: "{{.runtime}}". Valid runtimes are: {{.validOptions}}`, out.V{"runtime": name, "validOptions": strings.Join(cruntime.ValidRuntimes(), ", ")})
+	return ""
+}
+
+func init() {
+	preloadCreateCmd.Flags().StringVar(&preloadK8sVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version to preload the images and binaries of")
+	preloadCreateCmd.Flags().StringVar(&preloadRuntime, "container-runtime", constants.DefaultContainerRuntime, "The container runtime to preload the images into. Valid options: docker, cri-o, containerd")
+	preloadCreateCmd.Flags().StringVar(&preloadArch, "arch", runtime.GOARCH, "The architecture of the preload, it must be the architecture of this host")
+	preloadCreateCmd.Flags().StringVar(&preloadImageRepository, "image-repository", "", "Alternative image repository to pull the images from")
+	preloadCreateCmd.Flags().BoolVar(&preloadForce, "force", false, "Generate the preload even if it already exists")
+	preloadCmd.AddCommand(preloadCreateCmd)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/prune"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	pruneDryRun     bool
+	pruneAll        bool
+	pruneCategories = map[prune.Category]*bool{}
+)
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Reclaims the disk space used by what no cluster uses anymore",
+	Long: `Reclaims the disk space used on the host and on the nodes by what no cluster uses anymore:
+  cache:       the Kubernetes binaries and preloaded tarballs of the host cache, for versions which no cluster uses
+  base-images: the ISOs and kicbase images of other minikube versions, which no cluster uses
+  images:      the images of the nodes which no container uses, except the ones Kubernetes needs
+  build-cache: the cache of the image builds of the nodes
+  volumes:     the directories of the storage provisioner which no persistent volume uses anymore
+
+All the categories are pruned unless some are given. The nodes pruned are the running nodes of the cluster, or of all clusters with --all.`,
+	Example: `minikube prune --dry-run
+minikube prune --cache --base-images
+minikube prune --images --all`,
+	Run: func(cmd *cobra.Command, args []string) {
+		categories := map[prune.Category]bool{}
+		for c, set := range pruneCategories {
+			if *set {
+				categories[c] = true
+			}
+		}
+		if len(categories) == 0 {
+			for _, c := range prune.Categories {
+				categories[c] = true
+			}
+		}
+
+		items := pruneItems(categories)
+		prune.Sort(items)
+		if len(items) == 0 {
+			out.Step(style.Celebrate, "Nothing to prune")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Category", "Location", "Name", "Size"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, i := range items {
+			table.Append([]string{string(i.Category), i.Location, i.Name, units.HumanSize(float64(i.Size))})
+		}
+		table.Render()
+
+		if pruneDryRun {
+			out.Step(style.Notice, "{{.size}} can be reclaimed, run without --dry-run to remove them", out.V{"size": units.HumanSize(float64(prune.Total(items)))})
+			return
+		}
+
+		var reclaimed int64
+		for _, i := range items {
+			if err := i.Remove(); err != nil {
+				out.WarningT("Unable to remove {{.name}} from {{.location}}: {{.error}}", out.V{"name": i.Name, "location": i.Location, "error": err})
+				continue
+			}
+			reclaimed += i.Size
+		}
+		out.Step(style.Deleted, "Reclaimed {{.size}}", out.V{"size": units.HumanSize(float64(reclaimed))})
+	},
+}
+
+// pruneItems returns what can be pruned on the host and on the nodes for the categories
+func pruneItems(categories map[prune.Category]bool) []prune.Item {
+	validProfiles, invalidProfiles, err := config.ListProfiles()
+	if err != nil {
+		klog.Warningf("error listing profiles: %v", err)
+	}
+	// what any cluster uses is kept, whichever clusters are pruned
+	u := prune.InUseBy(append(validProfiles, invalidProfiles...))
+
+	items, err := prune.HostItems(localpath.MakeMiniPath("cache"), categories, u)
+	if err != nil {
+		exit.Error(reason.HostPrune, "Failed to list what can be pruned on the host", err)
+	}
+	if categories[prune.BaseImages] {
+		items = append(items, prune.DaemonItems(u)...)
+	}
+
+	if !categories[prune.Images] && !categories[prune.BuildCache] && !categories[prune.Volumes] {
+		return items
+	}
+	profiles := validProfiles
+	if !pruneAll {
+		profiles = nil
+		for _, p := range validProfiles {
+			if p.Name == ClusterFlagValue() {
+				profiles = append(profiles, p)
+			}
+		}
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.Error(reason.NewAPIClient, "Failed to get machine client", err)
+	}
+	defer api.Close()
+	for _, p := range profiles {
+		nodeItems, err := machine.PruneItems(api, p.Config, categories)
+		if err != nil {
+			out.WarningT("Unable to list what can be pruned on {{.profile}}: {{.error}}", out.V{"profile": p.Name, "error": err})
+			continue
+		}
+		items = append(items, nodeItems...)
+	}
+	return items
+}
+
+func init() {
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Only report what would be removed, and how much disk space it would reclaim")
+	pruneCmd.Flags().BoolVar(&pruneAll, "all", false, "Prune the nodes of all clusters, instead of the ones of --profile")
+	for _, f := range []struct {
+		category prune.Category
+		usage    string
+	}{
+		{prune.Cache, "Prune the Kubernetes binaries and preloaded tarballs of the host cache, for versions which no cluster uses"},
+		{prune.BaseImages, "Prune the ISOs and kicbase images of other minikube versions, which no cluster uses"},
+		{prune.Images, "Prune the images of the nodes which no container uses, except the ones Kubernetes needs"},
+		{prune.BuildCache, "Prune the cache of the image builds of the nodes"},
+		{prune.Volumes, "Prune the directories of the storage provisioner which no persistent volume uses anymore"},
+	} {
+		pruneCategories[f.category] = pruneCmd.Flags().Bool(string(f.category), false, f.usage)
+	}
+}
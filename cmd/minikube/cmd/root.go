@@ -17,11 +17,13 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,6 +36,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kubectl/pkg/util/templates"
 	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/addons"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/audit"
 	"k8s.io/minikube/pkg/minikube/config"
@@ -46,6 +49,9 @@ import (
 	"k8s.io/minikube/pkg/minikube/translate"
 )
 
+// outputFormat is the value of the global --output flag
+var outputFormat string
+
 var dirs = [...]string{
 	localpath.MiniPath(),
 	localpath.MakeMiniPath("certs"),
@@ -73,6 +79,17 @@ var RootCmd = &cobra.Command{
 			out.WarningT("User name '{{.username}}' is not valid", out.V{"username": userName})
 			exit.Message(reason.Usage, "User name must be 60 chars or less.")
 		}
+		// commands with their own --output flag handle it themselves
+		if cmd.Flags().Lookup("output") == cmd.Root().PersistentFlags().Lookup("output") {
+			if outputFormat != "text" && outputFormat != "json" {
+				exit.Message(reason.Usage, "Sorry, please set the --output flag to one of the following valid options: [text,json]")
+			}
+			out.SetJSON(outputFormat == "json")
+		}
+		// clusters started with --docker-host keep talking to their remote daemon
+		if cc, err := config.Load(ClusterFlagValue()); err == nil {
+			useProfileDockerHost(cc)
+		}
 	},
 }
 
@@ -204,6 +221,7 @@ func init() {
 	RootCmd.PersistentFlags().StringP(config.ProfileName, "p", constants.DefaultClusterName, `The name of the minikube VM being used. This can be set to allow having multiple instances of minikube independently.`)
 	RootCmd.PersistentFlags().StringP(configCmd.Bootstrapper, "b", "kubeadm", "The name of the cluster bootstrapper that will set up the Kubernetes cluster.")
 	RootCmd.PersistentFlags().String(config.UserFlag, "", "Specifies the user executing the operation. Useful for auditing operations executed by 3rd party tools. Defaults to the operating system username.")
+	RootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
 
 	groups := templates.CommandGroups{
 		{
@@ -225,6 +243,9 @@ func init() {
 				podmanEnvCmd,
 				cacheCmd,
 				imageCmd,
+				preloadCmd,
+				bundleCmd,
+				buildBaseCmd,
 			},
 		},
 		{
@@ -233,7 +254,11 @@ func init() {
 				configCmd.AddonsCmd,
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
+				scheduleCmd,
+				certsCmd,
 				updateContextCmd,
+				kubeconfigCmd,
+				pruneCmd,
 			},
 		},
 		{
@@ -241,6 +266,8 @@ func init() {
 			Commands: []*cobra.Command{
 				serviceCmd,
 				tunnelCmd,
+				networkCmd,
+				dnsCmd,
 			},
 		},
 		{
@@ -250,7 +277,9 @@ func init() {
 				sshCmd,
 				kubectlCmd,
 				nodeCmd,
+				snapshotCmd,
 				cpCmd,
+				scpCmd,
 			},
 		},
 		{
@@ -260,6 +289,9 @@ func init() {
 				sshHostCmd,
 				ipCmd,
 				logsCmd,
+				auditCmd,
+				topCmd,
+				storageCmd,
 				updateCheckCmd,
 				versionCmd,
 				optionsCmd,
@@ -270,6 +302,7 @@ func init() {
 
 	// Ungrouped commands will show up in the "Other Commands" section
 	RootCmd.AddCommand(completionCmd)
+	RootCmd.AddCommand(sshForwardCmd)
 	templates.ActsAsRootCommand(RootCmd, []string{"options"}, groups...)
 
 	if err := viper.BindPFlags(RootCmd.PersistentFlags()); err != nil {
@@ -292,6 +325,7 @@ func initConfig() {
 		}
 	}
 	setupViper()
+	addons.RegisterInstalled()
 }
 
 func setupViper() {
@@ -305,6 +339,20 @@ func setupViper() {
 	viper.SetDefault(config.WantUpdateNotification, true)
 	viper.SetDefault(config.ReminderWaitPeriodInHours, 24)
 	viper.SetDefault(config.WantNoneDriverWarning, true)
+
+	// the oci package, and the commands it runs, only look at the environment
+	if viper.IsSet(config.Rootless) && os.Getenv(constants.MinikubeRootlessEnv) == "" {
+		os.Setenv(constants.MinikubeRootlessEnv, strconv.FormatBool(viper.GetBool(config.Rootless)))
+	}
+}
+
+// printJSON prints v as the JSON document a command outputs with --output=json
+func printJSON(v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		exit.Error(reason.InternalJSONMarshal, "json encoding failure", err)
+	}
+	out.String("%s\n", b)
 }
 
 func addToPath(dir string) {
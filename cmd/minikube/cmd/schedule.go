@@ -0,0 +1,194 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/schedule"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	lifecycleStart string
+	lifecycleStop  string
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Starts and stops the cluster on a schedule",
+	Long: `Starts and stops the cluster on recurring schedules in the standard cron format: minute, hour, day of month, month and day of week.
+
+The schedules are saved with the profile and applied by a lightweight background process on the host, shared by all the profiles, which exits once no profile has a schedule anymore.
+Starts and stops which were missed while the host was asleep are applied when it wakes up, if they are less than 12 hours old.
+The background process does not survive a reboot of the host: it is restarted by the next 'minikube start' or 'minikube schedule set', and the schedules which fired in between are skipped.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+var scheduleSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Sets the start and stop schedules of the cluster",
+	Long:  "Sets the start and stop schedules of the cluster, in the standard cron format, and starts the background scheduler if it is not running.",
+	Example: `# start on weekday mornings and stop in the evenings
+minikube schedule set --start "0 9 * * 1-5" --stop "0 19 * * 1-5"
+
+# only stop every night, leaving starts to you
+minikube schedule set --stop "0 22 * * *"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube schedule set --start <cron> --stop <cron>")
+		}
+		if lifecycleStart == "" && lifecycleStop == "" {
+			exit.Message(reason.Usage, "At least one of --start and --stop is required")
+		}
+
+		lc := config.LifecycleConfig{Start: lifecycleStart, Stop: lifecycleStop}
+		if _, err := schedule.ParsePolicy(lc); err != nil {
+			exit.Message(reason.Usage, "Invalid schedule: {{.error}}", out.V{"error": err})
+		}
+
+		cname := ClusterFlagValue()
+		_, cc := mustload.Partial(cname)
+		cc.Lifecycle = &lc
+		if err := config.SaveProfile(cname, cc); err != nil {
+			exit.Error(reason.HostSaveProfile, "Failed to save config", err)
+		}
+
+		d, err := schedule.EnsureLifecycleDaemon()
+		if err != nil {
+			exit.Error(reason.HostLifecycleDaemon, "Failed to start the scheduler", err)
+		}
+		out.Step(style.Check, "Saved the schedules of {{.profile}}", out.V{"profile": cname})
+		showLifecycle(cc)
+		out.Styled(style.Tip, "The scheduler is running in the background (pid {{.pid}}), logs are written to {{.log}}", out.V{"pid": d.Pid, "log": d.LogFile})
+	},
+}
+
+var scheduleShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Shows the schedules of the cluster",
+	Long:  "Shows the start and stop schedules of the cluster, when they fire next, and any pending stop set with 'minikube stop --schedule'.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube schedule show")
+		}
+
+		cname := ClusterFlagValue()
+		_, cc := mustload.Partial(cname)
+		if cc.ScheduledStop != nil {
+			at := time.Unix(cc.ScheduledStop.InitiationTime, 0).Add(cc.ScheduledStop.Duration)
+			out.Infof("one-time stop at {{.time}}", out.V{"time": at.Format("2006-01-02 15:04")})
+		}
+		if cc.Lifecycle == nil {
+			out.Step(style.Empty, "No schedules are set for {{.profile}}", out.V{"profile": cname})
+			return
+		}
+		showLifecycle(cc)
+
+		d, err := schedule.LoadLifecycleDaemon()
+		if err != nil {
+			exit.Error(reason.HostLifecycleDaemon, "Failed to load the scheduler state", err)
+		}
+		if d == nil || !d.Running() {
+			out.WarningT("The scheduler is not running, run 'minikube start' or 'minikube schedule set' again to restart it")
+		}
+	},
+}
+
+var scheduleClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Removes the schedules of the cluster",
+	Long:  "Removes the start and stop schedules of the cluster. The background scheduler exits once no profile has a schedule anymore.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube schedule clear")
+		}
+
+		cname := ClusterFlagValue()
+		_, cc := mustload.Partial(cname)
+		if cc.Lifecycle == nil {
+			out.Step(style.Empty, "No schedules are set for {{.profile}}", out.V{"profile": cname})
+			return
+		}
+		cc.Lifecycle = nil
+		if err := config.SaveProfile(cname, cc); err != nil {
+			exit.Error(reason.HostSaveProfile, "Failed to save config", err)
+		}
+		out.Step(style.Deleted, "Removed the schedules of {{.profile}}", out.V{"profile": cname})
+	},
+}
+
+var scheduleDaemonCmd = &cobra.Command{
+	Use:    "daemon",
+	Short:  "Runs the lifecycle scheduler in the foreground",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := schedule.RunLifecycle(); err != nil {
+			exit.Error(reason.HostLifecycleDaemon, "Scheduler failed", err)
+		}
+	},
+}
+
+// restartLifecycle restarts the lifecycle scheduler if the cluster has schedules, as it does not survive a reboot of the host
+func restartLifecycle(cc *config.ClusterConfig) {
+	if cc.Lifecycle == nil {
+		return
+	}
+	if _, err := schedule.EnsureLifecycleDaemon(); err != nil {
+		out.WarningT("Unable to restart the scheduler: {{.error}}", out.V{"error": err})
+	}
+}
+
+// showLifecycle prints the schedules of the cluster and when they fire next
+func showLifecycle(cc *config.ClusterConfig) {
+	policy, err := schedule.ParsePolicy(*cc.Lifecycle)
+	if err != nil {
+		out.WarningT("Invalid schedule: {{.error}}", out.V{"error": err})
+		return
+	}
+	now := time.Now()
+	for _, s := range []struct {
+		action string
+		cron   *schedule.Cron
+	}{{"start", policy.Start}, {"stop", policy.Stop}} {
+		if s.cron == nil {
+			continue
+		}
+		next := "never"
+		if t := s.cron.Next(now); !t.IsZero() {
+			next = t.Format("Mon 2006-01-02 15:04")
+		}
+		out.Infof("{{.action}}: {{.cron}} (next: {{.next}})", out.V{"action": s.action, "cron": s.cron.String(), "next": next})
+	}
+}
+
+func init() {
+	scheduleSetCmd.Flags().StringVar(&lifecycleStart, "start", "", "Schedule to start the cluster on, in cron format (e.g. \"0 9 * * 1-5\")")
+	scheduleSetCmd.Flags().StringVar(&lifecycleStop, "stop", "", "Schedule to stop the cluster on, in cron format (e.g. \"0 19 * * 1-5\")")
+	scheduleCmd.AddCommand(scheduleSetCmd)
+	scheduleCmd.AddCommand(scheduleShowCmd)
+	scheduleCmd.AddCommand(scheduleClearCmd)
+	scheduleCmd.AddCommand(scheduleDaemonCmd)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+// scpCmd represents the scp command
+var scpCmd = &cobra.Command{
+	Use:   "scp [NODE:]SOURCE [NODE:]TARGET",
+	Short: "Copy files and directories between the host and the nodes with scp",
+	Long: `Copy files and directories between the host and the nodes, or between two nodes, with the command line 'scp' command.
+Directories are copied recursively, and the modes and modification times of the files are preserved.
+NODE is the name of a node, as listed by 'minikube node list'. The target must be writable by the SSH user of the node.
+Use 'minikube cp' to copy as root, with glob patterns, or with drivers which do not run SSH on the nodes.`,
+	Example: `minikube scp ./manifests minikube:/home/docker/
+minikube scp minikube-m02:/var/log/pods ./pods
+minikube scp minikube:/home/docker/data minikube-m02:/home/docker/`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.Message(reason.Usage, "usage: minikube scp [NODE:]SOURCE [NODE:]TARGET")
+		}
+
+		co := mustload.Running(ClusterFlagValue())
+		if co.CP.Host.DriverName == driver.None {
+			exit.Message(reason.Usage, "'none' driver does not support 'minikube scp' command")
+		}
+
+		src := parseNodePath(*co.Config, args[0])
+		dst := parseNodePath(*co.Config, args[1])
+		if src.node == nil && dst.node == nil {
+			exit.Message(reason.Usage, "Neither {{.src}} nor {{.dst}} is on a node, prefix one of them with the name of a node and a colon", out.V{"src": args[0], "dst": args[1]})
+		}
+
+		if _, err := exec.LookPath("scp"); err != nil {
+			exit.Message(reason.Usage, "'minikube scp' requires the command line 'scp' command: {{.error}}", out.V{"error": err})
+		}
+
+		if err := copySCP(co.API, *co.Config, src, dst); err != nil {
+			exit.Error(reason.GuestSCP, "scp failed", err)
+		}
+	},
+}
+
+// copySCP copies src to dst, through a temporary directory on the host when both are on nodes
+func copySCP(api libmachine.API, cc config.ClusterConfig, src nodePath, dst nodePath) error {
+	if src.node != nil && dst.node != nil {
+		tmp, err := ioutil.TempDir("", "minikube-scp")
+		if err != nil {
+			return errors.Wrap(err, "temp dir")
+		}
+		defer os.RemoveAll(tmp)
+
+		if err := copySCP(api, cc, src, nodePath{path: tmp}); err != nil {
+			return err
+		}
+		return copySCP(api, cc, nodePath{path: filepath.Join(tmp, path.Base(src.path))}, dst)
+	}
+
+	n := src.node
+	if n == nil {
+		n = dst.node
+	}
+	t, err := machine.GetSSHTarget(api, cc, *n)
+	if err != nil {
+		return errors.Wrapf(err, "ssh target of %s", n.Name)
+	}
+
+	args := append(scpArgs(t), scpPath(t, src), scpPath(t, dst))
+	klog.Infof("running scp %v", args)
+	c := exec.Command("scp", args...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// scpArgs returns the arguments of a recursive, permission preserving scp to or from a node
+func scpArgs(t machine.SSHTarget) []string {
+	return []string{
+		"-r", "-p",
+		"-P", fmt.Sprint(t.Port),
+		"-i", t.KeyPath,
+		"-o", "IdentitiesOnly=yes",
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "UserKnownHostsFile=" + os.DevNull,
+		"-o", "LogLevel=ERROR",
+	}
+}
+
+// scpPath returns how scp refers to an endpoint
+func scpPath(t machine.SSHTarget, e nodePath) string {
+	if e.node == nil {
+		return e.path
+	}
+	addr := t.Addr
+	if strings.Contains(addr, ":") {
+		addr = "[" + addr + "]"
+	}
+	return fmt.Sprintf("%s@%s:%s", t.User, addr, e.path)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+func TestSCPPath(t *testing.T) {
+	n := &config.Node{Name: "m02"}
+	tests := []struct {
+		name   string
+		target machine.SSHTarget
+		e      nodePath
+		want   string
+	}{
+		{"host", machine.SSHTarget{User: "docker", Addr: "127.0.0.1"}, nodePath{path: "./logs"}, "./logs"},
+		{"node", machine.SSHTarget{User: "docker", Addr: "127.0.0.1"}, nodePath{node: n, path: "/var/log"}, "docker@127.0.0.1:/var/log"},
+		{"ipv6", machine.SSHTarget{User: "docker", Addr: "::1"}, nodePath{node: n, path: "/var/log"}, "docker@[::1]:/var/log"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := scpPath(tc.target, tc.e); got != tc.want {
+				t.Errorf("scpPath() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
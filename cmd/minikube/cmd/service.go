@@ -82,7 +82,8 @@ var serviceCmd = &cobra.Command{
 		cname := ClusterFlagValue()
 		co := mustload.Healthy(cname)
 
-		urls, err := service.WaitForService(co.API, co.Config.Name, namespace, svc, serviceURLTemplate, serviceURLMode, https, wait, interval)
+		urlMode := serviceURLMode || outputFormat == "json"
+		urls, err := service.WaitForService(co.API, co.Config.Name, namespace, svc, serviceURLTemplate, urlMode, https, wait, interval)
 		if err != nil {
 			var s *service.SVCNotFoundError
 			if errors.As(err, &s) {
@@ -93,7 +94,12 @@ You may select another namespace by using 'minikube service {{.service}} -n <nam
 		}
 
 		if driver.NeedsPortForward(co.Config.Driver) {
-			startKicServiceTunnel(svc, cname)
+			startKicServiceTunnel(svc, cname, co.Config.Driver)
+			return
+		}
+
+		if outputFormat == "json" {
+			printJSON(service.SvcURL{Namespace: namespace, Name: svc, URLs: urls})
 			return
 		}
 
@@ -111,7 +117,7 @@ func init() {
 	serviceCmd.PersistentFlags().StringVar(&serviceURLFormat, "format", defaultServiceFormatTemplate, "Format to output service URL in. This format will be applied to each url individually and they will be printed one at a time.")
 }
 
-func startKicServiceTunnel(svc, configName string) {
+func startKicServiceTunnel(svc, configName, driverName string) {
 	ctrlC := make(chan os.Signal, 1)
 	signal.Notify(ctrlC, os.Interrupt)
 
@@ -120,7 +126,7 @@ func startKicServiceTunnel(svc, configName string) {
 		exit.Error(reason.InternalKubernetesClient, "error creating clientset", err)
 	}
 
-	port, err := oci.ForwardedPort(oci.Docker, configName, 22)
+	port, err := oci.ForwardedPort(driverName, configName, 22)
 	if err != nil {
 		exit.Error(reason.DrvPortForward, "error getting ssh port", err)
 	}
@@ -152,14 +158,15 @@ func startKicServiceTunnel(svc, configName string) {
 
 func openURLs(svc string, urls []string) {
 	for _, u := range urls {
-		_, err := url.Parse(u)
+		parsed, err := url.Parse(u)
 		if err != nil {
 			klog.Warningf("failed to parse url %q: %v (will not open)", u, err)
 			out.String(fmt.Sprintf("%s\n", u))
 			continue
 		}
 
-		if serviceURLMode {
+		// UDP and SCTP ports can not be opened in a browser
+		if serviceURLMode || (parsed.Scheme != "http" && parsed.Scheme != "https" && parsed.Scheme != "") {
 			out.String(fmt.Sprintf("%s\n", u))
 			continue
 		}
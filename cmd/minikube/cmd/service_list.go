@@ -48,6 +48,11 @@ var serviceListCmd = &cobra.Command{
 			os.Exit(reason.ExSvcUnavailable)
 		}
 
+		if outputFormat == "json" {
+			printJSON(serviceURLs)
+			return
+		}
+
 		var data [][]string
 		for _, serviceURL := range serviceURLs {
 			if len(serviceURL.URLs) == 0 {
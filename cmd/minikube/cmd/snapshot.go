@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+)
+
+// snapshotCmd represents the set of snapshot subcommands
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore the state of a cluster",
+	Long:  "Operations on snapshots, which capture the disks of every node of a cluster - including etcd and loaded images - so that it can be reset to a known state",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube snapshot [create|restore|list]")
+	},
+}
+
+// validateSnapshotDriver exits if the driver of a cluster can't be snapshotted
+func validateSnapshotDriver(cc *config.ClusterConfig) {
+	if !snapshot.Supported(cc.Driver) {
+		exit.Message(reason.DrvUnsupportedSnapshot, "Snapshots are not supported by the {{.driver}} driver, only by docker, podman and kvm2", out.V{"driver": cc.Driver})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:     "create [name]",
+	Short:   "Captures the current state of a cluster",
+	Long:    "Captures the disks of every node of a running cluster as a named snapshot. Docker and podman nodes are paused while their volume is archived, KVM nodes are briefly stopped.",
+	Example: "minikube snapshot create clean",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube snapshot create [name]")
+		}
+
+		co := mustload.Running(ClusterFlagValue())
+		validateSnapshotDriver(co.Config)
+
+		out.Step(style.Copying, "Creating snapshot {{.name}} of {{.profile}} ...", out.V{"name": args[0], "profile": co.Config.Name})
+		s, err := snapshot.Create(co.API, *co.Config, args[0])
+		if err != nil {
+			exit.Error(reason.GuestSnapshotCreate, "creating snapshot", err)
+		}
+		out.Step(style.Success, "Created snapshot {{.name}} of {{.count}} node(s)", out.V{"name": s.Name, "count": len(s.Machines)})
+	},
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+}
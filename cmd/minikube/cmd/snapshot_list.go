@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the snapshots of a cluster",
+	Long:  "Lists the snapshots of a cluster, oldest first.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube snapshot list")
+		}
+
+		_, cc := mustload.Partial(ClusterFlagValue())
+		snaps, err := snapshot.List(cc.Name)
+		if err != nil {
+			exit.Error(reason.GuestSnapshotList, "listing snapshots", err)
+		}
+		if len(snaps) == 0 {
+			out.Step(style.Empty, "No snapshots found for {{.profile}}. To create one, run: minikube snapshot create <name>", out.V{"profile": cc.Name})
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Created", "Kubernetes", "Nodes", "Size"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, s := range snaps {
+			size := ""
+			if b, err := s.Size(); err != nil {
+				klog.Warningf("unable to compute size of snapshot %s: %v", s.Name, err)
+			} else {
+				size = units.HumanSize(float64(b))
+			}
+			table.Append([]string{s.Name, s.Created.Format("2006-01-02 15:04:05"), s.KubernetesVersion, strings.Join(s.Machines, ","), size})
+		}
+		table.Render()
+	},
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotListCmd)
+}
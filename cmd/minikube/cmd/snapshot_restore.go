@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:     "restore [name]",
+	Short:   "Resets a cluster to a snapshot",
+	Long:    "Stops every node of a cluster, replaces their disks with the ones captured in a snapshot and starts them again. Changes made since the snapshot was created are lost. If the restore fails, the nodes stopped for it are started again.",
+	Example: "minikube snapshot restore clean",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube snapshot restore [name]")
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		validateSnapshotDriver(cc)
+
+		out.Step(style.Restarting, "Restoring {{.profile}} to snapshot {{.name}} ...", out.V{"name": args[0], "profile": cc.Name})
+		s, err := snapshot.Restore(api, *cc, args[0])
+		if err != nil {
+			exit.Error(reason.GuestSnapshotRestore, "restoring snapshot", err)
+		}
+		out.Step(style.Success, "Restored snapshot {{.name}} created at {{.time}}", out.V{"name": s.Name, "time": s.Created.Format("2006-01-02 15:04:05")})
+	},
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+}
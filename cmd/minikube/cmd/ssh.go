@@ -31,13 +31,21 @@ import (
 	"k8s.io/minikube/pkg/minikube/reason"
 )
 
-var nativeSSHClient bool
+var (
+	nativeSSHClient bool
+	sshForwards     machine.SSHForwards
+)
 
 // sshCmd represents the docker-ssh command
 var sshCmd = &cobra.Command{
 	Use:   "ssh",
 	Short: "Log into the minikube environment (for debugging)",
-	Long:  "Log into or run a command on a machine with SSH; similar to 'docker-machine ssh'.",
+	Long: `Log into or run a command on a machine with SSH; similar to 'docker-machine ssh'.
+
+Agent (-A) and port (-L, -R) forwardings use the command line 'ssh' command, whatever --native-ssh is set to.`,
+	Example: `minikube ssh -n m02
+minikube ssh -A -- git clone git@github.com:kubernetes/minikube.git
+minikube ssh -L 8080:localhost:80 -R 5000:localhost:5000`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cname := ClusterFlagValue()
 		co := mustload.Running(cname)
@@ -56,7 +64,7 @@ var sshCmd = &cobra.Command{
 			}
 		}
 
-		err = machine.CreateSSHShell(co.API, *co.Config, *n, args, nativeSSHClient)
+		err = machine.CreateSSHShell(co.API, *co.Config, *n, args, nativeSSHClient, sshForwards)
 		if err != nil {
 			// This is typically due to a non-zero exit code, so no need for flourish.
 			out.ErrLn("ssh: %v", err)
@@ -69,4 +77,7 @@ var sshCmd = &cobra.Command{
 func init() {
 	sshCmd.Flags().BoolVar(&nativeSSHClient, "native-ssh", true, "Use native Golang SSH client (default true). Set to 'false' to use the command line 'ssh' command when accessing the docker machine. Useful for the machine drivers when they will not start with 'Waiting for SSH'.")
 	sshCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to ssh into. Defaults to the primary control plane.")
+	sshCmd.Flags().BoolVarP(&sshForwards.Agent, "forward-agent", "A", false, "Forward the connection to the local authentication agent, to use its keys from the node.")
+	sshCmd.Flags().StringArrayVarP(&sshForwards.Local, "local-forward", "L", []string{}, "Forward a local port to the node, as [bind_address:]port:host:hostport. Can be repeated.")
+	sshCmd.Flags().StringArrayVarP(&sshForwards.Remote, "remote-forward", "R", []string{}, "Forward a port of the node to the host, as [bind_address:]port:host:hostport. Can be repeated.")
 }
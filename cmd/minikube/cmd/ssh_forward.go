@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/sshforward"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// sshForwardDriver is the driver of the cluster, whose config is not saved yet when it is first started
+var sshForwardDriver string
+
+// sshForwardCmd forwards the ports of the containers of a cluster on a daemon reached over ssh, see 'minikube start --docker-host'
+var sshForwardCmd = &cobra.Command{
+	Use:    "ssh-forward",
+	Short:  "Forwards the ports of a cluster on a remote docker or podman host",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := sshforward.Run(ctx, ClusterFlagValue(), sshForwardDriver); err != nil {
+			exit.Error(reason.HostSSHForward, "port forwarding failed", err)
+		}
+	},
+}
+
+// useDockerHost points the docker and podman CLIs to the daemon given with --docker-host, or to the one of the existing cluster
+func useDockerHost(cmd *cobra.Command, existing *config.ClusterConfig) {
+	host := viper.GetString(dockerHost)
+	drvName := viper.GetString("driver")
+	if existing != nil {
+		if cmd.Flags().Changed(dockerHost) && host != existing.DockerHost {
+			exit.Message(reason.Usage, "The docker host of the existing {{.profile}} cluster can not be changed, delete it first", out.V{"profile": existing.Name})
+		}
+		host = existing.DockerHost
+		drvName = existing.Driver
+	}
+	if host == "" {
+		return
+	}
+	if drvName != oci.Docker && drvName != oci.Podman {
+		exit.Message(reason.Usage, "--docker-host can only be used with --driver=docker or --driver=podman")
+	}
+	if err := oci.ValidateDaemonHost(host); err != nil {
+		exit.Message(reason.Usage, "Invalid --docker-host {{.host}}: {{.error}}", out.V{"host": host, "error": err})
+	}
+	if err := oci.SetDaemonHost(drvName, host); err != nil {
+		exit.Error(reason.Usage, "Unable to use the docker host", err)
+	}
+}
+
+// useProfileDockerHost points the docker and podman CLIs to the daemon of a cluster: the one given with --docker-host,
+// or the one of the environment of the user
+func useProfileDockerHost(cc *config.ClusterConfig) {
+	if cc == nil || !driver.IsKIC(cc.Driver) {
+		return
+	}
+	if err := oci.SetDaemonHost(cc.Driver, cc.DockerHost); err != nil {
+		klog.Warningf("unable to use the docker host of %s: %v", cc.Name, err)
+	}
+}
+
+// startSSHForward (re)starts forwarding the ports of a cluster, if its daemon is reached over ssh.
+// It must run before the nodes are provisioned, as provisioning connects to their forwarded ssh port.
+func startSSHForward(cc *config.ClusterConfig) {
+	if !driver.IsKIC(cc.Driver) || !oci.IsSSHDaemonHost(cc.Driver) {
+		return
+	}
+	d, err := sshforward.RestartDaemon(cc.Name, cc.Driver)
+	if err != nil {
+		exit.Error(reason.HostSSHForward, "Unable to forward the ports of the remote docker host", err)
+	}
+	out.Step(style.Connectivity, "Forwarding the ports of {{.profile}} from the remote {{.driver}} host (pid {{.pid}})", out.V{"profile": cc.Name, "driver": cc.Driver, "pid": d.Pid})
+}
+
+// stopSSHForward stops forwarding the ports of a cluster
+func stopSSHForward(profile string) {
+	if _, err := sshforward.StopDaemon(profile); err != nil {
+		klog.Warningf("failed to stop the port forwarder of %s: %v", profile, err)
+	}
+}
+
+func init() {
+	sshForwardCmd.Flags().StringVar(&sshForwardDriver, "driver", oci.Docker, "The driver of the cluster, docker or podman")
+}
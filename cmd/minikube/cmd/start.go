@@ -48,6 +48,8 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/bundle"
+	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
@@ -56,6 +58,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/driver/auxdriver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/hooks"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -64,7 +67,9 @@ import (
 	"k8s.io/minikube/pkg/minikube/notify"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/sharednet"
 	"k8s.io/minikube/pkg/minikube/style"
 	pkgtrace "k8s.io/minikube/pkg/trace"
 
@@ -80,6 +85,8 @@ var (
 	apiServerNames   []string
 	apiServerIPs     []net.IP
 	hostRe           = regexp.MustCompile(`^[^-][\w\.-]+$`)
+	// startBundle is the offline bundle the cluster is started from, if any
+	startBundle *bundle.Manifest
 )
 
 func init() {
@@ -137,14 +144,14 @@ func runStart(cmd *cobra.Command, args []string) {
 	register.SetEventLogPath(localpath.EventLog(ClusterFlagValue()))
 	ctx := context.Background()
 	out.SetJSON(outputFormat == "json")
-	if err := pkgtrace.Initialize(viper.GetString(trace)); err != nil {
+	if err := pkgtrace.Initialize(viper.GetString(trace), viper.GetString(traceEndpoint), "minikube start"); err != nil {
 		exit.Message(reason.Usage, "error initializing tracing: {{.Error}}", out.V{"Error": err.Error()})
 	}
 	defer pkgtrace.Cleanup()
 	displayVersion(version.GetVersion())
 
-	// No need to do the update check if no one is going to see it
-	if !viper.GetBool(interactive) || !viper.GetBool(dryRun) {
+	// No need to do the update check if no one is going to see it, nor if the host is offline
+	if viper.GetString(config.BundleFlag) == "" && (!viper.GetBool(interactive) || !viper.GetBool(dryRun)) {
 		// Avoid blocking execution on optional HTTP fetches
 		go notify.MaybePrintUpdateTextFromGithub()
 	}
@@ -154,6 +161,10 @@ func runStart(cmd *cobra.Command, args []string) {
 		out.WarningT("minikube skips various validations when --force is supplied; this may lead to unexpected behavior")
 	}
 
+	if viper.GetBool(detectProxy) {
+		detectSystemProxy()
+	}
+
 	// if --registry-mirror specified when run minikube start,
 	// take arg precedence over MINIKUBE_REGISTRY_MIRROR
 	// actually this is a hack, because viper 1.0.0 can assign env to variable if StringSliceVar
@@ -184,6 +195,11 @@ func runStart(cmd *cobra.Command, args []string) {
 		validateProfileName()
 	}
 
+	if viper.GetString(config.BundleFlag) != "" {
+		applyBundle(cmd)
+	}
+
+	useDockerHost(cmd, existing)
 	validateSpecifiedDriver(existing)
 	validateKubernetesVersion(existing)
 
@@ -204,6 +220,13 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// dry runs exit before provisioning, so do not run hooks for them
+	if !viper.GetBool(dryRun) {
+		if err := hooks.Run(hooks.Payload{Event: hooks.PreStart, Profile: ClusterFlagValue()}); err != nil {
+			exit.Error(reason.HostHook, "pre-start hook failed", err)
+		}
+	}
+
 	starter, err := provisionWithDriver(cmd, ds, existing)
 	if err != nil {
 		node.ExitIfFatal(err)
@@ -246,6 +269,8 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	traceAttributes(starter.Cfg)
+
 	if existing != nil && driver.IsKIC(existing.Driver) {
 		if viper.GetBool(createMount) {
 			old := ""
@@ -268,9 +293,38 @@ func runStart(cmd *cobra.Command, args []string) {
 		exit.Error(reason.GuestStart, "failed to start node", err)
 	}
 
+	syncSharedNetwork(starter.Cfg)
+	startMountManager(starter.Cfg)
+	restartDNS(starter.Cfg)
+	restartLifecycle(starter.Cfg)
+
 	if err := showKubectlInfo(kubeconfig, starter.Node.KubernetesVersion, starter.Cfg.Name); err != nil {
 		klog.Errorf("kubectl info: %v", err)
 	}
+
+	hooks.Notify(hooks.Payload{Event: hooks.PostStart, Profile: starter.Cfg.Name})
+}
+
+// traceAttributes describes the cluster being started in its trace, so that start times can be compared by configuration
+func traceAttributes(cc *config.ClusterConfig) {
+	pkgtrace.SetAttribute("minikube.version", version.GetVersion())
+	pkgtrace.SetAttribute("minikube.profile", cc.Name)
+	pkgtrace.SetAttribute("minikube.driver", cc.Driver)
+	pkgtrace.SetAttribute("minikube.container_runtime", cc.KubernetesConfig.ContainerRuntime)
+	pkgtrace.SetAttribute("minikube.kubernetes_version", cc.KubernetesConfig.KubernetesVersion)
+	pkgtrace.SetAttribute("minikube.nodes", strconv.Itoa(len(cc.Nodes)))
+	pkgtrace.SetAttribute("minikube.host_os", runtime.GOOS+"/"+runtime.GOARCH)
+}
+
+// syncSharedNetwork routes between the cluster and the other clusters running on its shared network, if it is on one
+func syncSharedNetwork(cc *config.ClusterConfig) {
+	sn, err := sharednet.Load(cc.Network)
+	if err != nil || sn == nil {
+		return
+	}
+	if err := sharednet.SyncRoutes(sn.Name); err != nil {
+		out.WarningT("Unable to route to the other clusters on {{.network}}: {{.error}}", out.V{"network": sn.Name, "error": err})
+	}
 }
 
 func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *config.ClusterConfig) (node.Starter, error) {
@@ -298,9 +352,11 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 	if err != nil {
 		return node.Starter{}, errors.Wrap(err, "Failed to generate config")
 	}
+	validateIPFamily(cc)
 
 	// This is about as far as we can go without overwriting config files
 	if viper.GetBool(dryRun) {
+		printStartPlan(newStartPlan(cc, n, existing))
 		out.Step(style.DryRun, `dry-run validation complete!`)
 		os.Exit(0)
 	}
@@ -327,12 +383,20 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 		ssh.SetDefaultClient(ssh.External)
 	}
 
+	startSSHForward(&cc)
+
 	mRunner, preExists, mAPI, host, err := node.Provision(&cc, &n, true, viper.GetBool(deleteOnFailure))
 	if err != nil {
 		return node.Starter{}, err
 	}
 
+	var images []string
+	if startBundle != nil {
+		images = startBundle.Images
+	}
+
 	return node.Starter{
+		Images:         images,
 		Runner:         mRunner,
 		PreExists:      preExists,
 		MachineAPI:     mAPI,
@@ -352,7 +416,7 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 		}
 	}
 
-	numNodes := viper.GetInt(nodes)
+	numNodes := requestedNodes()
 	if existing != nil {
 		if numNodes > 1 {
 			// We ignore the --nodes parameter if we're restarting an existing cluster
@@ -370,7 +434,7 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 					n := config.Node{
 						Name:              nodeName,
 						Worker:            true,
-						ControlPlane:      false,
+						ControlPlane:      starter.Cfg.HA && i < haControlPlanes,
 						KubernetesVersion: starter.Cfg.KubernetesConfig.KubernetesVersion,
 					}
 					out.Ln("") // extra newline for clarity on the command line
@@ -381,7 +445,7 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 				}
 			} else {
 				for _, n := range existing.Nodes {
-					if !n.ControlPlane {
+					if !config.IsPrimaryControlPlane(*existing, n) {
 						err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
 						if err != nil {
 							return nil, errors.Wrap(err, "adding node")
@@ -395,10 +459,48 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 	return kubeconfig, nil
 }
 
+// haControlPlanes is the number of control planes of an HA cluster, the smallest one tolerating a failure
+const haControlPlanes = 3
+
+// requestedNodes returns the number of nodes to create, accounting for the control planes of HA clusters
+func requestedNodes() int {
+	n := viper.GetInt(nodes)
+	if viper.GetBool(ha) && n < haControlPlanes {
+		return haControlPlanes
+	}
+	return n
+}
+
 func warnAboutMultiNodeCNI() {
 	out.WarningT("Cluster was created without any CNI, adding a node to it might cause broken networking.")
 }
 
+// applyBundle extracts an offline bundle into the cache, and defaults the start flags to the ones it was created for
+func applyBundle(cmd *cobra.Command) {
+	path := viper.GetString(config.BundleFlag)
+	out.Step(style.Caching, "Extracting offline bundle {{.path}} ...", out.V{"path": path})
+	m, err := bundle.Extract(path)
+	if err != nil {
+		exit.Error(reason.HostBundleExtract, "Failed to extract the bundle", err)
+	}
+
+	for flag, value := range map[string]string{kubernetesVersion: m.KubernetesVersion, "driver": m.Driver, containerRuntime: m.ContainerRuntime} {
+		if !cmd.Flags().Changed(flag) {
+			viper.Set(flag, value)
+			continue
+		}
+		if viper.GetString(flag) != value {
+			out.WarningT("The bundle was created for --{{.flag}}={{.bundled}}, starting with {{.value}} requires network access", out.V{"flag": flag, "bundled": value, "value": viper.GetString(flag)})
+		}
+	}
+	// there is nothing to download, neither a newer driver nor a remote preload
+	viper.Set(autoUpdate, false)
+	if !m.Preload {
+		viper.Set(preload, false)
+	}
+	startBundle = m
+}
+
 func updateDriver(driverName string) {
 	v, err := version.GetSemverVersion()
 	if err != nil {
@@ -430,6 +532,27 @@ func displayEnviron(env []string) {
 	}
 }
 
+// detectSystemProxy sets the proxy environment from the proxy settings of the host, if none is set
+func detectSystemProxy() {
+	s, err := proxy.Detect()
+	if err != nil {
+		klog.Warningf("unable to detect the proxy settings of the host: %v", err)
+		return
+	}
+	if s == nil {
+		return
+	}
+	p := s.HTTPS
+	if p == "" {
+		p = s.HTTP
+	}
+	if p == "" {
+		out.WarningT("The host uses the proxy auto-config file {{.pac}}, which minikube can not evaluate. If the cluster needs a proxy, set HTTP_PROXY and HTTPS_PROXY", out.V{"pac": s.PAC})
+		return
+	}
+	out.Step(style.Internet, "Using the proxy of the host: {{.proxy}}", out.V{"proxy": p})
+}
+
 func showKubectlInfo(kcs *kubeconfig.Settings, k8sVersion string, machineName string) error {
 	// To be shown at the end, regardless of exit path
 	defer func() {
@@ -1012,7 +1135,7 @@ func validateRequestedMemorySize(req int, drvName string) {
 		}
 	}
 
-	advised := suggestMemoryAllocation(sysLimit, containerLimit, viper.GetInt(nodes))
+	advised := suggestMemoryAllocation(sysLimit, containerLimit, requestedNodes())
 	if req > sysLimit {
 		exitIfNotForced(reason.Kind{ID: "RSRC_OVER_ALLOC_MEM", Advice: "Start minikube with less memory allocated: 'minikube start --memory={{.advised}}mb'"},
 			`Requested memory allocation {{.requested}}MB is more than your system limit {{.system_limit}}MB.`,
@@ -1151,6 +1274,8 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 		validateCNI(cmd, runtime)
 	}
 
+	validateCNIVersion(cmd)
+
 	if driver.BareMetal(drvName) {
 		if ClusterFlagValue() != constants.DefaultClusterName {
 			exit.Message(reason.DrvUnsupportedProfile, "The '{{.name}} driver does not support multiple profiles: https://minikube.sigs.k8s.io/docs/reference/drivers/none/", out.V{"name": drvName})
@@ -1170,6 +1295,27 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 		}
 	}
 
+	// The virtual IP floats between nodes, so it needs an address their network never leases:
+	// only the private networks kvm2 creates keep one out of their DHCP range
+	if viper.GetBool(ha) && !driver.IsKVM(drvName) {
+		exit.Message(reason.DrvUnsupportedMulti, "The '{{.driver}}' driver does not support HA clusters", out.V{"driver": drvName})
+	}
+	if viper.GetBool(ha) && viper.GetString(network) != "" {
+		exit.Message(reason.Usage, "HA clusters reserve an address of their own private network, they can not be started with --network")
+	}
+
+	if cmd.Flags().Changed(ipFamily) {
+		viper.Set(ipFamily, strings.ToLower(viper.GetString(ipFamily)))
+	}
+
+	if r := viper.GetString(loadBalancerIPRange); r != "" {
+		validateLoadBalancerIPRange(r)
+	}
+
+	if cmd.Flags().Changed(gpus) {
+		validateGPUs(drvName, viper.GetString(containerRuntime))
+	}
+
 	if driver.IsSSH(drvName) {
 		sshIPAddress := viper.GetString(sshIPAddress)
 		if sshIPAddress == "" {
@@ -1205,14 +1351,116 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 		}
 	}
 
-	if outputFormat != "text" && outputFormat != "json" {
-		exit.Message(reason.Usage, "Sorry, please set the --output flag to one of the following valid options: [text,json]")
-	}
-
 	validateRegistryMirror()
 	validateInsecureRegistry()
 }
 
+// validateLoadBalancerIPRange validates the --loadbalancer-ip-range flag, which must not overlap the cluster networks
+func validateLoadBalancerIPRange(r string) {
+	_, pool, err := net.ParseCIDR(r)
+	if err != nil || pool.IP.To4() == nil {
+		exit.Message(reason.Usage, "Sorry, the --loadbalancer-ip-range flag must be an IPv4 CIDR such as 10.112.0.0/24, got: {{.range}}", out.V{"range": r})
+	}
+	for _, cidr := range []string{getServiceCIDR(), cni.DefaultPodCIDR} {
+		for _, c := range util.SplitCIDRs(cidr) {
+			_, n, err := net.ParseCIDR(c)
+			if err == nil && (n.Contains(pool.IP) || pool.Contains(n.IP)) {
+				exit.Message(reason.Usage, "The --loadbalancer-ip-range {{.range}} overlaps with the cluster network {{.cidr}}", out.V{"range": r, "cidr": c})
+			}
+		}
+	}
+}
+
+// validateGPUs validates the --gpus flag and that the host is able to pass the GPUs through
+func validateGPUs(drvName, rtime string) {
+	g := getGPUs()
+	switch g {
+	case "":
+		return
+	case constants.GPUNvidia, constants.GPUAMD:
+	default:
+		exit.Message(reason.Usage, "Sorry, please set the --gpus flag to one of the following valid options: [all,nvidia,amd]")
+	}
+
+	if !driver.IsKIC(drvName) {
+		exit.Message(reason.Usage, "The --gpus flag is only supported by the docker and podman drivers, got: {{.driver}}", out.V{"driver": drvName})
+	}
+	if runtime.GOOS != "linux" {
+		exit.Message(reason.Usage, "The --gpus flag is only supported on Linux hosts")
+	}
+
+	if g == constants.GPUAMD {
+		if _, err := os.Stat("/dev/kfd"); err != nil {
+			exit.Message(reason.Usage, "--gpus=amd requires the AMD ROCm kernel driver, but /dev/kfd was not found on the host")
+		}
+		return
+	}
+
+	if rtime != "docker" {
+		exit.Message(reason.Usage, "--gpus={{.gpus}} requires --container-runtime=docker", out.V{"gpus": viper.GetString(gpus)})
+	}
+	if _, err := exec.LookPath("nvidia-smi"); err != nil {
+		exit.Message(reason.Usage, "--gpus={{.gpus}} requires the NVIDIA driver, but nvidia-smi was not found in your path", out.V{"gpus": viper.GetString(gpus)})
+	}
+	if driver.IsDocker(drvName) {
+		if _, err := exec.LookPath("nvidia-container-cli"); err != nil {
+			exit.Message(reason.Usage, "--gpus={{.gpus}} requires the NVIDIA Container Toolkit to be installed and configured for Docker, see https://docs.nvidia.com/datacenter/cloud-native/container-toolkit/install-guide.html", out.V{"gpus": viper.GetString(gpus)})
+		}
+		return
+	}
+	for _, spec := range []string{"/etc/cdi/nvidia.yaml", "/var/run/cdi/nvidia.yaml"} {
+		if _, err := os.Stat(spec); err == nil {
+			return
+		}
+	}
+	exit.Message(reason.Usage, "--gpus={{.gpus}} requires a CDI specification for the NVIDIA GPUs, generate one with 'sudo nvidia-ctk cdi generate --output=/etc/cdi/nvidia.yaml'", out.V{"gpus": viper.GetString(gpus)})
+}
+
+// validateIPFamily validates the IP family of the cluster against the rest of its configuration, which may have
+// been saved by an earlier start
+func validateIPFamily(cc config.ClusterConfig) {
+	family := cc.KubernetesConfig.IPFamily
+	switch family {
+	case "", constants.IPFamilyIPv4:
+		return
+	case constants.IPFamilyIPv6, constants.IPFamilyDual:
+	default:
+		exit.Message(reason.Usage, "Sorry, please set the --ip-family flag to one of the following valid options: [ipv4,ipv6,dual]")
+	}
+
+	if !driver.IsKIC(cc.Driver) {
+		exit.Message(reason.Usage, "The '{{.driver}}' driver does not support --ip-family={{.family}}, use the docker or podman driver", out.V{"driver": cc.Driver, "family": family})
+	}
+	if cc.HA {
+		exit.Message(reason.Usage, "HA clusters do not support --ip-family={{.family}}", out.V{"family": family})
+	}
+	if chosen := cc.KubernetesConfig.CNI; chosen != "" && chosen != "auto" && chosen != "kindnet" {
+		exit.Message(reason.Usage, "The {{.cni}} CNI does not support --ip-family={{.family}}, use --cni=kindnet", out.V{"cni": chosen, "family": family})
+	}
+
+	// IPv6DualStack is enabled by default since Kubernetes 1.21
+	if family == constants.IPFamilyDual {
+		version, err := util.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion)
+		if err == nil && version.LT(semver.MustParse("1.21.0-alpha.0")) {
+			exit.Message(reason.Usage, "Dual-stack clusters require Kubernetes v1.21.0 or newer, got {{.version}}", out.V{"version": version.String()})
+		}
+	}
+}
+
+// validateCNIVersion checks the release of the CNI chosen with --cni=<name>@<version> is known
+func validateCNIVersion(cmd *cobra.Command) {
+	if !cmd.Flags().Changed(cniFlag) {
+		return
+	}
+	name, version := cni.ParseCNI(viper.GetString(cniFlag))
+	if version == "" {
+		return
+	}
+	if _, err := cni.ResolveVersion(name, version, ""); err != nil {
+		exit.Message(reason.Usage, "Invalid --cni: {{.error}}", out.V{"error": err})
+	}
+}
+
 // if container runtime is not docker, check that cni is not disabled
 func validateCNI(cmd *cobra.Command, runtime string) {
 	if runtime == "docker" {
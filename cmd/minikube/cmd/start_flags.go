@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/cni"
@@ -39,7 +42,9 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/sharednet"
 	"k8s.io/minikube/pkg/minikube/style"
+	pkgtrace "k8s.io/minikube/pkg/trace"
 	pkgutil "k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
 )
@@ -58,6 +63,7 @@ const (
 	networkPlugin           = "network-plugin"
 	enableDefaultCNI        = "enable-default-cni"
 	cniFlag                 = "cni"
+	cniValues               = "cni-values"
 	hypervVirtualSwitch     = "hyperv-virtual-switch"
 	hypervUseExternalSwitch = "hyperv-use-external-switch"
 	hypervExternalAdapter   = "hyperv-external-adapter"
@@ -103,14 +109,19 @@ const (
 	hostOnlyNicType         = "host-only-nic-type"
 	natNicType              = "nat-nic-type"
 	nodes                   = "nodes"
+	ha                      = "ha"
 	preload                 = "preload"
 	deleteOnFailure         = "delete-on-failure"
 	forceSystemd            = "force-systemd"
 	kicBaseImage            = "base-image"
 	ports                   = "ports"
 	network                 = "network"
+	ipFamily                = "ip-family"
+	loadBalancerIPRange     = "loadbalancer-ip-range"
+	gpus                    = "gpus"
 	startNamespace          = "namespace"
 	trace                   = "trace"
+	traceEndpoint           = "trace-endpoint"
 	sshIPAddress            = "ssh-ip-address"
 	sshSSHUser              = "ssh-user"
 	sshSSHKey               = "ssh-key"
@@ -118,10 +129,8 @@ const (
 	defaultSSHUser          = "root"
 	defaultSSHPort          = 22
 	listenAddress           = "listen-address"
-)
-
-var (
-	outputFormat string
+	dockerHost              = "docker-host"
+	detectProxy             = "detect-proxy"
 )
 
 // initMinikubeFlags includes commandline flags for minikube.
@@ -133,7 +142,8 @@ func initMinikubeFlags() {
 	viper.AutomaticEnv()
 	startCmd.Flags().Bool(force, false, "Force minikube to perform possibly dangerous operations")
 	startCmd.Flags().Bool(interactive, true, "Allow user prompts for more information")
-	startCmd.Flags().Bool(dryRun, false, "dry-run mode. Validates configuration, but does not mutate system state")
+	startCmd.Flags().Bool(detectProxy, false, "Use the proxy settings of the host on macOS and Windows when no HTTP_PROXY or HTTPS_PROXY environment variable is set. Proxy auto-config files are not evaluated")
+	startCmd.Flags().Bool(dryRun, false, "dry-run mode. Validates configuration and prints what start would do - driver, versions, downloads, addons, resources and network - but does not mutate system state")
 
 	startCmd.Flags().Int(cpus, 2, "Number of CPUs allocated to Kubernetes.")
 	startCmd.Flags().String(memory, "", "Amount of RAM to allocate to Kubernetes (format: <number>[<unit>], where unit = b, k, m or g).")
@@ -151,19 +161,22 @@ func initMinikubeFlags() {
 	startCmd.Flags().String(criSocket, "", "The cri socket path to be used.")
 	startCmd.Flags().String(networkPlugin, "", "Kubelet network plug-in to use (default: auto)")
 	startCmd.Flags().Bool(enableDefaultCNI, false, "DEPRECATED: Replaced by --cni=bridge")
-	startCmd.Flags().String(cniFlag, "", "CNI plug-in to use. Valid options: auto, bridge, calico, cilium, flannel, kindnet, or path to a CNI manifest (default: auto)")
-	startCmd.Flags().StringSlice(waitComponents, kverify.DefaultWaitList, fmt.Sprintf("comma separated list of Kubernetes components to verify and wait for after starting a cluster. defaults to %q, available options: %q . other acceptable values are 'all' or 'none', 'true' and 'false'", strings.Join(kverify.DefaultWaitList, ","), strings.Join(kverify.AllComponentsList, ",")))
-	startCmd.Flags().Duration(waitTimeout, 6*time.Minute, "max time to wait per Kubernetes or host to be healthy.")
+	startCmd.Flags().String(cniFlag, "", "CNI plug-in to use. Valid options: auto, bridge, calico, cilium, flannel, kindnet, or path to a CNI manifest (default: auto). Append @<version> to choose the release of calico, cilium, flannel or kindnet, such as calico@3.14")
+	startCmd.Flags().String(cniValues, "", "Path to a YAML file merged into the manifest of the calico, cilium, flannel or kindnet CNI, to customize it")
+	startCmd.Flags().StringSlice(waitComponents, kverify.DefaultWaitList, fmt.Sprintf("comma separated list of Kubernetes components to verify and wait for after starting a cluster, each optionally with its own timeout, e.g. apiserver:90s,node_ready:2m. defaults to %q, available options: %q . other acceptable values are 'all' or 'none', 'true' and 'false'", strings.Join(kverify.DefaultWaitList, ","), strings.Join(kverify.AllComponentsList, ",")))
+	startCmd.Flags().Duration(waitTimeout, 6*time.Minute, "max time to wait per Kubernetes or host to be healthy, for components not given their own timeout with --wait.")
 	startCmd.Flags().Bool(nativeSSH, true, "Use native Golang SSH client (default true). Set to 'false' to use the command line 'ssh' command when accessing the docker machine. Useful for the machine drivers when they will not start with 'Waiting for SSH'.")
 	startCmd.Flags().Bool(autoUpdate, true, "If set, automatically updates drivers to the latest version. Defaults to true.")
 	startCmd.Flags().Bool(installAddons, true, "If set, install addons. Defaults to true.")
 	startCmd.Flags().IntP(nodes, "n", 1, "The number of nodes to spin up. Defaults to 1.")
+	startCmd.Flags().Bool(ha, false, "Create a highly available cluster with 3 control plane nodes behind a virtual IP. Additional --nodes are added as workers.")
 	startCmd.Flags().Bool(preload, true, "If set, download tarball of preloaded images if available to improve start time. Defaults to true.")
+	startCmd.Flags().String(config.BundleFlag, "", "Path to an offline bundle created by 'minikube bundle create', to start the cluster without network access")
 	startCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 	startCmd.Flags().Bool(forceSystemd, false, "If set, force the container runtime to use systemd as cgroup manager. Defaults to false.")
-	startCmd.Flags().StringP(network, "", "", "network to run minikube with. Now it is used by docker/podman and KVM drivers. If left empty, minikube will create a new network.")
-	startCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
-	startCmd.Flags().StringP(trace, "", "", "Send trace events. Options include: [gcp]")
+	startCmd.Flags().StringP(network, "", "", "network to run minikube with. Now it is used by docker/podman and KVM drivers, and is the host bridge of the microvm driver. If left empty, minikube will create a new network, or use the virbr0 bridge for microvm. Clusters on a network created with 'minikube network create' can reach each other.")
+	startCmd.Flags().StringP(trace, "", "", "Send trace events. Options include: [gcp, otlp]")
+	startCmd.Flags().String(traceEndpoint, "", "The OTLP/gRPC endpoint to send trace events to with --trace=otlp, prefixed with https:// to use TLS. Defaults to "+pkgtrace.DefaultOTLPEndpoint)
 }
 
 // initKubernetesFlags inits the commandline flags for Kubernetes related options
@@ -189,6 +202,7 @@ func initDriverFlags() {
 	startCmd.Flags().String("vm-driver", "", "DEPRECATED, use `driver` instead.")
 	startCmd.Flags().Bool(disableDriverMounts, false, "Disables the filesystem mounts provided by the hypervisors")
 	startCmd.Flags().Bool("vm", false, "Filter to use only VM Drivers")
+	startCmd.Flags().String(gpus, "", "Allow pods to use your GPUs. Options include: [all,nvidia,amd] (docker and podman driver on Linux only)")
 
 	// kvm2
 	startCmd.Flags().String(kvmNetwork, "default", "The KVM default network name. (kvm2 driver only)")
@@ -220,6 +234,7 @@ func initDriverFlags() {
 	// docker & podman
 	startCmd.Flags().String(listenAddress, "", "IP Address to use to expose ports (docker and podman driver only)")
 	startCmd.Flags().StringSlice(ports, []string{}, "List of ports that should be exposed (docker and podman driver only)")
+	startCmd.Flags().String(dockerHost, "", "The remote docker or podman daemon to create the cluster on, as ssh://user@host or tcp://host:port. Over ssh, minikube forwards the ports of the cluster to this host (docker and podman driver only)")
 }
 
 // initNetworkingFlags inits the commandline flags for connectivity related flags for start
@@ -229,6 +244,8 @@ func initNetworkingFlags() {
 	startCmd.Flags().String(imageRepository, "", "Alternative image repository to pull docker images from. This can be used when you have limited access to gcr.io. Set it to \"auto\" to let minikube decide one for you. For Chinese mainland users, you may use local gcr.io mirrors such as registry.cn-hangzhou.aliyuncs.com/google_containers")
 	startCmd.Flags().String(imageMirrorCountry, "", "Country code of the image mirror to be used. Leave empty to use the global one. For Chinese mainland users, set it to cn.")
 	startCmd.Flags().String(serviceCIDR, constants.DefaultServiceCIDR, "The CIDR to be used for service cluster IPs.")
+	startCmd.Flags().String(loadBalancerIPRange, "", "CIDR 'minikube tunnel' allocates the external IPs of LoadBalancer services from, for example 10.112.0.0/24. If empty, their ClusterIP is used.")
+	startCmd.Flags().String(ipFamily, constants.IPFamilyIPv4, "IP family of the cluster: ipv4, ipv6 or dual (dual-stack). ipv6 and dual are only supported by the docker and podman drivers.")
 	startCmd.Flags().StringArrayVar(&config.DockerEnv, "docker-env", nil, "Environment variables to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().StringArrayVar(&config.DockerOpt, "docker-opt", nil, "Specify arbitrary flags to pass to the Docker daemon. (format: key=value)")
 
@@ -270,6 +287,13 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 		}
 	}
 
+	// record the release of the CNI applied, so that the cluster keeps it across minikube upgrades
+	v, err := cni.ResolveVersion(cc.KubernetesConfig.CNI, cc.KubernetesConfig.CNIVersion, cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return cc, config.Node{}, errors.Wrap(err, "cni version")
+	}
+	cc.KubernetesConfig.CNIVersion = v
+
 	klog.Infof("config:\n%+v", cc)
 
 	r, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime})
@@ -277,6 +301,15 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 		return cc, config.Node{}, errors.Wrap(err, "new runtime manager")
 	}
 
+	// Keep the traffic to pods and services away from the proxy
+	if proxy.IsSet() {
+		for _, cidr := range append(pkgutil.SplitCIDRs(cc.KubernetesConfig.ServiceCIDR), pkgutil.SplitCIDRs(cni.PodCIDR(cc))...) {
+			if err := proxy.ExcludeIP(cidr); err != nil {
+				klog.Warningf("unable to add %s to NO_PROXY: %v", cidr, err)
+			}
+		}
+	}
+
 	// Feed Docker our host proxy environment by default, so that it can pull images
 	// doing this for both new config and existing, in case proxy changed since previous start
 	if _, ok := r.(*cruntime.Docker); ok {
@@ -296,7 +329,7 @@ func getMemorySize(cmd *cobra.Command, drvName string) int {
 		klog.Warningf("Unable to query memory limits: %+v", err)
 	}
 
-	mem := suggestMemoryAllocation(sysLimit, containerLimit, viper.GetInt(nodes))
+	mem := suggestMemoryAllocation(sysLimit, containerLimit, requestedNodes())
 	if cmd.Flags().Changed(memory) || viper.IsSet(memory) {
 		var err error
 		mem, err = pkgutil.CalculateSizeInMB(viper.GetString(memory))
@@ -360,6 +393,47 @@ func getCNIConfig(cmd *cobra.Command) string {
 	return chosenCNI
 }
 
+// getCNIValues returns the absolute path to the file passed with --cni-values, so that it is found from any directory
+func getCNIValues() string {
+	values := viper.GetString(cniValues)
+	if values == "" {
+		return ""
+	}
+	p, err := filepath.Abs(values)
+	if err != nil {
+		exit.Error(reason.HostPathStat, "Unable to get the absolute path of the CNI values", err)
+	}
+	if _, err := os.Stat(p); err != nil {
+		exit.Message(reason.HostPathMissing, "Cannot find the CNI values {{.path}}: {{.error}}", out.V{"path": p, "error": err})
+	}
+	return p
+}
+
+// getServiceCIDR returns the service CIDR, defaulting to the one of the requested IP family
+func getServiceCIDR() string {
+	cidr := viper.GetString(serviceCIDR)
+	if cidr != constants.DefaultServiceCIDR {
+		return cidr
+	}
+	switch viper.GetString(ipFamily) {
+	case constants.IPFamilyIPv6:
+		return constants.DefaultServiceCIDRv6
+	case constants.IPFamilyDual:
+		return constants.DefaultServiceCIDR + "," + constants.DefaultServiceCIDRv6
+	default:
+		return cidr
+	}
+}
+
+// getGPUs returns the GPU vendor requested with --gpus, "all" currently means NVIDIA
+func getGPUs() string {
+	g := strings.ToLower(viper.GetString(gpus))
+	if g == "all" {
+		return constants.GPUNvidia
+	}
+	return g
+}
+
 // generateNewConfigFromFlags generate a config.ClusterConfig based on flags
 func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName string) config.ClusterConfig {
 	var cc config.ClusterConfig
@@ -388,6 +462,7 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName s
 		DiskSize:                getDiskSize(),
 		Driver:                  drvName,
 		ListenAddress:           viper.GetString(listenAddress),
+		DockerHost:              viper.GetString(dockerHost),
 		HyperkitVpnKitSock:      viper.GetString(vpnkitSock),
 		HyperkitVSockPorts:      viper.GetStringSlice(vsockPorts),
 		NFSShare:                viper.GetStringSlice(nfsShare),
@@ -405,6 +480,7 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName s
 		KVMGPU:                  viper.GetBool(kvmGPU),
 		KVMHidden:               viper.GetBool(kvmHidden),
 		KVMNUMACount:            viper.GetInt(kvmNUMACount),
+		GPUs:                    getGPUs(),
 		DisableDriverMounts:     viper.GetBool(disableDriverMounts),
 		UUID:                    viper.GetString(uuid),
 		NoVTXCheck:              viper.GetBool(noVTXCheck),
@@ -430,23 +506,93 @@ func generateNewConfigFromFlags(cmd *cobra.Command, k8sVersion string, drvName s
 			ContainerRuntime:       viper.GetString(containerRuntime),
 			CRISocket:              viper.GetString(criSocket),
 			NetworkPlugin:          chosenNetworkPlugin,
-			ServiceCIDR:            viper.GetString(serviceCIDR),
+			ServiceCIDR:            getServiceCIDR(),
+			IPFamily:               viper.GetString(ipFamily),
+			LoadBalancerIPRange:    viper.GetString(loadBalancerIPRange),
 			ImageRepository:        getRepository(cmd, k8sVersion),
 			ExtraOptions:           config.ExtraOptions,
 			ShouldLoadCachedImages: viper.GetBool(cacheImages),
-			CNI:                    getCNIConfig(cmd),
+			CNIValues:              getCNIValues(),
 			NodePort:               viper.GetInt(apiServerPort),
 		},
-		MultiNodeRequested: viper.GetInt(nodes) > 1,
+		MultiNodeRequested: requestedNodes() > 1,
+		HA:                 viper.GetBool(ha),
 	}
-	cc.VerifyComponents = interpretWaitFlag(*cmd)
+	cc.KubernetesConfig.CNI, cc.KubernetesConfig.CNIVersion = cni.ParseCNI(getCNIConfig(cmd))
+	cc.VerifyComponents, cc.WaitTimeouts = interpretWaitFlag(*cmd)
 	if viper.GetBool(createMount) && driver.IsKIC(drvName) {
 		cc.ContainerVolumeMounts = []string{viper.GetString(mountString)}
 	}
 
+	if drvName == driver.Podman && oci.IsRootlessForced() {
+		applyRootless(cmd, &cc)
+	}
+
+	sn, err := sharednet.Load(cc.Network)
+	if err != nil {
+		exit.Error(reason.HostSharedNetwork, "Failed to load shared network", err)
+	}
+	if sn != nil {
+		applySharedNetwork(cmd, &cc, sn)
+	}
+
 	return cc
 }
 
+// applySharedNetwork gives a cluster joining a shared network pod and service CIDRs which do not overlap those of
+// the other clusters on it
+func applySharedNetwork(cmd *cobra.Command, cc *config.ClusterConfig, sn *sharednet.Network) {
+	if sn.Driver != cc.Driver {
+		exit.Message(reason.Usage, "The shared network {{.network}} is for the {{.network_driver}} driver, it can not be used with the {{.driver}} driver", out.V{"network": sn.Name, "network_driver": sn.Driver, "driver": cc.Driver})
+	}
+	if cc.KubernetesConfig.IPFamily != "" && cc.KubernetesConfig.IPFamily != constants.IPFamilyIPv4 {
+		exit.Message(reason.Usage, "Shared networks only support IPv4 clusters")
+	}
+
+	pod, svc, err := sharednet.AllocateCIDRs(sn.Name, cc.Name)
+	if err != nil {
+		exit.Error(reason.HostSharedNetwork, "Failed to allocate CIDRs on the shared network", err)
+	}
+	cc.KubernetesConfig.PodCIDR = pod
+	if cmd.Flags().Changed(serviceCIDR) {
+		out.WarningT("Keeping --service-cluster-ip-range={{.cidr}}, it must not overlap the CIDRs of the other clusters on {{.network}}", out.V{"cidr": cc.KubernetesConfig.ServiceCIDR, "network": sn.Name})
+	} else {
+		cc.KubernetesConfig.ServiceCIDR = svc
+	}
+	if m, err := cni.New(cc); err == nil && m.CIDR() != pod {
+		out.WarningT("{{.cni}} does not support the pod CIDR of the shared network, so pods may not reach the other clusters: use --cni=kindnet instead", out.V{"cni": m.String()})
+	}
+	out.Step(style.Connectivity, "Joining shared network {{.network}} with pod CIDR {{.pod}} and service CIDR {{.service}}", out.V{"network": sn.Name, "pod": pod, "service": cc.KubernetesConfig.ServiceCIDR})
+}
+
+// applyRootless adjusts the config of a cluster running in rootless podman
+func applyRootless(cmd *cobra.Command, cc *config.ClusterConfig) {
+	out.Styled(style.Notice, "Using rootless {{.driver_name}} driver", out.V{"driver_name": cc.Driver})
+
+	// dockerd can't run in the user namespace of a rootless container
+	if cc.KubernetesConfig.ContainerRuntime == constants.DefaultContainerRuntime {
+		if cmd.Flags().Changed(containerRuntime) {
+			exit.Message(reason.Usage, "The docker container runtime is not supported in rootless mode, use containerd or cri-o instead")
+		}
+		cc.KubernetesConfig.ContainerRuntime = "containerd"
+		klog.Infof("rootless mode: set %s to %q", containerRuntime, cc.KubernetesConfig.ContainerRuntime)
+	}
+
+	// the kubelet needs to ignore the sysctls and rlimits a user namespace does not allow setting
+	v, err := pkgutil.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion)
+	if err == nil && v.LT(semver.Version{Major: 1, Minor: 22}) {
+		out.WarningT("Kubernetes {{.version}} does not support running in a user namespace, rootless mode requires Kubernetes v1.22 or later", out.V{"version": cc.KubernetesConfig.KubernetesVersion})
+		return
+	}
+	if !strings.Contains(cc.KubernetesConfig.FeatureGates, "KubeletInUserNamespace") {
+		gates := []string{"KubeletInUserNamespace=true"}
+		if cc.KubernetesConfig.FeatureGates != "" {
+			gates = append([]string{cc.KubernetesConfig.FeatureGates}, gates...)
+		}
+		cc.KubernetesConfig.FeatureGates = strings.Join(gates, ",")
+	}
+}
+
 func checkNumaCount(k8sVersion string) {
 	if viper.GetInt(kvmNUMACount) < 1 || viper.GetInt(kvmNUMACount) > 8 {
 		exit.Message(reason.Usage, "--kvm-numa-count range is 1-8")
@@ -529,6 +675,18 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		out.WarningT("You cannot change the disk size for an existing minikube cluster. Please first delete the cluster.")
 	}
 
+	existingFamily := existing.KubernetesConfig.IPFamily
+	if existingFamily == "" {
+		existingFamily = constants.IPFamilyIPv4
+	}
+	if cmd.Flags().Changed(ipFamily) && viper.GetString(ipFamily) != existingFamily {
+		out.WarningT("You cannot change the IP family for an existing minikube cluster. Please first delete the cluster.")
+	}
+
+	if cmd.Flags().Changed(gpus) && getGPUs() != existing.GPUs {
+		out.WarningT("You cannot change the GPUs for an existing minikube cluster. Please first delete the cluster.")
+	}
+
 	updateStringFromFlag(cmd, &cc.MinikubeISO, isoURL)
 	updateBoolFromFlag(cmd, &cc.KeepContext, keepContext)
 	updateBoolFromFlag(cmd, &cc.EmbedCerts, embedCerts)
@@ -569,6 +727,7 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 	updateStringFromFlag(cmd, &cc.KubernetesConfig.CRISocket, criSocket)
 	updateStringFromFlag(cmd, &cc.KubernetesConfig.NetworkPlugin, networkPlugin)
 	updateStringFromFlag(cmd, &cc.KubernetesConfig.ServiceCIDR, serviceCIDR)
+	updateStringFromFlag(cmd, &cc.KubernetesConfig.LoadBalancerIPRange, loadBalancerIPRange)
 	updateBoolFromFlag(cmd, &cc.KubernetesConfig.ShouldLoadCachedImages, cacheImages)
 	updateIntFromFlag(cmd, &cc.KubernetesConfig.NodePort, apiServerPort)
 
@@ -581,11 +740,15 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 	}
 
 	if cmd.Flags().Changed(cniFlag) || cmd.Flags().Changed(enableDefaultCNI) {
-		cc.KubernetesConfig.CNI = getCNIConfig(cmd)
+		cc.KubernetesConfig.CNI, cc.KubernetesConfig.CNIVersion = cni.ParseCNI(getCNIConfig(cmd))
+	}
+
+	if cmd.Flags().Changed(cniValues) {
+		cc.KubernetesConfig.CNIValues = getCNIValues()
 	}
 
 	if cmd.Flags().Changed(waitComponents) {
-		cc.VerifyComponents = interpretWaitFlag(*cmd)
+		cc.VerifyComponents, cc.WaitTimeouts = interpretWaitFlag(*cmd)
 	}
 
 	// Handle flags and legacy configuration upgrades that do not contain KicBaseImage
@@ -632,46 +795,48 @@ func updateDurationFromFlag(cmd *cobra.Command, v *time.Duration, key string) {
 }
 
 // interpretWaitFlag interprets the wait flag and respects the legacy minikube users
-// returns map of components to wait for
-func interpretWaitFlag(cmd cobra.Command) map[string]bool {
+// returns map of components to wait for, and the timeouts given to some of them as in --wait=apiserver:90s
+func interpretWaitFlag(cmd cobra.Command) (map[string]bool, map[string]time.Duration) {
 	if !cmd.Flags().Changed(waitComponents) {
 		klog.Infof("Wait components to verify : %+v", kverify.DefaultComponents)
-		return kverify.DefaultComponents
+		return kverify.DefaultComponents, nil
 	}
 
 	waitFlags, err := cmd.Flags().GetStringSlice(waitComponents)
 	if err != nil {
 		klog.Warningf("Failed to read --wait from flags: %v.\n Moving on will use the default wait components: %+v", err, kverify.DefaultComponents)
-		return kverify.DefaultComponents
+		return kverify.DefaultComponents, nil
 	}
 
 	if len(waitFlags) == 1 {
 		// respecting legacy flag before minikube 1.9.0, wait flag was boolean
 		if waitFlags[0] == "false" || waitFlags[0] == "none" {
 			klog.Infof("Waiting for no components: %+v", kverify.NoComponents)
-			return kverify.NoComponents
+			return kverify.NoComponents, nil
 		}
 		// respecting legacy flag before minikube 1.9.0, wait flag was boolean
 		if waitFlags[0] == "true" || waitFlags[0] == "all" {
 			klog.Infof("Waiting for all components: %+v", kverify.AllComponents)
-			return kverify.AllComponents
+			return kverify.AllComponents, nil
 		}
 	}
 
-	waitComponents := kverify.NoComponents
+	waitComponents := map[string]bool{}
+	for c := range kverify.NoComponents {
+		waitComponents[c] = false
+	}
+	timeouts := map[string]time.Duration{}
 	for _, wc := range waitFlags {
-		seen := false
-		for _, valid := range kverify.AllComponentsList {
-			if wc == valid {
-				waitComponents[wc] = true
-				seen = true
-				continue
-			}
+		name, timeout, err := kverify.ParseWaitComponent(wc)
+		if err != nil {
+			out.WarningT("Ignoring invalid --wait value {{.value}}: {{.error}}", out.V{"value": wc, "error": err})
+			continue
 		}
-		if !seen {
-			klog.Warningf("The value %q is invalid for --wait flag. valid options are %q", wc, strings.Join(kverify.AllComponentsList, ","))
+		waitComponents[name] = true
+		if timeout > 0 {
+			timeouts[name] = timeout
 		}
 	}
-	klog.Infof("Waiting for components: %+v", waitComponents)
-	return waitComponents
+	klog.Infof("Waiting for components: %+v, with timeouts: %+v", waitComponents, timeouts)
+	return waitComponents, timeouts
 }
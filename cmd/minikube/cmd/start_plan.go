@@ -0,0 +1,204 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/minikube/cni"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// startPlan is what 'minikube start' would do with the given flags, as resolved by --dry-run
+type startPlan struct {
+	Profile           string
+	Existing          bool
+	Driver            string
+	Nodes             int
+	HA                bool
+	KubernetesVersion string
+	ContainerRuntime  string
+	CPUs              int
+	Memory            int
+	DiskSize          int
+	Downloads         []plannedDownload
+	Addons            []string
+	Network           plannedNetwork
+}
+
+// plannedDownload is an artifact start needs, and whether it is already on the host
+type plannedDownload struct {
+	Kind   string
+	Source string
+	Cached bool
+}
+
+// plannedNetwork is the network configuration of the cluster
+type plannedNetwork struct {
+	Name          string `json:",omitempty"`
+	APIServerPort int
+	ServiceCIDR   string
+	PodCIDR       string `json:",omitempty"`
+	CNI           string
+	IPFamily      string
+	ListenAddress string   `json:",omitempty"`
+	ExposedPorts  []string `json:",omitempty"`
+}
+
+// newStartPlan resolves the plan of a start from its generated config, without changing anything
+func newStartPlan(cc config.ClusterConfig, n config.Node, existing *config.ClusterConfig) startPlan {
+	p := startPlan{
+		Profile:           cc.Name,
+		Existing:          existing != nil,
+		Driver:            cc.Driver,
+		Nodes:             len(cc.Nodes),
+		HA:                cc.HA,
+		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
+		CPUs:              cc.CPUs,
+		Memory:            cc.Memory,
+		DiskSize:          cc.DiskSize,
+		Downloads:         plannedDownloads(cc),
+		Network: plannedNetwork{
+			APIServerPort: n.Port,
+			ServiceCIDR:   cc.KubernetesConfig.ServiceCIDR,
+			IPFamily:      cc.KubernetesConfig.IPFamily,
+			ListenAddress: cc.ListenAddress,
+			ExposedPorts:  cc.ExposedPorts,
+		},
+	}
+	// only the primary node is in the config of a new cluster, the others are added once it is up
+	if existing == nil && viper.GetInt(nodes) > p.Nodes {
+		p.Nodes = viper.GetInt(nodes)
+	}
+	if p.Network.IPFamily == "" {
+		p.Network.IPFamily = "ipv4"
+	}
+
+	switch {
+	case driver.IsKIC(cc.Driver):
+		p.Network.Name = cc.Network
+		if p.Network.Name == "" {
+			p.Network.Name = cc.Name
+		}
+	case cc.Driver == driver.KVM2:
+		p.Network.Name = cc.KVMNetwork
+	case cc.Driver == driver.VirtualBox:
+		p.Network.Name = cc.HostOnlyCIDR
+	}
+
+	p.Network.CNI = "disabled"
+	if m, err := cni.New(&cc); err != nil {
+		klog.Warningf("unable to resolve CNI: %v", err)
+	} else {
+		p.Network.CNI = m.String()
+		p.Network.PodCIDR = m.CIDR()
+	}
+
+	if viper.GetBool(installAddons) {
+		// the same selection as start, on a copy as it records the defaults
+		toEnable := map[string]bool{}
+		if existing != nil {
+			for name, enabled := range existing.Addons {
+				toEnable[name] = enabled
+			}
+		}
+		additional := viper.GetStringSlice(config.AddonListFlag)
+		if plugin := addons.GPUDevicePlugin(cc.GPUs); plugin != "" {
+			additional = append(additional, plugin)
+		}
+		p.Addons = addons.ToEnable(&cc, toEnable, additional)
+	}
+	return p
+}
+
+// plannedDownloads returns the ISO or base image, and the preload, start needs for the cluster
+func plannedDownloads(cc config.ClusterConfig) []plannedDownload {
+	var ds []plannedDownload
+	switch {
+	case driver.IsKIC(cc.Driver):
+		img := cc.KicBaseImage
+		cached := download.ImageExistsInCache(img)
+		if cc.Driver == driver.Docker {
+			cached = cached || download.ImageExistsInDaemon(img)
+		}
+		ds = append(ds, plannedDownload{Kind: "base image", Source: img, Cached: cached})
+	case driver.IsVM(cc.Driver) && !driver.IsSSH(cc.Driver):
+		// start uses the first ISO it can get, which is the first one unless it is unreachable
+		urls := viper.GetStringSlice(isoURL)
+		if len(urls) > 0 {
+			local := strings.TrimPrefix(download.LocalISOResource(urls[0]), "file://")
+			_, err := os.Stat(local)
+			ds = append(ds, plannedDownload{Kind: "ISO", Source: urls[0], Cached: err == nil})
+		}
+	}
+
+	k8s := cc.KubernetesConfig
+	if download.PreloadExists(k8s.KubernetesVersion, k8s.ContainerRuntime, cc.Driver) {
+		_, err := os.Stat(download.TarballPath(k8s.KubernetesVersion, k8s.ContainerRuntime))
+		ds = append(ds, plannedDownload{Kind: "preload", Source: download.TarballName(k8s.KubernetesVersion, k8s.ContainerRuntime), Cached: err == nil})
+	}
+	return ds
+}
+
+// printStartPlan prints the plan as JSON with --output=json, else for humans
+func printStartPlan(p startPlan) {
+	if outputFormat == "json" {
+		printJSON(p)
+		return
+	}
+
+	action := "create"
+	if p.Existing {
+		action = "start the existing"
+	}
+	out.Step(style.DryRun, "Plan to {{.action}} cluster {{.profile}}:", out.V{"action": action, "profile": p.Profile})
+	out.Infof("driver: {{.driver}}", out.V{"driver": p.Driver})
+	out.Infof("nodes: {{.nodes}} (ha: {{.ha}})", out.V{"nodes": p.Nodes, "ha": p.HA})
+	out.Infof("kubernetes: {{.version}} on {{.runtime}}", out.V{"version": p.KubernetesVersion, "runtime": p.ContainerRuntime})
+	out.Infof("resources per node: {{.cpus}} CPUs, {{.memory}}MB memory, {{.disk}}MB disk", out.V{"cpus": p.CPUs, "memory": p.Memory, "disk": p.DiskSize})
+	for _, d := range p.Downloads {
+		state := "to download"
+		if d.Cached {
+			state = "cached"
+		}
+		out.Infof("{{.kind}}: {{.source}} ({{.state}})", out.V{"kind": d.Kind, "source": d.Source, "state": state})
+	}
+	if len(p.Addons) > 0 {
+		out.Infof("addons: {{.addons}}", out.V{"addons": strings.Join(p.Addons, ", ")})
+	}
+	if p.Network.Name != "" {
+		out.Infof("network: {{.name}}", out.V{"name": p.Network.Name})
+	}
+	out.Infof("apiserver port: {{.port}}, service CIDR: {{.svc}}, {{.family}}", out.V{"port": p.Network.APIServerPort, "svc": p.Network.ServiceCIDR, "family": p.Network.IPFamily})
+	cniInfo := p.Network.CNI
+	if p.Network.PodCIDR != "" {
+		cniInfo += ", pod CIDR " + p.Network.PodCIDR
+	}
+	out.Infof("CNI: {{.cni}}", out.V{"cni": cniInfo})
+	if p.Network.ListenAddress != "" || len(p.Network.ExposedPorts) > 0 {
+		out.Infof("listen address: {{.address}}, exposed ports: {{.ports}}", out.V{"address": p.Network.ListenAddress, "ports": strings.Join(p.Network.ExposedPorts, ", ")})
+	}
+}
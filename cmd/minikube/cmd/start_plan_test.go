@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestNewStartPlan(t *testing.T) {
+	viper.Set("preload", false)
+	viper.Set(installAddons, false)
+	viper.Set(nodes, 3)
+	defer func() {
+		viper.Set("preload", true)
+		viper.Set(installAddons, true)
+		viper.Set(nodes, 1)
+	}()
+
+	n := config.Node{Name: "", Port: 8443, ControlPlane: true, Worker: true}
+	cc := config.ClusterConfig{
+		Name:         "plan",
+		Driver:       "podman",
+		KicBaseImage: "gcr.io/k8s-minikube/kicbase:test",
+		CPUs:         2,
+		Memory:       2200,
+		DiskSize:     20000,
+		Nodes:        []config.Node{n},
+		KubernetesConfig: config.KubernetesConfig{
+			KubernetesVersion: "v1.22.3",
+			ContainerRuntime:  "cri-o",
+			ServiceCIDR:       "10.96.0.0/12",
+		},
+	}
+
+	p := newStartPlan(cc, n, nil)
+	if p.Existing {
+		t.Errorf("Existing = true for a new cluster")
+	}
+	if p.Nodes != 3 {
+		t.Errorf("Nodes = %d, want the 3 requested", p.Nodes)
+	}
+	if p.Network.Name != "plan" {
+		t.Errorf("Network.Name = %q, want the profile name", p.Network.Name)
+	}
+	if p.Network.IPFamily != "ipv4" {
+		t.Errorf("Network.IPFamily = %q, want ipv4", p.Network.IPFamily)
+	}
+	if p.Network.APIServerPort != 8443 {
+		t.Errorf("Network.APIServerPort = %d, want 8443", p.Network.APIServerPort)
+	}
+	if len(p.Downloads) != 1 || p.Downloads[0].Kind != "base image" || p.Downloads[0].Source != cc.KicBaseImage {
+		t.Errorf("Downloads = %+v, want only the base image", p.Downloads)
+	}
+	if p.Addons != nil {
+		t.Errorf("Addons = %v, want none with --install-addons=false", p.Addons)
+	}
+
+	// an existing cluster keeps its nodes
+	p = newStartPlan(cc, n, &cc)
+	if !p.Existing || p.Nodes != 1 {
+		t.Errorf("Existing, Nodes = %v, %d, want true, 1", p.Existing, p.Nodes)
+	}
+}
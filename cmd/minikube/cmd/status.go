@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -33,7 +34,10 @@ import (
 	"github.com/docker/machine/libmachine/state"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
@@ -56,6 +60,7 @@ var (
 	output       string
 	layout       string
 	watch        time.Duration
+	detailedExit bool
 )
 
 // Additional legacy states
@@ -140,6 +145,8 @@ type Status struct {
 	TimeToStop string `json:",omitempty"`
 	DockerEnv  string `json:",omitempty"`
 	PodManEnv  string `json:",omitempty"`
+	// Addons is the health of the enabled addons, only checked with --watch
+	Addons map[string]string `json:",omitempty"`
 }
 
 // ClusterState holds a cluster state representation
@@ -177,10 +184,13 @@ type BaseState struct {
 }
 
 const (
-	minikubeNotRunningStatusFlag = 1 << 0
-	clusterNotRunningStatusFlag  = 1 << 1
-	k8sNotRunningStatusFlag      = 1 << 2
-	defaultStatusFormat          = `{{.Name}}
+	minikubeNotRunningStatusFlag  = 1 << 0
+	clusterNotRunningStatusFlag   = 1 << 1
+	k8sNotRunningStatusFlag       = 1 << 2
+	nonexistentStatusFlag         = 1 << 3
+	apiserverNotRunningStatusFlag = 1 << 4
+	kubeletNotRunningStatusFlag   = 1 << 5
+	defaultStatusFormat           = `{{.Name}}
 type: Control Plane
 host: {{.Host}}
 kubelet: {{.Kubelet}}
@@ -195,6 +205,12 @@ docker-env: {{.DockerEnv}}
 {{- if .PodManEnv }}
 podman-env: {{.PodManEnv}}
 {{- end }}
+{{- if .Addons }}
+addons:
+{{- range $name, $health := .Addons }}
+  {{ $name }}: {{ $health }}
+{{- end }}
+{{- end }}
 
 `
 	workerStatusFormat = `{{.Name}}
@@ -211,7 +227,11 @@ var statusCmd = &cobra.Command{
 	Short: "Gets the status of a local Kubernetes cluster",
 	Long: `Gets the status of a local Kubernetes cluster.
 	Exit status contains the status of minikube's VM, cluster and Kubernetes encoded on it's bits in this order from right to left.
-	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for Kubernetes NOK)`,
+	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for Kubernetes NOK)
+	With --detailed-exit-code, higher bits tell why: 8 (the node does not exist), 16 (the apiserver of a running node is unhealthy) and 32 (the kubelet of a running node is down).
+	Eg: 7 means the cluster is stopped, 15 that it does not exist, and 18 that its apiserver is unhealthy.
+
+	With --watch, the status is refreshed at an interval, and includes the health of the enabled addons.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		output = strings.ToLower(output)
 		if output != "text" && statusFormat != defaultStatusFormat {
@@ -236,6 +256,15 @@ func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *con
 	for {
 		var statuses []*Status
 
+		if duration != 0 {
+			// pick up nodes and addons changed since the last refresh
+			if c, err := config.Load(cc.Name); err == nil {
+				cc = c
+			} else {
+				klog.Warningf("unable to reload cluster config: %v", err)
+			}
+		}
+
 		if nodeName != "" || statusFormat != defaultStatusFormat && len(cc.Nodes) > 1 {
 			n, _, err := node.Retrieve(*cc, nodeName)
 			if err != nil {
@@ -264,8 +293,17 @@ func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *con
 			}
 		}
 
+		if duration != 0 {
+			addAddonHealth(cc, statuses)
+		}
+
 		switch output {
 		case "text":
+			if duration != 0 && out.IsTerminal(os.Stdout) {
+				// redraw in place, like watch(1)
+				out.String("\033[H\033[2J")
+				out.String("Every %s: minikube status -p %s\t%s\n\n", duration, cc.Name, time.Now().Format(time.RFC1123))
+			}
 			for _, st := range statuses {
 				if err := statusText(st, os.Stdout); err != nil {
 					exit.Error(reason.InternalStatusText, "status text failure", err)
@@ -282,24 +320,40 @@ func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *con
 					exit.Error(reason.InternalStatusJSON, "status json failure", err)
 				}
 			}
+			if duration != 0 {
+				// one status per line, so that it can be consumed as a stream
+				out.String("\n")
+			}
 		default:
 			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'text', 'json'", output))
 		}
 
 		if duration == 0 {
-			os.Exit(exitCode(statuses))
+			os.Exit(exitCode(statuses, detailedExit))
 		}
 		time.Sleep(duration)
 	}
 }
 
-// exitCode calcluates the appropriate exit code given a set of status messages
-func exitCode(statuses []*Status) int {
+// exitCode calcluates the appropriate exit code given a set of status messages, with the reason in the higher bits if detailed
+func exitCode(statuses []*Status, detailed bool) int {
 	c := 0
 	for _, st := range statuses {
 		if st.Host != state.Running.String() {
 			c |= minikubeNotRunningStatusFlag
 		}
+		if detailed && st.Host == Nonexistent {
+			c |= nonexistentStatusFlag
+		}
+		// a paused cluster has its kubelet stopped on purpose
+		if detailed && st.Host == state.Running.String() && st.APIServer != state.Paused.String() {
+			if st.APIServer != state.Running.String() && st.APIServer != Irrelevant {
+				c |= apiserverNotRunningStatusFlag
+			}
+			if st.Kubelet != state.Running.String() {
+				c |= kubeletNotRunningStatusFlag
+			}
+		}
 		if (st.APIServer != state.Running.String() && st.APIServer != Irrelevant) || st.Kubelet != state.Running.String() {
 			c |= clusterNotRunningStatusFlag
 		}
@@ -310,6 +364,30 @@ func exitCode(statuses []*Status) int {
 	return c
 }
 
+// addAddonHealth adds the health of the enabled addons to the status of the primary control plane, if its apiserver is running
+func addAddonHealth(cc *config.ClusterConfig, statuses []*Status) {
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return
+	}
+	for _, st := range statuses {
+		if st.Name != config.MachineName(*cc, cp) || st.APIServer != state.Running.String() {
+			continue
+		}
+		client, err := kapi.Client(cc.Name)
+		if err != nil {
+			klog.Warningf("unable to get kubernetes client: %v", err)
+			return
+		}
+		pods, err := client.CoreV1().Pods("").List(context.Background(), meta.ListOptions{})
+		if err != nil {
+			klog.Warningf("unable to list pods: %v", err)
+			return
+		}
+		st.Addons = addons.Health(cc, pods.Items)
+	}
+}
+
 // nodeStatus looks up the status of a node
 func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*Status, error) {
 	controlPlane := n.ControlPlane
@@ -439,6 +517,7 @@ For the list accessible variables for the template, see the struct values here:
 	statusCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to check status for. Defaults to control plane. Leave blank with default format for status on all nodes.")
 	statusCmd.Flags().DurationVarP(&watch, "watch", "w", 1*time.Second, "Continuously listing/getting the status with optional interval duration.")
 	statusCmd.Flags().Lookup("watch").NoOptDefVal = "1s"
+	statusCmd.Flags().BoolVar(&detailedExit, "detailed-exit-code", false, "Also encode why the cluster is not running in the exit code: 8 (the node does not exist), 16 (the apiserver is unhealthy) and 32 (the kubelet is down).")
 }
 
 func statusText(st *Status, w io.Writer) error {
@@ -542,6 +621,14 @@ func clusterState(sts []*Status) ClusterState {
 			ns.Components["apiserver"] = BaseState{Name: "apiserver", StatusCode: statusCode(st.APIServer)}
 		}
 
+		for name, health := range st.Addons {
+			code := OK
+			if health == addons.HealthNotReady {
+				code = Warning
+			}
+			cs.Components["addon-"+name] = BaseState{Name: name, StatusCode: code, StatusName: codeNames[code]}
+		}
+
 		// Convert status codes to status names
 		ns.StatusName = codeNames[ns.StatusCode]
 		for k, v := range ns.Components {
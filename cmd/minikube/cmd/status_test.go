@@ -24,21 +24,29 @@ import (
 
 func TestExitCode(t *testing.T) {
 	var tests = []struct {
-		name  string
-		want  int
-		state *Status
+		name         string
+		want         int
+		wantDetailed int
+		state        *Status
 	}{
-		{"ok", 0, &Status{Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured}},
-		{"paused", 2, &Status{Host: "Running", Kubelet: "Stopped", APIServer: "Paused", Kubeconfig: Configured}},
-		{"down", 7, &Status{Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: Misconfigured}},
-		{"missing", 7, &Status{Host: "Nonexistent", Kubelet: "Nonexistent", APIServer: "Nonexistent", Kubeconfig: "Nonexistent"}},
+		{"ok", 0, 0, &Status{Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured}},
+		{"paused", 2, 2, &Status{Host: "Running", Kubelet: "Stopped", APIServer: "Paused", Kubeconfig: Configured}},
+		{"down", 7, 7, &Status{Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: Misconfigured}},
+		{"missing", 7, 15, &Status{Host: "Nonexistent", Kubelet: "Nonexistent", APIServer: "Nonexistent", Kubeconfig: "Nonexistent"}},
+		{"apiserver unhealthy", 2, 18, &Status{Host: "Running", Kubelet: "Running", APIServer: "Error", Kubeconfig: Configured}},
+		{"kubelet down", 2, 34, &Status{Host: "Running", Kubelet: "Stopped", APIServer: "Running", Kubeconfig: Configured}},
+		{"worker kubelet down", 2, 34, &Status{Host: "Running", Kubelet: "Stopped", APIServer: Irrelevant, Kubeconfig: Irrelevant}},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := exitCode([]*Status{tc.state})
+			got := exitCode([]*Status{tc.state}, false)
 			if got != tc.want {
 				t.Errorf("exitcode(%+v) = %d, want: %d", tc.state, got, tc.want)
 			}
+			got = exitCode([]*Status{tc.state}, true)
+			if got != tc.wantDetailed {
+				t.Errorf("detailed exitcode(%+v) = %d, want: %d", tc.state, got, tc.wantDetailed)
+			}
 		})
 	}
 }
@@ -59,6 +67,11 @@ func TestStatusText(t *testing.T) {
 			state: &Status{Name: "minikube", Host: "Running", Kubelet: "Stopped", APIServer: "Paused", Kubeconfig: Configured},
 			want:  "minikube\ntype: Control Plane\nhost: Running\nkubelet: Stopped\napiserver: Paused\nkubeconfig: Configured\n\n",
 		},
+		{
+			name:  "addons",
+			state: &Status{Name: "minikube", Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, Addons: map[string]string{"storage-provisioner": "Running", "dashboard": "NotReady"}},
+			want:  "minikube\ntype: Control Plane\nhost: Running\nkubelet: Running\napiserver: Running\nkubeconfig: Configured\naddons:\n  dashboard: NotReady\n  storage-provisioner: Running\n\n",
+		},
 		{
 			name:  "down",
 			state: &Status{Name: "minikube", Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: Misconfigured},
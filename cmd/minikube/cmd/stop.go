@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/hooks"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -38,6 +39,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/schedule"
 	"k8s.io/minikube/pkg/minikube/style"
+	pkgtrace "k8s.io/minikube/pkg/trace"
 	"k8s.io/minikube/pkg/util/retry"
 )
 
@@ -46,6 +48,8 @@ var (
 	keepActive            bool
 	scheduledStopDuration time.Duration
 	cancelScheduledStop   bool
+	stopTrace             string
+	stopTraceEndpoint     string
 )
 
 // stopCmd represents the stop command
@@ -61,16 +65,23 @@ func init() {
 	stopCmd.Flags().BoolVar(&keepActive, "keep-context-active", false, "keep the kube-context active after cluster is stopped. Defaults to false.")
 	stopCmd.Flags().DurationVar(&scheduledStopDuration, "schedule", 0*time.Second, "Set flag to stop cluster after a set amount of time (e.g. --schedule=5m)")
 	stopCmd.Flags().BoolVar(&cancelScheduledStop, "cancel-scheduled", false, "cancel any existing scheduled stop requests")
-	stopCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
 
 	if err := viper.GetViper().BindPFlags(stopCmd.Flags()); err != nil {
 		exit.Error(reason.InternalFlagsBind, "unable to bind flags", err)
 	}
+
+	// not bound to viper, as they would shadow the start flags of the same name
+	stopCmd.Flags().StringVar(&stopTrace, trace, "", "Send trace events. Options include: [gcp, otlp]")
+	stopCmd.Flags().StringVar(&stopTraceEndpoint, traceEndpoint, "", "The OTLP/gRPC endpoint to send trace events to with --trace=otlp, prefixed with https:// to use TLS. Defaults to "+pkgtrace.DefaultOTLPEndpoint)
 }
 
 // runStop handles the executes the flow of "minikube stop"
 func runStop(cmd *cobra.Command, args []string) {
 	out.SetJSON(outputFormat == "json")
+	if err := pkgtrace.Initialize(stopTrace, stopTraceEndpoint, "minikube stop"); err != nil {
+		exit.Message(reason.Usage, "error initializing tracing: {{.Error}}", out.V{"Error": err.Error()})
+	}
+	defer pkgtrace.Cleanup()
 	register.Reg.SetStep(register.Stopping)
 
 	// check if profile path exists, if no PathError log file exists for valid profile
@@ -131,6 +142,12 @@ func stopProfile(profile string) int {
 	// end new code
 	api, cc := mustload.Partial(profile)
 	defer api.Close()
+	useProfileDockerHost(cc)
+
+	if err := hooks.Run(hooks.Payload{Event: hooks.PreStop, Profile: profile}); err != nil {
+		exit.Error(reason.HostHook, "pre-stop hook failed", err)
+	}
+	stopMountManager(cc)
 
 	for _, n := range cc.Nodes {
 		machineName := config.MachineName(*cc, n)
@@ -144,6 +161,7 @@ func stopProfile(profile string) int {
 	if err := killMountProcess(); err != nil {
 		out.WarningT("Unable to kill mount process: {{.error}}", out.V{"error": err})
 	}
+	stopSSHForward(profile)
 
 	if !keepActive {
 		if err := kubeconfig.DeleteContext(profile, kubeconfig.PathFromEnv()); err != nil {
@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/storage/journal"
+)
+
+const (
+	hostPathProvisionerName = "k8s.io/minikube-hostpath"
+	provisionedByAnnotation = "pv.kubernetes.io/provisioned-by"
+	// hostPathNodeAnnotation is set by the provisioner on volumes bound to a specific node
+	hostPathNodeAnnotation = "hostPathProvisionerNode"
+)
+
+var storageStatusEvents int
+
+// storageStatusCmd represents the storage status command
+var storageStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the volumes managed by the storage provisioner and its recent failures",
+	Long:  "Lists the PersistentVolumes owned by the minikube storage provisioner with their backing paths and disk usage, followed by the most recent failed operations.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		co := mustload.Healthy(cname)
+
+		client, err := kapi.Client(cname)
+		if err != nil {
+			exit.Error(reason.InternalKubernetesClient, "kubernetes client", err)
+		}
+
+		pvs, err := client.CoreV1().PersistentVolumes().List(context.Background(), meta.ListOptions{})
+		if err != nil {
+			exit.Error(reason.InternalKubernetesClient, "listing persistent volumes", err)
+		}
+		var owned []core.PersistentVolume
+		for _, pv := range pvs.Items {
+			if pv.Annotations[provisionedByAnnotation] == hostPathProvisionerName && pv.Spec.HostPath != nil {
+				owned = append(owned, pv)
+			}
+		}
+
+		usage := volumeUsage(co, owned)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Volume", "Claim", "Status", "Node", "Path", "Usage"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, pv := range owned {
+			claim := ""
+			if ref := pv.Spec.ClaimRef; ref != nil {
+				claim = ref.Namespace + "/" + ref.Name
+			}
+			table.Append([]string{pv.Name, claim, string(pv.Status.Phase), pv.Annotations[hostPathNodeAnnotation], pv.Spec.HostPath.Path, usage[pv.Spec.HostPath.Path]})
+		}
+		table.Render()
+
+		events, err := journal.Read(context.Background(), client)
+		if err != nil {
+			exit.Error(reason.InternalKubernetesClient, "reading storage provisioner journal", err)
+		}
+		var failures []journal.Event
+		for _, e := range events {
+			if e.Failed() {
+				failures = append(failures, e)
+			}
+		}
+		if len(failures) > storageStatusEvents {
+			failures = failures[len(failures)-storageStatusEvents:]
+		}
+		if len(failures) == 0 {
+			out.Styled(style.Happy, "No recent storage provisioner failures")
+			return
+		}
+
+		out.Styled(style.Failure, "Recent storage provisioner failures:")
+		table = tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Time", "Operation", "Volume", "Claim", "Error"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, e := range failures {
+			table.Append([]string{e.Time.Local().Format("2006-01-02 15:04:05"), e.Operation, e.Volume, e.Claim, e.Error})
+		}
+		table.Render()
+	},
+}
+
+// volumeUsage returns the disk usage of each volume path, grouping volumes by the node they live on
+func volumeUsage(co mustload.ClusterController, pvs []core.PersistentVolume) map[string]string {
+	byNode := map[string][]string{}
+	for _, pv := range pvs {
+		n := pv.Annotations[hostPathNodeAnnotation]
+		byNode[n] = append(byNode[n], pv.Spec.HostPath.Path)
+	}
+
+	usage := map[string]string{}
+	for n, paths := range byNode {
+		runner := co.CP.Runner
+		if n != "" {
+			var err error
+			runner, err = nodeRunnerByName(co, n)
+			if err != nil {
+				klog.Warningf("unable to get disk usage on %s: %v", n, err)
+				continue
+			}
+		}
+
+		args := append([]string{"du", "-sk"}, paths...)
+		rr, err := runner.RunCmd(exec.Command("sudo", args...))
+		if err != nil {
+			// du exits non-zero if some paths are missing, but still reports the others
+			klog.Warningf("du: %v", err)
+		}
+		for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+			var kb int64
+			var path string
+			if _, err := fmt.Sscanf(line, "%d\t%s", &kb, &path); err != nil {
+				continue
+			}
+			usage[path] = units.BytesSize(float64(kb * 1024))
+		}
+	}
+	return usage
+}
+
+// nodeRunnerByName returns a command runner for the node with the given Kubernetes node name
+func nodeRunnerByName(co mustload.ClusterController, name string) (command.Runner, error) {
+	n, _, err := node.Retrieve(*co.Config, name)
+	if err != nil {
+		return nil, err
+	}
+	return nodeRunner(co, *n)
+}
+
+func init() {
+	storageStatusCmd.Flags().IntVar(&storageStatusEvents, "events", 10, "The maximum number of recent failures to show")
+	storageCmd.AddCommand(storageStatusCmd)
+}
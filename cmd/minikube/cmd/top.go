@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/metrics"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// topWatch is the interval of the refreshes in watch mode, or 0 to print the usage once
+var topWatch time.Duration
+
+// topCmd represents the set of top subcommands
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Display the resource usage of nodes and pods",
+	Long:  "Display the CPU and memory usage of the nodes or pods of the cluster, enabling the metrics-server addon if needed.",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube top [node|pod]")
+	},
+}
+
+// metricsClient returns a client for the cluster, once metrics-server serves the usage of its nodes
+func metricsClient(co mustload.ClusterController) kubernetes.Interface {
+	cname := ClusterFlagValue()
+	if !assets.Addons["metrics-server"].IsEnabled(co.Config) {
+		out.ErrT(style.Enabling, "Enabling metrics-server ...")
+		if err := addons.SetAndSave(cname, "metrics-server", "true"); err != nil {
+			exit.Error(reason.InternalAddonEnable, "Unable to enable metrics-server", err)
+		}
+	}
+
+	client, err := kapi.Client(cname)
+	if err != nil {
+		exit.Error(reason.InternalKubernetesClient, "kubernetes client", err)
+	}
+
+	if _, err := metrics.Nodes(client); err != nil {
+		klog.Infof("metrics API not ready: %v", err)
+		out.ErrT(style.Waiting, "Waiting for metrics-server to collect the first metrics ...")
+		if err := kapi.WaitForDeploymentToStabilize(client, "kube-system", "metrics-server", kapi.ReasonableStartTime); err != nil {
+			exit.Error(reason.SvcMetricsTimeout, "metrics-server is not running", err)
+		}
+		if err := metrics.WaitForAPI(client, kapi.ReasonableStartTime); err != nil {
+			exit.Error(reason.SvcMetricsTimeout, "metrics-server did not serve the metrics in time", err)
+		}
+	}
+	return client
+}
+
+// watchTop calls show once, or at every topWatch interval in watch mode
+func watchTop(cmd *cobra.Command, show func()) {
+	if !cmd.Flags().Changed("watch") || topWatch <= 0 {
+		show()
+		return
+	}
+	for {
+		if outputFormat == "text" && out.IsTerminal(os.Stdout) {
+			// clear the screen, like top does
+			out.String("\033[H\033[2J")
+		}
+		show()
+		time.Sleep(topWatch)
+	}
+}
+
+func init() {
+	topCmd.PersistentFlags().DurationVarP(&topWatch, "watch", "w", 0, "Continuously refresh the usage, at the given interval.")
+	topCmd.PersistentFlags().Lookup("watch").NoOptDefVal = "2s"
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/metrics"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+// hostMemoryWarnPercent is the share of the memory limit of the driver above which top warns
+const hostMemoryWarnPercent = 90
+
+// nodeTop is the usage of a node, as shown by 'minikube top node --output=json'
+type nodeTop struct {
+	metrics.NodeUsage
+	// Host is the usage of its VM or container, nil when unknown
+	Host *metrics.HostUsage
+}
+
+var topNodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Display the resource usage of nodes",
+	Long:  "Display the CPU and memory usage of the nodes, as seen by Kubernetes and by the driver running them.",
+	Example: `
+$ minikube top node
+$ minikube top node --watch=5s
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		client := metricsClient(co)
+
+		watchTop(cmd, func() {
+			usages, err := metrics.Nodes(client)
+			if err != nil {
+				exit.Error(reason.SvcMetricsTimeout, "Unable to get node metrics", err)
+			}
+			tops := []nodeTop{}
+			for _, u := range usages {
+				tops = append(tops, nodeTop{NodeUsage: u, Host: hostUsage(co, u.Name)})
+			}
+
+			if outputFormat == "json" {
+				printJSON(tops)
+				return
+			}
+			printNodeTops(tops)
+		})
+	},
+}
+
+// hostUsage returns the usage of the machine of the node named name, or nil if it can't be read
+func hostUsage(co mustload.ClusterController, name string) *metrics.HostUsage {
+	for _, n := range co.Config.Nodes {
+		if config.MachineName(*co.Config, n) != name {
+			continue
+		}
+		r, err := nodeRunner(co, n)
+		if err != nil {
+			klog.Warningf("unable to get runner of %s: %v", name, err)
+			return nil
+		}
+		u, err := metrics.Host(*co.Config, n, r)
+		if err != nil {
+			klog.Warningf("unable to get host usage of %s: %v", name, err)
+			return nil
+		}
+		return u
+	}
+	return nil
+}
+
+func printNodeTops(tops []nodeTop) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node", "CPU(cores)", "CPU%", "Memory", "Memory%", "Host CPU%", "Host Memory"})
+	table.SetAutoFormatHeaders(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	for _, t := range tops {
+		hostCPU, hostMem := "<unknown>", "<unknown>"
+		if t.Host != nil {
+			hostCPU = fmt.Sprintf("%.1f%%", t.Host.CPUPercent)
+			hostMem = fmt.Sprintf("%s / %s (%d%%)", units.BytesSize(float64(t.Host.MemoryUsed)), units.BytesSize(float64(t.Host.MemoryLimit)), t.Host.MemoryPercent())
+		}
+		table.Append([]string{t.Name, fmt.Sprintf("%dm", t.CPU), fmt.Sprintf("%d%%", t.CPUPercent),
+			units.BytesSize(float64(t.Memory)), fmt.Sprintf("%d%%", t.MemoryPercent), hostCPU, hostMem})
+	}
+	table.Render()
+
+	for _, t := range tops {
+		if t.Host != nil && t.Host.MemoryPercent() >= hostMemoryWarnPercent {
+			out.WarningT("{{.name}} is using {{.percent}}% of the memory its driver allows. To give it more, recreate the cluster with a larger --memory.", out.V{"name": t.Name, "percent": t.Host.MemoryPercent()})
+		}
+	}
+}
+
+func init() {
+	topCmd.AddCommand(topNodeCmd)
+}
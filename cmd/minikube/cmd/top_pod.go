@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/metrics"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+var (
+	// topNamespace is the namespace of the pods to show
+	topNamespace string
+	// topAllNamespaces shows the pods of all the namespaces
+	topAllNamespaces bool
+)
+
+var topPodCmd = &cobra.Command{
+	Use:   "pod",
+	Short: "Display the resource usage of pods",
+	Long:  "Display the CPU and memory usage of the pods, summed over their containers.",
+	Example: `
+$ minikube top pod
+$ minikube top pod --all-namespaces --watch
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		client := metricsClient(co)
+
+		namespace := topNamespace
+		if topAllNamespaces {
+			namespace = ""
+		}
+		watchTop(cmd, func() {
+			usages, err := metrics.Pods(client, namespace)
+			if err != nil {
+				exit.Error(reason.SvcMetricsTimeout, "Unable to get pod metrics", err)
+			}
+
+			if outputFormat == "json" {
+				printJSON(usages)
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Namespace", "Pod", "CPU(cores)", "Memory"})
+			table.SetAutoFormatHeaders(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetCenterSeparator("|")
+			for _, u := range usages {
+				table.Append([]string{u.Namespace, u.Name, fmt.Sprintf("%dm", u.CPU), units.BytesSize(float64(u.Memory))})
+			}
+			table.Render()
+		})
+	},
+}
+
+func init() {
+	topPodCmd.Flags().StringVarP(&topNamespace, "namespace", "n", "default", "The namespace of the pods to show.")
+	topPodCmd.Flags().BoolVarP(&topAllNamespaces, "all-namespaces", "A", false, "Show the pods of all the namespaces.")
+	topCmd.AddCommand(topPodCmd)
+}
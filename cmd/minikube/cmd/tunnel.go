@@ -22,6 +22,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -33,18 +34,24 @@ import (
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/tunnel"
 	"k8s.io/minikube/pkg/minikube/tunnel/kic"
 )
 
-var cleanup bool
+var (
+	cleanup   bool
+	reconnect bool
+)
 
 // tunnelCmd represents the tunnel command
 var tunnelCmd = &cobra.Command{
 	Use:   "tunnel",
 	Short: "Connect to LoadBalancer services",
-	Long:  `tunnel creates a route to services deployed with type LoadBalancer and sets their Ingress to their ClusterIP. for a detailed example see https://minikube.sigs.k8s.io/docs/tasks/loadbalancer`,
+	Long: `tunnel creates a route to services deployed with type LoadBalancer and sets their Ingress to their ClusterIP. for a detailed example see https://minikube.sigs.k8s.io/docs/tasks/loadbalancer
+
+Use 'minikube tunnel start' to run the tunnel in the background instead.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		RootCmd.PersistentPreRun(cmd, args)
 	},
@@ -70,7 +77,8 @@ var tunnelCmd = &cobra.Command{
 		}
 
 		ctrlC := make(chan os.Signal, 1)
-		signal.Notify(ctrlC, os.Interrupt)
+		// SIGTERM is sent by 'minikube tunnel stop' to background tunnels
+		signal.Notify(ctrlC, os.Interrupt, syscall.SIGTERM)
 		ctx, cancel := context.WithCancel(context.Background())
 		go func() {
 			<-ctrlC
@@ -78,8 +86,11 @@ var tunnelCmd = &cobra.Command{
 		}()
 
 		if driver.NeedsPortForward(co.Config.Driver) {
+			if co.Config.KubernetesConfig.LoadBalancerIPRange != "" {
+				out.WarningT("The {{.driver}} driver can not route the LoadBalancer IP range {{.range}}, services will be exposed on 127.0.0.1", out.V{"driver": co.Config.Driver, "range": co.Config.KubernetesConfig.LoadBalancerIPRange})
+			}
 
-			port, err := oci.ForwardedPort(oci.Docker, cname, 22)
+			port, err := oci.ForwardedPort(co.Config.Driver, cname, 22)
 			if err != nil {
 				exit.Error(reason.DrvPortForward, "error getting ssh port", err)
 			}
@@ -87,6 +98,11 @@ var tunnelCmd = &cobra.Command{
 			sshKey := filepath.Join(localpath.MiniPath(), "machines", cname, "id_rsa")
 
 			kicSSHTunnel := kic.NewSSHTunnel(ctx, sshPort, sshKey, clientset.CoreV1())
+			// the ssh port changes when the node container is restarted
+			kicSSHTunnel.ReconnectWith(func() (string, error) {
+				port, err := oci.ForwardedPort(co.Config.Driver, cname, 22)
+				return strconv.Itoa(port), err
+			})
 			err = kicSSHTunnel.Start()
 			if err != nil {
 				exit.Error(reason.SvcTunnelStart, "error starting tunnel", err)
@@ -95,6 +111,9 @@ var tunnelCmd = &cobra.Command{
 			return
 		}
 
+		if reconnect {
+			manager.EnableReconnect()
+		}
 		done, err := manager.StartTunnel(ctx, cname, co.API, config.DefaultLoader, clientset.CoreV1())
 		if err != nil {
 			exit.Error(reason.SvcTunnelStart, "error starting tunnel", err)
@@ -105,4 +124,5 @@ var tunnelCmd = &cobra.Command{
 
 func init() {
 	tunnelCmd.Flags().BoolVarP(&cleanup, "cleanup", "c", true, "call with cleanup=true to remove old tunnels")
+	tunnelCmd.Flags().BoolVar(&reconnect, "reconnect", false, "Keep the tunnel running while the cluster is stopped, and re-establish it once the cluster is running again")
 }
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+)
+
+var tunnelStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Runs the tunnel in the background",
+	Long: `Runs 'minikube tunnel' as a background process, which keeps running until 'minikube tunnel stop' or 'minikube delete'.
+
+The background tunnel survives restarts of the cluster and of the host: its routes are re-established once the cluster is running again.
+Routes and privileged ports require root permissions: as a background tunnel can not prompt for a password, passwordless sudo is required for them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube tunnel start")
+		}
+
+		cname := ClusterFlagValue()
+		mustload.Healthy(cname)
+
+		var extraArgs []string
+		if !cleanup {
+			extraArgs = append(extraArgs, "--cleanup=false")
+		}
+		d, err := tunnel.StartDaemon(cname, extraArgs)
+		if err != nil {
+			exit.Error(reason.SvcTunnelStart, "error starting tunnel", err)
+		}
+		out.Step(style.Running, "Started tunnel for {{.profile}} in the background (pid {{.pid}})", out.V{"profile": cname, "pid": d.Pid})
+		out.Styled(style.Tip, "Logs are written to {{.log}}, stop it with: minikube tunnel stop -p {{.profile}}", out.V{"log": d.LogFile, "profile": cname})
+	},
+}
+
+var tunnelStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stops the background tunnel",
+	Long:  "Stops the tunnel started with 'minikube tunnel start', and removes the routes left behind by tunnels which are not running anymore.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube tunnel stop")
+		}
+
+		cname := ClusterFlagValue()
+		found, err := tunnel.StopDaemon(cname)
+		if err != nil {
+			exit.Error(reason.SvcTunnelStop, "error stopping tunnel", err)
+		}
+		if !found {
+			out.Step(style.Empty, "No background tunnel is running for {{.profile}}", out.V{"profile": cname})
+			return
+		}
+		out.Step(style.Stopped, "Stopped the background tunnel of {{.profile}}", out.V{"profile": cname})
+	},
+}
+
+var tunnelStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the status of the background tunnel",
+	Long:  "Shows whether the tunnel started with 'minikube tunnel start' is running, and the routes registered for the cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube tunnel status")
+		}
+
+		cname := ClusterFlagValue()
+		d, err := tunnel.LoadDaemon(cname)
+		if err != nil {
+			exit.Error(reason.SvcTunnelStatus, "error loading tunnel state", err)
+		}
+		switch {
+		case d == nil:
+			out.Step(style.Empty, "No background tunnel was started for {{.profile}}", out.V{"profile": cname})
+		case d.Running():
+			out.Step(style.Running, "Background tunnel for {{.profile}} is running (pid {{.pid}}), started at {{.started}}", out.V{"profile": cname, "pid": d.Pid, "started": d.Started.Format("2006-01-02 15:04:05")})
+		default:
+			out.WarningT("Background tunnel for {{.profile}} exited, see {{.log}}", out.V{"profile": cname, "log": d.LogFile})
+		}
+
+		ids, err := tunnel.RegisteredTunnels(cname)
+		if err != nil {
+			exit.Error(reason.SvcTunnelStatus, "error listing tunnel routes", err)
+		}
+		for _, id := range ids {
+			out.Infof("route {{.route}} (pid {{.pid}})", out.V{"route": id.Route.String(), "pid": id.Pid})
+		}
+	},
+}
+
+func init() {
+	tunnelStartCmd.Flags().BoolVarP(&cleanup, "cleanup", "c", true, "call with cleanup=true to remove old tunnels")
+	tunnelCmd.AddCommand(tunnelStartCmd)
+	tunnelCmd.AddCommand(tunnelStopCmd)
+	tunnelCmd.AddCommand(tunnelStatusCmd)
+}
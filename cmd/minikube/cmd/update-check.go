@@ -18,17 +18,23 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/notify"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/version"
 )
 
 var updateCheckCmd = &cobra.Command{
 	Use:   "update-check",
 	Short: "Print current and latest version number",
-	Long:  `Print current and latest version number`,
+	Long:  `Print current and latest version number, and warn about the profiles using a custom base image built from an older base image than the one of this minikube`,
 	Run: func(command *cobra.Command, args []string) {
 		url := notify.GithubMinikubeReleasesURL
 		r, err := notify.AllVersionsFromURL(url)
@@ -42,5 +48,30 @@ var updateCheckCmd = &cobra.Command{
 
 		out.Ln("CurrentVersion: %s", version.GetVersion())
 		out.Ln("LatestVersion: %s", r[0].Name)
+		warnOutdatedBaseImages()
 	},
 }
+
+// warnOutdatedBaseImages warns about the profiles using a custom base image built from an older base image than the one of this minikube
+func warnOutdatedBaseImages() {
+	profiles, _, err := config.ListProfiles()
+	if err != nil {
+		klog.Warningf("failed to list profiles: %v", err)
+		return
+	}
+	for _, p := range profiles {
+		cc := p.Config
+		if cc == nil || !driver.IsKIC(cc.Driver) || cc.KicBaseImage == "" || cc.KicBaseImage == kic.BaseImage {
+			continue
+		}
+		labels, err := oci.ImageLabels(cc.Driver, cc.KicBaseImage)
+		if err != nil {
+			klog.Warningf("failed to inspect base image %s of %s: %v", cc.KicBaseImage, p.Name, err)
+			continue
+		}
+		if v, outdated := kic.CustomBaseOutdated(labels); outdated {
+			out.WarningT("Profile {{.profile}} uses the base image {{.image}}, built from kicbase {{.built}} which is older than {{.current}}", out.V{"profile": p.Name, "image": cc.KicBaseImage, "built": v, "current": kic.Version})
+			out.Styled(style.Tip, "To rebuild it, run: minikube build-base --from={{.from}} --tag=<new tag> with the same --add-pkg and --add-file flags", out.V{"from": kic.BaseImage})
+		}
+	}
+}
@@ -25,7 +25,21 @@ import (
 	"k8s.io/minikube/pkg/storage"
 )
 
-var pvDir = "/tmp/hostpath-provisioner"
+var (
+	pvDir          = "/tmp/hostpath-provisioner"
+	metricsAddr    = flag.String("metrics-address", ":9095", "The address to serve Prometheus metrics on, empty to disable")
+	leaderElect    = flag.Bool("leader-elect", false, "Coordinate with other replicas through a Lease, so only one provisions at a time")
+	leaseNamespace = flag.String("leader-elect-namespace", "kube-system", "The namespace of the leader election Lease")
+	leaseName      = flag.String("leader-elect-name", "k8s.io-minikube-hostpath", "The name of the leader election Lease")
+
+	// set on helper pods the provisioner runs on the node a volume lives on
+	volumeHelper = flag.String("volume-helper", "", "Run as a volume helper: 'create' or 'delete' the directory at --volume-path and exit")
+	volumePath   = flag.String("volume-path", "", "The volume directory a volume helper operates on")
+	volumeMode   = flag.String("volume-mode", "", "The octal mode of the directory created by a volume helper")
+	volumeUID    = flag.String("volume-uid", "", "The owner of the directory created by a volume helper")
+	volumeGID    = flag.String("volume-gid", "", "The group of the directory created by a volume helper")
+	volumeLabel  = flag.String("volume-selinux-label", "", "The SELinux label of the directory created by a volume helper")
+)
 
 func main() {
 	// Glog requires that /tmp exists.
@@ -35,7 +49,28 @@ func main() {
 	}
 	flag.Parse()
 
-	if err := storage.StartStorageProvisioner(pvDir); err != nil {
+	if *volumeHelper != "" {
+		if err := storage.RunVolumeHelper(storage.VolumeHelperConfig{
+			PVDir:        pvDir,
+			Operation:    *volumeHelper,
+			Path:         *volumePath,
+			Mode:         *volumeMode,
+			UID:          *volumeUID,
+			GID:          *volumeGID,
+			SELinuxLabel: *volumeLabel,
+		}); err != nil {
+			klog.Exit(err)
+		}
+		return
+	}
+
+	if err := storage.StartStorageProvisioner(storage.Config{
+		PVDir:          pvDir,
+		MetricsAddress: *metricsAddr,
+		LeaderElection: *leaderElect,
+		LeaseNamespace: *leaseNamespace,
+		LeaseName:      *leaseName,
+	}); err != nil {
 		klog.Exit(err)
 	}
 
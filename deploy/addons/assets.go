@@ -77,6 +77,10 @@ var (
 	//go:embed registry/*.tmpl
 	RegistryAssets embed.FS
 
+	// RegistryMirrorAssets assets for registry-mirror addon
+	//go:embed registry-mirror/*.tmpl
+	RegistryMirrorAssets embed.FS
+
 	// RegistryCredsAssets assets for registry-creds addon
 	//go:embed registry-creds/registry-creds-rc.yaml.tmpl
 	RegistryCredsAssets embed.FS
@@ -97,6 +101,10 @@ var (
 	//go:embed gpu/nvidia-gpu-device-plugin.yaml.tmpl
 	NvidiaGpuDevicePluginAssets embed.FS
 
+	// AmdGpuDevicePluginAssets assets for amd-gpu-device-plugin addon
+	//go:embed gpu/amd-gpu-device-plugin.yaml.tmpl
+	AmdGpuDevicePluginAssets embed.FS
+
 	// LogviewerAssets assets for logviewer addon
 	//go:embed logviewer/*.tmpl
 	LogviewerAssets embed.FS
@@ -27,4 +27,10 @@ define BUILDKIT_BIN_INSTALL_TARGET_CMDS
 		$(TARGET_DIR)/usr/sbin
 endef
 
+define BUILDKIT_BIN_INSTALL_INIT_SYSTEMD
+	$(INSTALL) -Dm644 \
+		$(BUILDKIT_BIN_PKGDIR)/buildkit.service \
+		$(TARGET_DIR)/usr/lib/systemd/system/buildkit.service
+endef
+
 $(eval $(generic-package))
@@ -17,14 +17,10 @@ limitations under the License.
 package main
 
 import (
-	"bytes"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"runtime/debug"
-	"strings"
 
 	"github.com/pkg/errors"
 
@@ -39,14 +35,12 @@ const (
 )
 
 var (
-	dockerStorageDriver = "overlay2"
-	podmanStorageDriver = "overlay"
-	containerRuntimes   = []string{"docker", "containerd", "cri-o"}
-	k8sVersions         []string
-	k8sVersion          = flag.String("kubernetes-version", "", "desired Kubernetes version, for example `v1.17.2`")
-	noUpload            = flag.Bool("no-upload", false, "Do not upload tarballs to GCS")
-	force               = flag.Bool("force", false, "Generate the preload tarball even if it's already exists")
-	limit               = flag.Int("limit", 0, "Limit the number of tarballs to generate")
+	containerRuntimes = []string{"docker", "containerd", "cri-o"}
+	k8sVersions       []string
+	k8sVersion        = flag.String("kubernetes-version", "", "desired Kubernetes version, for example `v1.17.2`")
+	noUpload          = flag.Bool("no-upload", false, "Do not upload tarballs to GCS")
+	force             = flag.Bool("force", false, "Generate the preload tarball even if it's already exists")
+	limit             = flag.Int("limit", 0, "Limit the number of tarballs to generate")
 )
 
 type preloadCfg struct {
@@ -152,41 +146,6 @@ func makePreload(cfg preloadCfg) error {
 	return nil
 }
 
-func verifyDockerStorage() error {
-	cmd := exec.Command("docker", "exec", profile, "docker", "info", "-f", "{{.Info.Driver}}")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("%v: %v:\n%s", cmd.Args, err, stderr.String())
-	}
-	driver := strings.Trim(string(output), " \n")
-	if driver != dockerStorageDriver {
-		return fmt.Errorf("docker storage driver %s does not match requested %s", driver, dockerStorageDriver)
-	}
-	return nil
-}
-
-func verifyPodmanStorage() error {
-	cmd := exec.Command("docker", "exec", profile, "sudo", "podman", "info", "-f", "json")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("%v: %v:\n%s", cmd.Args, err, stderr.String())
-	}
-	var info map[string]map[string]interface{}
-	err = json.Unmarshal(output, &info)
-	if err != nil {
-		return err
-	}
-	driver := info["store"]["graphDriverName"]
-	if driver != podmanStorageDriver {
-		return fmt.Errorf("podman storage driver %s does not match requested %s", driver, podmanStorageDriver)
-	}
-	return nil
-}
-
 // exit will exit and clean up minikube
 func exit(msg string, err error) {
 	fmt.Printf("WithError(%s)=%v called from:\n%s", msg, err, debug.Stack())
@@ -44,6 +44,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/trace"
 	"k8s.io/minikube/pkg/util/retry"
 )
 
@@ -237,7 +238,7 @@ https://github.com/kubernetes/minikube/issues/7332`, out.V{"driver_name": cc.Dri
 		out.WarningT("At least needs control plane nodes to enable addon")
 	}
 
-	data := assets.GenerateTemplateData(addon, cc.KubernetesConfig, networkInfo, images, customRegistries)
+	data := assets.GenerateTemplateData(addon, cc.KubernetesConfig, networkInfo, images, customRegistries, cc.AddonValues[name])
 	return enableOrDisableAddonInternal(cc, addon, runner, data, enable)
 }
 
@@ -334,16 +335,9 @@ func verifyAddonStatusInternal(cc *config.ClusterConfig, name string, val string
 	return nil
 }
 
-// Start enables the default addons for a profile, plus any additional
-func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]bool, additional []string) {
-	defer wg.Done()
-
-	start := time.Now()
-	klog.Infof("enableAddons start: toEnable=%v, additional=%s", toEnable, additional)
-	defer func() {
-		klog.Infof("enableAddons completed in %s", time.Since(start))
-	}()
-
+// ToEnable returns the sorted names of the addons to enable on start: the addons saved in toEnable,
+// those enabled by default which are not saved, and the additional addons requested. toEnable is updated with the defaults.
+func ToEnable(cc *config.ClusterConfig, toEnable map[string]bool, additional []string) []string {
 	// Get the default values of any addons not saved to our config
 	for name, a := range assets.Addons {
 		defaultVal := a.IsEnabled(cc)
@@ -374,6 +368,20 @@ func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]boo
 		}
 	}
 	sort.Strings(toEnableList)
+	return toEnableList
+}
+
+// Start enables the default addons for a profile, plus any additional
+func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]bool, additional []string) {
+	defer wg.Done()
+
+	start := time.Now()
+	klog.Infof("enableAddons start: toEnable=%v, additional=%s", toEnable, additional)
+	defer func() {
+		klog.Infof("enableAddons completed in %s", time.Since(start))
+	}()
+
+	toEnableList := ToEnable(cc, toEnable, additional)
 
 	var awg sync.WaitGroup
 
@@ -386,7 +394,10 @@ func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]boo
 	for _, a := range toEnableList {
 		awg.Add(1)
 		go func(name string) {
+			span := "Enabling addon " + name
+			trace.StartSpan(span)
 			err := RunCallbacks(cc, name, "true")
+			trace.EndSpan(span)
 			if err != nil {
 				out.WarningT("Enabling '{{.name}}' returned an error: {{.error}}", out.V{"name": name, "error": err})
 			} else {
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"os/exec"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+const registryMirrorAddon = "registry-mirror"
+
+// RegistryMirrors maps the registries cached by the registry-mirror addon to the
+// address its pull-through proxy for them listens on, on every node
+var RegistryMirrors = map[string]string{
+	"docker.io": "localhost:5001",
+	"gcr.io":    "localhost:5002",
+	"quay.io":   "localhost:5003",
+}
+
+// RegistryMirrorEnabled returns whether the container runtime should pull through the registry-mirror addon
+func RegistryMirrorEnabled(cc config.ClusterConfig) bool {
+	if cc.Addons[registryMirrorAddon] {
+		return true
+	}
+	for _, a := range viper.GetStringSlice(config.AddonListFlag) {
+		if a == registryMirrorAddon {
+			return true
+		}
+	}
+	return false
+}
+
+// enableOrDisableRegistryMirror tells how to route image pulls through the mirror, which is configured in the runtime on start
+func enableOrDisableRegistryMirror(cc *config.ClusterConfig, name string, val string) error {
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrapf(err, "parsing bool: %s", name)
+	}
+	if !enable {
+		return nil
+	}
+
+	if !driver.IsKIC(cc.Driver) {
+		out.Infof("The {{.driver}} driver keeps the registry mirror cache inside each node: it survives restarts but not 'minikube delete'", out.V{"driver": cc.Driver})
+	}
+	if cc.KubernetesConfig.ContainerRuntime == "docker" {
+		out.Infof("The docker runtime can only pull Docker Hub images through the mirror, use containerd or cri-o to also mirror gcr.io and quay.io")
+	}
+
+	co := mustload.Running(cc.Name)
+	// the runtime was already configured if the addon was enabled as part of 'minikube start'
+	mirror := RegistryMirrors["docker.io"]
+	c := exec.Command("sudo", "grep", "-rqs", mirror, "/etc/docker/daemon.json", "/etc/containerd/config.toml", "/etc/containers/registries.conf.d")
+	if _, err := co.CP.Runner.RunCmd(c); err != nil {
+		klog.Infof("%s is not configured as a mirror: %v", mirror, err)
+		out.Step(style.Tip, "To pull images through the mirror, restart the container runtime with: {{.cmd}}", out.V{"cmd": mustload.ExampleCmd(cc.Name, "start")})
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+const (
+	// legacyProvisionerPod is the bare Pod earlier versions of the storage-provisioner addon ran as
+	legacyProvisionerPod = "storage-provisioner"
+	// legacyProvisionerLock is the Endpoints it elected itself leader through, before the Lease
+	legacyProvisionerLock = "k8s.io-minikube-hostpath"
+)
+
+// removeLegacyStorageProvisioner deletes the Pod and the Endpoints lock of earlier versions of the
+// storage-provisioner addon before it is enabled, as the legacy Pod would keep provisioning next to the new replicas
+func removeLegacyStorageProvisioner(cc *config.ClusterConfig, name string, val string) error {
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrapf(err, "parsing bool: %s", name)
+	}
+	if !enable {
+		return nil
+	}
+
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "client")
+	}
+	ctx := context.Background()
+	pods := client.CoreV1().Pods("kube-system")
+	pod, err := pods.Get(ctx, legacyProvisionerPod, meta.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "getting pod %s", legacyProvisionerPod)
+	}
+	// pods of the DaemonSet have an owner and a generated name, the legacy one has neither
+	if err == nil && len(pod.OwnerReferences) == 0 {
+		klog.Infof("deleting the legacy %s pod", legacyProvisionerPod)
+		if err := pods.Delete(ctx, legacyProvisionerPod, meta.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "deleting pod %s", legacyProvisionerPod)
+		}
+		// wait for it to stop provisioning before the replicas start
+		gone := func() (bool, error) {
+			_, err := pods.Get(ctx, legacyProvisionerPod, meta.GetOptions{})
+			return apierrors.IsNotFound(err), nil
+		}
+		if err := wait.PollImmediate(time.Second, time.Minute, gone); err != nil {
+			return errors.Wrapf(err, "waiting for pod %s to be deleted", legacyProvisionerPod)
+		}
+	}
+
+	err = client.CoreV1().Endpoints("kube-system").Delete(ctx, legacyProvisionerLock, meta.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "deleting endpoints %s", legacyProvisionerLock)
+	}
+	return nil
+}
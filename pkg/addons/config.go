@@ -124,6 +124,11 @@ var Addons = []*Addon{
 		set:       SetBool,
 		callbacks: []setFn{EnableOrDisableAddon},
 	},
+	{
+		name:      "amd-gpu-device-plugin",
+		set:       SetBool,
+		callbacks: []setFn{EnableOrDisableAddon},
+	},
 	{
 		name:      "olm",
 		set:       SetBool,
@@ -134,10 +139,15 @@ var Addons = []*Addon{
 		set:       SetBool,
 		callbacks: []setFn{EnableOrDisableAddon, verifyAddonStatus},
 	},
+	{
+		name:      "registry-mirror",
+		set:       SetBool,
+		callbacks: []setFn{EnableOrDisableAddon, enableOrDisableRegistryMirror},
+	},
 	{
 		name:      "registry-creds",
 		set:       SetBool,
-		callbacks: []setFn{EnableOrDisableAddon},
+		callbacks: []setFn{EnableOrDisableAddon, applyRegistryCreds},
 	},
 	{
 		name:      "registry-aliases",
@@ -149,7 +159,7 @@ var Addons = []*Addon{
 	{
 		name:      "storage-provisioner",
 		set:       SetBool,
-		callbacks: []setFn{EnableOrDisableAddon},
+		callbacks: []setFn{removeLegacyStorageProvisioner, EnableOrDisableAddon},
 	},
 	{
 		name:      "storage-provisioner-gluster",
@@ -0,0 +1,300 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/util/lock"
+)
+
+const registryCredsAddon = "registry-creds"
+
+// configurableAddons lists the settings of the built-in addons which can be set with 'minikube addons configure'
+var configurableAddons = map[string][]string{
+	registryCredsAddon: {
+		"awsAccessID", "awsAccessKey", "awsSessionToken", "awsRegion", "awsAccount", "awsRole",
+		"gcrApplicationDefaultCredentials", "gcrURL",
+		"dockerServer", "dockerUser", "dockerPass",
+		"acrURL", "acrClientID", "acrPassword",
+	},
+	"metallb": {"loadBalancerStartIP", "loadBalancerEndIP"},
+	"ingress": {"customCert"},
+}
+
+// secretSettings are the settings which hold credentials. They are stored in a
+// separate file readable only by the user, rather than in the cluster config.
+var secretSettings = map[string][]string{
+	registryCredsAddon: {"awsAccessKey", "awsSessionToken", "gcrApplicationDefaultCredentials", "dockerPass", "acrPassword"},
+}
+
+var customCertFormat = regexp.MustCompile("^.+/.+$")
+
+// Settings returns the settings of an addon which can be set with 'minikube addons configure'
+func Settings(name string) []string {
+	if s, ok := configurableAddons[name]; ok {
+		return s
+	}
+	a, ok := assets.Addons[name]
+	if !ok || a.Bundle == nil {
+		return nil
+	}
+	var s []string
+	for _, p := range a.Bundle.Parameters {
+		s = append(s, p.Name)
+	}
+	return s
+}
+
+// Configure validates and stores the given settings of an addon, on top of the ones stored before,
+// and applies them to the cluster if the addon is enabled. The stored settings are applied again
+// every time the addon is enabled, including when the cluster is started.
+func Configure(cc *config.ClusterConfig, name string, values map[string]string) error {
+	settings := Settings(name)
+	if len(settings) == 0 {
+		return errors.Errorf("%s has no available configuration options", name)
+	}
+	for k, v := range values {
+		if !contains(settings, k) {
+			return errors.Errorf("unknown setting %q for addon %s, valid settings are: %s", k, name, strings.Join(settings, ", "))
+		}
+		if err := validateSetting(name, k, v); err != nil {
+			return err
+		}
+	}
+
+	secrets, err := loadSecrets(cc.Name)
+	if err != nil {
+		return err
+	}
+	if secrets[name] == nil {
+		secrets[name] = map[string]string{}
+	}
+	stored := map[string]string{}
+	for k, v := range cc.AddonValues[name] {
+		stored[k] = v
+	}
+	for k, v := range values {
+		stored[k] = v
+	}
+	// also moves secrets stored in the cluster config by earlier versions
+	for k, v := range stored {
+		if contains(secretSettings[name], k) {
+			secrets[name][k] = v
+			delete(stored, k)
+		}
+	}
+	if err := saveSecrets(cc.Name, secrets); err != nil {
+		return err
+	}
+	if cc.AddonValues == nil {
+		cc.AddonValues = map[string]map[string]string{}
+	}
+	cc.AddonValues[name] = stored
+
+	// these addons read their settings from the cluster config when their manifests are generated
+	switch name {
+	case "metallb":
+		cc.KubernetesConfig.LoadBalancerStartIP = stored["loadBalancerStartIP"]
+		cc.KubernetesConfig.LoadBalancerEndIP = stored["loadBalancerEndIP"]
+	case "ingress":
+		cc.KubernetesConfig.CustomIngressCert = stored["customCert"]
+	}
+
+	if err := config.Write(cc.Name, cc); err != nil {
+		return errors.Wrap(err, "saving config")
+	}
+
+	if !assets.Addons[name].IsEnabled(cc) {
+		klog.Infof("addon %s is not enabled, its settings will be applied when it is", name)
+		return nil
+	}
+	if name == registryCredsAddon {
+		return applyRegistryCreds(cc, name, "true")
+	}
+	// re-enable the addon to generate its manifests with the new settings
+	return EnableOrDisableAddon(cc, name, "true")
+}
+
+// ParseSettings parses settings given as key=value
+func ParseSettings(pairs []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, p := range pairs {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("invalid setting %q, expected key=value", p)
+		}
+		values[kv[0]] = kv[1]
+	}
+	return values, nil
+}
+
+// SettingsFromMap converts the values of a settings file, which may be numbers or booleans, to strings
+func SettingsFromMap(m map[string]interface{}) map[string]string {
+	values := map[string]string{}
+	for k, v := range m {
+		if v == nil {
+			values[k] = ""
+			continue
+		}
+		values[k] = fmt.Sprint(v)
+	}
+	return values
+}
+
+func validateSetting(addon, key, value string) error {
+	switch {
+	case addon == "metallb" && net.ParseIP(value) == nil:
+		return errors.Errorf("%s must be an IP address, got %q", key, value)
+	case addon == "ingress" && !customCertFormat.MatchString(value):
+		return errors.Errorf("%s must have the format namespace/secret, got %q", key, value)
+	}
+	return nil
+}
+
+// applyRegistryCreds creates the secrets registry-creds reads its credentials from
+func applyRegistryCreds(cc *config.ClusterConfig, name string, val string) error {
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrapf(err, "parsing bool: %s", name)
+	}
+	if !enable {
+		return nil
+	}
+	secrets, err := loadSecrets(cc.Name)
+	if err != nil {
+		return err
+	}
+	stored := map[string]string{}
+	for k, v := range cc.AddonValues[registryCredsAddon] {
+		stored[k] = v
+	}
+	for k, v := range secrets[registryCredsAddon] {
+		stored[k] = v
+	}
+	if len(stored) == 0 {
+		return nil
+	}
+	v := func(key string) string {
+		if s, ok := stored[key]; ok {
+			return s
+		}
+		// the placeholders registry-creds has always been configured with for registries not in use
+		if key == "awsSessionToken" {
+			return ""
+		}
+		if key == "gcrURL" {
+			return "https://gcr.io"
+		}
+		return "changeme"
+	}
+
+	secrets = map[string]map[string]string{
+		"ecr": {
+			"AWS_ACCESS_KEY_ID":     v("awsAccessID"),
+			"AWS_SECRET_ACCESS_KEY": v("awsAccessKey"),
+			"AWS_SESSION_TOKEN":     v("awsSessionToken"),
+			"aws-account":           v("awsAccount"),
+			"aws-region":            v("awsRegion"),
+			"aws-assume-role":       v("awsRole"),
+		},
+		"gcr": {
+			"application_default_credentials.json": v("gcrApplicationDefaultCredentials"),
+			"gcrurl":                               v("gcrURL"),
+		},
+		"dpr": {
+			"DOCKER_PRIVATE_REGISTRY_SERVER":   v("dockerServer"),
+			"DOCKER_PRIVATE_REGISTRY_USER":     v("dockerUser"),
+			"DOCKER_PRIVATE_REGISTRY_PASSWORD": v("dockerPass"),
+		},
+		"acr": {
+			"ACR_URL":       v("acrURL"),
+			"ACR_CLIENT_ID": v("acrClientID"),
+			"ACR_PASSWORD":  v("acrPassword"),
+		},
+	}
+	clouds := make([]string, 0, len(secrets))
+	for c := range secrets {
+		clouds = append(clouds, c)
+	}
+	sort.Strings(clouds)
+
+	var errs []string
+	for _, c := range clouds {
+		err := service.CreateSecret(cc.Name, "kube-system", "registry-creds-"+c, secrets[c], map[string]string{
+			"app":                           registryCredsAddon,
+			"cloud":                         c,
+			"kubernetes.io/minikube-addons": registryCredsAddon,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("registry-creds-%s: %v", c, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("creating secrets: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// loadSecrets returns the secret settings of the addons of a profile, by addon
+func loadSecrets(profile string) (map[string]map[string]string, error) {
+	secrets := map[string]map[string]string{}
+	b, err := ioutil.ReadFile(localpath.AddonSecrets(profile))
+	if os.IsNotExist(err) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading addon secrets")
+	}
+	if err := json.Unmarshal(b, &secrets); err != nil {
+		return nil, errors.Wrap(err, "parsing addon secrets")
+	}
+	return secrets, nil
+}
+
+// saveSecrets writes the secret settings of the addons of a profile, readable only by the user
+func saveSecrets(profile string, secrets map[string]map[string]string) error {
+	b, err := json.MarshalIndent(secrets, "", "    ")
+	if err != nil {
+		return err
+	}
+	path := localpath.AddonSecrets(profile)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := lock.WriteFile(path, b, 0600); err != nil {
+		return errors.Wrap(err, "writing addon secrets")
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, 0600)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		pairs    []string
+		expected map[string]string
+		err      bool
+	}{
+		{[]string{"loadBalancerStartIP=192.168.49.100", "loadBalancerEndIP=192.168.49.120"}, map[string]string{"loadBalancerStartIP": "192.168.49.100", "loadBalancerEndIP": "192.168.49.120"}, false},
+		{[]string{"dockerPass=a=b"}, map[string]string{"dockerPass": "a=b"}, false},
+		{[]string{"awsSessionToken="}, map[string]string{"awsSessionToken": ""}, false},
+		{[]string{"dockerPass"}, nil, true},
+		{[]string{"=value"}, nil, true},
+	}
+	for _, tc := range tests {
+		got, err := ParseSettings(tc.pairs)
+		if (err != nil) != tc.err {
+			t.Errorf("ParseSettings(%v) error = %v, expected error: %v", tc.pairs, err, tc.err)
+			continue
+		}
+		if !tc.err && !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("ParseSettings(%v) = %v, expected %v", tc.pairs, got, tc.expected)
+		}
+	}
+}
+
+func TestSettingsFromMap(t *testing.T) {
+	got := SettingsFromMap(map[string]interface{}{"replicas": 2, "debug": true, "name": "x", "empty": nil})
+	expected := map[string]string{"replicas": "2", "debug": "true", "name": "x", "empty": ""}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("SettingsFromMap() = %v, expected %v", got, expected)
+	}
+}
+
+func TestValidateSetting(t *testing.T) {
+	tests := []struct {
+		addon string
+		key   string
+		value string
+		err   bool
+	}{
+		{"metallb", "loadBalancerStartIP", "192.168.49.100", false},
+		{"metallb", "loadBalancerStartIP", "192.168.49", true},
+		{"ingress", "customCert", "kube-system/mkcert", false},
+		{"ingress", "customCert", "mkcert", true},
+		{"registry-creds", "dockerPass", "", false},
+	}
+	for _, tc := range tests {
+		if err := validateSetting(tc.addon, tc.key, tc.value); (err != nil) != tc.err {
+			t.Errorf("validateSetting(%s, %s, %q) error = %v, expected error: %v", tc.addon, tc.key, tc.value, err, tc.err)
+		}
+	}
+}
+
+func TestConfigureKeepsSecretsOutOfConfig(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "addons")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, tmp)
+
+	// a secret stored in the cluster config by an earlier version
+	cc := &config.ClusterConfig{Name: "p1", AddonValues: map[string]map[string]string{
+		registryCredsAddon: {"awsAccessKey": "old-key"},
+	}}
+	if err := Configure(cc, registryCredsAddon, map[string]string{"dockerUser": "me", "dockerPass": "hunter2"}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	if want := map[string]string{"dockerUser": "me"}; !reflect.DeepEqual(cc.AddonValues[registryCredsAddon], want) {
+		t.Errorf("stored settings = %v, expected %v", cc.AddonValues[registryCredsAddon], want)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(localpath.Profile("p1"), "config.json"))
+	if err != nil {
+		t.Fatalf("reading config: %v", err)
+	}
+	for _, secret := range []string{"hunter2", "old-key"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("config.json contains secret %q", secret)
+		}
+	}
+
+	secrets, err := loadSecrets("p1")
+	if err != nil {
+		t.Fatalf("loadSecrets: %v", err)
+	}
+	if want := map[string]string{"dockerPass": "hunter2", "awsAccessKey": "old-key"}; !reflect.DeepEqual(secrets[registryCredsAddon], want) {
+		t.Errorf("secrets = %v, expected %v", secrets[registryCredsAddon], want)
+	}
+	fi, err := os.Stat(localpath.AddonSecrets("p1"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("secrets file mode = %#o, expected 0600", fi.Mode().Perm())
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// Addon health states
+const (
+	// HealthRunning means all the pods of the addon are ready
+	HealthRunning = "Running"
+	// HealthNotReady means some pods of the addon are not ready
+	HealthNotReady = "NotReady"
+	// HealthEnabled means the addon is enabled, but has no pods to check
+	HealthEnabled = "Enabled"
+)
+
+// Health returns the health of the enabled addons of a cluster, from the pods running their images
+func Health(cc *config.ClusterConfig, pods []v1.Pod) map[string]string {
+	names := []string{}
+	for name, enabled := range cc.Addons {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	health := map[string]string{}
+	for _, name := range names {
+		health[name] = HealthEnabled
+		addon, ok := assets.Addons[name]
+		if !ok {
+			continue
+		}
+		for _, p := range pods {
+			if !runsImages(p, addon.Images) {
+				continue
+			}
+			if !podReady(p) {
+				health[name] = HealthNotReady
+				break
+			}
+			health[name] = HealthRunning
+		}
+	}
+	return health
+}
+
+// runsImages returns whether a pod runs one of the images of an addon
+func runsImages(p v1.Pod, images map[string]string) bool {
+	for _, c := range p.Spec.Containers {
+		for _, img := range images {
+			// the registry of the image may be overridden, so only its repository has to match
+			if strings.HasSuffix(imageRepository(c.Image), "/"+imageRepository(img)) || imageRepository(c.Image) == imageRepository(img) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// imageRepository returns the name of an image without its tag or digest
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// a ':' after the last '/' is a tag, before it a registry port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// podReady returns whether a pod is running with all its containers ready, or has completed
+func podReady(p v1.Pod) bool {
+	if p.Status.Phase == v1.PodSucceeded {
+		return true
+	}
+	if p.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, c := range p.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
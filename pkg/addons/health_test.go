@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func testPod(image string, phase v1.PodPhase, ready bool) v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return v1.Pod{
+		Spec: v1.PodSpec{Containers: []v1.Container{{Image: image}}},
+		Status: v1.PodStatus{
+			Phase:      phase,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestHealth(t *testing.T) {
+	cc := &config.ClusterConfig{Addons: map[string]bool{"dashboard": true, "default-storageclass": true, "ingress": false}}
+	tests := []struct {
+		name string
+		pods []v1.Pod
+		want map[string]string
+	}{
+		{
+			name: "ready",
+			pods: []v1.Pod{
+				testPod("docker.io/kubernetesui/dashboard:v2.1.0", v1.PodRunning, true),
+				testPod("kubernetesui/metrics-scraper:v1.0.4@sha256:555981a24f184420f3be0c79d4efb6c948a85cfce84034f85a563f4151a81cbf", v1.PodRunning, true),
+			},
+			want: map[string]string{"dashboard": HealthRunning, "default-storageclass": HealthEnabled},
+		},
+		{
+			name: "not ready",
+			pods: []v1.Pod{
+				testPod("kubernetesui/dashboard:v2.1.0", v1.PodRunning, true),
+				testPod("localhost:5000/kubernetesui/metrics-scraper:v1.0.4", v1.PodPending, false),
+			},
+			want: map[string]string{"dashboard": HealthNotReady, "default-storageclass": HealthEnabled},
+		},
+		{
+			name: "no pods",
+			pods: []v1.Pod{testPod("k8s.gcr.io/coredns:1.7.0", v1.PodRunning, true)},
+			want: map[string]string{"dashboard": HealthEnabled, "default-storageclass": HealthEnabled},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Health(cc, tc.pods)); diff != "" {
+				t.Errorf("Health() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestImageRepository(t *testing.T) {
+	tests := map[string]string{
+		"kubernetesui/dashboard:v2.1.0@sha256:7f80b5ba141bead69c4fee8661464857af300d7d7ed0274cf7beecedc00322e6": "kubernetesui/dashboard",
+		"localhost:5000/registry:2.7.1": "localhost:5000/registry",
+		"localhost:5000/registry":       "localhost:5000/registry",
+		"busybox":                       "busybox",
+	}
+	for image, want := range tests {
+		if got := imageRepository(image); got != want {
+			t.Errorf("imageRepository(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
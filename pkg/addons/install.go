@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+// ociScheme prefixes the references of addon bundles stored in OCI registries
+const ociScheme = "oci://"
+
+// RegisterInstalled makes the installed addon bundles available like the built-in addons
+func RegisterInstalled() {
+	for _, n := range assets.RegisterInstalledAddons() {
+		if _, ok := isAddonValid(n); ok {
+			continue
+		}
+		Addons = append(Addons, &Addon{
+			name:      n,
+			set:       SetBool,
+			callbacks: []setFn{EnableOrDisableAddon},
+		})
+	}
+}
+
+// Install fetches the addon bundle at source, an oci:// reference, and installs it.
+// The bundle has to be signed with the private key matching the public key at keyPath,
+// unless allowUnsigned is set.
+func Install(source, keyPath string, allowUnsigned bool) (*assets.AddonBundle, error) {
+	if !strings.HasPrefix(source, ociScheme) {
+		return nil, errors.Errorf("unsupported addon source %q, expected an %s reference", source, ociScheme)
+	}
+	if keyPath == "" && !allowUnsigned {
+		return nil, errors.New("a public key is required to verify the addon bundle")
+	}
+	ref, err := name.ParseReference(strings.TrimPrefix(source, ociScheme))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", source)
+	}
+
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s", source)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, errors.Wrap(err, "digest")
+	}
+
+	signed := false
+	if keyPath != "" {
+		if err := verifyImageSignature(ref, digest, keyPath); err != nil {
+			return nil, errors.Wrapf(err, "verifying signature of %s", source)
+		}
+		signed = true
+	}
+
+	// extract next to the installed addons, so that the bundle can be moved in place once it is complete
+	if err := os.MkdirAll(assets.InstalledAddonsDir(), 0755); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+	tmp, err := ioutil.TempDir(assets.InstalledAddonsDir(), ".install-")
+	if err != nil {
+		return nil, errors.Wrap(err, "tempdir")
+	}
+	defer os.RemoveAll(tmp)
+
+	rc := mutate.Extract(img)
+	defer rc.Close()
+	if err := extractBundle(rc, tmp); err != nil {
+		return nil, errors.Wrapf(err, "extracting %s", source)
+	}
+
+	b, err := assets.ReadAddonBundle(tmp)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := assets.Addons[b.Name]; ok && existing.Bundle == nil {
+		return nil, errors.Errorf("addon %s conflicts with a built-in addon", b.Name)
+	}
+	b.Source = source
+	b.Digest = digest.String()
+	b.Signed = signed
+	if err := assets.WriteAddonBundle(tmp, b); err != nil {
+		return nil, errors.Wrap(err, "writing addon bundle")
+	}
+	if _, err := assets.LoadAddonBundle(tmp); err != nil {
+		return nil, err
+	}
+
+	dst := filepath.Join(assets.InstalledAddonsDir(), b.Name)
+	if err := os.RemoveAll(dst); err != nil {
+		return nil, errors.Wrap(err, "removing previous version")
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return nil, errors.Wrap(err, "rename")
+	}
+	klog.Infof("installed addon %s %s from %s@%s", b.Name, b.Version, source, b.Digest)
+	return b, nil
+}
+
+// Uninstall removes an installed addon bundle
+func Uninstall(addonName string) error {
+	a, ok := assets.Addons[addonName]
+	if !ok || a.Bundle == nil {
+		return errors.Errorf("%s is not an installed addon", addonName)
+	}
+	return os.RemoveAll(filepath.Join(assets.InstalledAddonsDir(), addonName))
+}
+
+// extractBundle writes the addon.yaml and manifests of a bundle to dir, ignoring any other file
+func extractBundle(r io.Reader, dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, assets.AddonManifestsDir), 0755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		p := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		if p != assets.AddonBundleFile && path.Dir(p) != assets.AddonManifestsDir {
+			klog.Infof("ignoring %s in addon bundle", hdr.Name)
+			continue
+		}
+		f, err := os.OpenFile(filepath.Join(dir, filepath.FromSlash(p)), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+}
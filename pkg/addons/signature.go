@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// Addon bundles are signed the way 'cosign sign --key' signs images: the signatures are
+// stored as layers of the <repo>:sha256-<digest>.sig image, the layer holding the signed
+// payload and its annotation the signature.
+const signatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// signaturePayload is the part of the signed payload identifying the signed image
+type signaturePayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// verifyImageSignature checks that the image at ref with the given digest is signed with the key at keyPath
+func verifyImageSignature(ref name.Reference, digest v1.Hash, keyPath string) error {
+	key, err := loadPublicKey(keyPath)
+	if err != nil {
+		return err
+	}
+
+	sigRef := ref.Context().Tag(fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex))
+	sigImg, err := remote.Image(sigRef, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return errors.Wrapf(err, "fetching signatures %s", sigRef)
+	}
+	m, err := sigImg.Manifest()
+	if err != nil {
+		return errors.Wrap(err, "signatures manifest")
+	}
+
+	for _, l := range m.Layers {
+		sig, ok := l.Annotations[signatureAnnotation]
+		if !ok {
+			continue
+		}
+		layer, err := sigImg.LayerByDigest(l.Digest)
+		if err != nil {
+			return errors.Wrap(err, "signature layer")
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return errors.Wrap(err, "signature payload")
+		}
+		payload, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return errors.Wrap(err, "reading signature payload")
+		}
+		if err := verifySignature(key, payload, sig, digest); err != nil {
+			klog.Infof("signature %s does not match: %v", l.Digest, err)
+			continue
+		}
+		return nil
+	}
+	return errors.Errorf("no signature of %s matches the public key %s", digest, keyPath)
+}
+
+// verifySignature checks that sig is the signature of payload, and that the payload is about digest
+func verifySignature(key *ecdsa.PublicKey, payload []byte, sig string, digest v1.Hash) error {
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return errors.Wrap(err, "decoding signature")
+	}
+	h := sha256.Sum256(payload)
+	if !ecdsa.VerifyASN1(key, h[:], raw) {
+		return errors.New("invalid signature")
+	}
+
+	var p signaturePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return errors.Wrap(err, "parsing signature payload")
+	}
+	if p.Critical.Image.DockerManifestDigest != digest.String() {
+		return errors.Errorf("signature is for %s", p.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
+
+// loadPublicKey reads a PEM encoded ECDSA public key, as generated by 'cosign generate-key-pair'
+func loadPublicKey(path string) (*ecdsa.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading public key")
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("%s is not a PEM encoded public key", path)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing public key")
+	}
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("%s is not an ECDSA public key", path)
+	}
+	return key, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+func TestVerifySignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	digest := v1.Hash{Algorithm: "sha256", Hex: "8d1a1f4ab0d8f0fa2f58a4b0ae5c6c3bbd3f5e2b9e5e8aa0c3f6b5b9b7b1c2d3"}
+	payload := func(d string) []byte {
+		return []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"ghcr.io/acme/my-addon"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, d))
+	}
+	sign := func(k *ecdsa.PrivateKey, p []byte) string {
+		h := sha256.Sum256(p)
+		sig, err := ecdsa.SignASN1(rand.Reader, k, h[:])
+		if err != nil {
+			t.Fatalf("signing: %v", err)
+		}
+		return base64.StdEncoding.EncodeToString(sig)
+	}
+
+	tests := []struct {
+		description string
+		payload     []byte
+		sig         string
+		valid       bool
+	}{
+		{"valid", payload(digest.String()), sign(key, payload(digest.String())), true},
+		{"other key", payload(digest.String()), sign(other, payload(digest.String())), false},
+		{"other image", payload("sha256:0000"), sign(key, payload("sha256:0000")), false},
+		{"tampered payload", payload("sha256:0000"), sign(key, payload(digest.String())), false},
+		{"not base64", payload(digest.String()), "!", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := verifySignature(&key.PublicKey, tc.payload, tc.sig, digest)
+			if tc.valid && err != nil {
+				t.Errorf("expected valid signature, got %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Errorf("expected invalid signature")
+			}
+		})
+	}
+}
@@ -424,7 +424,7 @@ func (d *Driver) extractKernel(isoPath string) error {
 		{"/isolinux/isolinux.cfg", "isolinux.cfg"},
 	} {
 		fullDestPath := d.ResolveStorePath(f.destPath)
-		if err := ExtractFile(isoPath, f.pathInIso, fullDestPath); err != nil {
+		if err := pkgdrivers.ExtractFile(isoPath, f.pathInIso, fullDestPath); err != nil {
 			return err
 		}
 	}
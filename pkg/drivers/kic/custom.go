@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+)
+
+// The labels recording the provenance of the base images built with 'minikube build-base'
+const (
+	// LabelBaseImage is the image a custom base image was built from
+	LabelBaseImage = "io.k8s.minikube.base-image"
+	// LabelKicVersion is the version of kicbase a custom base image derives from
+	LabelKicVersion = "io.k8s.minikube.kic-version"
+	// LabelMinikubeVersion is the version of minikube which built a custom base image
+	LabelMinikubeVersion = "io.k8s.minikube.version"
+	// LabelPackages are the packages added to a custom base image
+	LabelPackages = "io.k8s.minikube.packages"
+)
+
+// caDir is where CA certificates to be trusted by the node are added
+const caDir = "/usr/local/share/ca-certificates"
+
+// kicTag matches the tags of kicbase releases
+var kicTag = regexp.MustCompile(`^v\d+\.\d+\.\d+$`)
+
+// CustomFile is a file added to a custom base image
+type CustomFile struct {
+	// Src is the path to the file on the host
+	Src string
+	// Dst is the absolute path to the file in the image
+	Dst string
+}
+
+// CustomBase is a base image customized with 'minikube build-base'
+type CustomBase struct {
+	// From is the base image it is built from, such as BaseImage
+	From     string
+	Packages []string
+	Files    []CustomFile
+}
+
+// KicVersion returns the version of kicbase of the tag of an image, or "" if the tag is not the one of a kicbase release
+func KicVersion(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	i := strings.LastIndex(image, ":")
+	if i == -1 || i < strings.LastIndex(image, "/") {
+		return ""
+	}
+	if tag := image[i+1:]; kicTag.MatchString(tag) {
+		return tag
+	}
+	return ""
+}
+
+// Dockerfile returns the Dockerfile of a custom base image, its build context holding the files added under files/<index>/.
+// Images inherit the labels of the image they are built from, so the kicbase version of a custom base image built from
+// another one is kept when the tag of the latter does not tell it.
+func (b CustomBase) Dockerfile(minikubeVersion string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "FROM %s\n", b.From)
+	if len(b.Packages) > 0 {
+		fmt.Fprintf(&sb, "RUN clean-install %s\n", strings.Join(b.Packages, " "))
+	}
+	cas := false
+	for i, f := range b.Files {
+		fmt.Fprintf(&sb, "COPY %s %s\n", path.Join("files", strconv.Itoa(i), filepath.Base(f.Src)), f.Dst)
+		if strings.HasPrefix(f.Dst, caDir+"/") {
+			cas = true
+		}
+	}
+	if cas {
+		sb.WriteString("RUN update-ca-certificates\n")
+	}
+
+	labels := []string{
+		fmt.Sprintf("%s=%s", LabelBaseImage, strconv.Quote(b.From)),
+		fmt.Sprintf("%s=%s", LabelMinikubeVersion, strconv.Quote(minikubeVersion)),
+		fmt.Sprintf("%s=%s", LabelPackages, strconv.Quote(strings.Join(b.Packages, " "))),
+	}
+	if v := KicVersion(b.From); v != "" {
+		labels = append(labels, fmt.Sprintf("%s=%s", LabelKicVersion, strconv.Quote(v)))
+	}
+	fmt.Fprintf(&sb, "LABEL %s\n", strings.Join(labels, " \\\n      "))
+	return sb.String()
+}
+
+// BuildCustomBase builds a custom base image with docker or podman, and tags it
+func BuildCustomBase(ociBin string, tag string, b CustomBase, minikubeVersion string) error {
+	dir, err := ioutil.TempDir("", "minikube-build-base")
+	if err != nil {
+		return errors.Wrap(err, "creating build context")
+	}
+	defer os.RemoveAll(dir)
+
+	for i, f := range b.Files {
+		data, err := ioutil.ReadFile(f.Src)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", f.Src)
+		}
+		fdir := filepath.Join(dir, "files", strconv.Itoa(i))
+		if err := os.MkdirAll(fdir, 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(fdir, filepath.Base(f.Src)), data, 0644); err != nil {
+			return errors.Wrapf(err, "copying %s", f.Src)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(b.Dockerfile(minikubeVersion)), 0644); err != nil {
+		return errors.Wrap(err, "writing Dockerfile")
+	}
+	return oci.BuildImage(ociBin, dir, tag)
+}
+
+// CustomBaseOutdated returns the version of kicbase a custom base image derives from, according to its labels,
+// and whether it is older than the one of this minikube. Images without provenance labels are never outdated.
+func CustomBaseOutdated(labels map[string]string) (string, bool) {
+	v := labels[LabelKicVersion]
+	built, err := semver.ParseTolerant(v)
+	if err != nil {
+		return v, false
+	}
+	current, err := semver.ParseTolerant(Version)
+	if err != nil {
+		return v, false
+	}
+	return v, built.LT(current)
+}
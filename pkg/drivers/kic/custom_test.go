@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kic
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKicVersion(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"gcr.io/k8s-minikube/kicbase:v0.0.23", "v0.0.23"},
+		{"gcr.io/k8s-minikube/kicbase:v0.0.23@sha256:baf6d94a2050a115eb9c5a4d8a1d7d5d1a5d0dbc7b38a4a0ce1b4c3a64e2e1e7", "v0.0.23"},
+		{"localhost:5000/kicbase:v0.0.22", "v0.0.22"},
+		{"localhost:5000/kicbase", ""},
+		{"my-kicbase:latest", ""},
+		{"my-kicbase", ""},
+	}
+	for _, tc := range tests {
+		if got := KicVersion(tc.image); got != tc.want {
+			t.Errorf("KicVersion(%q) = %q, want %q", tc.image, got, tc.want)
+		}
+	}
+}
+
+func TestDockerfile(t *testing.T) {
+	b := CustomBase{
+		From:     "gcr.io/k8s-minikube/kicbase:v0.0.23",
+		Packages: []string{"nfs-common", "open-iscsi"},
+		Files: []CustomFile{
+			{Src: "/home/me/corp.crt", Dst: "/usr/local/share/ca-certificates/corp.crt"},
+			{Src: "/home/me/limits.conf", Dst: "/etc/security/limits.conf"},
+		},
+	}
+	got := b.Dockerfile("v1.21.0")
+	for _, want := range []string{
+		"FROM gcr.io/k8s-minikube/kicbase:v0.0.23\n",
+		"RUN clean-install nfs-common open-iscsi\n",
+		"COPY files/0/corp.crt /usr/local/share/ca-certificates/corp.crt\n",
+		"COPY files/1/limits.conf /etc/security/limits.conf\n",
+		"RUN update-ca-certificates\n",
+		`io.k8s.minikube.base-image="gcr.io/k8s-minikube/kicbase:v0.0.23"`,
+		`io.k8s.minikube.version="v1.21.0"`,
+		`io.k8s.minikube.packages="nfs-common open-iscsi"`,
+		`io.k8s.minikube.kic-version="v0.0.23"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Dockerfile does not contain %q:\n%s", want, got)
+		}
+	}
+
+	got = CustomBase{From: "my-kicbase:latest"}.Dockerfile("v1.21.0")
+	for _, unwanted := range []string{"clean-install", "COPY", "update-ca-certificates", LabelKicVersion} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("Dockerfile contains %q:\n%s", unwanted, got)
+		}
+	}
+}
+
+func TestCustomBaseOutdated(t *testing.T) {
+	tests := []struct {
+		description string
+		labels      map[string]string
+		outdated    bool
+	}{
+		{"no labels", nil, false},
+		{"current", map[string]string{LabelKicVersion: Version}, false},
+		{"older", map[string]string{LabelKicVersion: "v0.0.1"}, true},
+		{"newer", map[string]string{LabelKicVersion: "v99.0.0"}, false},
+		{"invalid", map[string]string{LabelKicVersion: "latest"}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if _, got := CustomBaseOutdated(tc.labels); got != tc.outdated {
+				t.Errorf("CustomBaseOutdated(%v) = %v, want %v", tc.labels, got, tc.outdated)
+			}
+		})
+	}
+}
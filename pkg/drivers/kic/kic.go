@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -44,6 +46,8 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/network"
 	"k8s.io/minikube/pkg/util/retry"
 )
 
@@ -86,35 +90,63 @@ func (d *Driver) Create() error {
 		ExtraArgs:     append([]string{"--expose", fmt.Sprintf("%d", d.NodeConfig.APIServerPort)}, d.NodeConfig.ExtraArgs...),
 		OCIBinary:     d.NodeConfig.OCIBinary,
 		APIServerPort: d.NodeConfig.APIServerPort,
+		GPUs:          d.NodeConfig.GPUs,
 	}
 
 	networkName := d.NodeConfig.Network
 	if networkName == "" {
 		networkName = d.NodeConfig.ClusterName
 	}
-	if gateway, err := oci.CreateNetwork(d.OCIBinary, networkName); err != nil {
+	ipv6 := d.NodeConfig.IPFamily == constants.IPFamilyIPv6 || d.NodeConfig.IPFamily == constants.IPFamilyDual
+	if gateway, err := oci.CreateNetwork(d.OCIBinary, networkName, ipv6); err != nil {
+		if ipv6 {
+			return errors.Wrap(err, "creating dual-stack network")
+		}
 		out.WarningT("Unable to create dedicated network, this might result in cluster IP change after restart: {{.error}}", out.V{"error": err})
 	} else if gateway != nil {
 		params.Network = networkName
-		ip := gateway.To4()
-		// calculate the container IP based on guessing the machine index
-		index := driver.IndexFromMachineName(d.NodeConfig.MachineName)
-		if int(ip[3])+index > 255 {
-			return fmt.Errorf("too many machines to calculate an IP")
+		if d.NodeConfig.StaticIP != "" {
+			klog.Infof("using static IP %q for the %q container", d.NodeConfig.StaticIP, d.NodeConfig.MachineName)
+			params.IP = d.NodeConfig.StaticIP
+		} else {
+			ip := gateway.To4()
+			// calculate the container IP based on guessing the machine index
+			index := driver.IndexFromMachineName(d.NodeConfig.MachineName)
+			if int(ip[3])+index > 255 {
+				return fmt.Errorf("too many machines to calculate an IP")
+			}
+			ip[3] += byte(index)
+			klog.Infof("calculated static IP %q for the %q container", ip.String(), d.NodeConfig.MachineName)
+			params.IP = ip.String()
+		}
+		if ipv6 {
+			if params.IPv6, err = network.IPv6ForIPv4(params.IP); err != nil {
+				return errors.Wrap(err, "calculating IPv6 address")
+			}
+			klog.Infof("calculated static IPv6 %q for the %q container", params.IPv6, d.NodeConfig.MachineName)
 		}
-		ip[3] += byte(index)
-		klog.Infof("calculated static IP %q for the %q container", ip.String(), d.NodeConfig.MachineName)
-		params.IP = ip.String()
 	}
 	drv := d.DriverName()
 
+	if !oci.IsExternalDaemonHost(drv) {
+		// keep the cache of the registry-mirror addon across deletes of the node. The storage of the
+		// registry is not safe for several writers, so every node has its own.
+		cacheDir := filepath.Join(constants.RegistryMirrorCacheDir, d.NodeConfig.MachineName)
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			klog.Warningf("unable to create %s: %v", cacheDir, err)
+		} else {
+			params.Mounts = append(params.Mounts, oci.Mount{HostPath: cacheDir, ContainerPath: vmpath.GuestRegistryMirrorDir})
+		}
+	}
+
 	listAddr := oci.DefaultBindIPV4
 	if d.NodeConfig.ListenAddress != "" && d.NodeConfig.ListenAddress != listAddr {
 		out.Step(style.Tip, "minikube is not meant for production use. You are opening non-local traffic")
 		out.WarningT("Listening to {{.listenAddr}}. This is not recommended and can cause a security vulnerability. Use at your own risk",
 			out.V{"listenAddr": d.NodeConfig.ListenAddress})
 		listAddr = d.NodeConfig.ListenAddress
-	} else if oci.IsExternalDaemonHost(drv) {
+	} else if oci.IsExternalDaemonHost(drv) && !oci.IsSSHDaemonHost(drv) {
+		// the ports of containers on a daemon reached over ssh stay on its loopback, minikube forwards them
 		out.WarningT("Listening to 0.0.0.0 on external docker host {{.host}}. Please be advised",
 			out.V{"host": oci.DaemonHost(drv)})
 		listAddr = "0.0.0.0"
@@ -175,6 +207,11 @@ func (d *Driver) Create() error {
 		if !download.PreloadExists(d.NodeConfig.KubernetesVersion, d.NodeConfig.ContainerRuntime, d.DriverName()) {
 			return
 		}
+		// the tarball can not be bind mounted into a container on another host
+		if oci.IsExternalDaemonHost(drv) {
+			klog.Infof("not extracting preloaded images to volume on external daemon host %s", oci.DaemonHost(drv))
+			return
+		}
 		t := time.Now()
 		klog.Infof("Starting extracting preloaded images to volume ...")
 		// Extract preloaded images to container
@@ -19,6 +19,10 @@ limitations under the License.
 package oci
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
 	"syscall"
 
 	"golang.org/x/sys/unix"
@@ -32,3 +36,15 @@ func IsCgroup2UnifiedMode() (bool, error) {
 	}
 	return st.Type == unix.CGROUP2_SUPER_MAGIC, nil
 }
+
+// DelegatedControllers returns the cgroup v2 controllers systemd delegates to the current user,
+// only those can be used by rootless containers.
+func DelegatedControllers() ([]string, error) {
+	uid := os.Getuid()
+	f := fmt.Sprintf("/sys/fs/cgroup/user.slice/user-%d.slice/user@%d.service/cgroup.controllers", uid, uid)
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(b)), nil
+}
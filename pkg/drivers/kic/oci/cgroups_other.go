@@ -28,3 +28,8 @@ import (
 func IsCgroup2UnifiedMode() (bool, error) {
 	return false, errors.Errorf("Not supported on %s", runtime.GOOS)
 }
+
+// DelegatedControllers returns the cgroup v2 controllers systemd delegates to the current user.
+func DelegatedControllers() ([]string, error) {
+	return nil, errors.Errorf("Not supported on %s", runtime.GOOS)
+}
@@ -54,6 +54,12 @@ var ErrDaemonInfo = errors.New("daemon info not responding")
 // ErrInsufficientDockerStorage is thrown when there is not more storage for docker
 var ErrInsufficientDockerStorage = &FailFastError{errors.New("insufficient docker storage, no space left on device")}
 
+// ErrRootlessCgroupV1 is thrown when running rootless on a host not using cgroup v2, which rootless containers need for resource limits
+var ErrRootlessCgroupV1 = &FailFastError{errors.New("rootless mode requires cgroup v2")}
+
+// ErrCgroupNotDelegated is thrown when systemd does not delegate the cgroup controllers needed by rootless containers to the user
+var ErrCgroupNotDelegated = &FailFastError{errors.New("cgroup controllers are not delegated to the user")}
+
 // ErrVolumeNotFound is when given volume was not found
 var ErrVolumeNotFound = errors.New("kic volume not found")
 
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// BuildImage builds the image described by the Dockerfile of a build context directory, and tags it
+func BuildImage(ociBin string, dir string, tag string) error {
+	cmd := PrefixCmd(exec.Command(ociBin, "build", "-t", tag, dir))
+	// the build takes minutes, show its progress
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	klog.Infof("Run: %v", cmd.Args)
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "%s build", ociBin)
+	}
+	return nil
+}
+
+// ImageLabels returns the labels of a local image
+func ImageLabels(ociBin string, image string) (map[string]string, error) {
+	rr, err := runCmd(exec.Command(ociBin, "image", "inspect", "--format", "{{json .Config.Labels}}", image))
+	if err != nil {
+		return nil, errors.Wrapf(err, "inspecting %s", image)
+	}
+	labels := map[string]string{}
+	out := strings.TrimSpace(rr.Stdout.String())
+	if out == "" || out == "null" {
+		return labels, nil
+	}
+	if err := json.Unmarshal([]byte(out), &labels); err != nil {
+		return nil, errors.Wrapf(err, "parsing the labels of %s", image)
+	}
+	return labels, nil
+}
+
+// LocalImage is an image of the local daemon
+type LocalImage struct {
+	ID         string
+	Repository string
+	Tag        string
+	Digest     string
+	Size       int64
+}
+
+// Name returns the name to remove the image by, its tag if it has one, otherwise its digest or ID
+func (i LocalImage) Name() string {
+	if i.Tag != "" && i.Tag != "<none>" {
+		return i.Repository + ":" + i.Tag
+	}
+	if i.Digest != "" && i.Digest != "<none>" {
+		return i.Repository + "@" + i.Digest
+	}
+	return i.ID
+}
+
+// ListImages returns the images of a repository in the local daemon
+func ListImages(ociBin string, repo string) ([]LocalImage, error) {
+	rr, err := runCmd(exec.Command(ociBin, "images", "--digests", "--no-trunc", "--format", "{{.ID}}\t{{.Repository}}\t{{.Tag}}\t{{.Digest}}\t{{.Size}}", repo))
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing images of %s", repo)
+	}
+	return parseLocalImages(rr.Stdout.String())
+}
+
+// parseLocalImages parses the images listed by ListImages
+func parseLocalImages(output string) ([]LocalImage, error) {
+	var imgs []LocalImage
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) != 5 {
+			continue
+		}
+		size, err := units.FromHumanSize(fields[4])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing the size of %s", fields[0])
+		}
+		imgs = append(imgs, LocalImage{ID: fields[0], Repository: fields[1], Tag: fields[2], Digest: fields[3], Size: size})
+	}
+	return imgs, nil
+}
+
+// RemoveImage removes an image from the local daemon
+func RemoveImage(ociBin string, image string) error {
+	if _, err := runCmd(exec.Command(ociBin, "rmi", image)); err != nil {
+		return errors.Wrapf(err, "removing %s", image)
+	}
+	return nil
+}
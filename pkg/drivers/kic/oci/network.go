@@ -52,6 +52,10 @@ func RoutableHostIPFromInside(ociBin string, clusterName string, containerName s
 	}
 	// podman
 	if runtime.GOOS == "linux" {
+		if IsRootlessForced() {
+			// the gateway of a rootless network lives in a user namespace, podman resolves the host itself instead
+			return hostsEntry(ociBin, containerName, "host.containers.internal")
+		}
 		return containerGatewayIP(ociBin, containerName)
 	}
 
@@ -70,6 +74,25 @@ func digDNS(ociBin, containerName, dns string) (net.IP, error) {
 	return ip, nil
 }
 
+// hostsEntry will get the IP of a host name from the /etc/hosts file of a container
+func hostsEntry(ociBin, containerName, name string) (net.IP, error) {
+	rr, err := runCmd(exec.Command(ociBin, "exec", containerName, "getent", "ahostsv4", name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolve %s", name)
+	}
+	fields := strings.Fields(rr.Stdout.String())
+	if len(fields) == 0 {
+		return nil, errors.Errorf("no address for %s", name)
+	}
+	ip := net.ParseIP(fields[0])
+	if ip == nil {
+		return nil, errors.Errorf("failed to parse address %q of %s", fields[0], name)
+	}
+
+	klog.Infof("got host ip for mount in container from its hosts file: %s", ip.String())
+	return ip, nil
+}
+
 // gatewayIP inspects oci container to find a gateway IP string
 func gatewayIP(ociBin, containerName string) (string, error) {
 	rr, err := runCmd(exec.Command(ociBin, "container", "inspect", "--format", "{{.NetworkSettings.Gateway}}", containerName))
@@ -182,6 +205,25 @@ func ContainerIPs(ociBin string, name string) (string, string, error) {
 	return dockerContainerIP(ociBin, name)
 }
 
+// NetworkContainerIPs returns the IPv4 addresses of the running containers on a network
+func NetworkContainerIPs(ociBin string, networkName string) ([]string, error) {
+	rr, err := runCmd(exec.Command(ociBin, "ps", "--filter", "network="+networkName, "--format", "{{.Names}}"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing the containers on %s", networkName)
+	}
+	var ips []string
+	for _, name := range strings.Fields(rr.Stdout.String()) {
+		lines, err := inspect(ociBin, name, "{{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}}")
+		if err != nil {
+			return nil, errors.Wrapf(err, "inspecting the networks of %s", name)
+		}
+		for _, l := range lines {
+			ips = append(ips, strings.Fields(l)...)
+		}
+	}
+	return ips, nil
+}
+
 // podmanContainerIP returns ipv4, ipv6 of container or error
 func podmanContainerIP(ociBin string, name string) (string, string, error) {
 	rr, err := runCmd(exec.Command(ociBin, "container", "inspect",
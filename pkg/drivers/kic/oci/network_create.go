@@ -54,7 +54,8 @@ func defaultBridgeName(ociBin string) string {
 }
 
 // CreateNetwork creates a network returns gateway and error, minikube creates one network per cluster
-func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
+// If ipv6 is true, the network is created dual-stack, with an IPv6 subnet paired with the IPv4 one (see network.IPv6ForIPv4)
+func CreateNetwork(ociBin string, networkName string, ipv6 bool) (net.IP, error) {
 	defaultBridgeName := defaultBridgeName(ociBin)
 	if networkName == defaultBridgeName {
 		klog.Infof("skipping creating network since default network %s was specified", networkName)
@@ -65,6 +66,9 @@ func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
 	info, err := containerNetworkInspect(ociBin, networkName)
 	if err == nil {
 		klog.Infof("Found existing network %+v", info)
+		if ipv6 && info.subnetV6 == nil {
+			return nil, fmt.Errorf("un-retryable: existing network %s has no IPv6 subnet", networkName)
+		}
 		return info.gateway, nil
 	}
 
@@ -85,7 +89,7 @@ func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
 			klog.Errorf("failed to find free subnet for %s network %s after %d attempts: %v", ociBin, networkName, 20, err)
 			return nil, fmt.Errorf("un-retryable: %w", err)
 		}
-		info.gateway, err = tryCreateDockerNetwork(ociBin, subnet, info.mtu, networkName)
+		info.gateway, err = tryCreateDockerNetwork(ociBin, subnet, info.mtu, networkName, ipv6)
 		if err == nil {
 			klog.Infof("%s network %s %s created", ociBin, networkName, subnet.CIDR)
 			return info.gateway, nil
@@ -101,7 +105,7 @@ func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
 	return info.gateway, fmt.Errorf("failed to create %s network %s: %w", ociBin, networkName, err)
 }
 
-func tryCreateDockerNetwork(ociBin string, subnet *network.Parameters, mtu int, name string) (net.IP, error) {
+func tryCreateDockerNetwork(ociBin string, subnet *network.Parameters, mtu int, name string, ipv6 bool) (net.IP, error) {
 	gateway := net.ParseIP(subnet.Gateway)
 	klog.Infof("attempt to create %s network %s %s with gateway %s and MTU of %d ...", ociBin, name, subnet.CIDR, subnet.Gateway, mtu)
 	args := []string{
@@ -111,6 +115,18 @@ func tryCreateDockerNetwork(ociBin string, subnet *network.Parameters, mtu int,
 		fmt.Sprintf("--subnet=%s", subnet.CIDR),
 		fmt.Sprintf("--gateway=%s", subnet.Gateway),
 	}
+	if ipv6 {
+		subnetV6, err := network.IPv6SubnetForIPv4(subnet.IP)
+		if err != nil {
+			return nil, err
+		}
+		gatewayV6, err := network.IPv6ForIPv4(subnet.Gateway)
+		if err != nil {
+			return nil, err
+		}
+		klog.Infof("adding IPv6 subnet %s with gateway %s to %s network %s", subnetV6, gatewayV6, ociBin, name)
+		args = append(args, "--ipv6", fmt.Sprintf("--subnet=%s", subnetV6), fmt.Sprintf("--gateway=%s", gatewayV6))
+	}
 	if ociBin == Docker {
 		// options documentation https://docs.docker.com/engine/reference/commandline/network_create/#bridge-driver-options
 		args = append(args, "-o")
@@ -147,10 +163,11 @@ func tryCreateDockerNetwork(ociBin string, subnet *network.Parameters, mtu int,
 
 // netInfo holds part of a docker or podman network information relevant to kic drivers
 type netInfo struct {
-	name    string
-	subnet  *net.IPNet
-	gateway net.IP
-	mtu     int
+	name     string
+	subnet   *net.IPNet
+	gateway  net.IP
+	subnetV6 *net.IPNet // only set for dual-stack networks
+	mtu      int
 }
 
 func containerNetworkInspect(ociBin string, name string) (netInfo, error) {
@@ -176,7 +193,7 @@ type networkInspect struct {
 var dockerInspectGetter = func(name string) (*RunResult, error) {
 	// hack -- 'support ancient versions of docker again (template parsing issue) #10362' and resolve 'Template parsing error: template: :1: unexpected "=" in operand' / 'exit status 64'
 	// note: docker v18.09.7 and older use go v1.10.8 and older, whereas support for '=' operator in go templates came in go v1.11
-	cmd := exec.Command(Docker, "network", "inspect", name, "--format", `{"Name": "{{.Name}}","Driver": "{{.Driver}}","Subnet": "{{range .IPAM.Config}}{{.Subnet}}{{end}}","Gateway": "{{range .IPAM.Config}}{{.Gateway}}{{end}}","MTU": {{if (index .Options "com.docker.network.driver.mtu")}}{{(index .Options "com.docker.network.driver.mtu")}}{{else}}0{{end}}, "ContainerIPs": [{{range $k,$v := .Containers }}"{{$v.IPv4Address}}",{{end}}]}`)
+	cmd := exec.Command(Docker, "network", "inspect", name, "--format", `{"Name": "{{.Name}}","Driver": "{{.Driver}}","Subnet": "{{range .IPAM.Config}}{{.Subnet}},{{end}}","Gateway": "{{range .IPAM.Config}}{{.Gateway}},{{end}}","MTU": {{if (index .Options "com.docker.network.driver.mtu")}}{{(index .Options "com.docker.network.driver.mtu")}}{{else}}0{{end}}, "ContainerIPs": [{{range $k,$v := .Containers }}"{{$v.IPv4Address}}",{{end}}]}`)
 	rr, err := runCmd(cmd)
 	// remove extra ',' after the last element in the ContainerIPs slice
 	rr.Stdout = *bytes.NewBuffer(bytes.ReplaceAll(rr.Stdout.Bytes(), []byte(",]"), []byte("]")))
@@ -203,12 +220,30 @@ func dockerNetworkInspect(name string) (netInfo, error) {
 		return info, fmt.Errorf("error parsing network inspect output: %q", rr.Stdout.String())
 	}
 
-	info.gateway = net.ParseIP(vals.Gateway)
 	info.mtu = vals.MTU
 
-	_, info.subnet, err = net.ParseCIDR(vals.Subnet)
-	if err != nil {
-		return info, errors.Wrapf(err, "parse subnet for %s", name)
+	// dual-stack networks have one IPAM config per family, listed as "subnet4,subnet6,"
+	for _, gw := range strings.Split(vals.Gateway, ",") {
+		if ip := net.ParseIP(gw); ip != nil && ip.To4() != nil {
+			info.gateway = ip
+		}
+	}
+	for _, s := range strings.Split(vals.Subnet, ",") {
+		if s == "" {
+			continue
+		}
+		ip, subnet, err := net.ParseCIDR(s)
+		if err != nil {
+			return info, errors.Wrapf(err, "parse subnet for %s", name)
+		}
+		if ip.To4() != nil {
+			info.subnet = subnet
+		} else {
+			info.subnetV6 = subnet
+		}
+	}
+	if info.subnet == nil {
+		return info, fmt.Errorf("no IPv4 subnet found for %s: %q", name, vals.Subnet)
 	}
 
 	return info, nil
@@ -279,6 +314,15 @@ func RemoveNetwork(ociBin string, name string) error {
 	return err
 }
 
+// NetworkSubnet returns the IPv4 subnet of a network
+func NetworkSubnet(ociBin string, name string) (*net.IPNet, error) {
+	info, err := containerNetworkInspect(ociBin, name)
+	if err != nil {
+		return nil, err
+	}
+	return info.subnet, nil
+}
+
 func networkExists(ociBin string, name string) bool {
 	_, err := containerNetworkInspect(ociBin, name)
 	if err != nil && !errors.Is(err, ErrNetworkNotFound) { // log unexpected error
@@ -37,6 +37,7 @@ func TestDockerInspect(t *testing.T) {
 		dockerInspectResponse string
 		gateway               string
 		subnetIP              string
+		subnetV6IP            string
 		mtu                   int
 	}{
 		{
@@ -53,6 +54,14 @@ func TestDockerInspect(t *testing.T) {
 			subnetIP:              "172.19.0.0",
 			mtu:                   0,
 		},
+		{
+			name:                  "dualStack",
+			dockerInspectResponse: `{"Name": "m2","Driver": "bridge","Subnet": "192.168.49.0/24,fd00:192:168:49::/64,","Gateway": "192.168.49.1,fd00:192:168:49::1,","MTU": 0, "ContainerIPs": []}`,
+			gateway:               "192.168.49.1",
+			subnetIP:              "192.168.49.0",
+			subnetV6IP:            "fd00:192:168:49::",
+			mtu:                   0,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -79,6 +88,13 @@ func TestDockerInspect(t *testing.T) {
 			if !netInfo.subnet.IP.Equal(net.ParseIP(tc.subnetIP)) {
 				t.Errorf("Expected not to have subnet as %v but got %v", tc.subnetIP, netInfo.gateway)
 			}
+
+			if tc.subnetV6IP == "" && netInfo.subnetV6 != nil {
+				t.Errorf("Expected no IPv6 subnet but got %v", netInfo.subnetV6)
+			}
+			if tc.subnetV6IP != "" && (netInfo.subnetV6 == nil || !netInfo.subnetV6.IP.Equal(net.ParseIP(tc.subnetV6IP))) {
+				t.Errorf("Expected IPv6 subnet %v but got %v", tc.subnetV6IP, netInfo.subnetV6)
+			}
 		})
 	}
 }
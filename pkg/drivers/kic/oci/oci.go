@@ -21,7 +21,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"net/url"
 	"os"
 	"os/exec"
 	"runtime"
@@ -182,10 +181,21 @@ func CreateContainerNode(p CreateParams) error {
 	if p.Network != "" && p.IP != "" {
 		runArgs = append(runArgs, "--network", p.Network)
 		runArgs = append(runArgs, "--ip", p.IP)
+		if p.IPv6 != "" {
+			runArgs = append(runArgs, "--ip6", p.IPv6)
+		}
 	}
 
 	memcgSwap := hasMemorySwapCgroup()
 	memcg := HasMemoryCgroup()
+	if p.OCIBinary == Podman && IsRootlessForced() {
+		// the limits of a rootless container need the controllers to be delegated to the user
+		if err := CheckRootlessCgroups(); err != nil {
+			klog.Warningf("not setting resource limits on rootless container: %v", err)
+			memcgSwap = false
+			memcg = false
+		}
+	}
 
 	// https://www.freedesktop.org/wiki/Software/systemd/ContainerInterface/
 	var virtualization string
@@ -244,6 +254,8 @@ func CreateContainerNode(p CreateParams) error {
 		runArgs = append(runArgs, "-e", fmt.Sprintf("%s=%s", key, val))
 	}
 
+	runArgs = append(runArgs, gpuArgs(p.OCIBinary, p.GPUs)...)
+
 	// adds node specific args
 	runArgs = append(runArgs, p.ExtraArgs...)
 
@@ -292,6 +304,21 @@ func CreateContainerNode(p CreateParams) error {
 	return nil
 }
 
+// gpuArgs returns the arguments passing the GPUs of the host to a container
+func gpuArgs(ociBin string, gpus string) []string {
+	switch gpus {
+	case constants.GPUNvidia:
+		if ociBin == Podman {
+			// podman uses the CDI specification generated by 'nvidia-ctk cdi generate'
+			return []string{"--device", "nvidia.com/gpu=all"}
+		}
+		return []string{"--gpus", "all"}
+	case constants.GPUAMD:
+		return []string{"--device", "/dev/kfd", "--device", "/dev/dri", "--group-add", "video"}
+	}
+	return nil
+}
+
 // CreateContainer creates a container with "docker/podman run"
 func createContainer(ociBin string, image string, opts ...createOpt) error {
 	o := &createOpts{}
@@ -311,7 +338,8 @@ func createContainer(ociBin string, image string, opts ...createOpt) error {
 
 	// to run nested container from privileged container in podman https://bugzilla.redhat.com/show_bug.cgi?id=1687713
 	// only add when running locally (linux), when running remotely it needs to be configured on server in libpod.conf
-	if ociBin == Podman && runtime.GOOS == "linux" {
+	// rootless podman can only use the cgroups systemd delegates to the user
+	if ociBin == Podman && runtime.GOOS == "linux" && !IsRootlessForced() {
 		args = append(args, "--cgroup-manager", "cgroupfs")
 	}
 
@@ -341,7 +369,8 @@ func StartContainer(ociBin string, container string) error {
 
 	// to run nested container from privileged container in podman https://bugzilla.redhat.com/show_bug.cgi?id=1687713
 	// only add when running locally (linux), when running remotely it needs to be configured on server in libpod.conf
-	if ociBin == Podman && runtime.GOOS == "linux" {
+	// rootless podman can only use the cgroups systemd delegates to the user
+	if ociBin == Podman && runtime.GOOS == "linux" && !IsRootlessForced() {
 		args = append(args, "--cgroup-manager", "cgroupfs")
 	}
 
@@ -354,6 +383,51 @@ func StartContainer(ociBin string, container string) error {
 	return nil
 }
 
+// PauseContainer freezes all processes of a container with "docker/podman pause"
+func PauseContainer(ociBin string, container string) error {
+	if _, err := runCmd(exec.Command(ociBin, "pause", container)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnpauseContainer resumes a container frozen by PauseContainer
+func UnpauseContainer(ociBin string, container string) error {
+	if _, err := runCmd(exec.Command(ociBin, "unpause", container)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateContainerResources changes the CPU and memory limits of a running container, leaving those which are 0 unchanged
+func UpdateContainerResources(ociBin string, container string, cpus int, memoryMB int) error {
+	args := updateArgs(cpus, memoryMB, HasMemoryCgroup(), hasMemorySwapCgroup())
+	if len(args) == 0 {
+		return nil
+	}
+	cmd := exec.Command(ociBin, append(append([]string{"update"}, args...), container)...)
+	if _, err := runCmd(cmd); err != nil {
+		return errors.Wrapf(err, "update %s", container)
+	}
+	return nil
+}
+
+// updateArgs returns the arguments of "docker/podman update" setting the limits, like those of CreateContainerNode
+func updateArgs(cpus int, memoryMB int, memcg bool, memcgSwap bool) []string {
+	args := []string{}
+	if cpus != 0 {
+		args = append(args, fmt.Sprintf("--cpus=%d", cpus))
+	}
+	if memoryMB != 0 && memcg {
+		args = append(args, fmt.Sprintf("--memory=%dmb", memoryMB))
+		if memcgSwap {
+			// Disable swap by setting the value to match
+			args = append(args, fmt.Sprintf("--memory-swap=%dmb", memoryMB))
+		}
+	}
+	return args
+}
+
 // ContainerID returns id of a container name
 func ContainerID(ociBin string, nameOrID string) (string, error) {
 	rr, err := runCmd(exec.Command(ociBin, "container", "inspect", "-f", "{{.Id}}", nameOrID))
@@ -669,25 +743,11 @@ func iptablesFileExists(ociBin string, nameOrID string) bool {
 // DaemonHost returns the ip/hostname where OCI daemon service for driver is running
 // For Podman return the host part of CONTAINER_HOST environment variable if set
 // For Docker return the host part of DOCKER_HOST environment variable if set
-// or DefaultBindIPV4 otherwise
+// or DefaultBindIPV4 otherwise. The ports of containers on a daemon reached over SSH
+// are forwarded to DefaultBindIPV4 by minikube, see IsSSHDaemonHost.
 func DaemonHost(driver string) string {
-	if driver == Podman {
-		if dh := os.Getenv(constants.PodmanContainerHostEnv); dh != "" {
-			if u, err := url.Parse(dh); err == nil {
-				if u.Host != "" {
-					return u.Hostname()
-				}
-			}
-		}
-	}
-	if driver == Docker {
-		if dh := os.Getenv(constants.DockerHostEnv); dh != "" {
-			if u, err := url.Parse(dh); err == nil {
-				if u.Host != "" {
-					return u.Hostname()
-				}
-			}
-		}
+	if u := daemonHostURL(driver); u != nil && u.Host != "" && u.Scheme != "ssh" {
+		return u.Hostname()
 	}
 	return DefaultBindIPV4
 }
@@ -696,19 +756,6 @@ func DaemonHost(driver string) string {
 // For Podman driver return true if CONTAINER_HOST is set to a URI, and the URI contains a host item
 // For Docker driver return true if DOCKER_HOST is set to a URI, and the URI contains a host item
 func IsExternalDaemonHost(driver string) bool {
-	if driver == Podman {
-		if dh := os.Getenv(constants.PodmanContainerHostEnv); dh != "" {
-			if u, err := url.Parse(dh); err == nil {
-				return u.Host != ""
-			}
-		}
-	}
-	if driver == Docker {
-		if dh := os.Getenv(constants.DockerHostEnv); dh != "" {
-			if u, err := url.Parse(dh); err == nil {
-				return u.Host != ""
-			}
-		}
-	}
-	return false
+	u := daemonHostURL(driver)
+	return u != nil && u.Host != ""
 }
@@ -18,6 +18,7 @@ package oci
 
 import (
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -128,6 +129,7 @@ func TestDaemonHost(t *testing.T) {
 		{"docker", "", "unix:///var/run/something", "127.0.0.1", false},
 		{"docker", "", "tcp://127.0.0.1/foo", "127.0.0.1", true},
 		{"docker", "", "ssh://127.0.0.1/bar", "127.0.0.1", true},
+		{"docker", "", "ssh://me@buildserver:2222", "127.0.0.1", true},
 	}
 	for _, test := range tests {
 		_ = os.Setenv("CONTAINER_HOST", test.containerHost)
@@ -140,3 +142,27 @@ func TestDaemonHost(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateArgs(t *testing.T) {
+	tests := []struct {
+		desc      string
+		cpus      int
+		memory    int
+		memcg     bool
+		memcgSwap bool
+		want      []string
+	}{
+		{"cpus and memory", 4, 8192, true, true, []string{"--cpus=4", "--memory=8192mb", "--memory-swap=8192mb"}},
+		{"no swap cgroup", 0, 4096, true, false, []string{"--memory=4096mb"}},
+		{"no memory cgroup", 2, 4096, false, false, []string{"--cpus=2"}},
+		{"unchanged", 0, 0, true, true, []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := updateArgs(tc.cpus, tc.memory, tc.memcg, tc.memcgSwap)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("updateArgs() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// daemonHostEnv returns the environment variable pointing the CLI of a driver to its daemon
+func daemonHostEnv(driver string) string {
+	switch driver {
+	case Docker:
+		return constants.DockerHostEnv
+	case Podman:
+		return constants.PodmanContainerHostEnv
+	}
+	return ""
+}
+
+// daemonHostURL returns the URL of the daemon of a driver, or nil if it is not set or not a URL
+func daemonHostURL(driver string) *url.URL {
+	env := daemonHostEnv(driver)
+	if env == "" {
+		return nil
+	}
+	dh := os.Getenv(env)
+	if dh == "" {
+		return nil
+	}
+	u, err := url.Parse(dh)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// ValidateDaemonHost validates the URL of a remote daemon, given with 'minikube start --docker-host'
+func ValidateDaemonHost(host string) error {
+	u, err := url.Parse(host)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "ssh" && u.Scheme != "tcp" {
+		return fmt.Errorf("unsupported scheme %q, expected ssh:// or tcp://", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.New("missing host")
+	}
+	return nil
+}
+
+// userDaemonHosts holds the daemon hosts of the environment of the user, nil when unset, saved by SetDaemonHost
+var userDaemonHosts = map[string]*string{}
+
+// SetDaemonHost points the CLI of a driver to the daemon at host, for this process and the processes it starts.
+// An empty host points it back to the daemon of the environment of the user.
+func SetDaemonHost(driver string, host string) error {
+	env := daemonHostEnv(driver)
+	if env == "" {
+		return fmt.Errorf("the %s driver does not support remote daemons", driver)
+	}
+	if _, saved := userDaemonHosts[env]; !saved {
+		if v, ok := os.LookupEnv(env); ok {
+			userDaemonHosts[env] = &v
+		} else {
+			userDaemonHosts[env] = nil
+		}
+	}
+	if host != "" {
+		return os.Setenv(env, host)
+	}
+	if v := userDaemonHosts[env]; v != nil {
+		return os.Setenv(env, *v)
+	}
+	return os.Unsetenv(env)
+}
+
+// IsSSHDaemonHost returns whether the daemon of a driver is reached over SSH. The daemon is then on another host,
+// whose published ports are only bound to its loopback: minikube forwards them to the same ports of this host.
+func IsSSHDaemonHost(driver string) bool {
+	u := daemonHostURL(driver)
+	return u != nil && u.Scheme == "ssh" && u.Host != ""
+}
+
+// SSHForwardArgs returns the arguments of ssh forwarding ports of this host to the same ports of the loopback of
+// the host of a daemon reached over SSH
+func SSHForwardArgs(driver string, ports []int) ([]string, error) {
+	u := daemonHostURL(driver)
+	if u == nil || u.Scheme != "ssh" {
+		return nil, fmt.Errorf("the %s daemon is not reached over ssh", driver)
+	}
+	args := []string{"-N", "-o", "BatchMode=yes", "-o", "ServerAliveInterval=10", "-o", "ServerAliveCountMax=3", "-o", "ExitOnForwardFailure=no"}
+	if u.User != nil {
+		args = append(args, "-l", u.User.Username())
+	}
+	if u.Port() != "" {
+		args = append(args, "-p", u.Port())
+	}
+	for _, p := range ports {
+		hp := net.JoinHostPort(DefaultBindIPV4, strconv.Itoa(p))
+		args = append(args, "-L", fmt.Sprintf("%s:%s", hp, hp))
+	}
+	return append(args, u.Hostname()), nil
+}
+
+// PublishedPorts returns the ports of the host of the daemon which the ports of a container are published on
+func PublishedPorts(ociBin string, name string) ([]int, error) {
+	rr, err := runCmd(exec.Command(ociBin, "port", name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing ports of %s", name)
+	}
+	return parsePublishedPorts(rr.Stdout.Bytes()), nil
+}
+
+// parsePublishedPorts parses the output of 'docker port', lines such as "22/tcp -> 127.0.0.1:49153"
+func parsePublishedPorts(output []byte) []int {
+	seen := map[int]bool{}
+	var ports []int
+	s := bufio.NewScanner(bytes.NewReader(output))
+	for s.Scan() {
+		parts := strings.SplitN(s.Text(), "->", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		_, port, err := net.SplitHostPort(strings.TrimSpace(parts[1]))
+		if err != nil {
+			continue
+		}
+		p, err := strconv.Atoi(port)
+		if err != nil || seen[p] {
+			continue
+		}
+		seen[p] = true
+		ports = append(ports, p)
+	}
+	sort.Ints(ports)
+	return ports
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestValidateDaemonHost(t *testing.T) {
+	tests := []struct {
+		host  string
+		valid bool
+	}{
+		{"ssh://me@buildserver", true},
+		{"ssh://buildserver:2222", true},
+		{"tcp://192.168.1.10:2376", true},
+		{"unix:///var/run/docker.sock", false},
+		{"buildserver", false},
+		{"ssh://", false},
+	}
+	for _, tc := range tests {
+		if err := ValidateDaemonHost(tc.host); (err == nil) != tc.valid {
+			t.Errorf("ValidateDaemonHost(%q) = %v, want valid: %v", tc.host, err, tc.valid)
+		}
+	}
+}
+
+func TestIsSSHDaemonHost(t *testing.T) {
+	tests := []struct {
+		driver     string
+		dockerHost string
+		want       bool
+	}{
+		{"docker", "", false},
+		{"docker", "tcp://1.1.1.1:2376", false},
+		{"docker", "ssh://me@buildserver", true},
+		{"podman", "ssh://me@buildserver", false},
+	}
+	for _, tc := range tests {
+		_ = os.Setenv("DOCKER_HOST", tc.dockerHost)
+		_ = os.Unsetenv("CONTAINER_HOST")
+		if got := IsSSHDaemonHost(tc.driver); got != tc.want {
+			t.Errorf("IsSSHDaemonHost(%q) with DOCKER_HOST=%q = %v, want %v", tc.driver, tc.dockerHost, got, tc.want)
+		}
+	}
+	_ = os.Unsetenv("DOCKER_HOST")
+}
+
+func TestSSHForwardArgs(t *testing.T) {
+	_ = os.Setenv("DOCKER_HOST", "ssh://me@buildserver:2222")
+	defer os.Unsetenv("DOCKER_HOST")
+
+	got, err := SSHForwardArgs(Docker, []int{49153, 49154})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"-N", "-o", "BatchMode=yes", "-o", "ServerAliveInterval=10", "-o", "ServerAliveCountMax=3", "-o", "ExitOnForwardFailure=no",
+		"-l", "me", "-p", "2222",
+		"-L", "127.0.0.1:49153:127.0.0.1:49153", "-L", "127.0.0.1:49154:127.0.0.1:49154",
+		"buildserver"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SSHForwardArgs() = %v, want %v", got, want)
+	}
+
+	_ = os.Setenv("DOCKER_HOST", "tcp://buildserver:2376")
+	if _, err := SSHForwardArgs(Docker, []int{49153}); err == nil {
+		t.Errorf("SSHForwardArgs() with a tcp daemon did not fail")
+	}
+}
+
+func TestParsePublishedPorts(t *testing.T) {
+	output := `22/tcp -> 127.0.0.1:49157
+2376/tcp -> 127.0.0.1:49156
+8443/tcp -> 127.0.0.1:49153
+8443/tcp -> [::1]:49153
+not a port
+`
+	want := []int{49153, 49156, 49157}
+	if got := parsePublishedPorts([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePublishedPorts() = %v, want %v", got, want)
+	}
+}
+
+func TestSetDaemonHost(t *testing.T) {
+	_ = os.Setenv("DOCKER_HOST", "unix:///home/me/docker.sock")
+	defer os.Unsetenv("DOCKER_HOST")
+	defer func() { userDaemonHosts = map[string]*string{} }()
+
+	if err := SetDaemonHost(Docker, "ssh://me@buildserver"); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv("DOCKER_HOST"); got != "ssh://me@buildserver" {
+		t.Errorf("DOCKER_HOST = %q after SetDaemonHost", got)
+	}
+	if err := SetDaemonHost(Docker, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv("DOCKER_HOST"); got != "unix:///home/me/docker.sock" {
+		t.Errorf("DOCKER_HOST = %q, want the one of the user to be restored", got)
+	}
+	if err := SetDaemonHost("kvm2", "ssh://me@buildserver"); err == nil {
+		t.Errorf("SetDaemonHost() for kvm2 did not fail")
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// rootlessControllers are the cgroup controllers the kic container limits, which must be delegated in rootless mode
+var rootlessControllers = []string{"cpu", "memory", "pids"}
+
+// CheckRootlessCgroups returns an error if the host can't run rootless containers with resource limits
+func CheckRootlessCgroups() error {
+	cgroup2, err := IsCgroup2UnifiedMode()
+	if err != nil {
+		return errors.Wrap(err, "checking cgroup version")
+	}
+	if !cgroup2 {
+		return ErrRootlessCgroupV1
+	}
+	delegated, err := DelegatedControllers()
+	if err != nil {
+		return errors.Wrap(err, "reading delegated cgroup controllers")
+	}
+	if missing := missingControllers(delegated); len(missing) > 0 {
+		return errors.Wrapf(ErrCgroupNotDelegated, "missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// missingControllers returns the controllers needed in rootless mode which are not delegated
+func missingControllers(delegated []string) []string {
+	have := map[string]bool{}
+	for _, c := range delegated {
+		have[c] = true
+	}
+	missing := []string{}
+	for _, c := range rootlessControllers {
+		if !have[c] {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
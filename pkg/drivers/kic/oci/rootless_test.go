@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingControllers(t *testing.T) {
+	tests := []struct {
+		name      string
+		delegated []string
+		want      []string
+	}{
+		{"default systemd", []string{"memory", "pids"}, []string{"cpu"}},
+		{"delegated", []string{"cpuset", "cpu", "io", "memory", "pids"}, []string{}},
+		{"none", nil, []string{"cpu", "memory", "pids"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := missingControllers(tc.delegated)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("missingControllers(%v) = %v, want %v", tc.delegated, got, tc.want)
+			}
+		})
+	}
+}
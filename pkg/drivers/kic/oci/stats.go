@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// ContainerStats is the resource usage of a container, as seen by the host
+type ContainerStats struct {
+	// CPUPercent is the share of a host CPU used by the container, above 100 when it uses more than one
+	CPUPercent float64
+	// MemoryUsed is the memory used by the container, in bytes
+	MemoryUsed int64
+	// MemoryLimit is the memory the container may use, in bytes
+	MemoryLimit int64
+}
+
+// Stats returns the current resource usage of a container
+func Stats(ociBin string, name string) (*ContainerStats, error) {
+	rr, err := runCmd(exec.Command(ociBin, "stats", "--no-stream", "--format", "{{.CPUPerc}}|{{.MemUsage}}", name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s stats %s", ociBin, name)
+	}
+	return parseStats(strings.TrimSpace(rr.Stdout.String()))
+}
+
+// parseStats parses the CPUPerc and MemUsage columns of docker and podman stats, such as "12.5%|1.2GiB / 2GiB"
+func parseStats(s string) (*ContainerStats, error) {
+	fields := strings.Split(s, "|")
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("unexpected stats output: %q", s)
+	}
+	cpu, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(fields[0]), "%"), 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing cpu usage %q", fields[0])
+	}
+	mem := strings.Split(fields[1], "/")
+	if len(mem) != 2 {
+		return nil, fmt.Errorf("unexpected memory usage: %q", fields[1])
+	}
+	used, err := units.RAMInBytes(strings.TrimSpace(mem[0]))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing memory usage %q", mem[0])
+	}
+	limit, err := units.RAMInBytes(strings.TrimSpace(mem[1]))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing memory limit %q", mem[1])
+	}
+	return &ContainerStats{CPUPercent: cpu, MemoryUsed: used, MemoryLimit: limit}, nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseStats(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		input   string
+		want    *ContainerStats
+		wantErr bool
+	}{
+		{"docker", "12.50%|1.5GiB / 2GiB", &ContainerStats{CPUPercent: 12.5, MemoryUsed: 1536 << 20, MemoryLimit: 2 << 30}, false},
+		{"podman", "105.2%|512MB / 4GB", &ContainerStats{CPUPercent: 105.2, MemoryUsed: 512 << 20, MemoryLimit: 4 << 30}, false},
+		{"missing memory", "12.50%", nil, true},
+		{"bad cpu", "--|1.5GiB / 2GiB", nil, true},
+		{"bad memory", "12.50%|1.5GiB", nil, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := parseStats(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseStats(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("parseStats(%q) mismatch (-want +got):\n%s", tc.input, diff)
+			}
+		})
+	}
+}
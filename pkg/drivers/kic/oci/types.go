@@ -59,6 +59,8 @@ type CreateParams struct {
 	OCIBinary     string            // docker or podman
 	Network       string            // network name that the container will attach to
 	IP            string            // static IP to assign for th container in the cluster network
+	IPv6          string            // static IPv6 to assign for the container in a dual-stack cluster network
+	GPUs          string            // nvidia or amd, the GPUs of the host to pass to the container
 }
 
 // createOpt is an option for Create
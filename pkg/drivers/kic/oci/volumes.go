@@ -23,6 +23,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"path"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -161,3 +163,33 @@ func prepareVolumeSideCar(ociBin string, imageName string, nodeName string) erro
 	}
 	return nil
 }
+
+// SaveVolumeToTarball runs a docker image imageName which archives the content of the volume named volumeName
+// to the tarball at tarballPath
+func SaveVolumeToTarball(ociBin string, volumeName, tarballPath, imageName string) error {
+	cmdArgs := []string{"run", "--rm", "--entrypoint", "/usr/bin/tar"}
+	if ociBin == Podman && runtime.GOOS == "linux" {
+		cmdArgs = append(cmdArgs, "--security-opt", "label=disable")
+	}
+	dir, file := filepath.Split(tarballPath)
+	cmdArgs = append(cmdArgs, "-v", fmt.Sprintf("%s:/snapshot", dir), "-v", fmt.Sprintf("%s:/extractDir:ro", volumeName), imageName, "--numeric-owner", "-cpf", path.Join("/snapshot", file), "-C", "/extractDir", ".")
+	if _, err := runCmd(exec.Command(ociBin, cmdArgs...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreVolumeFromTarball runs a docker image imageName which replaces the content of the volume named volumeName
+// with the tarball at tarballPath, as written by SaveVolumeToTarball
+func RestoreVolumeFromTarball(ociBin string, tarballPath, volumeName, imageName string) error {
+	cmdArgs := []string{"run", "--rm", "--entrypoint", "/bin/bash"}
+	if ociBin == Podman && runtime.GOOS == "linux" {
+		cmdArgs = append(cmdArgs, "--security-opt", "label=disable")
+	}
+	script := "find /extractDir -mindepth 1 -delete && tar --numeric-owner -xpf /snapshot.tar -C /extractDir"
+	cmdArgs = append(cmdArgs, "-v", fmt.Sprintf("%s:/snapshot.tar:ro", tarballPath), "-v", fmt.Sprintf("%s:/extractDir", volumeName), imageName, "-c", script)
+	if _, err := runCmd(exec.Command(ociBin, cmdArgs...)); err != nil {
+		return err
+	}
+	return nil
+}
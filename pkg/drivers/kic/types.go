@@ -24,7 +24,7 @@ import (
 
 const (
 	// Version is the current version of kic
-	Version = "v0.0.23"
+	Version = "v0.0.24"
 	// SHA of the kic base image
 	baseImageSHA = "baf6d94b2050bcbecd98994e265cf965a4f4768978620ccf5227a6dcb75ade45"
 	// The name of the GCR kicbase repository
@@ -64,6 +64,9 @@ type Config struct {
 	KubernetesVersion string            // Kubernetes version to install
 	ContainerRuntime  string            // container runtime kic is running
 	Network           string            //  network to run with kic
+	StaticIP          string            // IP address of the node on its network, derived from the gateway and machine index if empty
 	ExtraArgs         []string          // a list of any extra option to pass to oci binary during creation time, for example --expose 8080...
 	ListenAddress     string            // IP Address to listen to
+	IPFamily          string            // ipv4, ipv6 or dual
+	GPUs              string            // nvidia or amd, the GPUs of the host to pass to the node
 }
@@ -0,0 +1,323 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+)
+
+const (
+	isoFilename     = "boot2docker.iso"
+	pidFileName     = "qemu.pid"
+	qmpFileName     = "qmp.sock"
+	consoleFileName = "console.log"
+	// DefaultBridge is the bridge of the default libvirt network, which most distributions set up along with QEMU
+	DefaultBridge = "virbr0"
+	// QEMU is the QEMU binary, the microvm machine type only exists for x86
+	QEMU = "qemu-system-x86_64"
+)
+
+// Driver is the machine driver for QEMU microvm virtual machines, which boot the kernel of the minikube ISO directly
+type Driver struct {
+	*drivers.BaseDriver
+	*pkgdrivers.CommonDriver
+	Boot2DockerURL string
+	DiskSize       int
+	CPU            int
+	Memory         int
+	Cmdline        string
+	// Bridge is the host bridge the VM is attached to, through qemu-bridge-helper
+	Bridge string
+	MAC    string
+}
+
+// NewDriver creates a new driver for a host
+func NewDriver(hostName, storePath string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: hostName,
+			StorePath:   storePath,
+			SSHUser:     "docker",
+		},
+		CommonDriver: &pkgdrivers.CommonDriver{},
+		Bridge:       DefaultBridge,
+	}
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	return "microvm"
+}
+
+// PreCreateCheck is called to enforce pre-creation steps
+func (d *Driver) PreCreateCheck() error {
+	if _, err := exec.LookPath(QEMU); err != nil {
+		return errors.Wrapf(err, "%s is required by the microvm driver", QEMU)
+	}
+	if _, err := net.InterfaceByName(d.Bridge); err != nil {
+		return errors.Wrapf(err, "bridge %s", d.Bridge)
+	}
+	return nil
+}
+
+// Create a host using the driver's config
+func (d *Driver) Create() error {
+	if err := pkgdrivers.MakeDiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize); err != nil {
+		return errors.Wrap(err, "making disk image")
+	}
+
+	// booting the kernel directly skips the firmware and bootloader, which take most of the boot time
+	isoPath := d.ResolveStorePath(isoFilename)
+	for _, f := range []struct {
+		pathInIso string
+		destPath  string
+	}{
+		{"/boot/bzimage", "bzimage"},
+		{"/boot/initrd", "initrd"},
+	} {
+		if err := pkgdrivers.ExtractFile(isoPath, f.pathInIso, d.ResolveStorePath(f.destPath)); err != nil {
+			return errors.Wrap(err, "extracting kernel")
+		}
+	}
+
+	if d.MAC == "" {
+		mac, err := randomMAC()
+		if err != nil {
+			return errors.Wrap(err, "generating MAC address")
+		}
+		d.MAC = mac
+	}
+	return d.Start()
+}
+
+// qemuArgs returns the arguments to start the VM with
+func (d *Driver) qemuArgs() []string {
+	return []string{
+		"-name", d.MachineName,
+		"-machine", "microvm,accel=kvm,pcie=on,acpi=on",
+		"-cpu", "host",
+		"-smp", strconv.Itoa(d.CPU),
+		"-m", fmt.Sprintf("%dM", d.Memory),
+		"-nodefaults", "-no-user-config",
+		"-display", "none",
+		"-serial", "file:" + d.ResolveStorePath(consoleFileName),
+		"-kernel", d.ResolveStorePath("bzimage"),
+		"-initrd", d.ResolveStorePath("initrd"),
+		"-append", d.Cmdline,
+		"-drive", fmt.Sprintf("id=disk0,file=%s,format=raw,if=none,discard=unmap", pkgdrivers.GetDiskPath(d.BaseDriver)),
+		"-device", "virtio-blk-pci,drive=disk0",
+		"-netdev", "bridge,id=net0,br=" + d.Bridge,
+		"-device", "virtio-net-pci,netdev=net0,mac=" + d.MAC,
+		"-device", "virtio-rng-pci",
+		"-qmp", fmt.Sprintf("unix:%s,server,nowait", d.ResolveStorePath(qmpFileName)),
+		"-pidfile", d.ResolveStorePath(pidFileName),
+		"-daemonize",
+	}
+}
+
+// Start a host
+func (d *Driver) Start() error {
+	if s, err := d.GetState(); err == nil && s == state.Running {
+		log.Debugf("%s is already running", d.MachineName)
+	} else {
+		args := d.qemuArgs()
+		log.Debugf("Starting %s %s", QEMU, strings.Join(args, " "))
+		out, err := exec.Command(QEMU, args...).CombinedOutput()
+		if err != nil {
+			return errors.Wrapf(err, "starting %s: %s", QEMU, strings.TrimSpace(string(out)))
+		}
+	}
+	return d.setupIP()
+}
+
+func (d *Driver) setupIP() error {
+	var err error
+	// Implement a retry loop without calling any minikube code
+	for i := 0; i < 60; i++ {
+		s, serr := d.GetState()
+		if serr != nil {
+			return errors.Wrap(serr, "get state")
+		}
+		if s != state.Running {
+			return fmt.Errorf("%s exited, see %s", QEMU, d.ResolveStorePath(consoleFileName))
+		}
+		d.IPAddress, err = ipAddress(d.Bridge, d.MAC)
+		if err == nil {
+			log.Debugf("IP: %s", d.IPAddress)
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return errors.Wrapf(err, "IP address of %s never found on %s", d.MAC, d.Bridge)
+}
+
+// GetSSHHostname returns hostname for use with ssh
+func (d *Driver) GetSSHHostname() (string, error) {
+	return d.IPAddress, nil
+}
+
+// GetURL returns a Docker URL inside this host
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s:2376", ip), nil
+}
+
+// GetState returns the state that the host is in (running, stopped, etc)
+func (d *Driver) GetState() (state.State, error) {
+	pid := d.getPid()
+	if pid == 0 {
+		return state.Stopped, nil
+	}
+	// signal 0 checks that the process exists, without sending anything
+	if err := syscall.Kill(pid, 0); err != nil {
+		log.Debugf("qemu pid %d is gone: %v", pid, err)
+		return state.Stopped, nil
+	}
+	b, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil || !strings.Contains(string(b), d.ResolveStorePath(pidFileName)) {
+		log.Debugf("pid %d is stale, and is not the VM of %s", pid, d.MachineName)
+		return state.Stopped, nil
+	}
+	return state.Running, nil
+}
+
+func (d *Driver) getPid() int {
+	b, err := ioutil.ReadFile(d.ResolveStorePath(pidFileName))
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		log.Warnf("Error parsing pid file: %v", err)
+		return 0
+	}
+	return pid
+}
+
+// Stop a host gracefully, through an ACPI power button press
+func (d *Driver) Stop() error {
+	s, err := d.GetState()
+	if err != nil {
+		return err
+	}
+	if s != state.Running {
+		return nil
+	}
+	if err := d.qmp("system_powerdown"); err != nil {
+		log.Warnf("powering down through QMP failed: %v", err)
+		return d.Kill()
+	}
+
+	for i := 0; i < 60; i++ {
+		log.Debug("waiting for graceful shutdown")
+		time.Sleep(time.Second)
+		s, err := d.GetState()
+		if err != nil {
+			return errors.Wrap(err, "waiting for graceful shutdown")
+		}
+		if s == state.Stopped {
+			return nil
+		}
+	}
+	log.Debug("sending sigkill")
+	return d.Kill()
+}
+
+// qmp runs a command of the QEMU machine protocol
+func (d *Driver) qmp(command string) error {
+	conn, err := net.DialTimeout("unix", d.ResolveStorePath(qmpFileName), 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	var greeting map[string]interface{}
+	if err := dec.Decode(&greeting); err != nil {
+		return errors.Wrap(err, "reading greeting")
+	}
+	for _, c := range []string{"qmp_capabilities", command} {
+		if err := enc.Encode(map[string]string{"execute": c}); err != nil {
+			return err
+		}
+		// skip the asynchronous events until the command reply
+		for {
+			var reply map[string]interface{}
+			if err := dec.Decode(&reply); err != nil {
+				return errors.Wrapf(err, "reading %s reply", c)
+			}
+			if e, ok := reply["error"]; ok {
+				return fmt.Errorf("%s: %v", c, e)
+			}
+			if _, ok := reply["return"]; ok {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Kill stops a host forcefully
+func (d *Driver) Kill() error {
+	s, err := d.GetState()
+	if err != nil || s != state.Running {
+		return err
+	}
+	return syscall.Kill(d.getPid(), syscall.SIGKILL)
+}
+
+// Remove a host
+func (d *Driver) Remove() error {
+	if err := d.Kill(); err != nil {
+		return errors.Wrap(err, "kill")
+	}
+	for _, f := range []string{pidFileName, qmpFileName} {
+		if err := os.Remove(d.ResolveStorePath(f)); err != nil && !os.IsNotExist(err) {
+			log.Warnf("removing %s: %v", f, err)
+		}
+	}
+	return nil
+}
+
+// Restart a host
+func (d *Driver) Restart() error {
+	return pkgdrivers.Restart(d)
+}
@@ -0,0 +1,112 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIPFromLeases(t *testing.T) {
+	leases := []byte(`[
+  {
+    "ip-address": "192.168.122.10",
+    "mac-address": "52:54:00:aa:bb:cc",
+    "hostname": "other",
+    "expiry-time": 1626000000
+  },
+  {
+    "ip-address": "192.168.122.42",
+    "mac-address": "52:54:00:12:34:56",
+    "hostname": "minikube",
+    "expiry-time": 1626000000
+  }
+]`)
+	tests := []struct {
+		mac     string
+		want    string
+		wantErr bool
+	}{
+		{"52:54:00:12:34:56", "192.168.122.42", false},
+		{"52:54:00:AA:BB:CC", "192.168.122.10", false},
+		{"52:54:00:00:00:00", "", true},
+	}
+	for _, tc := range tests {
+		got, err := ipFromLeases(leases, tc.mac)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("ipFromLeases(%s) = %q, %v, want %q (error: %v)", tc.mac, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestIPFromNeighbors(t *testing.T) {
+	out := `192.168.122.10 lladdr 52:54:00:aa:bb:cc STALE
+192.168.122.42 lladdr 52:54:00:12:34:56 REACHABLE
+192.168.122.50 FAILED
+`
+	tests := []struct {
+		mac     string
+		want    string
+		wantErr bool
+	}{
+		{"52:54:00:12:34:56", "192.168.122.42", false},
+		{"52:54:00:aa:bb:cc", "192.168.122.10", false},
+		{"52:54:00:00:00:00", "", true},
+	}
+	for _, tc := range tests {
+		got, err := ipFromNeighbors(out, tc.mac)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("ipFromNeighbors(%s) = %q, %v, want %q (error: %v)", tc.mac, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestQEMUArgs(t *testing.T) {
+	d := NewDriver("minikube", "/home/user/.minikube")
+	d.CPU = 2
+	d.Memory = 4000
+	d.MAC = "52:54:00:12:34:56"
+	d.Cmdline = "console=ttyS0"
+	args := strings.Join(d.qemuArgs(), " ")
+
+	for _, want := range []string{
+		"-machine microvm,accel=kvm,pcie=on,acpi=on",
+		"-smp 2",
+		"-m 4000M",
+		"-kernel /home/user/.minikube/machines/minikube/bzimage",
+		"-append console=ttyS0",
+		"-netdev bridge,id=net0,br=virbr0",
+		"-device virtio-net-pci,netdev=net0,mac=52:54:00:12:34:56",
+		"-pidfile /home/user/.minikube/machines/minikube/qemu.pid",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("qemu arguments %q do not contain %q", args, want)
+		}
+	}
+}
+
+func TestRandomMAC(t *testing.T) {
+	mac, err := randomMAC()
+	if err != nil {
+		t.Fatalf("randomMAC: %v", err)
+	}
+	if !strings.HasPrefix(mac, "52:54:00:") || len(mac) != 17 {
+		t.Errorf("randomMAC() = %q, want a 52:54:00:xx:xx:xx address", mac)
+	}
+}
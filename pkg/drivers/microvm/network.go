@@ -0,0 +1,87 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// leasesDir is where the dnsmasq of libvirt networks writes its leases, named after the bridge
+const leasesDir = "/var/lib/libvirt/dnsmasq"
+
+// randomMAC returns a random MAC address in the range QEMU uses for its guests
+func randomMAC() (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", b[0], b[1], b[2]), nil
+}
+
+// ipAddress returns the IP address of the guest with the given MAC address on a bridge
+func ipAddress(bridge string, mac string) (string, error) {
+	if b, err := ioutil.ReadFile(fmt.Sprintf("%s/%s.status", leasesDir, bridge)); err == nil {
+		if ip, err := ipFromLeases(b, mac); err == nil {
+			return ip, nil
+		}
+	}
+	// bridges which are not managed by libvirt have no leases file, but the guest shows up in the neighbor table
+	out, err := exec.Command("ip", "-4", "neigh", "show", "dev", bridge).Output()
+	if err != nil {
+		return "", errors.Wrap(err, "ip neigh")
+	}
+	return ipFromNeighbors(string(out), mac)
+}
+
+// ipFromLeases finds the address of mac in a libvirt dnsmasq status file
+func ipFromLeases(b []byte, mac string) (string, error) {
+	var leases []struct {
+		IPAddress  string `json:"ip-address"`
+		MACAddress string `json:"mac-address"`
+	}
+	if err := json.Unmarshal(b, &leases); err != nil {
+		return "", errors.Wrap(err, "parsing leases")
+	}
+	for _, l := range leases {
+		if strings.EqualFold(l.MACAddress, mac) {
+			return l.IPAddress, nil
+		}
+	}
+	return "", fmt.Errorf("no lease for %s", mac)
+}
+
+// ipFromNeighbors finds the address of mac in the output of 'ip neigh show'
+func ipFromNeighbors(out string, mac string) (string, error) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		for i := 0; i+1 < len(fields); i++ {
+			if fields[i] == "lladdr" && strings.EqualFold(fields[i+1], mac) && !strings.Contains(line, "FAILED") {
+				return fields[0], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s is not a neighbor", mac)
+}
@@ -0,0 +1,440 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package archive packages a profile into a single file that can be imported on another host
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+	"k8s.io/minikube/pkg/version"
+)
+
+const (
+	manifestFile = "manifest.json"
+	profileDir   = "profile"
+	certsDir     = "certs"
+	imagesDir    = "images"
+)
+
+// caFiles are the cluster-independent certificate authorities shared by all profiles
+var caFiles = []string{"ca.crt", "ca.key", "proxy-client-ca.crt", "proxy-client-ca.key"}
+
+// Manifest describes the content of a profile archive
+type Manifest struct {
+	Profile         string
+	MinikubeVersion string
+	Created         time.Time
+	// Images are the cached images included in the archive
+	Images []string
+	// Snapshot is the name of the included snapshot of the node disks, if any
+	Snapshot string
+}
+
+// ExportOptions controls what is included in a profile archive
+type ExportOptions struct {
+	// Images are cached images to include, as added by 'minikube cache add'
+	Images []string
+	// IncludeDisk captures the node disks with a snapshot, which requires a running cluster
+	IncludeDisk bool
+}
+
+// ImportResult describes the outcome of an import
+type ImportResult struct {
+	Manifest
+	// ConflictingCerts are certificate authorities which differ from the local ones and were not imported
+	ConflictingCerts []string
+}
+
+// Export writes the profile of a cluster, its certificates and the given cached images to dst.
+// The machines of the cluster are left out: their VMs or containers only exist on this host, so the
+// cluster is provisioned again from its configuration when it is started after an import.
+func Export(api libmachine.API, cc config.ClusterConfig, dst string, opts ExportOptions) (m *Manifest, err error) {
+	m = &Manifest{
+		Profile:         cc.Name,
+		MinikubeVersion: version.GetVersion(),
+		Created:         time.Now(),
+	}
+
+	if opts.IncludeDisk {
+		s, err := snapshot.Create(api, cc, fmt.Sprintf("export-%d", m.Created.Unix()))
+		if err != nil {
+			return nil, errors.Wrap(err, "snapshot")
+		}
+		// the snapshot only exists to be archived
+		defer func() {
+			dir := filepath.Join(snapshot.Dir(cc.Name), s.Name)
+			if rerr := os.RemoveAll(dir); rerr != nil {
+				klog.Warningf("unable to remove %s: %v", dir, rerr)
+			}
+		}()
+		m.Snapshot = s.Name
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+	zw, err := zstd.NewWriter(f)
+	if err != nil {
+		return nil, err
+	}
+	tw := tar.NewWriter(zw)
+
+	var images []string
+	for _, img := range opts.Images {
+		if !image.IsCached(constants.ImageCacheDir, img) {
+			klog.Warningf("skipping image %s which is not cached", img)
+			continue
+		}
+		images = append(images, img)
+	}
+	m.Images = images
+
+	b, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestFile, Mode: 0644, Size: int64(len(b)), ModTime: m.Created}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(b); err != nil {
+		return nil, err
+	}
+
+	for _, img := range images {
+		if err := addCachedImage(tw, img); err != nil {
+			return nil, errors.Wrapf(err, "adding image %s", img)
+		}
+	}
+
+	for _, name := range caFiles {
+		if err := addFile(tw, localpath.MakeMiniPath(name), path.Join(certsDir, name)); err != nil {
+			return nil, errors.Wrapf(err, "adding %s", name)
+		}
+	}
+
+	if err := addProfile(tw, cc.Name, m.Snapshot); err != nil {
+		return nil, errors.Wrap(err, "adding profile")
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// addCachedImage adds an image of the image cache, as a tarball
+func addCachedImage(tw *tar.Writer, img string) error {
+	tmp, err := ioutil.TempDir("", "minikube-export")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	src := filepath.Join(tmp, "image.tar")
+	if err := image.ExportCachedImage(constants.ImageCacheDir, img, src); err != nil {
+		return err
+	}
+	return addFile(tw, src, path.Join(imagesDir, filepath.ToSlash(localpath.SanitizeCacheDir(img))))
+}
+
+// addProfile adds the files of a profile directory, leaving out snapshots other than the one being exported
+func addProfile(tw *tar.Writer, profile string, snap string) error {
+	dir := localpath.Profile(profile)
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if info.IsDir() {
+			if rel == "snapshots" || !strings.HasPrefix(rel, "snapshots/") || rel == "snapshots/"+snap {
+				return nil
+			}
+			return filepath.SkipDir
+		}
+		// the scheduled stop process only exists on this host
+		if rel == "pid" {
+			return nil
+		}
+		// credentials stay on this host as well
+		if p == localpath.AddonSecrets(profile) {
+			return nil
+		}
+		return addFile(tw, p, path.Join(profileDir, rel))
+	})
+}
+
+func addFile(tw *tar.Writer, src string, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Import unpacks an archive written by Export into the local minikube home.
+// Certificate authorities which already exist locally are kept. The profile is
+// only moved into place once the whole archive was read, and nothing is left
+// behind if the import fails. The profile is refused if this host has leftover machines of the same
+// name, as the cluster must be provisioned again rather than start from their state.
+func Import(src string) (res *ImportResult, err error) {
+	// files written outside of the staging directory, removed if the import fails
+	var created []string
+	var staging string
+	defer func() {
+		if err == nil {
+			return
+		}
+		if staging != "" {
+			if rerr := os.RemoveAll(staging); rerr != nil {
+				klog.Warningf("unable to clean up %s: %v", staging, rerr)
+			}
+		}
+		for _, p := range created {
+			if rerr := os.Remove(p); rerr != nil {
+				klog.Warningf("unable to clean up %s: %v", p, rerr)
+			}
+		}
+	}()
+
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+
+	res = &ImportResult{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading archive")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return nil, fmt.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+
+		switch {
+		case name == manifestFile:
+			// the manifest is written before the profile, so that it can be validated first
+			if err := json.NewDecoder(tr).Decode(&res.Manifest); err != nil {
+				return nil, errors.Wrap(err, "parsing manifest")
+			}
+			if !config.ProfileNameValid(res.Profile) {
+				return nil, fmt.Errorf("invalid profile name in archive: %q", res.Profile)
+			}
+			if config.ProfileExists(res.Profile) {
+				return nil, fmt.Errorf("profile %q already exists, delete it first with: minikube delete -p %s", res.Profile, res.Profile)
+			}
+			if err := os.MkdirAll(localpath.MiniPath(), 0755); err != nil {
+				return nil, err
+			}
+			staging, err = ioutil.TempDir(localpath.MiniPath(), "import-"+res.Profile+"-")
+			if err != nil {
+				return nil, errors.Wrap(err, "creating staging directory")
+			}
+		case res.Profile == "":
+			return nil, fmt.Errorf("%s is not a profile archive: %s must come before %s", src, manifestFile, hdr.Name)
+		case strings.HasPrefix(name, profileDir+"/"):
+			dst := filepath.Join(staging, filepath.FromSlash(strings.TrimPrefix(name, profileDir+"/")))
+			if err := writeFile(tr, dst, hdr.FileInfo().Mode()); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(name, certsDir+"/"):
+			conflict, written, err := importCert(tr, path.Base(name), hdr.FileInfo().Mode())
+			if written != "" {
+				created = append(created, written)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if conflict {
+				res.ConflictingCerts = append(res.ConflictingCerts, path.Base(name))
+			}
+		case strings.HasPrefix(name, imagesDir+"/"):
+			dst := filepath.Join(constants.ImageCacheDir, filepath.FromSlash(strings.TrimPrefix(name, imagesDir+"/")))
+			if _, err := os.Stat(dst); err == nil {
+				klog.Infof("image %s is already cached", dst)
+				continue
+			}
+			created = append(created, dst)
+			if err := writeFile(tr, dst, hdr.FileInfo().Mode()); err != nil {
+				return nil, err
+			}
+		default:
+			klog.Warningf("ignoring unknown file in archive: %s", hdr.Name)
+		}
+	}
+	if res.Profile == "" {
+		return nil, fmt.Errorf("%s is not a profile archive: no %s", src, manifestFile)
+	}
+	if len(res.ConflictingCerts) > 0 {
+		if err := removeSignedCerts(staging); err != nil {
+			return nil, errors.Wrap(err, "removing certificates signed by the exported CA")
+		}
+	}
+
+	if err := checkNoMachines(res.Profile, staging); err != nil {
+		return nil, err
+	}
+
+	dst := localpath.Profile(res.Profile)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(staging, dst); err != nil {
+		return nil, errors.Wrapf(err, "moving profile to %s", dst)
+	}
+	return res, nil
+}
+
+// checkNoMachines returns an error if a machine of the imported cluster already exists on this host
+func checkNoMachines(profile string, staging string) error {
+	b, err := ioutil.ReadFile(filepath.Join(staging, "config.json"))
+	if err != nil {
+		return errors.Wrap(err, "reading imported config")
+	}
+	var cc config.ClusterConfig
+	if err := json.Unmarshal(b, &cc); err != nil {
+		return errors.Wrap(err, "parsing imported config")
+	}
+	machines := []string{profile}
+	if len(cc.Nodes) > 0 {
+		machines = nil
+		for _, n := range cc.Nodes {
+			machines = append(machines, config.MachineName(cc, n))
+		}
+	}
+	for _, m := range machines {
+		if _, err := os.Stat(localpath.MachinePath(m)); err == nil {
+			return fmt.Errorf("machine %q already exists on this host, remove it first with: minikube delete -p %s", m, profile)
+		}
+	}
+	return nil
+}
+
+// removeSignedCerts deletes the certificates of a profile, so that they get signed again by the local CA on start
+func removeSignedCerts(dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		n := e.Name()
+		if e.IsDir() || !(strings.Contains(n, ".crt") || strings.Contains(n, ".key")) {
+			continue
+		}
+		klog.Infof("removing %s signed by the exported CA", n)
+		if err := os.Remove(filepath.Join(dir, n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importCert installs a certificate authority, unless a different one already exists.
+// It returns the path it wrote to, if any, even when writing failed.
+func importCert(r io.Reader, name string, mode os.FileMode) (conflict bool, written string, err error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return false, "", err
+	}
+	dst := localpath.MakeMiniPath(name)
+	existing, err := ioutil.ReadFile(dst)
+	if err == nil {
+		return !bytes.Equal(existing, b), "", nil
+	}
+	if !os.IsNotExist(err) {
+		return false, "", err
+	}
+	return false, dst, writeFile(bytes.NewReader(b), dst, mode)
+}
+
+func writeFile(r io.Reader, dst string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrapf(err, "mkdir %s", filepath.Dir(dst))
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "writing %s", dst)
+	}
+	return f.Close()
+}
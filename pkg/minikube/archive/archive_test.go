@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+
+	src := filepath.Join(tmp, "src")
+	os.Setenv(localpath.MinikubeHome, src)
+	for _, name := range caFiles {
+		writeTestFile(t, localpath.MakeMiniPath(name), "source "+name)
+	}
+	writeTestFile(t, filepath.Join(localpath.Profile("p1"), "config.json"), `{"Name": "p1"}`)
+	writeTestFile(t, filepath.Join(localpath.Profile("p1"), "apiserver.crt"), "apiserver")
+	writeTestFile(t, filepath.Join(localpath.Profile("p1"), "pid"), "1234")
+	writeTestFile(t, filepath.Join(localpath.Profile("p1"), "snapshots", "old", "snapshot.json"), "{}")
+
+	dst := filepath.Join(tmp, "p1.tar.zst")
+	if _, err := Export(nil, config.ClusterConfig{Name: "p1"}, dst, ExportOptions{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	// import into a home which already has a different CA
+	os.Setenv(localpath.MinikubeHome, filepath.Join(tmp, "dst"))
+	writeTestFile(t, localpath.MakeMiniPath("ca.crt"), "local ca.crt")
+
+	res, err := Import(dst)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if res.Profile != "p1" {
+		t.Errorf("imported profile = %q, want p1", res.Profile)
+	}
+	if len(res.ConflictingCerts) != 1 || res.ConflictingCerts[0] != "ca.crt" {
+		t.Errorf("conflicting certs = %v, want [ca.crt]", res.ConflictingCerts)
+	}
+	for path, want := range map[string]string{
+		localpath.MakeMiniPath("ca.crt"): "local ca.crt",
+		localpath.MakeMiniPath("ca.key"): "source ca.key",
+	} {
+		got, err := ioutil.ReadFile(path)
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v, want %q", path, got, err, want)
+		}
+	}
+	for _, path := range []string{
+		filepath.Join(localpath.Profile("p1"), "pid"),
+		filepath.Join(localpath.Profile("p1"), "snapshots", "old"),
+		// signed by the exported CA, which was not imported
+		filepath.Join(localpath.Profile("p1"), "apiserver.crt"),
+	} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should not have been imported", path)
+		}
+	}
+
+	if _, err := Import(dst); err == nil {
+		t.Errorf("importing an existing profile should fail")
+	}
+
+	// the machine of a deleted cluster of the same name is left over
+	if err := os.RemoveAll(localpath.Profile("p1")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	writeTestFile(t, filepath.Join(localpath.MachinePath("p1"), "config.json"), "{}")
+	if _, err := Import(dst); err == nil {
+		t.Errorf("importing a profile over an existing machine should fail")
+	}
+	if config.ProfileExists("p1") {
+		t.Errorf("a refused import should not leave profile p1 behind")
+	}
+}
+
+func TestImportFailureLeavesNothingBehind(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, filepath.Join(tmp, "home"))
+
+	// an archive whose last entry is invalid, after the profile and a CA were read
+	src := filepath.Join(tmp, "broken.tar.zst")
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	zw, err := zstd.NewWriter(f)
+	if err != nil {
+		t.Fatalf("zstd: %v", err)
+	}
+	tw := tar.NewWriter(zw)
+	manifest, _ := json.Marshal(Manifest{Profile: "p1"})
+	for _, e := range []struct {
+		name    string
+		content []byte
+	}{
+		{manifestFile, manifest},
+		{profileDir + "/config.json", []byte(`{"Name": "p1"}`)},
+		{certsDir + "/ca.crt", []byte("source ca.crt")},
+		{"../outside", []byte("evil")},
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("header: %v", err)
+		}
+		if _, err := tw.Write(e.content); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar close: %v", err)
+	}
+	zw.Close()
+	f.Close()
+
+	if _, err := Import(src); err == nil {
+		t.Fatalf("importing an invalid archive should fail")
+	}
+	if config.ProfileExists("p1") {
+		t.Errorf("a failed import should not leave profile p1 behind")
+	}
+	for _, path := range []string{localpath.Profile("p1"), localpath.MakeMiniPath("ca.crt")} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should have been removed, got %v", path, err)
+		}
+	}
+	entries, err := ioutil.ReadDir(localpath.MiniPath())
+	if err != nil {
+		t.Fatalf("readdir: %v", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != "profiles" {
+			t.Errorf("unexpected leftover %s", e.Name())
+		}
+	}
+}
@@ -39,6 +39,9 @@ type Addon struct {
 
 	// Registries currently only shows the default registry of images
 	Registries map[string]string
+
+	// Bundle describes addons installed with 'minikube addons install', it is nil for built-in addons
+	Bundle *AddonBundle
 }
 
 // NetworkInfo contains control plane node IP address used for add on template
@@ -340,6 +343,15 @@ var Addons = map[string]*Addon{
 	}, map[string]string{
 		"KubeRegistryProxy": "gcr.io",
 	}),
+	"registry-mirror": NewAddon([]*BinAsset{
+		MustBinAsset(addons.RegistryMirrorAssets,
+			"registry-mirror/registry-mirror-ds.yaml.tmpl",
+			vmpath.GuestAddonsDir,
+			"registry-mirror-ds.yaml",
+			"0640"),
+	}, false, "registry-mirror", map[string]string{
+		"Registry": "registry:2.7.1@sha256:d5459fcb27aecc752520df4b492b08358a1912fcdfa454f7d2101d4b09991daa",
+	}, nil),
 	"registry-creds": NewAddon([]*BinAsset{
 		MustBinAsset(addons.RegistryCredsAssets,
 			"registry-creds/registry-creds-rc.yaml.tmpl",
@@ -416,6 +428,17 @@ var Addons = map[string]*Addon{
 	}, false, "nvidia-gpu-device-plugin", map[string]string{
 		"NvidiaDevicePlugin": "nvidia/k8s-device-plugin:1.0.0-beta4@sha256:94d46bf513cbc43c4d77a364e4bbd409d32d89c8e686e12551cc3eb27c259b90",
 	}, nil),
+	"amd-gpu-device-plugin": NewAddon([]*BinAsset{
+		MustBinAsset(addons.AmdGpuDevicePluginAssets,
+			"gpu/amd-gpu-device-plugin.yaml.tmpl",
+			vmpath.GuestAddonsDir,
+			"amd-gpu-device-plugin.yaml",
+			"0640"),
+	}, false, "amd-gpu-device-plugin", map[string]string{
+		"AmdDevicePlugin": "rocm/k8s-device-plugin:1.18.0",
+	}, map[string]string{
+		"AmdDevicePlugin": "docker.io",
+	}),
 	"logviewer": NewAddon([]*BinAsset{
 		MustBinAsset(addons.LogviewerAssets,
 			"logviewer/logviewer-dp-and-svc.yaml.tmpl",
@@ -769,7 +792,7 @@ func SelectAndPersistImages(addon *Addon, cc *config.ClusterConfig) (images, cus
 }
 
 // GenerateTemplateData generates template data for template assets
-func GenerateTemplateData(addon *Addon, cfg config.KubernetesConfig, netInfo NetworkInfo, images, customRegistries map[string]string) interface{} {
+func GenerateTemplateData(addon *Addon, cfg config.KubernetesConfig, netInfo NetworkInfo, images, customRegistries, values map[string]string) interface{} {
 
 	a := runtime.GOARCH
 	// Some legacy docker images still need the -arch suffix
@@ -790,6 +813,7 @@ func GenerateTemplateData(addon *Addon, cfg config.KubernetesConfig, netInfo Net
 		Registries          map[string]string
 		CustomRegistries    map[string]string
 		NetworkInfo         map[string]string
+		Values              map[string]string
 	}{
 		Arch:                a,
 		ExoticArch:          ea,
@@ -801,6 +825,7 @@ func GenerateTemplateData(addon *Addon, cfg config.KubernetesConfig, netInfo Net
 		Registries:          addon.Registries,
 		CustomRegistries:    customRegistries,
 		NetworkInfo:         make(map[string]string),
+		Values:              addonValues(addon, values),
 	}
 	if opts.ImageRepository != "" && !strings.HasSuffix(opts.ImageRepository, "/") {
 		opts.ImageRepository += "/"
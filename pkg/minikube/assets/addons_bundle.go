@@ -0,0 +1,186 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+const (
+	// AddonBundleFile is the file describing an addon bundle
+	AddonBundleFile = "addon.yaml"
+	// AddonManifestsDir is the directory of an addon bundle holding its manifests
+	AddonManifestsDir = "manifests"
+)
+
+var validAddonName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// AddonBundle describes an addon installed with 'minikube addons install'
+type AddonBundle struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Maintainer  string `yaml:"maintainer,omitempty"`
+	// Images and Registries have the same meaning as for the built-in addons
+	Images     map[string]string `yaml:"images,omitempty"`
+	Registries map[string]string `yaml:"registries,omitempty"`
+	// Parameters can be set with 'minikube addons configure', and are available to the manifests as {{.Values.<name>}}
+	Parameters []AddonParameter `yaml:"parameters,omitempty"`
+
+	// Source is the reference the bundle was installed from, and Digest the digest of its manifest
+	Source string `yaml:"source,omitempty"`
+	Digest string `yaml:"digest,omitempty"`
+	// Signed is whether the signature of the bundle was verified on install
+	Signed bool `yaml:"signed,omitempty"`
+}
+
+// AddonParameter is a value of an addon bundle which can be configured by the user
+type AddonParameter struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Default     string `yaml:"default,omitempty"`
+}
+
+// InstalledAddonsDir returns the directory addon bundles are installed to
+func InstalledAddonsDir() string {
+	// not "addons", which is synced to the addons directory of the node as is
+	return localpath.MakeMiniPath("addon-bundles")
+}
+
+// ReadAddonBundle reads and validates the addon.yaml of the bundle in dir
+func ReadAddonBundle(dir string) (*AddonBundle, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, AddonBundleFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading addon bundle")
+	}
+	b := &AddonBundle{}
+	if err := yaml.Unmarshal(data, b); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", AddonBundleFile)
+	}
+	if !validAddonName.MatchString(b.Name) {
+		return nil, errors.Errorf("invalid addon name %q: must consist of lower case alphanumeric characters or '-'", b.Name)
+	}
+	for _, p := range b.Parameters {
+		if p.Name == "" {
+			return nil, errors.Errorf("addon %s has a parameter without name", b.Name)
+		}
+	}
+	return b, nil
+}
+
+// WriteAddonBundle writes the addon.yaml of the bundle in dir
+func WriteAddonBundle(dir string, b *AddonBundle) error {
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	return ioutil.WriteFile(filepath.Join(dir, AddonBundleFile), data, 0644)
+}
+
+// LoadAddonBundle creates the addon for the bundle in dir, its manifests being
+// copied to the addons directory of the node with the addon name as prefix
+func LoadAddonBundle(dir string) (*Addon, error) {
+	b, err := ReadAddonBundle(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(dir, AddonManifestsDir))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading manifests")
+	}
+	fsys := os.DirFS(dir)
+	var manifests []*BinAsset
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".tmpl")
+		if e.IsDir() || (!strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml")) {
+			continue
+		}
+		asset, err := NewBinAsset(fsys, AddonManifestsDir+"/"+e.Name(), vmpath.GuestAddonsDir, b.Name+"-"+name, "0640")
+		if err != nil {
+			return nil, errors.Wrapf(err, "manifest %s", e.Name())
+		}
+		manifests = append(manifests, asset)
+	}
+	if len(manifests) == 0 {
+		return nil, errors.Errorf("addon %s has no manifests", b.Name)
+	}
+
+	a := NewAddon(manifests, false, b.Name, b.Images, b.Registries)
+	a.Bundle = b
+	return a, nil
+}
+
+// RegisterInstalledAddons adds the installed addon bundles to Addons, and returns their names
+func RegisterInstalledAddons() []string {
+	entries, err := ioutil.ReadDir(InstalledAddonsDir())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("unable to list installed addons: %v", err)
+		}
+		return nil
+	}
+
+	var names []string
+	for _, e := range entries {
+		// hidden directories hold bundles being installed
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		a, err := LoadAddonBundle(filepath.Join(InstalledAddonsDir(), e.Name()))
+		if err != nil {
+			klog.Warningf("skipping installed addon %s: %v", e.Name(), err)
+			continue
+		}
+		if existing, ok := Addons[a.Name()]; ok && existing.Bundle == nil {
+			klog.Warningf("skipping installed addon %s: conflicts with a built-in addon", a.Name())
+			continue
+		}
+		Addons[a.Name()] = a
+		names = append(names, a.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addonValues returns the values of the parameters of an installed addon, values
+// configured by the user taking precedence over the defaults of the bundle
+func addonValues(addon *Addon, configured map[string]string) map[string]string {
+	values := map[string]string{}
+	if addon.Bundle == nil {
+		return values
+	}
+	for _, p := range addon.Bundle.Parameters {
+		values[p.Name] = p.Default
+		if v, ok := configured[p.Name]; ok {
+			values[p.Name] = v
+		}
+	}
+	return values
+}
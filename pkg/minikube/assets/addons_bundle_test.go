@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadAddonBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addon-bundle")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		AddonBundleFile: `name: my-addon
+version: 1.2.0
+images:
+  Controller: acme/controller:1.2
+registries:
+  Controller: ghcr.io
+parameters:
+- name: replicas
+  default: "1"
+- name: logLevel
+`,
+		"manifests/deployment.yaml.tmpl": "replicas: {{.Values.replicas}}\n",
+		"manifests/service.yaml":         "kind: Service\n",
+		"manifests/README.md":            "not a manifest\n",
+	}
+	if err := os.Mkdir(filepath.Join(dir, AddonManifestsDir), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+
+	a, err := LoadAddonBundle(dir)
+	if err != nil {
+		t.Fatalf("LoadAddonBundle: %v", err)
+	}
+	if a.Name() != "my-addon" || a.Bundle == nil || a.Bundle.Version != "1.2.0" {
+		t.Errorf("unexpected addon %+v", a)
+	}
+	var targets []string
+	for _, m := range a.Assets {
+		targets = append(targets, m.GetTargetName())
+	}
+	if want := []string{"my-addon-deployment.yaml", "my-addon-service.yaml"}; !reflect.DeepEqual(targets, want) {
+		t.Errorf("targets = %v, want %v", targets, want)
+	}
+
+	values := addonValues(a, map[string]string{"logLevel": "debug", "unknown": "x"})
+	if want := map[string]string{"replicas": "1", "logLevel": "debug"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, AddonBundleFile), []byte("name: My_Addon\n"), 0644); err != nil {
+		t.Fatalf("writing: %v", err)
+	}
+	if _, err := LoadAddonBundle(dir); err == nil {
+		t.Errorf("expected an error for an invalid addon name")
+	}
+}
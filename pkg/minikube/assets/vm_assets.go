@@ -18,10 +18,10 @@ package assets
 
 import (
 	"bytes"
-	"embed"
 	"fmt"
 	"html/template"
 	"io"
+	"io/fs"
 	"os"
 	"path"
 	"time"
@@ -206,9 +206,66 @@ func NewMemoryAsset(d []byte, targetDir, targetName, permissions string) *Memory
 	}
 }
 
-// BinAsset is a bindata (binary data) asset
+// StreamAsset is an asset produced by a writer while it is read, for contents too large to hold in memory or a temporary file
+type StreamAsset struct {
+	BaseAsset
+	write  func(io.Writer) error
+	reader *io.PipeReader
+	length int
+}
+
+// NewStreamAsset creates a new StreamAsset of length bytes, written by write
+func NewStreamAsset(write func(io.Writer) error, length int, targetDir, targetName, permissions string) *StreamAsset {
+	return &StreamAsset{
+		BaseAsset: BaseAsset{
+			TargetDir:   targetDir,
+			TargetName:  targetName,
+			Permissions: permissions,
+			SourcePath:  MemorySource,
+		},
+		write:  write,
+		length: length,
+	}
+}
+
+// GetLength returns length
+func (s *StreamAsset) GetLength() int {
+	return s.length
+}
+
+// Read reads the asset, starting the writer on the first read
+func (s *StreamAsset) Read(p []byte) (int, error) {
+	if s.reader == nil {
+		r, w := io.Pipe()
+		go func() {
+			w.CloseWithError(s.write(w))
+		}()
+		s.reader = r
+	}
+	return s.reader.Read(p)
+}
+
+// Seek restarts the asset, only seeking to the start is supported
+func (s *StreamAsset) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, errors.New("a stream asset can only seek to the start")
+	}
+	return 0, s.Close()
+}
+
+// Close stops the writer
+func (s *StreamAsset) Close() error {
+	if s.reader == nil {
+		return nil
+	}
+	err := s.reader.Close()
+	s.reader = nil
+	return err
+}
+
+// BinAsset is a bindata (binary data) asset, read from the embedded addons or an installed addon bundle
 type BinAsset struct {
-	embed.FS
+	FS fs.FS
 	BaseAsset
 	reader   io.ReadSeeker
 	template *template.Template
@@ -216,8 +273,8 @@ type BinAsset struct {
 }
 
 // MustBinAsset creates a new BinAsset, or panics if invalid
-func MustBinAsset(fs embed.FS, name, targetDir, targetName, permissions string) *BinAsset {
-	asset, err := NewBinAsset(fs, name, targetDir, targetName, permissions)
+func MustBinAsset(fsys fs.FS, name, targetDir, targetName, permissions string) *BinAsset {
+	asset, err := NewBinAsset(fsys, name, targetDir, targetName, permissions)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to define asset %s: %v", name, err))
 	}
@@ -225,9 +282,9 @@ func MustBinAsset(fs embed.FS, name, targetDir, targetName, permissions string)
 }
 
 // NewBinAsset creates a new BinAsset
-func NewBinAsset(fs embed.FS, name, targetDir, targetName, permissions string) (*BinAsset, error) {
+func NewBinAsset(fsys fs.FS, name, targetDir, targetName, permissions string) (*BinAsset, error) {
 	m := &BinAsset{
-		FS: fs,
+		FS: fsys,
 		BaseAsset: BaseAsset{
 			SourcePath:  name,
 			TargetDir:   targetDir,
@@ -252,7 +309,7 @@ func defaultValue(defValue string, val interface{}) string {
 }
 
 func (m *BinAsset) loadData() error {
-	contents, err := m.FS.ReadFile(m.SourcePath)
+	contents, err := fs.ReadFile(m.FS, m.SourcePath)
 	if err != nil {
 		return err
 	}
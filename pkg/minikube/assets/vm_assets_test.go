@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestStreamAsset(t *testing.T) {
+	content := strings.Repeat("minikube", 1024)
+	writes := 0
+	f := NewStreamAsset(func(w io.Writer) error {
+		writes++
+		_, err := io.WriteString(w, content)
+		return err
+	}, len(content), "/var/lib/minikube/images", "busybox_latest", "0644")
+
+	if f.GetSourcePath() != MemorySource || f.GetLength() != len(content) {
+		t.Errorf("got source %q and length %d, want %q and %d", f.GetSourcePath(), f.GetLength(), MemorySource, len(content))
+	}
+	if writes != 0 {
+		t.Errorf("the writer ran %d times before the asset was read", writes)
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := ioutil.ReadAll(f)
+		if err != nil {
+			t.Fatalf("read %d: %v", i, err)
+		}
+		if string(got) != content {
+			t.Errorf("read %d: got %d bytes, want %d", i, len(got), len(content))
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			t.Fatalf("seek %d: %v", i, err)
+		}
+	}
+	if writes != 2 {
+		t.Errorf("the writer ran %d times, want 2", writes)
+	}
+
+	if _, err := f.Seek(10, io.SeekStart); err == nil {
+		t.Errorf("seeking past the start succeeded")
+	}
+	if err := f.Close(); err != nil {
+		t.Errorf("close: %v", err)
+	}
+}
@@ -56,7 +56,19 @@ func Log(startTime time.Time) {
 		return
 	}
 	r := newRow(os.Args[1], args(), userName(), version.GetVersion(), startTime, time.Now())
-	if err := appendToLog(r); err != nil {
+	bs, err := marshal(r)
+	if err != nil {
+		klog.Warning(err)
+		return
+	}
+	s := loadSettings()
+	if err := appendToLog(bs, s); err != nil {
+		klog.Warning(err)
+	}
+	if s.forward == "" {
+		return
+	}
+	if err := forward(s.forward, bs); err != nil {
 		klog.Warning(err)
 	}
 }
@@ -73,7 +85,7 @@ func shouldLog() bool {
 	}
 
 	// commands that should not be logged.
-	no := []string{"status", "version"}
+	no := []string{"audit", "status", "version"}
 	a := os.Args[1]
 	for _, c := range no {
 		if a == c {
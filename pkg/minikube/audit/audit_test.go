@@ -111,6 +111,10 @@ func TestAudit(t *testing.T) {
 				[]string{"minikube", "version"},
 				false,
 			},
+			{
+				[]string{"minikube", "audit", "list"},
+				false,
+			},
 			{
 				[]string{"minikube"},
 				false,
@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// forwardTimeout is how long forwarding an audit event may take, as it delays the exit of every command
+var forwardTimeout = 5 * time.Second
+
+// forward sends the JSON Cloud Event of a row to the target set with audit.forward.
+func forward(target string, event []byte) error {
+	if err := ValidateForward(target); err != nil {
+		return err
+	}
+	if target == "syslog" {
+		return toSyslog("", "", event)
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "udp" || u.Scheme == "tcp" {
+		return toSyslog(u.Scheme, u.Host, event)
+	}
+	return toWebhook(target, event)
+}
+
+// toWebhook posts the event to a webhook, in the structured mode of the Cloud Events HTTP binding.
+func toWebhook(target string, event []byte) error {
+	client := &http.Client{Timeout: forwardTimeout}
+	resp, err := client.Post(target, "application/cloudevents+json", bytes.NewReader(event))
+	if err != nil {
+		return fmt.Errorf("unable to post audit event to %s: %v", target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response posting audit event to %s: %s", target, resp.Status)
+	}
+	return nil
+}
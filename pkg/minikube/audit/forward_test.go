@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateForward(t *testing.T) {
+	tests := []struct {
+		target    string
+		shouldErr bool
+	}{
+		{"syslog", false},
+		{"udp://logs.example.com:514", false},
+		{"tcp://127.0.0.1:601", false},
+		{"https://audit.example.com/events", false},
+		{"udp://logs.example.com", true},
+		{"https://", true},
+		{"ftp://logs.example.com", true},
+		{"/var/log/minikube", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.target, func(t *testing.T) {
+			err := ValidateForward(tc.target)
+			if (err != nil) != tc.shouldErr {
+				t.Errorf("ValidateForward(%q) error = %v, shouldErr %t", tc.target, err, tc.shouldErr)
+			}
+		})
+	}
+}
+
+func TestForwardWebhook(t *testing.T) {
+	event := []byte(`{"type":"io.k8s.sigs.minikube.audit"}`)
+	var got []byte
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		got, _ = ioutil.ReadAll(r.Body)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	if err := forward(srv.URL+"/events", event); err != nil {
+		t.Fatalf("forward() error = %v", err)
+	}
+	if string(got) != string(event) {
+		t.Errorf("webhook got %q, want %q", got, event)
+	}
+	if contentType != "application/cloudevents+json" {
+		t.Errorf("webhook got content type %q, want application/cloudevents+json", contentType)
+	}
+	if err := forward(srv.URL+"/fail", event); err == nil {
+		t.Errorf("forward() to a failing webhook should fail")
+	}
+}
@@ -19,9 +19,17 @@ package audit
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/juju/mutex"
+	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/util/lock"
 )
 
 // currentLogFile the file that's used to store audit logs
@@ -29,7 +37,11 @@ var currentLogFile *os.File
 
 // setLogFile sets the logPath and creates the log file if it doesn't exist.
 func setLogFile() error {
-	lp := localpath.AuditLog()
+	return openLogFile(localpath.AuditLog())
+}
+
+// openLogFile opens the log file at lp for appending, creating it if it doesn't exist.
+func openLogFile(lp string) error {
 	f, err := os.OpenFile(lp, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
 		return fmt.Errorf("unable to open %s: %v", lp, err)
@@ -38,20 +50,136 @@ func setLogFile() error {
 	return nil
 }
 
-// appendToLog appends the row to the log file.
-func appendToLog(row *row) error {
+// appendToLog appends the event of a row to the log file, rotating it first if the event would take it over the size cap.
+// The log is locked meanwhile, as other minikube commands may be rotating it at the same time.
+func appendToLog(event []byte, s settings) error {
 	if currentLogFile == nil {
 		if err := setLogFile(); err != nil {
 			return err
 		}
 	}
-	ce := register.CloudEvent(row, row.toMap())
-	bs, err := ce.MarshalJSON()
+	r, err := lockLog(currentLogFile.Name())
+	if err != nil {
+		return fmt.Errorf("unable to lock audit log: %v", err)
+	}
+	defer r.Release()
+
+	if err := reopenIfRotated(); err != nil {
+		return err
+	}
+	fi, err := currentLogFile.Stat()
 	if err != nil {
-		return fmt.Errorf("error marshalling event: %v", err)
+		return fmt.Errorf("unable to stat audit log: %v", err)
+	}
+	if fi.Size() > 0 && fi.Size()+int64(len(event))+1 > s.maxSize {
+		if err := rotate(s.maxBackups); err != nil {
+			return err
+		}
 	}
-	if _, err := currentLogFile.WriteString(string(bs) + "\n"); err != nil {
+	if _, err := currentLogFile.WriteString(string(event) + "\n"); err != nil {
 		return fmt.Errorf("unable to write to audit log: %v", err)
 	}
 	return nil
 }
+
+// lockLog locks the log file at lp against the other minikube commands
+func lockLog(lp string) (mutex.Releaser, error) {
+	spec := lock.PathMutexSpec(lp)
+	spec.Timeout = 10 * time.Second
+	return mutex.Acquire(spec)
+}
+
+// reopenIfRotated opens the log file again if another minikube command rotated it since it was opened,
+// so that events are not appended to a backup.
+func reopenIfRotated() error {
+	lp := currentLogFile.Name()
+	opened, err := currentLogFile.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to stat audit log: %v", err)
+	}
+	current, err := os.Stat(lp)
+	if err == nil && os.SameFile(opened, current) {
+		return nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to stat %s: %v", lp, err)
+	}
+	if err := currentLogFile.Close(); err != nil {
+		klog.Warningf("unable to close %s: %v", lp, err)
+	}
+	return openLogFile(lp)
+}
+
+// marshal converts the row to its JSON Cloud Event.
+func marshal(row *row) ([]byte, error) {
+	ce := register.CloudEvent(row, row.toMap())
+	bs, err := ce.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling event: %v", err)
+	}
+	return bs, nil
+}
+
+// rotate moves the log file to audit.json.1, shifting the older backups up and removing the ones beyond maxBackups,
+// then starts a new log file. The log must be locked by the caller.
+func rotate(maxBackups int) error {
+	lp := currentLogFile.Name()
+	if err := currentLogFile.Close(); err != nil {
+		klog.Warningf("unable to close %s: %v", lp, err)
+	}
+	currentLogFile = nil
+
+	backups, err := backupFiles(lp)
+	if err != nil {
+		return err
+	}
+	// from the oldest, so that no backup is overwritten before it is moved
+	for i := len(backups) - 1; i >= 0; i-- {
+		n := i + 1
+		if n >= maxBackups {
+			if err := os.Remove(backups[i]); err != nil {
+				return fmt.Errorf("unable to remove %s: %v", backups[i], err)
+			}
+			continue
+		}
+		if err := os.Rename(backups[i], backupFile(lp, n+1)); err != nil {
+			return fmt.Errorf("unable to rotate %s: %v", backups[i], err)
+		}
+	}
+	if maxBackups > 0 {
+		err = os.Rename(lp, backupFile(lp, 1))
+	} else {
+		err = os.Remove(lp)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to rotate %s: %v", lp, err)
+	}
+	return openLogFile(lp)
+}
+
+// backupFile returns the path of the nth rotated log file, 1 being the most recent.
+func backupFile(lp string, n int) string {
+	return fmt.Sprintf("%s.%d", lp, n)
+}
+
+// backupFiles returns the rotated log files, from the most recent to the oldest.
+func backupFiles(lp string) ([]string, error) {
+	matches, err := filepath.Glob(lp + ".*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list rotated audit logs: %v", err)
+	}
+	nums := []int{}
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(m, lp+"."))
+		if err != nil || n <= 0 {
+			continue
+		}
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	files := []string{}
+	for _, n := range nums {
+		files = append(files, backupFile(lp, n))
+	}
+	return files, nil
+}
@@ -50,7 +50,11 @@ func TestLogFile(t *testing.T) {
 		currentLogFile = f
 
 		r := newRow("start", "-v", "user1", "v0.17.1", time.Now(), time.Now())
-		if err := appendToLog(r); err != nil {
+		bs, err := marshal(r)
+		if err != nil {
+			t.Fatalf("Error marshalling row: %v", err)
+		}
+		if err := appendToLog(bs, settings{maxSize: defaultMaxSize, maxBackups: defaultMaxBackups}); err != nil {
 			t.Fatalf("Error appendingToLog: %v", err)
 		}
 
@@ -59,4 +63,84 @@ func TestLogFile(t *testing.T) {
 			t.Errorf("Log was not appended to file: %v", err)
 		}
 	})
+	t.Run("Rotate", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "audit")
+		if err != nil {
+			t.Fatalf("Error creating temporary directory: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		oldLogFile := *currentLogFile
+		defer func() { currentLogFile = &oldLogFile }()
+		lp := filepath.Join(dir, "audit.json")
+		if err := openLogFile(lp); err != nil {
+			t.Fatal(err)
+		}
+
+		bs, err := marshal(newRow("start", "-v", "user1", "v0.17.1", time.Now(), time.Now()))
+		if err != nil {
+			t.Fatalf("Error marshalling row: %v", err)
+		}
+		// each event is over the size cap, so each append rotates the previous one
+		s := settings{maxSize: 10, maxBackups: 2}
+		for i := 0; i < 4; i++ {
+			if err := appendToLog(bs, s); err != nil {
+				t.Fatalf("Error appendingToLog: %v", err)
+			}
+		}
+		defer currentLogFile.Close()
+
+		backups, err := backupFiles(lp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{lp + ".1", lp + ".2"}
+		if len(backups) != len(want) || backups[0] != want[0] || backups[1] != want[1] {
+			t.Errorf("backupFiles() = %q, want %q", backups, want)
+		}
+		for _, f := range append(backups, lp) {
+			b, err := ioutil.ReadFile(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != string(bs)+"\n" {
+				t.Errorf("%s = %q, want a single event", f, b)
+			}
+		}
+	})
+	t.Run("RotatedByAnotherCommand", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "audit")
+		if err != nil {
+			t.Fatalf("Error creating temporary directory: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		oldLogFile := *currentLogFile
+		defer func() { currentLogFile = &oldLogFile }()
+		lp := filepath.Join(dir, "audit.json")
+		if err := openLogFile(lp); err != nil {
+			t.Fatal(err)
+		}
+		// another command rotates the log while this one has it open
+		if err := os.Rename(lp, backupFile(lp, 1)); err != nil {
+			t.Fatal(err)
+		}
+
+		bs, err := marshal(newRow("start", "-v", "user1", "v0.17.1", time.Now(), time.Now()))
+		if err != nil {
+			t.Fatalf("Error marshalling row: %v", err)
+		}
+		if err := appendToLog(bs, settings{maxSize: defaultMaxSize, maxBackups: defaultMaxBackups}); err != nil {
+			t.Fatalf("Error appendingToLog: %v", err)
+		}
+		defer currentLogFile.Close()
+
+		b, err := ioutil.ReadFile(lp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != string(bs)+"\n" {
+			t.Errorf("%s = %q, want the event appended after the rotation", lp, b)
+		}
+	})
 }
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// Filter selects the rows of the audit log, the fields left empty select all the rows.
+type Filter struct {
+	User    string
+	Command string
+	Profile string
+	// Since selects the commands started at or after it
+	Since time.Time
+}
+
+// matches returns whether the row is selected by the filter.
+func (f Filter) matches(r row) bool {
+	if f.User != "" && r.user != f.User {
+		return false
+	}
+	if f.Command != "" && r.command != f.Command {
+		return false
+	}
+	if f.Profile != "" && r.profile != f.Profile {
+		return false
+	}
+	if !f.Since.IsZero() {
+		start, err := time.Parse(constants.TimeFormat, r.startTime)
+		if err != nil {
+			klog.Warningf("unable to parse start time %q: %v", r.startTime, err)
+			return false
+		}
+		if start.Before(f.Since) {
+			return false
+		}
+	}
+	return true
+}
+
+// Query returns the rows of the audit log and of its rotated logs selected by the filter, from the oldest.
+func Query(f Filter) (*RawReport, error) {
+	lp := localpath.AuditLog()
+	backups, err := backupFiles(lp)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for i := len(backups) - 1; i >= 0; i-- {
+		files = append(files, backups[i])
+	}
+	rows, skipped, err := queryFiles(append(files, lp), f)
+	if err != nil {
+		return nil, err
+	}
+	r := &RawReport{
+		headers: []string{"Command", "Args", "Profile", "User", "Version", "Start Time", "End Time"},
+		rows:    rows,
+		skipped: skipped,
+	}
+	return r, nil
+}
+
+// queryFiles returns the rows of the log files selected by the filter, in the order of the files,
+// and how many malformed lines were skipped.
+func queryFiles(files []string, f Filter) ([]row, int, error) {
+	rows := []row{}
+	skipped := 0
+	for _, file := range files {
+		logs, err := readLines(file)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, l := range logs {
+			r, err := logToRow(l)
+			if err != nil {
+				klog.Warningf("skipping malformed line of %s: %v", file, err)
+				skipped++
+				continue
+			}
+			if f.matches(r) {
+				rows = append(rows, r)
+			}
+		}
+	}
+	return rows, skipped, nil
+}
+
+// readLines returns the lines of a log file, none if it doesn't exist.
+func readLines(file string) ([]string, error) {
+	fh, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to open %s: %v", file, err)
+	}
+	defer fh.Close()
+	var logs []string
+	s := bufio.NewScanner(fh)
+	for s.Scan() {
+		if s.Text() == "" {
+			continue
+		}
+		logs = append(logs, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read from %s: %v", file, err)
+	}
+	return logs, nil
+}
+
+// Rows returns the rows of the report as maps of their fields, to be output as JSON.
+func (rr *RawReport) Rows() []map[string]string {
+	maps := []map[string]string{}
+	for _, r := range rr.rows {
+		maps = append(maps, r.toMap())
+	}
+	return maps
+}
+
+// Skipped returns the number of malformed log lines left out of the report.
+func (rr *RawReport) Skipped() int {
+	return rr.skipped
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQueryFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("failed creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	old := `{"data":{"args":"-p mini1","command":"start","endTime":"Tue, 02 Feb 2021 15:33:05 UTC","profile":"mini1","startTime":"Tue, 02 Feb 2021 15:30:33 UTC","user":"user1"},"datacontenttype":"application/json","id":"9b7593cb-fbec-49e5-a3ce-bdc2d0bfb208","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.audit"}
+`
+	current := `{"data":{"args":"-p mini1","command":"stop","endTime":"Wed, 03 Feb 2021 15:33:05 UTC","profile":"mini1","startTime":"Wed, 03 Feb 2021 15:30:33 UTC","user":"user1"},"datacontenttype":"application/json","id":"9b7593cb-fbec-49e5-a3ce-bdc2d0bfb209","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.audit"}
+
+{"data":{"args":"-p mini1","command":"delete",
+{"data":{"args":"","command":"start","endTime":"Wed, 03 Feb 2021 16:46:20 UTC","profile":"minikube","startTime":"Wed, 03 Feb 2021 16:46:00 UTC","user":"user2"},"datacontenttype":"application/json","id":"fec03227-2484-48b6-880a-88fd010b5efd","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.audit"}
+`
+	files := []string{filepath.Join(dir, "audit.json.1"), filepath.Join(dir, "audit.json")}
+	for i, s := range []string{old, current} {
+		if err := ioutil.WriteFile(files[i], []byte(s), 0644); err != nil {
+			t.Fatalf("failed writing %s: %v", files[i], err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"All", Filter{}, []string{"start mini1", "stop mini1", "start minikube"}},
+		{"User", Filter{User: "user1"}, []string{"start mini1", "stop mini1"}},
+		{"Command", Filter{Command: "start"}, []string{"start mini1", "start minikube"}},
+		{"Profile", Filter{Profile: "minikube"}, []string{"start minikube"}},
+		{"Since", Filter{Since: time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC)}, []string{"stop mini1", "start minikube"}},
+		{"UserAndCommand", Filter{User: "user1", Command: "start"}, []string{"start mini1"}},
+		{"None", Filter{User: "user3"}, []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, skipped, err := queryFiles(append(files, filepath.Join(dir, "missing.json")), tc.filter)
+			if err != nil {
+				t.Fatalf("queryFiles() error = %v", err)
+			}
+			if skipped != 1 {
+				t.Errorf("queryFiles() skipped %d lines, want 1", skipped)
+			}
+			got := []string{}
+			for _, r := range rows {
+				got = append(got, r.command+" "+r.profile)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("queryFiles() = %q, want %q", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("queryFiles() = %q, want %q", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestReportRows(t *testing.T) {
+	r := &RawReport{rows: []row{*newRow("start", "-v", "user1", "v1.22.0", time.Now(), time.Now(), "mini1")}}
+	got := r.Rows()
+	if len(got) != 1 || got[0]["user"] != "user1" || got[0]["profile"] != "mini1" || got[0]["command"] != "start" {
+		t.Errorf("Rows() = %v, want the fields of the row", got)
+	}
+}
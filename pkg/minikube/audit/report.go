@@ -25,6 +25,8 @@ import (
 type RawReport struct {
 	headers []string
 	rows    []row
+	// skipped is the number of malformed log lines left out of the rows
+	skipped int
 }
 
 // Report is created using the last n lines from the log file.
@@ -54,8 +56,8 @@ func Report(lastNLines int) (*RawReport, error) {
 		return nil, fmt.Errorf("failed to convert logs to rows: %v", err)
 	}
 	r := &RawReport{
-		[]string{"Command", "Args", "Profile", "User", "Version", "Start Time", "End Time"},
-		rows,
+		headers: []string{"Command", "Args", "Profile", "User", "Version", "Start Time", "End Time"},
+		rows:    rows,
 	}
 	return r, nil
 }
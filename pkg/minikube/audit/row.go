@@ -98,16 +98,25 @@ func (e *row) toFields() []string {
 func logsToRows(logs []string) ([]row, error) {
 	rows := []row{}
 	for _, l := range logs {
-		r := row{}
-		if err := json.Unmarshal([]byte(l), &r); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal %q: %v", l, err)
+		r, err := logToRow(l)
+		if err != nil {
+			return nil, err
 		}
-		r.assignFields()
 		rows = append(rows, r)
 	}
 	return rows, nil
 }
 
+// logToRow converts an audit log line into a row.
+func logToRow(l string) (row, error) {
+	r := row{}
+	if err := json.Unmarshal([]byte(l), &r); err != nil {
+		return r, fmt.Errorf("failed to unmarshal %q: %v", l, err)
+	}
+	r.assignFields()
+	return r, nil
+}
+
 // rowsToASCIITable converts rows into a formatted ASCII table.
 func rowsToASCIITable(rows []row, headers []string) string {
 	c := [][]string{}
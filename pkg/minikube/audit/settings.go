@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/docker/go-units"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+const (
+	// MaxSizeKey is the 'minikube config' property capping the size of the audit log before it is rotated
+	MaxSizeKey = "audit.max-size"
+	// MaxBackupsKey is the 'minikube config' property setting how many rotated audit logs are kept
+	MaxBackupsKey = "audit.max-backups"
+	// ForwardKey is the 'minikube config' property setting where audit events are forwarded to
+	ForwardKey = "audit.forward"
+
+	// defaultMaxSize is the size the audit log is rotated at unless audit.max-size is set
+	defaultMaxSize = 5 * units.MB
+	// defaultMaxBackups is how many rotated audit logs are kept unless audit.max-backups is set
+	defaultMaxBackups = 3
+)
+
+// settings are the audit log settings set with 'minikube config'
+type settings struct {
+	maxSize    int64
+	maxBackups int
+	forward    string
+}
+
+// loadSettings reads the audit log settings, falling back to the defaults for the ones not set or not valid
+func loadSettings() settings {
+	s := settings{maxSize: defaultMaxSize, maxBackups: defaultMaxBackups}
+	cc, err := config.ReadConfig(localpath.ConfigFile())
+	if err != nil {
+		klog.Warningf("unable to read config: %v", err)
+		return s
+	}
+	if v, ok := cc[MaxSizeKey].(string); ok && v != "" {
+		size, err := units.FromHumanSize(v)
+		if err != nil || size <= 0 {
+			klog.Warningf("invalid %s %q, using %s", MaxSizeKey, v, units.HumanSize(float64(s.maxSize)))
+		} else {
+			s.maxSize = size
+		}
+	}
+	// integers set with 'minikube config' are read back from JSON as float64
+	if v, ok := cc[MaxBackupsKey].(float64); ok && v >= 0 {
+		s.maxBackups = int(v)
+	}
+	if v, ok := cc[ForwardKey].(string); ok {
+		s.forward = v
+	}
+	return s
+}
+
+// ValidateForward returns an error if the target is not somewhere audit events can be forwarded to:
+// "syslog" for the local syslog, udp:// or tcp:// URLs for a remote syslog, http:// or https:// URLs for a webhook
+func ValidateForward(target string) error {
+	if target == "syslog" {
+		return nil
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid URL: %v", target, err)
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+		if u.Port() == "" {
+			return fmt.Errorf("%q has no port, the syslog port is usually 514", target)
+		}
+	case "http", "https":
+		if u.Host == "" {
+			return fmt.Errorf("%q has no host", target)
+		}
+	default:
+		return fmt.Errorf("%q is neither syslog, a udp:// or tcp:// syslog URL, nor a http:// or https:// webhook URL", target)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// +build !windows
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"log/syslog"
+	"net"
+)
+
+// toSyslog writes the event to the local syslog, or to the remote one at raddr when network is set.
+func toSyslog(network, raddr string, event []byte) error {
+	if network != "" {
+		// syslog.Dial has no timeout of its own, don't hang on a remote syslog which is down
+		c, err := net.DialTimeout(network, raddr, forwardTimeout)
+		if err != nil {
+			return fmt.Errorf("unable to connect to syslog %s://%s: %v", network, raddr, err)
+		}
+		c.Close()
+	}
+	w, err := syslog.Dial(network, raddr, syslog.LOG_NOTICE|syslog.LOG_USER, "minikube")
+	if err != nil {
+		return fmt.Errorf("unable to connect to syslog: %v", err)
+	}
+	defer w.Close()
+	if err := w.Notice(string(event)); err != nil {
+		return fmt.Errorf("unable to write audit event to syslog: %v", err)
+	}
+	return nil
+}
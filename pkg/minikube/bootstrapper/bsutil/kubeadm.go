@@ -113,7 +113,7 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		CertDir:           vmpath.GuestKubernetesCertsDir,
 		ServiceCIDR:       constants.DefaultServiceCIDR,
 		PodSubnet:         podCIDR,
-		AdvertiseAddress:  n.IP,
+		AdvertiseAddress:  config.NodeAddress(cc, n),
 		APIServerPort:     nodePort,
 		KubernetesVersion: k8s.KubernetesVersion,
 		EtcdDataDir:       EtcdDataDir(),
@@ -127,7 +127,7 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		FeatureArgs:         kubeadmFeatureArgs,
 		NoTaintMaster:       false, // That does not work with k8s 1.12+
 		DNSDomain:           k8s.DNSDomain,
-		NodeIP:              n.IP,
+		NodeIP:              config.NodeIPs(cc, n),
 		CgroupDriver:        cgroupDriver,
 		ClientCAFile:        path.Join(vmpath.GuestKubernetesCertsDir, "ca.crt"),
 		StaticPodPath:       vmpath.GuestManifestsDir,
@@ -186,6 +186,23 @@ func InvokeKubeadm(version string) string {
 	return fmt.Sprintf("sudo env PATH=%s:$PATH kubeadm", binRoot(version))
 }
 
+// RenewCertsCmd returns the command renewing all the certificates kubeadm manages.
+// The command graduated from alpha in v1.20 and does not exist before v1.15.
+func RenewCertsCmd(version string) (string, error) {
+	v, err := util.ParseKubernetesVersion(version)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing kubernetes version")
+	}
+	phase := "certs"
+	if v.LT(semver.MustParse("1.15.0")) {
+		return "", fmt.Errorf("kubeadm %s cannot renew certificates, v1.15.0 or later is required", version)
+	}
+	if v.LT(semver.MustParse("1.20.0")) {
+		phase = "alpha certs"
+	}
+	return fmt.Sprintf("%s %s renew all --config %s", InvokeKubeadm(version), phase, KubeadmYamlPath), nil
+}
+
 // EtcdDataDir is where etcd data is stored.
 func EtcdDataDir() string {
 	return path.Join(vmpath.GuestPersistentDir, "etcd")
@@ -306,3 +306,34 @@ func TestEtcdExtraArgs(t *testing.T) {
 		t.Errorf("machines mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestRenewCertsCmd(t *testing.T) {
+	tests := []struct {
+		version string
+		phase   string
+		wantErr bool
+	}{
+		{version: "v1.14.0", wantErr: true},
+		{version: "v1.15.0", phase: " alpha certs renew all"},
+		{version: "v1.19.8", phase: " alpha certs renew all"},
+		{version: "v1.20.0", phase: "kubeadm certs renew all"},
+		{version: "v1.22.0-alpha.2", phase: "kubeadm certs renew all"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			got, err := RenewCertsCmd(tc.version)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("RenewCertsCmd(%s) = %q, want error", tc.version, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenewCertsCmd(%s): %v", tc.version, err)
+			}
+			if !strings.Contains(got, tc.phase) || !strings.HasSuffix(got, "--config "+KubeadmYamlPath) {
+				t.Errorf("RenewCertsCmd(%s) = %q, want it to contain %q", tc.version, got, tc.phase)
+			}
+		})
+	}
+}
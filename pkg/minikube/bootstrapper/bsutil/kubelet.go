@@ -62,11 +62,14 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 
 		if k8s.NetworkPlugin == "kubenet" {
 			extraOpts["pod-cidr"] = cni.DefaultPodCIDR
+			if k8s.PodCIDR != "" {
+				extraOpts["pod-cidr"] = k8s.PodCIDR
+			}
 		}
 	}
 
 	if _, ok := extraOpts["node-ip"]; !ok {
-		extraOpts["node-ip"] = nc.IP
+		extraOpts["node-ip"] = config.NodeIPs(mc, nc)
 	}
 	if _, ok := extraOpts["hostname-override"]; !ok {
 		nodeName := KubeNodeName(mc, nc)
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bsutil will eventually be renamed to kubeadm package after getting rid of older one
+package bsutil
+
+import (
+	"bytes"
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// KubeVIPManifestPath is where the kube-vip static pod is written on HA control planes
+var KubeVIPManifestPath = path.Join(vmpath.GuestManifestsDir, "kube-vip.yaml")
+
+// GenerateKubeVIPManifest generates the kube-vip static pod advertising the HA virtual IP on iface
+func GenerateKubeVIPManifest(cc config.ClusterConfig, n config.Node, iface string) ([]byte, error) {
+	if cc.KubernetesConfig.APIServerHAVIP == "" {
+		return nil, errors.New("cluster has no HA virtual IP")
+	}
+
+	port := n.Port
+	if port <= 0 {
+		port = constants.APIServerPort
+	}
+
+	opts := struct {
+		Image     string
+		VIP       string
+		Port      int
+		Interface string
+	}{
+		Image:     images.KubeVIP(cc.KubernetesConfig.ImageRepository),
+		VIP:       cc.KubernetesConfig.APIServerHAVIP,
+		Port:      port,
+		Interface: iface,
+	}
+
+	b := bytes.Buffer{}
+	if err := ktmpl.KubeVIPTemplate.Execute(&b, opts); err != nil {
+		return nil, errors.Wrap(err, "kube-vip template")
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestGenerateKubeVIPManifest(t *testing.T) {
+	cc := config.ClusterConfig{
+		Name: "ha",
+		KubernetesConfig: config.KubernetesConfig{
+			APIServerHAVIP: "192.168.49.254",
+		},
+	}
+	n := config.Node{Name: "m02", IP: "192.168.49.3", ControlPlane: true}
+
+	got, err := GenerateKubeVIPManifest(cc, n, "eth0")
+	if err != nil {
+		t.Fatalf("GenerateKubeVIPManifest: %v", err)
+	}
+	for _, want := range []string{
+		"value: 192.168.49.254",
+		"value: \"8443\"",
+		"value: eth0",
+		"image: ghcr.io/kube-vip/kube-vip:",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("manifest is missing %q:\n%s", want, got)
+		}
+	}
+
+	cc.KubernetesConfig.APIServerHAVIP = ""
+	if _, err := GenerateKubeVIPManifest(cc, n, "eth0"); err == nil {
+		t.Errorf("expected an error for a cluster without a virtual IP")
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kverify verifies a running Kubernetes cluster is healthy
+package kverify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// diagnosticLogLines is how many lines of the logs of a pod diagnostics include
+const diagnosticLogLines = 20
+
+// componentPods are the label selectors of the kube-system pods which are relevant when waiting for a component
+// times out. An empty selector is every pod in kube-system.
+var componentPods = map[string][]string{
+	APIServerWaitKey:      {"component=kube-apiserver"},
+	SystemPodsWaitKey:     {""},
+	DefaultSAWaitKey:      {"component=kube-controller-manager"},
+	AppsRunningKey:        {""},
+	NodeReadyKey:          {"k8s-app=kube-proxy"},
+	StorageProvisionerKey: {storageProvisionerLabel},
+	ExtraKey:              CorePodsLabels,
+}
+
+// Diagnose returns why a component may have timed out: the conditions of the node, and the state and recent logs of
+// the relevant pods which are not Ready.
+func Diagnose(cs *kubernetes.Clientset, component string, nodeName string) []string {
+	var lines []string
+	if component == NodeReadyKey {
+		node, err := cs.CoreV1().Nodes().Get(context.Background(), nodeName, meta.GetOptions{})
+		if err != nil {
+			return append(lines, fmt.Sprintf("unable to get node %q: %v", nodeName, err))
+		}
+		for _, c := range node.Status.Conditions {
+			if c.Status != core.ConditionFalse || c.Type == core.NodeReady {
+				lines = append(lines, fmt.Sprintf("node %s: %s=%s %s", nodeName, c.Type, c.Status, c.Message))
+			}
+		}
+	}
+
+	for _, selector := range componentPods[component] {
+		pods, err := cs.CoreV1().Pods(meta.NamespaceSystem).List(context.Background(), meta.ListOptions{LabelSelector: selector})
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("unable to list pods %q: %v", selector, err))
+			continue
+		}
+		if len(pods.Items) == 0 && selector != "" {
+			lines = append(lines, fmt.Sprintf("no pod matches %q", selector))
+		}
+		for _, pod := range pods.Items {
+			if podReady(pod) {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("pod %s is %s: %s", pod.Name, pod.Status.Phase, podWaitReasons(pod)))
+			lines = append(lines, podLogs(cs, pod)...)
+		}
+	}
+	return lines
+}
+
+// podReady returns whether a pod has the Ready condition
+func podReady(pod core.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == core.PodReady {
+			return c.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podWaitReasons returns why the containers of a pod are not running, such as "CrashLoopBackOff"
+func podWaitReasons(pod core.Pod) string {
+	var reasons []string
+	for _, cs := range pod.Status.ContainerStatuses {
+		switch {
+		case cs.State.Waiting != nil:
+			reasons = append(reasons, fmt.Sprintf("%s %s", cs.Name, cs.State.Waiting.Reason))
+		case cs.State.Terminated != nil:
+			reasons = append(reasons, fmt.Sprintf("%s terminated (%s, exit code %d)", cs.Name, cs.State.Terminated.Reason, cs.State.Terminated.ExitCode))
+		case !cs.Ready:
+			reasons = append(reasons, fmt.Sprintf("%s not ready, %d restarts", cs.Name, cs.RestartCount))
+		}
+	}
+	if len(reasons) == 0 {
+		return "no container is running"
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// podLogs returns the last lines of the logs of the first container of a pod
+func podLogs(cs *kubernetes.Clientset, pod core.Pod) []string {
+	if len(pod.Spec.Containers) == 0 {
+		return nil
+	}
+	tail := int64(diagnosticLogLines)
+	raw, err := cs.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &core.PodLogOptions{Container: pod.Spec.Containers[0].Name, TailLines: &tail}).DoRaw(context.Background())
+	if err != nil {
+		klog.Infof("unable to get logs of %s: %v", pod.Name, err)
+		return nil
+	}
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(string(raw), "\n"), "\n") {
+		if l != "" {
+			lines = append(lines, fmt.Sprintf("  %s | %s", pod.Name, l))
+		}
+	}
+	return lines
+}
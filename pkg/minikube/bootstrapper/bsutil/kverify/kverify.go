@@ -18,7 +18,11 @@ limitations under the License.
 package kverify
 
 import (
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // minLogCheckTime how long to wait before spamming error logs to console
@@ -39,6 +43,8 @@ const (
 	KubeletKey = "kubelet"
 	// ExtraKey is the name used for extra waiting for pods in CorePodsLabels to be Ready
 	ExtraKey = "extra"
+	// StorageProvisionerKey is the name used in the flags for waiting for the storage provisioner to be Ready
+	StorageProvisionerKey = "storage_provisioner"
 )
 
 //  vars related to the --wait flag
@@ -46,13 +52,13 @@ var (
 	// DefaultComponents is map of the the default components to wait for
 	DefaultComponents = map[string]bool{APIServerWaitKey: true, SystemPodsWaitKey: true}
 	// NoWaitComponents is map of componets to wait for if specified 'none' or 'false'
-	NoComponents = map[string]bool{APIServerWaitKey: false, SystemPodsWaitKey: false, DefaultSAWaitKey: false, AppsRunningKey: false, NodeReadyKey: false, KubeletKey: false, ExtraKey: false}
+	NoComponents = map[string]bool{APIServerWaitKey: false, SystemPodsWaitKey: false, DefaultSAWaitKey: false, AppsRunningKey: false, NodeReadyKey: false, KubeletKey: false, StorageProvisionerKey: false, ExtraKey: false}
 	// AllComponents is map for waiting for all components.
-	AllComponents = map[string]bool{APIServerWaitKey: true, SystemPodsWaitKey: true, DefaultSAWaitKey: true, AppsRunningKey: true, NodeReadyKey: true, KubeletKey: true, ExtraKey: true}
+	AllComponents = map[string]bool{APIServerWaitKey: true, SystemPodsWaitKey: true, DefaultSAWaitKey: true, AppsRunningKey: true, NodeReadyKey: true, KubeletKey: true, StorageProvisionerKey: true, ExtraKey: true}
 	// DefaultWaitList is list of all default components to wait for. only names to be used for start flags.
 	DefaultWaitList = []string{APIServerWaitKey, SystemPodsWaitKey}
 	// AllComponentsList list of all valid components keys to wait for. only names to be used used for start flags.
-	AllComponentsList = []string{APIServerWaitKey, SystemPodsWaitKey, DefaultSAWaitKey, AppsRunningKey, NodeReadyKey, KubeletKey}
+	AllComponentsList = []string{APIServerWaitKey, SystemPodsWaitKey, DefaultSAWaitKey, AppsRunningKey, NodeReadyKey, KubeletKey, StorageProvisionerKey}
 	// AppsRunningList running list are valid k8s-app components to wait for them to be running
 	AppsRunningList = []string{
 		"kube-dns", // coredns
@@ -82,3 +88,36 @@ func ShouldWait(wcs map[string]bool) bool {
 	}
 	return false
 }
+
+// ParseWaitComponent parses a value of the --wait flag: a component, optionally followed by how long to wait
+// for it, such as "apiserver:90s". Dashes may be used instead of underscores, such as "node-ready".
+func ParseWaitComponent(value string) (string, time.Duration, error) {
+	name := value
+	var timeout time.Duration
+	if i := strings.Index(value, ":"); i >= 0 {
+		name = value[:i]
+		d, err := time.ParseDuration(value[i+1:])
+		if err != nil {
+			return "", 0, errors.Wrapf(err, "timeout of %q", name)
+		}
+		if d <= 0 {
+			return "", 0, fmt.Errorf("timeout of %q must be positive", name)
+		}
+		timeout = d
+	}
+	name = strings.ReplaceAll(name, "-", "_")
+	for _, c := range AllComponentsList {
+		if name == c {
+			return name, timeout, nil
+		}
+	}
+	return "", 0, fmt.Errorf("%q is not a valid component, valid components are %q", name, strings.Join(AllComponentsList, ","))
+}
+
+// Timeout returns how long to wait for a component: its own timeout if it has one, else the default
+func Timeout(timeouts map[string]time.Duration, component string, def time.Duration) time.Duration {
+	if t, ok := timeouts[component]; ok && t > 0 {
+		return t
+	}
+	return def
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kverify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWaitComponent(t *testing.T) {
+	tests := []struct {
+		value   string
+		name    string
+		timeout time.Duration
+		wantErr bool
+	}{
+		{value: "apiserver", name: APIServerWaitKey},
+		{value: "apiserver:90s", name: APIServerWaitKey, timeout: 90 * time.Second},
+		{value: "node-ready:2m", name: NodeReadyKey, timeout: 2 * time.Minute},
+		{value: "default-sa", name: DefaultSAWaitKey},
+		{value: "storage-provisioner:60s", name: StorageProvisionerKey, timeout: time.Minute},
+		{value: "etcd", wantErr: true},
+		{value: "apiserver:soon", wantErr: true},
+		{value: "apiserver:0s", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			name, timeout, err := ParseWaitComponent(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseWaitComponent(%q) error = %v, wantErr %v", tc.value, err, tc.wantErr)
+			}
+			if name != tc.name || timeout != tc.timeout {
+				t.Errorf("ParseWaitComponent(%q) = %q, %s, want %q, %s", tc.value, name, timeout, tc.name, tc.timeout)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	timeouts := map[string]time.Duration{APIServerWaitKey: 90 * time.Second}
+	if got := Timeout(timeouts, APIServerWaitKey, 6*time.Minute); got != 90*time.Second {
+		t.Errorf("Timeout(apiserver) = %s, want its own 1m30s", got)
+	}
+	if got := Timeout(timeouts, NodeReadyKey, 6*time.Minute); got != 6*time.Minute {
+		t.Errorf("Timeout(node_ready) = %s, want the default 6m0s", got)
+	}
+	if got := Timeout(nil, NodeReadyKey, 6*time.Minute); got != 6*time.Minute {
+		t.Errorf("Timeout with no timeouts = %s, want the default 6m0s", got)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kverify verifies a running Kubernetes cluster is healthy
+package kverify
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	kconst "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+)
+
+// storageProvisionerLabel selects the pod of the storage-provisioner addon
+const storageProvisionerLabel = "integration-test=storage-provisioner"
+
+// WaitForStorageProvisioner waits for the pod of the storage-provisioner addon to be deployed and Ready.
+func WaitForStorageProvisioner(cs *kubernetes.Clientset, timeout time.Duration) error {
+	klog.Infof("waiting up to %v for the storage provisioner to be %q ...", timeout, core.PodReady)
+	start := time.Now()
+	ready := func() (bool, error) {
+		pods, err := cs.CoreV1().Pods(meta.NamespaceSystem).List(context.Background(), meta.ListOptions{LabelSelector: storageProvisionerLabel})
+		if err != nil {
+			klog.Infof("temporary error listing storage provisioner pods: %v", err)
+			return false, nil
+		}
+		for _, pod := range pods.Items {
+			for _, c := range pod.Status.Conditions {
+				if c.Type == core.PodReady && c.Status == core.ConditionTrue {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+	if err := wait.PollImmediate(kconst.APICallRetryInterval, timeout, ready); err != nil {
+		return errors.Wrapf(err, "waited %s for the storage provisioner", time.Since(start))
+	}
+
+	klog.Infof("duration metric: took %s for the storage provisioner to be ready ...", time.Since(start))
+	return nil
+}
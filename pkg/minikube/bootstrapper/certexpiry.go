@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bufio"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// opensslTimeLayout is how openssl prints the validity dates of a certificate
+const opensslTimeLayout = "Jan _2 15:04:05 2006 MST"
+
+// CertExpiry describes when a certificate expires
+type CertExpiry struct {
+	// Name is the file name of the certificate, relative to its directory
+	Name string
+	// Location is "host" or the name of the node holding the certificate
+	Location string
+	NotAfter time.Time
+}
+
+// ExpiresWithin returns whether the certificate expires within d of now
+func (c CertExpiry) ExpiresWithin(now time.Time, d time.Duration) bool {
+	return c.NotAfter.Before(now.Add(d))
+}
+
+// HostCertExpiry returns the expiry of the shared CAs and of the certificates signed for a profile
+func HostCertExpiry(profile string) ([]CertExpiry, error) {
+	profilePath := localpath.Profile(profile)
+	files := []string{
+		localpath.CACert(),
+		filepath.Join(localpath.MiniPath(), "proxy-client-ca.crt"),
+		localpath.ClientCert(profile),
+		filepath.Join(profilePath, "apiserver.crt"),
+		filepath.Join(profilePath, "proxy-client.crt"),
+	}
+
+	certs := []CertExpiry{}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", f)
+		}
+		notAfter, err := pemNotAfter(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", f)
+		}
+		certs = append(certs, CertExpiry{Name: filepath.Base(f), Location: "host", NotAfter: notAfter})
+	}
+	return certs, nil
+}
+
+// GuestCertExpiry returns the expiry of the Kubernetes certificates in the guest, including the ones generated by kubeadm
+func GuestCertExpiry(cr command.Runner, nodeName string) ([]CertExpiry, error) {
+	dir := vmpath.GuestKubernetesCertsDir
+	script := fmt.Sprintf(`for f in $(sudo find %s -name '*.crt'); do echo "${f#%s/} $(sudo openssl x509 -noout -enddate -in $f)"; done`, dir, dir)
+	rr, err := cr.RunCmd(exec.Command("/bin/bash", "-c", script))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading certificates")
+	}
+	certs, err := parseGuestCertExpiry(rr.Stdout.String())
+	if err != nil {
+		return nil, err
+	}
+	for i := range certs {
+		certs[i].Location = nodeName
+	}
+	return certs, nil
+}
+
+// parseGuestCertExpiry parses lines of "name notAfter=date" into certificate expiries, sorted by name
+func parseGuestCertExpiry(output string) ([]CertExpiry, error) {
+	certs := []CertExpiry{}
+	s := bufio.NewScanner(strings.NewReader(output))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 || !strings.HasPrefix(fields[1], "notAfter=") {
+			return nil, fmt.Errorf("unexpected line %q", line)
+		}
+		notAfter, err := time.Parse(opensslTimeLayout, strings.TrimPrefix(fields[1], "notAfter="))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing expiry of %s", fields[0])
+		}
+		certs = append(certs, CertExpiry{Name: fields[0], NotAfter: notAfter})
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].Name < certs[j].Name })
+	return certs, nil
+}
+
+// pemNotAfter returns the expiry of the first certificate in PEM encoded data
+func pemNotAfter(data []byte) (time.Time, error) {
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			return time.Time{}, fmt.Errorf("no certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return cert.NotAfter, nil
+		}
+		data = rest
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestParseGuestCertExpiry(t *testing.T) {
+	output := `apiserver.crt notAfter=Oct 16 12:00:00 2027 GMT
+etcd/server.crt notAfter=Jan  2 15:04:05 2027 GMT
+
+ca.crt notAfter=Oct 14 12:00:00 2036 GMT
+`
+	certs, err := parseGuestCertExpiry(output)
+	if err != nil {
+		t.Fatalf("parseGuestCertExpiry: %v", err)
+	}
+	want := []CertExpiry{
+		{Name: "apiserver.crt", NotAfter: time.Date(2027, time.October, 16, 12, 0, 0, 0, time.UTC)},
+		{Name: "ca.crt", NotAfter: time.Date(2036, time.October, 14, 12, 0, 0, 0, time.UTC)},
+		{Name: "etcd/server.crt", NotAfter: time.Date(2027, time.January, 2, 15, 4, 5, 0, time.UTC)},
+	}
+	if len(certs) != len(want) {
+		t.Fatalf("got %d certs, want %d: %+v", len(certs), len(want), certs)
+	}
+	for i := range want {
+		if certs[i].Name != want[i].Name || !certs[i].NotAfter.Equal(want[i].NotAfter) {
+			t.Errorf("cert %d = %+v, want %+v", i, certs[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"apiserver.crt", "apiserver.crt unable to load certificate", "apiserver.crt notAfter=tomorrow"} {
+		if _, err := parseGuestCertExpiry(bad); err == nil {
+			t.Errorf("parseGuestCertExpiry(%q) returned no error", bad)
+		}
+	}
+}
+
+func TestHostCertExpiry(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	if err := util.GenerateCACert(localpath.CACert(), filepath.Join(tempDir, "ca.key"), "minikubeCA"); err != nil {
+		t.Fatalf("error generating certificate: %v", err)
+	}
+	certs, err := HostCertExpiry("minikube")
+	if err != nil {
+		t.Fatalf("HostCertExpiry: %v", err)
+	}
+	if len(certs) != 1 || certs[0].Name != "ca.crt" || certs[0].Location != "host" {
+		t.Fatalf("HostCertExpiry = %+v, want only the host ca.crt", certs)
+	}
+
+	now := time.Now()
+	if certs[0].ExpiresWithin(now, 30*24*time.Hour) {
+		t.Errorf("new CA expires within 30 days: %s", certs[0].NotAfter)
+	}
+	if !certs[0].ExpiresWithin(now, 11*365*24*time.Hour) {
+		t.Errorf("new CA does not expire within 11 years: %s", certs[0].NotAfter)
+	}
+}
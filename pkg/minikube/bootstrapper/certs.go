@@ -139,6 +139,90 @@ func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig, n config.Node)
 	return nil
 }
 
+// AddCACert validates the PEM certificate at src and copies it to ~/.minikube/certs, returning its new path.
+// SetupCerts installs the certificates found there on every node it sets up.
+func AddCACert(src string) (string, error) {
+	valid, err := isValidPEMCertificate(src)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading %s", src)
+	}
+	if !valid {
+		return "", fmt.Errorf("%s does not contain a PEM encoded certificate", src)
+	}
+
+	name := filepath.Base(src)
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext != ".crt" && ext != ".pem" {
+		name += ".crt"
+	}
+	if name == "ca.pem" || name == "cert.pem" {
+		return "", fmt.Errorf("%s is reserved for the docker machine certificates, please rename the file", name)
+	}
+
+	certsDir := filepath.Join(localpath.MiniPath(), "certs")
+	if err := os.MkdirAll(certsDir, 0755); err != nil {
+		return "", errors.Wrapf(err, "mkdir %s", certsDir)
+	}
+	dst := filepath.Join(certsDir, name)
+	if err := copy.Copy(src, dst); err != nil {
+		return "", errors.Wrapf(err, "copy %s", src)
+	}
+	return dst, nil
+}
+
+// InstallCACerts copies the CA certificates in ~/.minikube/certs to the guest and adds them to its trust store
+func InstallCACerts(cr command.Runner) error {
+	caCerts, err := collectCACerts()
+	if err != nil {
+		return err
+	}
+	for src, dst := range caCerts {
+		certFile, err := assets.NewFileAsset(src, path.Dir(dst), path.Base(dst), "0644")
+		if err != nil {
+			return errors.Wrapf(err, "ca asset %s", src)
+		}
+		err = cr.Copy(certFile)
+		if cerr := certFile.Close(); cerr != nil {
+			klog.Warningf("error closing the file %s: %v", src, cerr)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Copy %s", src)
+		}
+	}
+
+	if err := installCertSymlinks(cr, caCerts); err != nil {
+		return errors.Wrapf(err, "certificate symlinks")
+	}
+	return nil
+}
+
+// RemoveProfileCerts removes the certificates signed for a profile, so that SetupCerts signs new ones.
+// The shared CAs are kept, which keeps every other profile and kubeconfig valid.
+func RemoveProfileCerts(profile string) error {
+	profilePath := localpath.Profile(profile)
+	patterns := []string{
+		localpath.ClientCert(profile),
+		localpath.ClientKey(profile),
+		filepath.Join(profilePath, "apiserver.crt*"),
+		filepath.Join(profilePath, "apiserver.key*"),
+		filepath.Join(profilePath, "proxy-client.crt"),
+		filepath.Join(profilePath, "proxy-client.key"),
+	}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "glob %s", pattern)
+		}
+		for _, m := range matches {
+			klog.Infof("removing %s", m)
+			if err := os.Remove(m); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "remove %s", m)
+			}
+		}
+	}
+	return nil
+}
+
 // CACerts has cert and key for CA (and Proxy)
 type CACerts struct {
 	caCert    string
@@ -199,13 +283,22 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 
 	profilePath := localpath.Profile(k8s.ClusterName)
 
-	serviceIP, err := util.GetServiceClusterIP(k8s.ServiceCIDR)
-	if err != nil {
-		return nil, errors.Wrap(err, "getting service cluster ip")
-	}
-
 	apiServerIPs := append(k8s.APIServerIPs,
-		net.ParseIP(n.IP), serviceIP, net.ParseIP(oci.DefaultBindIPV4), net.ParseIP("10.0.0.1"))
+		net.ParseIP(n.IP), net.ParseIP(oci.DefaultBindIPV4), net.ParseIP("10.0.0.1"))
+	// dual-stack clusters have a kubernetes service IP in each family
+	for _, cidr := range util.SplitCIDRs(k8s.ServiceCIDR) {
+		serviceIP, err := util.GetServiceClusterIP(cidr)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting service cluster ip")
+		}
+		apiServerIPs = append(apiServerIPs, serviceIP)
+	}
+	if n.IPv6 != "" {
+		apiServerIPs = append(apiServerIPs, net.ParseIP(n.IPv6), net.IPv6loopback)
+	}
+	if k8s.APIServerHAVIP != "" {
+		apiServerIPs = append(apiServerIPs, net.ParseIP(k8s.APIServerHAVIP))
+	}
 
 	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName, constants.ControlPlaneAlias)
 	apiServerAlternateNames := append(
@@ -288,17 +381,20 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 
 		if canRead(cp) && canRead(kp) {
 			klog.Infof("skipping %s signed cert generation: %s", spec.subject, kp)
-			continue
-		}
-
-		klog.Infof("generating %s signed cert: %s", spec.subject, kp)
-		err := util.GenerateSignedCert(
-			cp, kp, spec.subject,
-			spec.ips, spec.alternateNames,
-			spec.caCertPath, spec.caKeyPath,
-		)
-		if err != nil {
-			return xfer, errors.Wrapf(err, "generate signed cert for %q", spec.subject)
+			if spec.hash == "" {
+				continue
+			}
+			// another control plane may have left its own cert in place, so restore ours
+		} else {
+			klog.Infof("generating %s signed cert: %s", spec.subject, kp)
+			err := util.GenerateSignedCert(
+				cp, kp, spec.subject,
+				spec.ips, spec.alternateNames,
+				spec.caCertPath, spec.caKeyPath,
+			)
+			if err != nil {
+				return xfer, errors.Wrapf(err, "generate signed cert for %q", spec.subject)
+			}
 		}
 
 		if spec.hash != "" {
@@ -178,3 +178,12 @@ func CalicoDeployment(repo string) string {
 	}
 	return path.Join(repo, "kube-controllers:v3.14.1")
 }
+
+// KubeVIP returns the image used for the virtual IP of HA control planes
+// ref: https://github.com/kube-vip/kube-vip/releases
+func KubeVIP(repo string) string {
+	if repo == "" {
+		repo = "ghcr.io/kube-vip"
+	}
+	return path.Join(repo, "kube-vip:v0.3.9")
+}
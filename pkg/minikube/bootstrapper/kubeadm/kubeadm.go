@@ -19,6 +19,7 @@ package kubeadm
 import (
 	"bufio"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net"
@@ -102,20 +103,22 @@ func (k *Bootstrapper) LogCommands(cfg config.ClusterConfig, o bootstrapper.LogO
 	var dmesg strings.Builder
 	dmesg.WriteString("sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg")
 	if o.Follow {
+		// tail would hold back the lines until dmesg ends
 		dmesg.WriteString(" --follow")
-	}
-	if o.Lines > 0 {
+	} else if o.Lines > 0 {
 		dmesg.WriteString(fmt.Sprintf(" | tail -n %d", o.Lines))
 	}
 
-	describeNodes := fmt.Sprintf("sudo %s describe nodes --kubeconfig=%s", kubectlPath(cfg),
-		path.Join(vmpath.GuestPersistentDir, "kubeconfig"))
-
-	return map[string]string{
-		"kubelet":        kubelet.String(),
-		"dmesg":          dmesg.String(),
-		"describe nodes": describeNodes,
+	cmds := map[string]string{
+		"kubelet": kubelet.String(),
+		"dmesg":   dmesg.String(),
 	}
+	// the nodes can only be described once
+	if !o.Follow {
+		cmds["describe nodes"] = fmt.Sprintf("sudo %s describe nodes --kubeconfig=%s", kubectlPath(cfg),
+			path.Join(vmpath.GuestPersistentDir, "kubeconfig"))
+	}
+	return cmds
 }
 
 // createCompatSymlinks creates compatibility symlinks to transition running services to new directory structures
@@ -492,13 +495,17 @@ func (k *Bootstrapper) WaitForNode(cfg config.ClusterConfig, n config.Node, time
 
 	if cfg.VerifyComponents[kverify.NodeReadyKey] {
 		name := bsutil.KubeNodeName(cfg, n)
-		if err := kverify.WaitNodeCondition(client, name, core.NodeReady, timeout); err != nil {
+		if err := k.waitComponent(cfg, n, client, kverify.NodeReadyKey, timeout, func(t time.Duration) error {
+			return kverify.WaitNodeCondition(client, name, core.NodeReady, t)
+		}); err != nil {
 			return errors.Wrap(err, "waiting for node to be ready")
 		}
 	}
 
 	if cfg.VerifyComponents[kverify.ExtraKey] {
-		if err := kverify.WaitExtra(client, kverify.CorePodsLabels, timeout); err != nil {
+		if err := k.waitComponent(cfg, n, client, kverify.ExtraKey, timeout, func(t time.Duration) error {
+			return kverify.WaitExtra(client, kverify.CorePodsLabels, t)
+		}); err != nil {
 			return errors.Wrap(err, "extra waiting")
 		}
 	}
@@ -510,36 +517,56 @@ func (k *Bootstrapper) WaitForNode(cfg config.ClusterConfig, n config.Node, time
 
 	if n.ControlPlane {
 		if cfg.VerifyComponents[kverify.APIServerWaitKey] {
-			if err := kverify.WaitForAPIServerProcess(cr, k, cfg, k.c, start, timeout); err != nil {
-				return errors.Wrap(err, "wait for apiserver proc")
-			}
-
-			if err := kverify.WaitForHealthyAPIServer(cr, k, cfg, k.c, client, start, hostname, port, timeout); err != nil {
+			if err := k.waitComponent(cfg, n, client, kverify.APIServerWaitKey, timeout, func(t time.Duration) error {
+				if err := kverify.WaitForAPIServerProcess(cr, k, cfg, k.c, start, t); err != nil {
+					return errors.Wrap(err, "wait for apiserver proc")
+				}
+				return kverify.WaitForHealthyAPIServer(cr, k, cfg, k.c, client, start, hostname, port, t)
+			}); err != nil {
 				return errors.Wrap(err, "wait for healthy API server")
 			}
 		}
 
 		if cfg.VerifyComponents[kverify.SystemPodsWaitKey] {
-			if err := kverify.WaitForSystemPods(cr, k, cfg, k.c, client, start, timeout); err != nil {
+			if err := k.waitComponent(cfg, n, client, kverify.SystemPodsWaitKey, timeout, func(t time.Duration) error {
+				return kverify.WaitForSystemPods(cr, k, cfg, k.c, client, start, t)
+			}); err != nil {
 				return errors.Wrap(err, "waiting for system pods")
 			}
 		}
 
 		if cfg.VerifyComponents[kverify.DefaultSAWaitKey] {
-			if err := kverify.WaitForDefaultSA(client, timeout); err != nil {
+			if err := k.waitComponent(cfg, n, client, kverify.DefaultSAWaitKey, timeout, func(t time.Duration) error {
+				return kverify.WaitForDefaultSA(client, t)
+			}); err != nil {
 				return errors.Wrap(err, "waiting for default service account")
 			}
 		}
 
 		if cfg.VerifyComponents[kverify.AppsRunningKey] {
-			if err := kverify.WaitForAppsRunning(client, kverify.AppsRunningList, timeout); err != nil {
+			if err := k.waitComponent(cfg, n, client, kverify.AppsRunningKey, timeout, func(t time.Duration) error {
+				return kverify.WaitForAppsRunning(client, kverify.AppsRunningList, t)
+			}); err != nil {
 				return errors.Wrap(err, "waiting for apps_running")
 			}
 		}
+
+		// the storage provisioner runs on the primary control plane, once the addon is enabled
+		if cfg.VerifyComponents[kverify.StorageProvisionerKey] && config.IsPrimaryControlPlane(cfg, n) {
+			if !assets.Addons["storage-provisioner"].IsEnabled(&cfg) {
+				out.WarningT("Not waiting for the storage provisioner, as the storage-provisioner addon is disabled")
+			} else if err := k.waitComponent(cfg, n, client, kverify.StorageProvisionerKey, timeout, func(t time.Duration) error {
+				return kverify.WaitForStorageProvisioner(client, t)
+			}); err != nil {
+				return errors.Wrap(err, "waiting for storage provisioner")
+			}
+		}
 	}
 
 	if cfg.VerifyComponents[kverify.KubeletKey] {
-		if err := kverify.WaitForService(k.c, "kubelet", timeout); err != nil {
+		if err := k.waitComponent(cfg, n, client, kverify.KubeletKey, timeout, func(t time.Duration) error {
+			return kverify.WaitForService(k.c, "kubelet", t)
+		}); err != nil {
 			return errors.Wrap(err, "waiting for kubelet")
 		}
 	}
@@ -553,6 +580,57 @@ func (k *Bootstrapper) WaitForNode(cfg config.ClusterConfig, n config.Node, time
 	return nil
 }
 
+// waitComponent waits for a component with its own timeout if it was given one, reporting its progress as wait
+// events. When the wait fails, it shows what the relevant pods, the node or kubelet were doing.
+func (k *Bootstrapper) waitComponent(cfg config.ClusterConfig, n config.Node, client *kubernetes.Clientset, component string, timeout time.Duration, fn func(time.Duration) error) error {
+	timeout = kverify.Timeout(cfg.WaitTimeouts, component, timeout)
+	start := time.Now()
+	if out.JSON {
+		register.PrintWait(component, "waiting", 0, timeout)
+	}
+	err := fn(timeout)
+	elapsed := time.Since(start)
+	if err == nil {
+		if out.JSON {
+			register.PrintWait(component, "done", elapsed, timeout)
+		}
+		klog.Infof("duration metric: took %s waiting for %s", elapsed, component)
+		return nil
+	}
+
+	status := "failed"
+	if elapsed >= timeout {
+		status = "timeout"
+	}
+	if out.JSON {
+		register.PrintWait(component, status, elapsed, timeout)
+	}
+	if status == "timeout" {
+		out.WarningT("Timed out after {{.elapsed}} waiting for {{.component}}, here is what may have caused it:", out.V{"component": component, "elapsed": elapsed.Round(time.Second)})
+	} else {
+		out.WarningT("Waiting for {{.component}} failed after {{.elapsed}}, here is what may have caused it:", out.V{"component": component, "elapsed": elapsed.Round(time.Second)})
+	}
+	lines := kverify.Diagnose(client, component, bsutil.KubeNodeName(cfg, n))
+	if component == kverify.KubeletKey || component == kverify.NodeReadyKey {
+		rr, jerr := k.c.RunCmd(exec.Command("sudo", "journalctl", "-u", "kubelet", "--no-pager", "-n", "20"))
+		if jerr == nil {
+			for _, l := range strings.Split(strings.TrimSpace(rr.Stdout.String()), "\n") {
+				lines = append(lines, "  kubelet | "+l)
+			}
+		}
+	}
+	for _, l := range lines {
+		out.Infof("{{.line}}", out.V{"line": l})
+	}
+	if len(lines) == 0 {
+		out.Infof("nothing relevant was found, see 'minikube logs' for more")
+	}
+	if _, ok := cfg.WaitTimeouts[component]; !ok {
+		out.Styled(style.Tip, "To wait longer for it, use: minikube start --wait={{.component}}:{{.longer}}", out.V{"component": component, "longer": (2 * timeout).String()})
+	}
+	return err
+}
+
 // ensureKubeletStarted will start a systemd or init.d service if it is not running.
 func (k *Bootstrapper) ensureServiceStarted(svc string) error {
 	if st := kverify.ServiceStatus(k.c, svc); st != state.Running {
@@ -762,6 +840,13 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinCmd string) error {
 	// Join the master by specifying its token
 	joinCmd = fmt.Sprintf("%s --node-name=%s", joinCmd, config.MachineName(cc, n))
+	if n.ControlPlane {
+		port := n.Port
+		if port <= 0 {
+			port = constants.APIServerPort
+		}
+		joinCmd = fmt.Sprintf("%s --control-plane --apiserver-advertise-address=%s --apiserver-bind-port=%d", joinCmd, n.IP, port)
+	}
 
 	if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", joinCmd)); err != nil {
 		return errors.Wrapf(err, "kubeadm join")
@@ -774,10 +859,20 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 	return nil
 }
 
-// GenerateToken creates a token and returns the appropriate kubeadm join command to run, or the already existing token
-func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
+// GenerateToken creates a token and returns the appropriate kubeadm join command for n to run, or the already existing token
+func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig, n config.Node) (string, error) {
+	tokenArgs := "--print-join-command --ttl=0"
+	if n.ControlPlane {
+		// control planes fetch the cluster CAs and keys from the kubeadm-certs secret
+		key, err := k.uploadCerts(cc)
+		if err != nil {
+			return "", errors.Wrap(err, "uploading certs")
+		}
+		tokenArgs = fmt.Sprintf("%s --certificate-key=%s", tokenArgs, key)
+	}
+
 	// Take that generated token and use it to get a kubeadm join command
-	tokenCmd := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s token create --print-join-command --ttl=0", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion)))
+	tokenCmd := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s token create %s", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion), tokenArgs))
 	r, err := k.c.RunCmd(tokenCmd)
 	if err != nil {
 		return "", errors.Wrap(err, "generating join command")
@@ -801,6 +896,35 @@ func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
 	return joinCmd, nil
 }
 
+// uploadCerts stores the control plane certificates in the cluster, returning the key they are encrypted with
+func (k *Bootstrapper) uploadCerts(cc config.ClusterConfig) (string, error) {
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s init phase upload-certs --upload-certs --config %s", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion), bsutil.KubeadmYamlPath))
+	r, err := k.c.RunCmd(c)
+	if err != nil {
+		return "", err
+	}
+
+	return certificateKey(r.Stdout.String())
+}
+
+// certificateKey returns the key printed by kubeadm init phase upload-certs, on the line after:
+// [upload-certs] Using certificate key:
+func certificateKey(output string) (string, error) {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if !strings.HasSuffix(strings.TrimSpace(line), "Using certificate key:") || i+1 == len(lines) {
+			continue
+		}
+		key := strings.TrimSpace(lines[i+1])
+		// the key is 32 random bytes, hex encoded
+		if _, err := hex.DecodeString(key); err != nil || len(key) != 64 {
+			return "", fmt.Errorf("invalid certificate key %q", key)
+		}
+		return key, nil
+	}
+	return "", fmt.Errorf("no certificate key in upload-certs output: %q", output)
+}
+
 // DeleteCluster removes the components that were started earlier
 func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
 	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c, Socket: k8s.CRISocket})
@@ -870,17 +994,17 @@ func (k *Bootstrapper) UpdateCluster(cfg config.ClusterConfig) error {
 		klog.Infof("preload failed, will try to load cached images: %v", err)
 	}
 
-	if cfg.KubernetesConfig.ShouldLoadCachedImages {
-		if err := machine.LoadCachedImages(&cfg, k.c, images, constants.ImageCacheDir, false); err != nil {
-			out.FailureT("Unable to load cached images: {{.error}}", out.V{"error": err})
-		}
-	}
-
 	cp, err := config.PrimaryControlPlane(&cfg)
 	if err != nil {
 		return errors.Wrap(err, "getting control plane")
 	}
 
+	if cfg.KubernetesConfig.ShouldLoadCachedImages {
+		if err := machine.LoadCachedImages(&cfg, config.MachineName(cfg, cp), k.c, images, constants.ImageCacheDir, false); err != nil {
+			out.FailureT("Unable to load cached images: {{.error}}", out.V{"error": err})
+		}
+	}
+
 	err = k.UpdateNode(cfg, cp, r)
 	if err != nil {
 		return errors.Wrap(err, "updating control plane")
@@ -923,6 +1047,18 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		files = append(files, assets.NewMemoryAssetTarget(kubeadmCfg, bsutil.KubeadmYamlPath+".new", "0640"))
 	}
 
+	if n.ControlPlane && cfg.HA {
+		iface, err := k.nodeInterface(n.IP)
+		if err != nil {
+			return errors.Wrap(err, "finding node interface")
+		}
+		kubeVIP, err := bsutil.GenerateKubeVIPManifest(cfg, n, iface)
+		if err != nil {
+			return errors.Wrap(err, "generating kube-vip manifest")
+		}
+		files = append(files, assets.NewMemoryAssetTarget(kubeVIP, bsutil.KubeVIPManifestPath, "0600"))
+	}
+
 	// Installs compatibility shims for non-systemd environments
 	kubeletPath := path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubelet")
 	shims, err := sm.GenerateInitShim("kubelet", kubeletPath, bsutil.KubeletSystemdConfFile)
@@ -940,13 +1076,34 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		return errors.Wrap(err, "control plane")
 	}
 
-	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cp.IP)); err != nil {
+	// HA clusters reach the apiservers through the virtual IP, so any control plane can go away
+	endpoint := config.NodeAddress(cfg, cp)
+	if cfg.HA {
+		endpoint = cfg.KubernetesConfig.APIServerHAVIP
+	}
+	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(endpoint)); err != nil {
 		return errors.Wrap(err, "host alias")
 	}
 
 	return nil
 }
 
+// nodeInterface returns the name of the network interface holding ip
+func (k *Bootstrapper) nodeInterface(ip string) (string, error) {
+	rr, err := k.c.RunCmd(exec.Command("ip", "-o", "-4", "addr", "show"))
+	if err != nil {
+		return "", err
+	}
+	// 2: eth0    inet 192.168.49.2/24 brd 192.168.49.255 scope global eth0 ...
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 4 && strings.HasPrefix(fields[3], ip+"/") {
+			return fields[1], nil
+		}
+	}
+	return "", fmt.Errorf("no interface found for %s", ip)
+}
+
 // kubectlPath returns the path to the kubelet
 func kubectlPath(cfg config.ClusterConfig) string {
 	return path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubectl")
@@ -0,0 +1,425 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle packs everything 'minikube start' downloads into a single file, for hosts without network access
+package bundle
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+const manifestFile = "bundle.json"
+
+// Manifest describes the content of a bundle
+type Manifest struct {
+	MinikubeVersion   string
+	KubernetesVersion string
+	Driver            string
+	ContainerRuntime  string
+	// OS and Arch are the platform of the host the bundle was created for
+	OS      string
+	Arch    string
+	Created time.Time
+	// Preload is whether the bundle contains the preloaded images tarball
+	Preload bool
+	// Images are the cached images included in the bundle
+	Images []string
+	// Files are the other files of the bundle, relative to the minikube home
+	Files []string
+}
+
+// Options controls what is included in a bundle
+type Options struct {
+	KubernetesVersion string
+	Driver            string
+	ContainerRuntime  string
+	// Addons are added to the addons enabled by default
+	Addons []string
+}
+
+// Supported returns whether bundles can provide everything the driver needs to start a cluster
+func Supported(name string) bool {
+	return name == driver.Docker || (driver.IsVM(name) && !driver.IsSSH(name))
+}
+
+// Create caches everything needed to start a cluster with the given options, and writes it to dst
+func Create(o Options, dst string) (*Manifest, error) {
+	if !Supported(o.Driver) {
+		return nil, fmt.Errorf("the %s driver is not supported by offline bundles", o.Driver)
+	}
+	m := &Manifest{
+		MinikubeVersion:   version.GetVersion(),
+		KubernetesVersion: o.KubernetesVersion,
+		Driver:            o.Driver,
+		ContainerRuntime:  o.ContainerRuntime,
+		OS:                runtime.GOOS,
+		Arch:              runtime.GOARCH,
+		Created:           time.Now(),
+	}
+
+	var files []string
+	if driver.IsKIC(o.Driver) {
+		out.Step(style.Pulling, "Caching base image ...")
+		if err := download.ImageToCache(kic.BaseImage); err != nil {
+			return nil, errors.Wrap(err, "caching base image")
+		}
+		files = append(files, download.ImagePathInCache(kic.BaseImage))
+	} else {
+		iso, err := download.ISO(download.DefaultISOURLs(), false)
+		if err != nil {
+			return nil, errors.Wrap(err, "caching ISO")
+		}
+		files = append(files, filepath.FromSlash(strings.TrimPrefix(download.LocalISOResource(iso), "file://")))
+
+		if o.Driver == driver.KVM2 || o.Driver == driver.HyperKit {
+			p, err := cacheDriver(o.Driver)
+			if err != nil {
+				return nil, errors.Wrapf(err, "caching %s driver", o.Driver)
+			}
+			files = append(files, p)
+		}
+	}
+
+	var images []string
+	if download.PreloadExists(o.KubernetesVersion, o.ContainerRuntime, o.Driver, true) {
+		if err := download.Preload(o.KubernetesVersion, o.ContainerRuntime, o.Driver); err != nil {
+			return nil, errors.Wrap(err, "caching preload")
+		}
+		m.Preload = true
+		files = append(files, download.TarballPath(o.KubernetesVersion, o.ContainerRuntime))
+	} else {
+		// without a preload, the node gets the Kubernetes binaries and images from the cache
+		out.Step(style.FileDownload, "Caching Kubernetes {{.version}} binaries ...", out.V{"version": o.KubernetesVersion})
+		for _, bin := range bootstrapper.GetCachedBinaryList(bootstrapper.Kubeadm) {
+			p, err := download.Binary(bin, o.KubernetesVersion, "linux", runtime.GOARCH)
+			if err != nil {
+				return nil, errors.Wrapf(err, "caching %s", bin)
+			}
+			files = append(files, p)
+		}
+		k8sImages, err := bootstrapper.GetCachedImageList("", o.KubernetesVersion, bootstrapper.Kubeadm)
+		if err != nil {
+			return nil, errors.Wrap(err, "Kubernetes images")
+		}
+		images = append(images, k8sImages...)
+	}
+
+	kubectl := "kubectl"
+	if runtime.GOOS == "windows" {
+		kubectl = "kubectl.exe"
+	}
+	p, err := download.Binary(kubectl, o.KubernetesVersion, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return nil, errors.Wrap(err, "caching kubectl")
+	}
+	files = append(files, p)
+
+	addonImages, err := AddonImages(o.Addons)
+	if err != nil {
+		return nil, err
+	}
+	images = append(images, addonImages...)
+	out.Step(style.Pulling, "Caching {{.count}} images ...", out.V{"count": len(images)})
+	if err := image.SaveToDir(images, constants.ImageCacheDir, false); err != nil {
+		return nil, errors.Wrap(err, "caching images")
+	}
+	m.Images = images
+
+	for _, f := range files {
+		rel, err := filepath.Rel(localpath.MiniPath(), f)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("%s is not in the minikube home %s", f, localpath.MiniPath())
+		}
+		m.Files = append(m.Files, filepath.ToSlash(rel))
+	}
+
+	out.Step(style.Copying, "Writing bundle to {{.path}} ...", out.V{"path": dst})
+	return m, write(m, dst)
+}
+
+// AddonImages returns the default images of the addons enabled by default and of the given addons
+func AddonImages(names []string) ([]string, error) {
+	wanted := map[string]bool{}
+	for _, a := range assets.Addons {
+		if a.IsEnabled(&config.ClusterConfig{}) {
+			wanted[a.Name()] = true
+		}
+	}
+	for _, n := range names {
+		if _, ok := assets.Addons[n]; !ok {
+			return nil, fmt.Errorf("unknown addon %q", n)
+		}
+		wanted[n] = true
+	}
+
+	var images []string
+	for n := range wanted {
+		a := assets.Addons[n]
+		for name, img := range a.Images {
+			if reg := a.Registries[name]; reg != "" {
+				img = reg + "/" + img
+			}
+			images = append(images, img)
+		}
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// cacheDriver downloads the driver binary of this minikube version, unless it is cached already
+func cacheDriver(name string) (string, error) {
+	executable := fmt.Sprintf("docker-machine-driver-%s", name)
+	p := localpath.MakeMiniPath("bin", executable)
+	if _, err := os.Stat(p); err == nil {
+		return p, nil
+	}
+	v, err := version.GetSemverVersion()
+	if err != nil {
+		return "", err
+	}
+	return p, download.Driver(executable, p, v)
+}
+
+// imageFile returns the path of the tarball of a cached image in the bundle
+func imageFile(img string) string {
+	return path.Join("cache", "images", filepath.ToSlash(localpath.SanitizeCacheDir(img)))
+}
+
+// write writes the manifest and the files it lists to a zstd compressed tarball at dst
+func write(m *Manifest, dst string) (err error) {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+	zw, err := zstd.NewWriter(f)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(zw)
+
+	b, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestFile, Mode: 0644, Size: int64(len(b)), ModTime: m.Created}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(b); err != nil {
+		return err
+	}
+
+	for _, img := range m.Images {
+		if err := addCachedImage(tw, img); err != nil {
+			return errors.Wrapf(err, "adding image %s", img)
+		}
+	}
+	for _, rel := range m.Files {
+		if err := addFile(tw, localpath.MakeMiniPath(filepath.FromSlash(rel)), rel); err != nil {
+			return errors.Wrapf(err, "adding %s", rel)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// addCachedImage adds an image of the image cache, as a tarball
+func addCachedImage(tw *tar.Writer, img string) error {
+	tmp, err := ioutil.TempDir("", "minikube-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	src := filepath.Join(tmp, "image.tar")
+	if err := image.ExportCachedImage(constants.ImageCacheDir, img, src); err != nil {
+		return err
+	}
+	return addFile(tw, src, imageFile(img))
+}
+
+func addFile(tw *tar.Writer, src string, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Extract unpacks a bundle written by Create into the local minikube home.
+// Files which already exist locally are kept.
+func Extract(src string) (*Manifest, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+
+	var m *Manifest
+	// expected maps the files listed in the manifest to the image they hold, if any
+	expected := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading bundle")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+
+		if name == manifestFile {
+			// the manifest is written first, so that the bundle can be validated before anything is written
+			m = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(m); err != nil {
+				return nil, errors.Wrap(err, "parsing manifest")
+			}
+			if err := m.validate(); err != nil {
+				return nil, err
+			}
+			for _, rel := range m.Files {
+				expected[path.Clean(rel)] = ""
+			}
+			for _, img := range m.Images {
+				expected[imageFile(img)] = img
+			}
+			for name := range expected {
+				if name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+					return nil, fmt.Errorf("invalid path in bundle: %s", name)
+				}
+			}
+			continue
+		}
+		if m == nil {
+			return nil, fmt.Errorf("%s is not a bundle: %s must come before %s", src, manifestFile, hdr.Name)
+		}
+		img, ok := expected[name]
+		if !ok {
+			return nil, fmt.Errorf("unexpected file in bundle: %s", hdr.Name)
+		}
+
+		dst := localpath.MakeMiniPath(filepath.FromSlash(name))
+		if img != "" && image.IsCached(constants.ImageCacheDir, img) {
+			klog.Infof("image %s is already cached", img)
+			continue
+		}
+		if _, err := os.Stat(dst); err == nil {
+			klog.Infof("%s already exists", dst)
+			continue
+		}
+		if err := writeFile(tr, dst, hdr.FileInfo().Mode()); err != nil {
+			return nil, err
+		}
+	}
+	if m == nil {
+		return nil, fmt.Errorf("%s is not a bundle: no %s", src, manifestFile)
+	}
+	return m, nil
+}
+
+// validate checks that the bundle was created for this host and minikube version
+func (m *Manifest) validate() error {
+	if m.MinikubeVersion != version.GetVersion() {
+		return fmt.Errorf("the bundle was created by minikube %s, but this is minikube %s", m.MinikubeVersion, version.GetVersion())
+	}
+	if m.OS != runtime.GOOS || m.Arch != runtime.GOARCH {
+		return fmt.Errorf("the bundle was created for %s/%s hosts, but this host is %s/%s", m.OS, m.Arch, runtime.GOOS, runtime.GOARCH)
+	}
+	return nil
+}
+
+// writeFile atomically writes the content of r to dst, so that interrupted extractions leave no partial files behind
+func writeFile(r io.Reader, dst string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrapf(err, "mkdir %s", filepath.Dir(dst))
+	}
+	f, err := ioutil.TempFile(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "writing %s", dst)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), dst)
+}
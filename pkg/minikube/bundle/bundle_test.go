@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/version"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func testManifest(files ...string) *Manifest {
+	return &Manifest{
+		MinikubeVersion:   version.GetVersion(),
+		KubernetesVersion: "v1.20.7",
+		Driver:            "docker",
+		ContainerRuntime:  "docker",
+		OS:                runtime.GOOS,
+		Arch:              runtime.GOARCH,
+		Files:             files,
+	}
+}
+
+func TestWriteExtract(t *testing.T) {
+	tmp := t.TempDir()
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+
+	os.Setenv(localpath.MinikubeHome, filepath.Join(tmp, "src"))
+	writeTestFile(t, localpath.MakeMiniPath("cache", "kic", "kicbase.tar"), "source kicbase")
+	writeTestFile(t, localpath.MakeMiniPath("cache", "linux", "v1.20.7", "kubectl"), "source kubectl")
+	writeTestFile(t, localpath.MakeMiniPath("cache", "other"), "not bundled")
+
+	dst := filepath.Join(tmp, "bundle.tar.zst")
+	if err := write(testManifest("cache/kic/kicbase.tar", "cache/linux/v1.20.7/kubectl"), dst); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// extract into a home which already has one of the files
+	os.Setenv(localpath.MinikubeHome, filepath.Join(tmp, "dst"))
+	writeTestFile(t, localpath.MakeMiniPath("cache", "linux", "v1.20.7", "kubectl"), "local kubectl")
+
+	m, err := Extract(dst)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if m.KubernetesVersion != "v1.20.7" {
+		t.Errorf("extracted Kubernetes version = %q, want v1.20.7", m.KubernetesVersion)
+	}
+	for path, want := range map[string]string{
+		localpath.MakeMiniPath("cache", "kic", "kicbase.tar"):          "source kicbase",
+		localpath.MakeMiniPath("cache", "linux", "v1.20.7", "kubectl"): "local kubectl",
+	} {
+		got, err := ioutil.ReadFile(path)
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v, want %q", path, got, err, want)
+		}
+	}
+	if _, err := os.Stat(localpath.MakeMiniPath("cache", "other")); !os.IsNotExist(err) {
+		t.Errorf("files not listed in the manifest should not be bundled")
+	}
+}
+
+func TestExtractInvalid(t *testing.T) {
+	tmp := t.TempDir()
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, filepath.Join(tmp, "home"))
+	writeTestFile(t, localpath.MakeMiniPath("cache", "iso", "minikube.iso"), "iso")
+	writeTestFile(t, filepath.Join(localpath.MiniPath(), "..", "escaped"), "escaped")
+
+	otherVersion := testManifest("cache/iso/minikube.iso")
+	otherVersion.MinikubeVersion = "v0.0.1"
+	otherArch := testManifest("cache/iso/minikube.iso")
+	otherArch.Arch = "s390x"
+
+	tests := []struct {
+		name string
+		m    *Manifest
+	}{
+		{"path outside of the minikube home", testManifest("cache/iso/minikube.iso", "../escaped")},
+		{"other minikube version", otherVersion},
+		{"other architecture", otherArch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := filepath.Join(tmp, "bundle.tar.zst")
+			if err := write(tc.m, dst); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if _, err := Extract(dst); err == nil {
+				t.Errorf("Extract() should fail")
+			}
+		})
+	}
+}
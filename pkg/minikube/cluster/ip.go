@@ -22,6 +22,7 @@ import (
 	"os/exec"
 	"reflect"
 	"regexp"
+	"runtime"
 	"strings"
 
 	"github.com/docker/machine/libmachine"
@@ -33,6 +34,25 @@ import (
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
+// MountIPs returns the IP the guest reaches the file servers of mounts at, and the IP they listen on on this host.
+// The containers of a daemon reached over ssh can not reach this host: the file servers then listen on its
+// loopback, and ForwardToGuest must forward their ports to the loopback of the guest.
+func MountIPs(host *host.Host, clusterName string) (ip net.IP, bindIP string, forward bool, err error) {
+	name := host.Driver.DriverName()
+	if driver.IsKIC(name) && oci.IsSSHDaemonHost(name) {
+		return net.ParseIP(oci.DefaultBindIPV4), oci.DefaultBindIPV4, true, nil
+	}
+	ip, err = HostIP(host, clusterName)
+	if err != nil {
+		return nil, "", false, err
+	}
+	bindIP = ip.String()
+	if driver.IsKIC(name) && runtime.GOOS != "linux" {
+		bindIP = oci.DefaultBindIPV4
+	}
+	return ip, bindIP, false, nil
+}
+
 // HostIP gets the ip address to be used for mapping host -> VM and VM -> host
 func HostIP(host *host.Host, clusterName string) (net.IP, error) {
 	switch host.DriverName {
@@ -46,7 +66,7 @@ func HostIP(host *host.Host, clusterName string) (net.IP, error) {
 			return []byte{}, errors.Wrap(err, "Error getting VM/Host IP address")
 		}
 		return net.ParseIP(ip), nil
-	case driver.KVM2:
+	case driver.KVM2, driver.MicroVM:
 		// `host.Driver.GetIP` returns dhcp lease info for a given network(=`virsh net-dhcp-leases minikube-net`)
 		vmIPString, err := host.Driver.GetIP()
 		if err != nil {
@@ -18,15 +18,20 @@ package cluster
 
 import (
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/docker/machine/libmachine/drivers"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/sshutil"
 )
 
 // MountConfig defines the options available to the Mount command
@@ -137,6 +142,15 @@ func mntCmd(source string, target string, c *MountConfig) string {
 	return fmt.Sprintf("sudo mount -t %s -o %s %s %s", c.Type, strings.Join(opts, ","), source, target)
 }
 
+// IsMounted returns whether a filesystem is mounted on a path
+func IsMounted(r mountRunner, target string) (bool, error) {
+	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("findmnt -n -o TARGET -M %s || true", target)))
+	if err != nil {
+		return false, errors.Wrap(err, "findmnt")
+	}
+	return strings.TrimSpace(rr.Stdout.String()) != "", nil
+}
+
 // Unmount unmounts a path
 func Unmount(r mountRunner, target string) error {
 	// grep because findmnt will also display the parent!
@@ -147,3 +161,56 @@ func Unmount(r mountRunner, target string) error {
 	klog.Infof("unmount for %s ran successfully", target)
 	return nil
 }
+
+// guestForward is a port of the loopback of a guest forwarded to this host
+type guestForward struct {
+	listener net.Listener
+	client   *ssh.Client
+}
+
+// Close stops forwarding
+func (f *guestForward) Close() error {
+	err := f.listener.Close()
+	if cerr := f.client.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// ForwardToGuest forwards a port of the loopback of a guest to the same port of the loopback of this host,
+// over ssh, for the guests which can not reach this host, see MountIPs. It forwards until closed.
+func ForwardToGuest(d drivers.Driver, port int) (io.Closer, error) {
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	client, err := sshutil.NewSSHClient(d)
+	if err != nil {
+		return nil, errors.Wrap(err, "ssh client")
+	}
+	l, err := client.Listen("tcp", addr)
+	if err != nil {
+		client.Close()
+		return nil, errors.Wrapf(err, "listening on %s in the guest", addr)
+	}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				klog.Infof("stopped forwarding %s: %v", addr, err)
+				return
+			}
+			go func() {
+				defer c.Close()
+				h, err := net.Dial("tcp", addr)
+				if err != nil {
+					klog.Warningf("unable to forward to %s: %v", addr, err)
+					return
+				}
+				defer h.Close()
+				go func() {
+					_, _ = io.Copy(h, c)
+				}()
+				_, _ = io.Copy(c, h)
+			}()
+		}
+	}()
+	return &guestForward{listener: l, client: client}, nil
+}
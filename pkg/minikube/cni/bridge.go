@@ -69,7 +69,7 @@ func (c Bridge) String() string {
 }
 
 func (c Bridge) netconf() (assets.CopyableFile, error) {
-	input := &tmplInput{PodCIDR: DefaultPodCIDR}
+	input := &tmplInput{PodCIDR: podCIDROrDefault(c.cc)}
 
 	b := bytes.Buffer{}
 	if err := bridgeConf.Execute(&b, input); err != nil {
@@ -103,5 +103,5 @@ func (c Bridge) Apply(r Runner) error {
 
 // CIDR returns the default CIDR used by this CNI
 func (c Bridge) CIDR() string {
-	return DefaultPodCIDR
+	return podCIDROrDefault(c.cc)
 }
@@ -586,7 +586,7 @@ spec:
         # It can be deleted if this is a fresh installation, or if you have already
         # upgraded to use calico-ipam.
         - name: upgrade-ipam
-          image: calico/cni:v3.14.1
+          image: calico/cni:{{ .Version }}
           command: ["/opt/cni/bin/calico-ipam", "-upgrade"]
           env:
             - name: KUBERNETES_NODE_NAME
@@ -608,7 +608,7 @@ spec:
         # This container installs the CNI binaries
         # and CNI network config file on each node.
         - name: install-cni
-          image: calico/cni:v3.14.1
+          image: calico/cni:{{ .Version }}
           command: ["/install-cni.sh"]
           env:
             # Name of the CNI config file to create.
@@ -644,7 +644,7 @@ spec:
         # Adds a Flex Volume Driver that creates a per-pod Unix Domain Socket to allow Dikastes
         # to communicate with Felix over the Policy Sync API.
         - name: flexvol-driver
-          image: calico/pod2daemon-flexvol:v3.14.1
+          image: calico/pod2daemon-flexvol:{{ .Version }}
           volumeMounts:
           - name: flexvol-driver-host
             mountPath: /host/driver
@@ -880,6 +880,7 @@ type Calico struct {
 type calicoTmplStruct struct {
 	DeploymentImageName string
 	DaemonSetImageName  string
+	Version             string
 }
 
 // String returns a string representation of this CNI
@@ -889,16 +890,18 @@ func (c Calico) String() string {
 
 // manifest returns a Kubernetes manifest for a CNI
 func (c Calico) manifest() (assets.CopyableFile, error) {
+	v := version(c.cc, "calico")
 	input := &calicoTmplStruct{
-		DeploymentImageName: images.CalicoDeployment(c.cc.KubernetesConfig.ImageRepository),
-		DaemonSetImageName:  images.CalicoDaemonSet(c.cc.KubernetesConfig.ImageRepository),
+		DeploymentImageName: withTag(images.CalicoDeployment(c.cc.KubernetesConfig.ImageRepository), v),
+		DaemonSetImageName:  withTag(images.CalicoDaemonSet(c.cc.KubernetesConfig.ImageRepository), v),
+		Version:             v,
 	}
 
 	b := bytes.Buffer{}
 	if err := calicoTmpl.Execute(&b, input); err != nil {
 		return nil, err
 	}
-	return manifestAsset(b.Bytes()), nil
+	return customizedManifest(c.cc, b.Bytes())
 }
 
 // Apply enables the CNI
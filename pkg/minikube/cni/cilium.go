@@ -17,14 +17,17 @@ limitations under the License.
 package cni
 
 import (
+	"bytes"
 	"os/exec"
+	"text/template"
 
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/config"
 )
 
 // From https://raw.githubusercontent.com/cilium/cilium/v1.8/install/kubernetes/quick-install.yaml
-var ciliumTmpl = `---
+var ciliumTmpl = template.Must(template.New("cilium").Parse(`---
 # Source: cilium/charts/agent/templates/serviceaccount.yaml
 apiVersion: v1
 kind: ServiceAccount
@@ -440,7 +443,7 @@ spec:
               key: custom-cni-conf
               name: cilium-config
               optional: true
-        image: "docker.io/cilium/cilium:v1.8.0"
+        image: "docker.io/cilium/cilium:{{ .Version }}"
         imagePullPolicy: IfNotPresent
         lifecycle:
           postStart:
@@ -503,7 +506,7 @@ spec:
               key: wait-bpf-mount
               name: cilium-config
               optional: true
-        image: "docker.io/cilium/cilium:v1.8.0"
+        image: "docker.io/cilium/cilium:{{ .Version }}"
         imagePullPolicy: IfNotPresent
         name: clean-cilium-state
         securityContext:
@@ -641,7 +644,7 @@ spec:
               key: AWS_DEFAULT_REGION
               name: cilium-aws
               optional: true
-        image: "docker.io/cilium/operator-generic:v1.8.0"
+        image: "docker.io/cilium/operator-generic:{{ .Version }}"
         imagePullPolicy: IfNotPresent
         name: cilium-operator
         livenessProbe:
@@ -667,7 +670,7 @@ spec:
       - configMap:
           name: cilium-config
         name: cilium-config-path
-`
+`))
 
 // Cilium is the Cilium CNI manager
 type Cilium struct {
@@ -686,7 +689,20 @@ func (c Cilium) Apply(r Runner) error {
 		return errors.Wrap(err, "bpf mount")
 	}
 
-	return applyManifest(c.cc, r, manifestAsset([]byte(ciliumTmpl)))
+	m, err := c.manifest()
+	if err != nil {
+		return errors.Wrap(err, "manifest")
+	}
+	return applyManifest(c.cc, r, m)
+}
+
+// manifest returns a Kubernetes manifest for a CNI
+func (c Cilium) manifest() (assets.CopyableFile, error) {
+	b := bytes.Buffer{}
+	if err := ciliumTmpl.Execute(&b, &tmplInput{Version: version(c.cc, "cilium")}); err != nil {
+		return nil, err
+	}
+	return customizedManifest(c.cc, b.Bytes())
 }
 
 // CIDR returns the default CIDR used by this CNI
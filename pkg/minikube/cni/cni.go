@@ -38,6 +38,8 @@ import (
 const (
 	// DefaultPodCIDR is the default CIDR to use in minikube CNI's.
 	DefaultPodCIDR = "10.244.0.0/16"
+	// DefaultPodCIDRv6 is the default CIDR to use for pod IPv6 addresses
+	DefaultPodCIDRv6 = "fd00:10:244::/56"
 
 	// DefaultConfDir is the default CNI Config Directory path
 	DefaultConfDir = "/etc/cni/net.d"
@@ -81,6 +83,7 @@ type tmplInput struct {
 	PodCIDR      string
 	DefaultRoute string
 	CNIConfDir   string
+	Version      string
 }
 
 // New returns a new CNI manager
@@ -92,8 +95,15 @@ func New(cc *config.ClusterConfig) (Manager, error) {
 
 	klog.Infof("Creating CNI manager for %q", cc.KubernetesConfig.CNI)
 
+	_, err := ResolveVersion(cc.KubernetesConfig.CNI, cc.KubernetesConfig.CNIVersion, cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	if _, bundled := Versions[bundledName(cc.KubernetesConfig.CNI)]; cc.KubernetesConfig.CNIValues != "" && !bundled {
+		return nil, fmt.Errorf("only the calico, cilium, flannel and kindnet CNIs support values, got %q", cc.KubernetesConfig.CNI)
+	}
+
 	var cnm Manager
-	var err error
 	switch cc.KubernetesConfig.CNI {
 	case "", "auto":
 		cnm = chooseDefault(*cc)
@@ -148,6 +158,11 @@ func chooseDefault(cc config.ClusterConfig) Manager {
 		return Disabled{cc: cc}
 	}
 
+	if config.HasIPv6(cc) {
+		klog.Infof("%s IP family found, recommending kindnet", cc.KubernetesConfig.IPFamily)
+		return KindNet{cc: cc}
+	}
+
 	if len(cc.Nodes) > 1 || cc.MultiNodeRequested {
 		// Enables KindNet CNI in master in multi node cluster, This solves the network problem
 		// inside pod for multi node clusters. See https://github.com/kubernetes/minikube/issues/9838.
@@ -168,6 +183,42 @@ func chooseDefault(cc config.ClusterConfig) Manager {
 	return Disabled{cc: cc}
 }
 
+// PodCIDR returns the pod CIDR of the cluster if it has one, such as on a shared network, else the default for its
+// IP family, comma separated for dual-stack clusters
+func PodCIDR(cc config.ClusterConfig) string {
+	if cc.KubernetesConfig.PodCIDR != "" {
+		return cc.KubernetesConfig.PodCIDR
+	}
+	switch cc.KubernetesConfig.IPFamily {
+	case constants.IPFamilyIPv6:
+		return DefaultPodCIDRv6
+	case constants.IPFamilyDual:
+		return DefaultPodCIDR + "," + DefaultPodCIDRv6
+	default:
+		return DefaultPodCIDR
+	}
+}
+
+// defaultRoute returns the default route of the pods for the IP family of the cluster, comma separated for dual-stack clusters
+func defaultRoute(cc config.ClusterConfig) string {
+	switch cc.KubernetesConfig.IPFamily {
+	case constants.IPFamilyIPv6:
+		return "::/0"
+	case constants.IPFamilyDual:
+		return "0.0.0.0/0,::/0"
+	default:
+		return "0.0.0.0/0"
+	}
+}
+
+// podCIDROrDefault returns the pod CIDR of the cluster if it has one, else the default IPv4 one
+func podCIDROrDefault(cc config.ClusterConfig) string {
+	if cc.KubernetesConfig.PodCIDR != "" {
+		return cc.KubernetesConfig.PodCIDR
+	}
+	return DefaultPodCIDR
+}
+
 // manifestPath returns the path to the CNI manifest
 func manifestPath() string {
 	return path.Join(vmpath.GuestEphemeralDir, "cni.yaml")
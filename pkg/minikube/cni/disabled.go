@@ -48,5 +48,5 @@ func (c Disabled) Apply(r Runner) error {
 // CIDR returns the default CIDR used by this CNI
 func (c Disabled) CIDR() string {
 	// Even without any CNI we want our nodes to have spec.PodCIDR set.
-	return DefaultPodCIDR
+	return podCIDROrDefault(c.cc)
 }
@@ -17,17 +17,20 @@ limitations under the License.
 package cni
 
 import (
+	"bytes"
 	"os/exec"
 	"path/filepath"
+	"text/template"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 )
 
 // From https://raw.githubusercontent.com/coreos/flannel/master/Documentation/kube-flannel.yml
-var flannelTmpl = `---
+var flannelTmpl = template.Must(template.New("flannel").Parse(`---
 apiVersion: policy/v1beta1
 kind: PodSecurityPolicy
 metadata:
@@ -198,7 +201,7 @@ spec:
       serviceAccountName: flannel
       initContainers:
       - name: install-cni
-        image: quay.io/coreos/flannel:v0.12.0-amd64
+        image: quay.io/coreos/flannel:{{ .Version }}-amd64
         command:
         - cp
         args:
@@ -212,7 +215,7 @@ spec:
           mountPath: /etc/kube-flannel/
       containers:
       - name: kube-flannel
-        image: quay.io/coreos/flannel:v0.12.0-amd64
+        image: quay.io/coreos/flannel:{{ .Version }}-amd64
         command:
         - /opt/bin/flanneld
         args:
@@ -292,7 +295,7 @@ spec:
       serviceAccountName: flannel
       initContainers:
       - name: install-cni
-        image: quay.io/coreos/flannel:v0.12.0-arm64
+        image: quay.io/coreos/flannel:{{ .Version }}-arm64
         command:
         - cp
         args:
@@ -306,7 +309,7 @@ spec:
           mountPath: /etc/kube-flannel/
       containers:
       - name: kube-flannel
-        image: quay.io/coreos/flannel:v0.12.0-arm64
+        image: quay.io/coreos/flannel:{{ .Version }}-arm64
         command:
         - /opt/bin/flanneld
         args:
@@ -386,7 +389,7 @@ spec:
       serviceAccountName: flannel
       initContainers:
       - name: install-cni
-        image: quay.io/coreos/flannel:v0.12.0-arm
+        image: quay.io/coreos/flannel:{{ .Version }}-arm
         command:
         - cp
         args:
@@ -400,7 +403,7 @@ spec:
           mountPath: /etc/kube-flannel/
       containers:
       - name: kube-flannel
-        image: quay.io/coreos/flannel:v0.12.0-arm
+        image: quay.io/coreos/flannel:{{ .Version }}-arm
         command:
         - /opt/bin/flanneld
         args:
@@ -480,7 +483,7 @@ spec:
       serviceAccountName: flannel
       initContainers:
       - name: install-cni
-        image: quay.io/coreos/flannel:v0.12.0-ppc64le
+        image: quay.io/coreos/flannel:{{ .Version }}-ppc64le
         command:
         - cp
         args:
@@ -494,7 +497,7 @@ spec:
           mountPath: /etc/kube-flannel/
       containers:
       - name: kube-flannel
-        image: quay.io/coreos/flannel:v0.12.0-ppc64le
+        image: quay.io/coreos/flannel:{{ .Version }}-ppc64le
         command:
         - /opt/bin/flanneld
         args:
@@ -574,7 +577,7 @@ spec:
       serviceAccountName: flannel
       initContainers:
       - name: install-cni
-        image: quay.io/coreos/flannel:v0.12.0-s390x
+        image: quay.io/coreos/flannel:{{ .Version }}-s390x
         command:
         - cp
         args:
@@ -588,7 +591,7 @@ spec:
           mountPath: /etc/kube-flannel/
       containers:
       - name: kube-flannel
-        image: quay.io/coreos/flannel:v0.12.0-s390x
+        image: quay.io/coreos/flannel:{{ .Version }}-s390x
         command:
         - /opt/bin/flanneld
         args:
@@ -629,7 +632,7 @@ spec:
         - name: flannel-cfg
           configMap:
             name: kube-flannel-cfg
-`
+`))
 
 // Flannel is the Flannel CNI manager
 type Flannel struct {
@@ -664,7 +667,20 @@ func (c Flannel) Apply(r Runner) error {
 		}
 	}
 
-	return applyManifest(c.cc, r, manifestAsset([]byte(flannelTmpl)))
+	m, err := c.manifest()
+	if err != nil {
+		return errors.Wrap(err, "manifest")
+	}
+	return applyManifest(c.cc, r, m)
+}
+
+// manifest returns a Kubernetes manifest for a CNI
+func (c Flannel) manifest() (assets.CopyableFile, error) {
+	b := bytes.Buffer{}
+	if err := flannelTmpl.Execute(&b, &tmplInput{Version: version(c.cc, "flannel")}); err != nil {
+		return nil, err
+	}
+	return customizedManifest(c.cc, b.Bytes())
 }
 
 // CIDR returns the default CIDR used by this CNI
@@ -163,9 +163,9 @@ func (c KindNet) String() string {
 // manifest returns a Kubernetes manifest for a CNI
 func (c KindNet) manifest() (assets.CopyableFile, error) {
 	input := &tmplInput{
-		DefaultRoute: "0.0.0.0/0", // assumes IPv4
-		PodCIDR:      DefaultPodCIDR,
-		ImageName:    images.KindNet(c.cc.KubernetesConfig.ImageRepository),
+		DefaultRoute: defaultRoute(c.cc),
+		PodCIDR:      c.CIDR(),
+		ImageName:    withTag(images.KindNet(c.cc.KubernetesConfig.ImageRepository), version(c.cc, "kindnet")),
 		CNIConfDir:   ConfDir,
 	}
 
@@ -173,7 +173,7 @@ func (c KindNet) manifest() (assets.CopyableFile, error) {
 	if err := kindNetManifest.Execute(&b, input); err != nil {
 		return nil, err
 	}
-	return manifestAsset(b.Bytes()), nil
+	return customizedManifest(c.cc, b.Bytes())
 }
 
 // Apply enables the CNI
@@ -193,5 +193,5 @@ func (c KindNet) Apply(r Runner) error {
 
 // CIDR returns the default CIDR used by this CNI
 func (c KindNet) CIDR() string {
-	return DefaultPodCIDR
+	return PodCIDR(c.cc)
 }
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// customizedManifest returns the manifest of a bundled CNI, customized by the values file of the cluster if it has one
+func customizedManifest(cc config.ClusterConfig, manifest []byte) (assets.CopyableFile, error) {
+	if cc.KubernetesConfig.CNIValues == "" {
+		return manifestAsset(manifest), nil
+	}
+	values, err := ioutil.ReadFile(cc.KubernetesConfig.CNIValues)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading cni values")
+	}
+	b, err := applyValues(manifest, values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "applying %s", cc.KubernetesConfig.CNIValues)
+	}
+	return manifestAsset(b), nil
+}
+
+// applyValues merges the objects of a values file into the objects of a manifest with the same kind, namespace and
+// name, and appends the others. Maps are merged, null values remove keys, and lists of maps with a name, such as
+// containers or env, are merged by name. Other values are replaced.
+func applyValues(manifest, values []byte) ([]byte, error) {
+	objs, err := decodeObjects(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing manifest")
+	}
+	overlays, err := decodeObjects(values)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing values")
+	}
+
+	for _, o := range overlays {
+		merged := false
+		for i := range objs {
+			if objectKey(objs[i]) == objectKey(o) {
+				objs[i] = merge(objs[i], o).(map[string]interface{})
+				merged = true
+			}
+		}
+		if !merged {
+			objs = append(objs, o)
+		}
+	}
+
+	// JSON is valid YAML, and keeps integers from being written in exponent notation
+	var b bytes.Buffer
+	for _, o := range objs {
+		data, err := json.Marshal(o)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString("---\n")
+		b.Write(data)
+		b.WriteString("\n")
+	}
+	return b.Bytes(), nil
+}
+
+// decodeObjects returns the objects of a YAML or JSON stream
+func decodeObjects(data []byte) ([]map[string]interface{}, error) {
+	var objs []map[string]interface{}
+	d := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var o map[string]interface{}
+		if err := d.Decode(&o); err != nil {
+			if err == io.EOF {
+				return objs, nil
+			}
+			return nil, err
+		}
+		if len(o) > 0 {
+			objs = append(objs, o)
+		}
+	}
+}
+
+// objectKey identifies an object by its kind, namespace and name
+func objectKey(o map[string]interface{}) string {
+	meta, _ := o["metadata"].(map[string]interface{})
+	return fmt.Sprintf("%v/%v/%v", o["kind"], meta["namespace"], meta["name"])
+}
+
+// merge returns the merge of src into dst
+func merge(dst, src interface{}) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			return s
+		}
+		for k, v := range s {
+			if v == nil {
+				delete(d, k)
+				continue
+			}
+			d[k] = merge(d[k], v)
+		}
+		return d
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok || len(s) == 0 || !named(d) || !named(s) {
+			return s
+		}
+		for _, se := range s {
+			found := false
+			for i, de := range d {
+				if de.(map[string]interface{})["name"] == se.(map[string]interface{})["name"] {
+					d[i] = merge(de, se)
+					found = true
+				}
+			}
+			if !found {
+				d = append(d, se)
+			}
+		}
+		return d
+	default:
+		return src
+	}
+}
+
+// named returns whether a list only holds maps with a name
+func named(l []interface{}) bool {
+	for _, e := range l {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m["name"]; !ok {
+			return false
+		}
+	}
+	return true
+}
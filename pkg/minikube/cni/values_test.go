@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyValues(t *testing.T) {
+	manifest := `---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: cilium
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: cilium-agent
+        image: cilium/cilium:v1.8.0
+        args: ["--config-dir=/tmp/cilium"]
+        env:
+        - name: K8S_NODE_NAME
+          value: node
+        - name: CILIUM_DEBUG
+          value: "false"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cilium-config
+  namespace: kube-system
+data:
+  debug: "false"
+  tunnel: vxlan
+`
+	values := `
+kind: DaemonSet
+metadata:
+  name: cilium
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: cilium-agent
+        args: ["--debug"]
+        env:
+        - name: CILIUM_DEBUG
+          value: "true"
+        resources:
+          limits:
+            memory: 1000000
+---
+kind: ConfigMap
+metadata:
+  name: cilium-config
+  namespace: kube-system
+data:
+  debug: "true"
+  tunnel: null
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: extra
+  namespace: kube-system
+`
+	b, err := applyValues([]byte(manifest), []byte(values))
+	if err != nil {
+		t.Fatalf("applyValues: %v", err)
+	}
+	got, err := decodeObjects(b)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", b, err)
+	}
+	want, err := decodeObjects([]byte(`---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: cilium
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: cilium-agent
+        image: cilium/cilium:v1.8.0
+        args: ["--debug"]
+        env:
+        - name: K8S_NODE_NAME
+          value: node
+        - name: CILIUM_DEBUG
+          value: "true"
+        resources:
+          limits:
+            memory: 1000000
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cilium-config
+  namespace: kube-system
+data:
+  debug: "true"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: extra
+  namespace: kube-system
+`))
+	if err != nil {
+		t.Fatalf("decoding want: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("applyValues() = %s, want %v", b, want)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+// Version is a release of a bundled CNI whose images work with the manifest minikube embeds for it
+type Version struct {
+	Version string
+	// MinKubernetes is the oldest Kubernetes version the release supports
+	MinKubernetes string
+	// MaxKubernetes, if set, is the first Kubernetes version the manifest does not work with anymore
+	MaxKubernetes string
+}
+
+// Versions are the releases of the bundled CNIs which can be chosen with --cni=<name>@<version>, oldest first.
+// The first release of each CNI is its default.
+var Versions = map[string][]Version{
+	// the CRDs of the manifest are apiextensions.k8s.io/v1beta1, removed in v1.22
+	"calico": {
+		{Version: "v3.14.1", MinKubernetes: "1.16.0", MaxKubernetes: "1.22.0"},
+		{Version: "v3.14.2", MinKubernetes: "1.16.0", MaxKubernetes: "1.22.0"},
+	},
+	"cilium": {
+		{Version: "v1.8.0", MinKubernetes: "1.11.0"},
+		{Version: "v1.8.5", MinKubernetes: "1.11.0"},
+	},
+	// the RBAC of the manifest is rbac.authorization.k8s.io/v1beta1, removed in v1.22
+	"flannel": {
+		{Version: "v0.12.0", MinKubernetes: "1.12.0", MaxKubernetes: "1.22.0"},
+		{Version: "v0.13.0", MinKubernetes: "1.12.0", MaxKubernetes: "1.22.0"},
+		{Version: "v0.14.0", MinKubernetes: "1.12.0", MaxKubernetes: "1.22.0"},
+	},
+	"kindnet": {
+		{Version: "v20210326-1e038dc5"},
+	},
+}
+
+// ParseCNI splits a --cni value of the form <name>[@<version>]
+func ParseCNI(s string) (string, string) {
+	// paths to manifests may contain @, only the names of bundled CNIs take a version
+	if i := strings.LastIndex(s, "@"); i != -1 {
+		if _, ok := Versions[bundledName(s[:i])]; ok {
+			return s[:i], s[i+1:]
+		}
+	}
+	return s, ""
+}
+
+// bundledName returns the name of the bundled CNI a --cni value refers to
+func bundledName(name string) string {
+	name = strings.ToLower(name)
+	if name == "true" {
+		return "kindnet"
+	}
+	return name
+}
+
+// ResolveVersion returns the release of a bundled CNI matching a version, which may omit the leading "v" and the patch
+// release, and checks it supports a Kubernetes version, unless k8sVersion is empty. An empty version resolves to the
+// default release.
+func ResolveVersion(name, version, k8sVersion string) (string, error) {
+	name = bundledName(name)
+	vs, ok := Versions[name]
+	if !ok {
+		if version != "" {
+			return "", fmt.Errorf("the %s CNI does not support choosing its version", name)
+		}
+		return "", nil
+	}
+
+	var found *Version
+	if version == "" {
+		found = &vs[0]
+	} else {
+		want := "v" + strings.TrimPrefix(version, "v")
+		for i := range vs {
+			if vs[i].Version == want || strings.HasPrefix(vs[i].Version, want+".") {
+				found = &vs[i]
+			}
+		}
+	}
+	if found == nil {
+		var known []string
+		for _, v := range vs {
+			known = append(known, v.Version)
+		}
+		return "", fmt.Errorf("%s %s is not supported, supported versions: %s", name, version, strings.Join(known, ", "))
+	}
+
+	if k8sVersion == "" {
+		return found.Version, nil
+	}
+	k8s, err := util.ParseKubernetesVersion(k8sVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing kubernetes version")
+	}
+	if found.MinKubernetes != "" && k8s.LT(semver.MustParse(found.MinKubernetes)) {
+		return "", fmt.Errorf("%s %s requires Kubernetes v%s or newer, got %s", name, found.Version, found.MinKubernetes, k8sVersion)
+	}
+	if found.MaxKubernetes != "" && k8s.GTE(semver.MustParse(found.MaxKubernetes)) {
+		return "", fmt.Errorf("%s %s does not support Kubernetes v%s or newer, got %s", name, found.Version, found.MaxKubernetes, k8sVersion)
+	}
+	return found.Version, nil
+}
+
+// version returns the release of a bundled CNI a cluster uses
+func version(cc config.ClusterConfig, name string) string {
+	if cc.KubernetesConfig.CNIVersion != "" && bundledName(cc.KubernetesConfig.CNI) == name {
+		if v, err := ResolveVersion(name, cc.KubernetesConfig.CNIVersion, ""); err == nil {
+			return v
+		}
+	}
+	return Versions[name][0].Version
+}
+
+// withTag returns an image with its tag replaced
+func withTag(image, tag string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image + ":" + tag
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"testing"
+)
+
+func TestParseCNI(t *testing.T) {
+	tests := []struct {
+		in      string
+		name    string
+		version string
+	}{
+		{"", "", ""},
+		{"calico", "calico", ""},
+		{"cilium@1.8", "cilium", "1.8"},
+		{"true@v20210326-1e038dc5", "true", "v20210326-1e038dc5"},
+		{"bridge@1.0", "bridge@1.0", ""},
+		{"/home/me@work/cni.yaml", "/home/me@work/cni.yaml", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			name, version := ParseCNI(tc.in)
+			if name != tc.name || version != tc.version {
+				t.Errorf("ParseCNI(%q) = %q, %q, want %q, %q", tc.in, name, version, tc.name, tc.version)
+			}
+		})
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		k8s     string
+		want    string
+		wantErr bool
+	}{
+		{"calico", "", "v1.21.2", "v3.14.1", false},
+		{"calico", "3.14", "v1.21.2", "v3.14.2", false},
+		{"calico", "v3.14.1", "v1.21.2", "v3.14.1", false},
+		{"calico", "3.14", "v1.22.0", "", true},
+		{"calico", "3.14", "v1.15.0", "", true},
+		{"calico", "3.20", "v1.21.2", "", true},
+		{"cilium", "1.8", "", "v1.8.5", false},
+		{"flannel", "0.13.0", "v1.20.0", "v0.13.0", false},
+		{"true", "", "v1.21.2", "v20210326-1e038dc5", false},
+		{"bridge", "", "v1.21.2", "", false},
+		{"bridge", "1.0", "v1.21.2", "", true},
+		{"", "", "v1.21.2", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name+"@"+tc.version+"/"+tc.k8s, func(t *testing.T) {
+			got, err := ResolveVersion(tc.name, tc.version, tc.k8s)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ResolveVersion() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ResolveVersion() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"calico/node:v3.14.1", "calico/node:v9"},
+		{"localhost:5000/calico/node:v3.14.1", "localhost:5000/calico/node:v9"},
+		{"localhost:5000/calico/node", "localhost:5000/calico/node:v9"},
+	}
+	for _, tc := range tests {
+		if got := withTag(tc.image, "v9"); got != tc.want {
+			t.Errorf("withTag(%q) = %q, want %q", tc.image, got, tc.want)
+		}
+	}
+}
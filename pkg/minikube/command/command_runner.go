@@ -77,6 +77,9 @@ type Runner interface {
 
 	// Remove is a convenience method that runs a command to remove a file
 	Remove(assets.CopyableFile) error
+
+	// CopyFrom is a convenience method that streams the content of a file to w, without holding it in memory
+	CopyFrom(src string, w io.Writer) error
 }
 
 // Command returns a human readable command string that does not induce eye fatigue
@@ -186,6 +189,17 @@ func fileExists(r Runner, f assets.CopyableFile, dst string) (bool, error) {
 	return srcModTime.Equal(dstModTime), nil
 }
 
+// streamFile writes f to dst through the stdin of a command run by r, without a local copy of it
+func streamFile(r Runner, f assets.CopyableFile, dst string) error {
+	cmd := exec.Command("sudo", "dd", "of="+dst, "bs=1M")
+	cmd.Stdin = f
+	if _, err := r.RunCmd(cmd); err != nil {
+		return errors.Wrapf(err, "streaming %s", dst)
+	}
+	_, err := r.RunCmd(exec.Command("sudo", "chmod", f.GetPermissions(), dst))
+	return err
+}
+
 // writeFile is like ioutil.WriteFile, but does not require reading file into memory
 func writeFile(dst string, f assets.CopyableFile, perms os.FileMode) error {
 	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE, perms)
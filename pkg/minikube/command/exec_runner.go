@@ -159,6 +159,10 @@ func (e *execRunner) Copy(f assets.CopyableFile) error {
 		return errors.Wrapf(err, "error converting permissions %s to integer", f.GetPermissions())
 	}
 
+	if e.sudo && src == assets.MemorySource && f.GetLength() > (1024*1024) {
+		// If >1MB, avoid local copy
+		return streamFile(e, f, dst)
+	}
 	if e.sudo {
 		// write to temp location ...
 		tmpfile, err := ioutil.TempFile("", "minikube")
@@ -184,6 +188,22 @@ func (e *execRunner) Copy(f assets.CopyableFile) error {
 	return writeFile(dst, f, os.FileMode(perms))
 }
 
+// CopyFrom streams the content of a file to w
+func (e *execRunner) CopyFrom(src string, w io.Writer) error {
+	c := exec.Command("cat", src)
+	if e.sudo {
+		c = exec.Command("sudo", "cat", src)
+	}
+	var stderr bytes.Buffer
+	c.Stdout = w
+	c.Stderr = &stderr
+	klog.Infof("copy from: %s", src)
+	if err := c.Run(); err != nil {
+		return errors.Wrapf(err, "cat %s: %s", src, stderr.String())
+	}
+	return nil
+}
+
 // Remove removes a file
 func (e *execRunner) Remove(f assets.CopyableFile) error {
 	dst := filepath.Join(f.GetTargetDir(), f.GetTargetName())
@@ -148,6 +148,16 @@ func (f *FakeCommandRunner) Remove(file assets.CopyableFile) error {
 	return nil
 }
 
+// CopyFrom writes the contents stored for the filename to w
+func (f *FakeCommandRunner) CopyFrom(src string, w io.Writer) error {
+	v, ok := f.fileMap.Load(src)
+	if !ok {
+		return fmt.Errorf("%s: no such file", src)
+	}
+	_, err := io.WriteString(w, v.(string))
+	return err
+}
+
 // SetFileToContents stores the file to contents map for the FakeCommandRunner
 func (f *FakeCommandRunner) SetFileToContents(fileToContents map[string]string) {
 	for k, v := range fileToContents {
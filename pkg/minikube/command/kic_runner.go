@@ -184,6 +184,10 @@ func (k *kicRunner) Copy(f assets.CopyableFile) error {
 				return k.chmod(dst, f.GetPermissions())
 			}
 		}
+	} else if f.GetLength() > (1024 * 1024) {
+		// If >1MB, avoid local copy
+		klog.Infof("%s (stream): %s --> %s (%d bytes)", k.ociBin, src, dst, f.GetLength())
+		return streamFile(k, f, dst)
 	}
 	klog.Infof("%s (temp): %s --> %s (%d bytes)", k.ociBin, src, dst, f.GetLength())
 
@@ -269,6 +273,20 @@ func copyToDocker(src string, dest string) error {
 }
 
 // Remove removes a file
+// CopyFrom streams the content of a file in the container to w
+func (k *kicRunner) CopyFrom(src string, w io.Writer) error {
+	var stderr bytes.Buffer
+	oc := exec.Command(k.ociBin, "exec", "--privileged", k.nameOrID, "sudo", "cat", src)
+	oc.Stdout = w
+	oc.Stderr = &stderr
+	oc = oci.PrefixCmd(oc)
+	klog.Infof("copy from: %v", oc.Args)
+	if err := oc.Run(); err != nil {
+		return errors.Wrapf(err, "cat %s: %s", src, stderr.String())
+	}
+	return nil
+}
+
 func (k *kicRunner) Remove(f assets.CopyableFile) error {
 	dst := path.Join(f.GetTargetDir(), f.GetTargetName())
 	klog.Infof("rm: %s", dst)
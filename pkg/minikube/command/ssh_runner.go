@@ -108,6 +108,24 @@ func (s *SSHRunner) Remove(f assets.CopyableFile) error {
 	return sess.Run(fmt.Sprintf("sudo rm %s", dst))
 }
 
+// CopyFrom streams the content of a file on the remote to w
+func (s *SSHRunner) CopyFrom(src string, w io.Writer) error {
+	klog.Infof("copy from: %s", src)
+	sess, err := s.session()
+	if err != nil {
+		return errors.Wrap(err, "getting ssh session")
+	}
+	defer sess.Close()
+
+	var stderr bytes.Buffer
+	sess.Stdout = w
+	sess.Stderr = &stderr
+	if err := sess.Run(shellquote.Join("sudo", "cat", src)); err != nil {
+		return errors.Wrapf(err, "cat %s: %s", src, stderr.String())
+	}
+	return nil
+}
+
 // teeSSH runs an SSH command, streaming stdout, stderr to logs
 func teeSSH(s *ssh.Session, cmd string, outB io.Writer, errB io.Writer) error {
 	outPipe, err := s.StdoutPipe()
@@ -50,6 +50,10 @@ const (
 	AddonListFlag = "addons"
 	// EmbedCerts represents the config for embedding certificates in kubeconfig
 	EmbedCerts = "EmbedCerts"
+	// Rootless is the key for running the podman driver in rootless mode
+	Rootless = "rootless"
+	// BundleFlag is the key for the offline bundle a cluster is started from
+	BundleFlag = "bundle"
 )
 
 var (
@@ -229,7 +233,7 @@ func MultiNode(cc ClusterConfig) bool {
 		return true
 	}
 
-	if viper.GetInt("nodes") > 1 {
+	if viper.GetInt("nodes") > 1 || viper.GetBool("ha") {
 		return true
 	}
 
@@ -233,6 +233,30 @@ func TestMachineName(t *testing.T) {
 			},
 			Want: "p2-m2",
 		},
+
+		{
+			ClusterConfig: ClusterConfig{Name: "ha",
+				Nodes: []Node{
+					{
+						Name:              "",
+						IP:                "192.168.39.2",
+						Port:              8443,
+						KubernetesVersion: "v1.21.2",
+						ControlPlane:      true,
+						Worker:            true,
+					},
+					{
+						Name:              "m02",
+						IP:                "192.168.39.3",
+						Port:              8443,
+						KubernetesVersion: "v1.21.2",
+						ControlPlane:      true,
+						Worker:            true,
+					},
+				},
+			},
+			Want: "ha-m02",
+		},
 	}
 
 	for _, tc := range testsCases {
@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/util/lock"
 )
@@ -50,6 +51,52 @@ func (p *Profile) IsValid() bool {
 	return true
 }
 
+// ControlPlanes returns all the control plane nodes of the cluster
+func ControlPlanes(cc ClusterConfig) []Node {
+	cps := []Node{}
+	for _, n := range cc.Nodes {
+		if n.ControlPlane {
+			cps = append(cps, n)
+		}
+	}
+	return cps
+}
+
+// HasIPv6 returns whether the cluster nodes have IPv6 addresses, that is for ipv6 and dual-stack clusters
+func HasIPv6(cc ClusterConfig) bool {
+	f := cc.KubernetesConfig.IPFamily
+	return f == constants.IPFamilyIPv6 || f == constants.IPFamilyDual
+}
+
+// NodeAddress returns the address Kubernetes components of the node should advertise, in the primary IP family of the cluster
+func NodeAddress(cc ClusterConfig, n Node) string {
+	if cc.KubernetesConfig.IPFamily == constants.IPFamilyIPv6 && n.IPv6 != "" {
+		return n.IPv6
+	}
+	return n.IP
+}
+
+// NodeIPs returns the comma separated addresses of the node, primary IP family first, as expected by the kubelet --node-ip flag
+func NodeIPs(cc ClusterConfig, n Node) string {
+	if n.IPv6 == "" {
+		return n.IP
+	}
+	switch cc.KubernetesConfig.IPFamily {
+	case constants.IPFamilyIPv6:
+		return n.IPv6
+	case constants.IPFamilyDual:
+		return n.IP + "," + n.IPv6
+	default:
+		return n.IP
+	}
+}
+
+// IsPrimaryControlPlane returns whether n is the first created control plane of the cluster
+func IsPrimaryControlPlane(cc ClusterConfig, n Node) bool {
+	cp, err := PrimaryControlPlane(&cc)
+	return err == nil && cp.Name == n.Name
+}
+
 // PrimaryControlPlane gets the node specific config for the first created control plane
 func PrimaryControlPlane(cc *ClusterConfig) (Node, error) {
 	for _, n := range cc.Nodes {
@@ -333,8 +380,37 @@ func ProfileFolderPath(profile string, miniHome ...string) string {
 // MachineName returns the name of the machine, as seen by the hypervisor given the cluster and node names
 func MachineName(cc ClusterConfig, n Node) string {
 	// For single node cluster, default to back to old naming
-	if (len(cc.Nodes) == 1 && cc.Nodes[0].Name == n.Name) || n.ControlPlane {
+	if len(cc.Nodes) == 1 && cc.Nodes[0].Name == n.Name {
 		return cc.Name
 	}
+	// The primary control plane keeps the name of the cluster, the others of HA clusters are named like workers
+	if n.ControlPlane {
+		for _, cn := range cc.Nodes {
+			if cn.ControlPlane {
+				if cn.Name == n.Name {
+					return cc.Name
+				}
+				break
+			}
+		}
+		if len(cc.Nodes) == 0 {
+			return cc.Name
+		}
+	}
 	return fmt.Sprintf("%s-%s", cc.Name, n.Name)
 }
+
+// NodeResources returns the CPUs, memory and disk size in MB of a node, which are those of the cluster unless it was resized
+func NodeResources(cc ClusterConfig, n Node) (cpus int, memory int, disk int) {
+	cpus, memory, disk = cc.CPUs, cc.Memory, cc.DiskSize
+	if n.CPUs != 0 {
+		cpus = n.CPUs
+	}
+	if n.Memory != 0 {
+		memory = n.Memory
+	}
+	if n.DiskSize != 0 {
+		disk = n.DiskSize
+	}
+	return cpus, memory, disk
+}
@@ -347,3 +347,53 @@ func TestGetPrimaryControlPlane(t *testing.T) {
 		}()
 	}
 }
+
+func TestNodeIPs(t *testing.T) {
+	n := Node{IP: "192.168.49.2", IPv6: "fd00:192:168:49::2"}
+	tests := []struct {
+		family  string
+		node    Node
+		ips     string
+		address string
+	}{
+		{"", n, "192.168.49.2", "192.168.49.2"},
+		{"ipv4", n, "192.168.49.2", "192.168.49.2"},
+		{"ipv6", n, "fd00:192:168:49::2", "fd00:192:168:49::2"},
+		{"dual", n, "192.168.49.2,fd00:192:168:49::2", "192.168.49.2"},
+		{"dual", Node{IP: "192.168.49.2"}, "192.168.49.2", "192.168.49.2"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.family, func(t *testing.T) {
+			cc := ClusterConfig{KubernetesConfig: KubernetesConfig{IPFamily: tc.family}}
+			if got := NodeIPs(cc, tc.node); got != tc.ips {
+				t.Errorf("NodeIPs() = %q, want %q", got, tc.ips)
+			}
+			if got := NodeAddress(cc, tc.node); got != tc.address {
+				t.Errorf("NodeAddress() = %q, want %q", got, tc.address)
+			}
+		})
+	}
+}
+
+func TestNodeResources(t *testing.T) {
+	cc := ClusterConfig{CPUs: 2, Memory: 2200, DiskSize: 20000}
+	tests := []struct {
+		desc   string
+		node   Node
+		cpus   int
+		memory int
+		disk   int
+	}{
+		{"cluster", Node{Name: "m02"}, 2, 2200, 20000},
+		{"resized", Node{Name: "m02", CPUs: 4, Memory: 8192, DiskSize: 40000}, 4, 8192, 40000},
+		{"memory only", Node{Name: "m02", Memory: 4096}, 2, 4096, 20000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			cpus, memory, disk := NodeResources(cc, tc.node)
+			if cpus != tc.cpus || memory != tc.memory || disk != tc.disk {
+				t.Errorf("NodeResources() = %d, %d, %d, want %d, %d, %d", cpus, memory, disk, tc.cpus, tc.memory, tc.disk)
+			}
+		})
+	}
+}
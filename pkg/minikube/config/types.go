@@ -46,6 +46,7 @@ type ClusterConfig struct {
 	HyperkitVSockPorts      []string // Only used by the Hyperkit driver
 	DockerEnv               []string // Each entry is formatted as KEY=VALUE.
 	ContainerVolumeMounts   []string // Only used by container drivers: Docker, Podman
+	Mounts                  []Mount  // host directories the mount manager keeps mounted, see 'minikube mount add'
 	InsecureRegistry        []string
 	RegistryMirror          []string
 	HostOnlyCIDR            string // Only used by the virtualbox driver
@@ -74,15 +75,21 @@ type ClusterConfig struct {
 	KubernetesConfig        KubernetesConfig
 	Nodes                   []Node
 	Addons                  map[string]bool
-	CustomAddonImages       map[string]string // Maps image names to the image to use for addons. e.g. Dashboard -> k8s.gcr.io/echoserver:1.4 makes dashboard addon use echoserver for its Dashboard deployment.
-	CustomAddonRegistries   map[string]string // Maps image names to the registry to use for addons. See CustomAddonImages for example.
-	VerifyComponents        map[string]bool   // map of components to verify and wait for after start.
+	CustomAddonImages       map[string]string            // Maps image names to the image to use for addons. e.g. Dashboard -> k8s.gcr.io/echoserver:1.4 makes dashboard addon use echoserver for its Dashboard deployment.
+	CustomAddonRegistries   map[string]string            // Maps image names to the registry to use for addons. See CustomAddonImages for example.
+	AddonValues             map[string]map[string]string // Maps addon names to the values of their parameters set with 'minikube addons configure'
+	VerifyComponents        map[string]bool              // map of components to verify and wait for after start.
+	WaitTimeouts            map[string]time.Duration     // how long to wait for components given a timeout with --wait=component:timeout
 	StartHostTimeout        time.Duration
 	ScheduledStop           *ScheduledStopConfig
+	Lifecycle               *LifecycleConfig
 	ExposedPorts            []string // Only used by the docker and podman driver
 	ListenAddress           string   // Only used by the docker and podman driver
+	DockerHost              string   // Only used by the docker and podman driver: the remote daemon, as ssh://user@host or tcp://host:port
+	GPUs                    string   // Only used by the docker and podman driver
 	Network                 string   // only used by docker driver
 	MultiNodeRequested      bool
+	HA                      bool // whether the control plane is replicated behind a virtual IP
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -93,15 +100,19 @@ type KubernetesConfig struct {
 	APIServerName       string
 	APIServerNames      []string
 	APIServerIPs        []net.IP
+	APIServerHAVIP      string // virtual IP fronting the apiservers of an HA cluster
 	DNSDomain           string
 	ContainerRuntime    string
 	CRISocket           string
 	NetworkPlugin       string
 	FeatureGates        string // https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates/
 	ServiceCIDR         string // the subnet which Kubernetes services will be deployed to
+	PodCIDR             string // the subnet pods get their IPs from, empty for the default of the CNI
+	IPFamily            string // ipv4, ipv6 or dual; empty means ipv4
 	ImageRepository     string
 	LoadBalancerStartIP string // currently only used by MetalLB addon
 	LoadBalancerEndIP   string // currently only used by MetalLB addon
+	LoadBalancerIPRange string // CIDR minikube tunnel allocates LoadBalancer IPs from, empty to use the ClusterIP
 	CustomIngressCert   string // used by Ingress addon
 	ExtraOptions        ExtraOptionSlice
 
@@ -109,6 +120,8 @@ type KubernetesConfig struct {
 
 	EnableDefaultCNI bool   // deprecated in preference to CNI
 	CNI              string // CNI to use
+	CNIVersion       string // release of the bundled CNI applied, see cni.Versions
+	CNIValues        string // path to a file customizing the manifest of the bundled CNI
 
 	// We need to keep these in the short term for backwards compatibility
 	NodeIP   string
@@ -120,10 +133,15 @@ type KubernetesConfig struct {
 type Node struct {
 	Name              string
 	IP                string
+	IPv6              string // only set for ipv6 and dual-stack clusters
 	Port              int
 	KubernetesVersion string
 	ControlPlane      bool
 	Worker            bool
+	// CPUs, Memory and DiskSize are set when the node was resized, else those of the cluster apply
+	CPUs     int
+	Memory   int
+	DiskSize int
 }
 
 // VersionedExtraOption holds information on flags to apply to a specific range
@@ -157,3 +175,23 @@ type ScheduledStopConfig struct {
 	InitiationTime int64
 	Duration       time.Duration
 }
+
+// Mount is a host directory the mount manager of a cluster keeps mounted into its primary control plane
+type Mount struct {
+	HostPath   string
+	GuestPath  string
+	Persistent bool // whether the mount is re-established on every start, rather than dropped when the cluster stops
+	Type       string
+	UID        string
+	GID        string
+	Version    string
+	MSize      int
+	Mode       uint32
+	Options    []string
+}
+
+// LifecycleConfig holds the cron schedules on which the cluster is started and stopped, see 'minikube schedule set'
+type LifecycleConfig struct {
+	Start string
+	Stop  string
+}
@@ -64,6 +64,18 @@ const (
 	ClusterDNSDomain = "cluster.local"
 	// DefaultServiceCIDR is The CIDR to be used for service cluster IPs
 	DefaultServiceCIDR = "10.96.0.0/12"
+	// DefaultServiceCIDRv6 is the CIDR to be used for IPv6 service cluster IPs
+	DefaultServiceCIDRv6 = "fd00:10:96::/112"
+	// IPFamilyIPv4 is the default, IPv4 only, cluster IP family
+	IPFamilyIPv4 = "ipv4"
+	// IPFamilyIPv6 is the IPv6 only cluster IP family
+	IPFamilyIPv6 = "ipv6"
+	// IPFamilyDual is the dual-stack (IPv4 and IPv6) cluster IP family
+	IPFamilyDual = "dual"
+	// GPUNvidia passes the NVIDIA GPUs of the host to the node containers
+	GPUNvidia = "nvidia"
+	// GPUAMD passes the AMD GPUs of the host to the node containers
+	GPUAMD = "amd"
 	// HostAlias is a DNS alias to the the container/VM host IP
 	HostAlias = "host.minikube.internal"
 	// ControlPlaneAlias is a DNS alias pointing to the apiserver frontend
@@ -89,6 +101,8 @@ const (
 	MinikubeActivePodmanEnv = "MINIKUBE_ACTIVE_PODMAN"
 	// MinikubeForceSystemdEnv is used to force systemd as cgroup manager for the container runtime
 	MinikubeForceSystemdEnv = "MINIKUBE_FORCE_SYSTEMD"
+	// MinikubeRootlessEnv is used to run the podman driver without sudo, in rootless mode
+	MinikubeRootlessEnv = "MINIKUBE_ROOTLESS"
 	// TestDiskUsedEnv is used in integration tests for insufficient storage with 'minikube status'
 	TestDiskUsedEnv = "MINIKUBE_TEST_STORAGE_CAPACITY"
 
@@ -158,6 +172,8 @@ var (
 	KICCacheDir = localpath.MakeMiniPath("cache", "kic")
 	// ImageCacheDir is the path to the container image cache directory
 	ImageCacheDir = localpath.MakeMiniPath("cache", "images")
+	// RegistryMirrorCacheDir is the path to the caches of the registry-mirror addon, one per container node
+	RegistryMirrorCacheDir = localpath.MakeMiniPath("cache", "registry-mirror")
 
 	// DefaultNamespaces are Kubernetes namespaces used by minikube, including addons
 	DefaultNamespaces = []string{
@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/blang/semver"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/assets"
@@ -40,6 +41,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/util/retry"
 )
 
 const (
@@ -100,7 +102,13 @@ oom_score = 0
     [plugins.cri.registry]
       [plugins.cri.registry.mirrors]
         [plugins.cri.registry.mirrors."docker.io"]
-          endpoint = ["https://registry-1.docker.io"]
+          endpoint = [{{ with index .RegistryMirrors "docker.io" }}"http://{{ . }}", {{ end }}"https://registry-1.docker.io"]
+        {{ range $registry, $mirror := .RegistryMirrors -}}
+        {{ if ne $registry "docker.io" -}}
+        [plugins.cri.registry.mirrors."{{ $registry }}"]
+          endpoint = ["http://{{ $mirror }}", "https://{{ $registry }}"]
+        {{ end -}}
+        {{ end -}}
         {{ range .InsecureRegistry -}}
         [plugins.cri.registry.mirrors."{{. -}}"]
           endpoint = ["http://{{. -}}"]
@@ -130,6 +138,8 @@ type Containerd struct {
 	KubernetesVersion semver.Version
 	Init              sysinit.Manager
 	InsecureRegistry  []string
+	RegistryMirrors   map[string]string
+	ProxyEnv          []string
 }
 
 // Name is a human readable name for containerd
@@ -180,7 +190,7 @@ func (r *Containerd) Available() error {
 }
 
 // generateContainerdConfig sets up /etc/containerd/config.toml
-func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version, forceSystemd bool, insecureRegistry []string) error {
+func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version, forceSystemd bool, insecureRegistry []string, registryMirrors map[string]string) error {
 	cPath := containerdConfigFile
 	t, err := template.New("containerd.config.toml").Parse(containerdConfigTemplate)
 	if err != nil {
@@ -191,11 +201,13 @@ func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semve
 		PodInfraContainerImage string
 		SystemdCgroup          bool
 		InsecureRegistry       []string
+		RegistryMirrors        map[string]string
 		CNIConfDir             string
 	}{
 		PodInfraContainerImage: pauseImage,
 		SystemdCgroup:          forceSystemd,
 		InsecureRegistry:       insecureRegistry,
+		RegistryMirrors:        registryMirrors,
 		CNIConfDir:             cni.ConfDir,
 	}
 	var b bytes.Buffer
@@ -219,12 +231,15 @@ func (r *Containerd) Enable(disOthers, forceSystemd bool) error {
 	if err := populateCRIConfig(r.Runner, r.SocketPath()); err != nil {
 		return err
 	}
-	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, forceSystemd, r.InsecureRegistry); err != nil {
+	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, forceSystemd, r.InsecureRegistry, r.RegistryMirrors); err != nil {
 		return err
 	}
 	if err := enableIPForwarding(r.Runner); err != nil {
 		return err
 	}
+	if _, err := generateProxyDropIn(r.Runner, "containerd", r.ProxyEnv); err != nil {
+		return err
+	}
 
 	// Otherwise, containerd will fail API requests with 'Unimplemented'
 	return r.Init.Restart("containerd")
@@ -292,6 +307,51 @@ func (r *Containerd) RemoveImage(name string) error {
 	return removeCRIImage(r.Runner, name)
 }
 
+// UnusedImages returns the images which no container uses
+func (r *Containerd) UnusedImages() ([]ImageInfo, error) {
+	return unusedCRIImages(r.Runner)
+}
+
+// BuildCacheSize returns how many bytes removing the cache of image builds would reclaim
+func (r *Containerd) BuildCacheSize() (int64, error) {
+	// buildkitd is only started to build images, there is no cache when it is not running
+	if _, err := r.Runner.RunCmd(exec.Command("sudo", "buildctl", "debug", "workers")); err != nil {
+		klog.Infof("buildkitd is not running: %v", err)
+		return 0, nil
+	}
+	rr, err := r.Runner.RunCmd(exec.Command("sudo", "buildctl", "du"))
+	if err != nil {
+		return 0, errors.Wrap(err, "buildctl du")
+	}
+	return parseBuildctlReclaimable(rr.Stdout.String())
+}
+
+// parseBuildctlReclaimable parses the reclaimable size in the output of 'buildctl du'
+func parseBuildctlReclaimable(output string) (int64, error) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "Reclaimable:" {
+			size, err := units.FromHumanSize(fields[1])
+			if err != nil {
+				return 0, errors.Wrapf(err, "parsing reclaimable size %q", fields[1])
+			}
+			return size, nil
+		}
+	}
+	return 0, nil
+}
+
+// PruneBuildCache removes the cache of image builds
+func (r *Containerd) PruneBuildCache() error {
+	if _, err := r.Runner.RunCmd(exec.Command("sudo", "buildctl", "debug", "workers")); err != nil {
+		return nil
+	}
+	if _, err := r.Runner.RunCmd(exec.Command("sudo", "buildctl", "prune")); err != nil {
+		return errors.Wrap(err, "buildctl prune")
+	}
+	return nil
+}
+
 func gitClone(cr CommandRunner, src string) (string, error) {
 	// clone to a temporary directory
 	rr, err := cr.RunCmd(exec.Command("mktemp", "-d"))
@@ -344,7 +404,10 @@ func downloadRemote(cr CommandRunner, src string) (string, error) {
 }
 
 // BuildImage builds an image into this runtime
-func (r *Containerd) BuildImage(src string, file string, tag string, push bool, env []string, opts []string) error {
+func (r *Containerd) BuildImage(src string, file string, tag string, push bool, env []string, opts []string, platform string, secrets []string) error {
+	if err := r.startBuildkitd(); err != nil {
+		return err
+	}
 	// download url if not already present
 	dir, err := downloadRemote(r.Runner, src)
 	if err != nil {
@@ -380,6 +443,12 @@ func (r *Containerd) BuildImage(src string, file string, tag string, push bool,
 		"--local", fmt.Sprintf("context=%s", dir),
 		"--local", fmt.Sprintf("dockerfile=%s", dir),
 		"--output", fmt.Sprintf("type=image%s", extra)}
+	if platform != "" {
+		args = append(args, "--opt", fmt.Sprintf("platform=%s", platform))
+	}
+	for _, secret := range secrets {
+		args = append(args, "--secret", secret)
+	}
 	for _, opt := range opts {
 		args = append(args, "--"+opt)
 	}
@@ -395,6 +464,30 @@ func (r *Containerd) BuildImage(src string, file string, tag string, push bool,
 	return nil
 }
 
+// startBuildkitd starts buildkitd if it is not already running, it is only needed to build images.
+// Base images older than the buildkit service only have the binary, which is then started directly.
+func (r *Containerd) startBuildkitd() error {
+	workers := func() error {
+		_, err := r.Runner.RunCmd(exec.Command("sudo", "buildctl", "debug", "workers"))
+		return err
+	}
+	if workers() == nil {
+		return nil
+	}
+	if _, err := r.Runner.RunCmd(exec.Command("sudo", "which", "buildkitd")); err != nil {
+		return errors.Wrap(err, "buildkitd is not installed on the node, recreate it with 'minikube delete' and 'minikube start' to build images")
+	}
+	klog.Infof("Starting buildkitd")
+	if err := r.Init.Start("buildkit"); err != nil {
+		klog.Warningf("unable to start the buildkit service, starting buildkitd directly: %v", err)
+		c := exec.Command("sudo", "-b", "/bin/sh", "-c", "exec buildkitd --oci-worker=false --containerd-worker=true --containerd-worker-namespace=k8s.io > /var/log/buildkitd.log 2>&1")
+		if _, err := r.Runner.RunCmd(c); err != nil {
+			return errors.Wrap(err, "starting buildkitd")
+		}
+	}
+	return retry.Expo(workers, 250*time.Millisecond, 30*time.Second)
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Containerd) CGroupDriver() (string, error) {
 	info, err := getCRIInfo(r.Runner)
@@ -459,8 +552,8 @@ func (r *Containerd) ContainerLogCmd(id string, len int, follow bool) string {
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *Containerd) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u containerd -n %d", len)
+func (r *Containerd) SystemLogCmd(len int, follow bool) string {
+	return journalLogCmd("containerd", len, follow)
 }
 
 // Preload preloads the container runtime with k8s images
@@ -23,10 +23,12 @@ import (
 	"html/template"
 	"os/exec"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/command"
 )
 
@@ -213,6 +215,79 @@ func removeCRIImage(cr CommandRunner, name string) error {
 	return nil
 }
 
+// criImages maps to 'crictl images -o json'
+type criImages struct {
+	Images []struct {
+		ID          string   `json:"id"`
+		RepoTags    []string `json:"repoTags"`
+		RepoDigests []string `json:"repoDigests"`
+		Size        string   `json:"size"`
+	} `json:"images"`
+}
+
+// criContainers maps to 'crictl ps -a -o json'
+type criContainers struct {
+	Containers []struct {
+		Image struct {
+			Image string `json:"image"`
+		} `json:"image"`
+		ImageRef string `json:"imageRef"`
+	} `json:"containers"`
+}
+
+// parseCRIImages parses the output of 'crictl images -o json'
+func parseCRIImages(data []byte) ([]ImageInfo, error) {
+	var ci criImages
+	if err := json.Unmarshal(data, &ci); err != nil {
+		return nil, errors.Wrap(err, "parsing crictl images")
+	}
+	var imgs []ImageInfo
+	for _, i := range ci.Images {
+		size, err := strconv.ParseInt(i.Size, 10, 64)
+		if err != nil {
+			klog.Warningf("invalid size %q of image %s", i.Size, i.ID)
+		}
+		imgs = append(imgs, ImageInfo{ID: i.ID, Tags: i.RepoTags, Digests: i.RepoDigests, Size: size})
+	}
+	return imgs, nil
+}
+
+// parseCRIContainerImages parses the images used by the containers in the output of 'crictl ps -a -o json'
+func parseCRIContainerImages(data []byte) (map[string]bool, error) {
+	var cc criContainers
+	if err := json.Unmarshal(data, &cc); err != nil {
+		return nil, errors.Wrap(err, "parsing crictl ps")
+	}
+	used := map[string]bool{}
+	for _, c := range cc.Containers {
+		used[c.Image.Image] = true
+		used[c.ImageRef] = true
+	}
+	return used, nil
+}
+
+// unusedCRIImages returns the images which no container uses, using crictl
+func unusedCRIImages(cr CommandRunner) ([]ImageInfo, error) {
+	crictl := getCrictlPath(cr)
+	rr, err := cr.RunCmd(exec.Command("sudo", crictl, "images", "-o", "json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl images")
+	}
+	imgs, err := parseCRIImages(rr.Stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	rr, err = cr.RunCmd(exec.Command("sudo", crictl, "ps", "-a", "-o", "json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl ps")
+	}
+	used, err := parseCRIContainerImages(rr.Stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return unusedImages(imgs, used), nil
+}
+
 // stopCRIContainers stops containers using crictl
 func stopCRIContainers(cr CommandRunner, ids []string) error {
 	if len(ids) == 0 {
@@ -251,6 +326,39 @@ image-endpoint: unix://{{.Socket}}
 	return nil
 }
 
+// proxyDropIn returns a systemd drop-in setting the proxy environment of a service
+func proxyDropIn(env []string) string {
+	var b strings.Builder
+	b.WriteString("[Service]\n")
+	for _, e := range env {
+		// '%' introduces specifiers in systemd units
+		fmt.Fprintf(&b, "Environment=%q\n", strings.ReplaceAll(e, "%", "%%"))
+	}
+	return b.String()
+}
+
+// generateProxyDropIn passes the proxy environment to a runtime service, or removes it if env is empty.
+// It returns whether the drop-in was written or removed, which only applies once the service restarts.
+func generateProxyDropIn(cr CommandRunner, svc string, env []string) (bool, error) {
+	dropIn := fmt.Sprintf("/etc/systemd/system/%s.service.d/10-minikube-proxy.conf", svc)
+	if len(env) == 0 {
+		if _, err := cr.RunCmd(exec.Command("sudo", "test", "-f", dropIn)); err != nil {
+			return false, nil
+		}
+		if _, err := cr.RunCmd(exec.Command("sudo", "rm", "-f", dropIn)); err != nil {
+			return false, errors.Wrap(err, "remove proxy drop-in")
+		}
+		return true, nil
+	}
+	if _, err := cr.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Dir(dropIn))); err != nil {
+		return false, errors.Wrap(err, "mkdir")
+	}
+	if err := cr.Copy(assets.NewMemoryAsset([]byte(proxyDropIn(env)), path.Dir(dropIn), path.Base(dropIn), "0644")); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // getCRIInfo returns current information
 func getCRIInfo(cr CommandRunner) (map[string]interface{}, error) {
 	args := []string{"crictl", "info"}
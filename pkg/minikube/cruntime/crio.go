@@ -22,10 +22,12 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/blang/semver"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/assets"
@@ -41,6 +43,8 @@ import (
 const (
 	// CRIOConfFile is the path to the CRI-O configuration
 	crioConfigFile = "/etc/crio/crio.conf"
+	// crioMirrorsFile is the path to the registries configuration listing pull-through mirrors
+	crioMirrorsFile = "/etc/containers/registries.conf.d/99-minikube-mirrors.conf"
 )
 
 // CRIO contains CRIO runtime state
@@ -50,6 +54,8 @@ type CRIO struct {
 	ImageRepository   string
 	KubernetesVersion semver.Version
 	Init              sysinit.Manager
+	RegistryMirrors   map[string]string
+	ProxyEnv          []string
 }
 
 // generateCRIOConfig sets up /etc/crio/crio.conf
@@ -154,9 +160,46 @@ func (r *CRIO) Enable(disOthers, _ bool) error {
 	if err := enableIPForwarding(r.Runner); err != nil {
 		return err
 	}
+	proxyChanged, err := generateProxyDropIn(r.Runner, "crio", r.ProxyEnv)
+	if err != nil {
+		return err
+	}
+	if len(r.RegistryMirrors) > 0 {
+		if err := generateCRIOMirrors(r.Runner, r.RegistryMirrors); err != nil {
+			return err
+		}
+	}
+	// a running crio only reads its registries and environment when it starts
+	if len(r.RegistryMirrors) > 0 || proxyChanged {
+		return r.Init.Restart("crio")
+	}
 	return r.Init.Start("crio")
 }
 
+// generateCRIOMirrors sets up pull-through mirrors for registries, falling back to the registries themselves
+func generateCRIOMirrors(cr CommandRunner, mirrors map[string]string) error {
+	registries := []string{}
+	for registry := range mirrors {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	var b strings.Builder
+	for _, registry := range registries {
+		fmt.Fprintf(&b, "[[registry]]\nprefix = %q\nlocation = %q\n\n[[registry.mirror]]\nlocation = %q\ninsecure = true\n\n", registry, registry, mirrors[registry])
+	}
+	if _, err := cr.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Dir(crioMirrorsFile))); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	ma := assets.NewMemoryAsset([]byte(b.String()), path.Dir(crioMirrorsFile), path.Base(crioMirrorsFile), "0644")
+	return cr.Copy(ma)
+}
+
+// Restart restarts CRIO on a host
+func (r *CRIO) Restart() error {
+	return r.Init.Restart("crio")
+}
+
 // Disable idempotently disables CRIO on a host
 func (r *CRIO) Disable() error {
 	return r.Init.ForceStop("crio")
@@ -216,8 +259,42 @@ func (r *CRIO) RemoveImage(name string) error {
 	return removeCRIImage(r.Runner, name)
 }
 
+// UnusedImages returns the images which no container uses
+func (r *CRIO) UnusedImages() ([]ImageInfo, error) {
+	return unusedCRIImages(r.Runner)
+}
+
+// BuildCacheSize returns how many bytes removing the cache of image builds would reclaim,
+// the dangling images left by the builds of podman
+func (r *CRIO) BuildCacheSize() (int64, error) {
+	rr, err := r.Runner.RunCmd(exec.Command("sudo", "podman", "images", "--filter", "dangling=true", "--format", "{{.Size}}"))
+	if err != nil {
+		return 0, errors.Wrap(err, "podman images")
+	}
+	var total int64
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		size, err := units.FromHumanSize(strings.TrimSpace(line))
+		if err != nil {
+			return 0, errors.Wrapf(err, "parsing image size %q", line)
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// PruneBuildCache removes the cache of image builds
+func (r *CRIO) PruneBuildCache() error {
+	if _, err := r.Runner.RunCmd(exec.Command("sudo", "podman", "image", "prune", "-f")); err != nil {
+		return errors.Wrap(err, "podman image prune")
+	}
+	return nil
+}
+
 // BuildImage builds an image into this runtime
-func (r *CRIO) BuildImage(src string, file string, tag string, push bool, env []string, opts []string) error {
+func (r *CRIO) BuildImage(src string, file string, tag string, push bool, env []string, opts []string, platform string, secrets []string) error {
 	klog.Infof("Building image: %s", src)
 	args := []string{"podman", "build"}
 	if file != "" {
@@ -226,6 +303,12 @@ func (r *CRIO) BuildImage(src string, file string, tag string, push bool, env []
 	if tag != "" {
 		args = append(args, "-t", tag)
 	}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+	for _, secret := range secrets {
+		args = append(args, "--secret", secret)
+	}
 	args = append(args, src)
 	for _, opt := range opts {
 		args = append(args, "--"+opt)
@@ -311,8 +394,8 @@ func (r *CRIO) ContainerLogCmd(id string, len int, follow bool) string {
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *CRIO) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u crio -n %d", len)
+func (r *CRIO) SystemLogCmd(len int, follow bool) string {
+	return journalLogCmd("crio", len, follow)
 }
 
 // Preload preloads the container runtime with k8s images
@@ -19,6 +19,7 @@ package cruntime
 
 import (
 	"fmt"
+	"io"
 	"os/exec"
 
 	"github.com/blang/semver"
@@ -67,6 +68,8 @@ type CommandRunner interface {
 	Copy(assets.CopyableFile) error
 	// Remove is a convenience method that runs a command to remove a file
 	Remove(assets.CopyableFile) error
+	// CopyFrom is a convenience method that streams the content of a file to w
+	CopyFrom(src string, w io.Writer) error
 }
 
 // Manager is a common interface for container runtimes
@@ -79,6 +82,8 @@ type Manager interface {
 	Enable(bool, bool) error
 	// Disable idempotently disables this runtime on a host
 	Disable() error
+	// Restart restarts this runtime on a host, so that it reloads its configuration and trusted CAs
+	Restart() error
 	// Active returns whether or not a runtime is active on a host
 	Active() bool
 	// Available returns an error if it is not possible to use this runtime on a host
@@ -98,7 +103,7 @@ type Manager interface {
 	// Pull an image to the runtime from the container registry
 	PullImage(string) error
 	// Build an image idempotently into the runtime on a host
-	BuildImage(string, string, string, bool, []string, []string) error
+	BuildImage(string, string, string, bool, []string, []string, string, []string) error
 	// Save an image from the runtime on a host
 	SaveImage(string, string) error
 
@@ -109,6 +114,12 @@ type Manager interface {
 
 	// RemoveImage remove image based on name
 	RemoveImage(string) error
+	// UnusedImages returns the images which no container uses
+	UnusedImages() ([]ImageInfo, error)
+	// BuildCacheSize returns how many bytes removing the cache of image builds would reclaim
+	BuildCacheSize() (int64, error)
+	// PruneBuildCache removes the cache of image builds
+	PruneBuildCache() error
 
 	// ListContainers returns a list of containers managed by this container runtime
 	ListContainers(ListContainersOptions) ([]string, error)
@@ -122,8 +133,8 @@ type Manager interface {
 	UnpauseContainers([]string) error
 	// ContainerLogCmd returns the command to retrieve the log for a container based on ID
 	ContainerLogCmd(string, int, bool) string
-	// SystemLogCmd returns the command to return the system logs
-	SystemLogCmd(int) string
+	// SystemLogCmd returns the command to return the system logs, optionally following them
+	SystemLogCmd(int, bool) string
 	// Preload preloads the container runtime with k8s images
 	Preload(config.ClusterConfig) error
 	// ImagesPreloaded returns true if all images have been preloaded
@@ -144,6 +155,12 @@ type Config struct {
 	KubernetesVersion semver.Version
 	// InsecureRegistry list of insecure registries
 	InsecureRegistry []string
+	// RegistryMirrors maps registries to the host:port of a pull-through mirror for them
+	RegistryMirrors map[string]string
+	// GPUs is nvidia or amd if the GPUs of the host are passed to the node
+	GPUs string
+	// ProxyEnv is the proxy environment (KEY=VALUE) of the runtime, Docker gets it from the machine provisioner instead
+	ProxyEnv []string
 }
 
 // ListContainersOptions are the options to use for listing containers
@@ -160,6 +177,41 @@ type ListContainersOptions struct {
 type ListImagesOptions struct {
 }
 
+// ImageInfo describes an image of the container runtime
+type ImageInfo struct {
+	// ID is the ID of the image, the digest of its config
+	ID string
+	// Tags are the names of the image
+	Tags []string
+	// Digests are the repository digests of the image
+	Digests []string
+	// Size is the size of the image in bytes
+	Size int64
+}
+
+// Names returns the names to remove the image by, its tags or its ID if it has none
+func (i ImageInfo) Names() []string {
+	if len(i.Tags) > 0 {
+		return i.Tags
+	}
+	return []string{i.ID}
+}
+
+// unusedImages returns the images which are not in use, given the IDs, tags or digests of the images of the containers
+func unusedImages(imgs []ImageInfo, used map[string]bool) []ImageInfo {
+	var unused []ImageInfo
+	for _, img := range imgs {
+		inUse := used[img.ID]
+		for _, name := range append(append([]string{}, img.Tags...), img.Digests...) {
+			inUse = inUse || used[name]
+		}
+		if !inUse {
+			unused = append(unused, img)
+		}
+	}
+	return unused
+}
+
 // ErrContainerRuntimeNotRunning is thrown when container runtime is not running
 var ErrContainerRuntimeNotRunning = errors.New("container runtime is not running")
 
@@ -176,6 +228,8 @@ func New(c Config) (Manager, error) {
 			KubernetesVersion: c.KubernetesVersion,
 			Init:              sm,
 			UseCRI:            (c.Socket != ""), // !dockershim
+			RegistryMirrors:   c.RegistryMirrors,
+			GPUs:              c.GPUs,
 		}, nil
 	case "crio", "cri-o":
 		return &CRIO{
@@ -184,6 +238,8 @@ func New(c Config) (Manager, error) {
 			ImageRepository:   c.ImageRepository,
 			KubernetesVersion: c.KubernetesVersion,
 			Init:              sm,
+			RegistryMirrors:   c.RegistryMirrors,
+			ProxyEnv:          c.ProxyEnv,
 		}, nil
 	case "containerd":
 		return &Containerd{
@@ -193,6 +249,8 @@ func New(c Config) (Manager, error) {
 			KubernetesVersion: c.KubernetesVersion,
 			Init:              sm,
 			InsecureRegistry:  c.InsecureRegistry,
+			RegistryMirrors:   c.RegistryMirrors,
+			ProxyEnv:          c.ProxyEnv,
 		}, nil
 	default:
 		return nil, fmt.Errorf("unknown runtime type: %q", c.Type)
@@ -205,6 +263,15 @@ func ContainerStatusCommand() string {
 	return "sudo `which crictl || echo crictl` ps -a || sudo docker ps -a"
 }
 
+// journalLogCmd returns the command to retrieve the journal of the unit of a container runtime
+func journalLogCmd(unit string, len int, follow bool) string {
+	cmd := fmt.Sprintf("sudo journalctl -u %s -n %d", unit, len)
+	if follow {
+		cmd += " -f"
+	}
+	return cmd
+}
+
 // disableOthers disables all other runtimes except for me.
 func disableOthers(me Manager, cr CommandRunner) error {
 	// valid values returned by manager.Name()
@@ -17,6 +17,7 @@ limitations under the License.
 package cruntime
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -25,12 +26,14 @@ import (
 	"time"
 
 	"github.com/blang/semver"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/docker"
 	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/style"
@@ -64,6 +67,8 @@ type Docker struct {
 	KubernetesVersion semver.Version
 	Init              sysinit.Manager
 	UseCRI            bool
+	RegistryMirrors   map[string]string
+	GPUs              string
 }
 
 // Name is a human readable name for Docker
@@ -128,8 +133,9 @@ func (r *Docker) Enable(disOthers, forceSystemd bool) error {
 		klog.ErrorS(err, "Failed to enable", "service", "docker.socket")
 	}
 
-	if forceSystemd {
-		if err := r.forceSystemd(); err != nil {
+	mirror := r.RegistryMirrors["docker.io"]
+	if forceSystemd || mirror != "" || r.GPUs == constants.GPUNvidia {
+		if err := r.generateDaemonConfig(forceSystemd, mirror); err != nil {
 			return err
 		}
 		return r.Init.Restart("docker")
@@ -244,21 +250,125 @@ func (r *Docker) RemoveImage(name string) error {
 	return nil
 }
 
+// UnusedImages returns the images which no container uses
+func (r *Docker) UnusedImages() ([]ImageInfo, error) {
+	if r.UseCRI {
+		return unusedCRIImages(r.Runner)
+	}
+	rr, err := r.Runner.RunCmd(exec.Command("docker", "images", "--no-trunc", "--format", "{{.ID}}\t{{.Repository}}:{{.Tag}}\t{{.Size}}"))
+	if err != nil {
+		return nil, errors.Wrap(err, "docker images")
+	}
+	imgs, err := parseDockerImages(rr.Stdout.String())
+	if err != nil {
+		return nil, err
+	}
+
+	rr, err = r.Runner.RunCmd(exec.Command("docker", "ps", "-a", "-q", "--no-trunc"))
+	if err != nil {
+		return nil, errors.Wrap(err, "docker ps")
+	}
+	used := map[string]bool{}
+	if ids := strings.Fields(rr.Stdout.String()); len(ids) > 0 {
+		rr, err = r.Runner.RunCmd(exec.Command("docker", append([]string{"inspect", "--format", "{{.Image}}"}, ids...)...))
+		if err != nil {
+			return nil, errors.Wrap(err, "docker inspect")
+		}
+		for _, id := range strings.Fields(rr.Stdout.String()) {
+			used[id] = true
+		}
+	}
+	return unusedImages(imgs, used), nil
+}
+
+// parseDockerImages parses the output of 'docker images --format "{{.ID}}\t{{.Repository}}:{{.Tag}}\t{{.Size}}"'
+func parseDockerImages(output string) ([]ImageInfo, error) {
+	var imgs []ImageInfo
+	byID := map[string]int{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		id, tag := fields[0], fields[1]
+		i, ok := byID[id]
+		if !ok {
+			size, err := units.FromHumanSize(fields[2])
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing the size of %s", id)
+			}
+			imgs = append(imgs, ImageInfo{ID: id, Size: size})
+			i = len(imgs) - 1
+			byID[id] = i
+		}
+		if !strings.Contains(tag, "<none>") {
+			imgs[i].Tags = append(imgs[i].Tags, tag)
+		}
+	}
+	return imgs, nil
+}
+
+// BuildCacheSize returns how many bytes removing the cache of image builds would reclaim
+func (r *Docker) BuildCacheSize() (int64, error) {
+	rr, err := r.Runner.RunCmd(exec.Command("docker", "system", "df", "--format", "{{.Type}}\t{{.Reclaimable}}"))
+	if err != nil {
+		return 0, errors.Wrap(err, "docker system df")
+	}
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) == 2 && fields[0] == "Build Cache" {
+			return parseReclaimable(fields[1])
+		}
+	}
+	return 0, nil
+}
+
+// parseReclaimable parses a reclaimable size as given by 'docker system df', for example "1.2GB (50%)"
+func parseReclaimable(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, " ("); i != -1 {
+		s = s[:i]
+	}
+	size, err := units.FromHumanSize(s)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing reclaimable size %q", s)
+	}
+	return size, nil
+}
+
+// PruneBuildCache removes the cache of image builds
+func (r *Docker) PruneBuildCache() error {
+	if _, err := r.Runner.RunCmd(exec.Command("docker", "builder", "prune", "-f")); err != nil {
+		return errors.Wrap(err, "docker builder prune")
+	}
+	return nil
+}
+
 // BuildImage builds an image into this runtime
-func (r *Docker) BuildImage(src string, file string, tag string, push bool, env []string, opts []string) error {
+func (r *Docker) BuildImage(src string, file string, tag string, push bool, env []string, opts []string, platform string, secrets []string) error {
 	klog.Infof("Building image: %s", src)
-	args := []string{"build"}
+	args := []string{"docker", "build"}
 	if file != "" {
 		args = append(args, "-f", file)
 	}
 	if tag != "" {
 		args = append(args, "-t", tag)
 	}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+	for _, secret := range secrets {
+		args = append(args, "--secret", secret)
+	}
 	args = append(args, src)
 	for _, opt := range opts {
 		args = append(args, "--"+opt)
 	}
-	c := exec.Command("docker", args...)
+	if len(secrets) > 0 {
+		// build secrets are only supported by BuildKit
+		args = append([]string{"env", "DOCKER_BUILDKIT=1"}, args...)
+	}
+	c := exec.Command(args[0], args[1:]...)
 	e := os.Environ()
 	e = append(e, env...)
 	c.Env = e
@@ -426,23 +536,56 @@ func (r *Docker) ContainerLogCmd(id string, len int, follow bool) string {
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *Docker) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u docker -n %d", len)
-}
-
-// ForceSystemd forces the docker daemon to use systemd as cgroup manager
-func (r *Docker) forceSystemd() error {
-	klog.Infof("Forcing docker to use systemd as cgroup manager...")
-	daemonConfig := `{
-"exec-opts": ["native.cgroupdriver=systemd"],
-"log-driver": "json-file",
-"log-opts": {
-	"max-size": "100m"
-},
-"storage-driver": "overlay2"
-}
-`
-	ma := assets.NewMemoryAsset([]byte(daemonConfig), "/etc/docker", "daemon.json", "0644")
+func (r *Docker) SystemLogCmd(len int, follow bool) string {
+	return journalLogCmd("docker", len, follow)
+}
+
+// hasNvidiaRuntime returns whether the NVIDIA container runtime is installed on the node, which older
+// base images lack: docker would not start with it as default runtime
+func (r *Docker) hasNvidiaRuntime() bool {
+	if _, err := r.Runner.RunCmd(exec.Command("which", "nvidia-container-runtime")); err != nil {
+		klog.Warningf("nvidia-container-runtime not found, GPUs will not be available to pods: %v", err)
+		return false
+	}
+	return true
+}
+
+// generateDaemonConfig writes /etc/docker/daemon.json, optionally forcing the docker daemon to use
+// systemd as cgroup manager, pulling Docker Hub images through a mirror and running containers with
+// the NVIDIA container runtime
+func (r *Docker) generateDaemonConfig(forceSystemd bool, mirror string) error {
+	if forceSystemd {
+		klog.Infof("Forcing docker to use systemd as cgroup manager...")
+	}
+	daemonConfig := map[string]interface{}{
+		"log-driver": "json-file",
+		"log-opts": map[string]string{
+			"max-size": "100m",
+		},
+		"storage-driver": "overlay2",
+	}
+	if forceSystemd {
+		daemonConfig["exec-opts"] = []string{"native.cgroupdriver=systemd"}
+	}
+	if mirror != "" {
+		// only Docker Hub can be mirrored by the docker daemon
+		daemonConfig["registry-mirrors"] = []string{"http://" + mirror}
+	}
+	if r.GPUs == constants.GPUNvidia && r.hasNvidiaRuntime() {
+		// the runtime makes the GPUs available to the pods, as the device plugin expects
+		daemonConfig["default-runtime"] = "nvidia"
+		daemonConfig["runtimes"] = map[string]interface{}{
+			"nvidia": map[string]interface{}{
+				"path":        "nvidia-container-runtime",
+				"runtimeArgs": []string{},
+			},
+		}
+	}
+	b, err := json.MarshalIndent(daemonConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	ma := assets.NewMemoryAsset(b, "/etc/docker", "daemon.json", "0644")
 	return r.Runner.Copy(ma)
 }
 
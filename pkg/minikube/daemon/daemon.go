@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package daemon runs minikube commands as detached background processes, and stops them again
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shirou/gopsutil/v3/process"
+	"k8s.io/klog/v2"
+)
+
+// Process is a minikube command running in the background, as persisted in the state file of its owner
+type Process struct {
+	Pid     int
+	Started time.Time
+	LogFile string
+	// Created is the creation time of the process in milliseconds since the epoch, as reported by the OS.
+	// Pids are reused once a process exits, so a process is only the daemon if both its pid and creation time match.
+	Created int64
+}
+
+// createTime returns the creation time of a process in milliseconds since the epoch
+func createTime(pid int) (int64, error) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0, err
+	}
+	return p.CreateTime()
+}
+
+// Running returns whether the daemon is still alive. A process which reused its pid is not the daemon,
+// and neither is the process of a state file which did not record its creation time.
+func (p *Process) Running() bool {
+	if p.Pid <= 0 || p.Created == 0 {
+		return false
+	}
+	created, err := createTime(p.Pid)
+	if err != nil {
+		if err != process.ErrorProcessNotRunning {
+			klog.Warningf("unable to check if %d is running: %v", p.Pid, err)
+		}
+		return false
+	}
+	if created != p.Created {
+		klog.Infof("pid %d was reused by another process, the daemon is not running anymore", p.Pid)
+		return false
+	}
+	return true
+}
+
+// Start runs minikube with args as a detached process logging to logFile, which keeps running after this one exits
+func Start(args []string, logFile string) (*Process, error) {
+	bin, err := os.Executable()
+	if err != nil {
+		return nil, errors.Wrap(err, "locating minikube binary")
+	}
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening log")
+	}
+	defer f.Close()
+
+	cmd := exec.Command(bin, args...)
+	cmd.Stdout = f
+	cmd.Stderr = f
+	cmd.SysProcAttr = detachedProcAttr()
+	klog.Infof("starting daemon: %v", cmd.Args)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &Process{Pid: cmd.Process.Pid, Started: time.Now(), LogFile: logFile}
+	if p.Created, err = createTime(p.Pid); err != nil {
+		return nil, fmt.Errorf("%v exited right away, see %s: %v", cmd.Args, logFile, err)
+	}
+	if err := cmd.Process.Release(); err != nil {
+		klog.Warningf("unable to release process %d: %v", p.Pid, err)
+	}
+	return p, nil
+}
+
+// Stop asks the daemon to exit, and kills it if it is still running after timeout.
+// The identity of the process is checked before it is signaled, so that a process which reused the pid is left alone.
+func (p *Process) Stop(timeout time.Duration) error {
+	if !p.Running() {
+		return nil
+	}
+	proc, err := os.FindProcess(p.Pid)
+	if err != nil {
+		return errors.Wrapf(err, "finding process %d", p.Pid)
+	}
+	klog.Infof("stopping daemon %d", p.Pid)
+	if err := terminate(proc); err != nil {
+		klog.Warningf("unable to terminate %d: %v", p.Pid, err)
+	}
+	deadline := time.Now().Add(timeout)
+	for p.Running() && time.Now().Before(deadline) {
+		time.Sleep(250 * time.Millisecond)
+	}
+	if p.Running() {
+		klog.Warningf("daemon %d did not exit after %s, killing it", p.Pid, timeout)
+		if err := proc.Kill(); err != nil {
+			return errors.Wrapf(err, "killing %d", p.Pid)
+		}
+	}
+	return nil
+}
+
+// Load reads the state file of a daemon into d. It returns false if there is none.
+func Load(path string, d interface{}) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "reading %s", path)
+	}
+	if err := json.Unmarshal(data, d); err != nil {
+		return false, errors.Wrapf(err, "parsing %s", path)
+	}
+	return true, nil
+}
+
+// Save writes the state file of a daemon
+func Save(path string, d interface{}) error {
+	data, err := json.MarshalIndent(d, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// Remove removes the state file of a daemon, if any
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "removing %s", path)
+	}
+	return nil
+}
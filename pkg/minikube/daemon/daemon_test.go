@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunning(t *testing.T) {
+	created, err := createTime(os.Getpid())
+	if err != nil {
+		t.Fatalf("createTime: %v", err)
+	}
+
+	tests := []struct {
+		description string
+		process     Process
+		want        bool
+	}{
+		{"this process", Process{Pid: os.Getpid(), Created: created}, true},
+		{"reused pid", Process{Pid: os.Getpid(), Created: created - 1000}, false},
+		{"no creation time", Process{Pid: os.Getpid()}, false},
+		{"exited", Process{Pid: 12341234, Created: created}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := tc.process.Running(); got != tc.want {
+				t.Errorf("Running() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStopReusedPid(t *testing.T) {
+	created, err := createTime(os.Getpid())
+	if err != nil {
+		t.Fatalf("createTime: %v", err)
+	}
+	// this process has the pid of the daemon but is not it, so must not be signaled
+	p := &Process{Pid: os.Getpid(), Created: created + 1000}
+	if err := p.Stop(time.Second); err != nil {
+		t.Errorf("Stop() = %v", err)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "daemon")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "daemon.json")
+
+	var p Process
+	if found, err := Load(path, &p); err != nil || found {
+		t.Fatalf("Load() without state = %v, %v, want false, nil", found, err)
+	}
+	if err := Save(path, &Process{Pid: 42, Created: 1}); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	if found, err := Load(path, &p); err != nil || !found || p.Pid != 42 || p.Created != 1 {
+		t.Fatalf("Load() = %+v, %v, %v", p, found, err)
+	}
+	if err := Remove(path); err != nil {
+		t.Fatalf("Remove() = %v", err)
+	}
+	if err := Remove(path); err != nil {
+		t.Errorf("Remove() of a removed state = %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// detachedProcess is the DETACHED_PROCESS process creation flag, which is not exported by the syscall package
+const detachedProcess = 0x00000008
+
+// detachedProcAttr starts the daemon without a console, so that it survives the terminal it was started from
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// terminate stops the daemon. Windows processes can not be signaled, so the daemon can not clean up after itself.
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
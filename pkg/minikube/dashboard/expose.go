@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dashboard exposes the Kubernetes dashboard beyond localhost, behind a bearer token and TLS
+package dashboard
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// TokenParam is the query parameter browsers pass the token in, on their first request
+	TokenParam = "token"
+	// tokenCookie is the cookie browsers pass the token in, once authenticated
+	tokenCookie = "minikube-dashboard-token"
+)
+
+// GenerateToken returns a new random bearer token
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "generating token")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Handler returns a handler proxying requests bearing the token to the dashboard served at target, through kubectl proxy.
+// In read-only mode, it only proxies the requests which cannot change the cluster.
+func Handler(target *url.URL, token string, readOnly bool) http.Handler {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(r *http.Request) {
+		director(r)
+		// kubectl proxy only accepts requests to localhost
+		r.Host = target.Host
+		r.Header.Del("Authorization")
+		removeCookie(r, tokenCookie)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t := r.URL.Query().Get(TokenParam); t != "" {
+			if !validToken(t, token) {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: tokenCookie, Value: t, Path: "/", Secure: true, HttpOnly: true, SameSite: http.SameSiteStrictMode})
+			q := r.URL.Query()
+			q.Del(TokenParam)
+			u := *r.URL
+			u.RawQuery = q.Encode()
+			http.Redirect(w, r, u.RequestURI(), http.StatusFound)
+			return
+		}
+		if !authorized(r, token) {
+			http.Error(w, "missing or invalid token: open the URL printed by 'minikube dashboard --expose', or pass the token as a bearer token", http.StatusUnauthorized)
+			return
+		}
+		if readOnly && !readOnlyRequest(r) {
+			klog.Infof("denied %s %s in read-only mode", r.Method, r.URL.Path)
+			http.Error(w, "the dashboard is exposed in read-only mode", http.StatusForbidden)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	})
+}
+
+// validToken returns whether a token is the expected one, in constant time
+func validToken(got string, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// authorized returns whether a request bears the token, as a bearer token or a cookie
+func authorized(r *http.Request, token string) bool {
+	if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		return validToken(strings.TrimPrefix(h, "Bearer "), token)
+	}
+	if c, err := r.Cookie(tokenCookie); err == nil {
+		return validToken(c.Value, token)
+	}
+	return false
+}
+
+// readOnlyRequest returns whether a request cannot change the cluster.
+// The dashboard opens shells in containers over websockets, which are started with GET requests.
+func readOnlyRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+	return !strings.Contains(r.URL.Path, "/sockjs") && !strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// removeCookie removes a cookie from a request
+func removeCookie(r *http.Request, name string) {
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+	for _, c := range cookies {
+		if c.Name != name {
+			r.AddCookie(c)
+		}
+	}
+}
+
+// Certs returns the paths to the TLS certificate and key to serve the dashboard with, generating them when none were given.
+// Generated certificates are signed by a CA kept in the profile directory, which clients can trust once for all.
+func Certs(profile string, address string, certFile string, keyFile string) (string, string, string, error) {
+	if certFile != "" || keyFile != "" {
+		return certFile, keyFile, "", nil
+	}
+
+	dir := filepath.Join(localpath.Profile(profile), "dashboard")
+	caCert := filepath.Join(dir, "ca.crt")
+	caKey := filepath.Join(dir, "ca.key")
+	if _, err := os.Stat(caCert); err != nil {
+		if err := util.GenerateCACert(caCert, caKey, "minikubeDashboardCA"); err != nil {
+			return "", "", "", errors.Wrap(err, "generating CA")
+		}
+	}
+
+	ips, names := Hosts(address)
+	certFile = filepath.Join(dir, "dashboard.crt")
+	keyFile = filepath.Join(dir, "dashboard.key")
+	if err := util.GenerateSignedCert(certFile, keyFile, "minikube-dashboard", ips, names, caCert, caKey); err != nil {
+		return "", "", "", errors.Wrap(err, "generating certificate")
+	}
+	return certFile, keyFile, caCert, nil
+}
+
+// Hosts returns the IPs and names the dashboard is reachable at when listening on an address:
+// all the addresses of the host for an unspecified address, and the address itself otherwise.
+func Hosts(address string) ([]net.IP, []string) {
+	if ip := net.ParseIP(address); ip != nil && !ip.IsUnspecified() {
+		return []net.IP{ip}, nil
+	}
+	if address != "" && net.ParseIP(address) == nil {
+		return nil, []string{address}
+	}
+
+	ips := []net.IP{net.ParseIP("127.0.0.1")}
+	names := []string{"localhost"}
+	if hostname, err := os.Hostname(); err == nil {
+		names = append(names, hostname)
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		klog.Warningf("failed to list the addresses of the host: %v", err)
+		return ips, names
+	}
+	for _, a := range addrs {
+		n, ok := a.(*net.IPNet)
+		if !ok || n.IP.IsLoopback() || n.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		ips = append(ips, n.IP)
+	}
+	return ips, names
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	var got *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}))
+	defer backend.Close()
+	target, err := url.Parse(backend.URL + "/api/v1/namespaces/kubernetes-dashboard/services/http:kubernetes-dashboard:/proxy/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		method      string
+		path        string
+		header      http.Header
+		readOnly    bool
+		want        int
+	}{
+		{"no token", http.MethodGet, "/", nil, false, http.StatusUnauthorized},
+		{"invalid bearer token", http.MethodGet, "/", http.Header{"Authorization": {"Bearer nope"}}, false, http.StatusUnauthorized},
+		{"bearer token", http.MethodGet, "/", http.Header{"Authorization": {"Bearer secret"}}, false, http.StatusOK},
+		{"cookie", http.MethodGet, "/", http.Header{"Cookie": {tokenCookie + "=secret"}}, false, http.StatusOK},
+		{"invalid query token", http.MethodGet, "/?token=nope", nil, false, http.StatusUnauthorized},
+		{"query token", http.MethodGet, "/?token=secret", nil, false, http.StatusFound},
+		{"write", http.MethodPost, "/api/v1/scale", http.Header{"Authorization": {"Bearer secret"}}, false, http.StatusOK},
+		{"read-only read", http.MethodGet, "/api/v1/pod", http.Header{"Authorization": {"Bearer secret"}}, true, http.StatusOK},
+		{"read-only write", http.MethodPost, "/api/v1/scale", http.Header{"Authorization": {"Bearer secret"}}, true, http.StatusForbidden},
+		{"read-only shell", http.MethodGet, "/api/sockjs/info", http.Header{"Authorization": {"Bearer secret"}}, true, http.StatusForbidden},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got = nil
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			for k, v := range tc.header {
+				r.Header[k] = v
+			}
+			w := httptest.NewRecorder()
+			Handler(target, "secret", tc.readOnly).ServeHTTP(w, r)
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.want, w.Body.String())
+			}
+			if tc.want != http.StatusOK {
+				if got != nil {
+					t.Errorf("request was proxied")
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("request was not proxied")
+			}
+			if got.Header.Get("Authorization") != "" {
+				t.Errorf("token was proxied in Authorization header")
+			}
+			if _, err := got.Cookie(tokenCookie); err == nil {
+				t.Errorf("token was proxied in cookie")
+			}
+			if got.Host != target.Host {
+				t.Errorf("Host = %q, want %q", got.Host, target.Host)
+			}
+		})
+	}
+}
+
+func TestHandlerTokenRedirect(t *testing.T) {
+	target, _ := url.Parse("http://127.0.0.1:8001/proxy/")
+	r := httptest.NewRequest(http.MethodGet, "/?token=secret&namespace=default", nil)
+	w := httptest.NewRecorder()
+	Handler(target, "secret", false).ServeHTTP(w, r)
+	if loc := w.Header().Get("Location"); loc != "/?namespace=default" {
+		t.Errorf("Location = %q, want %q", loc, "/?namespace=default")
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != tokenCookie || cookies[0].Value != "secret" || !cookies[0].Secure || !cookies[0].HttpOnly {
+		t.Errorf("unexpected cookies: %v", cookies)
+	}
+}
+
+func TestHosts(t *testing.T) {
+	ips, names := Hosts("192.168.1.10")
+	if len(ips) != 1 || !ips[0].Equal(net.ParseIP("192.168.1.10")) || len(names) != 0 {
+		t.Errorf("Hosts(192.168.1.10) = %v, %v", ips, names)
+	}
+	ips, names = Hosts("dev.example.com")
+	if len(ips) != 0 || len(names) != 1 || names[0] != "dev.example.com" {
+		t.Errorf("Hosts(dev.example.com) = %v, %v", ips, names)
+	}
+	ips, names = Hosts("0.0.0.0")
+	if len(ips) == 0 || !ips[0].Equal(net.ParseIP("127.0.0.1")) || names[0] != "localhost" {
+		t.Errorf("Hosts(0.0.0.0) = %v, %v", ips, names)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/daemon"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// daemonStopTimeout is how long the resolver gets to exit before being killed
+const daemonStopTimeout = 10 * time.Second
+
+// DefaultPort is the port the resolver of a cluster listens on by default
+const DefaultPort = 10053
+
+// Daemon is the persisted state of the resolver of a profile running in the background, see 'minikube dns enable'
+type Daemon struct {
+	daemon.Process
+	Profile string
+	Port    int
+	Domains []string
+}
+
+// daemonPath returns the path to the state file of the resolver of a profile
+func daemonPath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "dns.json")
+}
+
+// DaemonLogFile returns the path to the log file of the resolver of a profile
+func DaemonLogFile(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "dns.log")
+}
+
+// LoadDaemon returns the resolver of a profile, or nil if it was never started
+func LoadDaemon(profile string) (*Daemon, error) {
+	d := &Daemon{}
+	found, err := daemon.Load(daemonPath(profile), d)
+	if err != nil || !found {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DaemonRunning returns whether the resolver of a profile is running
+func DaemonRunning(profile string) bool {
+	d, err := LoadDaemon(profile)
+	if err != nil {
+		klog.Warningf("unable to load resolver of %s: %v", profile, err)
+		return false
+	}
+	return d != nil && d.Running()
+}
+
+// StartDaemon runs 'minikube dns serve' for a profile as a detached process, which resolves
+// the names of the cluster until StopDaemon is called.
+func StartDaemon(profile string, domains []string, port int) (*Daemon, error) {
+	existing, err := LoadDaemon(profile)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Running() {
+		return nil, fmt.Errorf("a resolver is already running for %q with pid %d", profile, existing.Pid)
+	}
+
+	args := []string{"dns", "serve", "--profile", profile, "--port", strconv.Itoa(port), "--alsologtostderr"}
+	for _, d := range domains {
+		args = append(args, "--domain", d)
+	}
+	p, err := daemon.Start(args, DaemonLogFile(profile))
+	if err != nil {
+		return nil, errors.Wrap(err, "starting resolver")
+	}
+	d := &Daemon{Process: *p, Profile: profile, Port: port, Domains: domains}
+	if err := daemon.Save(daemonPath(profile), d); err != nil {
+		return nil, errors.Wrap(err, "saving resolver state")
+	}
+	return d, nil
+}
+
+// StopDaemon stops the resolver of a profile. It returns whether a resolver was found.
+func StopDaemon(profile string) (bool, error) {
+	d, err := LoadDaemon(profile)
+	if err != nil || d == nil {
+		return false, err
+	}
+	if err := d.Stop(daemonStopTimeout); err != nil {
+		return true, errors.Wrap(err, "stopping resolver")
+	}
+	return true, daemon.Remove(daemonPath(profile))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// resolverDir holds the per domain resolver configuration files, see resolver(5)
+const resolverDir = "/etc/resolver"
+
+// resolverConf returns the resolver configuration of a domain sending its queries to the resolver of a cluster
+func resolverConf(profile string, port int) string {
+	return fmt.Sprintf("%s\nnameserver 127.0.0.1\nport %d\n", marker(profile), port)
+}
+
+// ConfigureHost routes the queries of the host for the names of a cluster to its resolver listening on port,
+// through a file in /etc/resolver for each of its domains
+func ConfigureHost(profile string, domains []string, port int) error {
+	for _, d := range append([]string{Zone(profile)}, domains...) {
+		path := filepath.Join(resolverDir, d)
+		data, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "reading %s", path)
+		}
+		if err == nil && !strings.HasPrefix(string(data), "# Added by minikube") {
+			return fmt.Errorf("%s was not created by minikube, remove it to resolve %s", path, d)
+		}
+		if err == nil && !strings.HasPrefix(string(data), marker(profile)) {
+			klog.Warningf("%s resolved the %s domain by another cluster, replacing it", path, d)
+		}
+		if err := sudoWrite(path, []byte(resolverConf(profile, port))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnconfigureHost removes the files in /etc/resolver written by ConfigureHost for a cluster
+func UnconfigureHost(profile string) error {
+	fs, err := ioutil.ReadDir(resolverDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", resolverDir)
+	}
+	for _, f := range fs {
+		path := filepath.Join(resolverDir, f.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			klog.Warningf("unable to read %s: %v", path, err)
+			continue
+		}
+		if !strings.HasPrefix(string(data), marker(profile)) {
+			continue
+		}
+		if err := sudoRemove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
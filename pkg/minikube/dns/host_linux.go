@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// resolvedDir holds the drop-in configuration files of systemd-resolved
+const resolvedDir = "/etc/systemd/resolved.conf.d"
+
+// resolvedPath returns the path to the systemd-resolved configuration of a cluster
+func resolvedPath(profile string) string {
+	return filepath.Join(resolvedDir, fmt.Sprintf("minikube-%s.conf", profile))
+}
+
+// resolvedConf returns the systemd-resolved configuration routing the queries for the names of a cluster to its resolver
+func resolvedConf(profile string, domains []string, port int) string {
+	var routes []string
+	for _, d := range append([]string{Zone(profile)}, domains...) {
+		routes = append(routes, "~"+d)
+	}
+	return fmt.Sprintf("%s\n[Resolve]\nDNS=127.0.0.1:%d\nDomains=%s\n", marker(profile), port, strings.Join(routes, " "))
+}
+
+// ConfigureHost routes the queries of the host for the names of a cluster to its resolver listening on port,
+// through a systemd-resolved drop-in. Servers with a port require systemd 246 or later.
+func ConfigureHost(profile string, domains []string, port int) error {
+	if err := exec.Command("systemctl", "is-active", "--quiet", "systemd-resolved").Run(); err != nil {
+		return fmt.Errorf("systemd-resolved is not running, configure your resolver to forward %s to 127.0.0.1:%d", strings.Join(append([]string{Zone(profile)}, domains...), ", "), port)
+	}
+	if err := sudoWrite(resolvedPath(profile), []byte(resolvedConf(profile, domains, port))); err != nil {
+		return err
+	}
+	return restartResolved()
+}
+
+// UnconfigureHost removes the routing of the queries for the names of a cluster set up by ConfigureHost
+func UnconfigureHost(profile string) error {
+	if _, err := os.Stat(resolvedPath(profile)); os.IsNotExist(err) {
+		return nil
+	}
+	if err := sudoRemove(resolvedPath(profile)); err != nil {
+		return err
+	}
+	return restartResolved()
+}
+
+// restartResolved makes systemd-resolved pick up changes to its configuration
+func restartResolved() error {
+	if out, err := exec.Command("sudo", "systemctl", "restart", "systemd-resolved").CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "restarting systemd-resolved: %s", out)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// +build !darwin,!linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// ConfigureHost is not supported on this operating system: its resolver has to be configured by hand
+func ConfigureHost(profile string, domains []string, port int) error {
+	return fmt.Errorf("configuring the resolver of %s is not supported, forward the queries for %s to 127.0.0.1:%d", runtime.GOOS, Zone(profile), port)
+}
+
+// UnconfigureHost does nothing, as ConfigureHost is not supported on this operating system
+func UnconfigureHost(profile string) error {
+	return nil
+}
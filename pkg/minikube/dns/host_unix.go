@@ -0,0 +1,57 @@
+// +build darwin linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// marker starts the host resolver configuration files written for a cluster, so that they can be told apart
+func marker(profile string) string {
+	return fmt.Sprintf("# Added by minikube for the %q cluster, removed by 'minikube dns disable'", profile)
+}
+
+// sudoWrite writes a root owned file
+func sudoWrite(path string, data []byte) error {
+	if out, err := exec.Command("sudo", "mkdir", "-p", filepath.Dir(path)).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "creating %s: %s", filepath.Dir(path), out)
+	}
+	cmd := exec.Command("sudo", "tee", path)
+	cmd.Stdin = bytes.NewReader(data)
+	klog.Infof("writing %s", path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "writing %s: %s", path, out)
+	}
+	return nil
+}
+
+// sudoRemove removes a root owned file
+func sudoRemove(path string) error {
+	klog.Infof("removing %s", path)
+	if out, err := exec.Command("sudo", "rm", "-f", path).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "removing %s: %s", path, out)
+	}
+	return nil
+}
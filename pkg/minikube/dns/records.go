@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dns resolves the hostnames of the ingresses and LoadBalancer services of a cluster on the host
+package dns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+)
+
+// DefaultDomains are the domains of the ingress hosts resolved by default
+var DefaultDomains = []string{"test"}
+
+// Zone returns the domain under which the names of a cluster resolve
+func Zone(profile string) string {
+	return profile + ".minikube"
+}
+
+// Records maps fully qualified hostnames, possibly starting with a "*." wildcard, to the IP they resolve to
+type Records map[string]net.IP
+
+// NewRecords returns the records of a cluster:
+// - the hosts of its ingresses which are in one of domains, resolving to the ingress IP, or to nodeIP until it has one
+// - <service>.<namespace>.<profile>.minikube for its LoadBalancer services with an external IP, see 'minikube tunnel'
+func NewRecords(profile string, nodeIP net.IP, domains []string, ingresses []networking.Ingress, services []core.Service) Records {
+	zone := Zone(profile)
+	rs := Records{}
+	for _, ing := range ingresses {
+		ip := nodeIP
+		if lb := ing.Status.LoadBalancer.Ingress; len(lb) > 0 && net.ParseIP(lb[0].IP) != nil {
+			ip = net.ParseIP(lb[0].IP)
+		}
+		for _, rule := range ing.Spec.Rules {
+			host := strings.ToLower(rule.Host)
+			if host == "" || !(InZone(host, zone) || inDomains(host, domains)) {
+				continue
+			}
+			rs[host] = ip
+		}
+	}
+	for _, svc := range services {
+		if svc.Spec.Type != core.ServiceTypeLoadBalancer || len(svc.Status.LoadBalancer.Ingress) == 0 {
+			continue
+		}
+		ip := net.ParseIP(svc.Status.LoadBalancer.Ingress[0].IP)
+		if ip == nil {
+			continue
+		}
+		rs[strings.ToLower(fmt.Sprintf("%s.%s.%s", svc.Name, svc.Namespace, zone))] = ip
+	}
+	return rs
+}
+
+// Lookup returns the IP a hostname resolves to. Names without a record fall back to wildcard records, then
+// names in the zone of the cluster resolve to fallback, so that ingresses can be reached under any name in it.
+func (rs Records) Lookup(name, zone string, fallback net.IP) (net.IP, bool) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if ip, ok := rs[name]; ok {
+		return ip, true
+	}
+	for labels := strings.Split(name, ".")[1:]; len(labels) > 0; labels = labels[1:] {
+		if ip, ok := rs["*."+strings.Join(labels, ".")]; ok {
+			return ip, true
+		}
+	}
+	if InZone(name, zone) && fallback != nil {
+		return fallback, true
+	}
+	return nil, false
+}
+
+// InZone returns whether a hostname is a domain or one of its subdomains
+func InZone(name, domain string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	domain = strings.ToLower(strings.Trim(domain, "."))
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// inDomains returns whether a hostname is in one of domains
+func inDomains(name string, domains []string) bool {
+	for _, d := range domains {
+		if InZone(name, d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ingress(ip string, hosts ...string) networking.Ingress {
+	ing := networking.Ingress{}
+	for _, h := range hosts {
+		ing.Spec.Rules = append(ing.Spec.Rules, networking.IngressRule{Host: h})
+	}
+	if ip != "" {
+		ing.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: ip}}
+	}
+	return ing
+}
+
+func service(name, ns string, typ core.ServiceType, ip string) core.Service {
+	svc := core.Service{ObjectMeta: meta.ObjectMeta{Name: name, Namespace: ns}, Spec: core.ServiceSpec{Type: typ}}
+	if ip != "" {
+		svc.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: ip}}
+	}
+	return svc
+}
+
+func TestNewRecords(t *testing.T) {
+	nodeIP := net.ParseIP("192.168.49.2")
+	ings := []networking.Ingress{
+		ingress("", "hello-john.test", "", "Hello-Jane.TEST", "example.com"),
+		ingress("10.0.0.5", "api.test", "*.apps.test", "web.p1.minikube"),
+	}
+	svcs := []core.Service{
+		service("db", "default", core.ServiceTypeLoadBalancer, "10.96.0.7"),
+		service("pending", "default", core.ServiceTypeLoadBalancer, ""),
+		service("internal", "default", core.ServiceTypeClusterIP, ""),
+	}
+	want := Records{
+		"hello-john.test":        nodeIP,
+		"hello-jane.test":        nodeIP,
+		"api.test":               net.ParseIP("10.0.0.5"),
+		"*.apps.test":            net.ParseIP("10.0.0.5"),
+		"web.p1.minikube":        net.ParseIP("10.0.0.5"),
+		"db.default.p1.minikube": net.ParseIP("10.96.0.7"),
+	}
+	if got := NewRecords("p1", nodeIP, []string{"test"}, ings, svcs); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewRecords() = %v, want %v", got, want)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	nodeIP := net.ParseIP("192.168.49.2")
+	rs := Records{
+		"api.test":    net.ParseIP("10.0.0.5"),
+		"*.apps.test": net.ParseIP("10.0.0.6"),
+	}
+	tests := []struct {
+		name  string
+		want  net.IP
+		found bool
+	}{
+		{"api.test.", net.ParseIP("10.0.0.5"), true},
+		{"API.test", net.ParseIP("10.0.0.5"), true},
+		{"shop.apps.test.", net.ParseIP("10.0.0.6"), true},
+		{"a.shop.apps.test.", net.ParseIP("10.0.0.6"), true},
+		{"apps.test.", nil, false},
+		{"missing.test.", nil, false},
+		{"p1.minikube.", nodeIP, true},
+		{"anything.p1.minikube.", nodeIP, true},
+		{"p2.minikube.", nil, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, found := rs.Lookup(tc.name, Zone("p1"), nodeIP)
+			if found != tc.found || !got.Equal(tc.want) {
+				t.Errorf("Lookup(%q) = %v, %v, want %v, %v", tc.name, got, found, tc.want, tc.found)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+)
+
+// refreshInterval is how often the records are refreshed from the cluster
+const refreshInterval = 5 * time.Second
+
+// Run serves the names of a cluster on 127.0.0.1:port until ctx is done
+func Run(ctx context.Context, profile string, domains []string, port int) error {
+	conn, err := net.ListenPacket("udp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return errors.Wrap(err, "listening for dns queries")
+	}
+	s := NewServer(profile, domains)
+	go func() {
+		for {
+			if err := refresh(ctx, s, profile, domains); err != nil {
+				klog.Warningf("unable to refresh the records of %s: %v", profile, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(refreshInterval):
+			}
+		}
+	}()
+	klog.Infof("serving the names of %s on %s", profile, conn.LocalAddr())
+	return s.Serve(ctx, conn)
+}
+
+// refresh replaces the records of a server with the current ones of the cluster
+func refresh(ctx context.Context, s *Server, profile string, domains []string) error {
+	cc, err := config.Load(profile)
+	if err != nil {
+		return errors.Wrap(err, "loading profile")
+	}
+	nodeIP, err := NodeIP(cc)
+	if err != nil {
+		return err
+	}
+	client, err := kapi.Client(profile)
+	if err != nil {
+		return errors.Wrap(err, "kubernetes client")
+	}
+	ings, err := client.NetworkingV1().Ingresses(meta.NamespaceAll).List(ctx, meta.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing ingresses")
+	}
+	svcs, err := client.CoreV1().Services(meta.NamespaceAll).List(ctx, meta.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing services")
+	}
+	rs := NewRecords(profile, nodeIP, domains, ings.Items, svcs.Items)
+	klog.V(1).Infof("records of %s: %v", profile, rs)
+	s.SetRecords(rs, nodeIP)
+	return nil
+}
+
+// NodeIP returns the IP the ingresses of a cluster are reachable on from the host
+func NodeIP(cc *config.ClusterConfig) (net.IP, error) {
+	// the container of the node is only reachable from the host on linux, elsewhere ingresses are reached through 'minikube tunnel'
+	if driver.IsKIC(cc.Driver) && runtime.GOOS != "linux" {
+		return net.ParseIP("127.0.0.1"), nil
+	}
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting control plane")
+	}
+	ip := net.ParseIP(cp.IP)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip %q for %s", cp.IP, cc.Name)
+	}
+	return ip, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/dns/dnsmessage"
+	"k8s.io/klog/v2"
+)
+
+// ttl is how long, in seconds, resolvers may cache an answer: records change as ingresses and services do
+const ttl = 5
+
+// Server answers the DNS queries for the zone of a cluster and the domains of its ingress hosts
+type Server struct {
+	zone    string
+	domains []string
+
+	mu      sync.RWMutex
+	records Records
+	nodeIP  net.IP
+}
+
+// NewServer returns a server for the names of a cluster, without any record until SetRecords is called
+func NewServer(profile string, domains []string) *Server {
+	return &Server{zone: Zone(profile), domains: domains, records: Records{}}
+}
+
+// SetRecords replaces the records served, nodeIP is the IP of the names in the zone of the cluster without a record
+func (s *Server) SetRecords(rs Records, nodeIP net.IP) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = rs
+	s.nodeIP = nodeIP
+}
+
+// Serve answers the queries received on conn until ctx is done
+func (s *Server) Serve(ctx context.Context, conn net.PacketConn) error {
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "reading query")
+		}
+		resp, err := s.answer(buf[:n])
+		if err != nil {
+			klog.Warningf("invalid query from %s: %v", addr, err)
+			continue
+		}
+		if _, err := conn.WriteTo(resp, addr); err != nil {
+			klog.Warningf("unable to answer %s: %v", addr, err)
+		}
+	}
+}
+
+// answer returns the response to a query
+func (s *Server) answer(query []byte) ([]byte, error) {
+	var p dnsmessage.Parser
+	h, err := p.Start(query)
+	if err != nil {
+		return nil, err
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	name := q.Name.String()
+	rh := dnsmessage.Header{ID: h.ID, Response: true, OpCode: h.OpCode, RecursionDesired: h.RecursionDesired, RCode: dnsmessage.RCodeSuccess}
+	s.mu.RLock()
+	ip, found := s.records.Lookup(name, s.zone, s.nodeIP)
+	s.mu.RUnlock()
+	switch {
+	case !InZone(name, s.zone) && !inDomains(name, s.domains):
+		rh.RCode = dnsmessage.RCodeRefused
+	case !found:
+		rh.Authoritative = true
+		rh.RCode = dnsmessage.RCodeNameError
+	default:
+		rh.Authoritative = true
+	}
+
+	b := dnsmessage.NewBuilder(nil, rh)
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(q); err != nil {
+		return nil, err
+	}
+	// other types than A get an empty answer, so that resolvers fall back to the A record
+	if ip4 := ip.To4(); found && ip4 != nil && q.Type == dnsmessage.TypeA && q.Class == dnsmessage.ClassINET {
+		if err := b.StartAnswers(); err != nil {
+			return nil, err
+		}
+		a := dnsmessage.AResource{}
+		copy(a.A[:], ip4)
+		if err := b.AResource(dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: ttl}, a); err != nil {
+			return nil, err
+		}
+	}
+	return b.Finish()
+}
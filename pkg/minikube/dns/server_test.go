@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func query(t *testing.T, name string, typ dnsmessage.Type) []byte {
+	t.Helper()
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: 42, RecursionDesired: true})
+	if err := b.StartQuestions(); err != nil {
+		t.Fatalf("start questions: %v", err)
+	}
+	if err := b.Question(dnsmessage.Question{Name: dnsmessage.MustNewName(name), Type: typ, Class: dnsmessage.ClassINET}); err != nil {
+		t.Fatalf("question: %v", err)
+	}
+	q, err := b.Finish()
+	if err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	return q
+}
+
+func TestAnswer(t *testing.T) {
+	s := NewServer("p1", []string{"test"})
+	s.SetRecords(Records{"api.test": net.ParseIP("10.0.0.5")}, net.ParseIP("192.168.49.2"))
+
+	tests := []struct {
+		name  string
+		typ   dnsmessage.Type
+		rcode dnsmessage.RCode
+		want  string
+	}{
+		{"api.test.", dnsmessage.TypeA, dnsmessage.RCodeSuccess, "10.0.0.5"},
+		{"web.p1.minikube.", dnsmessage.TypeA, dnsmessage.RCodeSuccess, "192.168.49.2"},
+		{"api.test.", dnsmessage.TypeAAAA, dnsmessage.RCodeSuccess, ""},
+		{"missing.test.", dnsmessage.TypeA, dnsmessage.RCodeNameError, ""},
+		{"example.com.", dnsmessage.TypeA, dnsmessage.RCodeRefused, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name+tc.typ.String(), func(t *testing.T) {
+			resp, err := s.answer(query(t, tc.name, tc.typ))
+			if err != nil {
+				t.Fatalf("answer: %v", err)
+			}
+			var m dnsmessage.Message
+			if err := m.Unpack(resp); err != nil {
+				t.Fatalf("unpack: %v", err)
+			}
+			if m.Header.ID != 42 || !m.Header.Response {
+				t.Errorf("header = %+v, want a response to query 42", m.Header)
+			}
+			if m.Header.RCode != tc.rcode {
+				t.Errorf("rcode = %v, want %v", m.Header.RCode, tc.rcode)
+			}
+			got := ""
+			for _, a := range m.Answers {
+				if r, ok := a.Body.(*dnsmessage.AResource); ok {
+					got = net.IP(r.A[:]).String()
+				}
+			}
+			if got != tc.want {
+				t.Errorf("answer = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -56,6 +56,8 @@ const (
 	HyperV = "hyperv"
 	// Parallels driver
 	Parallels = "parallels"
+	// MicroVM driver, QEMU with the microvm machine type and direct kernel boot
+	MicroVM = "microvm"
 
 	// AliasKVM is driver name alias for kvm2
 	AliasKVM = "kvm"
@@ -184,6 +186,10 @@ func NeedsPortForward(name string) bool {
 	if oci.IsExternalDaemonHost(name) {
 		return true
 	}
+	// the container network of rootless podman is not reachable from the host
+	if name == Podman && oci.IsRootlessForced() {
+		return true
+	}
 	// Docker for Desktop
 	return runtime.GOOS == "darwin" || runtime.GOOS == "windows" || detect.IsMicrosoftWSL()
 }
@@ -25,6 +25,7 @@ var supportedDrivers = []string{
 	VirtualBox,
 	VMwareFusion,
 	KVM2,
+	MicroVM,
 	VMware,
 	None,
 	Docker,
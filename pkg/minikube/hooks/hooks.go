@@ -0,0 +1,206 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hooks runs user-registered executables and webhooks on cluster lifecycle events
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// Event is a cluster lifecycle event hooks can be registered for
+type Event string
+
+const (
+	// PreStart happens before a cluster is created or started
+	PreStart Event = "pre-start"
+	// PostStart happens once a cluster is started and kubectl is configured
+	PostStart Event = "post-start"
+	// PreStop happens before a cluster is stopped
+	PreStop Event = "pre-stop"
+	// PostDelete happens once a cluster is deleted
+	PostDelete Event = "post-delete"
+	// NodeAdded happens once a node is added to a cluster
+	NodeAdded Event = "node-added"
+	// AddonEnabled happens once an addon is enabled
+	AddonEnabled Event = "addon-enabled"
+)
+
+// Events are all the events hooks can be registered for
+var Events = []Event{PreStart, PostStart, PreStop, PostDelete, NodeAdded, AddonEnabled}
+
+var (
+	// Timeout is how long a hook may run before it is killed
+	Timeout = 5 * time.Minute
+	// webhookTimeout is how long a webhook may take to answer
+	webhookTimeout = 30 * time.Second
+)
+
+// Payload describes the event a hook runs for, it is the JSON body of webhooks and the standard input of executables
+type Payload struct {
+	Event   Event     `json:"event"`
+	Profile string    `json:"profile"`
+	Node    string    `json:"node,omitempty"`
+	Addon   string    `json:"addon,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// ConfigKey returns the 'minikube config' property registering a hook for an event
+func ConfigKey(e Event) string {
+	return "hooks." + string(e)
+}
+
+// Dir returns the directory the executables run on an event are placed in
+func Dir(e Event) string {
+	return filepath.Join(localpath.HooksDir(), string(e))
+}
+
+// IsWebhook returns whether a hook is a URL to post the event to, rather than an executable
+func IsWebhook(hook string) bool {
+	return strings.HasPrefix(hook, "http://") || strings.HasPrefix(hook, "https://")
+}
+
+// Hooks returns the hooks registered for an event: the one set with 'minikube config', then the executables of its hooks.d directory by name
+func Hooks(e Event) ([]string, error) {
+	hooks := []string{}
+	cc, err := config.ReadConfig(localpath.ConfigFile())
+	if err != nil {
+		return nil, errors.Wrap(err, "read config")
+	}
+	if h, ok := cc[ConfigKey(e)].(string); ok && h != "" {
+		hooks = append(hooks, h)
+	}
+
+	files, err := ioutil.ReadDir(Dir(e))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hooks, nil
+		}
+		return nil, errors.Wrapf(err, "read %s", Dir(e))
+	}
+	names := []string{}
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		if runtime.GOOS != "windows" && f.Mode()&0111 == 0 {
+			klog.Warningf("skipping %s hook %s: not executable", e, f.Name())
+			continue
+		}
+		names = append(names, filepath.Join(Dir(e), f.Name()))
+	}
+	sort.Strings(names)
+	return append(hooks, names...), nil
+}
+
+// Run runs the hooks registered for an event one after the other, stopping at the first failure
+func Run(p Payload) error {
+	if p.Time.IsZero() {
+		p.Time = time.Now()
+	}
+	hooks, err := Hooks(p.Event)
+	if err != nil {
+		return err
+	}
+	for _, h := range hooks {
+		klog.Infof("running %s hook %s for %q", p.Event, h, p.Profile)
+		if IsWebhook(h) {
+			err = post(h, p)
+		} else {
+			err = execute(h, p)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "%s hook %s", p.Event, h)
+		}
+	}
+	return nil
+}
+
+// Notify runs the hooks registered for an event, warning rather than failing if one of them does
+func Notify(p Payload) {
+	if err := Run(p); err != nil {
+		out.WarningT("Failed to run {{.event}} hooks: {{.error}}", out.V{"event": p.Event, "error": err})
+	}
+}
+
+// env returns the environment variables describing an event to executables
+func env(p Payload) []string {
+	return []string{
+		"MINIKUBE_HOOK_EVENT=" + string(p.Event),
+		"MINIKUBE_PROFILE=" + p.Profile,
+		"MINIKUBE_NODE=" + p.Node,
+		"MINIKUBE_ADDON=" + p.Addon,
+	}
+}
+
+// execute runs an executable hook, with the payload on its standard input
+func execute(path string, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return errors.Wrap(err, "marshal payload")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = append(os.Environ(), env(p)...)
+	cmd.Stdin = bytes.NewReader(body)
+	output, err := cmd.CombinedOutput()
+	klog.Infof("%s hook %s output:\n%s", p.Event, path, output)
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", Timeout)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "output: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// post sends the payload of a webhook hook
+func post(url string, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return errors.Wrap(err, "marshal payload")
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "post")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return nil
+}
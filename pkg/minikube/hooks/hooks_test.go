@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func setupHome(t *testing.T) func() {
+	tmp, err := ioutil.TempDir("", "hooks")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	old := os.Getenv(localpath.MinikubeHome)
+	os.Setenv(localpath.MinikubeHome, tmp)
+	if err := os.MkdirAll(filepath.Dir(localpath.ConfigFile()), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	return func() {
+		os.Setenv(localpath.MinikubeHome, old)
+		os.RemoveAll(tmp)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	defer setupHome(t)()
+
+	hooks, err := Hooks(PostStart)
+	if err != nil || len(hooks) != 0 {
+		t.Fatalf("Hooks() without any registered = %v, %v, want none", hooks, err)
+	}
+
+	if err := config.WriteConfig(localpath.ConfigFile(), config.MinikubeConfig{ConfigKey(PostStart): "https://example.com/hook"}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := os.MkdirAll(Dir(PostStart), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for name, mode := range map[string]os.FileMode{
+		"20-notify":    0755,
+		"10-load":      0755,
+		".hidden":      0755,
+		"README":       0644,
+		"30-unrelated": 0755,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(Dir(PostStart), name), []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(Dir(PostStart), "subdir"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	hooks, err = Hooks(PostStart)
+	if err != nil {
+		t.Fatalf("Hooks: %v", err)
+	}
+	want := []string{
+		"https://example.com/hook",
+		filepath.Join(Dir(PostStart), "10-load"),
+		filepath.Join(Dir(PostStart), "20-notify"),
+		filepath.Join(Dir(PostStart), "30-unrelated"),
+	}
+	if runtime.GOOS == "windows" {
+		// files have no executable bit on windows
+		want = append(want, filepath.Join(Dir(PostStart), "README"))
+	}
+	if !reflect.DeepEqual(hooks, want) {
+		t.Errorf("Hooks() = %v, want %v", hooks, want)
+	}
+
+	hooks, err = Hooks(PreStop)
+	if err != nil || len(hooks) != 0 {
+		t.Errorf("Hooks(%s) = %v, %v, want none", PreStop, hooks, err)
+	}
+}
+
+func TestRunWebhook(t *testing.T) {
+	defer setupHome(t)()
+
+	var got Payload
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	if err := config.WriteConfig(localpath.ConfigFile(), config.MinikubeConfig{ConfigKey(AddonEnabled): srv.URL}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := Run(Payload{Event: AddonEnabled, Profile: "p1", Addon: "dashboard"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got.Event != AddonEnabled || got.Profile != "p1" || got.Addon != "dashboard" || got.Time.IsZero() {
+		t.Errorf("webhook received %+v", got)
+	}
+
+	status = http.StatusInternalServerError
+	if err := Run(Payload{Event: AddonEnabled, Profile: "p1", Addon: "dashboard"}); err == nil {
+		t.Errorf("Run() with a failing webhook succeeded, want error")
+	}
+}
+
+func TestRunExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	defer setupHome(t)()
+
+	if err := os.MkdirAll(Dir(NodeAdded), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	result := filepath.Join(localpath.MiniPath(), "result")
+	script := "#!/bin/sh\necho \"$MINIKUBE_HOOK_EVENT $MINIKUBE_PROFILE $MINIKUBE_NODE\" > " + result + "\n"
+	if err := ioutil.WriteFile(filepath.Join(Dir(NodeAdded), "10-record"), []byte(script), 0755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := Run(Payload{Event: NodeAdded, Profile: "p1", Node: "m02"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	b, err := ioutil.ReadFile(result)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	if got, want := string(b), "node-added p1 m02\n"; got != want {
+		t.Errorf("hook recorded %q, want %q", got, want)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(Dir(NodeAdded), "20-fail"), []byte("#!/bin/sh\necho boom\nexit 1\n"), 0755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := Run(Payload{Event: NodeAdded, Profile: "p1", Node: "m02"}); err == nil {
+		t.Errorf("Run() with a failing hook succeeded, want error")
+	}
+}
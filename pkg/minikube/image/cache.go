@@ -17,22 +17,16 @@ limitations under the License.
 package image
 
 import (
-	"io/ioutil"
 	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
-	"github.com/google/go-containerregistry/pkg/v1/tarball"
-	"github.com/juju/mutex"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/constants"
-	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/out"
-	"k8s.io/minikube/pkg/util/lock"
 )
 
 type cacheError struct {
@@ -46,39 +40,42 @@ func (f *cacheError) Error() string {
 // errCacheImageDoesntExist is thrown when image that user is trying to add does not exist
 var errCacheImageDoesntExist = &cacheError{errors.New("the image you are trying to add does not exist")}
 
-// DeleteFromCacheDir deletes tar files stored in cache dir
+// DeleteFromCacheDir deletes images from the cache dir
 func DeleteFromCacheDir(images []string) error {
 	for _, image := range images {
-		path := filepath.Join(constants.ImageCacheDir, image)
-		path = localpath.SanitizeCacheDir(path)
-		klog.Infoln("Deleting image in cache at ", path)
-		if err := os.Remove(path); err != nil {
-			return err
+		path := legacyPath(constants.ImageCacheDir, image)
+		if _, err := os.Stat(path); err == nil {
+			klog.Infoln("Deleting image in cache at ", path)
+			if err := os.Remove(path); err != nil {
+				return err
+			}
 		}
 	}
+	if err := removeFromStore(constants.ImageCacheDir, images); err != nil {
+		return err
+	}
 	return cleanImageCacheDir()
 }
 
 // SaveToDir will cache images on the host
 //
-// The cache directory currently caches images using the imagename_tag
-// For example, k8s.gcr.io/kube-addon-manager:v6.5 would be
-// stored at $CACHE_DIR/k8s.gcr.io/kube-addon-manager_v6.5
-func SaveToDir(images []string, cacheDir string, overwrite bool) error {
+// The images are stored in an OCI image layout in the cache directory, where
+// the layers shared by several images are only stored once. They are cached for
+// the given platforms, or for the platform of the host if none is given.
+func SaveToDir(images []string, cacheDir string, overwrite bool, platforms ...v1.Platform) error {
+	platforms = uniquePlatforms(platforms)
 	var g errgroup.Group
 	for _, image := range images {
 		image := image
 		g.Go(func() error {
-			dst := filepath.Join(cacheDir, image)
-			dst = localpath.SanitizeCacheDir(dst)
-			if err := saveToTarFile(image, dst, overwrite); err != nil {
+			if err := saveToStore(image, cacheDir, overwrite, platforms); err != nil {
 				if err == errCacheImageDoesntExist {
 					out.WarningT("The image '{{.imageName}}' was not found; unable to add it to cache.", out.V{"imageName": image})
 					return nil
 				}
-				return errors.Wrapf(err, "caching image %q", dst)
+				return errors.Wrapf(err, "caching image %q", image)
 			}
-			klog.Infof("save to tar file %s -> %s succeeded", image, dst)
+			klog.Infof("save to image store %s succeeded", image)
 			return nil
 		})
 	}
@@ -89,36 +86,20 @@ func SaveToDir(images []string, cacheDir string, overwrite bool) error {
 	return nil
 }
 
-// saveToTarFile caches an image
-func saveToTarFile(iname, rawDest string, overwrite bool) error {
+// saveToStore caches an image for platforms
+func saveToStore(iname, cacheDir string, overwrite bool, platforms []v1.Platform) error {
 	iname = normalizeTagName(iname)
 	start := time.Now()
 	defer func() {
-		klog.Infof("cache image %q -> %q took %s", iname, rawDest, time.Since(start))
+		klog.Infof("cache image %q took %s", iname, time.Since(start))
 	}()
 
-	// OS-specific mangling of destination path
-	dst, err := localpath.DstPath(rawDest)
-	if err != nil {
-		return errors.Wrap(err, "getting destination path")
-	}
-
-	spec := lock.PathMutexSpec(dst)
-	spec.Timeout = 10 * time.Minute
-	klog.Infof("acquiring lock: %+v", spec)
-	releaser, err := mutex.Acquire(spec)
-	if err != nil {
-		return errors.Wrapf(err, "unable to acquire lock for %+v", spec)
-	}
-	defer releaser.Release()
-
-	if _, err := os.Stat(dst); !overwrite && err == nil {
-		klog.Infof("%s exists", dst)
-		return nil
-	}
-
-	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
-		return errors.Wrapf(err, "making cache image directory: %s", dst)
+	if !overwrite {
+		platforms = uncachedPlatforms(cacheDir, iname, platforms)
+		if len(platforms) == 0 {
+			klog.Infof("%s exists", iname)
+			return nil
+		}
 	}
 
 	// use given short name
@@ -130,12 +111,15 @@ func saveToTarFile(iname, rawDest string, overwrite bool) error {
 		return errors.Wrapf(err, "nil reference for %s", iname)
 	}
 
-	img, cname, err := retrieveImage(ref, iname)
+	imgs, cname, err := retrieveImages(ref, iname, platforms)
 	if err != nil {
+		if _, ok := err.(*platformError); ok {
+			return err
+		}
 		return errCacheImageDoesntExist
 	}
-	if img == nil {
-		return errors.Wrapf(err, "nil image for %s", iname)
+	if len(imgs) == 0 {
+		return errors.Errorf("no image for %s", iname)
 	}
 
 	if cname != iname {
@@ -149,43 +133,14 @@ func saveToTarFile(iname, rawDest string, overwrite bool) error {
 		}
 	}
 
-	err = writeImage(img, dst, ref)
-	if err != nil {
+	if err := storeImages(cacheDir, iname, ref, imgs); err != nil {
 		return err
 	}
-
-	klog.Infof("%s exists", dst)
-	return nil
-}
-
-func writeImage(img v1.Image, dst string, ref name.Reference) error {
-	klog.Infoln("opening: ", dst)
-	f, err := ioutil.TempFile(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
-	if err != nil {
-		return err
+	// the image is now in the store, remove the tarball of previous minikube versions
+	if err := os.Remove(legacyPath(cacheDir, iname)); err != nil && !os.IsNotExist(err) {
+		klog.Warningf("failed to remove %s: %v", legacyPath(cacheDir, iname), err)
 	}
-	defer func() {
-		// If we left behind a temp file, remove it.
-		_, err := os.Stat(f.Name())
-		if err == nil {
-			err = os.Remove(f.Name())
-			if err != nil {
-				klog.Warningf("failed to clean up the temp file %s: %v", f.Name(), err)
-			}
-		}
-	}()
 
-	err = tarball.Write(ref, img, f)
-	if err != nil {
-		return errors.Wrap(err, "write")
-	}
-	err = f.Close()
-	if err != nil {
-		return errors.Wrap(err, "close")
-	}
-	err = os.Rename(f.Name(), dst)
-	if err != nil {
-		return errors.Wrap(err, "rename")
-	}
+	klog.Infof("%s exists", iname)
 	return nil
 }
@@ -19,6 +19,7 @@ package image
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -33,6 +34,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/daemon"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
@@ -101,6 +103,44 @@ func DigestByGoLib(imgName string) string {
 	return cf.Hex
 }
 
+// DigestByCache returns the digest of the image of platform p in the image cache, or an empty string if it is not cached
+func DigestByCache(cacheDir string, imgName string, p v1.Platform) string {
+	img, _, err := CachedPlatformImage(cacheDir, imgName, p)
+	if err != nil {
+		klog.Infof("couldn't find image digest %s in the cache: %v", imgName, err)
+		return ""
+	}
+	cf, err := img.ConfigName()
+	if err != nil {
+		klog.Infof("error getting Image config name %s %v ", imgName, err)
+		return ""
+	}
+	return cf.Hex
+}
+
+// DigestByTarball returns the name and digest of the image in a tarball, as written by 'docker save'
+func DigestByTarball(path string) (string, string, error) {
+	m, err := tarball.LoadManifest(func() (io.ReadCloser, error) { return os.Open(path) })
+	if err != nil {
+		return "", "", errors.Wrapf(err, "reading manifest of %s", path)
+	}
+	if len(m) != 1 {
+		return "", "", fmt.Errorf("%s contains %d images, expected one", path, len(m))
+	}
+	if len(m[0].RepoTags) == 0 {
+		return "", "", fmt.Errorf("%s contains an untagged image", path)
+	}
+	img, err := tarball.ImageFromPath(path, nil)
+	if err != nil {
+		return "", "", err
+	}
+	cf, err := img.ConfigName()
+	if err != nil {
+		return "", "", err
+	}
+	return m[0].RepoTags[0], cf.Hex, nil
+}
+
 // Tag returns just the image with the tag
 // eg image:tag@sha256:digest -> image:tag if there is an associated tag
 // if not possible, just return the initial img
@@ -123,14 +163,24 @@ func canonicalName(ref name.Reference) string {
 }
 
 func retrieveImage(ref name.Reference, imgName string) (v1.Image, string, error) {
+	imgs, cname, err := retrieveImages(ref, imgName, []v1.Platform{defaultPlatform})
+	if err != nil {
+		return nil, "", err
+	}
+	return imgs[0].img, cname, nil
+}
+
+// retrieveImages returns the images of ref for platforms, preferring the ones of the local daemon, along with the name of ref
+func retrieveImages(ref name.Reference, imgName string, platforms []v1.Platform) ([]platformImage, string, error) {
 	var err error
-	var img v1.Image
 
 	if !useDaemon && !useRemote {
 		return nil, "", fmt.Errorf("neither daemon nor remote")
 	}
 
-	klog.Infof("retrieving image: %+v", ref)
+	klog.Infof("retrieving image: %+v for %v", ref, platforms)
+	var found []platformImage
+	missing := platforms
 	if useDaemon {
 		local := strings.HasPrefix(imgName, "localhost/")
 		canonical := imgName == canonicalName(ref)
@@ -147,24 +197,63 @@ func retrieveImage(ref name.Reference, imgName string) (v1.Image, string, error)
 				klog.Infof("short name: %s", imgName)
 			}
 		}
+		var img v1.Image
 		img, err = retrieveDaemon(ref)
 		if err == nil {
-			return img, imgName, nil
+			found, missing, err = daemonPlatforms(img, platforms)
+			if err != nil {
+				return nil, "", err
+			}
+			if len(missing) == 0 {
+				return found, imgName, nil
+			}
+			if !useRemote || len(found) == 0 {
+				// the image of the daemon is used anyway, it may have been built for the other platforms
+				klog.Warningf("%s of the daemon is for %s, not %v", imgName, PlatformString(found[0].platform), missing)
+				return found, imgName, nil
+			}
 		}
 	}
 	if useRemote {
-		img, err = retrieveRemote(ref, defaultPlatform)
+		var imgs []platformImage
+		imgs, err = retrieveRemote(ref, missing)
 		if err == nil {
-			img, err = fixPlatform(ref, img, defaultPlatform)
-			if err == nil {
-				return img, canonicalName(ref), nil
+			if len(found) > 0 {
+				return append(found, imgs...), imgName, nil
 			}
+			return imgs, canonicalName(ref), nil
+		}
+		if len(found) > 0 {
+			klog.Warningf("%s is only cached for %s: %v", imgName, PlatformString(found[0].platform), err)
+			return found, imgName, nil
 		}
 	}
 
 	return nil, "", err
 }
 
+// daemonPlatforms returns the image of the daemon for the platforms it matches, and the platforms it does not match
+// if it matches none, it is returned for its own platform
+func daemonPlatforms(img v1.Image, platforms []v1.Platform) ([]platformImage, []v1.Platform, error) {
+	p, err := imagePlatform(img)
+	if err != nil {
+		return nil, nil, err
+	}
+	var found []platformImage
+	var missing []v1.Platform
+	for _, want := range platforms {
+		if matchPlatform(want, p) {
+			found = append(found, platformImage{platform: want, img: img})
+		} else {
+			missing = append(missing, want)
+		}
+	}
+	if len(found) == 0 {
+		found = []platformImage{{platform: p, img: img}}
+	}
+	return found, missing, nil
+}
+
 func retrieveDaemon(ref name.Reference) (v1.Image, error) {
 	img, err := daemon.Image(ref)
 	if err == nil {
@@ -176,19 +265,54 @@ func retrieveDaemon(ref name.Reference) (v1.Image, error) {
 	return img, err
 }
 
-func retrieveRemote(ref name.Reference, p v1.Platform) (v1.Image, error) {
-	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithPlatform(p))
-	if err == nil {
-		return img, nil
+// retrieveRemote returns the images of ref for platforms, picking them from its manifest list if it has one
+func retrieveRemote(ref name.Reference, platforms []v1.Platform) ([]platformImage, error) {
+	desc, err := remote.Get(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		klog.Warningf("authn lookup for %+v (trying anon): %+v", ref, err)
+		desc, err = remote.Get(ref)
 	}
-
-	klog.Warningf("authn lookup for %+v (trying anon): %+v", ref, err)
-	img, err = remote.Image(ref, remote.WithPlatform(p))
 	// reference does not exist in the remote registry
 	if err != nil {
 		klog.Infof("remote lookup for %+v: %v", ref, err)
+		return nil, err
 	}
-	return img, err
+
+	if !desc.MediaType.IsIndex() {
+		img, err := desc.Image()
+		if err != nil {
+			return nil, err
+		}
+		if len(platforms) == 1 {
+			img, err = fixPlatform(ref, img, platforms[0])
+			return []platformImage{{platform: platforms[0], img: img}}, err
+		}
+		// a single image can only be cached for its own platform
+		p, err := imagePlatform(img)
+		return []platformImage{{platform: p, img: img}}, err
+	}
+
+	idx, err := desc.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading manifest list of %s", ref)
+	}
+	var imgs []platformImage
+	for _, p := range platforms {
+		d := findPlatform(m.Manifests, p)
+		if d == nil {
+			return nil, &platformError{image: ref.Name(), platform: p, available: manifestPlatforms(m.Manifests)}
+		}
+		img, err := idx.Image(d.Digest)
+		if err != nil {
+			return nil, err
+		}
+		imgs = append(imgs, platformImage{platform: p, img: img})
+	}
+	return imgs, nil
 }
 
 // See https://github.com/kubernetes/minikube/issues/10402
@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+)
+
+// platformImage is the image of one platform of a multi-platform image
+type platformImage struct {
+	platform v1.Platform
+	img      v1.Image
+}
+
+// platformError is returned when an image is not available for a platform
+type platformError struct {
+	image     string
+	platform  v1.Platform
+	available []v1.Platform
+}
+
+func (e *platformError) Error() string {
+	var names []string
+	for _, p := range e.available {
+		names = append(names, PlatformString(p))
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("%s is not available for %s", e.image, PlatformString(e.platform))
+	}
+	return fmt.Sprintf("%s is not available for %s, only for %s", e.image, PlatformString(e.platform), strings.Join(names, ", "))
+}
+
+// ParsePlatform parses a platform as given to --platform
+// Example:
+//  arm64 -> linux/arm64
+//  linux/amd64 -> linux/amd64
+//  linux/arm/v7 -> linux/arm/v7
+func ParsePlatform(s string) (v1.Platform, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) == 1 {
+		parts = []string{"linux", parts[0]}
+	}
+	if len(parts) > 3 {
+		return v1.Platform{}, errors.Errorf("invalid platform %q, expected os/arch[/variant]", s)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return v1.Platform{}, errors.Errorf("invalid platform %q, expected os/arch[/variant]", s)
+		}
+	}
+	if parts[0] != "linux" {
+		return v1.Platform{}, errors.Errorf("invalid platform %q, the nodes only run linux images", s)
+	}
+	p := v1.Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// PlatformString returns the os/arch[/variant] form of a platform
+func PlatformString(p v1.Platform) string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// PlatformFromArch returns the platform of a node, from the machine hardware name given by 'uname -m'
+func PlatformFromArch(arch string) (v1.Platform, error) {
+	switch strings.TrimSpace(arch) {
+	case "x86_64", "amd64":
+		return v1.Platform{OS: "linux", Architecture: "amd64"}, nil
+	case "aarch64", "arm64":
+		return v1.Platform{OS: "linux", Architecture: "arm64"}, nil
+	case "armv7l":
+		return v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}, nil
+	case "armv6l":
+		return v1.Platform{OS: "linux", Architecture: "arm", Variant: "v6"}, nil
+	case "ppc64le":
+		return v1.Platform{OS: "linux", Architecture: "ppc64le"}, nil
+	case "s390x":
+		return v1.Platform{OS: "linux", Architecture: "s390x"}, nil
+	}
+	return v1.Platform{}, errors.Errorf("unsupported machine architecture %q", arch)
+}
+
+// matchPlatform returns whether an image of platform got runs on platform want, a missing variant matching any
+func matchPlatform(want v1.Platform, got v1.Platform) bool {
+	if got.OS == "" {
+		got.OS = "linux"
+	}
+	if want.OS != got.OS || want.Architecture != got.Architecture {
+		return false
+	}
+	return want.Variant == "" || got.Variant == "" || want.Variant == got.Variant
+}
+
+// findPlatform returns the descriptor of the image for platform p in the manifests of an image index, preferring the exact variant
+func findPlatform(manifests []v1.Descriptor, p v1.Platform) *v1.Descriptor {
+	var found *v1.Descriptor
+	for i := range manifests {
+		d := &manifests[i]
+		if d.Platform == nil || !matchPlatform(p, *d.Platform) {
+			continue
+		}
+		if d.Platform.Variant == p.Variant {
+			return d
+		}
+		if found == nil {
+			found = d
+		}
+	}
+	return found
+}
+
+// manifestPlatforms returns the platforms of the manifests of an image index
+func manifestPlatforms(manifests []v1.Descriptor) []v1.Platform {
+	var ps []v1.Platform
+	for _, d := range manifests {
+		if d.Platform != nil {
+			ps = append(ps, *d.Platform)
+		}
+	}
+	return ps
+}
+
+// imagePlatform returns the platform of an image, from its configuration
+func imagePlatform(img v1.Image) (v1.Platform, error) {
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return v1.Platform{}, errors.Wrap(err, "reading image config")
+	}
+	p := v1.Platform{OS: cfg.OS, Architecture: cfg.Architecture}
+	if p.OS == "" {
+		p.OS = "linux"
+	}
+	return p, nil
+}
+
+// uniquePlatforms returns platforms without duplicates, or the default platform if there are none
+func uniquePlatforms(platforms []v1.Platform) []v1.Platform {
+	if len(platforms) == 0 {
+		return []v1.Platform{defaultPlatform}
+	}
+	seen := map[string]bool{}
+	var ps []v1.Platform
+	for _, p := range platforms {
+		if seen[PlatformString(p)] {
+			continue
+		}
+		seen[PlatformString(p)] = true
+		ps = append(ps, p)
+	}
+	return ps
+}
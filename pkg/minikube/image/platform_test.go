@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "arm64", want: "linux/arm64"},
+		{in: "linux/amd64", want: "linux/amd64"},
+		{in: "linux/arm/v7", want: "linux/arm/v7"},
+		{in: "windows/amd64", wantErr: true},
+		{in: "linux/", wantErr: true},
+		{in: "linux/arm/v7/extra", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParsePlatform(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParsePlatform(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			}
+			if err == nil && PlatformString(got) != tc.want {
+				t.Errorf("ParsePlatform(%q) = %s, want %s", tc.in, PlatformString(got), tc.want)
+			}
+		})
+	}
+}
+
+func TestPlatformFromArch(t *testing.T) {
+	tests := []struct {
+		arch    string
+		want    string
+		wantErr bool
+	}{
+		{arch: "x86_64\n", want: "linux/amd64"},
+		{arch: "aarch64", want: "linux/arm64"},
+		{arch: "armv7l", want: "linux/arm/v7"},
+		{arch: "s390x", want: "linux/s390x"},
+		{arch: "mips", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := PlatformFromArch(tc.arch)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("PlatformFromArch(%q) error = %v, wantErr %v", tc.arch, err, tc.wantErr)
+		}
+		if err == nil && PlatformString(got) != tc.want {
+			t.Errorf("PlatformFromArch(%q) = %s, want %s", tc.arch, PlatformString(got), tc.want)
+		}
+	}
+}
+
+func TestFindPlatform(t *testing.T) {
+	manifests := []v1.Descriptor{
+		{Digest: v1.Hash{Algorithm: "sha256", Hex: "amd64"}, Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}},
+		{Digest: v1.Hash{Algorithm: "sha256", Hex: "armv6"}, Platform: &v1.Platform{OS: "linux", Architecture: "arm", Variant: "v6"}},
+		{Digest: v1.Hash{Algorithm: "sha256", Hex: "armv7"}, Platform: &v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}},
+		{Digest: v1.Hash{Algorithm: "sha256", Hex: "arm64"}, Platform: &v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+		{Digest: v1.Hash{Algorithm: "sha256", Hex: "attestation"}},
+	}
+	tests := []struct {
+		platform v1.Platform
+		want     string
+	}{
+		{platform: v1.Platform{OS: "linux", Architecture: "amd64"}, want: "amd64"},
+		{platform: v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}, want: "armv7"},
+		{platform: v1.Platform{OS: "linux", Architecture: "arm"}, want: "armv6"},
+		{platform: v1.Platform{OS: "linux", Architecture: "arm64"}, want: "arm64"},
+		{platform: v1.Platform{OS: "linux", Architecture: "s390x"}, want: ""},
+	}
+	for _, tc := range tests {
+		t.Run(PlatformString(tc.platform), func(t *testing.T) {
+			d := findPlatform(manifests, tc.platform)
+			got := ""
+			if d != nil {
+				got = d.Digest.Hex
+			}
+			if got != tc.want {
+				t.Errorf("findPlatform(%s) = %q, want %q", PlatformString(tc.platform), got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// Scanners are the vulnerability scanners supported by 'minikube image scan', in order of preference
+var Scanners = []string{"trivy", "grype"}
+
+// severities are the vulnerability severities, from the least to the most severe
+var severities = []string{"UNKNOWN", "NEGLIGIBLE", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// Vulnerability is a vulnerability found in an image
+type Vulnerability struct {
+	Image            string
+	ID               string
+	Severity         string
+	Package          string
+	InstalledVersion string
+	FixedVersion     string
+}
+
+// FindScanner returns the path of the scanner to use, preferring the given one if not empty
+func FindScanner(name string) (string, string, error) {
+	candidates := Scanners
+	if name != "" {
+		candidates = []string{name}
+	}
+	for _, c := range candidates {
+		if !supportedScanner(c) {
+			return "", "", fmt.Errorf("unsupported scanner %q, supported scanners are: %s", c, strings.Join(Scanners, ", "))
+		}
+		if p, err := exec.LookPath(c); err == nil {
+			return c, p, nil
+		}
+	}
+	return "", "", fmt.Errorf("none of %s was found in your path", strings.Join(candidates, ", "))
+}
+
+func supportedScanner(name string) bool {
+	for _, s := range Scanners {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidSeverity returns whether s is a known vulnerability severity
+func ValidSeverity(s string) bool {
+	return severityRank(s) >= 0
+}
+
+func severityRank(s string) int {
+	for i, v := range severities {
+		if strings.EqualFold(v, s) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ScanArchive scans the image archive at path with scanner, and returns the vulnerabilities found
+func ScanArchive(scanner, scannerPath, img, path string) ([]Vulnerability, error) {
+	var args []string
+	switch scanner {
+	case "trivy":
+		args = []string{"image", "--quiet", "--format", "json", "--input", path}
+	case "grype":
+		args = []string{"--quiet", "--output", "json", "docker-archive:" + path}
+	default:
+		return nil, fmt.Errorf("unsupported scanner %q", scanner)
+	}
+
+	c := exec.Command(scannerPath, args...)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	klog.Infof("Scanning %s: %s", img, c.Args)
+	if err := c.Run(); err != nil {
+		return nil, errors.Wrapf(err, "%s: %s", scanner, stderr.String())
+	}
+
+	if scanner == "trivy" {
+		return parseTrivy(img, stdout.Bytes())
+	}
+	return parseGrype(img, stdout.Bytes())
+}
+
+func parseTrivy(img string, data []byte) ([]Vulnerability, error) {
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID  string
+				PkgName          string
+				InstalledVersion string
+				FixedVersion     string
+				Severity         string
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, errors.Wrap(err, "parsing trivy report")
+	}
+
+	var vulns []Vulnerability
+	for _, r := range report.Results {
+		for _, v := range r.Vulnerabilities {
+			vulns = append(vulns, Vulnerability{
+				Image:            img,
+				ID:               v.VulnerabilityID,
+				Severity:         strings.ToUpper(v.Severity),
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+			})
+		}
+	}
+	return vulns, nil
+}
+
+func parseGrype(img string, data []byte) ([]Vulnerability, error) {
+	var report struct {
+		Matches []struct {
+			Vulnerability struct {
+				ID       string `json:"id"`
+				Severity string `json:"severity"`
+				Fix      struct {
+					Versions []string `json:"versions"`
+				} `json:"fix"`
+			} `json:"vulnerability"`
+			Artifact struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"artifact"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, errors.Wrap(err, "parsing grype report")
+	}
+
+	var vulns []Vulnerability
+	for _, m := range report.Matches {
+		vulns = append(vulns, Vulnerability{
+			Image:            img,
+			ID:               m.Vulnerability.ID,
+			Severity:         strings.ToUpper(m.Vulnerability.Severity),
+			Package:          m.Artifact.Name,
+			InstalledVersion: m.Artifact.Version,
+			FixedVersion:     strings.Join(m.Vulnerability.Fix.Versions, ", "),
+		})
+	}
+	return vulns, nil
+}
+
+// FilterVulnerabilities returns the vulnerabilities of at least the given severity, the most severe first
+func FilterVulnerabilities(vulns []Vulnerability, minSeverity string) []Vulnerability {
+	min := severityRank(minSeverity)
+	filtered := []Vulnerability{}
+	for _, v := range vulns {
+		if severityRank(v.Severity) >= min {
+			filtered = append(filtered, v)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return severityRank(filtered[i].Severity) > severityRank(filtered[j].Severity)
+	})
+	return filtered
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseReports(t *testing.T) {
+	expected := []Vulnerability{
+		{Image: "nginx", ID: "CVE-2021-3711", Severity: "CRITICAL", Package: "libssl1.1", InstalledVersion: "1.1.1d-0", FixedVersion: "1.1.1k-1"},
+		{Image: "nginx", ID: "CVE-2021-33560", Severity: "HIGH", Package: "libgcrypt20", InstalledVersion: "1.8.4-5"},
+	}
+	tests := []struct {
+		name  string
+		parse func(string, []byte) ([]Vulnerability, error)
+		data  string
+	}{
+		{
+			name:  "trivy",
+			parse: parseTrivy,
+			data: `{"Results": [{"Target": "nginx (debian 10.10)", "Vulnerabilities": [
+				{"VulnerabilityID": "CVE-2021-3711", "PkgName": "libssl1.1", "InstalledVersion": "1.1.1d-0", "FixedVersion": "1.1.1k-1", "Severity": "CRITICAL"},
+				{"VulnerabilityID": "CVE-2021-33560", "PkgName": "libgcrypt20", "InstalledVersion": "1.8.4-5", "Severity": "HIGH"}]}]}`,
+		},
+		{
+			name:  "grype",
+			parse: parseGrype,
+			data: `{"matches": [
+				{"vulnerability": {"id": "CVE-2021-3711", "severity": "Critical", "fix": {"versions": ["1.1.1k-1"]}}, "artifact": {"name": "libssl1.1", "version": "1.1.1d-0"}},
+				{"vulnerability": {"id": "CVE-2021-33560", "severity": "High", "fix": {"versions": []}}, "artifact": {"name": "libgcrypt20", "version": "1.8.4-5"}}]}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.parse("nginx", []byte(tc.data))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Errorf("vulnerabilities mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFilterVulnerabilities(t *testing.T) {
+	vulns := []Vulnerability{
+		{ID: "low", Severity: "LOW"},
+		{ID: "critical", Severity: "CRITICAL"},
+		{ID: "unknown", Severity: "UNKNOWN"},
+		{ID: "medium", Severity: "MEDIUM"},
+	}
+	tests := []struct {
+		severity string
+		expected []string
+	}{
+		{severity: "", expected: []string{"critical", "medium", "low", "unknown"}},
+		{severity: "medium", expected: []string{"critical", "medium"}},
+		{severity: "CRITICAL", expected: []string{"critical"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.severity, func(t *testing.T) {
+			var got []string
+			for _, v := range FilterVulnerabilities(vulns, tc.severity) {
+				got = append(got, v.ID)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("filtered vulnerabilities mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
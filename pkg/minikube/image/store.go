@@ -0,0 +1,529 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/match"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/juju/mutex"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util/lock"
+)
+
+// The image cache is an OCI image layout: every layer is stored once, named after its digest,
+// however many cached images use it, and the images are listed in its index.json.
+// Each image is listed as an image index of the platforms it is cached for, except for the
+// images cached by previous minikube versions, which are single images used for every platform.
+
+// storeDir is the name of the OCI image layout within the cache directory
+const storeDir = "store"
+
+// refNameAnnotation is the annotation holding the name of an image in the OCI image layout index
+const refNameAnnotation = "org.opencontainers.image.ref.name"
+
+// canonicalNameAnnotation holds the name of the image the container runtimes are given, which may differ from the one it is cached as
+const canonicalNameAnnotation = "io.k8s.minikube.image.name"
+
+// blobGracePeriod protects recently written blobs, which may not be in the index yet, from removal
+const blobGracePeriod = 10 * time.Minute
+
+// storePath returns the path of the image store within cacheDir
+func storePath(cacheDir string) string {
+	return filepath.Join(cacheDir, storeDir)
+}
+
+// legacyPath returns where images were cached as separate tarballs before the image store
+func legacyPath(cacheDir string, img string) string {
+	return localpath.SanitizeCacheDir(filepath.Join(cacheDir, img))
+}
+
+// lockStore locks the index of the image store, the blobs can be written without it
+func lockStore(cacheDir string) (mutex.Releaser, error) {
+	spec := lock.PathMutexSpec(storePath(cacheDir))
+	spec.Timeout = 10 * time.Minute
+	klog.Infof("acquiring lock: %+v", spec)
+	return mutex.Acquire(spec)
+}
+
+// openStore opens the image store, creating it if needed
+func openStore(cacheDir string) (layout.Path, error) {
+	p, err := layout.FromPath(storePath(cacheDir))
+	if err == nil {
+		return p, nil
+	}
+	if err := os.MkdirAll(storePath(cacheDir), 0777); err != nil {
+		return "", errors.Wrap(err, "making image store directory")
+	}
+	return layout.Write(storePath(cacheDir), empty.Index)
+}
+
+// findCached returns the descriptor of img in the image store
+func findCached(p layout.Path, img string) (*v1.Descriptor, error) {
+	idx, err := p.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	ref := normalizeTagName(img)
+	for i := range m.Manifests {
+		if m.Manifests[i].Annotations[refNameAnnotation] == ref {
+			return &m.Manifests[i], nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+// IsCached returns whether img is in the image cache
+func IsCached(cacheDir string, img string) bool {
+	if _, err := os.Stat(legacyPath(cacheDir, img)); err == nil {
+		return true
+	}
+	p, err := layout.FromPath(storePath(cacheDir))
+	if err != nil {
+		return false
+	}
+	_, err = findCached(p, img)
+	return err == nil
+}
+
+// CachedImage returns img from the image cache, along with the name to give it to container runtimes
+func CachedImage(cacheDir string, img string) (v1.Image, name.Reference, error) {
+	return CachedPlatformImage(cacheDir, img, defaultPlatform)
+}
+
+// CachedPlatformImage returns the image of platform pl of img from the image cache, along with the name to give it to container runtimes
+func CachedPlatformImage(cacheDir string, img string, pl v1.Platform) (v1.Image, name.Reference, error) {
+	if err := migrateLegacy(cacheDir, img); err != nil {
+		klog.Warningf("failed to move %s to the image store: %v", img, err)
+	}
+	p, err := layout.FromPath(storePath(cacheDir))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "%s is not cached", img)
+	}
+	desc, err := findCached(p, img)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "%s is not cached", img)
+	}
+	cname := desc.Annotations[canonicalNameAnnotation]
+	if cname == "" {
+		cname = normalizeTagName(img)
+	}
+	ref, err := name.ParseReference(cname, name.WeakValidation)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "parsing image ref name for %s", cname)
+	}
+	if !desc.MediaType.IsIndex() {
+		i, err := p.Image(desc.Digest)
+		return i, ref, err
+	}
+	idx, m, err := cachedIndex(p, desc)
+	if err != nil {
+		return nil, nil, err
+	}
+	d := findPlatform(m.Manifests, pl)
+	if d == nil {
+		return nil, nil, &platformError{image: img, platform: pl, available: manifestPlatforms(m.Manifests)}
+	}
+	i, err := idx.Image(d.Digest)
+	return i, ref, err
+}
+
+// cachedIndex returns the image index of the platforms of an image in the image store
+func cachedIndex(p layout.Path, desc *v1.Descriptor) (v1.ImageIndex, *v1.IndexManifest, error) {
+	root, err := p.ImageIndex()
+	if err != nil {
+		return nil, nil, err
+	}
+	idx, err := root.ImageIndex(desc.Digest)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading image index %s", desc.Digest)
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading image index %s", desc.Digest)
+	}
+	return idx, m, nil
+}
+
+// uncachedPlatforms returns the platforms which img is not cached for
+func uncachedPlatforms(cacheDir string, img string, platforms []v1.Platform) []v1.Platform {
+	if _, err := os.Stat(legacyPath(cacheDir, img)); err == nil {
+		return nil
+	}
+	p, err := layout.FromPath(storePath(cacheDir))
+	if err != nil {
+		return platforms
+	}
+	desc, err := findCached(p, img)
+	if err != nil {
+		return platforms
+	}
+	if !desc.MediaType.IsIndex() {
+		return nil
+	}
+	_, m, err := cachedIndex(p, desc)
+	if err != nil {
+		klog.Warningf("failed to read the platforms of %s: %v", img, err)
+		return platforms
+	}
+	var missing []v1.Platform
+	for _, pl := range platforms {
+		if findPlatform(m.Manifests, pl) == nil {
+			missing = append(missing, pl)
+		}
+	}
+	return missing
+}
+
+// WriteCachedImage writes img from the image cache to w, as a tarball that container runtimes can load
+func WriteCachedImage(cacheDir string, img string, w io.Writer) error {
+	return WriteCachedPlatformImage(cacheDir, img, defaultPlatform, w)
+}
+
+// WriteCachedPlatformImage writes the image of platform pl of img from the image cache to w, as a tarball that container runtimes can load
+func WriteCachedPlatformImage(cacheDir string, img string, pl v1.Platform, w io.Writer) error {
+	i, ref, err := CachedPlatformImage(cacheDir, img, pl)
+	if err != nil {
+		return err
+	}
+	return tarball.Write(ref, i, w)
+}
+
+// CachedPlatformImageSize returns the size of the tarball WriteCachedPlatformImage writes, without writing it anywhere
+func CachedPlatformImageSize(cacheDir string, img string, pl v1.Platform) (int64, error) {
+	var c byteCounter
+	err := WriteCachedPlatformImage(cacheDir, img, pl, &c)
+	return int64(c), err
+}
+
+// byteCounter is a writer which only counts the bytes written to it
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// ExportCachedImage writes img from the image cache to a tarball at dst
+func ExportCachedImage(cacheDir string, img string, dst string) error {
+	return ExportCachedPlatformImage(cacheDir, img, defaultPlatform, dst)
+}
+
+// ExportCachedPlatformImage writes the image of platform pl of img from the image cache to a tarball at dst
+func ExportCachedPlatformImage(cacheDir string, img string, pl v1.Platform, dst string) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err := WriteCachedPlatformImage(cacheDir, img, pl, f); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+	return f.Close()
+}
+
+// storeImage adds img to the image store as iname, replacing the image previously stored as iname
+func storeImage(cacheDir string, iname string, ref name.Reference, img v1.Image) error {
+	p, err := openStore(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	// Write the blobs first, so that the index is not locked while they are downloaded
+	if err := writeLayers(p, img); err != nil {
+		return err
+	}
+
+	releaser, err := lockStore(cacheDir)
+	if err != nil {
+		return errors.Wrap(err, "locking image store")
+	}
+	defer releaser.Release()
+
+	iname = normalizeTagName(iname)
+	annotations := map[string]string{refNameAnnotation: iname, canonicalNameAnnotation: ref.String()}
+	err = p.ReplaceImage(img, match.Annotation(refNameAnnotation, iname), layout.WithAnnotations(annotations))
+	if err != nil {
+		return errors.Wrap(err, "adding image to the store")
+	}
+	return removeUnusedBlobs(p)
+}
+
+// storeImages adds the images of several platforms of iname to the image store, as an image index,
+// keeping the images of the other platforms previously stored as iname
+func storeImages(cacheDir string, iname string, ref name.Reference, imgs []platformImage) error {
+	p, err := openStore(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	// Write the blobs first, so that the index is not locked while they are downloaded
+	for _, pi := range imgs {
+		if err := writeLayers(p, pi.img); err != nil {
+			return err
+		}
+	}
+
+	releaser, err := lockStore(cacheDir)
+	if err != nil {
+		return errors.Wrap(err, "locking image store")
+	}
+	defer releaser.Release()
+
+	iname = normalizeTagName(iname)
+	var adds []mutate.IndexAddendum
+	for _, pi := range imgs {
+		pl := pi.platform
+		adds = append(adds, mutate.IndexAddendum{Add: pi.img, Descriptor: v1.Descriptor{Platform: &pl}})
+	}
+	kept, err := otherPlatforms(p, iname, imgs)
+	if err != nil {
+		return errors.Wrapf(err, "reading the cached platforms of %s", iname)
+	}
+	adds = append(adds, kept...)
+
+	annotations := map[string]string{refNameAnnotation: iname, canonicalNameAnnotation: ref.String()}
+	err = p.ReplaceIndex(mutate.AppendManifests(empty.Index, adds...), match.Annotation(refNameAnnotation, iname), layout.WithAnnotations(annotations))
+	if err != nil {
+		return errors.Wrap(err, "adding image to the store")
+	}
+	return removeUnusedBlobs(p)
+}
+
+// otherPlatforms returns the images stored as iname for other platforms than the ones of imgs, the store must be locked
+func otherPlatforms(p layout.Path, iname string, imgs []platformImage) ([]mutate.IndexAddendum, error) {
+	desc, err := findCached(p, iname)
+	if err != nil || !desc.MediaType.IsIndex() {
+		return nil, nil
+	}
+	idx, m, err := cachedIndex(p, desc)
+	if err != nil {
+		return nil, err
+	}
+	var adds []mutate.IndexAddendum
+	for _, d := range m.Manifests {
+		if d.Platform == nil || replacesPlatform(imgs, *d.Platform) {
+			continue
+		}
+		img, err := idx.Image(d.Digest)
+		if err != nil {
+			return nil, err
+		}
+		adds = append(adds, mutate.IndexAddendum{Add: img, Descriptor: v1.Descriptor{Platform: d.Platform}})
+	}
+	return adds, nil
+}
+
+// replacesPlatform returns whether one of imgs is for platform pl
+func replacesPlatform(imgs []platformImage, pl v1.Platform) bool {
+	for _, pi := range imgs {
+		if matchPlatform(pi.platform, pl) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeLayers writes the layers of img which are not in the image store yet
+func writeLayers(p layout.Path, img v1.Image) error {
+	layers, err := img.Layers()
+	if err != nil {
+		return errors.Wrap(err, "getting layers")
+	}
+	for _, l := range layers {
+		h, err := l.Digest()
+		if err != nil {
+			return err
+		}
+		if err := writeBlob(p, h, l.Compressed); err != nil {
+			return errors.Wrapf(err, "writing layer %s", h)
+		}
+	}
+	return nil
+}
+
+// writeBlob atomically writes the blob h, if it is not in the image store yet
+func writeBlob(p layout.Path, h v1.Hash, open func() (io.ReadCloser, error)) error {
+	dst := filepath.Join(string(p), "blobs", h.Algorithm, h.Hex)
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+		return err
+	}
+	rc, err := open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := ioutil.TempFile(filepath.Dir(dst), h.Hex+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), dst)
+}
+
+// removeFromStore removes images from the image store
+func removeFromStore(cacheDir string, images []string) error {
+	p, err := layout.FromPath(storePath(cacheDir))
+	if err != nil {
+		return nil
+	}
+
+	releaser, err := lockStore(cacheDir)
+	if err != nil {
+		return errors.Wrap(err, "locking image store")
+	}
+	defer releaser.Release()
+
+	for _, img := range images {
+		ref := normalizeTagName(img)
+		if err := p.RemoveDescriptors(match.Annotation(refNameAnnotation, ref)); err != nil {
+			return errors.Wrapf(err, "removing %s", img)
+		}
+	}
+	return removeUnusedBlobs(p)
+}
+
+// removeUnusedBlobs removes the blobs not used by any image of the store, the store must be locked
+func removeUnusedBlobs(p layout.Path) error {
+	idx, err := p.ImageIndex()
+	if err != nil {
+		return err
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return err
+	}
+
+	used := map[string]bool{}
+	for _, desc := range m.Manifests {
+		used[desc.Digest.Hex] = true
+		if !desc.MediaType.IsIndex() {
+			img, err := idx.Image(desc.Digest)
+			if err != nil {
+				return err
+			}
+			if err := markUsed(used, img); err != nil {
+				return err
+			}
+			continue
+		}
+		child, err := idx.ImageIndex(desc.Digest)
+		if err != nil {
+			return err
+		}
+		cm, err := child.IndexManifest()
+		if err != nil {
+			return err
+		}
+		for _, d := range cm.Manifests {
+			used[d.Digest.Hex] = true
+			img, err := child.Image(d.Digest)
+			if err != nil {
+				return err
+			}
+			if err := markUsed(used, img); err != nil {
+				return err
+			}
+		}
+	}
+
+	dir := filepath.Join(string(p), "blobs", "sha256")
+	blobs, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, b := range blobs {
+		if used[b.Name()] || time.Since(b.ModTime()) < blobGracePeriod {
+			continue
+		}
+		klog.Infof("removing unused blob %s", b.Name())
+		if err := os.Remove(filepath.Join(dir, b.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markUsed marks the config and layers of img as used
+func markUsed(used map[string]bool, img v1.Image) error {
+	cfg, err := img.ConfigName()
+	if err != nil {
+		return err
+	}
+	used[cfg.Hex] = true
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+	for _, l := range layers {
+		h, err := l.Digest()
+		if err != nil {
+			return err
+		}
+		used[h.Hex] = true
+	}
+	return nil
+}
+
+// migrateLegacy moves img from its own tarball into the image store
+func migrateLegacy(cacheDir string, img string) error {
+	src := legacyPath(cacheDir, img)
+	if _, err := os.Stat(src); err != nil {
+		return nil
+	}
+	ref, err := name.ParseReference(normalizeTagName(img), name.WeakValidation)
+	if err != nil {
+		return err
+	}
+	i, err := tarball.ImageFromPath(src, nil)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", src)
+	}
+	if err := storeImage(cacheDir, img, ref, i); err != nil {
+		return err
+	}
+	klog.Infof("moved %s to the image store", src)
+	return os.Remove(src)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+func blobCount(t *testing.T, cacheDir string) int {
+	t.Helper()
+	blobs, err := ioutil.ReadDir(filepath.Join(storePath(cacheDir), "blobs", "sha256"))
+	if err != nil {
+		t.Fatalf("reading blobs: %v", err)
+	}
+	return len(blobs)
+}
+
+func TestStoreSharesLayers(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	base, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatalf("random image: %v", err)
+	}
+	extra, err := random.Layer(1024, "application/vnd.docker.image.rootfs.diff.tar.gzip")
+	if err != nil {
+		t.Fatalf("random layer: %v", err)
+	}
+	app, err := mutate.AppendLayers(base, extra)
+	if err != nil {
+		t.Fatalf("append layer: %v", err)
+	}
+
+	for iname, img := range map[string]v1.Image{"base:1": base, "app:1": app} {
+		ref, err := name.ParseReference(iname, name.WeakValidation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := storeImage(cacheDir, iname, ref, img); err != nil {
+			t.Fatalf("storeImage(%s): %v", iname, err)
+		}
+		if !IsCached(cacheDir, iname) {
+			t.Errorf("%s is not cached after being stored", iname)
+		}
+	}
+
+	// 3 layers, 2 configs and 2 manifests: the layers of base are only stored once
+	if n := blobCount(t, cacheDir); n != 7 {
+		t.Errorf("got %d blobs, want 7", n)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCachedImage(cacheDir, "app:1", &buf); err != nil {
+		t.Fatalf("WriteCachedImage: %v", err)
+	}
+	size, err := CachedPlatformImageSize(cacheDir, "app:1", defaultPlatform)
+	if err != nil || size != int64(buf.Len()) {
+		t.Errorf("CachedPlatformImageSize = %d (%v), want %d", size, err, buf.Len())
+	}
+	loaded, err := tarball.Image(func() (io.ReadCloser, error) { return ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil }, nil)
+	if err != nil {
+		t.Fatalf("reading tarball: %v", err)
+	}
+	want, _ := app.ConfigName()
+	got, err := loaded.ConfigName()
+	if err != nil || got != want {
+		t.Errorf("loaded image config = %v (%v), want %v", got, err, want)
+	}
+}
+
+func TestRemoveFromStore(t *testing.T) {
+	cacheDir := t.TempDir()
+	img, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatalf("random image: %v", err)
+	}
+	ref, _ := name.ParseReference("busybox:1", name.WeakValidation)
+	if err := storeImage(cacheDir, "busybox:1", ref, img); err != nil {
+		t.Fatalf("storeImage: %v", err)
+	}
+
+	// blobs of images which were just written are kept, as they may not be in the index yet
+	old := time.Now().Add(-2 * blobGracePeriod)
+	blobs := filepath.Join(storePath(cacheDir), "blobs", "sha256")
+	files, _ := ioutil.ReadDir(blobs)
+	for _, f := range files {
+		if err := os.Chtimes(filepath.Join(blobs, f.Name()), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := removeFromStore(cacheDir, []string{"busybox:1"}); err != nil {
+		t.Fatalf("removeFromStore: %v", err)
+	}
+	if IsCached(cacheDir, "busybox:1") {
+		t.Errorf("busybox:1 is still cached after being removed")
+	}
+	if n := blobCount(t, cacheDir); n != 0 {
+		t.Errorf("got %d blobs after removing the only image, want 0", n)
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	cacheDir := t.TempDir()
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatalf("random image: %v", err)
+	}
+	ref, _ := name.ParseReference("registry.example.com/app:v1", name.WeakValidation)
+	legacy := legacyPath(cacheDir, "registry.example.com/app:v1")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := tarball.WriteToFile(legacy, ref, img); err != nil {
+		t.Fatalf("writing legacy tarball: %v", err)
+	}
+
+	if _, _, err := CachedImage(cacheDir, "registry.example.com/app:v1"); err != nil {
+		t.Fatalf("CachedImage: %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy tarball %s was not removed: %v", legacy, err)
+	}
+	if !IsCached(cacheDir, "registry.example.com/app:v1") {
+		t.Errorf("image is not cached after being moved to the store")
+	}
+}
+
+func TestDigestByTarball(t *testing.T) {
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatalf("random image: %v", err)
+	}
+	ref, _ := name.ParseReference("registry.example.com/app:v1", name.WeakValidation)
+	path := filepath.Join(t.TempDir(), "app.tar")
+	if err := tarball.WriteToFile(path, ref, img); err != nil {
+		t.Fatalf("writing tarball: %v", err)
+	}
+
+	gotName, gotDigest, err := DigestByTarball(path)
+	if err != nil {
+		t.Fatalf("DigestByTarball: %v", err)
+	}
+	want, _ := img.ConfigName()
+	if gotName != "registry.example.com/app:v1" || gotDigest != want.Hex {
+		t.Errorf("DigestByTarball() = %q, %q, want %q, %q", gotName, gotDigest, "registry.example.com/app:v1", want.Hex)
+	}
+}
+
+func TestStorePlatforms(t *testing.T) {
+	cacheDir := t.TempDir()
+	amd64 := v1.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := v1.Platform{OS: "linux", Architecture: "arm64"}
+	ref, _ := name.ParseReference("busybox:1", name.WeakValidation)
+
+	imgs := map[string]v1.Image{}
+	for _, p := range []v1.Platform{amd64, arm64} {
+		img, err := random.Image(1024, 1)
+		if err != nil {
+			t.Fatalf("random image: %v", err)
+		}
+		// the images of each platform are cached separately, as when a node of another platform joins the cluster
+		if err := storeImages(cacheDir, "busybox:1", ref, []platformImage{{platform: p, img: img}}); err != nil {
+			t.Fatalf("storeImages(%s): %v", PlatformString(p), err)
+		}
+		imgs[PlatformString(p)] = img
+	}
+
+	if missing := uncachedPlatforms(cacheDir, "busybox:1", []v1.Platform{amd64, arm64}); len(missing) != 0 {
+		t.Errorf("busybox:1 is not cached for %v", missing)
+	}
+	for p, want := range imgs {
+		pl, _ := ParsePlatform(p)
+		got, _, err := CachedPlatformImage(cacheDir, "busybox:1", pl)
+		if err != nil {
+			t.Fatalf("CachedPlatformImage(%s): %v", p, err)
+		}
+		wantCfg, _ := want.ConfigName()
+		gotCfg, _ := got.ConfigName()
+		if gotCfg != wantCfg {
+			t.Errorf("CachedPlatformImage(%s) config = %v, want %v", p, gotCfg, wantCfg)
+		}
+	}
+
+	s390x := v1.Platform{OS: "linux", Architecture: "s390x"}
+	if _, _, err := CachedPlatformImage(cacheDir, "busybox:1", s390x); err == nil {
+		t.Errorf("CachedPlatformImage(linux/s390x) succeeded for an image which is not cached for it")
+	}
+	if missing := uncachedPlatforms(cacheDir, "busybox:1", []v1.Platform{s390x}); len(missing) != 1 {
+		t.Errorf("uncachedPlatforms(linux/s390x) = %v, want [linux/s390x]", missing)
+	}
+
+	if err := removeFromStore(cacheDir, []string{"busybox:1"}); err != nil {
+		t.Fatalf("removeFromStore: %v", err)
+	}
+	if IsCached(cacheDir, "busybox:1") {
+		t.Errorf("busybox:1 is still cached after being removed")
+	}
+}
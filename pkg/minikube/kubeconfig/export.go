@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/clientcmd/api/latest"
+)
+
+// HasContext returns whether the kubeconfig has a context
+func HasContext(contextName string, configPath ...string) bool {
+	cfg, err := readOrNew(configPath...)
+	if err != nil {
+		return false
+	}
+	_, ok := cfg.Contexts[contextName]
+	return ok
+}
+
+// Extract returns a kubeconfig holding only a context of the kubeconfig, with its cluster and user, which is its current context.
+// With embedCerts, the certificates and keys it references by path are embedded, so that it can be used on other hosts.
+func Extract(contextName string, embedCerts bool, configPath ...string) (*api.Config, error) {
+	cfg, err := readOrNew(configPath...)
+	if err != nil {
+		return nil, errors.Wrap(err, "read")
+	}
+	ctx, ok := cfg.Contexts[contextName]
+	if !ok {
+		return nil, errors.Errorf("context %q not found", contextName)
+	}
+	cluster, ok := cfg.Clusters[ctx.Cluster]
+	if !ok {
+		return nil, errors.Errorf("cluster %q of context %q not found", ctx.Cluster, contextName)
+	}
+	user, ok := cfg.AuthInfos[ctx.AuthInfo]
+	if !ok {
+		return nil, errors.Errorf("user %q of context %q not found", ctx.AuthInfo, contextName)
+	}
+
+	out := api.NewConfig()
+	out.Contexts[contextName] = ctx.DeepCopy()
+	out.Clusters[ctx.Cluster] = cluster.DeepCopy()
+	out.AuthInfos[ctx.AuthInfo] = user.DeepCopy()
+	out.CurrentContext = contextName
+	if embedCerts {
+		if err := embed(out.Clusters[ctx.Cluster], out.AuthInfos[ctx.AuthInfo]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// embed embeds the certificates and keys referenced by path by a cluster and a user
+func embed(cluster *api.Cluster, user *api.AuthInfo) error {
+	var err error
+	if cluster.CertificateAuthority != "" {
+		if cluster.CertificateAuthorityData, err = ioutil.ReadFile(cluster.CertificateAuthority); err != nil {
+			return errors.Wrap(err, "reading certificate authority")
+		}
+		cluster.CertificateAuthority = ""
+	}
+	if user.ClientCertificate != "" {
+		if user.ClientCertificateData, err = ioutil.ReadFile(user.ClientCertificate); err != nil {
+			return errors.Wrap(err, "reading client certificate")
+		}
+		user.ClientCertificate = ""
+	}
+	if user.ClientKey != "" {
+		if user.ClientKeyData, err = ioutil.ReadFile(user.ClientKey); err != nil {
+			return errors.Wrap(err, "reading client key")
+		}
+		user.ClientKey = ""
+	}
+	return nil
+}
+
+// Merge merges kubeconfigs into one, whose current context is the one of the first
+func Merge(cfgs ...*api.Config) *api.Config {
+	out := api.NewConfig()
+	for _, cfg := range cfgs {
+		for k, v := range cfg.Clusters {
+			out.Clusters[k] = v
+		}
+		for k, v := range cfg.AuthInfos {
+			out.AuthInfos[k] = v
+		}
+		for k, v := range cfg.Contexts {
+			out.Contexts[k] = v
+		}
+		if out.CurrentContext == "" {
+			out.CurrentContext = cfg.CurrentContext
+		}
+	}
+	return out
+}
+
+// Encode encodes a kubeconfig to YAML
+func Encode(cfg *api.Config) ([]byte, error) {
+	return runtime.Encode(latest.Codec, cfg)
+}
+
+// Write writes a kubeconfig to a file, readable by its owner only
+func Write(cfg *api.Config, path string) error {
+	return writeToFile(cfg, path)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var kubeConfigTwoClusters = []byte(`
+apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority: CA_PATH
+    server: https://192.168.49.2:8443
+  name: foo
+- cluster:
+    server: https://192.168.58.2:8443
+  name: bar
+contexts:
+- context:
+    cluster: foo
+    namespace: dev
+    user: foo
+  name: foo
+- context:
+    cluster: bar
+    user: bar
+  name: bar
+current-context: bar
+kind: Config
+preferences: {}
+users:
+- name: foo
+  user:
+    client-certificate: CERT_PATH
+    client-key: KEY_PATH
+- name: bar
+  user:
+    token: secret
+`)
+
+func TestExtract(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	paths := map[string]string{}
+	for _, f := range []string{"ca.crt", "client.crt", "client.key"} {
+		paths[f] = filepath.Join(dir, f)
+		if err := ioutil.WriteFile(paths[f], []byte(f), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := string(kubeConfigTwoClusters)
+	for k, v := range map[string]string{"CA_PATH": paths["ca.crt"], "CERT_PATH": paths["client.crt"], "KEY_PATH": paths["client.key"]} {
+		data = strings.ReplaceAll(data, k, v)
+	}
+	path := tempFile(t, []byte(data))
+	defer os.Remove(path)
+
+	if !HasContext("foo", path) || HasContext("baz", path) {
+		t.Errorf("HasContext() did not find the right contexts")
+	}
+
+	cfg, err := Extract("foo", false, path)
+	if err != nil {
+		t.Fatalf("Extract() failed: %v", err)
+	}
+	if cfg.CurrentContext != "foo" || len(cfg.Contexts) != 1 || len(cfg.Clusters) != 1 || len(cfg.AuthInfos) != 1 {
+		t.Fatalf("Extract() = %+v, want only the foo context", cfg)
+	}
+	if cfg.Contexts["foo"].Namespace != "dev" || cfg.AuthInfos["foo"].ClientCertificate != paths["client.crt"] {
+		t.Errorf("Extract() did not keep the foo context as is: %+v", cfg)
+	}
+
+	cfg, err = Extract("foo", true, path)
+	if err != nil {
+		t.Fatalf("Extract() with embedded certs failed: %v", err)
+	}
+	c, u := cfg.Clusters["foo"], cfg.AuthInfos["foo"]
+	if c.CertificateAuthority != "" || string(c.CertificateAuthorityData) != "ca.crt" || u.ClientKey != "" || string(u.ClientKeyData) != "client.key" {
+		t.Errorf("Extract() did not embed the certificates: %+v %+v", c, u)
+	}
+
+	if _, err := Extract("baz", false, path); err == nil {
+		t.Errorf("Extract() of a missing context did not fail")
+	}
+
+	bar, err := Extract("bar", false, path)
+	if err != nil {
+		t.Fatalf("Extract() failed: %v", err)
+	}
+	merged := Merge(cfg, bar)
+	if merged.CurrentContext != "foo" || len(merged.Contexts) != 2 || len(merged.Clusters) != 2 || len(merged.AuthInfos) != 2 {
+		t.Errorf("Merge() = %+v", merged)
+	}
+
+	out := filepath.Join(dir, "out", "bar.kubeconfig")
+	if err := Write(bar, out); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if !HasContext("bar", out) {
+		t.Errorf("written kubeconfig has no bar context")
+	}
+}
@@ -155,6 +155,32 @@ func UpdateEndpoint(contextName string, hostname string, port int, confpath stri
 	return true, nil
 }
 
+// RefreshEmbeddedCerts reloads the client certificate and key embedded in the kubeconfig entry of contextName.
+// Entries that reference the certificate files by path are left alone, as they pick up new certificates by themselves.
+func RefreshEmbeddedCerts(contextName string, confpath string) (bool, error) {
+	cfg, err := readOrNew(confpath)
+	if err != nil {
+		return false, errors.Wrap(err, "read")
+	}
+
+	user, ok := cfg.AuthInfos[contextName]
+	if !ok || len(user.ClientCertificateData) == 0 {
+		return false, nil
+	}
+
+	if user.ClientCertificateData, err = ioutil.ReadFile(localpath.ClientCert(contextName)); err != nil {
+		return false, errors.Wrap(err, "reading client certificate")
+	}
+	if user.ClientKeyData, err = ioutil.ReadFile(localpath.ClientKey(contextName)); err != nil {
+		return false, errors.Wrap(err, "reading client key")
+	}
+
+	if err := writeToFile(cfg, confpath); err != nil {
+		return false, errors.Wrap(err, "write")
+	}
+	return true, nil
+}
+
 // writeToFile encodes the configuration and writes it to the given file.
 // If the file exists, it's contents will be overwritten.
 func writeToFile(config runtime.Object, configPath ...string) error {
@@ -437,6 +437,55 @@ func TestUpdateIP(t *testing.T) {
 	}
 }
 
+func TestRefreshEmbeddedCerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minikube-home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv(localpath.MinikubeHome, dir)
+
+	if err := os.MkdirAll(localpath.Profile("minikube"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(localpath.ClientCert("minikube"), []byte("new cert"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(localpath.ClientKey("minikube"), []byte("new key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := api.NewConfig()
+	cfg.AuthInfos["minikube"] = &api.AuthInfo{ClientCertificateData: []byte("old cert"), ClientKeyData: []byte("old key")}
+	cfg.AuthInfos["other"] = &api.AuthInfo{ClientCertificate: "/other/client.crt", ClientKey: "/other/client.key"}
+	configFilename := tempFile(t, nil)
+	defer os.Remove(configFilename)
+	if err := writeToFile(cfg, configFilename); err != nil {
+		t.Fatal(err)
+	}
+
+	if updated, err := RefreshEmbeddedCerts("other", configFilename); err != nil || updated {
+		t.Errorf("RefreshEmbeddedCerts(other) = %t, %v, want false, nil", updated, err)
+	}
+	if updated, err := RefreshEmbeddedCerts("minikube", configFilename); err != nil || !updated {
+		t.Fatalf("RefreshEmbeddedCerts(minikube) = %t, %v, want true, nil", updated, err)
+	}
+
+	actual, err := readOrNew(configFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(actual.AuthInfos["minikube"].ClientCertificateData); got != "new cert" {
+		t.Errorf("client certificate = %q, want %q", got, "new cert")
+	}
+	if got := string(actual.AuthInfos["minikube"].ClientKeyData); got != "new key" {
+		t.Errorf("client key = %q, want %q", got, "new key")
+	}
+	if got := actual.AuthInfos["other"].ClientCertificate; got != "/other/client.crt" {
+		t.Errorf("other client certificate = %q, want it unchanged", got)
+	}
+}
+
 func TestEmptyConfig(t *testing.T) {
 	tmp := tempFile(t, []byte{})
 	defer os.Remove(tmp)
@@ -68,6 +68,12 @@ func EventLog(name string) string {
 	return filepath.Join(Profile(name), "events.json")
 }
 
+// AddonSecrets returns the path to the secret addon settings of a profile.
+// They are kept out of the cluster config, so they aren't shown or exported with it.
+func AddonSecrets(name string) string {
+	return filepath.Join(Profile(name), "addon-secrets.json")
+}
+
 // AuditLog returns the path to the audit log.
 // This log contains a history of commands run, by who, when, and what arguments.
 func AuditLog() string {
@@ -79,6 +85,12 @@ func LastStartLog() string {
 	return filepath.Join(MiniPath(), "logs", "lastStart.txt")
 }
 
+// HooksDir returns the path to the lifecycle hooks directory.
+// Executables in its per-event subdirectories run when that event happens.
+func HooksDir() string {
+	return filepath.Join(MiniPath(), "hooks.d")
+}
+
 // ClientCert returns client certificate path, used by kubeconfig
 func ClientCert(name string) string {
 	new := filepath.Join(Profile(name), "client.crt")
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+)
+
+const (
+	// followInterval is how often Follow looks for new containers, which crash looping pods keep creating
+	followInterval = 2 * time.Second
+
+	// timestampFormat is the format of the time prefixing the lines of followed logs
+	timestampFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// Follow streams the logs of the components, of all of them when there are none, interleaving their lines as they arrive.
+// The containers created while following, such as those of crash looping pods, are followed as they appear.
+// It runs until minikube is interrupted.
+func Follow(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, cr logRunner, logOutput io.Writer, lines int, components []string) {
+	f := newFollower(cr, logOutput)
+
+	cmds := bs.LogCommands(cfg, bootstrapper.LogOptions{Lines: lines, Follow: true})
+	cmds[r.Name()] = r.SystemLogCmd(lines, true)
+	for name, cmd := range cmds {
+		if matchesComponent(name, components) {
+			f.follow(name, cmd)
+		}
+	}
+
+	// the containers which exist when starting are shown from their last lines, the new ones from their start
+	length := lines
+	for {
+		for _, pod := range importantPods {
+			if !matchesComponent(pod, components) {
+				continue
+			}
+			ids, err := r.ListContainers(cruntime.ListContainersOptions{Name: pod})
+			if err != nil {
+				klog.Warningf("Failed to list containers for %q: %v", pod, err)
+				continue
+			}
+			for _, id := range ids {
+				f.follow(fmt.Sprintf("%s [%s]", pod, id), r.ContainerLogCmd(id, length, true))
+			}
+		}
+		length = 0
+		time.Sleep(followInterval)
+	}
+}
+
+// follower prints the logs of many sources at once, a line at a time
+type follower struct {
+	runner logRunner
+	out    io.Writer
+	now    func() time.Time
+
+	// mu serializes the lines of the sources
+	mu sync.Mutex
+	// followed are the sources being followed, or which were
+	followed map[string]bool
+}
+
+func newFollower(cr logRunner, w io.Writer) *follower {
+	return &follower{runner: cr, out: w, now: time.Now, followed: map[string]bool{}}
+}
+
+// follow runs cmd in the background, printing its output as the logs of source, unless source was already followed
+func (f *follower) follow(source string, cmd string) {
+	if f.followed[source] {
+		return
+	}
+	f.followed[source] = true
+	klog.Infof("following logs of %s: %s", source, cmd)
+
+	go func() {
+		w := &lineWriter{emit: func(l string) { f.print(source, l) }}
+		c := exec.Command("/bin/bash", "-c", cmd)
+		c.Stdout = w
+		c.Stderr = w
+		if rr, err := f.runner.RunCmd(c); err != nil {
+			klog.Warningf("following %s: %s: %v", source, rr.Command(), err)
+		}
+		w.flush()
+	}()
+}
+
+// print prints a line of the logs of source, prefixed with the time it was received
+func (f *follower) print(source string, line string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t := f.now()
+	if out.JSON {
+		register.PrintLogLineAt(source, line, t)
+		return
+	}
+	fmt.Fprintf(f.out, "%s %s | %s\n", t.Format(timestampFormat), source, line)
+}
+
+// lineWriter calls emit with every line written to it
+type lineWriter struct {
+	mu   sync.Mutex
+	buf  []byte
+	emit func(string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(strings.TrimSuffix(string(w.buf[:i]), "\r"))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush emits the last line, when it did not end with a newline
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = nil
+	}
+}
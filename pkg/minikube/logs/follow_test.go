@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := &lineWriter{emit: func(l string) { lines = append(lines, l) }}
+	for _, s := range []string{"first\nsec", "ond\r\n", "\nlast"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatalf("Write(%q): %v", s, err)
+		}
+	}
+	w.flush()
+
+	want := []string{"first", "second", "", "last"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("got lines %q, want %q", lines, want)
+	}
+}
+
+func TestFollowerPrint(t *testing.T) {
+	var b bytes.Buffer
+	f := newFollower(nil, &b)
+	f.now = func() time.Time { return time.Date(2021, 11, 1, 10, 30, 5, 123000000, time.UTC) }
+
+	f.print("kubelet", "Started kubelet")
+	f.print("kube-apiserver [0123456789ab]", "I1101 serving securely")
+
+	want := "2021-11-01T10:30:05.123Z kubelet | Started kubelet\n" +
+		"2021-11-01T10:30:05.123Z kube-apiserver [0123456789ab] | I1101 serving securely\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
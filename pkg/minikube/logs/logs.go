@@ -37,6 +37,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/style"
 )
 
@@ -93,23 +94,6 @@ type logRunner interface {
 // include usage messages from a failed binary, but small enough to not include irrelevant problems.
 const lookBackwardsCount = 400
 
-// Follow follows logs from multiple files in tail(1) format
-func Follow(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, cr logRunner, logOutput io.Writer) error {
-	cs := []string{}
-	for _, v := range logCommands(r, bs, cfg, 0, true) {
-		cs = append(cs, v+" &")
-	}
-	cs = append(cs, "wait")
-
-	cmd := exec.Command("/bin/bash", "-c", strings.Join(cs, " "))
-	cmd.Stdout = logOutput
-	cmd.Stderr = logOutput
-	if _, err := cr.RunCmd(cmd); err != nil {
-		return errors.Wrapf(err, "log follow")
-	}
-	return nil
-}
-
 // IsProblem returns whether this line matches a known problem
 func IsProblem(line string) bool {
 	return rootCauseRe.MatchString(line) && !ignoreCauseRe.MatchString(line)
@@ -118,7 +102,7 @@ func IsProblem(line string) bool {
 // FindProblems finds possible root causes among the logs
 func FindProblems(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, cr logRunner) map[string][]string {
 	pMap := map[string][]string{}
-	cmds := logCommands(r, bs, cfg, lookBackwardsCount, false)
+	cmds := logCommands(r, bs, cfg, lookBackwardsCount)
 	for name := range cmds {
 		klog.Infof("Gathering logs for %s ...", name)
 		var b bytes.Buffer
@@ -162,14 +146,16 @@ func OutputProblems(problems map[string][]string, maxLines int, logOutput *os.Fi
 	}
 }
 
-// Output displays logs from multiple sources in tail(1) format
-func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, runner command.Runner, lines int, logOutput *os.File) error {
-	cmds := logCommands(r, bs, cfg, lines, false)
+// Output displays logs from multiple sources in tail(1) format, only those of components when given
+func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, runner command.Runner, lines int, logOutput *os.File, components []string) error {
+	cmds := logCommands(r, bs, cfg, lines)
 	cmds["kernel"] = "uptime && uname -a && grep PRETTY /etc/os-release"
 
 	names := []string{}
 	for k := range cmds {
-		names = append(names, k)
+		if matchesComponent(k, components) {
+			names = append(names, k)
+		}
 	}
 
 	out.SetOutFile(logOutput)
@@ -178,10 +164,12 @@ func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.Cluster
 	sort.Strings(names)
 	failed := []string{}
 	for i, name := range names {
-		if i > 0 {
+		if i > 0 && !out.JSON {
 			out.Styled(style.Empty, "")
 		}
-		out.Styled(style.Empty, "==> {{.name}} <==", out.V{"name": name})
+		if !out.JSON {
+			out.Styled(style.Empty, "==> {{.name}} <==", out.V{"name": name})
+		}
 		var b bytes.Buffer
 		c := exec.Command("/bin/bash", "-c", cmds[name])
 		c.Stdout = &b
@@ -191,6 +179,10 @@ func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.Cluster
 			failed = append(failed, name)
 			continue
 		}
+		if out.JSON {
+			printLogLines(name, &b)
+			continue
+		}
 		l := ""
 		scanner := bufio.NewScanner(&b)
 		for scanner.Scan() {
@@ -205,22 +197,38 @@ func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.Cluster
 	return nil
 }
 
+// printLogLines prints every line read from r as a log event of source, for the JSON output
+func printLogLines(source string, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		register.PrintLogLine(source, scanner.Text())
+	}
+}
+
 // outputAudit displays the audit logs.
 func outputAudit(lines int) error {
-	out.Styled(style.Empty, "")
-	out.Styled(style.Empty, "==> Audit <==")
+	if !out.JSON {
+		out.Styled(style.Empty, "")
+		out.Styled(style.Empty, "==> Audit <==")
+	}
 	r, err := audit.Report(lines)
 	if err != nil {
 		return fmt.Errorf("failed to create audit report: %v", err)
 	}
+	if out.JSON {
+		printLogLines("audit", strings.NewReader(r.ASCIITable()))
+		return nil
+	}
 	out.Styled(style.Empty, r.ASCIITable())
 	return nil
 }
 
 // outputLastStart outputs the last start logs.
 func outputLastStart() error {
-	out.Styled(style.Empty, "")
-	out.Styled(style.Empty, "==> Last Start <==")
+	if !out.JSON {
+		out.Styled(style.Empty, "")
+		out.Styled(style.Empty, "==> Last Start <==")
+	}
 	fp := localpath.LastStartLog()
 	f, err := os.Open(fp)
 	if os.IsNotExist(err) {
@@ -232,6 +240,10 @@ func outputLastStart() error {
 		return fmt.Errorf("failed to open file %s: %v", fp, err)
 	}
 	defer f.Close()
+	if out.JSON {
+		printLogLines("last start", f)
+		return nil
+	}
 	l := ""
 	s := bufio.NewScanner(f)
 	for s.Scan() {
@@ -255,12 +267,14 @@ func OutputOffline(lines int, logOutput *os.File) {
 		klog.Errorf("failed to output last start logs: %v", err)
 	}
 
-	out.Styled(style.Empty, "")
+	if !out.JSON {
+		out.Styled(style.Empty, "")
+	}
 }
 
 // logCommands returns a list of commands that would be run to receive the anticipated logs
-func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, length int, follow bool) map[string]string {
-	cmds := bs.LogCommands(cfg, bootstrapper.LogOptions{Lines: length, Follow: follow})
+func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, length int) map[string]string {
+	cmds := bs.LogCommands(cfg, bootstrapper.LogOptions{Lines: length})
 	for _, pod := range importantPods {
 		ids, err := r.ListContainers(cruntime.ListContainersOptions{Name: pod})
 		if err != nil {
@@ -274,11 +288,49 @@ func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.Cl
 		}
 		for _, i := range ids {
 			key := fmt.Sprintf("%s [%s]", pod, i)
-			cmds[key] = r.ContainerLogCmd(i, length, follow)
+			cmds[key] = r.ContainerLogCmd(i, length, false)
 		}
 	}
-	cmds[r.Name()] = r.SystemLogCmd(length)
+	cmds[r.Name()] = r.SystemLogCmd(length, false)
 	cmds["container status"] = cruntime.ContainerStatusCommand()
 
 	return cmds
 }
+
+// matchesComponent returns whether the logs of source are selected by components, all of them are when there are none.
+// A component selects the sources of the same name, ignoring the kube- prefix and the container ID:
+// "apiserver" selects "kube-apiserver [0123456789ab]".
+func matchesComponent(source string, components []string) bool {
+	if len(components) == 0 {
+		return true
+	}
+	name := strings.SplitN(source, " [", 2)[0]
+	for _, c := range components {
+		if c == name || "kube-"+c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateComponents returns an error if a component does not select the logs of any source
+func ValidateComponents(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, components []string) error {
+	sources := append([]string{r.Name(), "container status", "kernel"}, importantPods...)
+	for name := range bs.LogCommands(cfg, bootstrapper.LogOptions{}) {
+		sources = append(sources, name)
+	}
+	sort.Strings(sources)
+	for _, c := range components {
+		found := false
+		for _, s := range sources {
+			if matchesComponent(s, []string{c}) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("unknown component %q, valid components are: %s", c, strings.Join(sources, ", "))
+		}
+	}
+	return nil
+}
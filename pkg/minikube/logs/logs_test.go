@@ -61,3 +61,25 @@ func TestIsProblem(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchesComponent(t *testing.T) {
+	var tests = []struct {
+		source     string
+		components []string
+		want       bool
+	}{
+		{"kubelet", nil, true},
+		{"kubelet", []string{"kubelet"}, true},
+		{"kube-apiserver [0123456789ab]", []string{"kubelet", "apiserver"}, true},
+		{"kube-apiserver [0123456789ab]", []string{"kube-apiserver"}, true},
+		{"storage-provisioner [0123456789ab]", []string{"storage-provisioner"}, true},
+		{"kube-scheduler [0123456789ab]", []string{"apiserver"}, false},
+		{"describe nodes", []string{"kubelet"}, false},
+	}
+	for _, tc := range tests {
+		got := matchesComponent(tc.source, tc.components)
+		if got != tc.want {
+			t.Errorf("matchesComponent(%q, %v) = %v, want %v", tc.source, tc.components, got, tc.want)
+		}
+	}
+}
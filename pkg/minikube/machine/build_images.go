@@ -17,6 +17,7 @@ limitations under the License.
 package machine
 
 import (
+	"fmt"
 	"net/url"
 	"os"
 	"os/exec"
@@ -39,8 +40,11 @@ import (
 // buildRoot is where images should be built from within the guest VM
 var buildRoot = path.Join(vmpath.GuestPersistentDir, "build")
 
+// secretsRoot is where build secrets are copied to within the guest VM, for the duration of the build
+var secretsRoot = path.Join(buildRoot, "secrets")
+
 // BuildImage builds image to all profiles
-func BuildImage(path string, file string, tag string, push bool, env []string, opt []string, profiles []*config.Profile) error {
+func BuildImage(path string, file string, tag string, push bool, env []string, opt []string, platform string, secrets []string, profiles []*config.Profile) error {
 	api, err := NewAPIClient()
 	if err != nil {
 		return errors.Wrap(err, "api")
@@ -91,10 +95,17 @@ func BuildImage(path string, file string, tag string, push bool, env []string, o
 				if err != nil {
 					return err
 				}
+				nodeSecrets, err := transferSecrets(cr, secrets)
+				if err != nil {
+					return err
+				}
 				if remote {
-					err = buildImage(cr, c.KubernetesConfig, path, file, tag, push, env, opt)
+					err = buildImage(cr, c.KubernetesConfig, path, file, tag, push, env, opt, platform, nodeSecrets)
 				} else {
-					err = transferAndBuildImage(cr, c.KubernetesConfig, path, file, tag, push, env, opt)
+					err = transferAndBuildImage(cr, c.KubernetesConfig, path, file, tag, push, env, opt, platform, nodeSecrets)
+				}
+				if len(nodeSecrets) > 0 {
+					removeSecrets(cr)
 				}
 				if err != nil {
 					failed = append(failed, m)
@@ -112,14 +123,14 @@ func BuildImage(path string, file string, tag string, push bool, env []string, o
 }
 
 // buildImage builds a single image
-func buildImage(cr command.Runner, k8s config.KubernetesConfig, src string, file string, tag string, push bool, env []string, opt []string) error {
+func buildImage(cr command.Runner, k8s config.KubernetesConfig, src string, file string, tag string, push bool, env []string, opt []string, platform string, secrets []string) error {
 	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
 	if err != nil {
 		return errors.Wrap(err, "runtime")
 	}
 	klog.Infof("Building image from url: %s", src)
 
-	err = r.BuildImage(src, file, tag, push, env, opt)
+	err = r.BuildImage(src, file, tag, push, env, opt, platform, secrets)
 	if err != nil {
 		return errors.Wrapf(err, "%s build %s", r.Name(), src)
 	}
@@ -129,7 +140,7 @@ func buildImage(cr command.Runner, k8s config.KubernetesConfig, src string, file
 }
 
 // transferAndBuildImage transfers and builds a single image
-func transferAndBuildImage(cr command.Runner, k8s config.KubernetesConfig, src string, file string, tag string, push bool, env []string, opt []string) error {
+func transferAndBuildImage(cr command.Runner, k8s config.KubernetesConfig, src string, file string, tag string, push bool, env []string, opt []string, platform string, secrets []string) error {
 	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
 	if err != nil {
 		return errors.Wrap(err, "runtime")
@@ -176,7 +187,7 @@ func transferAndBuildImage(cr command.Runner, k8s config.KubernetesConfig, src s
 	if file != "" && !path.IsAbs(file) {
 		file = path.Join(context, file)
 	}
-	err = r.BuildImage(context, file, tag, push, env, opt)
+	err = r.BuildImage(context, file, tag, push, env, opt, platform, secrets)
 	if err != nil {
 		return errors.Wrapf(err, "%s build %s", r.Name(), dst)
 	}
@@ -193,3 +204,56 @@ func transferAndBuildImage(cr command.Runner, k8s config.KubernetesConfig, src s
 	klog.Infof("Built %s from %s", tag, src)
 	return nil
 }
+
+// ParseSecret parses a build secret of the form id=mysecret,src=/local/secret into its id and source file
+func ParseSecret(secret string) (string, string, error) {
+	var id, src string
+	for _, field := range strings.Split(secret, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return "", "", errors.Errorf("invalid secret %q, expected id=mysecret,src=/local/secret", secret)
+		}
+		switch kv[0] {
+		case "id":
+			id = kv[1]
+		case "src", "source":
+			src = kv[1]
+		default:
+			return "", "", errors.Errorf("invalid secret %q, unknown key %q", secret, kv[0])
+		}
+	}
+	if id == "" || src == "" {
+		return "", "", errors.Errorf("invalid secret %q, expected id=mysecret,src=/local/secret", secret)
+	}
+	return id, src, nil
+}
+
+// transferSecrets copies the build secrets to the guest VM, and returns them pointing to their copies
+func transferSecrets(cr command.Runner, secrets []string) ([]string, error) {
+	var transferred []string
+	for _, secret := range secrets {
+		id, src, err := ParseSecret(secret)
+		if err != nil {
+			return nil, err
+		}
+		f, err := assets.NewFileAsset(src, secretsRoot, id, "0600")
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading secret %s", id)
+		}
+		err = cr.Copy(f)
+		f.Close()
+		if err != nil {
+			removeSecrets(cr)
+			return nil, errors.Wrapf(err, "transferring secret %s", id)
+		}
+		transferred = append(transferred, fmt.Sprintf("id=%s,src=%s", id, path.Join(secretsRoot, id)))
+	}
+	return transferred, nil
+}
+
+// removeSecrets removes the build secrets from the guest VM
+func removeSecrets(cr command.Runner) {
+	if _, err := cr.RunCmd(exec.Command("sudo", "rm", "-rf", secretsRoot)); err != nil {
+		klog.Warningf("failed to remove build secrets: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import "testing"
+
+func TestParseSecret(t *testing.T) {
+	tests := []struct {
+		secret  string
+		id      string
+		src     string
+		wantErr bool
+	}{
+		{secret: "id=npmrc,src=/home/me/.npmrc", id: "npmrc", src: "/home/me/.npmrc"},
+		{secret: "source=/tmp/token,id=token", id: "token", src: "/tmp/token"},
+		{secret: "id=token", wantErr: true},
+		{secret: "src=/tmp/token", wantErr: true},
+		{secret: "id=token,src=/tmp/token,env=TOKEN", wantErr: true},
+		{secret: "token", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.secret, func(t *testing.T) {
+			id, src, err := ParseSecret(tc.secret)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseSecret(%q) error = %v, wantErr %v", tc.secret, err, tc.wantErr)
+			}
+			if id != tc.id || src != tc.src {
+				t.Errorf("ParseSecret(%q) = %q, %q, want %q, %q", tc.secret, id, src, tc.id, tc.src)
+			}
+		})
+	}
+}
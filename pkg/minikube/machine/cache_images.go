@@ -18,16 +18,21 @@ package machine
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/docker/docker/client"
+	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/state"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/klog/v2"
@@ -39,14 +44,19 @@ import (
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/image"
 	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/vmpath"
 )
 
 // loadRoot is where images should be loaded from within the guest VM
 var loadRoot = path.Join(vmpath.GuestPersistentDir, "images")
 
-// loadImageLock is used to serialize image loads to avoid overloading the guest VM
-var loadImageLock sync.Mutex
+// loadImageLocks are used to serialize the image loads of every node to avoid overloading the guest VM, they are keyed by machine name
+var loadImageLocks sync.Map
+
+// maxNodeTransfers is how many images are transferred to a node at the same time
+const maxNodeTransfers = 4
 
 // CacheImagesForBootstrapper will cache images for a bootstrapper
 func CacheImagesForBootstrapper(imageRepository string, version string, clusterBootstrapper string) error {
@@ -62,8 +72,8 @@ func CacheImagesForBootstrapper(imageRepository string, version string, clusterB
 	return nil
 }
 
-// LoadCachedImages loads previously cached images into the container runtime
-func LoadCachedImages(cc *config.ClusterConfig, runner command.Runner, images []string, cacheDir string, overwrite bool) error {
+// LoadCachedImages loads previously cached images into the container runtime of a machine
+func LoadCachedImages(cc *config.ClusterConfig, machineName string, runner command.Runner, images []string, cacheDir string, overwrite bool) error {
 	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
 	if err != nil {
 		return errors.Wrap(err, "runtime")
@@ -82,6 +92,15 @@ func LoadCachedImages(cc *config.ClusterConfig, runner command.Runner, images []
 		klog.Infof("LoadImages completed in %s", time.Since(start))
 	}()
 
+	p, err := nodePlatform(runner)
+	if err != nil {
+		return err
+	}
+	// the images were cached for the platform of the host, which may not be the one of the node
+	if err := image.SaveToDir(images, cacheDir, false, p); err != nil {
+		klog.Warningf("failed to cache images for %s: %v", image.PlatformString(p), err)
+	}
+
 	var g errgroup.Group
 
 	var imgClient *client.Client
@@ -92,19 +111,27 @@ func LoadCachedImages(cc *config.ClusterConfig, runner command.Runner, images []
 		}
 	}
 
-	for _, image := range images {
-		image := image
+	sem := make(chan struct{}, maxNodeTransfers)
+	for _, img := range images {
+		img := img
 		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
 			// Put a ten second limit on deciding if an image needs transfer
 			// because it takes much less than that time to just transfer the image.
 			// This is needed because if running in offline mode, we can spend minutes here
 			// waiting for i/o timeout.
-			err := timedNeedsTransfer(imgClient, image, cr, 10*time.Second)
+			err := timedNeedsTransfer(imgClient, img, cr, 10*time.Second)
 			if err == nil {
 				return nil
 			}
-			klog.Infof("%q needs transfer: %v", image, err)
-			return transferAndLoadCachedImage(runner, cc.KubernetesConfig, image, cacheDir)
+			klog.Infof("%q needs transfer: %v", img, err)
+			size, err := image.CachedPlatformImageSize(cacheDir, img, p)
+			if err != nil {
+				return errors.Wrapf(err, "reading %s from the cache", img)
+			}
+			return transferAndLoadCachedImage(runner, machineName, cc.KubernetesConfig, img, cacheDir, p, size)
 		})
 	}
 	if err := g.Wait(); err != nil {
@@ -159,13 +186,16 @@ func needsTransfer(imgClient *client.Client, imgName string, cr cruntime.Manager
 	return nil
 }
 
-// LoadLocalImages loads images into the container runtime
-func LoadLocalImages(cc *config.ClusterConfig, runner command.Runner, images []string) error {
+// LoadLocalImages loads images into the container runtime of a machine
+func LoadLocalImages(cc *config.ClusterConfig, machineName string, runner command.Runner, images []string) error {
 	var g errgroup.Group
+	sem := make(chan struct{}, maxNodeTransfers)
 	for _, image := range images {
 		image := image
 		g.Go(func() error {
-			return transferAndLoadImage(runner, cc.KubernetesConfig, image, image)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			return transferAndLoadImage(runner, machineName, cc.KubernetesConfig, image, image)
 		})
 	}
 	if err := g.Wait(); err != nil {
@@ -175,18 +205,40 @@ func LoadLocalImages(cc *config.ClusterConfig, runner command.Runner, images []s
 	return nil
 }
 
-// CacheAndLoadImages caches and loads images to all profiles
-func CacheAndLoadImages(images []string, profiles []*config.Profile, overwrite bool) error {
+// CacheAndLoadImages caches and loads images to all profiles, for the platform of each node unless platform is given
+func CacheAndLoadImages(images []string, profiles []*config.Profile, overwrite bool, platform *v1.Platform) error {
 	if len(images) == 0 {
 		return nil
 	}
 
+	api, err := NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "api")
+	}
+	defer api.Close()
+
+	targets, failed, err := loadTargets(api, profiles, platform)
+	if err != nil {
+		return err
+	}
+
 	// This is the most important thing
-	if err := image.SaveToDir(images, constants.ImageCacheDir, overwrite); err != nil {
+	if err := image.SaveToDir(images, constants.ImageCacheDir, overwrite, targetPlatforms(targets, platform)...); err != nil {
 		return errors.Wrap(err, "save to dir")
 	}
 
-	return DoLoadImages(images, profiles, constants.ImageCacheDir, overwrite)
+	return loadImages(images, targets, failed, constants.ImageCacheDir, overwrite)
+}
+
+// maxParallelLoads is how many nodes images are loaded into at the same time
+const maxParallelLoads = 4
+
+// loadTarget is a running node to load images into
+type loadTarget struct {
+	cc       *config.ClusterConfig
+	machine  string
+	runner   command.Runner
+	platform v1.Platform
 }
 
 // DoLoadImages loads images to all profiles
@@ -197,8 +249,17 @@ func DoLoadImages(images []string, profiles []*config.Profile, cacheDir string,
 	}
 	defer api.Close()
 
-	succeeded := []string{}
+	targets, failed, err := loadTargets(api, profiles, nil)
+	if err != nil {
+		return err
+	}
+	return loadImages(images, targets, failed, cacheDir, overwrite)
+}
+
+// loadTargets returns the running nodes of profiles with their platform, or the given platform, and the nodes which failed
+func loadTargets(api libmachine.API, profiles []*config.Profile, platform *v1.Platform) ([]loadTarget, []string, error) {
 	failed := []string{}
+	var targets []loadTarget
 
 	for _, p := range profiles { // loading images to all running profiles
 		pName := p.Name // capture the loop variable
@@ -230,24 +291,97 @@ func DoLoadImages(images []string, profiles []*config.Profile, cacheDir string,
 				}
 				cr, err := CommandRunner(h)
 				if err != nil {
-					return err
+					return nil, nil, err
 				}
-				if cacheDir != "" {
-					// loading image names, from cache
-					err = LoadCachedImages(c, cr, images, cacheDir, overwrite)
-				} else {
-					// loading image files
-					err = LoadLocalImages(c, cr, images)
-				}
-				if err != nil {
+				t := loadTarget{cc: c, machine: m, runner: cr}
+				if platform != nil {
+					t.platform = *platform
+				} else if t.platform, err = nodePlatform(cr); err != nil {
+					klog.Warningf("Failed to get the platform of %q: %v", m, err)
 					failed = append(failed, m)
-					klog.Warningf("Failed to load cached images for profile %s. make sure the profile is running. %v", pName, err)
 					continue
 				}
-				succeeded = append(succeeded, m)
+				targets = append(targets, t)
 			}
 		}
 	}
+	return targets, failed, nil
+}
+
+// targetPlatforms returns the platforms of targets, or the given platform
+func targetPlatforms(targets []loadTarget, platform *v1.Platform) []v1.Platform {
+	if platform != nil {
+		return []v1.Platform{*platform}
+	}
+	var ps []v1.Platform
+	for _, t := range targets {
+		ps = append(ps, t.platform)
+	}
+	return ps
+}
+
+// nodePlatform returns the platform of the images a node runs
+func nodePlatform(runner command.Runner) (v1.Platform, error) {
+	rr, err := runner.RunCmd(exec.Command("uname", "-m"))
+	if err != nil {
+		return v1.Platform{}, errors.Wrap(err, "uname")
+	}
+	return image.PlatformFromArch(rr.Stdout.String())
+}
+
+// loadImages loads images to the targets, from the image cache unless cacheDir is empty
+func loadImages(images []string, targets []loadTarget, failed []string, cacheDir string, overwrite bool) error {
+	succeeded := []string{}
+
+	// the cached images of each platform, the images are archives when not loading from the cache
+	cached := map[string]platformImages{}
+	if cacheDir != "" {
+		// size the tarballs once per platform, rather than for every node
+		for _, t := range targets {
+			key := image.PlatformString(t.platform)
+			if _, ok := cached[key]; ok {
+				continue
+			}
+			imgs, sizes, err := cachedImageSizes(images, cacheDir, t.platform)
+			cached[key] = platformImages{images: imgs, sizes: sizes, err: err}
+		}
+	}
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	sem := make(chan struct{}, maxParallelLoads)
+	for _, t := range targets {
+		t := t
+		pi, ok := cached[image.PlatformString(t.platform)]
+		if !ok {
+			pi = platformImages{images: images}
+		}
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var loaded int
+			err := pi.err
+			if err == nil {
+				out.Step(style.Waiting, "Loading {{.count}} image(s) into {{.node}} ...", out.V{"count": len(pi.images), "node": t.machine})
+				loaded, err = loadImagesIntoNode(t, pi, cacheDir, overwrite)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed = append(failed, t.machine)
+				klog.Warningf("Failed to load cached images for profile %s. make sure the profile is running. %v", t.cc.Name, err)
+				out.FailureT("Failed to load images into {{.node}}: {{.error}}", out.V{"node": t.machine, "error": err})
+				return nil
+			}
+			succeeded = append(succeeded, t.machine)
+			out.Step(style.Check, "Loaded {{.loaded}} image(s) into {{.node}}, {{.skipped}} already present", out.V{"loaded": loaded, "skipped": len(pi.images) - loaded, "node": t.machine})
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
 
 	klog.Infof("succeeded pushing to: %s", strings.Join(succeeded, " "))
 	klog.Infof("failed pushing to: %s", strings.Join(failed, " "))
@@ -255,57 +389,154 @@ func DoLoadImages(images []string, profiles []*config.Profile, cacheDir string,
 	return nil
 }
 
-// transferAndLoadCachedImage transfers and loads a single image from the cache
-func transferAndLoadCachedImage(cr command.Runner, k8s config.KubernetesConfig, imgName string, cacheDir string) error {
-	src := filepath.Join(cacheDir, imgName)
-	src = localpath.SanitizeCacheDir(src)
-	return transferAndLoadImage(cr, k8s, src, imgName)
+// platformImages are the images found in the cache for a platform, with the size of their tarballs
+type platformImages struct {
+	images []string
+	sizes  []int64
+	err    error
 }
 
-// transferAndLoadImage transfers and loads a single image
-func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, src string, imgName string) error {
-	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
+// cachedImageSizes returns the images of platform p found in the cache, with the size of the tarballs they are loaded from
+func cachedImageSizes(images []string, cacheDir string, p v1.Platform) ([]string, []int64, error) {
+	var cached []string
+	for _, img := range images {
+		if !image.IsCached(cacheDir, img) {
+			klog.Warningf("skipping %s which is not cached", img)
+			continue
+		}
+		cached = append(cached, img)
+	}
+
+	sizes := make([]int64, len(cached))
+	var g errgroup.Group
+	for i, img := range cached {
+		i, img := i, img
+		g.Go(func() error {
+			size, err := image.CachedPlatformImageSize(cacheDir, img, p)
+			if err != nil {
+				return errors.Wrapf(err, "reading %s from the cache", img)
+			}
+			sizes[i] = size
+			return nil
+		})
+	}
+	return cached, sizes, g.Wait()
+}
+
+// loadImagesIntoNode loads the images into a node, skipping the ones it already has, and returns how many were loaded
+func loadImagesIntoNode(t loadTarget, pi platformImages, cacheDir string, overwrite bool) (int, error) {
+	cr, err := cruntime.New(cruntime.Config{Type: t.cc.KubernetesConfig.ContainerRuntime, Runner: t.runner})
 	if err != nil {
-		return errors.Wrap(err, "runtime")
+		return 0, errors.Wrap(err, "runtime")
 	}
 
-	if err := r.RemoveImage(imgName); err != nil {
-		errStr := strings.ToLower(err.Error())
-		if !strings.Contains(errStr, "no such image") {
-			return errors.Wrap(err, "removing image")
+	var loaded int32
+	var g errgroup.Group
+	sem := make(chan struct{}, maxNodeTransfers)
+	for i := range pi.images {
+		i, img := i, pi.images[i]
+		g.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if !overwrite && imageUpToDate(cr, img, cacheDir, t.platform) {
+				klog.Infof("%s already has %s, skipping", t.machine, img)
+				return nil
+			}
+			var err error
+			if cacheDir != "" {
+				err = transferAndLoadCachedImage(t.runner, t.machine, t.cc.KubernetesConfig, img, cacheDir, t.platform, pi.sizes[i])
+			} else {
+				err = transferAndLoadImage(t.runner, t.machine, t.cc.KubernetesConfig, img, img)
+			}
+			if err != nil {
+				return err
+			}
+			atomic.AddInt32(&loaded, 1)
+			return nil
+		})
+	}
+	err = g.Wait()
+	return int(loaded), err
+}
+
+// imageUpToDate returns whether the container runtime already has the image, with the same digest, img is an archive when not loading from the cache
+func imageUpToDate(cr cruntime.Manager, img string, cacheDir string, p v1.Platform) bool {
+	var digest string
+	if cacheDir != "" {
+		digest = image.DigestByCache(cacheDir, img, p)
+	} else {
+		name, d, err := image.DigestByTarball(img)
+		if err != nil {
+			klog.Infof("unable to get the digest of %s: %v", img, err)
+			return false
 		}
+		img, digest = name, d
 	}
+	return digest != "" && cr.ImageExists(img, digest)
+}
+
+// transferAndLoadCachedImage transfers and loads the image of platform p from the cache, size is the size of its tarball
+func transferAndLoadCachedImage(cr command.Runner, machineName string, k8s config.KubernetesConfig, imgName string, cacheDir string, p v1.Platform, size int64) error {
+	// the cache stores the layers shared by images once, so the image is assembled into a tarball as it is transferred
+	filename := filepath.Base(localpath.SanitizeCacheDir(imgName))
+	f := assets.NewStreamAsset(func(w io.Writer) error {
+		return image.WriteCachedPlatformImage(cacheDir, imgName, p, w)
+	}, int(size), loadRoot, filename, "0644")
+	klog.Infof("Loading image %s from the cache", imgName)
+	return loadImageAsset(cr, machineName, k8s, f, imgName)
+}
 
+// transferAndLoadImage transfers and loads a single image
+func transferAndLoadImage(cr command.Runner, machineName string, k8s config.KubernetesConfig, src string, imgName string) error {
 	klog.Infof("Loading image from: %s", src)
 	filename := filepath.Base(src)
 	if _, err := os.Stat(src); err != nil {
 		return err
 	}
 
-	dst := path.Join(loadRoot, filename)
 	f, err := assets.NewFileAsset(src, loadRoot, filename, "0644")
 	if err != nil {
 		return errors.Wrapf(err, "creating copyable file asset: %s", filename)
 	}
+	return loadImageAsset(cr, machineName, k8s, f, imgName)
+}
+
+// loadImageAsset transfers the image archive f and loads it into the container runtime
+func loadImageAsset(cr command.Runner, machineName string, k8s config.KubernetesConfig, f assets.CopyableFile, imgName string) error {
 	defer func() {
 		if err := f.Close(); err != nil {
 			klog.Warningf("error closing the file %s: %v", f.GetSourcePath(), err)
 		}
 	}()
 
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+
+	if err := r.RemoveImage(imgName); err != nil {
+		errStr := strings.ToLower(err.Error())
+		if !strings.Contains(errStr, "no such image") {
+			return errors.Wrap(err, "removing image")
+		}
+	}
+
+	dst := path.Join(f.GetTargetDir(), f.GetTargetName())
 	if err := cr.Copy(f); err != nil {
 		return errors.Wrap(err, "transferring cached image")
 	}
 
-	loadImageLock.Lock()
-	defer loadImageLock.Unlock()
+	l, _ := loadImageLocks.LoadOrStore(machineName, &sync.Mutex{})
+	l.(*sync.Mutex).Lock()
+	defer l.(*sync.Mutex).Unlock()
 
 	err = r.LoadImage(dst)
 	if err != nil {
 		return errors.Wrapf(err, "%s load %s", r.Name(), dst)
 	}
 
-	klog.Infof("Transferred and loaded %s from cache", src)
+	klog.Infof("Transferred and loaded %s", imgName)
 	return nil
 }
 
@@ -471,11 +702,11 @@ func RemoveImages(images []string, profile *config.Profile) error {
 	return nil
 }
 
-// ListImages lists images on all nodes in profile
-func ListImages(profile *config.Profile) error {
+// ListImages returns the images on all nodes in profile
+func ListImages(profile *config.Profile) ([]string, error) {
 	api, err := NewAPIClient()
 	if err != nil {
-		return errors.Wrap(err, "error creating api client")
+		return nil, errors.Wrap(err, "error creating api client")
 	}
 	defer api.Close()
 
@@ -484,9 +715,12 @@ func ListImages(profile *config.Profile) error {
 	c, err := config.Load(pName)
 	if err != nil {
 		klog.Errorf("Failed to load profile %q: %v", pName, err)
-		return errors.Wrapf(err, "error loading config for profile :%v", pName)
+		return nil, errors.Wrapf(err, "error loading config for profile :%v", pName)
 	}
 
+	images := []string{}
+	seen := map[string]bool{}
+
 	for _, n := range c.Nodes {
 		m := config.MachineName(*c, n)
 
@@ -504,21 +738,26 @@ func ListImages(profile *config.Profile) error {
 			}
 			runner, err := CommandRunner(h)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			cr, err := cruntime.New(cruntime.Config{Type: c.KubernetesConfig.ContainerRuntime, Runner: runner})
 			if err != nil {
-				return errors.Wrap(err, "error creating container runtime")
+				return nil, errors.Wrap(err, "error creating container runtime")
 			}
 			list, err := cr.ListImages(cruntime.ListImagesOptions{})
 			if err != nil {
 				klog.Warningf("Failed to list images for profile %s %v", pName, err.Error())
 				continue
 			}
-			sort.Sort(sort.Reverse(sort.StringSlice(list)))
-			fmt.Printf(strings.Join(list, "\n") + "\n")
+			for _, img := range list {
+				if !seen[img] {
+					seen[img] = true
+					images = append(images, img)
+				}
+			}
 		}
 	}
 
-	return nil
+	sort.Sort(sort.Reverse(sort.StringSlice(images)))
+	return images, nil
 }
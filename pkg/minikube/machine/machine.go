@@ -24,6 +24,7 @@ import (
 	libprovision "github.com/docker/machine/libmachine/provision"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/provision"
@@ -123,5 +124,13 @@ func saveHost(api libmachine.API, h *host.Host, cfg *config.ClusterConfig, n *co
 		return err
 	}
 	n.IP = ip
+
+	if config.HasIPv6(*cfg) && driver.IsKIC(h.DriverName) {
+		_, ipv6, err := oci.ContainerIPs(h.DriverName, h.Name)
+		if err != nil {
+			return errors.Wrap(err, "getting IPv6 address")
+		}
+		n.IPv6 = ipv6
+	}
 	return config.SaveNode(cfg, n)
 }
@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/prune"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// hostpathProvisionerDir is where the storage provisioner creates the directories of persistent volumes
+const hostpathProvisionerDir = "/tmp/hostpath-provisioner"
+
+// PruneItems returns what can be pruned on the running nodes of a cluster, for the categories
+func PruneItems(api libmachine.API, cc *config.ClusterConfig, categories map[prune.Category]bool) ([]prune.Item, error) {
+	var volumes map[string]bool
+	if categories[prune.Volumes] {
+		cp, err := config.PrimaryControlPlane(cc)
+		if err != nil {
+			return nil, err
+		}
+		runner, err := pruneRunner(api, config.MachineName(*cc, cp))
+		if err != nil {
+			return nil, err
+		}
+		// without the persistent volumes, the directories which are still in use are not known
+		if runner != nil {
+			if volumes, err = hostPathVolumes(cc, runner); err != nil {
+				return nil, errors.Wrap(err, "listing the persistent volumes")
+			}
+		}
+	}
+
+	var items []prune.Item
+	for _, n := range cc.Nodes {
+		m := config.MachineName(*cc, n)
+		runner, err := pruneRunner(api, m)
+		if err != nil {
+			return nil, err
+		}
+		if runner == nil {
+			continue
+		}
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			return nil, errors.Wrap(err, "runtime")
+		}
+
+		if categories[prune.Images] {
+			imgs, err := unusedImageItems(cc, cr, m)
+			if err != nil {
+				return nil, errors.Wrapf(err, "listing the unused images of %s", m)
+			}
+			items = append(items, imgs...)
+		}
+		if categories[prune.BuildCache] {
+			size, err := cr.BuildCacheSize()
+			if err != nil {
+				return nil, errors.Wrapf(err, "measuring the build cache of %s", m)
+			}
+			if size > 0 {
+				items = append(items, prune.NewItem(prune.BuildCache, m, "build cache", size, cr.PruneBuildCache))
+			}
+		}
+		if volumes != nil {
+			dirs, err := orphanedVolumeItems(runner, m, volumes)
+			if err != nil {
+				return nil, errors.Wrapf(err, "listing the volumes of %s", m)
+			}
+			items = append(items, dirs...)
+		}
+	}
+	return items, nil
+}
+
+// pruneRunner returns the command runner of a machine, or nil if it is not running
+func pruneRunner(api libmachine.API, machine string) (command.Runner, error) {
+	status, err := Status(api, machine)
+	if err != nil || status != state.Running.String() {
+		klog.Infof("skipping %s which is not running: %s %v", machine, status, err)
+		return nil, nil
+	}
+	h, err := api.Load(machine)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading %s", machine)
+	}
+	return CommandRunner(h)
+}
+
+// unusedImageItems returns the images of a node which no container uses, except the ones Kubernetes needs
+func unusedImageItems(cc *config.ClusterConfig, cr cruntime.Manager, machine string) ([]prune.Item, error) {
+	required, err := bootstrapper.GetCachedImageList(cc.KubernetesConfig.ImageRepository, cc.KubernetesConfig.KubernetesVersion, bootstrapper.Kubeadm)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing the images of Kubernetes")
+	}
+	keep := map[string]bool{}
+	for _, img := range required {
+		keep[shortImageName(img)] = true
+	}
+
+	imgs, err := cr.UnusedImages()
+	if err != nil {
+		return nil, err
+	}
+	var items []prune.Item
+	for _, img := range imgs {
+		needed := false
+		for _, tag := range img.Tags {
+			needed = needed || keep[shortImageName(tag)]
+		}
+		if needed {
+			continue
+		}
+		names := img.Names()
+		items = append(items, prune.NewItem(prune.Images, machine, strings.Join(names, ", "), img.Size, func() error {
+			for _, name := range names {
+				if err := cr.RemoveImage(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	}
+	return items, nil
+}
+
+// shortImageName returns the name of an image without the default docker.io registry
+func shortImageName(img string) string {
+	img = strings.TrimPrefix(img, "docker.io/")
+	return strings.TrimPrefix(img, "library/")
+}
+
+// hostPathVolumes returns the paths of the hostPath persistent volumes of the cluster
+func hostPathVolumes(cc *config.ClusterConfig, runner command.Runner) (map[string]bool, error) {
+	kubectl := kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion)
+	rr, err := runner.RunCmd(exec.Command("sudo", fmt.Sprintf("KUBECONFIG=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig")), kubectl,
+		"get", "pv", "-o", `jsonpath={range .items[*]}{.spec.hostPath.path}{"\n"}{end}`))
+	if err != nil {
+		return nil, err
+	}
+	volumes := map[string]bool{}
+	for _, p := range strings.Fields(rr.Stdout.String()) {
+		volumes[path.Clean(p)] = true
+	}
+	return volumes, nil
+}
+
+// orphanedVolumeItems returns the directories of the storage provisioner of a node which are not the path of a persistent volume
+// the directories are created as <namespace>/<claim> within hostpathProvisionerDir
+func orphanedVolumeItems(runner command.Runner, machine string, volumes map[string]bool) ([]prune.Item, error) {
+	if _, err := runner.RunCmd(exec.Command("sudo", "test", "-d", hostpathProvisionerDir)); err != nil {
+		return nil, nil
+	}
+	rr, err := runner.RunCmd(exec.Command("sudo", "find", hostpathProvisionerDir, "-mindepth", "2", "-maxdepth", "2", "-type", "d", "-exec", "du", "-sk", "{}", "+"))
+	if err != nil {
+		return nil, err
+	}
+	usage, err := parseDiskUsage(rr.Stdout.String())
+	if err != nil {
+		return nil, err
+	}
+	var items []prune.Item
+	for dir, size := range usage {
+		if volumes[dir] {
+			continue
+		}
+		dir := dir
+		items = append(items, prune.NewItem(prune.Volumes, machine, dir, size, func() error {
+			_, err := runner.RunCmd(exec.Command("sudo", "rm", "-rf", dir))
+			return err
+		}))
+	}
+	return items, nil
+}
+
+// parseDiskUsage parses the output of 'du -sk', and returns the size in bytes of each path
+func parseDiskUsage(output string) (map[string]int64, error) {
+	usage := map[string]int64{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %q", line)
+		}
+		usage[path.Clean(fields[1])] = kb * 1024
+	}
+	return usage, nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDiskUsage(t *testing.T) {
+	output := "4\t/tmp/hostpath-provisioner/default/data\n1024\t/tmp/hostpath-provisioner/default/old claim/\n\n"
+	got, err := parseDiskUsage(output)
+	if err != nil {
+		t.Fatalf("parseDiskUsage: %v", err)
+	}
+	want := map[string]int64{
+		"/tmp/hostpath-provisioner/default/data":      4096,
+		"/tmp/hostpath-provisioner/default/old claim": 1048576,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseDiskUsage() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestShortImageName(t *testing.T) {
+	tests := map[string]string{
+		"docker.io/library/nginx:latest":             "nginx:latest",
+		"docker.io/kubernetesui/dashboard:v2":        "kubernetesui/dashboard:v2",
+		"k8s.gcr.io/pause:3.4.1":                     "k8s.gcr.io/pause:3.4.1",
+		"gcr.io/k8s-minikube/storage-provisioner:v5": "gcr.io/k8s-minikube/storage-provisioner:v5",
+	}
+	for in, want := range tests {
+		if got := shortImageName(in); got != want {
+			t.Errorf("shortImageName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
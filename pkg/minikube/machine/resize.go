@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+)
+
+// Resources are the CPUs, and the memory and disk size in MB, of a machine. Those which are 0 are unchanged.
+type Resources struct {
+	CPUs     int
+	Memory   int
+	DiskSize int
+}
+
+// CheckResize returns an error if the driver can't change the resources of a machine from current to wanted
+func CheckResize(driverName string, current Resources, wanted Resources) error {
+	if !driver.IsKIC(driverName) && driverName != driver.KVM2 && driverName != driver.HyperKit {
+		return errors.Errorf("the %s driver does not support resizing nodes", driverName)
+	}
+	if wanted.DiskSize == 0 || wanted.DiskSize == current.DiskSize {
+		return nil
+	}
+	if driver.IsKIC(driverName) {
+		return errors.Errorf("the disk of %s nodes is the one of the host, and can't be resized", driverName)
+	}
+	if wanted.DiskSize < current.DiskSize {
+		return errors.Errorf("disks can only grow, from %dMB", current.DiskSize)
+	}
+	return nil
+}
+
+// ResizeLive changes the resources of a running machine when its driver can, returning whether it did
+func ResizeLive(cc config.ClusterConfig, n config.Node, r Resources) (bool, error) {
+	name := config.MachineName(cc, n)
+	if driver.IsKIC(cc.Driver) {
+		if err := oci.UpdateContainerResources(cc.Driver, name, r.CPUs, r.Memory); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if cc.Driver != driver.KVM2 || r.DiskSize != 0 {
+		return false, nil
+	}
+
+	// hot-plugging fails beyond the maximums of the domain, which are its size when created
+	for _, args := range virshLiveArgs(name, r) {
+		if err := virsh(cc.KVMQemuURI, args...); err != nil {
+			klog.Infof("unable to hot-plug %s, it will be restarted: %v", name, err)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ResizeStopped changes the resources of a stopped machine, which it will have once started
+func ResizeStopped(api libmachine.API, cc config.ClusterConfig, n config.Node, r Resources) error {
+	name := config.MachineName(cc, n)
+	h, err := LoadHost(api, name)
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+	if err := updateDriverConfig(h, r); err != nil {
+		return errors.Wrap(err, "update driver config")
+	}
+	if err := api.Save(h); err != nil {
+		return errors.Wrap(err, "save host")
+	}
+
+	if cc.Driver == driver.KVM2 {
+		cpus, memory, _ := config.NodeResources(cc, n)
+		for _, args := range virshConfigArgs(name, Resources{CPUs: cpus, Memory: memory}, r) {
+			if err := virsh(cc.KVMQemuURI, args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.DiskSize != 0 {
+		// the guest grows its data partition into the new space when it boots
+		disk, err := diskPath(h.Driver)
+		if err != nil {
+			return errors.Wrap(err, "disk path")
+		}
+		if err := os.Truncate(disk, int64(r.DiskSize)*1024*1024); err != nil {
+			return errors.Wrap(err, "grow disk")
+		}
+		klog.Infof("grew %s to %dMB", disk, r.DiskSize)
+	}
+	return nil
+}
+
+// diskPath returns the path of the disk image of d, as the driver resolves it
+func diskPath(d interface{}) (string, error) {
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	// drivers which choose where their disk goes record it, the others keep it in their store path
+	var cfg struct {
+		DiskPath string
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return "", err
+	}
+	if cfg.DiskPath != "" {
+		return cfg.DiskPath, nil
+	}
+	var base drivers.BaseDriver
+	if err := json.Unmarshal(raw, &base); err != nil {
+		return "", err
+	}
+	if base.StorePath == "" || base.MachineName == "" {
+		return "", errors.New("the driver does not use a disk image")
+	}
+	return pkgdrivers.GetDiskPath(&base), nil
+}
+
+// updateDriverConfig sets the resources in the stored configuration of the driver of h
+func updateDriverConfig(h *host.Host, r Resources) error {
+	raw, err := json.Marshal(h.Driver)
+	if err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	if r.CPUs != 0 {
+		fields["CPU"] = r.CPUs
+	}
+	if r.Memory != 0 {
+		fields["Memory"] = r.Memory
+	}
+	if r.DiskSize != 0 {
+		fields["DiskSize"] = r.DiskSize
+	}
+	if raw, err = json.Marshal(fields); err != nil {
+		return err
+	}
+
+	// the drivers running as plugins hold their configuration in their own process
+	if d, ok := h.Driver.(interface{ SetConfigRaw([]byte) error }); ok {
+		return d.SetConfigRaw(raw)
+	}
+	return json.Unmarshal(raw, h.Driver)
+}
+
+// virshLiveArgs returns the virsh commands hot-plugging the CPUs and memory of a domain
+func virshLiveArgs(domain string, r Resources) [][]string {
+	cmds := [][]string{}
+	if r.CPUs != 0 {
+		cmds = append(cmds, []string{"setvcpus", domain, fmt.Sprint(r.CPUs), "--live", "--config"})
+	}
+	if r.Memory != 0 {
+		cmds = append(cmds, []string{"setmem", domain, fmt.Sprintf("%dM", r.Memory), "--live", "--config"})
+	}
+	return cmds
+}
+
+// virshConfigArgs returns the virsh commands changing the CPUs and memory of a stopped domain, raising maximums before and lowering them after
+func virshConfigArgs(domain string, current Resources, r Resources) [][]string {
+	cmds := [][]string{}
+	if r.CPUs != 0 {
+		max := []string{"setvcpus", domain, fmt.Sprint(r.CPUs), "--maximum", "--config"}
+		set := []string{"setvcpus", domain, fmt.Sprint(r.CPUs), "--config"}
+		if r.CPUs > current.CPUs {
+			cmds = append(cmds, max, set)
+		} else {
+			cmds = append(cmds, set, max)
+		}
+	}
+	if r.Memory != 0 {
+		max := []string{"setmaxmem", domain, fmt.Sprintf("%dM", r.Memory), "--config"}
+		set := []string{"setmem", domain, fmt.Sprintf("%dM", r.Memory), "--config"}
+		if r.Memory > current.Memory {
+			cmds = append(cmds, max, set)
+		} else {
+			cmds = append(cmds, set, max)
+		}
+	}
+	return cmds
+}
+
+func virsh(uri string, args ...string) error {
+	cmd := exec.Command("virsh", append([]string{"-c", uri}, args...)...)
+	klog.Infof("Run: %v", cmd.Args)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "%v: %s", cmd.Args, out)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+)
+
+func TestCheckResize(t *testing.T) {
+	current := Resources{CPUs: 2, Memory: 2200, DiskSize: 20000}
+	tests := []struct {
+		desc    string
+		driver  string
+		wanted  Resources
+		wantErr bool
+	}{
+		{"docker memory", "docker", Resources{Memory: 8192}, false},
+		{"docker disk", "docker", Resources{DiskSize: 40000}, true},
+		{"docker same disk", "docker", Resources{CPUs: 4, DiskSize: 20000}, false},
+		{"kvm2 disk", "kvm2", Resources{DiskSize: 40000}, false},
+		{"kvm2 smaller disk", "kvm2", Resources{DiskSize: 10000}, true},
+		{"hyperkit cpus", "hyperkit", Resources{CPUs: 4}, false},
+		{"virtualbox", "virtualbox", Resources{CPUs: 4}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := CheckResize(tc.driver, current, tc.wanted)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("CheckResize(%s, %+v) = %v, wantErr %v", tc.driver, tc.wanted, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestVirshLiveArgs(t *testing.T) {
+	got := virshLiveArgs("minikube-m02", Resources{CPUs: 4, Memory: 8192})
+	want := [][]string{
+		{"setvcpus", "minikube-m02", "4", "--live", "--config"},
+		{"setmem", "minikube-m02", "8192M", "--live", "--config"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("virshLiveArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestVirshConfigArgs(t *testing.T) {
+	current := Resources{CPUs: 2, Memory: 4096}
+	tests := []struct {
+		desc   string
+		wanted Resources
+		want   [][]string
+	}{
+		{
+			desc:   "grow",
+			wanted: Resources{CPUs: 4, Memory: 8192},
+			want: [][]string{
+				{"setvcpus", "m", "4", "--maximum", "--config"},
+				{"setvcpus", "m", "4", "--config"},
+				{"setmaxmem", "m", "8192M", "--config"},
+				{"setmem", "m", "8192M", "--config"},
+			},
+		},
+		{
+			desc:   "shrink",
+			wanted: Resources{CPUs: 1, Memory: 2048},
+			want: [][]string{
+				{"setvcpus", "m", "1", "--config"},
+				{"setvcpus", "m", "1", "--maximum", "--config"},
+				{"setmem", "m", "2048M", "--config"},
+				{"setmaxmem", "m", "2048M", "--config"},
+			},
+		},
+		{
+			desc:   "disk only",
+			wanted: Resources{DiskSize: 40000},
+			want:   [][]string{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := virshConfigArgs("m", current, tc.wanted)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("virshConfigArgs() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiskPath(t *testing.T) {
+	type kvmDriver struct {
+		*drivers.BaseDriver
+		DiskPath string
+	}
+	tests := []struct {
+		desc    string
+		driver  interface{}
+		want    string
+		wantErr bool
+	}{
+		{"own disk path", &kvmDriver{BaseDriver: &drivers.BaseDriver{MachineName: "m01", StorePath: "/store"}, DiskPath: "/images/m01.img"}, "/images/m01.img", false},
+		{"store path", &drivers.BaseDriver{MachineName: "m01", StorePath: "/store"}, filepath.Join("/store", "machines", "m01", "m01.rawdisk"), false},
+		{"no disk", struct{ Name string }{"docker"}, "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := diskPath(tc.driver)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("diskPath() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("diskPath() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
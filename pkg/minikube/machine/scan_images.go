@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/image"
+)
+
+// scanArchive is where images are saved within the guest VM before being copied to the host for scanning
+const scanArchive = "/tmp/minikube-image-scan.tar"
+
+// ScanImages scans images present in the container runtime of profile, or else in the image cache, with scanner
+func ScanImages(images []string, profile *config.Profile, scanner string) ([]image.Vulnerability, error) {
+	name, scannerPath, err := image.FindScanner(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	api, err := NewAPIClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating api client")
+	}
+	defer api.Close()
+
+	c, err := config.Load(profile.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error loading config for profile :%v", profile.Name)
+	}
+	cp, err := config.PrimaryControlPlane(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting primary control plane")
+	}
+	h, err := api.Load(config.MachineName(*c, cp))
+	if err != nil {
+		return nil, errors.Wrap(err, "loading machine")
+	}
+	runner, err := CommandRunner(h)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: c.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating container runtime")
+	}
+
+	var vulns []image.Vulnerability
+	for _, img := range images {
+		archive, cleanup, err := imageArchive(runner, cr, img)
+		if err != nil {
+			return nil, err
+		}
+		found, err := image.ScanArchive(name, scannerPath, img, archive)
+		cleanup()
+		if err != nil {
+			return nil, errors.Wrapf(err, "scanning %s", img)
+		}
+		vulns = append(vulns, found...)
+	}
+	return vulns, nil
+}
+
+// imageArchive returns the path of an archive of img on the host, saved from the container runtime or else found in the image cache
+func imageArchive(runner command.Runner, cr cruntime.Manager, img string) (string, func(), error) {
+	if err := cr.SaveImage(img, scanArchive); err != nil {
+		klog.Infof("%s not found in the container runtime, looking in the cache: %v", img, err)
+		if !image.IsCached(constants.ImageCacheDir, img) {
+			return "", nil, errors.Errorf("image %s was found neither in the cluster nor in the cache", img)
+		}
+		tmp, err := ioutil.TempFile("", "minikube-scan.*.tar")
+		if err != nil {
+			return "", nil, err
+		}
+		tmp.Close()
+		if err := image.ExportCachedImage(constants.ImageCacheDir, img, tmp.Name()); err != nil {
+			os.Remove(tmp.Name())
+			return "", nil, errors.Wrapf(err, "exporting %s from the cache", img)
+		}
+		return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+	}
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-f", scanArchive)); err != nil {
+			klog.Warningf("failed to remove %s: %v", scanArchive, err)
+		}
+	}()
+
+	tmp, err := ioutil.TempFile("", "minikube-scan.*.tar")
+	if err != nil {
+		return "", nil, err
+	}
+	defer tmp.Close()
+	cleanup := func() { os.Remove(tmp.Name()) }
+
+	c := exec.Command("sudo", "cat", scanArchive)
+	c.Stdout = tmp
+	if _, err := runner.RunCmd(c); err != nil {
+		cleanup()
+		return "", nil, errors.Wrapf(err, "copying %s from the cluster", img)
+	}
+	return tmp.Name(), cleanup, nil
+}
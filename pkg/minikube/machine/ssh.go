@@ -48,14 +48,45 @@ func GetHost(api libmachine.API, cc config.ClusterConfig, n config.Node) (*host.
 	return host, nil
 }
 
+// SSHForwards are the agent and port forwardings of an SSH session
+type SSHForwards struct {
+	// Agent forwards the connection to the local authentication agent
+	Agent bool
+	// Local forwards local ports to the node, in the [bind_address:]port:host:hostport format of 'ssh -L'
+	Local []string
+	// Remote forwards ports of the node to the host, in the [bind_address:]port:host:hostport format of 'ssh -R'
+	Remote []string
+}
+
+// IsSet returns whether any forwarding is requested
+func (f SSHForwards) IsSet() bool {
+	return f.Agent || len(f.Local) > 0 || len(f.Remote) > 0
+}
+
+// Args returns the ssh client arguments enabling the forwardings
+func (f SSHForwards) Args() []string {
+	args := []string{}
+	if f.Agent {
+		args = append(args, "-A")
+	}
+	for _, l := range f.Local {
+		args = append(args, "-L", l)
+	}
+	for _, r := range f.Remote {
+		args = append(args, "-R", r)
+	}
+	return args
+}
+
 // CreateSSHShell creates a new SSH shell / client
-func CreateSSHShell(api libmachine.API, cc config.ClusterConfig, n config.Node, args []string, native bool) error {
+// Forwardings require the external ssh client, which is used for them whatever native says.
+func CreateSSHShell(api libmachine.API, cc config.ClusterConfig, n config.Node, args []string, native bool, fwd SSHForwards) error {
 	host, err := GetHost(api, cc, n)
 	if err != nil {
 		return err
 	}
 
-	if native {
+	if native && !fwd.IsSet() {
 		ssh.SetDefaultClient(ssh.Native)
 	} else {
 		ssh.SetDefaultClient(ssh.External)
@@ -66,9 +97,47 @@ func CreateSSHShell(api libmachine.API, cc config.ClusterConfig, n config.Node,
 	if err != nil {
 		return errors.Wrap(err, "Creating ssh client")
 	}
+	if fwd.IsSet() {
+		ext, ok := client.(*ssh.ExternalClient)
+		if !ok {
+			return fmt.Errorf("forwarding requires the external ssh client, got %T", client)
+		}
+		ext.BaseArgs = append(ext.BaseArgs, fwd.Args()...)
+	}
 	return client.Shell(args...)
 }
 
+// SSHTarget is what external SSH clients need to connect to a node
+type SSHTarget struct {
+	User    string
+	Addr    string
+	Port    int
+	KeyPath string
+}
+
+// GetSSHTarget returns how external SSH clients connect to a node
+func GetSSHTarget(api libmachine.API, cc config.ClusterConfig, n config.Node) (SSHTarget, error) {
+	host, err := GetHost(api, cc, n)
+	if err != nil {
+		return SSHTarget{}, err
+	}
+
+	addr, err := host.Driver.GetSSHHostname()
+	if err != nil {
+		return SSHTarget{}, errors.Wrap(err, "hostname")
+	}
+	port, err := host.Driver.GetSSHPort()
+	if err != nil {
+		return SSHTarget{}, errors.Wrap(err, "port")
+	}
+	return SSHTarget{
+		User:    host.Driver.GetSSHUsername(),
+		Addr:    addr,
+		Port:    port,
+		KeyPath: host.Driver.GetSSHKeyPath(),
+	}, nil
+}
+
 // GetSSHHostAddrPort returns the host address and port for ssh
 func GetSSHHostAddrPort(api libmachine.API, cc config.ClusterConfig, n config.Node) (string, int, error) {
 	host, err := GetHost(api, cc, n)
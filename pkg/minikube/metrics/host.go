@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+)
+
+// HostUsage is the resource usage of the VM or container of a node, including what runs outside Kubernetes
+type HostUsage struct {
+	Name string
+	// CPUPercent is the share of a CPU used, above 100 when more than one is used
+	CPUPercent float64
+	// MemoryUsed and MemoryLimit are in bytes
+	MemoryUsed  int64
+	MemoryLimit int64
+}
+
+// MemoryPercent returns the memory used, as a percentage of the limit of the driver
+func (h HostUsage) MemoryPercent() int64 {
+	return percent(h.MemoryUsed, h.MemoryLimit)
+}
+
+// Host returns the resource usage of the machine of a node: the container for kic drivers, else the machine itself
+func Host(cc config.ClusterConfig, n config.Node, r command.Runner) (*HostUsage, error) {
+	name := config.MachineName(cc, n)
+	if driver.IsKIC(cc.Driver) {
+		s, err := oci.Stats(cc.Driver, name)
+		if err != nil {
+			return nil, err
+		}
+		return &HostUsage{Name: name, CPUPercent: s.CPUPercent, MemoryUsed: s.MemoryUsed, MemoryLimit: s.MemoryLimit}, nil
+	}
+
+	rr, err := r.RunCmd(exec.Command("cat", "/proc/meminfo"))
+	if err != nil {
+		return nil, errors.Wrap(err, "meminfo")
+	}
+	used, limit, err := parseMeminfo(rr.Stdout.String())
+	if err != nil {
+		return nil, err
+	}
+	// sample the cpu times a second apart
+	rr, err = r.RunCmd(exec.Command("/bin/bash", "-c", "head -n1 /proc/stat && sleep 1 && head -n1 /proc/stat && nproc"))
+	if err != nil {
+		return nil, errors.Wrap(err, "cpu stat")
+	}
+	cpu, err := parseCPUStat(rr.Stdout.String())
+	if err != nil {
+		return nil, err
+	}
+	return &HostUsage{Name: name, CPUPercent: cpu, MemoryUsed: used, MemoryLimit: limit}, nil
+}
+
+// parseMeminfo returns the memory used and the total memory in /proc/meminfo, in bytes
+func parseMeminfo(s string) (int64, int64, error) {
+	kb := map[string]int64{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		kb[strings.TrimSuffix(fields[0], ":")] = v
+	}
+	total, ok := kb["MemTotal"]
+	if !ok {
+		return 0, 0, fmt.Errorf("no MemTotal in meminfo")
+	}
+	available, ok := kb["MemAvailable"]
+	if !ok {
+		return 0, 0, fmt.Errorf("no MemAvailable in meminfo")
+	}
+	return (total - available) * 1024, total * 1024, nil
+}
+
+// parseCPUStat returns the cpu usage between two samples of the first line of /proc/stat, followed by the number of cpus
+func parseCPUStat(s string) (float64, error) {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) != 3 {
+		return 0, fmt.Errorf("unexpected cpu stat: %q", s)
+	}
+	busy1, total1, err := cpuTimes(lines[0])
+	if err != nil {
+		return 0, err
+	}
+	busy2, total2, err := cpuTimes(lines[1])
+	if err != nil {
+		return 0, err
+	}
+	cpus, err := strconv.Atoi(strings.TrimSpace(lines[2]))
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing cpu count %q", lines[2])
+	}
+	if total2 <= total1 {
+		return 0, nil
+	}
+	return float64(busy2-busy1) / float64(total2-total1) * 100 * float64(cpus), nil
+}
+
+// cpuTimes returns the busy and total times of a "cpu" line of /proc/stat
+func cpuTimes(line string) (int64, int64, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("unexpected cpu stat line: %q", line)
+	}
+	// guest times are already counted in user times
+	times := fields[1:]
+	if len(times) > 8 {
+		times = times[:8]
+	}
+	var busy, total int64
+	for i, f := range times {
+		v, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "parsing cpu stat %q", line)
+		}
+		total += v
+		// idle and iowait
+		if i != 3 && i != 4 {
+			busy += v
+		}
+	}
+	return busy, total, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+)
+
+func TestParseMeminfo(t *testing.T) {
+	meminfo := `MemTotal:        2035504 kB
+MemFree:          112280 kB
+MemAvailable:     835504 kB
+Buffers:           45296 kB
+`
+	used, limit, err := parseMeminfo(meminfo)
+	if err != nil {
+		t.Fatalf("parseMeminfo: %v", err)
+	}
+	if used != 1200000*1024 || limit != 2035504*1024 {
+		t.Errorf("parseMeminfo = %d, %d, want %d, %d", used, limit, 1200000*1024, 2035504*1024)
+	}
+
+	if _, _, err := parseMeminfo("MemTotal:        2035504 kB\n"); err == nil {
+		t.Errorf("parseMeminfo succeeded without MemAvailable")
+	}
+}
+
+func TestParseCPUStat(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		stat    string
+		want    float64
+		wantErr bool
+	}{
+		{"half busy on two cpus", "cpu  100 0 100 700 100 0 0 0 0 0\ncpu  150 0 150 750 150 0 0 0 0 0\n2\n", 100, false},
+		{"idle", "cpu  100 0 100 700 100 0 0 0 0 0\ncpu  100 0 100 800 100 0 0 0 0 0\n4\n", 0, false},
+		{"guest counted in user", "cpu  100 0 100 700 100 0 0 0 50 0\ncpu  150 0 150 750 150 0 0 0 100 0\n1\n", 50, false},
+		{"no cpu count", "cpu  100 0 100 700 100 0 0 0 0 0\ncpu  150 0 150 750 150 0 0 0 0 0\n", 0, true},
+		{"not a cpu line", "intr 1 2 3 4 5\ncpu  150 0 150 750 150 0 0 0 0 0\n2\n", 0, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := parseCPUStat(tc.stat)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseCPUStat error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("parseCPUStat = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
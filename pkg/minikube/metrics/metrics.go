@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics reads the resource usage of the cluster, from metrics-server and from the driver
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// metricsAPI is the path of the resource metrics API served by metrics-server
+const metricsAPI = "/apis/metrics.k8s.io/v1beta1"
+
+// NodeUsage is the resource usage of a Kubernetes node
+type NodeUsage struct {
+	Name string
+	// CPU is in millicores
+	CPU        int64
+	CPUPercent int64
+	// Memory is in bytes
+	Memory        int64
+	MemoryPercent int64
+}
+
+// PodUsage is the resource usage of a pod, summed over its containers
+type PodUsage struct {
+	Namespace string
+	Name      string
+	// CPU is in millicores
+	CPU int64
+	// Memory is in bytes
+	Memory int64
+}
+
+// metricsList is a NodeMetricsList or a PodMetricsList of the metrics.k8s.io API
+type metricsList struct {
+	Items []struct {
+		Metadata   meta.ObjectMeta   `json:"metadata"`
+		Usage      core.ResourceList `json:"usage"`
+		Containers []struct {
+			Usage core.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// WaitForAPI waits until metrics-server has scraped the nodes, which takes a minute after it starts
+func WaitForAPI(c kubernetes.Interface, timeout time.Duration) error {
+	start := time.Now()
+	err := wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		usages, err := Nodes(c)
+		if err != nil {
+			klog.Infof("metrics API not available yet: %v", err)
+			return false, nil
+		}
+		return len(usages) > 0, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "waiting for the metrics API")
+	}
+	klog.Infof("duration metric: took %s to wait for the metrics API", time.Since(start))
+	return nil
+}
+
+// Nodes returns the resource usage of the nodes, relative to what they can allocate to pods
+func Nodes(c kubernetes.Interface) ([]NodeUsage, error) {
+	raw, err := c.Discovery().RESTClient().Get().AbsPath(metricsAPI, "nodes").DoRaw(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "node metrics")
+	}
+	nodes, err := c.CoreV1().Nodes().List(context.Background(), meta.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list nodes")
+	}
+	return nodeUsages(raw, nodes.Items)
+}
+
+// Pods returns the resource usage of the pods in namespace, or in all namespaces when it is empty
+func Pods(c kubernetes.Interface, namespace string) ([]PodUsage, error) {
+	path := []string{metricsAPI, "pods"}
+	if namespace != "" {
+		path = []string{metricsAPI, "namespaces", namespace, "pods"}
+	}
+	raw, err := c.Discovery().RESTClient().Get().AbsPath(path...).DoRaw(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "pod metrics")
+	}
+	return podUsages(raw)
+}
+
+func nodeUsages(raw []byte, nodes []core.Node) ([]NodeUsage, error) {
+	var l metricsList
+	if err := json.Unmarshal(raw, &l); err != nil {
+		return nil, errors.Wrap(err, "unmarshal node metrics")
+	}
+	allocatable := map[string]core.ResourceList{}
+	for _, n := range nodes {
+		allocatable[n.Name] = n.Status.Allocatable
+	}
+
+	usages := []NodeUsage{}
+	for _, m := range l.Items {
+		u := NodeUsage{Name: m.Metadata.Name, CPU: m.Usage.Cpu().MilliValue(), Memory: m.Usage.Memory().Value()}
+		a, ok := allocatable[u.Name]
+		if !ok {
+			return nil, fmt.Errorf("no node named %q", u.Name)
+		}
+		u.CPUPercent = percent(u.CPU, a.Cpu().MilliValue())
+		u.MemoryPercent = percent(u.Memory, a.Memory().Value())
+		usages = append(usages, u)
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Name < usages[j].Name })
+	return usages, nil
+}
+
+func podUsages(raw []byte) ([]PodUsage, error) {
+	var l metricsList
+	if err := json.Unmarshal(raw, &l); err != nil {
+		return nil, errors.Wrap(err, "unmarshal pod metrics")
+	}
+	usages := []PodUsage{}
+	for _, m := range l.Items {
+		u := PodUsage{Namespace: m.Metadata.Namespace, Name: m.Metadata.Name}
+		for _, c := range m.Containers {
+			u.CPU += c.Usage.Cpu().MilliValue()
+			u.Memory += c.Usage.Memory().Value()
+		}
+		usages = append(usages, u)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Namespace != usages[j].Namespace {
+			return usages[i].Namespace < usages[j].Namespace
+		}
+		return usages[i].Name < usages[j].Name
+	})
+	return usages, nil
+}
+
+// percent returns used as a percentage of total, or 0 when total is unknown
+func percent(used int64, total int64) int64 {
+	if total == 0 {
+		return 0
+	}
+	return used * 100 / total
+}
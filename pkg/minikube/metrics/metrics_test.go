@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeUsages(t *testing.T) {
+	raw := []byte(`{"kind":"NodeMetricsList","items":[
+		{"metadata":{"name":"minikube-m02"},"usage":{"cpu":"50m","memory":"512Mi"}},
+		{"metadata":{"name":"minikube"},"usage":{"cpu":"250574141n","memory":"1Gi"}}]}`)
+	nodes := []core.Node{
+		{ObjectMeta: meta.ObjectMeta{Name: "minikube"}, Status: core.NodeStatus{Allocatable: core.ResourceList{
+			core.ResourceCPU: resource.MustParse("2"), core.ResourceMemory: resource.MustParse("4Gi")}}},
+		{ObjectMeta: meta.ObjectMeta{Name: "minikube-m02"}, Status: core.NodeStatus{Allocatable: core.ResourceList{
+			core.ResourceCPU: resource.MustParse("2"), core.ResourceMemory: resource.MustParse("2Gi")}}},
+	}
+
+	got, err := nodeUsages(raw, nodes)
+	if err != nil {
+		t.Fatalf("nodeUsages: %v", err)
+	}
+	want := []NodeUsage{
+		{Name: "minikube", CPU: 251, CPUPercent: 12, Memory: 1 << 30, MemoryPercent: 25},
+		{Name: "minikube-m02", CPU: 50, CPUPercent: 2, Memory: 512 << 20, MemoryPercent: 25},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("nodeUsages mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := nodeUsages(raw, nodes[:1]); err == nil {
+		t.Errorf("nodeUsages succeeded without the allocatable resources of a node")
+	}
+}
+
+func TestPodUsages(t *testing.T) {
+	raw := []byte(`{"kind":"PodMetricsList","items":[
+		{"metadata":{"name":"web","namespace":"default"},"containers":[
+			{"name":"app","usage":{"cpu":"10m","memory":"20Mi"}},
+			{"name":"sidecar","usage":{"cpu":"5m","memory":"10Mi"}}]},
+		{"metadata":{"name":"coredns","namespace":"kube-system"},"containers":[
+			{"name":"coredns","usage":{"cpu":"3m","memory":"12Mi"}}]},
+		{"metadata":{"name":"api","namespace":"default"},"containers":[]}]}`)
+
+	got, err := podUsages(raw)
+	if err != nil {
+		t.Fatalf("podUsages: %v", err)
+	}
+	want := []PodUsage{
+		{Namespace: "default", Name: "api"},
+		{Namespace: "default", Name: "web", CPU: 15, Memory: 30 << 20},
+		{Namespace: "kube-system", Name: "coredns", CPU: 3, Memory: 12 << 20},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("podUsages mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounts
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/daemon"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// daemonStopTimeout is how long the mount manager gets to unmount everything before being killed
+const daemonStopTimeout = 10 * time.Second
+
+// Daemon is the persisted state of the mount manager of a profile, see 'minikube mount add'
+type Daemon struct {
+	daemon.Process
+	Profile string
+}
+
+// daemonPath returns the path to the state file of the mount manager of a profile
+func daemonPath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "mounts.json")
+}
+
+// DaemonLogFile returns the path to the log file of the mount manager of a profile
+func DaemonLogFile(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "mounts.log")
+}
+
+// LoadDaemon returns the mount manager of a profile, or nil if it was never started
+func LoadDaemon(profile string) (*Daemon, error) {
+	d := &Daemon{}
+	found, err := daemon.Load(daemonPath(profile), d)
+	if err != nil || !found {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DaemonRunning returns whether the mount manager of a profile is running
+func DaemonRunning(profile string) bool {
+	d, err := LoadDaemon(profile)
+	if err != nil {
+		klog.Warningf("unable to load mount manager of %s: %v", profile, err)
+		return false
+	}
+	return d != nil && d.Running()
+}
+
+// StartDaemon runs 'minikube mount manager' for a profile as a detached process, which keeps
+// the mounts of the profile established until StopDaemon is called.
+func StartDaemon(profile string) (*Daemon, error) {
+	existing, err := LoadDaemon(profile)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Running() {
+		return nil, fmt.Errorf("a mount manager is already running for %q with pid %d", profile, existing.Pid)
+	}
+
+	p, err := daemon.Start([]string{"mount", "manager", "--profile", profile, "--alsologtostderr"}, DaemonLogFile(profile))
+	if err != nil {
+		return nil, errors.Wrap(err, "starting mount manager")
+	}
+	d := &Daemon{Process: *p, Profile: profile}
+	if err := daemon.Save(daemonPath(profile), d); err != nil {
+		return nil, errors.Wrap(err, "saving mount manager state")
+	}
+	return d, nil
+}
+
+// RestartDaemon stops the mount manager of a profile if it is running, and starts it again,
+// so that it picks up changes to the mounts of the profile.
+func RestartDaemon(profile string) (*Daemon, error) {
+	if _, err := StopDaemon(profile); err != nil {
+		return nil, err
+	}
+	return StartDaemon(profile)
+}
+
+// StopDaemon stops the mount manager of a profile, which unmounts everything it mounted.
+// It returns whether a mount manager was found.
+func StopDaemon(profile string) (bool, error) {
+	d, err := LoadDaemon(profile)
+	if err != nil || d == nil {
+		return false, err
+	}
+	if err := d.Stop(daemonStopTimeout); err != nil {
+		return true, errors.Wrap(err, "stopping mount manager")
+	}
+	return true, daemon.Remove(daemonPath(profile))
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/third_party/go9p/ufs"
+)
+
+const (
+	// NineP is the type of the mounts served by the userspace 9p file server
+	NineP = "9p"
+	// checkInterval is how often the mount manager checks that the mounts are still established
+	checkInterval = 10 * time.Second
+)
+
+// MountConfig returns the options used to mount m, served on port
+func MountConfig(m config.Mount, port int) *cluster.MountConfig {
+	cfg := &cluster.MountConfig{
+		Type:    m.Type,
+		UID:     m.UID,
+		GID:     m.GID,
+		Version: m.Version,
+		MSize:   m.MSize,
+		Port:    port,
+		Mode:    os.FileMode(m.Mode),
+		Options: map[string]string{},
+	}
+	for _, o := range m.Options {
+		if !strings.Contains(o, "=") {
+			cfg.Options[o] = ""
+			continue
+		}
+		parts := strings.SplitN(o, "=", 2)
+		cfg.Options[parts[0]] = parts[1]
+	}
+	return cfg
+}
+
+// manager keeps the mounts of a profile established
+type manager struct {
+	api     libmachine.API
+	profile string
+	// ports holds the port of the 9p server of each guest path, the servers run until the manager exits
+	ports map[string]int
+	// mounted holds the guest paths mounted since the cluster was last seen running
+	mounted map[string]bool
+	runner  command.Runner
+	// forwards holds the forwarding of the port of the 9p server of each guest path into the guest, see cluster.MountIPs
+	forwards map[string]io.Closer
+}
+
+// Run keeps the mounts of a profile established until ctx is done, then unmounts them.
+// Mounts are re-established whenever they are found missing, for instance after the cluster restarted.
+func Run(ctx context.Context, api libmachine.API, profile string) error {
+	m := &manager{api: api, profile: profile, ports: map[string]int{}, mounted: map[string]bool{}, forwards: map[string]io.Closer{}}
+	for {
+		if err := m.reconcile(); err != nil {
+			klog.Warningf("unable to establish mounts of %s: %v", profile, err)
+		}
+		select {
+		case <-ctx.Done():
+			m.unmountAll()
+			m.closeForwards()
+			return nil
+		case <-time.After(checkInterval):
+		}
+	}
+}
+
+// reconcile mounts every mount of the profile which is not mounted in its primary control plane
+func (m *manager) reconcile() error {
+	cc, err := config.Load(m.profile)
+	if err != nil {
+		return errors.Wrap(err, "loading profile")
+	}
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return errors.Wrap(err, "getting control plane")
+	}
+	h, err := machine.LoadHost(m.api, config.MachineName(*cc, cp))
+	if err != nil {
+		return errors.Wrap(err, "loading host")
+	}
+	st, err := h.Driver.GetState()
+	if err != nil || st != state.Running {
+		if len(m.mounted) > 0 {
+			klog.Infof("%s is not running (%v), its mounts will be re-established once it is", m.profile, st)
+		}
+		m.mounted = map[string]bool{}
+		m.runner = nil
+		m.closeForwards()
+		return err
+	}
+	if m.runner == nil {
+		if m.runner, err = machine.CommandRunner(h); err != nil {
+			return errors.Wrap(err, "getting command runner")
+		}
+	}
+
+	ip, bindIP, forward, err := cluster.MountIPs(h, cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "getting host ip")
+	}
+
+	for _, mnt := range cc.Mounts {
+		if m.mounted[mnt.GuestPath] {
+			ok, err := cluster.IsMounted(m.runner, mnt.GuestPath)
+			if err != nil {
+				return err
+			}
+			if ok {
+				continue
+			}
+			klog.Infof("%s is not mounted anymore", mnt.GuestPath)
+		}
+		port, err := m.serve(mnt, bindIP)
+		if err != nil {
+			klog.Warningf("unable to serve %s: %v", mnt.HostPath, err)
+			continue
+		}
+		if forward && m.forwards[mnt.GuestPath] == nil {
+			f, err := cluster.ForwardToGuest(h.Driver, port)
+			if err != nil {
+				klog.Warningf("unable to forward the port of %s: %v", mnt.HostPath, err)
+				continue
+			}
+			m.forwards[mnt.GuestPath] = f
+		}
+		klog.Infof("mounting %s into %s as %s", mnt.HostPath, m.profile, mnt.GuestPath)
+		if err := cluster.Mount(m.runner, ip.String(), mnt.GuestPath, MountConfig(mnt, port)); err != nil {
+			klog.Warningf("unable to mount %s: %v", mnt.GuestPath, err)
+			continue
+		}
+		m.mounted[mnt.GuestPath] = true
+	}
+	return nil
+}
+
+// serve starts the 9p server of a mount once, and returns the port it listens on
+func (m *manager) serve(mnt config.Mount, bindIP string) (int, error) {
+	if port, ok := m.ports[mnt.GuestPath]; ok {
+		return port, nil
+	}
+	if mnt.Type != NineP {
+		return 0, fmt.Errorf("%s mounts are not supported", mnt.Type)
+	}
+	if _, err := os.Stat(mnt.HostPath); err != nil {
+		return 0, err
+	}
+	port, err := freePort()
+	if err != nil {
+		return 0, err
+	}
+	debug := 0
+	if klog.V(1).Enabled() {
+		debug = 1
+	}
+	go ufs.StartServer(net.JoinHostPort(bindIP, strconv.Itoa(port)), debug, mnt.HostPath)
+	m.ports[mnt.GuestPath] = port
+	return port, nil
+}
+
+// closeForwards stops forwarding the ports of the 9p servers into the guest
+func (m *manager) closeForwards() {
+	for p, f := range m.forwards {
+		if err := f.Close(); err != nil {
+			klog.Warningf("unable to stop forwarding the port of %s: %v", p, err)
+		}
+		delete(m.forwards, p)
+	}
+}
+
+// unmountAll unmounts everything mounted by the manager
+func (m *manager) unmountAll() {
+	if m.runner == nil {
+		return
+	}
+	for p := range m.mounted {
+		klog.Infof("unmounting %s", p)
+		if err := cluster.Unmount(m.runner, p); err != nil {
+			klog.Warningf("unable to unmount %s: %v", p, err)
+		}
+	}
+}
+
+// freePort asks the kernel for a free open port
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, errors.Wrap(err, "listening on a free port")
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
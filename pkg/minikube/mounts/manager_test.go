@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounts
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestMountConfig(t *testing.T) {
+	m := config.Mount{
+		HostPath:  "/home/user/code",
+		GuestPath: "/code",
+		Type:      NineP,
+		UID:       "docker",
+		GID:       "docker",
+		Version:   "9p2000.L",
+		MSize:     262144,
+		Mode:      0o755,
+		Options:   []string{"cache=fscache", "noextend", "a=b=c"},
+	}
+	want := &cluster.MountConfig{
+		Type:    NineP,
+		UID:     "docker",
+		GID:     "docker",
+		Version: "9p2000.L",
+		MSize:   262144,
+		Port:    4567,
+		Mode:    os.FileMode(0o755),
+		Options: map[string]string{"cache": "fscache", "noextend": "", "a": "b=c"},
+	}
+	if got := MountConfig(m, 4567); !reflect.DeepEqual(got, want) {
+		t.Errorf("MountConfig() = %+v, want %+v", got, want)
+	}
+}
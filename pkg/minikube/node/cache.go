@@ -236,7 +236,7 @@ func CacheAndLoadImagesInConfig(profiles []*config.Profile) error {
 	if len(images) == 0 {
 		return nil
 	}
-	return machine.CacheAndLoadImages(images, profiles, false)
+	return machine.CacheAndLoadImages(images, profiles, false, nil)
 }
 
 func imagesInConfigFile() ([]string, error) {
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util"
+)
+
+// DrainOptions are the options of Drain
+type DrainOptions struct {
+	// Force evicts the pods which no controller manages, which are then lost
+	Force bool
+	// GracePeriod overrides the termination grace period of the pods, unless it is negative
+	GracePeriod int
+	// Timeout is how long to wait for the evictions, which disruption budgets can delay, or 0 to wait forever
+	Timeout time.Duration
+}
+
+// Drain cordons a node, then evicts its pods, honoring their disruption budgets
+func Drain(cc config.ClusterConfig, name string, opts DrainOptions) error {
+	n, _, err := Retrieve(cc, name)
+	if err != nil {
+		return errors.Wrap(err, "retrieve")
+	}
+	runner, err := controlPlaneRunner(cc)
+	if err != nil {
+		return err
+	}
+
+	kubectl := kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion)
+	args, err := drainArgs(cc.KubernetesConfig.KubernetesVersion, config.MachineName(cc, *n), opts)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("sudo", append([]string{"KUBECONFIG=/var/lib/minikube/kubeconfig", kubectl}, args...)...)
+	if _, err := runner.RunCmd(cmd); err != nil {
+		return errors.Wrapf(err, "drain %s", name)
+	}
+	klog.Infof("successfully drained node %q", name)
+	return nil
+}
+
+// drainArgs returns the arguments of kubectl to drain the node named machineName
+func drainArgs(k8sVersion string, machineName string, opts DrainOptions) ([]string, error) {
+	args := []string{"drain", machineName, "--ignore-daemonsets", fmt.Sprintf("--grace-period=%d", opts.GracePeriod), fmt.Sprintf("--timeout=%s", opts.Timeout)}
+
+	version, err := util.ParseKubernetesVersion(k8sVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing kubernetes version")
+	}
+	// --delete-local-data was renamed in v1.20
+	if version.GTE(semver.MustParse("1.20.0")) {
+		args = append(args, "--delete-emptydir-data")
+	} else {
+		args = append(args, "--delete-local-data")
+	}
+	if opts.Force {
+		args = append(args, "--force")
+	}
+	return args, nil
+}
+
+// Cordon marks a node as unschedulable, or as schedulable again when unschedulable is false
+func Cordon(cc config.ClusterConfig, name string, unschedulable bool) error {
+	n, _, err := Retrieve(cc, name)
+	if err != nil {
+		return errors.Wrap(err, "retrieve")
+	}
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "client")
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	m := config.MachineName(cc, *n)
+	if _, err := client.CoreV1().Nodes().Patch(context.Background(), m, types.StrategicMergePatchType, []byte(patch), v1.PatchOptions{}); err != nil {
+		return errors.Wrapf(err, "patch node %s", m)
+	}
+	klog.Infof("set unschedulable=%t on node %q", unschedulable, m)
+	return nil
+}
+
+// controlPlaneRunner returns a runner on the primary control plane, which has a kubeconfig for kubectl
+func controlPlaneRunner(cc config.ClusterConfig) (command.Runner, error) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	host, err := machine.LoadHost(api, cc.Name)
+	if err != nil {
+		return nil, err
+	}
+	return machine.CommandRunner(host)
+}
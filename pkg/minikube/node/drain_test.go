@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDrainArgs(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		version string
+		opts    DrainOptions
+		want    []string
+	}{
+		{
+			desc:    "defaults",
+			version: "v1.21.2",
+			opts:    DrainOptions{GracePeriod: -1, Timeout: 5 * time.Minute},
+			want:    []string{"drain", "minikube-m02", "--ignore-daemonsets", "--grace-period=-1", "--timeout=5m0s", "--delete-emptydir-data"},
+		},
+		{
+			desc:    "forced",
+			version: "v1.21.2",
+			opts:    DrainOptions{Force: true, GracePeriod: 1},
+			want:    []string{"drain", "minikube-m02", "--ignore-daemonsets", "--grace-period=1", "--timeout=0s", "--delete-emptydir-data", "--force"},
+		},
+		{
+			desc:    "before v1.20",
+			version: "v1.19.12",
+			opts:    DrainOptions{GracePeriod: -1},
+			want:    []string{"drain", "minikube-m02", "--ignore-daemonsets", "--grace-period=-1", "--timeout=0s", "--delete-local-data"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := drainArgs(tc.version, "minikube-m02", tc.opts)
+			if err != nil {
+				t.Fatalf("drainArgs: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("drainArgs = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
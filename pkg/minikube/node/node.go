@@ -88,18 +88,8 @@ func drainNode(cc config.ClusterConfig, name string) (*config.Node, error) {
 	}
 
 	m := config.MachineName(cc, *n)
-	api, err := machine.NewAPIClient()
-	if err != nil {
-		return n, err
-	}
-
 	// grab control plane to use kubeconfig
-	host, err := machine.LoadHost(api, cc.Name)
-	if err != nil {
-		return n, err
-	}
-
-	runner, err := machine.CommandRunner(host)
+	runner, err := controlPlaneRunner(cc)
 	if err != nil {
 		return n, err
 	}
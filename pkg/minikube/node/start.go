@@ -63,6 +63,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/network"
 	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/retry"
 )
@@ -83,6 +84,8 @@ type Starter struct {
 	Cfg            *config.ClusterConfig
 	Node           *config.Node
 	ExistingAddons map[string]bool
+	// Images are cached images to load into the node, such as the addon images of an offline bundle
+	Images []string
 }
 
 // Start spins up a guest and starts the Kubernetes node.
@@ -110,6 +113,18 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 	var bs bootstrapper.Bootstrapper
 	var kcs *kubeconfig.Settings
 	if apiServer {
+		if starter.Cfg.HA && starter.Cfg.KubernetesConfig.APIServerHAVIP == "" {
+			vip, err := network.VirtualIP(starter.Node.IP)
+			if err != nil {
+				return nil, errors.Wrap(err, "allocating HA virtual IP")
+			}
+			klog.Infof("Using %s as the HA virtual IP", vip)
+			starter.Cfg.KubernetesConfig.APIServerHAVIP = vip
+			if err := config.SaveProfile(starter.Cfg.Name, starter.Cfg); err != nil {
+				return nil, errors.Wrap(err, "saving HA virtual IP")
+			}
+		}
+
 		// Must be written before bootstrap, otherwise health checks may flake due to stale IP
 		kcs = setupKubeconfig(starter.Host, starter.Cfg, starter.Node, starter.Cfg.Name)
 		if err != nil {
@@ -173,10 +188,17 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 		if err := CacheAndLoadImagesInConfig([]*config.Profile{profile}); err != nil {
 			out.FailureT("Unable to push cached images: {{.error}}", out.V{"error": err})
 		}
+		if err := machine.CacheAndLoadImages(starter.Images, []*config.Profile{profile}, false, nil); err != nil {
+			out.FailureT("Unable to push cached images: {{.error}}", out.V{"error": err})
+		}
 	}()
 
 	// enable addons, both old and new!
 	addonList := viper.GetStringSlice(config.AddonListFlag)
+	if plugin := addons.GPUDevicePlugin(starter.Cfg.GPUs); plugin != "" {
+		// makes the GPUs passed to the node schedulable
+		addonList = append(addonList, plugin)
+	}
 	if starter.ExistingAddons != nil {
 		if viper.GetBool("force") {
 			addons.Force = true
@@ -231,7 +253,7 @@ func joinCluster(starter Starter, cpBs bootstrapper.Bootstrapper, bs bootstrappe
 		klog.Infof("JoinCluster complete in %s", time.Since(start))
 	}()
 
-	joinCmd, err := cpBs.GenerateToken(*starter.Cfg)
+	joinCmd, err := cpBs.GenerateToken(*starter.Cfg, *starter.Node)
 	if err != nil {
 		return fmt.Errorf("error generating join token: %w", err)
 	}
@@ -309,6 +331,20 @@ func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, k
 		ImageRepository:   cc.KubernetesConfig.ImageRepository,
 		KubernetesVersion: kv,
 		InsecureRegistry:  cc.InsecureRegistry,
+		GPUs:              cc.GPUs,
+	}
+	if addons.RegistryMirrorEnabled(cc) {
+		if cc.KubernetesConfig.ContainerRuntime == "docker" && len(cc.RegistryMirror) > 0 {
+			// dockerd refuses to start if mirrors are set both as a flag and in daemon.json
+			klog.Warningf("--registry-mirror=%v takes precedence over the registry-mirror addon", cc.RegistryMirror)
+		} else {
+			co.RegistryMirrors = addons.RegistryMirrors
+		}
+	}
+	// Docker gets the proxy environment from the machine provisioner instead
+	rt := cc.KubernetesConfig.ContainerRuntime
+	if rt != "" && rt != "docker" && !driver.BareMetal(cc.Driver) {
+		co.ProxyEnv = proxy.RuntimeEnv()
 	}
 	cr, err := cruntime.New(co)
 	if err != nil {
@@ -454,6 +490,10 @@ func setupKubeconfig(h *host.Host, cc *config.ClusterConfig, n *config.Node, clu
 		exit.Message(reason.DrvCPEndpoint, fmt.Sprintf("failed to get API Server URL: %v", err), out.V{"profileArg": fmt.Sprintf("--profile=%s", clusterName)})
 	}
 
+	if cc.HA {
+		// The virtual IP is reachable from the host and keeps working when n goes down
+		addr = strings.ReplaceAll(addr, n.IP, cc.KubernetesConfig.APIServerHAVIP)
+	}
 	if cc.KubernetesConfig.APIServerName != constants.APIServerName {
 		addr = strings.ReplaceAll(addr, n.IP, cc.KubernetesConfig.APIServerName)
 	}
@@ -586,8 +626,10 @@ func validateNetwork(h *host.Host, r command.Runner, imageRepository string) (st
 		}
 	}
 
-	// Non-blocking
-	go tryRegistry(r, h.Driver.DriverName(), imageRepository)
+	// Non-blocking, and pointless when starting offline from a bundle
+	if viper.GetString(config.BundleFlag) == "" {
+		go tryRegistry(r, h.Driver.DriverName(), imageRepository)
+	}
 	return ip, nil
 }
 
@@ -30,6 +30,10 @@ import (
 
 const (
 	specVersion = "1.0"
+
+	// SchemaVersion is the version of the JSON output of minikube, set on every event as the schemaversion extension.
+	// It is bumped when the data of an existing event type changes incompatibly.
+	SchemaVersion = "1"
 )
 
 var (
@@ -73,6 +77,7 @@ func CloudEvent(log Log, data map[string]string) cloudevents.Event {
 		klog.Warningf("error setting data: %v", err)
 	}
 	event.SetID(GetUUID())
+	event.SetExtension("schemaversion", SchemaVersion)
 	return event
 }
 
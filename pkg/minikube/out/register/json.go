@@ -16,6 +16,8 @@ limitations under the License.
 
 package register
 
+import "time"
+
 // PrintStep prints a Step type in JSON format
 func PrintStep(message string) {
 	s := NewStep(message)
@@ -69,3 +71,22 @@ func PrintWarning(warning string) {
 	w := NewWarning(warning)
 	printAndRecordCloudEvent(w, w.data)
 }
+
+// PrintLogLine prints a LogLine type in JSON format
+func PrintLogLine(source, line string) {
+	l := NewLogLine(source, line)
+	printAsCloudEvent(l, l.data)
+}
+
+// PrintLogLineAt prints a LogLine type, received at t, in JSON format
+func PrintLogLineAt(source, line string, t time.Time) {
+	l := NewLogLine(source, line)
+	l.data["time"] = t.Format(time.RFC3339Nano)
+	printAsCloudEvent(l, l.data)
+}
+
+// PrintWait prints a Wait type in JSON format
+func PrintWait(component, status string, elapsed, timeout time.Duration) {
+	w := NewWait(component, status, elapsed, timeout)
+	printAndRecordCloudEvent(w, w.data)
+}
@@ -21,12 +21,13 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestPrintStep(t *testing.T) {
 	Reg.SetStep(InitialSetup)
 
-	expected := `{"data":{"currentstep":"0","message":"message","name":"Initial Minikube Setup","totalsteps":"%v"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.step"}`
+	expected := `{"data":{"currentstep":"0","message":"message","name":"Initial Minikube Setup","totalsteps":"%v"},"datacontenttype":"application/json","id":"random-id","schemaversion":"1","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.step"}`
 	expected = fmt.Sprintf(expected, Reg.totalSteps())
 	expected += "\n"
 
@@ -47,7 +48,7 @@ func TestPrintStep(t *testing.T) {
 }
 
 func TestPrintInfo(t *testing.T) {
-	expected := `{"data":{"message":"info"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.info"}`
+	expected := `{"data":{"message":"info"},"datacontenttype":"application/json","id":"random-id","schemaversion":"1","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.info"}`
 	expected += "\n"
 
 	buf := bytes.NewBuffer([]byte{})
@@ -67,7 +68,7 @@ func TestPrintInfo(t *testing.T) {
 }
 
 func TestError(t *testing.T) {
-	expected := `{"data":{"message":"error"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.error"}`
+	expected := `{"data":{"message":"error"},"datacontenttype":"application/json","id":"random-id","schemaversion":"1","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.error"}`
 	expected += "\n"
 
 	buf := bytes.NewBuffer([]byte{})
@@ -87,7 +88,7 @@ func TestError(t *testing.T) {
 }
 
 func TestErrorExitCode(t *testing.T) {
-	expected := `{"data":{"a":"b","c":"d","exitcode":"5","message":"error"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.error"}`
+	expected := `{"data":{"a":"b","c":"d","exitcode":"5","message":"error"},"datacontenttype":"application/json","id":"random-id","schemaversion":"1","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.error"}`
 	expected += "\n"
 
 	buf := bytes.NewBuffer([]byte{})
@@ -106,7 +107,7 @@ func TestErrorExitCode(t *testing.T) {
 }
 
 func TestWarning(t *testing.T) {
-	expected := `{"data":{"message":"warning"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.warning"}`
+	expected := `{"data":{"message":"warning"},"datacontenttype":"application/json","id":"random-id","schemaversion":"1","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.warning"}`
 	expected += "\n"
 
 	buf := bytes.NewBuffer([]byte{})
@@ -124,3 +125,43 @@ func TestWarning(t *testing.T) {
 		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
 	}
 }
+
+func TestLogLine(t *testing.T) {
+	expected := `{"data":{"message":"I0101 kubelet started","source":"kubelet"},"datacontenttype":"application/json","id":"random-id","schemaversion":"1","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.log"}`
+	expected += "\n"
+
+	buf := bytes.NewBuffer([]byte{})
+	SetOutputFile(buf)
+	defer func() { SetOutputFile(os.Stdout) }()
+
+	GetUUID = func() string {
+		return "random-id"
+	}
+
+	PrintLogLine("kubelet", "I0101 kubelet started")
+	actual := buf.String()
+
+	if actual != expected {
+		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
+	}
+}
+
+func TestWait(t *testing.T) {
+	expected := `{"data":{"component":"apiserver","elapsed":"1.5s","status":"done","timeout":"1m30s"},"datacontenttype":"application/json","id":"random-id","schemaversion":"1","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.wait"}`
+	expected += "\n"
+
+	buf := bytes.NewBuffer([]byte{})
+	SetOutputFile(buf)
+	defer func() { SetOutputFile(os.Stdout) }()
+
+	GetUUID = func() string {
+		return "random-id"
+	}
+
+	PrintWait("apiserver", "done", 1500*time.Millisecond, 90*time.Second)
+	actual := buf.String()
+
+	if actual != expected {
+		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
+	}
+}
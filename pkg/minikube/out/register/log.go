@@ -19,10 +19,24 @@ package register
 import (
 	"fmt"
 	"strings"
+	"time"
+)
+
+// The types of the events in the JSON output, consumers may rely on them and on the fields of their data.
+// New fields may be added, changing or removing existing ones requires bumping SchemaVersion.
+const (
+	StepType             = "io.k8s.sigs.minikube.step"
+	DownloadType         = "io.k8s.sigs.minikube.download"
+	DownloadProgressType = "io.k8s.sigs.minikube.download.progress"
+	WarningType          = "io.k8s.sigs.minikube.warning"
+	InfoType             = "io.k8s.sigs.minikube.info"
+	ErrorType            = "io.k8s.sigs.minikube.error"
+	LogLineType          = "io.k8s.sigs.minikube.log"
+	WaitType             = "io.k8s.sigs.minikube.wait"
 )
 
 // Log represents the different types of logs that can be output as JSON
-// This includes: Step, Download, DownloadProgress, Warning, Info, Error
+// This includes: Step, Download, DownloadProgress, Warning, Info, Error, LogLine, Wait
 type Log interface {
 	Type() string
 }
@@ -34,7 +48,7 @@ type Step struct {
 
 // Type returns the cloud events compatible type of this struct
 func (s *Step) Type() string {
-	return "io.k8s.sigs.minikube.step"
+	return StepType
 }
 
 // NewStep returns a new step type
@@ -54,7 +68,7 @@ type Download struct {
 
 // Type returns the cloud events compatible type of this struct
 func (s *Download) Type() string {
-	return "io.k8s.sigs.minikube.download"
+	return DownloadType
 }
 
 // NewDownload returns a new download type
@@ -73,7 +87,7 @@ type DownloadProgress struct {
 
 // Type returns the cloud events compatible type of this struct
 func (s *DownloadProgress) Type() string {
-	return "io.k8s.sigs.minikube.download.progress"
+	return DownloadProgressType
 }
 
 // NewDownloadProgress returns a new download progress type
@@ -102,7 +116,7 @@ func NewWarning(warning string) *Warning {
 
 // Type returns the cloud events compatible type of this struct
 func (s *Warning) Type() string {
-	return "io.k8s.sigs.minikube.warning"
+	return WarningType
 }
 
 // Info will be used to notify users of any extra info (env variables, options)
@@ -112,7 +126,7 @@ type Info struct {
 
 // Type returns the cloud events compatible type of this struct
 func (s *Info) Type() string {
-	return "io.k8s.sigs.minikube.info"
+	return InfoType
 }
 
 // NewInfo returns a new Info type
@@ -152,5 +166,47 @@ func NewErrorExitCode(err string, exitcode int, additionalData ...map[string]str
 
 // Type returns the cloud events compatible type of this struct
 func (s *Error) Type() string {
-	return "io.k8s.sigs.minikube.error"
+	return ErrorType
+}
+
+// LogLine is a line of the logs of a component, as shown by 'minikube logs'
+type LogLine struct {
+	data map[string]string
+}
+
+// Type returns the cloud events compatible type of this struct
+func (s *LogLine) Type() string {
+	return LogLineType
+}
+
+// NewLogLine returns a new LogLine type
+func NewLogLine(source, line string) *LogLine {
+	return &LogLine{
+		map[string]string{
+			"source":  source,
+			"message": line,
+		},
+	}
+}
+
+// Wait is the progress of waiting for a Kubernetes component after start
+type Wait struct {
+	data map[string]string
+}
+
+// Type returns the cloud events compatible type of this struct
+func (s *Wait) Type() string {
+	return WaitType
+}
+
+// NewWait returns a new Wait type. status is "waiting", "done" or "timeout".
+func NewWait(component, status string, elapsed, timeout time.Duration) *Wait {
+	return &Wait{
+		map[string]string{
+			"component": component,
+			"status":    status,
+			"elapsed":   elapsed.Round(time.Millisecond).String(),
+			"timeout":   timeout.String(),
+		},
+	}
 }
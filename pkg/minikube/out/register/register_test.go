@@ -29,7 +29,7 @@ func TestSetCurrentStep(t *testing.T) {
 	secondStep := Reg.steps[InitialSetup][1]
 	Reg.SetStep(secondStep)
 
-	expected := `{"data":{"currentstep":"1","message":"message","name":"%s","totalsteps":"%v"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.step"}`
+	expected := `{"data":{"currentstep":"1","message":"message","name":"%s","totalsteps":"%v"},"datacontenttype":"application/json","id":"random-id","schemaversion":"1","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.step"}`
 	expected = fmt.Sprintf(expected, secondStep, Reg.totalSteps())
 	expected += "\n"
 
@@ -0,0 +1,271 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preload generates preloaded images tarballs, which minikube start extracts into a new node
+package preload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	dockerStorageDriver = "overlay2"
+	podmanStorageDriver = "overlay"
+)
+
+// Options are the options to generate a preloaded images tarball with
+type Options struct {
+	// KubernetesVersion is the Kubernetes version whose images and binaries are preloaded
+	KubernetesVersion string
+	// ContainerRuntime is the container runtime the images are preloaded into
+	ContainerRuntime string
+	// Arch is the architecture of the images and binaries, it must be the one of the host
+	Arch string
+	// ImageRepository is an alternative image repository to pull the images from
+	ImageRepository string
+	// Name is the name of the temporary container the tarball is generated in
+	Name string
+	// Output is the path of the tarball to write
+	Output string
+}
+
+// Generate generates a preloaded images tarball, by pulling the images into a temporary kic container
+func Generate(o Options) error {
+	if o.Arch != "" && o.Arch != runtime.GOARCH {
+		return fmt.Errorf("a preload for %s must be generated on a %s host, this host is %s", o.Arch, o.Arch, runtime.GOARCH)
+	}
+	sv, err := util.ParseKubernetesVersion(o.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse Kubernetes version")
+	}
+
+	driver := kic.NewDriver(kic.Config{
+		ClusterName:       o.Name,
+		KubernetesVersion: o.KubernetesVersion,
+		ContainerRuntime:  o.ContainerRuntime,
+		OCIBinary:         oci.Docker,
+		MachineName:       o.Name,
+		ImageDigest:       kic.BaseImage,
+		StorePath:         localpath.MiniPath(),
+		CPU:               2,
+		Memory:            4000,
+		APIServerPort:     8080,
+	})
+
+	baseDir := filepath.Dir(driver.GetSSHKeyPath())
+	defer os.RemoveAll(baseDir)
+
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if err := driver.Create(); err != nil {
+		return errors.Wrap(err, "creating kic driver")
+	}
+	defer func() {
+		if err := driver.Remove(); err != nil {
+			klog.Warningf("failed to remove %s: %v", o.Name, err)
+		}
+	}()
+
+	runner := command.NewKICRunner(o.Name, driver.OCIBinary)
+	if err := verifyStorage(runner, o.ContainerRuntime); err != nil {
+		return errors.Wrap(err, "verifying storage")
+	}
+
+	// Now, get images to pull
+	imgs, err := images.Kubeadm(o.ImageRepository, o.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "kubeadm images")
+	}
+
+	if o.ContainerRuntime != "docker" { // kic overlay image is only needed by containerd and cri-o https://github.com/kubernetes/minikube/issues/7428
+		imgs = append(imgs, images.KindNet(o.ImageRepository))
+	}
+
+	co := cruntime.Config{
+		Type:              o.ContainerRuntime,
+		Runner:            runner,
+		ImageRepository:   o.ImageRepository,
+		KubernetesVersion: sv, //  this is just to satisfy cruntime and shouldnt matter what version.
+	}
+	cr, err := cruntime.New(co)
+	if err != nil {
+		return errors.Wrap(err, "failed create new runtime")
+	}
+	if err := cr.Enable(true, false); err != nil {
+		return errors.Wrap(err, "enable container runtime")
+	}
+
+	for _, img := range imgs {
+		pull := func() error {
+			return cr.PullImage(img)
+		}
+		// retry up to 5 times if network is bad
+		if err = retry.Expo(pull, time.Microsecond, time.Minute, 5); err != nil {
+			return errors.Wrapf(err, "pull image %s", img)
+		}
+	}
+
+	// Transfer in k8s binaries
+	kcfg := config.KubernetesConfig{
+		KubernetesVersion: o.KubernetesVersion,
+	}
+
+	sm := sysinit.New(runner)
+
+	if err := bsutil.TransferBinaries(kcfg, runner, sm); err != nil {
+		return errors.Wrap(err, "transferring k8s binaries")
+	}
+
+	// Create image tarball
+	tarball := path.Join("/", filepath.Base(o.Output))
+	if err := createImageTarball(runner, tarball, o.ContainerRuntime); err != nil {
+		return errors.Wrap(err, "create tarball")
+	}
+
+	return copyTarballToHost(runner, o.Name, tarball, o.Output)
+}
+
+func verifyStorage(runner command.Runner, containerRuntime string) error {
+	if containerRuntime == "docker" || containerRuntime == "containerd" {
+		if err := verifyDockerStorage(runner); err != nil {
+			return errors.Wrap(err, "Docker storage type is incompatible")
+		}
+	}
+	if containerRuntime == "cri-o" {
+		if err := verifyPodmanStorage(runner); err != nil {
+			return errors.Wrap(err, "Podman storage type is incompatible")
+		}
+	}
+	return nil
+}
+
+func verifyDockerStorage(runner command.Runner) error {
+	rr, err := runner.RunCmd(exec.Command("docker", "info", "-f", "{{.Info.Driver}}"))
+	if err != nil {
+		return err
+	}
+	driver := strings.Trim(rr.Stdout.String(), " \n")
+	if driver != dockerStorageDriver {
+		return fmt.Errorf("docker storage driver %s does not match requested %s", driver, dockerStorageDriver)
+	}
+	return nil
+}
+
+func verifyPodmanStorage(runner command.Runner) error {
+	rr, err := runner.RunCmd(exec.Command("sudo", "podman", "info", "-f", "json"))
+	if err != nil {
+		return err
+	}
+	var info map[string]map[string]interface{}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &info); err != nil {
+		return err
+	}
+	driver := info["store"]["graphDriverName"]
+	if driver != podmanStorageDriver {
+		return fmt.Errorf("podman storage driver %s does not match requested %s", driver, podmanStorageDriver)
+	}
+	return nil
+}
+
+func createImageTarball(runner command.Runner, tarball, containerRuntime string) error {
+	// directories to save into tarball
+	dirs := []string{
+		"./lib/minikube/binaries",
+	}
+
+	if containerRuntime == "docker" {
+		dirs = append(dirs, fmt.Sprintf("./lib/docker/%s", dockerStorageDriver), "./lib/docker/image")
+	}
+
+	if containerRuntime == "containerd" {
+		dirs = append(dirs, "./lib/containerd")
+	}
+
+	if containerRuntime == "cri-o" {
+		dirs = append(dirs, "./lib/containers")
+	}
+
+	args := []string{"tar", "-I", "lz4", "-C", "/var", "-cf", tarball}
+	args = append(args, dirs...)
+	if _, err := runner.RunCmd(exec.Command("sudo", args...)); err != nil {
+		return errors.Wrap(err, "tarball")
+	}
+	return nil
+}
+
+// copyTarballToHost copies the tarball out of the container to dest, through a temporary file so that an interrupted copy never leaves a truncated preload behind
+func copyTarballToHost(runner command.Runner, name, tarball, dest string) error {
+	rr, err := runner.RunCmd(exec.Command("stat", "-c", "%s", tarball))
+	if err != nil {
+		return errors.Wrap(err, "stat tarball")
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(rr.Stdout.String()), 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "tarball size")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(dest), filepath.Base(dest)+".tmp-")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	cmd := exec.Command(oci.Docker, "cp", fmt.Sprintf("%s:%s", name, tarball), tmp.Name())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "cp cmd: %s: %s", cmd.Args, stderr.String())
+	}
+
+	fi, err := os.Stat(tmp.Name())
+	if err != nil {
+		return err
+	}
+	if fi.Size() != size {
+		return fmt.Errorf("copied %d bytes of %s, expected %d", fi.Size(), tarball, size)
+	}
+	return os.Rename(tmp.Name(), dest)
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// System is the proxy configuration of the host
+type System struct {
+	HTTP    string
+	HTTPS   string
+	NoProxy []string
+	// PAC is the URL of the proxy auto-config file of the host, if it uses one
+	PAC string
+}
+
+// IsSet returns whether an HTTP or HTTPS proxy is set in the environment
+func IsSet() bool {
+	for _, k := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+		if os.Getenv(k) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect sets the proxy environment variables from the proxy settings of the host, unless a proxy is set in the environment already.
+// It returns the settings it found, or nil if there are none. Proxy auto-config files are not evaluated: when the host only
+// uses one, the settings are returned with its URL and no proxy applied.
+func Detect() (*System, error) {
+	if IsSet() {
+		return nil, nil
+	}
+
+	s, err := systemProxy()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading system proxy settings")
+	}
+	if s == nil {
+		return nil, nil
+	}
+	klog.Infof("system proxy settings: %+v", *s)
+
+	if s.HTTP == "" && s.HTTPS == "" {
+		return s, nil
+	}
+
+	if s.HTTP != "" {
+		if err := os.Setenv("HTTP_PROXY", s.HTTP); err != nil {
+			return nil, err
+		}
+	}
+	if s.HTTPS != "" {
+		if err := os.Setenv("HTTPS_PROXY", s.HTTPS); err != nil {
+			return nil, err
+		}
+	}
+	for _, np := range append([]string{"localhost", "127.0.0.1"}, s.NoProxy...) {
+		if !checkEnv(np, "NO_PROXY") {
+			if err := updateEnv(np, "NO_PROXY"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+// parseScutil parses the output of 'scutil --proxy' on macOS
+func parseScutil(output string) *System {
+	values := map[string]string{}
+	exceptions := []string{}
+	inExceptions := false
+
+	s := bufio.NewScanner(strings.NewReader(output))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "ExceptionsList") {
+			inExceptions = true
+			continue
+		}
+		if inExceptions {
+			if line == "}" {
+				inExceptions = false
+				continue
+			}
+			if i := strings.Index(line, " : "); i > 0 {
+				exceptions = append(exceptions, strings.TrimSpace(line[i+3:]))
+			}
+			continue
+		}
+		if i := strings.Index(line, " : "); i > 0 {
+			values[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+3:])
+		}
+	}
+
+	sys := &System{NoProxy: macNoProxy(exceptions)}
+	if values["HTTPEnable"] == "1" && values["HTTPProxy"] != "" {
+		sys.HTTP = proxyURL(values["HTTPProxy"], values["HTTPPort"])
+	}
+	if values["HTTPSEnable"] == "1" && values["HTTPSProxy"] != "" {
+		sys.HTTPS = proxyURL(values["HTTPSProxy"], values["HTTPSPort"])
+	}
+	if values["ProxyAutoConfigEnable"] == "1" {
+		sys.PAC = values["ProxyAutoConfigURLString"]
+	}
+	if sys.HTTP == "" && sys.HTTPS == "" && sys.PAC == "" {
+		return nil
+	}
+	return sys
+}
+
+// macNoProxy converts the proxy exceptions of macOS to NO_PROXY entries
+func macNoProxy(exceptions []string) []string {
+	np := []string{}
+	for _, e := range exceptions {
+		// "*.local" becomes ".local", which every NO_PROXY implementation understands
+		e = strings.TrimPrefix(e, "*")
+		if e != "" {
+			np = append(np, e)
+		}
+	}
+	return np
+}
+
+// parseWindowsProxy parses the Internet Settings of Windows: ProxyServer is either "host:port",
+// or per scheme as "http=host:port;https=host:port", and ProxyOverride a ';' separated list.
+func parseWindowsProxy(enabled bool, server string, override string, autoConfig string) *System {
+	sys := &System{PAC: autoConfig, NoProxy: []string{}}
+	if enabled && server != "" {
+		if !strings.Contains(server, "=") {
+			sys.HTTP = proxyURL(server, "")
+			sys.HTTPS = sys.HTTP
+		}
+		for _, part := range strings.Split(server, ";") {
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(kv[0])) {
+			case "http":
+				sys.HTTP = proxyURL(strings.TrimSpace(kv[1]), "")
+			case "https":
+				sys.HTTPS = proxyURL(strings.TrimSpace(kv[1]), "")
+			}
+		}
+	}
+	for _, o := range strings.Split(override, ";") {
+		o = strings.TrimPrefix(strings.TrimSpace(o), "*")
+		// <local> bypasses hostnames without a dot, which NO_PROXY cannot express
+		if o != "" && o != "<local>" {
+			sys.NoProxy = append(sys.NoProxy, o)
+		}
+	}
+	if sys.HTTP == "" && sys.HTTPS == "" && sys.PAC == "" {
+		return nil
+	}
+	return sys
+}
+
+// proxyURL returns the URL of a proxy given as host and port, or host:port if port is empty
+func proxyURL(host string, port string) string {
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	if port != "" && port != "0" {
+		return fmt.Sprintf("%s:%s", host, port)
+	}
+	return host
+}
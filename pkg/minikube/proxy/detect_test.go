@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseScutil(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   *System
+	}{
+		{
+			name: "manual",
+			output: `<dictionary> {
+  ExceptionsList : <array> {
+    0 : *.local
+    1 : 169.254/16
+    2 : corp.example.com
+  }
+  FTPPassive : 1
+  HTTPEnable : 1
+  HTTPPort : 3128
+  HTTPProxy : proxy.corp
+  HTTPSEnable : 1
+  HTTPSPort : 3129
+  HTTPSProxy : proxy.corp
+}`,
+			want: &System{HTTP: "http://proxy.corp:3128", HTTPS: "http://proxy.corp:3129", NoProxy: []string{".local", "169.254/16", "corp.example.com"}},
+		},
+		{
+			name: "pac",
+			output: `<dictionary> {
+  HTTPEnable : 0
+  ProxyAutoConfigEnable : 1
+  ProxyAutoConfigURLString : http://wpad.corp/wpad.dat
+}`,
+			want: &System{PAC: "http://wpad.corp/wpad.dat", NoProxy: []string{}},
+		},
+		{
+			name: "none",
+			output: `<dictionary> {
+  ExceptionsList : <array> {
+    0 : *.local
+  }
+  HTTPEnable : 0
+  HTTPSEnable : 0
+}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, parseScutil(tc.output)); diff != "" {
+				t.Errorf("parseScutil mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseWindowsProxy(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		server     string
+		override   string
+		autoConfig string
+		want       *System
+	}{
+		{
+			name:     "single",
+			enabled:  true,
+			server:   "proxy.corp:8080",
+			override: "*.corp.example.com;10.*;<local>",
+			want:     &System{HTTP: "http://proxy.corp:8080", HTTPS: "http://proxy.corp:8080", NoProxy: []string{".corp.example.com", "10.*"}},
+		},
+		{
+			name:    "per scheme",
+			enabled: true,
+			server:  "http=proxy.corp:80;https=secure.corp:443;ftp=ftp.corp:21",
+			want:    &System{HTTP: "http://proxy.corp:80", HTTPS: "http://secure.corp:443", NoProxy: []string{}},
+		},
+		{
+			name:       "disabled with pac",
+			server:     "proxy.corp:8080",
+			autoConfig: "http://wpad.corp/proxy.pac",
+			want:       &System{PAC: "http://wpad.corp/proxy.pac", NoProxy: []string{}},
+		},
+		{
+			name:   "disabled",
+			server: "proxy.corp:8080",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseWindowsProxy(tc.enabled, tc.server, tc.override, tc.autoConfig)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("parseWindowsProxy mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDetectKeepsEnvironment(t *testing.T) {
+	defer os.Setenv("HTTPS_PROXY", os.Getenv("HTTPS_PROXY"))
+	os.Setenv("HTTPS_PROXY", "http://env.proxy:3128")
+
+	s, err := Detect()
+	if err != nil || s != nil {
+		t.Errorf("Detect() = %+v, %v, want nil, nil when a proxy is set", s, err)
+	}
+}
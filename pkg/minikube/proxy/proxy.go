@@ -155,6 +155,26 @@ func UpdateTransport(cfg *rest.Config) *rest.Config {
 
 // SetDockerEnv sets the proxy environment variables in the docker environment.
 func SetDockerEnv() []string {
+	config.DockerEnv = append(config.DockerEnv, RuntimeEnv()...)
+
+	// remove duplicates
+	seen := map[string]bool{}
+	uniqueEnvs := []string{}
+	for e := range config.DockerEnv {
+		if !seen[config.DockerEnv[e]] {
+			seen[config.DockerEnv[e]] = true
+			uniqueEnvs = append(uniqueEnvs, config.DockerEnv[e])
+		}
+	}
+	config.DockerEnv = uniqueEnvs
+
+	return config.DockerEnv
+}
+
+// RuntimeEnv returns the proxy environment variables to pass to a container runtime, as KEY=VALUE.
+// Proxies on the local host are skipped, as the runtime cannot reach them.
+func RuntimeEnv() []string {
+	env := []string{}
 	for _, k := range EnvVars {
 		if v := os.Getenv(k); v != "" {
 			// convert https_proxy to HTTPS_PROXY for linux
@@ -180,20 +200,8 @@ func SetDockerEnv() []string {
 					continue
 				}
 			}
-			config.DockerEnv = append(config.DockerEnv, fmt.Sprintf("%s=%s", k, v))
-		}
-	}
-
-	// remove duplicates
-	seen := map[string]bool{}
-	uniqueEnvs := []string{}
-	for e := range config.DockerEnv {
-		if !seen[config.DockerEnv[e]] {
-			seen[config.DockerEnv[e]] = true
-			uniqueEnvs = append(uniqueEnvs, config.DockerEnv[e])
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
 		}
 	}
-	config.DockerEnv = uniqueEnvs
-
-	return config.DockerEnv
+	return env
 }
@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prune
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+)
+
+// InUse is what the clusters and this minikube version use, which is never pruned
+type InUse struct {
+	// KubernetesVersions are the Kubernetes versions of the clusters
+	KubernetesVersions map[string]bool
+	// ISOs are the file names of the ISOs of the clusters
+	ISOs map[string]bool
+	// BaseImages are the kicbase images of the clusters
+	BaseImages []string
+}
+
+// InUseBy returns what the profiles use, along with the defaults of this minikube version
+func InUseBy(profiles []*config.Profile) InUse {
+	u := InUse{
+		KubernetesVersions: map[string]bool{constants.DefaultKubernetesVersion: true},
+		ISOs:               map[string]bool{},
+		BaseImages:         append([]string{kic.BaseImage}, kic.FallbackImages...),
+	}
+	for _, iso := range download.DefaultISOURLs() {
+		u.ISOs[path.Base(iso)] = true
+	}
+	for _, p := range profiles {
+		cc := p.Config
+		if cc == nil {
+			continue
+		}
+		if cc.KubernetesConfig.KubernetesVersion != "" {
+			u.KubernetesVersions[cc.KubernetesConfig.KubernetesVersion] = true
+		}
+		if cc.MinikubeISO != "" {
+			u.ISOs[path.Base(cc.MinikubeISO)] = true
+		}
+		if cc.KicBaseImage != "" {
+			u.BaseImages = append(u.BaseImages, cc.KicBaseImage)
+		}
+	}
+	return u
+}
+
+// HostItems returns what can be pruned in the cache directory of the host for the categories
+func HostItems(cacheDir string, categories map[Category]bool, u InUse) ([]Item, error) {
+	var items []Item
+	if categories[Cache] {
+		binaries, err := binaryItems(cacheDir, u)
+		if err != nil {
+			return nil, err
+		}
+		preloads, err := preloadItems(filepath.Join(cacheDir, "preloaded-tarball"), u)
+		if err != nil {
+			return nil, err
+		}
+		items = append(append(items, binaries...), preloads...)
+	}
+	if categories[BaseImages] {
+		isos, err := fileItems(filepath.Join(cacheDir, "iso"), ".iso", func(name string) bool { return u.ISOs[name] })
+		if err != nil {
+			return nil, err
+		}
+		kept := map[string]bool{}
+		for _, img := range u.BaseImages {
+			kept[filepath.Base(download.ImagePathInCache(img))] = true
+		}
+		kics, err := fileItems(filepath.Join(cacheDir, "kic"), ".tar", func(name string) bool { return kept[name] })
+		if err != nil {
+			return nil, err
+		}
+		items = append(append(items, isos...), kics...)
+	}
+	return items, nil
+}
+
+// binaryItems returns the directories of the Kubernetes binaries of the versions which are not in use
+// the binaries are cached in <cache>/<os>/<version>
+func binaryItems(cacheDir string, u InUse) ([]Item, error) {
+	var items []Item
+	for _, goos := range []string{"linux", "darwin", "windows"} {
+		dirs, err := ioutil.ReadDir(filepath.Join(cacheDir, goos))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range dirs {
+			if !d.IsDir() || !strings.HasPrefix(d.Name(), "v") || u.KubernetesVersions[d.Name()] {
+				continue
+			}
+			item, err := pathItem(Cache, filepath.Join(cacheDir, goos, d.Name()))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// preloadItems returns the preloaded tarballs, with their checksums, of other preload versions or of Kubernetes versions which are not in use
+// the tarballs are named preloaded-images-k8s-<preload version>-<kubernetes version>-<runtime>-<storage driver>-<arch>.tar.lz4
+func preloadItems(dir string, u InUse) ([]Item, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var items []Item
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".tar.lz4") {
+			continue
+		}
+		parts := strings.Split(f.Name(), "-")
+		if len(parts) < 5 || parts[0] != "preloaded" {
+			continue
+		}
+		if parts[3] == download.PreloadVersion && u.KubernetesVersions[parts[4]] {
+			continue
+		}
+		tarball := filepath.Join(dir, f.Name())
+		checksum := tarball + ".checksum"
+		size := f.Size()
+		if fi, err := os.Stat(checksum); err == nil {
+			size += fi.Size()
+		}
+		items = append(items, NewItem(Cache, HostLocation, tarball, size, func() error {
+			if err := os.Remove(checksum); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return os.Remove(tarball)
+		}))
+	}
+	return items, nil
+}
+
+// fileItems returns the files with suffix within dir and its sub-directories, except the ones named as kept
+func fileItems(dir string, suffix string, kept func(name string) bool) ([]Item, error) {
+	var items []Item
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), suffix) || kept(info.Name()) {
+			return nil
+		}
+		items = append(items, NewItem(BaseImages, HostLocation, p, info.Size(), func() error {
+			return os.Remove(p)
+		}))
+		return nil
+	})
+	return items, err
+}
+
+// DaemonItems returns the kicbase images of the docker and podman daemons of the host which are not in use
+func DaemonItems(u InUse) []Item {
+	repos := map[string]bool{}
+	keep := map[string]bool{}
+	for _, img := range append(append([]string{kic.BaseImage}, kic.FallbackImages...), u.BaseImages...) {
+		name, digest := splitDigest(img)
+		repos[repository(name)] = true
+		keep[name] = true
+		if digest != "" {
+			keep[digest] = true
+		}
+	}
+
+	var items []Item
+	for _, ociBin := range []string{oci.Docker, oci.Podman} {
+		if _, err := exec.LookPath(ociBin); err != nil {
+			continue
+		}
+		for repo := range repos {
+			imgs, err := oci.ListImages(ociBin, repo)
+			if err != nil {
+				klog.Infof("unable to list the %s images of %s: %v", ociBin, repo, err)
+				continue
+			}
+			for _, img := range imgs {
+				if keep[img.Repository+":"+img.Tag] || keep[img.Digest] || keep[strings.TrimPrefix(img.Repository, "docker.io/")+":"+img.Tag] {
+					continue
+				}
+				ociBin, name := ociBin, img.Name()
+				items = append(items, NewItem(BaseImages, HostLocation, ociBin+" image "+name, img.Size, func() error {
+					return oci.RemoveImage(ociBin, name)
+				}))
+			}
+		}
+	}
+	return items
+}
+
+// splitDigest splits an image into its name and digest
+func splitDigest(img string) (string, string) {
+	if i := strings.Index(img, "@"); i != -1 {
+		return img[:i], img[i+1:]
+	}
+	return img, ""
+}
+
+// repository returns the repository of an image name
+func repository(name string) string {
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		return name[:i]
+	}
+	return name
+}
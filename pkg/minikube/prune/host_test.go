@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prune
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/download"
+)
+
+func TestHostItems(t *testing.T) {
+	cacheDir := t.TempDir()
+	files := map[string]int{
+		"linux/v1.20.2/kubelet":  10,
+		"linux/v1.18.0/kubelet":  20,
+		"linux/v1.18.0/kubeadm":  5,
+		"darwin/v1.18.0/kubectl": 7,
+		"preloaded-tarball/preloaded-images-k8s-" + download.PreloadVersion + "-v1.20.2-docker-overlay2-amd64.tar.lz4":          100,
+		"preloaded-tarball/preloaded-images-k8s-" + download.PreloadVersion + "-v1.18.0-docker-overlay2-amd64.tar.lz4":          200,
+		"preloaded-tarball/preloaded-images-k8s-" + download.PreloadVersion + "-v1.18.0-docker-overlay2-amd64.tar.lz4.checksum": 1,
+		"preloaded-tarball/preloaded-images-k8s-v1-v1.20.2-docker-overlay2-amd64.tar.lz4":                                       300,
+		"iso/minikube-v1.20.0.iso":      400,
+		"iso/amd64/minikube-v1.0.0.iso": 500,
+		"kic/kicbase-v0.0.1.tar":        600,
+	}
+	for name, size := range files {
+		p := filepath.Join(cacheDir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	u := InUse{
+		KubernetesVersions: map[string]bool{"v1.20.2": true},
+		ISOs:               map[string]bool{"minikube-v1.20.0.iso": true},
+	}
+
+	tests := []struct {
+		category Category
+		want     map[string]int64
+	}{
+		{
+			category: Cache,
+			want: map[string]int64{
+				"linux/v1.18.0":  25,
+				"darwin/v1.18.0": 7,
+				"preloaded-tarball/preloaded-images-k8s-" + download.PreloadVersion + "-v1.18.0-docker-overlay2-amd64.tar.lz4": 201,
+				"preloaded-tarball/preloaded-images-k8s-v1-v1.20.2-docker-overlay2-amd64.tar.lz4":                              300,
+			},
+		},
+		{
+			category: BaseImages,
+			want: map[string]int64{
+				"iso/amd64/minikube-v1.0.0.iso": 500,
+				"kic/kicbase-v0.0.1.tar":        600,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.category), func(t *testing.T) {
+			items, err := HostItems(cacheDir, map[Category]bool{tc.category: true}, u)
+			if err != nil {
+				t.Fatalf("HostItems: %v", err)
+			}
+			got := map[string]int64{}
+			for _, i := range items {
+				rel, _ := filepath.Rel(cacheDir, i.Name)
+				got[filepath.ToSlash(rel)] = i.Size
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("HostItems(%s) mismatch (-want +got):\n%s", tc.category, diff)
+			}
+		})
+	}
+
+	items, err := HostItems(cacheDir, map[Category]bool{Cache: true, BaseImages: true}, u)
+	if err != nil {
+		t.Fatalf("HostItems: %v", err)
+	}
+	for _, i := range items {
+		if err := i.Remove(); err != nil {
+			t.Errorf("removing %s: %v", i.Name, err)
+		}
+	}
+	var left []string
+	err = filepath.Walk(cacheDir, func(p string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(cacheDir, p)
+			left = append(left, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(left)
+	want := []string{
+		"iso/minikube-v1.20.0.iso",
+		"linux/v1.20.2/kubelet",
+		"preloaded-tarball/preloaded-images-k8s-" + download.PreloadVersion + "-v1.20.2-docker-overlay2-amd64.tar.lz4",
+	}
+	if diff := cmp.Diff(want, left); diff != "" {
+		t.Errorf("files left after pruning mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRepository(t *testing.T) {
+	tests := map[string]string{
+		"gcr.io/k8s-minikube/kicbase:v0.0.22": "gcr.io/k8s-minikube/kicbase",
+		"localhost:5000/kicbase":              "localhost:5000/kicbase",
+		"localhost:5000/kicbase:v1":           "localhost:5000/kicbase",
+	}
+	for in, want := range tests {
+		if got := repository(in); got != want {
+			t.Errorf("repository(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prune finds what can be removed to reclaim disk space, on the host and on the nodes
+package prune
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Category is a kind of item which can be pruned
+type Category string
+
+const (
+	// Cache are the Kubernetes binaries and preloaded tarballs of the host cache, for versions which no cluster uses
+	Cache Category = "cache"
+	// BaseImages are the ISOs and kicbase images of other minikube versions, which no cluster uses
+	BaseImages Category = "base-images"
+	// Images are the images of the nodes which no container uses, and Kubernetes does not need
+	Images Category = "images"
+	// BuildCache is the cache of the image builds of the nodes
+	BuildCache Category = "build-cache"
+	// Volumes are the directories of the storage provisioner which no persistent volume uses anymore
+	Volumes Category = "volumes"
+)
+
+// Categories are all the categories, in the order they are pruned
+var Categories = []Category{Cache, BaseImages, Images, BuildCache, Volumes}
+
+// HostLocation is the location of the items of the host
+const HostLocation = "host"
+
+// Item is something whose removal reclaims disk space
+type Item struct {
+	Category Category
+	// Location is where the item is, the host or the name of a node
+	Location string
+	// Name describes the item, for example a path or an image
+	Name string
+	// Size is how many bytes removing the item reclaims
+	Size int64
+
+	remove func() error
+}
+
+// NewItem returns an item removed by calling remove
+func NewItem(c Category, location string, name string, size int64, remove func() error) Item {
+	return Item{Category: c, Location: location, Name: name, Size: size, remove: remove}
+}
+
+// Remove removes the item
+func (i Item) Remove() error {
+	return i.remove()
+}
+
+// Total returns how many bytes removing items reclaims
+func Total(items []Item) int64 {
+	var total int64
+	for _, i := range items {
+		total += i.Size
+	}
+	return total
+}
+
+// Sort sorts items by category, location and name
+func Sort(items []Item) {
+	order := map[Category]int{}
+	for i, c := range Categories {
+		order[c] = i
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Category != b.Category {
+			return order[a.Category] < order[b.Category]
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Name < b.Name
+	})
+}
+
+// pathItem returns an item removing path, with the size of the files within it
+func pathItem(c Category, path string) (Item, error) {
+	size, err := diskUsage(path)
+	if err != nil {
+		return Item{}, err
+	}
+	return NewItem(c, HostLocation, path, size, func() error {
+		return os.RemoveAll(path)
+	}), nil
+}
+
+// diskUsage returns the size of the files within path
+func diskUsage(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "measuring %s", path)
+	}
+	return size, nil
+}
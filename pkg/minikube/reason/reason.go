@@ -188,6 +188,8 @@ var (
 	HostHomeMkdir      = Kind{ID: "HOST_HOME_MKDIR", ExitCode: ExHostPermission}
 	HostHomeChown      = Kind{ID: "HOST_HOME_CHOWN", ExitCode: ExHostPermission}
 	HostBrowser        = Kind{ID: "HOST_BROWSER", ExitCode: ExHostError}
+	HostBundleCreate   = Kind{ID: "HOST_BUNDLE_CREATE", ExitCode: ExHostError}
+	HostBundleExtract  = Kind{ID: "HOST_BUNDLE_EXTRACT", ExitCode: ExHostError}
 	HostConfigLoad     = Kind{ID: "HOST_CONFIG_LOAD", ExitCode: ExHostConfig}
 	HostHomePermission = Kind{
 		ID:       "HOST_HOME_PERMISSION",
@@ -196,18 +198,33 @@ var (
 		Issues:   []int{9165},
 	}
 
+	HostAuditLog            = Kind{ID: "HOST_AUDIT_LOG", ExitCode: ExHostError}
+	HostBaseImageBuild      = Kind{ID: "HOST_BASE_IMAGE_BUILD", ExitCode: ExHostError}
+	HostCertAdd             = Kind{ID: "HOST_CERT_ADD", ExitCode: ExHostError}
 	HostCurrentUser         = Kind{ID: "HOST_CURRENT_USER", ExitCode: ExHostConfig}
+	HostDashboardExpose     = Kind{ID: "HOST_DASHBOARD_EXPOSE", ExitCode: ExHostError}
 	HostDelCache            = Kind{ID: "HOST_DEL_CACHE", ExitCode: ExHostError}
+	HostDNS                 = Kind{ID: "HOST_DNS", ExitCode: ExHostError}
+	HostHook                = Kind{ID: "HOST_HOOK", ExitCode: ExHostError}
 	HostKillMountProc       = Kind{ID: "HOST_KILL_MOUNT_PROC", ExitCode: ExHostError}
 	HostKubeconfigUnset     = Kind{ID: "HOST_KUBECNOFIG_UNSET", ExitCode: ExHostConfig}
 	HostKubeconfigUpdate    = Kind{ID: "HOST_KUBECONFIG_UPDATE", ExitCode: ExHostConfig}
 	HostKubeconfigDeleteCtx = Kind{ID: "HOST_KUBECONFIG_DELETE_CTX", ExitCode: ExHostConfig}
 	HostKubectlProxy        = Kind{ID: "HOST_KUBECTL_PROXY", ExitCode: ExHostError}
+	HostLifecycleDaemon     = Kind{ID: "HOST_LIFECYCLE_DAEMON", ExitCode: ExHostError}
+	HostMountManager        = Kind{ID: "HOST_MOUNT_MANAGER", ExitCode: ExHostError}
 	HostMountPid            = Kind{ID: "HOST_MOUNT_PID", ExitCode: ExHostError}
 	HostPathMissing         = Kind{ID: "HOST_PATH_MISSING", ExitCode: ExHostNotFound}
 	HostPathStat            = Kind{ID: "HOST_PATH_STAT", ExitCode: ExHostError}
+	HostPreloadCreate       = Kind{ID: "HOST_PRELOAD_CREATE", ExitCode: ExHostError}
+	HostProfileExport       = Kind{ID: "HOST_PROFILE_EXPORT", ExitCode: ExHostError}
+	HostProfileImport       = Kind{ID: "HOST_PROFILE_IMPORT", ExitCode: ExHostError}
+	HostPrune               = Kind{ID: "HOST_PRUNE", ExitCode: ExHostError}
 	HostPurge               = Kind{ID: "HOST_PURGE", ExitCode: ExHostError}
 	HostSaveProfile         = Kind{ID: "HOST_SAVE_PROFILE", ExitCode: ExHostConfig}
+	HostSharedNetwork       = Kind{ID: "HOST_SHARED_NETWORK", ExitCode: ExHostError}
+	HostSharedNetworkInUse  = Kind{ID: "HOST_SHARED_NETWORK_IN_USE", ExitCode: ExHostConflict}
+	HostSSHForward          = Kind{ID: "HOST_SSH_FORWARD", ExitCode: ExHostError}
 
 	ProviderNotFound    = Kind{ID: "PROVIDER_NOT_FOUND", ExitCode: ExProviderNotFound}
 	ProviderUnavailable = Kind{ID: "PROVIDER_UNAVAILABLE", ExitCode: ExProviderNotFound, Style: style.Shrug}
@@ -219,38 +236,51 @@ var (
 		ExitCode: ExDriverError,
 		Style:    style.Failure,
 	}
-	DrvPortForward        = Kind{ID: "DRV_PORT_FORWARD", ExitCode: ExDriverError}
-	DrvUnsupportedMulti   = Kind{ID: "DRV_UNSUPPORTED_MULTINODE", ExitCode: ExDriverConflict}
-	DrvUnsupportedOS      = Kind{ID: "DRV_UNSUPPORTED_OS", ExitCode: ExDriverUnsupported}
-	DrvUnsupportedProfile = Kind{ID: "DRV_UNSUPPORTED_PROFILE", ExitCode: ExDriverUnsupported}
-	DrvNotFound           = Kind{ID: "DRV_NOT_FOUND", ExitCode: ExDriverNotFound}
-	DrvNotDetected        = Kind{ID: "DRV_NOT_DETECTED", ExitCode: ExDriverNotFound}
-	DrvNotHealthy         = Kind{ID: "DRV_NOT_HEALTHY", ExitCode: ExDriverNotFound}
-	DrvDockerNotRunning   = Kind{ID: "DRV_DOCKER_NOT_RUNNING", ExitCode: ExDriverNotFound}
-	DrvAsRoot             = Kind{ID: "DRV_AS_ROOT", ExitCode: ExDriverPermission}
-	DrvNeedsRoot          = Kind{ID: "DRV_NEEDS_ROOT", ExitCode: ExDriverPermission}
-	DrvNeedsAdministrator = Kind{ID: "DRV_NEEDS_ADMINISTRATOR", ExitCode: ExDriverPermission}
+	DrvPortForward         = Kind{ID: "DRV_PORT_FORWARD", ExitCode: ExDriverError}
+	DrvUnsupportedMulti    = Kind{ID: "DRV_UNSUPPORTED_MULTINODE", ExitCode: ExDriverConflict}
+	DrvUnsupportedOS       = Kind{ID: "DRV_UNSUPPORTED_OS", ExitCode: ExDriverUnsupported}
+	DrvUnsupportedProfile  = Kind{ID: "DRV_UNSUPPORTED_PROFILE", ExitCode: ExDriverUnsupported}
+	DrvUnsupportedSnapshot = Kind{ID: "DRV_UNSUPPORTED_SNAPSHOT", ExitCode: ExDriverUnsupported}
+	DrvNotFound            = Kind{ID: "DRV_NOT_FOUND", ExitCode: ExDriverNotFound}
+	DrvNotDetected         = Kind{ID: "DRV_NOT_DETECTED", ExitCode: ExDriverNotFound}
+	DrvNotHealthy          = Kind{ID: "DRV_NOT_HEALTHY", ExitCode: ExDriverNotFound}
+	DrvDockerNotRunning    = Kind{ID: "DRV_DOCKER_NOT_RUNNING", ExitCode: ExDriverNotFound}
+	DrvAsRoot              = Kind{ID: "DRV_AS_ROOT", ExitCode: ExDriverPermission}
+	DrvNeedsRoot           = Kind{ID: "DRV_NEEDS_ROOT", ExitCode: ExDriverPermission}
+	DrvNeedsAdministrator  = Kind{ID: "DRV_NEEDS_ADMINISTRATOR", ExitCode: ExDriverPermission}
 
 	GuestCacheLoad                = Kind{ID: "GUEST_CACHE_LOAD", ExitCode: ExGuestError}
 	GuestCert                     = Kind{ID: "GUEST_CERT", ExitCode: ExGuestError}
+	GuestCertCheck                = Kind{ID: "GUEST_CERT_CHECK", ExitCode: ExGuestError}
+	GuestCertRenew                = Kind{ID: "GUEST_CERT_RENEW", ExitCode: ExGuestError}
 	GuestCpConfig                 = Kind{ID: "GUEST_CP_CONFIG", ExitCode: ExGuestConfig}
 	GuestDeletion                 = Kind{ID: "GUEST_DELETION", ExitCode: ExGuestError}
 	GuestImageList                = Kind{ID: "GUEST_IMAGE_LIST", ExitCode: ExGuestError}
 	GuestImageLoad                = Kind{ID: "GUEST_IMAGE_LOAD", ExitCode: ExGuestError}
 	GuestImageRemove              = Kind{ID: "GUEST_IMAGE_REMOVE", ExitCode: ExGuestError}
 	GuestImageBuild               = Kind{ID: "GUEST_IMAGE_BUILD", ExitCode: ExGuestError}
+	GuestImageScan                = Kind{ID: "GUEST_IMAGE_SCAN", ExitCode: ExGuestError}
 	GuestLoadHost                 = Kind{ID: "GUEST_LOAD_HOST", ExitCode: ExGuestError}
 	GuestMount                    = Kind{ID: "GUEST_MOUNT", ExitCode: ExGuestError}
 	GuestMountConflict            = Kind{ID: "GUEST_MOUNT_CONFLICT", ExitCode: ExGuestConflict}
 	GuestNodeAdd                  = Kind{ID: "GUEST_NODE_ADD", ExitCode: ExGuestError}
+	GuestNodeCordon               = Kind{ID: "GUEST_NODE_CORDON", ExitCode: ExGuestError}
 	GuestNodeDelete               = Kind{ID: "GUEST_NODE_DELETE", ExitCode: ExGuestError}
+	GuestNodeDrain                = Kind{ID: "GUEST_NODE_DRAIN", ExitCode: ExGuestError}
 	GuestNodeProvision            = Kind{ID: "GUEST_NODE_PROVISION", ExitCode: ExGuestError}
+	GuestNodeResize               = Kind{ID: "GUEST_NODE_RESIZE", ExitCode: ExGuestError}
+	GuestNodeResizeUnsupported    = Kind{ID: "GUEST_NODE_RESIZE_UNSUPPORTED", ExitCode: ExGuestUnsupported}
 	GuestNodeRetrieve             = Kind{ID: "GUEST_NODE_RETRIEVE", ExitCode: ExGuestNotFound}
 	GuestNodeStart                = Kind{ID: "GUEST_NODE_START", ExitCode: ExGuestError}
+	GuestNodeStop                 = Kind{ID: "GUEST_NODE_STOP", ExitCode: ExGuestError}
 	GuestPause                    = Kind{ID: "GUEST_PAUSE", ExitCode: ExGuestError}
 	GuestProfileDeletion          = Kind{ID: "GUEST_PROFILE_DELETION", ExitCode: ExGuestError}
 	GuestProvision                = Kind{ID: "GUEST_PROVISION", ExitCode: ExGuestError}
 	GuestProvisionContainerExited = Kind{ID: "GUEST_PROVISION_CONTAINER_EXITED", ExitCode: ExGuestError}
+	GuestSCP                      = Kind{ID: "GUEST_SCP", ExitCode: ExGuestError}
+	GuestSnapshotCreate           = Kind{ID: "GUEST_SNAPSHOT_CREATE", ExitCode: ExGuestError}
+	GuestSnapshotList             = Kind{ID: "GUEST_SNAPSHOT_LIST", ExitCode: ExGuestError}
+	GuestSnapshotRestore          = Kind{ID: "GUEST_SNAPSHOT_RESTORE", ExitCode: ExGuestError}
 	GuestStart                    = Kind{ID: "GUEST_START", ExitCode: ExGuestError}
 	GuestStatus                   = Kind{ID: "GUEST_STATUS", ExitCode: ExGuestError}
 	GuestStopTimeout              = Kind{ID: "GUEST_STOP_TIMEOUT", ExitCode: ExGuestTimeout}
@@ -277,13 +307,15 @@ var (
 	RuntimeCache   = Kind{ID: "RUNTIME_CACHE", ExitCode: ExRuntimeError}
 	RuntimeRestart = Kind{ID: "RUNTIME_RESTART", ExitCode: ExRuntimeError}
 
-	SvcCheckTimeout = Kind{ID: "SVC_CHECK_TIMEOUT", ExitCode: ExSvcTimeout}
-	SvcTimeout      = Kind{ID: "SVC_TIMEOUT", ExitCode: ExSvcTimeout}
-	SvcList         = Kind{ID: "SVC_LIST", ExitCode: ExSvcError}
-	SvcTunnelStart  = Kind{ID: "SVC_TUNNEL_START", ExitCode: ExSvcError}
-	SvcTunnelStop   = Kind{ID: "SVC_TUNNEL_STOP", ExitCode: ExSvcError}
-	SvcURLTimeout   = Kind{ID: "SVC_URL_TIMEOUT", ExitCode: ExSvcTimeout}
-	SvcNotFound     = Kind{ID: "SVC_NOT_FOUND", ExitCode: ExSvcNotFound}
+	SvcCheckTimeout   = Kind{ID: "SVC_CHECK_TIMEOUT", ExitCode: ExSvcTimeout}
+	SvcTimeout        = Kind{ID: "SVC_TIMEOUT", ExitCode: ExSvcTimeout}
+	SvcList           = Kind{ID: "SVC_LIST", ExitCode: ExSvcError}
+	SvcTunnelStart    = Kind{ID: "SVC_TUNNEL_START", ExitCode: ExSvcError}
+	SvcTunnelStop     = Kind{ID: "SVC_TUNNEL_STOP", ExitCode: ExSvcError}
+	SvcTunnelStatus   = Kind{ID: "SVC_TUNNEL_STATUS", ExitCode: ExSvcError}
+	SvcURLTimeout     = Kind{ID: "SVC_URL_TIMEOUT", ExitCode: ExSvcTimeout}
+	SvcNotFound       = Kind{ID: "SVC_NOT_FOUND", ExitCode: ExSvcNotFound}
+	SvcMetricsTimeout = Kind{ID: "SVC_METRICS_TIMEOUT", ExitCode: ExSvcTimeout}
 
 	EnvDriverConflict    = Kind{ID: "ENV_DRIVER_CONFLICT", ExitCode: ExDriverConflict}
 	EnvMultiConflict     = Kind{ID: "ENV_MULTINODE_CONFLICT", ExitCode: ExGuestConflict}
@@ -292,6 +324,9 @@ var (
 
 	AddonUnsupported = Kind{ID: "SVC_ADDON_UNSUPPORTED", ExitCode: ExSvcUnsupported}
 	AddonNotEnabled  = Kind{ID: "SVC_ADDON_NOT_ENABLED", ExitCode: ExProgramConflict}
+	AddonInstall     = Kind{ID: "SVC_ADDON_INSTALL", ExitCode: ExSvcError}
+	AddonUninstall   = Kind{ID: "SVC_ADDON_UNINSTALL", ExitCode: ExSvcError}
+	AddonConfigure   = Kind{ID: "SVC_ADDON_CONFIGURE", ExitCode: ExSvcError}
 
 	KubernetesInstallFailed                  = Kind{ID: "K8S_INSTALL_FAILED", ExitCode: ExControlPlaneError}
 	KubernetesInstallFailedRuntimeNotRunning = Kind{ID: "K8S_INSTALL_FAILED_CONTAINER_RUNTIME_NOT_RUNNING", ExitCode: ExRuntimeNotRunning}
@@ -35,6 +35,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/minikube/sharednet"
 )
 
 var docURL = "https://minikube.sigs.k8s.io/docs/drivers/docker/"
@@ -69,21 +70,31 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		extraArgs = append(extraArgs, "-p", port)
 	}
 
+	// nodes of the profiles on a shared network take free addresses, as their machine indexes collide
+	staticIP, err := sharednet.NodeIP(cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "allocating an address on the shared network")
+	}
+
+	cpus, memory, _ := config.NodeResources(cc, n)
 	return kic.NewDriver(kic.Config{
 		ClusterName:       cc.Name,
 		MachineName:       config.MachineName(cc, n),
 		StorePath:         localpath.MiniPath(),
 		ImageDigest:       cc.KicBaseImage,
 		Mounts:            mounts,
-		CPU:               cc.CPUs,
-		Memory:            cc.Memory,
+		CPU:               cpus,
+		Memory:            memory,
 		OCIBinary:         oci.Docker,
 		APIServerPort:     cc.Nodes[0].Port,
 		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
 		ExtraArgs:         extraArgs,
 		Network:           cc.Network,
+		StaticIP:          staticIP,
 		ListenAddress:     cc.ListenAddress,
+		IPFamily:          cc.KubernetesConfig.IPFamily,
+		GPUs:              cc.GPUs,
 	}), nil
 }
 
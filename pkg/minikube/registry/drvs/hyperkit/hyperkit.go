@@ -65,6 +65,7 @@ func configure(cfg config.ClusterConfig, n config.Node) (interface{}, error) {
 		u = uuid.NewUUID().String()
 	}
 
+	cpus, memory, disk := config.NodeResources(cfg, n)
 	return &hyperkit.Driver{
 		BaseDriver: &drivers.BaseDriver{
 			MachineName: config.MachineName(cfg, n),
@@ -72,9 +73,9 @@ func configure(cfg config.ClusterConfig, n config.Node) (interface{}, error) {
 			SSHUser:     "docker",
 		},
 		Boot2DockerURL: download.LocalISOResource(cfg.MinikubeISO),
-		DiskSize:       cfg.DiskSize,
-		Memory:         cfg.Memory,
-		CPU:            cfg.CPUs,
+		DiskSize:       disk,
+		Memory:         memory,
+		CPU:            cpus,
 		NFSShares:      cfg.NFSShare,
 		NFSSharesRoot:  cfg.NFSSharesRoot,
 		UUID:           u,
@@ -74,18 +74,19 @@ type kvmDriver struct {
 
 func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 	name := config.MachineName(cc, n)
+	cpus, memory, disk := config.NodeResources(cc, n)
 	return kvmDriver{
 		BaseDriver: &drivers.BaseDriver{
 			MachineName: name,
 			StorePath:   localpath.MiniPath(),
 			SSHUser:     "docker",
 		},
-		Memory:         cc.Memory,
-		CPU:            cc.CPUs,
+		Memory:         memory,
+		CPU:            cpus,
 		Network:        cc.KVMNetwork,
 		PrivateNetwork: privateNetwork(cc),
 		Boot2DockerURL: download.LocalISOResource(cc.MinikubeISO),
-		DiskSize:       cc.DiskSize,
+		DiskSize:       disk,
 		DiskPath:       filepath.Join(localpath.MiniPath(), "machines", name, fmt.Sprintf("%s.rawdisk", name)),
 		ISO:            filepath.Join(localpath.MiniPath(), "machines", name, "boot2docker.iso"),
 		GPU:            cc.KVMGPU,
@@ -0,0 +1,149 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+
+	"k8s.io/minikube/pkg/drivers/microvm"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+const (
+	docURL = "https://minikube.sigs.k8s.io/docs/drivers/microvm/"
+	// bridgeConf allows users to attach guests to bridges through qemu-bridge-helper
+	bridgeConf = "/etc/qemu/bridge.conf"
+)
+
+// minimumVersion is the first QEMU release with PCIe support in the microvm machine type
+var minimumVersion = []int{6, 0}
+
+func init() {
+	if err := registry.Register(registry.DriverDef{
+		Name:     driver.MicroVM,
+		Config:   configure,
+		Status:   status,
+		Default:  false, // requires a bridge set up for qemu-bridge-helper
+		Priority: registry.Experimental,
+		Init:     func() drivers.Driver { return microvm.NewDriver("", "") },
+	}); err != nil {
+		panic(fmt.Sprintf("register failed: %v", err))
+	}
+}
+
+func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	d := microvm.NewDriver(config.MachineName(cc, n), localpath.MiniPath())
+	d.Boot2DockerURL = download.LocalISOResource(cc.MinikubeISO)
+	d.DiskSize = cc.DiskSize
+	d.CPU = cc.CPUs
+	d.Memory = cc.Memory
+	d.Cmdline = "loglevel=3 console=ttyS0 noembed nomodeset norestore waitusb=10 systemd.legacy_systemd_cgroup_controller=yes random.trust_cpu=on hw_rng_model=virtio base host=" + cc.Name
+	if cc.Network != "" {
+		d.Bridge = cc.Network
+	}
+	return d, nil
+}
+
+func status() registry.State {
+	if runtime.GOARCH != "amd64" {
+		return registry.State{Error: fmt.Errorf("the microvm machine type is only available on amd64 hosts"), Doc: docURL}
+	}
+
+	path, err := exec.LookPath(microvm.QEMU)
+	if err != nil {
+		return registry.State{Error: err, Fix: "Install QEMU 6.0 or newer", Doc: docURL}
+	}
+
+	// Allow no more than 2 seconds for querying state
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, "--version")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return registry.State{Installed: true, Error: fmt.Errorf("%s failed:\n%s", strings.Join(cmd.Args, " "), out), Fix: "Install QEMU 6.0 or newer", Doc: docURL}
+	}
+	if !supportedVersion(string(out)) {
+		return registry.State{Installed: true, Error: fmt.Errorf("%s is too old: %s", microvm.QEMU, strings.TrimSpace(string(out))), Fix: "Install QEMU 6.0 or newer", Doc: docURL}
+	}
+
+	f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+	if err != nil {
+		return registry.State{Installed: true, Error: err, Fix: "Enable virtualization in the BIOS, and add your user to the kvm group", Doc: docURL}
+	}
+	f.Close()
+
+	if !bridgeAllowed(microvm.DefaultBridge) {
+		return registry.State{
+			Installed: true,
+			Running:   true,
+			Error:     fmt.Errorf("%s does not allow guests on the %s bridge", bridgeConf, microvm.DefaultBridge),
+			Fix:       fmt.Sprintf("Run: echo 'allow %s' | sudo tee -a %s", microvm.DefaultBridge, bridgeConf),
+			Doc:       docURL,
+		}
+	}
+	return registry.State{Installed: true, Healthy: true, Running: true}
+}
+
+// supportedVersion returns whether the output of 'qemu-system-x86_64 --version' is of a version which supports the driver
+func supportedVersion(out string) bool {
+	m := regexp.MustCompile(`version (\d+)\.(\d+)`).FindStringSubmatch(out)
+	if m == nil {
+		return false
+	}
+	for i, min := range minimumVersion {
+		v, _ := strconv.Atoi(m[i+1])
+		if v != min {
+			return v > min
+		}
+	}
+	return true
+}
+
+// bridgeAllowed returns whether qemu-bridge-helper lets users attach guests to the bridge
+func bridgeAllowed(bridge string) bool {
+	f, err := os.Open(bridgeConf)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 2 && fields[0] == "allow" && (fields[1] == bridge || fields[1] == "all") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import "testing"
+
+func TestSupportedVersion(t *testing.T) {
+	tests := []struct {
+		out  string
+		want bool
+	}{
+		{"QEMU emulator version 6.0.0 (Debian 1:6.0+dfsg-2)", true},
+		{"QEMU emulator version 6.2.0\nCopyright (c) 2003-2021 Fabrice Bellard", true},
+		{"QEMU emulator version 7.1.0", true},
+		{"QEMU emulator version 5.2.0 (Debian 1:5.2+dfsg-11)", false},
+		{"QEMU emulator version 4.2.1", false},
+		{"garbage", false},
+	}
+	for _, tc := range tests {
+		if got := supportedVersion(tc.out); got != tc.want {
+			t.Errorf("supportedVersion(%q) = %v, want %v", tc.out, got, tc.want)
+		}
+	}
+}
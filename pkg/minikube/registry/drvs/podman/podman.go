@@ -18,6 +18,7 @@ package podman
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -36,10 +37,13 @@ import (
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/minikube/sharednet"
 )
 
 var docURL = "https://minikube.sigs.k8s.io/docs/drivers/podman/"
 
+var rootlessDocURL = "https://minikube.sigs.k8s.io/docs/drivers/podman/#rootless-podman"
+
 // minReqPodmanVer is required the minimum version of podman to be installed for podman driver.
 var minReqPodmanVer = semver.Version{Major: 2, Minor: 1, Patch: 0}
 
@@ -82,20 +86,31 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		extraArgs = append(extraArgs, "-p", port)
 	}
 
+	// nodes of the profiles on a shared network take free addresses, as their machine indexes collide
+	staticIP, err := sharednet.NodeIP(cc)
+	if err != nil {
+		return nil, fmt.Errorf("allocating an address on the shared network: %w", err)
+	}
+
+	cpus, memory, _ := config.NodeResources(cc, n)
 	return kic.NewDriver(kic.Config{
 		ClusterName:       cc.Name,
 		MachineName:       config.MachineName(cc, n),
 		StorePath:         localpath.MiniPath(),
 		ImageDigest:       strings.Split(cc.KicBaseImage, "@")[0], // for podman does not support docker images references with both a tag and digest.
 		Mounts:            mounts,
-		CPU:               cc.CPUs,
-		Memory:            cc.Memory,
+		CPU:               cpus,
+		Memory:            memory,
 		OCIBinary:         oci.Podman,
 		APIServerPort:     cc.Nodes[0].Port,
 		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
 		ExtraArgs:         extraArgs,
+		Network:           cc.Network,
+		StaticIP:          staticIP,
 		ListenAddress:     cc.ListenAddress,
+		IPFamily:          cc.KubernetesConfig.IPFamily,
+		GPUs:              cc.GPUs,
 	}), nil
 }
 
@@ -110,10 +125,14 @@ func status() registry.State {
 
 	// Quickly returns an error code if service is not running
 	cmd := exec.CommandContext(ctx, oci.Podman, "version", "--format", "{{.Server.Version}}")
-	// Run with sudo on linux (local), otherwise podman-remote (as podman)
+	// Run with sudo on linux (local), unless rootless, otherwise podman-remote (as podman)
 	if runtime.GOOS == "linux" {
-		cmd = exec.CommandContext(ctx, "sudo", "-k", "-n", oci.Podman, "version", "--format", "{{.Version}}")
-		cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C") // sudo is localized
+		if oci.IsRootlessForced() {
+			cmd = exec.CommandContext(ctx, oci.Podman, "version", "--format", "{{.Version}}")
+		} else {
+			cmd = exec.CommandContext(ctx, "sudo", "-k", "-n", oci.Podman, "version", "--format", "{{.Version}}")
+			cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C") // sudo is localized
+		}
 	}
 	o, err := cmd.Output()
 	output := strings.TrimSpace(string(o))
@@ -130,6 +149,9 @@ func status() registry.State {
 				out.V{"minVersion": minReqPodmanVer.String(), "currentVersion": v.String()})
 		}
 
+		if runtime.GOOS == "linux" && oci.IsRootlessForced() {
+			return rootlessStatus()
+		}
 		return registry.State{Installed: true, Healthy: true}
 	}
 
@@ -167,3 +189,19 @@ func status() registry.State {
 
 	return registry.State{Error: err, Installed: true, Healthy: false, Doc: docURL}
 }
+
+// rootlessStatus checks that the host can run the kic container with rootless podman
+func rootlessStatus() registry.State {
+	err := oci.CheckRootlessCgroups()
+	switch {
+	case err == nil:
+		return registry.State{Installed: true, Healthy: true}
+	case errors.Is(err, oci.ErrRootlessCgroupV1):
+		return registry.State{Reason: "PROVIDER_PODMAN_ROOTLESS_CGROUP_V1", Error: err, Installed: true, Healthy: false,
+			Fix: "Boot the host with cgroup v2, by adding 'systemd.unified_cgroup_hierarchy=1' to the kernel command line", Doc: rootlessDocURL}
+	case errors.Is(err, oci.ErrCgroupNotDelegated):
+		return registry.State{Reason: "PROVIDER_PODMAN_ROOTLESS_DELEGATION", Error: err, Installed: true, Healthy: false,
+			Fix: "Delegate the cgroup controllers to your user with 'sudo mkdir -p /etc/systemd/system/user@.service.d && printf \"[Service]\\nDelegate=cpu cpuset io memory pids\\n\" | sudo tee /etc/systemd/system/user@.service.d/delegate.conf && sudo systemctl daemon-reload', then log in again", Doc: rootlessDocURL}
+	}
+	return registry.State{Error: err, Installed: true, Healthy: false, Doc: rootlessDocURL}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cronFields are the names and bounds of the fields of a cron schedule
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Cron is a schedule in the standard 5 field cron format: minute, hour, day of month, month and day of week
+type Cron struct {
+	spec   string
+	fields [5]map[int]bool
+	// dom and dow are whether the day of month and day of week are restricted, which makes them match days when either does
+	dom, dow bool
+}
+
+// ParseCron parses a schedule such as "0 19 * * 1-5". Fields are '*', numbers, ranges, lists and steps such as "*/15".
+func ParseCron(spec string) (*Cron, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("%q has %d fields, expected 5: minute hour day-of-month month day-of-week", spec, len(parts))
+	}
+	c := &Cron{spec: spec}
+	for i, p := range parts {
+		values, err := parseCronField(p, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s field of %q", cronFields[i].name, spec)
+		}
+		c.fields[i] = values
+	}
+	// Sunday is both 0 and 7
+	if c.fields[4][7] {
+		c.fields[4][0] = true
+	}
+	c.dom = parts[2] != "*"
+	c.dow = parts[4] != "*"
+	return c, nil
+}
+
+// parseCronField returns the values matched by a field, between min and max
+func parseCronField(field string, min int, max int) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, item := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			s, err := strconv.Atoi(item[i+1:])
+			if err != nil || s < 1 {
+				return nil, fmt.Errorf("invalid step in %q", item)
+			}
+			step = s
+			item = item[:i]
+		}
+
+		lo, hi := min, max
+		if item != "*" {
+			bounds := strings.SplitN(item, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value %q", item)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid range %q", item)
+				}
+			} else if step != 1 {
+				// "5/15" means from 5 to the end, every 15
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%q is out of the range %d-%d", item, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// String returns the schedule, as it was parsed
+func (c *Cron) String() string {
+	return c.spec
+}
+
+// Matches returns whether the schedule fires at the minute of t
+func (c *Cron) Matches(t time.Time) bool {
+	if !c.fields[0][t.Minute()] || !c.fields[1][t.Hour()] || !c.fields[3][int(t.Month())] {
+		return false
+	}
+	dom := c.fields[2][t.Day()]
+	dow := c.fields[4][int(t.Weekday())]
+	// like cron, when both days are restricted either one matching is enough
+	if c.dom && c.dow {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// Next returns the first time after t the schedule fires, or the zero time if it does not within 4 years
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for end := t.AddDate(4, 0, 0); t.Before(end); t = t.Add(time.Minute) {
+		if c.Matches(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Last returns the last time in (after, until] the schedule fired, or the zero time if it did not
+func (c *Cron) Last(after time.Time, until time.Time) time.Time {
+	for t := until.Truncate(time.Minute); t.After(after); t = t.Add(-time.Minute) {
+		if c.Matches(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
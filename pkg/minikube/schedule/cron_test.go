@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// at returns a local time on Monday 1 November 2021, or the days after
+func at(day int, hour int, min int) time.Time {
+	return time.Date(2021, time.November, day, hour, min, 0, 0, time.Local)
+}
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"0 19 * *",
+		"0 19 * * 1-5 *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	}
+	for _, spec := range tests {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) = nil error, expected one", spec)
+		}
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	tests := []struct {
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{"0 19 * * 1-5", at(1, 19, 0), true},
+		{"0 19 * * 1-5", at(1, 19, 1), false},
+		{"0 19 * * 1-5", at(6, 19, 0), false},
+		{"*/15 * * * *", at(1, 3, 45), true},
+		{"*/15 * * * *", at(1, 3, 50), false},
+		{"5/20 * * * *", at(1, 3, 45), true},
+		{"0 9,17 * * *", at(1, 17, 0), true},
+		{"0 0 * * 7", at(7, 0, 0), true},
+		{"0 0 * * 0", at(7, 0, 0), true},
+		// when both days are restricted, either one matches
+		{"0 0 1 * 3", at(1, 0, 0), true},
+		{"0 0 1 * 3", at(3, 0, 0), true},
+		{"0 0 1 * 3", at(2, 0, 0), false},
+		{"0 0 1 * *", at(2, 0, 0), false},
+	}
+	for _, tc := range tests {
+		c, err := ParseCron(tc.spec)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tc.spec, err)
+		}
+		if got := c.Matches(tc.t); got != tc.want {
+			t.Errorf("%q.Matches(%s) = %v, want %v", tc.spec, tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestCronNextAndLast(t *testing.T) {
+	c, err := ParseCron("0 19 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.Next(at(1, 19, 0)), at(2, 19, 0); !got.Equal(want) {
+		t.Errorf("Next = %s, want %s", got, want)
+	}
+	// Friday evening to Monday evening
+	if got, want := c.Next(at(5, 20, 0)), at(8, 19, 0); !got.Equal(want) {
+		t.Errorf("Next = %s, want %s", got, want)
+	}
+	if got, want := c.Last(at(1, 0, 0), at(3, 12, 0)), at(2, 19, 0); !got.Equal(want) {
+		t.Errorf("Last = %s, want %s", got, want)
+	}
+	if got := c.Last(at(2, 19, 0), at(3, 12, 0)); !got.IsZero() {
+		t.Errorf("Last = %s, want the zero time", got)
+	}
+	never, err := ParseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := never.Next(at(1, 0, 0)); !got.IsZero() {
+		t.Errorf("Next = %s, want the zero time", got)
+	}
+}
+
+func TestPolicyDue(t *testing.T) {
+	p, err := ParsePolicy(config.LifecycleConfig{Start: "0 9 * * 1-5", Stop: "0 19 * * 1-5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		description string
+		after       time.Time
+		until       time.Time
+		want        Action
+	}{
+		{"nothing fired", at(1, 10, 0), at(1, 10, 1), NoAction},
+		{"start fired", at(1, 8, 59), at(1, 9, 0), StartAction},
+		{"stop fired", at(1, 18, 59), at(1, 19, 0), StopAction},
+		{"latest wins", at(1, 8, 0), at(1, 20, 0), StopAction},
+		{"weekend", at(6, 0, 0), at(7, 23, 59), NoAction},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := p.Due(tc.after, tc.until); got != tc.want {
+				t.Errorf("Due = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := ParsePolicy(config.LifecycleConfig{Stop: "0 25 * * *"}); err == nil {
+		t.Errorf("ParsePolicy with an invalid stop schedule = nil error, expected one")
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/VividCortex/godaemon"
 	"github.com/pkg/errors"
+	"github.com/shirou/gopsutil/v3/process"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/localpath"
@@ -66,6 +67,14 @@ func killPIDForProfile(profile string) error {
 	if err != nil {
 		return errors.Wrapf(err, "converting %s to int", f)
 	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return errors.Wrapf(err, "stat %s", file)
+	}
+	if !startedBefore(pid, info.ModTime()) {
+		klog.Infof("process %v is not the scheduled stop anymore, leaving it alone", pid)
+		return nil
+	}
 	p, err := os.FindProcess(pid)
 	if err != nil {
 		return errors.Wrap(err, "finding process")
@@ -77,6 +86,21 @@ func killPIDForProfile(profile string) error {
 	return nil
 }
 
+// startedBefore returns whether pid is a process which was created before t. The scheduled stop writes its pid file
+// after it started, so a process which reused its pid after it exited was created after the file was written.
+func startedBefore(pid int, t time.Time) bool {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return false
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		klog.Warningf("unable to get the creation time of %d: %v", pid, err)
+		return false
+	}
+	return !time.Unix(0, created*int64(time.Millisecond)).After(t)
+}
+
 func daemonize(profiles []string, duration time.Duration) error {
 	_, _, err := godaemon.MakeDaemon(&godaemon.DaemonAttr{})
 	if err != nil {
@@ -0,0 +1,208 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/daemon"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// lifecycleCatchUp is how long after its time a start or stop is still applied, when the host was asleep then
+const lifecycleCatchUp = 12 * time.Hour
+
+// Action is what the lifecycle scheduler does to a cluster
+type Action string
+
+const (
+	// NoAction leaves the cluster as it is
+	NoAction Action = ""
+	// StartAction starts the cluster
+	StartAction Action = "start"
+	// StopAction stops the cluster
+	StopAction Action = "stop"
+)
+
+// Policy is the parsed lifecycle schedules of a cluster, either of which may be nil
+type Policy struct {
+	Start *Cron
+	Stop  *Cron
+}
+
+// ParsePolicy parses the lifecycle schedules of a cluster
+func ParsePolicy(lc config.LifecycleConfig) (*Policy, error) {
+	p := &Policy{}
+	var err error
+	if lc.Start != "" {
+		if p.Start, err = ParseCron(lc.Start); err != nil {
+			return nil, errors.Wrap(err, "start schedule")
+		}
+	}
+	if lc.Stop != "" {
+		if p.Stop, err = ParseCron(lc.Stop); err != nil {
+			return nil, errors.Wrap(err, "stop schedule")
+		}
+	}
+	return p, nil
+}
+
+// Due returns the action of the schedule which fired last in (after, until], if any
+func (p *Policy) Due(after time.Time, until time.Time) Action {
+	var start, stop time.Time
+	if p.Start != nil {
+		start = p.Start.Last(after, until)
+	}
+	if p.Stop != nil {
+		stop = p.Stop.Last(after, until)
+	}
+	switch {
+	case start.IsZero() && stop.IsZero():
+		return NoAction
+	case start.After(stop):
+		return StartAction
+	default:
+		return StopAction
+	}
+}
+
+// LifecycleDaemon is the persisted state of the lifecycle scheduler running in the background
+type LifecycleDaemon struct {
+	daemon.Process
+}
+
+// lifecycleDaemonPath returns the path to the state file of the lifecycle scheduler, which serves all the profiles
+func lifecycleDaemonPath() string {
+	return filepath.Join(localpath.MiniPath(), "lifecycle.json")
+}
+
+// LifecycleLogFile returns the path to the log file of the lifecycle scheduler
+func LifecycleLogFile() string {
+	return filepath.Join(localpath.MiniPath(), "lifecycle.log")
+}
+
+// LoadLifecycleDaemon returns the lifecycle scheduler, or nil if it was never started
+func LoadLifecycleDaemon() (*LifecycleDaemon, error) {
+	d := &LifecycleDaemon{}
+	found, err := daemon.Load(lifecycleDaemonPath(), d)
+	if err != nil || !found {
+		return nil, err
+	}
+	return d, nil
+}
+
+// EnsureLifecycleDaemon starts the lifecycle scheduler as a detached process, unless it is already running
+func EnsureLifecycleDaemon() (*LifecycleDaemon, error) {
+	existing, err := LoadLifecycleDaemon()
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Running() {
+		return existing, nil
+	}
+
+	p, err := daemon.Start([]string{"schedule", "daemon", "--alsologtostderr"}, LifecycleLogFile())
+	if err != nil {
+		return nil, errors.Wrap(err, "starting lifecycle scheduler")
+	}
+	d := &LifecycleDaemon{Process: *p}
+	if err := daemon.Save(lifecycleDaemonPath(), d); err != nil {
+		return nil, errors.Wrap(err, "saving lifecycle scheduler state")
+	}
+	return d, nil
+}
+
+// RunLifecycle starts and stops the clusters on their schedules, until none has any left
+func RunLifecycle() error {
+	bin, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "locating minikube binary")
+	}
+
+	last := time.Now()
+	for {
+		// wake up just after every minute
+		time.Sleep(time.Until(time.Now().Truncate(time.Minute).Add(time.Minute + time.Second)))
+		now := time.Now()
+		after := last
+		if now.Sub(after) > lifecycleCatchUp {
+			after = now.Add(-lifecycleCatchUp)
+		}
+
+		profiles, err := config.ListValidProfiles()
+		if err != nil {
+			klog.Warningf("unable to list profiles: %v", err)
+		}
+		scheduled := 0
+		for _, p := range profiles {
+			if p.Config.Lifecycle == nil {
+				continue
+			}
+			scheduled++
+			policy, err := ParsePolicy(*p.Config.Lifecycle)
+			if err != nil {
+				klog.Errorf("invalid schedules for %s: %v", p.Name, err)
+				continue
+			}
+			if a := policy.Due(after, now); a != NoAction {
+				apply(bin, p.Name, a)
+			}
+		}
+		if err == nil && scheduled == 0 {
+			klog.Infof("no profile has a lifecycle schedule anymore, exiting")
+			return os.Remove(lifecycleDaemonPath())
+		}
+		last = now
+	}
+}
+
+// apply starts or stops a cluster by running minikube, unless it already is in the state the action leads to
+func apply(bin string, profile string, a Action) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		klog.Errorf("unable to get machine client: %v", err)
+		return
+	}
+	st, err := machine.Status(api, profile)
+	api.Close()
+	if err != nil {
+		klog.Warningf("unable to get the status of %s: %v", profile, err)
+	}
+	running := st == state.Running.String()
+	if (a == StartAction && running) || (a == StopAction && !running && err == nil) {
+		klog.Infof("%s is already %s, nothing to do", profile, st)
+		return
+	}
+
+	cmd := exec.Command(bin, string(a), "--profile", profile)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	klog.Infof("scheduled %s of %s: %v", a, profile, cmd.Args)
+	if err := cmd.Run(); err != nil {
+		klog.Errorf("scheduled %s of %s failed: %v", a, profile, err)
+		return
+	}
+	klog.Infof("scheduled %s of %s done", a, profile)
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/url"
 	"os"
 	"strconv"
@@ -29,6 +30,7 @@ import (
 	"time"
 
 	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
 	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	core "k8s.io/api/core/v1"
@@ -37,6 +39,7 @@ import (
 	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/style"
@@ -95,7 +98,7 @@ func GetServiceURLs(api libmachine.API, cname string, namespace string, t *templ
 		return nil, err
 	}
 
-	ip, err := host.Driver.GetIP()
+	ip, err := nodeAddress(host, cname)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +134,7 @@ func GetServiceURLsForService(api libmachine.API, cname string, namespace, servi
 		return SvcURL{}, errors.Wrap(err, "Error checking if api exist and loading it")
 	}
 
-	ip, err := host.Driver.GetIP()
+	ip, err := nodeAddress(host, cname)
 	if err != nil {
 		return SvcURL{}, errors.Wrap(err, "Error getting ip from host")
 	}
@@ -144,11 +147,34 @@ func GetServiceURLsForService(api libmachine.API, cname string, namespace, servi
 	return printURLsForService(client, ip, service, namespace, t)
 }
 
+// nodeAddress returns the address node ports are reachable at, in the primary IP family of the cluster
+func nodeAddress(h *host.Host, cname string) (string, error) {
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		return "", err
+	}
+	cc, err := config.Load(cname)
+	if err != nil {
+		klog.Warningf("unable to load config for %s, assuming IPv4: %v", cname, err)
+		return ip, nil
+	}
+	if cp, err := config.PrimaryControlPlane(cc); err == nil && cp.IPv6 != "" {
+		cp.IP = ip
+		return config.NodeAddress(*cc, cp), nil
+	}
+	return ip, nil
+}
+
 func printURLsForService(c typed_core.CoreV1Interface, ip, service, namespace string, t *template.Template) (SvcURL, error) {
 	if t == nil {
 		return SvcURL{}, errors.New("Error, attempted to generate service url with nil --format template")
 	}
 
+	// IPv6 literals have to be bracketed to be followed by a port
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		ip = "[" + ip + "]"
+	}
+
 	svc, err := c.Services(namespace).Get(context.Background(), service, meta.GetOptions{})
 	if err != nil {
 		return SvcURL{}, errors.Wrapf(err, "service '%s' could not be found running", service)
@@ -176,25 +202,40 @@ func printURLsForService(c typed_core.CoreV1Interface, ip, service, namespace st
 
 		if port.NodePort > 0 {
 			var doc bytes.Buffer
+			protocol := strings.ToLower(string(port.Protocol))
+			if protocol == "" {
+				protocol = "tcp"
+			}
 			err = t.Execute(&doc, struct {
-				IP   string
-				Port int32
-				Name string
+				IP       string
+				Port     int32
+				Name     string
+				Protocol string
 			}{
 				ip,
 				port.NodePort,
 				m[port.TargetPort.IntVal],
+				protocol,
 			})
 			if err != nil {
 				return SvcURL{}, err
 			}
-			urls = append(urls, doc.String())
+			urls = append(urls, withProtocolScheme(doc.String(), protocol))
 			portNames = append(portNames, m[port.TargetPort.IntVal])
 		}
 	}
 	return SvcURL{Namespace: svc.Namespace, Name: svc.Name, URLs: urls, PortNames: portNames}, nil
 }
 
+// withProtocolScheme replaces the http scheme of a URL of a UDP or SCTP port, which would
+// otherwise be opened in a browser, by the name of the protocol
+func withProtocolScheme(u string, protocol string) string {
+	if protocol == "tcp" || !strings.HasPrefix(u, "http://") {
+		return u
+	}
+	return protocol + "://" + strings.TrimPrefix(u, "http://")
+}
+
 // CheckService checks if a service is listening on a port.
 func CheckService(cname string, namespace string, service string) error {
 	client, err := K8s.GetCoreClient(cname)
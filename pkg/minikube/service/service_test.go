@@ -354,6 +354,24 @@ func TestPrintURLsForService(t *testing.T) {
 	}
 }
 
+func TestWithProtocolScheme(t *testing.T) {
+	var tests = []struct {
+		url      string
+		protocol string
+		expected string
+	}{
+		{"http://127.0.0.1:1111", "tcp", "http://127.0.0.1:1111"},
+		{"http://127.0.0.1:1111", "udp", "udp://127.0.0.1:1111"},
+		{"http://127.0.0.1:1111", "sctp", "sctp://127.0.0.1:1111"},
+		{"127.0.0.1:1111", "udp", "127.0.0.1:1111"},
+	}
+	for _, test := range tests {
+		if got := withProtocolScheme(test.url, test.protocol); got != test.expected {
+			t.Errorf("withProtocolScheme(%q, %q) = %q, expected %q", test.url, test.protocol, got, test.expected)
+		}
+	}
+}
+
 func TestOptionallyHttpsFormattedUrlString(t *testing.T) {
 
 	var tests = []struct {
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharednet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/network"
+)
+
+// firstKVMSubnet is where the search for a free subnet for a shared KVM network starts, like the private networks of the kvm2 driver
+const firstKVMSubnet = "192.168.39.0"
+
+// kvmNetworkTmpl is an isolated network, like the private networks the kvm2 driver creates, so that it can be used as one
+var kvmNetworkTmpl = template.Must(template.New("network").Parse(`<network>
+  <name>{{.Name}}</name>
+  <dns enable='no'/>
+  <ip address='{{.Gateway}}' netmask='{{.Netmask}}'>
+    <dhcp>
+      <range start='{{.ClientMin}}' end='{{.ClientMax}}'/>
+    </dhcp>
+  </ip>
+</network>
+`))
+
+// kvmNetworkXML is the part of the definition of a libvirt network holding its addresses
+type kvmNetworkXML struct {
+	IPs []struct {
+		Address string `xml:"address,attr"`
+		Netmask string `xml:"netmask,attr"`
+		Prefix  int    `xml:"prefix,attr"`
+		Family  string `xml:"family,attr"`
+	} `xml:"ip"`
+}
+
+// virsh runs virsh against the libvirt daemon at uri
+func virsh(uri string, args ...string) (string, error) {
+	if uri == "" {
+		uri = "qemu:///system"
+	}
+	c := exec.Command("virsh", append([]string{"-c", uri}, args...)...)
+	klog.Infof("running %v", c.Args)
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return string(out), errors.Wrapf(err, "%v: %s", c.Args, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// createKVMNetwork defines, starts and autostarts an isolated libvirt network on a free subnet
+func createKVMNetwork(uri string, name string) error {
+	subnet, err := network.FreeSubnet(firstKVMSubnet, 11, 20)
+	if err != nil {
+		return errors.Wrap(err, "finding a free subnet")
+	}
+	var b bytes.Buffer
+	if err := kvmNetworkTmpl.Execute(&b, struct {
+		Name string
+		*network.Parameters
+	}{name, subnet}); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile("", "minikube-network-*.xml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	f.Close()
+
+	if _, err := virsh(uri, "net-define", f.Name()); err != nil {
+		return err
+	}
+	if _, err := virsh(uri, "net-start", name); err != nil {
+		return err
+	}
+	_, err = virsh(uri, "net-autostart", name)
+	return err
+}
+
+// deleteKVMNetwork stops and undefines a libvirt network
+func deleteKVMNetwork(uri string, name string) error {
+	if _, err := virsh(uri, "net-destroy", name); err != nil {
+		// an inactive network can not be destroyed, but can be undefined
+		klog.Infof("unable to stop network %s: %v", name, err)
+	}
+	_, err := virsh(uri, "net-undefine", name)
+	return err
+}
+
+// kvmNetworkSubnet returns the IPv4 subnet of a libvirt network
+func kvmNetworkSubnet(uri string, name string) (string, error) {
+	out, err := virsh(uri, "net-dumpxml", name)
+	if err != nil {
+		return "", err
+	}
+	return parseKVMNetworkSubnet([]byte(out))
+}
+
+// parseKVMNetworkSubnet returns the IPv4 subnet in the definition of a libvirt network
+func parseKVMNetworkSubnet(data []byte) (string, error) {
+	var n kvmNetworkXML
+	if err := xml.Unmarshal(data, &n); err != nil {
+		return "", errors.Wrap(err, "parsing network definition")
+	}
+	for _, ip := range n.IPs {
+		if ip.Family != "" && ip.Family != "ipv4" {
+			continue
+		}
+		addr := net.ParseIP(ip.Address)
+		if addr == nil || addr.To4() == nil {
+			continue
+		}
+		mask := net.CIDRMask(ip.Prefix, 32)
+		if ip.Netmask != "" {
+			mask = net.IPMask(net.ParseIP(ip.Netmask).To4())
+		}
+		return (&net.IPNet{IP: addr.Mask(mask), Mask: mask}).String(), nil
+	}
+	return "", fmt.Errorf("no IPv4 address in the network definition")
+}
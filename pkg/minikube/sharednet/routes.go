@@ -0,0 +1,183 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharednet
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util"
+)
+
+// member is a running cluster on a shared network
+type member struct {
+	profile string
+	// routes to the pods and services of the cluster
+	routes []route
+}
+
+// route sends the traffic to a CIDR of another cluster through one of its nodes
+type route struct {
+	cidr string
+	via  string
+}
+
+// clusterRoutes returns the routes the nodes of each member need to reach the pods and services of the others
+func clusterRoutes(members []member) map[string][]route {
+	routes := map[string][]route{}
+	for _, m := range members {
+		for _, o := range members {
+			if o.profile == m.profile {
+				continue
+			}
+			routes[m.profile] = append(routes[m.profile], o.routes...)
+		}
+	}
+	return routes
+}
+
+// memberRoutes returns the routes to the pods of each node through the node itself, and to the services through
+// gateway, the primary control plane. The pod CIDR of the whole cluster is routed through gateway as well when a
+// node was not assigned a pod CIDR of its own, as it depends on the CNI.
+func memberRoutes(cc *config.ClusterConfig, nodes []core.Node, gateway string) []route {
+	var routes []route
+	fallback := len(nodes) == 0
+	for _, n := range nodes {
+		cidrs := n.Spec.PodCIDRs
+		if len(cidrs) == 0 && n.Spec.PodCIDR != "" {
+			cidrs = []string{n.Spec.PodCIDR}
+		}
+		if len(cidrs) == 0 {
+			fallback = true
+		}
+		for _, c := range cidrs {
+			via := nodeAddress(n, strings.Contains(c, ":"))
+			if via == "" {
+				fallback = true
+				continue
+			}
+			routes = append(routes, route{cidr: c, via: via})
+		}
+	}
+	if fallback {
+		for _, c := range util.SplitCIDRs(cc.KubernetesConfig.PodCIDR) {
+			routes = append(routes, route{cidr: c, via: gateway})
+		}
+	}
+	for _, c := range util.SplitCIDRs(cc.KubernetesConfig.ServiceCIDR) {
+		routes = append(routes, route{cidr: c, via: gateway})
+	}
+	return routes
+}
+
+// nodeAddress returns the internal address of a node in the given IP family
+func nodeAddress(n core.Node, ipv6 bool) string {
+	for _, a := range n.Status.Addresses {
+		if a.Type == core.NodeInternalIP && strings.Contains(a.Address, ":") == ipv6 {
+			return a.Address
+		}
+	}
+	return ""
+}
+
+// clusterNodes returns the Kubernetes nodes of a profile
+func clusterNodes(profile string) ([]core.Node, error) {
+	c, err := kapi.Client(profile)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := c.CoreV1().Nodes().List(context.Background(), meta.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list nodes")
+	}
+	return nodes.Items, nil
+}
+
+// SyncRoutes routes the pod and service CIDRs of every running cluster on a shared network from the nodes of the
+// others, so that pods can reach each other across clusters. Routes do not survive restarts: they are synced
+// every time a cluster on the network starts.
+func SyncRoutes(name string) error {
+	ps, err := Profiles(name)
+	if err != nil {
+		return err
+	}
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "machine client")
+	}
+	defer api.Close()
+
+	var members []member
+	running := map[string][]string{}
+	for _, p := range ps {
+		cc := p.Config
+		for _, n := range cc.Nodes {
+			machineName := config.MachineName(*cc, n)
+			if st, err := machine.Status(api, machineName); err != nil || st != state.Running.String() {
+				continue
+			}
+			running[p.Name] = append(running[p.Name], machineName)
+		}
+		cp, err := config.PrimaryControlPlane(cc)
+		if err != nil || len(running[p.Name]) == 0 || cp.IP == "" {
+			continue
+		}
+		nodes, err := clusterNodes(p.Name)
+		if err != nil {
+			klog.Warningf("unable to list the nodes of %s, routing its pods through %s: %v", p.Name, cp.IP, err)
+		}
+		members = append(members, member{profile: p.Name, routes: memberRoutes(cc, nodes, cp.IP)})
+	}
+
+	var failed []string
+	for profile, routes := range clusterRoutes(members) {
+		for _, machineName := range running[profile] {
+			h, err := machine.LoadHost(api, machineName)
+			if err != nil {
+				klog.Warningf("unable to load %s: %v", machineName, err)
+				failed = append(failed, machineName)
+				continue
+			}
+			r, err := machine.CommandRunner(h)
+			if err != nil {
+				klog.Warningf("unable to get a runner for %s: %v", machineName, err)
+				failed = append(failed, machineName)
+				continue
+			}
+			for _, rt := range routes {
+				if _, err := r.RunCmd(exec.Command("sudo", "ip", "route", "replace", rt.cidr, "via", rt.via)); err != nil {
+					klog.Warningf("unable to route %s via %s on %s: %v", rt.cidr, rt.via, machineName, err)
+					failed = append(failed, machineName)
+					break
+				}
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("unable to route to the other clusters from %v", failed)
+	}
+	return nil
+}
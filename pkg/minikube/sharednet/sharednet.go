@@ -0,0 +1,287 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharednet manages networks shared by several profiles, so that their clusters can reach each other
+package sharednet
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// maxClusters is how many clusters can share a network, each with its own pod and service CIDRs
+const maxClusters = 50
+
+// Network is a network created with 'minikube network create', which profiles join with 'minikube start --network'
+type Network struct {
+	Name    string
+	Driver  string
+	Created time.Time
+}
+
+// Supported returns whether profiles of a driver can share a network
+func Supported(driverName string) bool {
+	return driver.IsKIC(driverName) || driver.IsKVM(driverName)
+}
+
+// dir returns the directory shared networks are saved in
+func dir() string {
+	return filepath.Join(localpath.MiniPath(), "networks")
+}
+
+// path returns the path a shared network is saved at
+func path(name string) string {
+	return filepath.Join(dir(), name+".json")
+}
+
+// Load returns the shared network with the given name, or nil if there is none
+func Load(name string) (*Network, error) {
+	if name == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path(name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading shared network %s", name)
+	}
+	n := &Network{}
+	if err := json.Unmarshal(data, n); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path(name))
+	}
+	return n, nil
+}
+
+// List returns the shared networks, sorted by name
+func List() ([]*Network, error) {
+	files, err := filepath.Glob(filepath.Join(dir(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var ns []*Network
+	for _, f := range files {
+		n, err := Load(strings.TrimSuffix(filepath.Base(f), ".json"))
+		if err != nil {
+			klog.Warningf("skipping %s: %v", f, err)
+			continue
+		}
+		ns = append(ns, n)
+	}
+	sort.Slice(ns, func(i, j int) bool { return ns[i].Name < ns[j].Name })
+	return ns, nil
+}
+
+// Create creates a network for the driver, and saves it as shared
+func Create(name string, driverName string, qemuURI string) (*Network, error) {
+	if !Supported(driverName) {
+		return nil, fmt.Errorf("the %s driver does not support shared networks, use docker, podman or kvm2", driverName)
+	}
+	existing, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("shared network %s already exists", name)
+	}
+
+	if driver.IsKIC(driverName) {
+		if _, err := oci.CreateNetwork(driverName, name, false); err != nil {
+			return nil, errors.Wrapf(err, "creating %s network", driverName)
+		}
+	} else if err := createKVMNetwork(qemuURI, name); err != nil {
+		return nil, errors.Wrap(err, "creating KVM network")
+	}
+
+	n := &Network{Name: name, Driver: driverName, Created: time.Now()}
+	data, err := json.MarshalIndent(n, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir(), 0o755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path(name), data, 0o644); err != nil {
+		return nil, errors.Wrap(err, "saving shared network")
+	}
+	return n, nil
+}
+
+// Delete deletes a shared network, which no profile may be using
+func Delete(n *Network, qemuURI string) error {
+	ps, err := Profiles(n.Name)
+	if err != nil {
+		return err
+	}
+	if len(ps) > 0 {
+		var names []string
+		for _, p := range ps {
+			names = append(names, p.Name)
+		}
+		return fmt.Errorf("shared network %s is used by %s", n.Name, strings.Join(names, ", "))
+	}
+
+	if driver.IsKIC(n.Driver) {
+		if err := oci.RemoveNetwork(n.Driver, n.Name); err != nil {
+			return errors.Wrapf(err, "removing %s network", n.Driver)
+		}
+	} else if err := deleteKVMNetwork(qemuURI, n.Name); err != nil {
+		return errors.Wrap(err, "removing KVM network")
+	}
+	return os.Remove(path(n.Name))
+}
+
+// Subnet returns the subnet of the network, or an empty string if it does not exist anymore: it is then created
+// again by the next profile to start on it.
+func (n *Network) Subnet(qemuURI string) string {
+	if driver.IsKIC(n.Driver) {
+		s, err := oci.NetworkSubnet(n.Driver, n.Name)
+		if err != nil || s == nil {
+			klog.Infof("unable to get the subnet of %s: %v", n.Name, err)
+			return ""
+		}
+		return s.String()
+	}
+	s, err := kvmNetworkSubnet(qemuURI, n.Name)
+	if err != nil {
+		klog.Infof("unable to get the subnet of %s: %v", n.Name, err)
+		return ""
+	}
+	return s
+}
+
+// NodeIP returns the address of a new node of cc on its shared KIC network. The nodes of every profile on the
+// network share its subnet, so the addresses of the containers on it and those saved for the nodes of the
+// profiles, which their stopped containers get back, are skipped. An empty address is returned when cc is not
+// on a shared KIC network or the network does not exist yet, for the driver to derive it from the gateway.
+func NodeIP(cc config.ClusterConfig) (string, error) {
+	n, err := Load(cc.Network)
+	if err != nil || n == nil || !driver.IsKIC(n.Driver) {
+		return "", err
+	}
+	subnet, err := oci.NetworkSubnet(n.Driver, n.Name)
+	if errors.Is(err, oci.ErrNetworkNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "inspecting %s", n.Name)
+	}
+	used, err := oci.NetworkContainerIPs(n.Driver, n.Name)
+	if err != nil {
+		return "", err
+	}
+	ps, err := Profiles(n.Name)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range ps {
+		for _, node := range p.Config.Nodes {
+			if node.IP != "" {
+				used = append(used, node.IP)
+			}
+		}
+	}
+	return freeIP(subnet, used)
+}
+
+// freeIP returns the first address of subnet after its gateway which is not used
+func freeIP(subnet *net.IPNet, used []string) (string, error) {
+	taken := map[string]bool{}
+	for _, u := range used {
+		taken[u] = true
+	}
+	first := subnet.IP.To4()
+	if first == nil {
+		return "", fmt.Errorf("%s is not an IPv4 subnet", subnet)
+	}
+	ones, bits := subnet.Mask.Size()
+	size := uint32(1) << uint(bits-ones)
+	// skip the address of the network and the gateway, and stop before broadcast
+	for i := uint32(2); i+1 < size; i++ {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(first)+i)
+		if !taken[ip.String()] {
+			return ip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no free address left in %s", subnet)
+}
+
+// Profiles returns the profiles on a shared network
+func Profiles(name string) ([]*config.Profile, error) {
+	ps, err := config.ListValidProfiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing profiles")
+	}
+	var on []*config.Profile
+	for _, p := range ps {
+		if p.Config != nil && p.Config.Network == name && Supported(p.Config.Driver) {
+			on = append(on, p)
+		}
+	}
+	return on, nil
+}
+
+// clusterCIDRs returns the pod and service CIDRs of the i-th cluster on a shared network
+func clusterCIDRs(i int) (string, string) {
+	return fmt.Sprintf("10.%d.0.0/16", 200+i), fmt.Sprintf("10.%d.0.0/16", 150+i)
+}
+
+// AllocateCIDRs returns pod and service CIDRs for a new cluster on a shared network, which do not overlap
+// those of the other clusters on it
+func AllocateCIDRs(name string, profile string) (string, string, error) {
+	ps, err := Profiles(name)
+	if err != nil {
+		return "", "", err
+	}
+	var used []string
+	for _, p := range ps {
+		if p.Name != profile {
+			used = append(used, p.Config.KubernetesConfig.PodCIDR)
+		}
+	}
+	return freeCIDRs(used)
+}
+
+// freeCIDRs returns the first pod and service CIDRs whose pod CIDR is not used
+func freeCIDRs(used []string) (string, string, error) {
+	taken := map[string]bool{}
+	for _, u := range used {
+		taken[u] = true
+	}
+	for i := 0; i < maxClusters; i++ {
+		pod, svc := clusterCIDRs(i)
+		if !taken[pod] {
+			return pod, svc, nil
+		}
+	}
+	return "", "", fmt.Errorf("no free CIDRs left, at most %d clusters can share a network", maxClusters)
+}
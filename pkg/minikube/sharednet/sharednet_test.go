@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharednet
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	core "k8s.io/api/core/v1"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestFreeCIDRs(t *testing.T) {
+	tests := []struct {
+		description string
+		used        []string
+		pod         string
+		svc         string
+	}{
+		{"first cluster", nil, "10.200.0.0/16", "10.150.0.0/16"},
+		{"second cluster", []string{"10.200.0.0/16"}, "10.201.0.0/16", "10.151.0.0/16"},
+		{"fills the gap of a deleted cluster", []string{"10.200.0.0/16", "10.202.0.0/16"}, "10.201.0.0/16", "10.151.0.0/16"},
+		{"cluster with the default CIDR", []string{""}, "10.200.0.0/16", "10.150.0.0/16"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			pod, svc, err := freeCIDRs(tc.used)
+			if err != nil {
+				t.Fatalf("freeCIDRs: %v", err)
+			}
+			if pod != tc.pod || svc != tc.svc {
+				t.Errorf("freeCIDRs(%v) = %s, %s, want %s, %s", tc.used, pod, svc, tc.pod, tc.svc)
+			}
+		})
+	}
+
+	var full []string
+	for i := 0; i < maxClusters; i++ {
+		pod, _ := clusterCIDRs(i)
+		full = append(full, pod)
+	}
+	if _, _, err := freeCIDRs(full); err == nil {
+		t.Errorf("freeCIDRs on a full network = nil error, expected one")
+	}
+}
+
+func TestClusterRoutes(t *testing.T) {
+	members := []member{
+		{profile: "east", routes: []route{{cidr: "10.200.0.0/24", via: "192.168.49.2"}, {cidr: "10.150.0.0/16", via: "192.168.49.2"}}},
+		{profile: "west", routes: []route{{cidr: "10.201.0.0/24", via: "192.168.49.3"}, {cidr: "10.201.1.0/24", via: "192.168.49.4"}}},
+	}
+	want := map[string][]route{
+		"east": {{cidr: "10.201.0.0/24", via: "192.168.49.3"}, {cidr: "10.201.1.0/24", via: "192.168.49.4"}},
+		"west": {{cidr: "10.200.0.0/24", via: "192.168.49.2"}, {cidr: "10.150.0.0/16", via: "192.168.49.2"}},
+	}
+	if diff := cmp.Diff(want, clusterRoutes(members), cmp.AllowUnexported(route{})); diff != "" {
+		t.Errorf("clusterRoutes mismatch (-want +got):\n%s", diff)
+	}
+	if got := clusterRoutes(members[:1]); len(got) != 0 {
+		t.Errorf("clusterRoutes of a single cluster = %v, want none", got)
+	}
+}
+
+func TestMemberRoutes(t *testing.T) {
+	cc := &config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{PodCIDR: "10.201.0.0/16", ServiceCIDR: "10.151.0.0/16"}}
+	node := func(ip string, podCIDR string) core.Node {
+		return core.Node{
+			Spec:   core.NodeSpec{PodCIDR: podCIDR},
+			Status: core.NodeStatus{Addresses: []core.NodeAddress{{Type: core.NodeHostName, Address: "west"}, {Type: core.NodeInternalIP, Address: ip}}},
+		}
+	}
+	tests := []struct {
+		description string
+		nodes       []core.Node
+		want        []route
+	}{
+		{
+			description: "pods through their node",
+			nodes:       []core.Node{node("192.168.49.3", "10.201.0.0/24"), node("192.168.49.4", "10.201.1.0/24")},
+			want: []route{
+				{cidr: "10.201.0.0/24", via: "192.168.49.3"},
+				{cidr: "10.201.1.0/24", via: "192.168.49.4"},
+				{cidr: "10.151.0.0/16", via: "192.168.49.3"},
+			},
+		},
+		{
+			description: "node without pod CIDR",
+			nodes:       []core.Node{node("192.168.49.3", "10.201.0.0/24"), node("192.168.49.4", "")},
+			want: []route{
+				{cidr: "10.201.0.0/24", via: "192.168.49.3"},
+				{cidr: "10.201.0.0/16", via: "192.168.49.3"},
+				{cidr: "10.151.0.0/16", via: "192.168.49.3"},
+			},
+		},
+		{
+			description: "nodes unknown",
+			want: []route{
+				{cidr: "10.201.0.0/16", via: "192.168.49.3"},
+				{cidr: "10.151.0.0/16", via: "192.168.49.3"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := memberRoutes(cc, tc.nodes, "192.168.49.3")
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(route{})); diff != "" {
+				t.Errorf("memberRoutes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFreeIP(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("192.168.58.0/29")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		description string
+		used        []string
+		want        string
+		wantErr     bool
+	}{
+		{"empty network", nil, "192.168.58.2", false},
+		{"skips used addresses", []string{"192.168.58.2", "192.168.58.3", "192.168.58.5"}, "192.168.58.4", false},
+		{"ignores addresses of other networks", []string{"192.168.49.2"}, "192.168.58.2", false},
+		{"full network", []string{"192.168.58.2", "192.168.58.3", "192.168.58.4", "192.168.58.5", "192.168.58.6"}, "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := freeIP(subnet, tc.used)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("freeIP error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("freeIP(%v) = %q, want %q", tc.used, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseKVMNetworkSubnet(t *testing.T) {
+	tests := []struct {
+		description string
+		xml         string
+		want        string
+		wantErr     bool
+	}{
+		{
+			description: "netmask",
+			xml:         `<network><name>shared</name><ip address='192.168.39.1' netmask='255.255.255.0'><dhcp><range start='192.168.39.2' end='192.168.39.253'/></dhcp></ip></network>`,
+			want:        "192.168.39.0/24",
+		},
+		{
+			description: "prefix after an IPv6 address",
+			xml:         `<network><ip family='ipv6' address='fd00::1' prefix='64'/><ip address='192.168.50.1' prefix='24'/></network>`,
+			want:        "192.168.50.0/24",
+		},
+		{
+			description: "no address",
+			xml:         `<network><name>shared</name></network>`,
+			wantErr:     true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := parseKVMNetworkSubnet([]byte(tc.xml))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseKVMNetworkSubnet error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("parseKVMNetworkSubnet = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
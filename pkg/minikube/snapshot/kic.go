@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+)
+
+// kicSnapshotter saves the /var volume of docker and podman nodes, which holds
+// etcd, the container runtime storage and the kubelet state.
+type kicSnapshotter struct {
+	ociBin string
+	// image is used to run tar against the node volume
+	image string
+}
+
+func tarball(dir string, machineName string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.tar", machineName))
+}
+
+// freeze pauses the node, so that etcd and the container runtime are captured at a single point in time
+func (k *kicSnapshotter) freeze(_ libmachine.API, machineName string) error {
+	return errors.Wrap(oci.PauseContainer(k.ociBin, machineName), "pause")
+}
+
+func (k *kicSnapshotter) thaw(_ libmachine.API, machineName string) error {
+	return errors.Wrap(oci.UnpauseContainer(k.ociBin, machineName), "unpause")
+}
+
+func (k *kicSnapshotter) save(machineName string, dir string) error {
+	return oci.SaveVolumeToTarball(k.ociBin, machineName, tarball(dir, machineName), k.image)
+}
+
+func (k *kicSnapshotter) restore(machineName string, dir string) error {
+	return oci.RestoreVolumeFromTarball(k.ociBin, tarball(dir, machineName), machineName, k.image)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// kvmSnapshotter keeps a compressed qcow2 copy of the raw disk of KVM nodes.
+// The disk is only consistent while the VM is off, so saving restarts the node.
+type kvmSnapshotter struct{}
+
+// diskPath returns the disk image the kvm2 driver creates for a machine
+func diskPath(machineName string) string {
+	return filepath.Join(localpath.MachinePath(machineName), fmt.Sprintf("%s.rawdisk", machineName))
+}
+
+func qcow2(dir string, machineName string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.qcow2", machineName))
+}
+
+// freeze stops the VM, as its disk can only be copied consistently while it isn't running
+func (k *kvmSnapshotter) freeze(api libmachine.API, machineName string) error {
+	return errors.Wrap(machine.StopHost(api, machineName), "stop")
+}
+
+func (k *kvmSnapshotter) thaw(api libmachine.API, machineName string) error {
+	return errors.Wrap(startMachine(api, machineName), "start")
+}
+
+func (k *kvmSnapshotter) save(machineName string, dir string) error {
+	return qemuImgConvert("qcow2", diskPath(machineName), qcow2(dir, machineName))
+}
+
+func (k *kvmSnapshotter) restore(machineName string, dir string) error {
+	return qemuImgConvert("raw", qcow2(dir, machineName), diskPath(machineName))
+}
+
+// qemuImgConvert copies the disk image src to dst in the given format, skipping unallocated blocks
+func qemuImgConvert(format string, src string, dst string) error {
+	path, err := exec.LookPath("qemu-img")
+	if err != nil {
+		return errors.Wrap(err, "snapshots of KVM nodes require qemu-img")
+	}
+	args := []string{"convert", "-O", format}
+	if format == "qcow2" {
+		args = append(args, "-c")
+	}
+	args = append(args, src, dst)
+	klog.Infof("running: %s %v", path, args)
+	if out, err := exec.Command(path, args...).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "qemu-img convert: %s", out)
+	}
+	return nil
+}
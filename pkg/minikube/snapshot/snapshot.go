@@ -0,0 +1,321 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot checkpoints the machines of a cluster so that they can be restored later
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+)
+
+const metadataFile = "snapshot.json"
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Snapshot describes a checkpoint of every node of a cluster
+type Snapshot struct {
+	Name              string
+	Profile           string
+	Driver            string
+	KubernetesVersion string
+	Created           time.Time
+	// Machines are the names of the machines captured, in node order
+	Machines []string
+}
+
+// snapshotter saves and restores the state of a single machine for a driver
+type snapshotter interface {
+	// freeze keeps a machine from changing its state until thaw is called
+	freeze(api libmachine.API, machineName string) error
+	thaw(api libmachine.API, machineName string) error
+	// save writes the state of a frozen machine to dir
+	save(machineName string, dir string) error
+	// restore replaces the state of a stopped machine with the one saved in dir
+	restore(machineName string, dir string) error
+}
+
+// Dir returns the directory where the snapshots of a profile are stored
+func Dir(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "snapshots")
+}
+
+// Supported returns whether clusters running on a driver can be snapshotted
+func Supported(drvName string) bool {
+	return driver.IsKIC(drvName) || driver.IsKVM(drvName)
+}
+
+func newSnapshotter(cc config.ClusterConfig) (snapshotter, error) {
+	switch {
+	case driver.IsKIC(cc.Driver):
+		return &kicSnapshotter{ociBin: cc.Driver, image: cc.KicBaseImage}, nil
+	case driver.IsKVM(cc.Driver):
+		return &kvmSnapshotter{}, nil
+	default:
+		return nil, fmt.Errorf("snapshots are not supported by the %s driver", cc.Driver)
+	}
+}
+
+// Create checkpoints every node of a cluster as a snapshot called name
+func Create(api libmachine.API, cc config.ClusterConfig, name string) (*Snapshot, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	s, err := newSnapshotter(cc)
+	if err != nil {
+		return nil, err
+	}
+	return create(api, cc, s, name)
+}
+
+func create(api libmachine.API, cc config.ClusterConfig, s snapshotter, name string) (*Snapshot, error) {
+	dir := filepath.Join(Dir(cc.Name), name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("snapshot %q already exists", name)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "mkdir %s", dir)
+	}
+
+	snap := &Snapshot{
+		Name:              name,
+		Profile:           cc.Name,
+		Driver:            cc.Driver,
+		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		Created:           time.Now(),
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			klog.Warningf("unable to clean up %s: %v", dir, err)
+		}
+	}
+
+	// Freeze every node before saving any, so the snapshot is consistent
+	// across nodes, e.g. etcd against the workloads of the workers
+	for _, n := range cc.Nodes {
+		m := config.MachineName(cc, n)
+		if err := s.freeze(api, m); err != nil {
+			thawAll(api, s, snap.Machines)
+			cleanup()
+			return nil, errors.Wrapf(err, "freezing %s", m)
+		}
+		snap.Machines = append(snap.Machines, m)
+	}
+	defer thawAll(api, s, snap.Machines)
+
+	for _, m := range snap.Machines {
+		klog.Infof("saving %s to snapshot %s", m, name)
+		if err := s.save(m, dir); err != nil {
+			cleanup()
+			return nil, errors.Wrapf(err, "saving %s", m)
+		}
+	}
+
+	b, err := json.MarshalIndent(snap, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, metadataFile), b, 0644); err != nil {
+		return nil, errors.Wrap(err, "writing snapshot metadata")
+	}
+	return snap, nil
+}
+
+// thawAll resumes frozen machines, logging failures as the snapshot itself is already done or abandoned
+func thawAll(api libmachine.API, s snapshotter, machines []string) {
+	for _, m := range machines {
+		if err := s.thaw(api, m); err != nil {
+			klog.Errorf("unable to resume %s: %v", m, err)
+		}
+	}
+}
+
+// Restore brings every node of a cluster back to the state captured in the snapshot called name
+func Restore(api libmachine.API, cc config.ClusterConfig, name string) (*Snapshot, error) {
+	snap, err := load(cc.Name, name)
+	if err != nil {
+		return nil, err
+	}
+	if snap.Driver != cc.Driver {
+		return nil, fmt.Errorf("snapshot %q was taken with the %s driver, but the cluster uses %s", name, snap.Driver, cc.Driver)
+	}
+	machines := []string{}
+	for _, n := range cc.Nodes {
+		machines = append(machines, config.MachineName(cc, n))
+	}
+	if !sameMachines(machines, snap.Machines) {
+		return nil, fmt.Errorf("snapshot %q has nodes %v, but the cluster has %v", name, snap.Machines, machines)
+	}
+	s, err := newSnapshotter(cc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := restore(api, s, snap, filepath.Join(Dir(cc.Name), name)); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// stopMachine and startMachine are variables so that tests can replace them
+var (
+	stopMachine  = machine.StopHost
+	startMachine = bootMachine
+)
+
+func restore(api libmachine.API, s snapshotter, snap *Snapshot, dir string) error {
+	// if the restore fails midway, the machines stopped so far are started again rather than left down
+	stopped := []string{}
+	restart := func(err error) error {
+		if serr := startAll(api, stopped); serr != nil {
+			klog.Errorf("unable to restart the cluster after a failed restore: %v", serr)
+		}
+		return err
+	}
+	for _, m := range snap.Machines {
+		if err := stopMachine(api, m); err != nil {
+			return restart(errors.Wrapf(err, "stopping %s", m))
+		}
+		stopped = append(stopped, m)
+		if err := s.restore(m, dir); err != nil {
+			return restart(errors.Wrapf(err, "restoring %s", m))
+		}
+	}
+	return startAll(api, snap.Machines)
+}
+
+// startAll starts every machine, even if some fail to, and returns the first error
+func startAll(api libmachine.API, machines []string) error {
+	var first error
+	for _, m := range machines {
+		if err := startMachine(api, m); err != nil {
+			klog.Errorf("unable to start %s: %v", m, err)
+			if first == nil {
+				first = errors.Wrapf(err, "starting %s", m)
+			}
+		}
+	}
+	return first
+}
+
+// bootMachine boots a machine and its kubelet, which brings the rest of the restored cluster up
+func bootMachine(api libmachine.API, machineName string) error {
+	h, err := machine.LoadHost(api, machineName)
+	if err != nil {
+		return err
+	}
+	if err := h.Start(); err != nil {
+		return err
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		return err
+	}
+	return sysinit.New(r).Start("kubelet")
+}
+
+// Size returns the disk space used by a snapshot in bytes
+func (s *Snapshot) Size() (int64, error) {
+	var size int64
+	err := filepath.Walk(filepath.Join(Dir(s.Profile), s.Name), func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// List returns the snapshots of a profile, oldest first
+func List(profile string) ([]*Snapshot, error) {
+	entries, err := ioutil.ReadDir(Dir(profile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snaps := []*Snapshot{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		s, err := load(profile, e.Name())
+		if err != nil {
+			klog.Warningf("skipping invalid snapshot %s: %v", e.Name(), err)
+			continue
+		}
+		snaps = append(snaps, s)
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Created.Before(snaps[j].Created) })
+	return snaps, nil
+}
+
+// checkName returns an error if name can't be used as a snapshot name, such as one escaping the snapshot directory
+func checkName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q: only alphanumerics, '_', '.' and '-' are allowed", name)
+	}
+	return nil
+}
+
+func load(profile string, name string) (*Snapshot, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(filepath.Join(Dir(profile), name, metadataFile))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("snapshot %q not found", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, errors.Wrapf(err, "parsing snapshot %q", name)
+	}
+	return s, nil
+}
+
+func sameMachines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
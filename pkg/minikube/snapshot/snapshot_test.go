@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestList(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, tmp)
+
+	snaps, err := List("p1")
+	if err != nil || len(snaps) != 0 {
+		t.Fatalf("List() of a profile without snapshots = %v, %v, want none", snaps, err)
+	}
+
+	now := time.Now()
+	for name, created := range map[string]time.Time{
+		"newest": now,
+		"oldest": now.Add(-time.Hour),
+	} {
+		dir := filepath.Join(Dir("p1"), name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		b, _ := json.Marshal(Snapshot{Name: name, Profile: "p1", Created: created})
+		if err := ioutil.WriteFile(filepath.Join(dir, metadataFile), b, 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	// a snapshot interrupted before its metadata was written
+	if err := os.MkdirAll(filepath.Join(Dir("p1"), "partial"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	snaps, err = List("p1")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var names []string
+	for _, s := range snaps {
+		names = append(names, s.Name)
+	}
+	if len(names) != 2 || names[0] != "oldest" || names[1] != "newest" {
+		t.Errorf("List() = %v, want [oldest newest]", names)
+	}
+}
+
+func TestCreateValidation(t *testing.T) {
+	tests := []struct {
+		desc string
+		cc   config.ClusterConfig
+		name string
+	}{
+		{"empty name", config.ClusterConfig{Name: "p1", Driver: "docker"}, ""},
+		{"path in name", config.ClusterConfig{Name: "p1", Driver: "docker"}, "../p2"},
+		{"unsupported driver", config.ClusterConfig{Name: "p1", Driver: "virtualbox"}, "good"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := Create(nil, tc.cc, tc.name); err == nil {
+				t.Errorf("Create(%q) succeeded, want an error", tc.name)
+			}
+		})
+	}
+}
+
+// fakeSnapshotter records the operations run against each machine, in order
+type fakeSnapshotter struct {
+	ops     []string
+	failing string
+}
+
+func (f *fakeSnapshotter) record(op string, machineName string) error {
+	f.ops = append(f.ops, op+" "+machineName)
+	if op+" "+machineName == f.failing {
+		return fmt.Errorf("%s failed", f.failing)
+	}
+	return nil
+}
+
+func (f *fakeSnapshotter) freeze(_ libmachine.API, machineName string) error {
+	return f.record("freeze", machineName)
+}
+
+func (f *fakeSnapshotter) thaw(_ libmachine.API, machineName string) error {
+	return f.record("thaw", machineName)
+}
+
+func (f *fakeSnapshotter) save(machineName string, _ string) error {
+	return f.record("save", machineName)
+}
+
+func (f *fakeSnapshotter) restore(machineName string, _ string) error {
+	return f.record("restore", machineName)
+}
+
+func TestCreateFreezesAllNodesFirst(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, tmp)
+
+	cc := config.ClusterConfig{Name: "p1", Driver: "docker", Nodes: []config.Node{{Name: "", ControlPlane: true}, {Name: "m02"}}}
+	tests := []struct {
+		desc    string
+		failing string
+		want    []string
+	}{
+		{"success", "", []string{"freeze p1", "freeze p1-m02", "save p1", "save p1-m02", "thaw p1", "thaw p1-m02"}},
+		{"freeze failure", "freeze p1-m02", []string{"freeze p1", "freeze p1-m02", "thaw p1"}},
+		{"save failure", "save p1", []string{"freeze p1", "freeze p1-m02", "save p1", "thaw p1", "thaw p1-m02"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := &fakeSnapshotter{failing: tc.failing}
+			_, err := create(nil, cc, f, strings.ReplaceAll(tc.desc, " ", "-"))
+			if (err != nil) != (tc.failing != "") {
+				t.Errorf("create() error = %v, want failure = %v", err, tc.failing != "")
+			}
+			if strings.Join(f.ops, ", ") != strings.Join(tc.want, ", ") {
+				t.Errorf("operations = %v, want %v", f.ops, tc.want)
+			}
+		})
+	}
+}
+
+func TestRestoreRestartsStoppedNodes(t *testing.T) {
+	defer func(stop, start func(libmachine.API, string) error) {
+		stopMachine, startMachine = stop, start
+	}(stopMachine, startMachine)
+
+	snap := &Snapshot{Name: "s1", Profile: "p1", Machines: []string{"p1", "p1-m02"}}
+	tests := []struct {
+		desc    string
+		failing string
+		want    []string
+	}{
+		{"success", "", []string{"stop p1", "restore p1", "stop p1-m02", "restore p1-m02", "start p1", "start p1-m02"}},
+		{"restore failure", "restore p1-m02", []string{"stop p1", "restore p1", "stop p1-m02", "restore p1-m02", "start p1", "start p1-m02"}},
+		{"stop failure", "stop p1-m02", []string{"stop p1", "restore p1", "stop p1-m02", "start p1"}},
+		{"start failure", "start p1", []string{"stop p1", "restore p1", "stop p1-m02", "restore p1-m02", "start p1", "start p1-m02"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := &fakeSnapshotter{failing: tc.failing}
+			stopMachine = func(_ libmachine.API, m string) error { return f.record("stop", m) }
+			startMachine = func(_ libmachine.API, m string) error { return f.record("start", m) }
+			err := restore(nil, f, snap, "")
+			if (err != nil) != (tc.failing != "") {
+				t.Errorf("restore() error = %v, want failure = %v", err, tc.failing != "")
+			}
+			if strings.Join(f.ops, ", ") != strings.Join(tc.want, ", ") {
+				t.Errorf("operations = %v, want %v", f.ops, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	for _, name := range []string{"", "../p2", "a/b"} {
+		if _, err := load("p1", name); err == nil || strings.Contains(err.Error(), "not found") {
+			t.Errorf("load(%q) = %v, want an invalid name error", name, err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshforward
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/daemon"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// daemonStopTimeout is how long the port forwarder gets to stop ssh before being killed
+const daemonStopTimeout = 10 * time.Second
+
+// Daemon is the persisted state of the port forwarder of a profile, which forwards the ports published by its
+// containers on a daemon reached over ssh, see 'minikube start --docker-host'
+type Daemon struct {
+	daemon.Process
+	Profile string
+}
+
+// daemonPath returns the path to the state file of the port forwarder of a profile
+func daemonPath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "sshforward.json")
+}
+
+// DaemonLogFile returns the path to the log file of the port forwarder of a profile
+func DaemonLogFile(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "sshforward.log")
+}
+
+// LoadDaemon returns the port forwarder of a profile, or nil if it was never started
+func LoadDaemon(profile string) (*Daemon, error) {
+	d := &Daemon{}
+	found, err := daemon.Load(daemonPath(profile), d)
+	if err != nil || !found {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DaemonRunning returns whether the port forwarder of a profile is running
+func DaemonRunning(profile string) bool {
+	d, err := LoadDaemon(profile)
+	if err != nil {
+		klog.Warningf("unable to load port forwarder of %s: %v", profile, err)
+		return false
+	}
+	return d != nil && d.Running()
+}
+
+// StartDaemon runs 'minikube ssh-forward' for a profile as a detached process, which keeps
+// the ports of the profile forwarded until StopDaemon is called.
+func StartDaemon(profile string, driver string) (*Daemon, error) {
+	existing, err := LoadDaemon(profile)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Running() {
+		return nil, fmt.Errorf("a port forwarder is already running for %q with pid %d", profile, existing.Pid)
+	}
+
+	if err := os.MkdirAll(localpath.Profile(profile), 0755); err != nil {
+		return nil, errors.Wrap(err, "creating profile directory")
+	}
+	p, err := daemon.Start([]string{"ssh-forward", "--profile", profile, "--driver", driver, "--alsologtostderr"}, DaemonLogFile(profile))
+	if err != nil {
+		return nil, errors.Wrap(err, "starting port forwarder")
+	}
+	d := &Daemon{Process: *p, Profile: profile}
+	if err := daemon.Save(daemonPath(profile), d); err != nil {
+		return nil, errors.Wrap(err, "saving port forwarder state")
+	}
+	return d, nil
+}
+
+// RestartDaemon stops the port forwarder of a profile if it is running, and starts it again,
+// so that it picks up changes to the daemon host of the profile.
+func RestartDaemon(profile string, driver string) (*Daemon, error) {
+	if _, err := StopDaemon(profile); err != nil {
+		return nil, err
+	}
+	return StartDaemon(profile, driver)
+}
+
+// StopDaemon stops the port forwarder of a profile, which stops forwarding its ports.
+// It returns whether a port forwarder was found.
+func StopDaemon(profile string) (bool, error) {
+	d, err := LoadDaemon(profile)
+	if err != nil || d == nil {
+		return false, err
+	}
+	if err := d.Stop(daemonStopTimeout); err != nil {
+		return true, errors.Wrap(err, "stopping port forwarder")
+	}
+	return true, daemon.Remove(daemonPath(profile))
+}
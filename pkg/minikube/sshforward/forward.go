@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sshforward forwards the ports published by the containers of a profile on a daemon reached over ssh,
+// such as with 'minikube start --docker-host=ssh://user@host', to the same ports of this host
+package sshforward
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+)
+
+// pollInterval is how often the published ports of the containers of the profile are checked.
+// It is short, as provisioning waits for the ssh port of new nodes to be forwarded.
+const pollInterval = 2 * time.Second
+
+// forwarder is an ssh process forwarding a set of ports
+type forwarder struct {
+	ports []int
+	cmd   *exec.Cmd
+	done  chan struct{}
+}
+
+// exited returns whether the ssh process exited
+func (f *forwarder) exited() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop stops the ssh process
+func (f *forwarder) stop() {
+	if f.exited() {
+		return
+	}
+	if err := f.cmd.Process.Kill(); err != nil {
+		klog.Warningf("unable to kill ssh %d: %v", f.cmd.Process.Pid, err)
+	}
+	<-f.done
+}
+
+// Run forwards the ports published by the containers of a profile until ctx is done, restarting ssh whenever
+// they change, as nodes are added, restarted or deleted, or when the connection to the daemon host is lost
+func Run(ctx context.Context, profile string, driver string) error {
+	if !oci.IsSSHDaemonHost(driver) {
+		return fmt.Errorf("the %s daemon of %s is not reached over ssh", driver, profile)
+	}
+	ssh, err := exec.LookPath("ssh")
+	if err != nil {
+		return errors.Wrap(err, "ssh is required to forward the ports of a remote daemon")
+	}
+
+	var f *forwarder
+	defer func() {
+		if f != nil {
+			f.stop()
+		}
+	}()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		ports, err := publishedPorts(ctx, profile, driver)
+		if err != nil {
+			klog.Warningf("unable to list the published ports of %s: %v", profile, err)
+		} else if f == nil || f.exited() || !reflect.DeepEqual(ports, f.ports) {
+			if f != nil {
+				f.stop()
+				f = nil
+			}
+			if len(ports) > 0 {
+				if f, err = forward(ssh, driver, ports); err != nil {
+					klog.Warningf("unable to forward %v: %v", ports, err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishedPorts returns the ports published by the containers of a profile, sorted
+func publishedPorts(ctx context.Context, profile string, driver string) ([]int, error) {
+	names, err := oci.ListContainersByLabel(ctx, driver, fmt.Sprintf("%s=%s", oci.ProfileLabelKey, profile))
+	if err != nil {
+		return nil, err
+	}
+	var ports []int
+	for _, n := range names {
+		ps, err := oci.PublishedPorts(driver, n)
+		if err != nil {
+			// stopped containers have no published ports
+			klog.Infof("no published ports for %s: %v", n, err)
+			continue
+		}
+		ports = append(ports, ps...)
+	}
+	sort.Ints(ports)
+	return ports, nil
+}
+
+// forward starts ssh forwarding ports
+func forward(ssh string, driver string, ports []int) (*forwarder, error) {
+	args, err := oci.SSHForwardArgs(driver, ports)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(ssh, args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	klog.Infof("forwarding %v: %v", ports, cmd.Args)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	f := &forwarder{ports: ports, cmd: cmd, done: make(chan struct{})}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			klog.Warningf("ssh forwarding %v exited: %v", ports, err)
+		}
+		close(f.done)
+	}()
+	return f, nil
+}
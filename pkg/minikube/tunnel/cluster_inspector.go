@@ -25,6 +25,7 @@ import (
 	"github.com/docker/machine/libmachine/state"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/util"
 )
@@ -85,15 +86,33 @@ func getRoute(host *host.Host, clusterConfig config.ClusterConfig) (*Route, erro
 	if err != nil {
 		return nil, errors.Wrapf(err, "error getting host IP for %s", host.Name)
 	}
-
-	_, ipNet, err := net.ParseCIDR(clusterConfig.KubernetesConfig.ServiceCIDR)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing service CIDR: %s", err)
+	// IPv6 only clusters have to be routed through the node IPv6 address
+	if clusterConfig.KubernetesConfig.IPFamily == constants.IPFamilyIPv6 {
+		if cp, err := config.PrimaryControlPlane(&clusterConfig); err == nil && cp.IPv6 != "" {
+			hostDriverIP = cp.IPv6
+		}
 	}
+
 	ip := net.ParseIP(hostDriverIP)
 	if ip == nil {
 		return nil, fmt.Errorf("invalid IP for host %s", hostDriverIP)
 	}
+
+	// dual-stack clusters have a service CIDR per family, route the one reachable through the gateway
+	var ipNet *net.IPNet
+	for _, cidr := range util.SplitCIDRs(clusterConfig.KubernetesConfig.ServiceCIDR) {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing service CIDR: %s", err)
+		}
+		if (n.IP.To4() == nil) == (ip.To4() == nil) {
+			ipNet = n
+			break
+		}
+	}
+	if ipNet == nil {
+		return nil, fmt.Errorf("no service CIDR of the same IP family as %s in %q", ip, clusterConfig.KubernetesConfig.ServiceCIDR)
+	}
 	dnsIP, err := util.GetDNSIP(ipNet.String())
 	if err != nil {
 		return nil, err
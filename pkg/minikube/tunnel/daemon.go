@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/daemon"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// daemonStopTimeout is how long a background tunnel gets to clean up after itself before being killed
+const daemonStopTimeout = 10 * time.Second
+
+// Daemon is the persisted state of a tunnel running in the background, see 'minikube tunnel start'
+type Daemon struct {
+	daemon.Process
+	Profile string
+}
+
+// daemonPath returns the path to the state file of the background tunnel of a profile
+func daemonPath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "tunnel.json")
+}
+
+// DaemonLogFile returns the path to the log file of the background tunnel of a profile
+func DaemonLogFile(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "tunnel.log")
+}
+
+// LoadDaemon returns the background tunnel of a profile, or nil if it was never started
+func LoadDaemon(profile string) (*Daemon, error) {
+	d := &Daemon{}
+	found, err := daemon.Load(daemonPath(profile), d)
+	if err != nil || !found {
+		return nil, err
+	}
+	return d, nil
+}
+
+// StartDaemon runs 'minikube tunnel' for a profile as a detached process, which keeps running
+// and re-establishes the tunnel across cluster restarts until StopDaemon is called.
+// extraArgs are passed to the tunnel command.
+func StartDaemon(profile string, extraArgs []string) (*Daemon, error) {
+	existing, err := LoadDaemon(profile)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Running() {
+		return nil, fmt.Errorf("a tunnel is already running for %q with pid %d", profile, existing.Pid)
+	}
+
+	args := append([]string{"tunnel", "--profile", profile, "--reconnect", "--alsologtostderr"}, extraArgs...)
+	p, err := daemon.Start(args, DaemonLogFile(profile))
+	if err != nil {
+		return nil, errors.Wrap(err, "starting tunnel")
+	}
+	d := &Daemon{Process: *p, Profile: profile}
+	if err := daemon.Save(daemonPath(profile), d); err != nil {
+		return nil, errors.Wrap(err, "saving tunnel state")
+	}
+	return d, nil
+}
+
+// StopDaemon stops the background tunnel of a profile, then cleans up any route left behind by tunnels which
+// are not running anymore. It returns whether a background tunnel was found.
+func StopDaemon(profile string) (bool, error) {
+	d, err := LoadDaemon(profile)
+	if err != nil || d == nil {
+		return false, err
+	}
+	if err := d.Stop(daemonStopTimeout); err != nil {
+		return true, errors.Wrap(err, "stopping tunnel")
+	}
+	if err := daemon.Remove(daemonPath(profile)); err != nil {
+		return true, err
+	}
+	return true, NewManager().CleanupNotRunningTunnels()
+}
+
+// RegisteredTunnels returns the tunnels with a route registered for a machine
+func RegisteredTunnels(machineName string) ([]*ID, error) {
+	reg := &persistentRegistry{path: RegistryPath()}
+	tunnels, err := reg.List()
+	if err != nil {
+		return nil, err
+	}
+	var ids []*ID
+	for _, t := range tunnels {
+		if t.MachineName == machineName {
+			ids = append(ids, t)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/daemon"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestStopDaemon(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "tunnel")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	defer func(home string) { os.Setenv(localpath.MinikubeHome, home) }(os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, tmp)
+
+	found, err := StopDaemon("p1")
+	if err != nil || found {
+		t.Fatalf("StopDaemon() without tunnel = %v, %v, want false, nil", found, err)
+	}
+
+	if err := os.MkdirAll(localpath.Profile("p1"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data, err := json.Marshal(&Daemon{Process: daemon.Process{Pid: 12341234, Created: 1}, Profile: "p1"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(localpath.Profile("p1"), "tunnel.json"), data, 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	d, err := LoadDaemon("p1")
+	if err != nil || d == nil || d.Pid != 12341234 {
+		t.Fatalf("LoadDaemon() = %+v, %v", d, err)
+	}
+	if d.Running() {
+		t.Errorf("Running() = true for an exited tunnel")
+	}
+
+	found, err = StopDaemon("p1")
+	if err != nil || !found {
+		t.Fatalf("StopDaemon() = %v, %v, want true, nil", found, err)
+	}
+	if d, err := LoadDaemon("p1"); err != nil || d != nil {
+		t.Errorf("tunnel state should have been removed, got %+v, %v", d, err)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/binary"
+	"net"
+	"sort"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// ipPool allocates the ingress IPs of LoadBalancer services from a CIDR, see --loadbalancer-ip-range.
+// It keeps no state of its own: the IPs already set in the status of the services are the allocations.
+type ipPool struct {
+	cidr *net.IPNet
+}
+
+// serviceKey identifies a service across namespaces
+func serviceKey(svc core.Service) string {
+	return svc.Namespace + "/" + svc.Name
+}
+
+// assign returns the ingress IP of each LoadBalancer service, keyed by serviceKey.
+// Services keep their current IP, then get the IP requested with spec.loadBalancerIP if it is free,
+// then the lowest free IP of the pool. Services are left out once the pool is exhausted.
+func (p *ipPool) assign(services []core.Service) map[string]string {
+	var lbs []core.Service
+	for _, svc := range services {
+		if svc.Spec.Type == core.ServiceTypeLoadBalancer {
+			lbs = append(lbs, svc)
+		}
+	}
+	// oldest services first, so that they win conflicts
+	sort.SliceStable(lbs, func(i, j int) bool {
+		ti, tj := lbs[i].CreationTimestamp, lbs[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return serviceKey(lbs[i]) < serviceKey(lbs[j])
+	})
+
+	assigned := map[string]string{}
+	used := map[string]bool{}
+	claim := func(svc core.Service, ip string) bool {
+		parsed := net.ParseIP(ip)
+		if parsed == nil || !p.usable(parsed) || used[parsed.String()] {
+			return false
+		}
+		used[parsed.String()] = true
+		assigned[serviceKey(svc)] = parsed.String()
+		return true
+	}
+
+	var pending []core.Service
+	for _, svc := range lbs {
+		ingresses := svc.Status.LoadBalancer.Ingress
+		if len(ingresses) == 1 && claim(svc, ingresses[0].IP) {
+			continue
+		}
+		pending = append(pending, svc)
+	}
+
+	var unassigned []core.Service
+	for _, svc := range pending {
+		if svc.Spec.LoadBalancerIP != "" && claim(svc, svc.Spec.LoadBalancerIP) {
+			continue
+		}
+		unassigned = append(unassigned, svc)
+	}
+
+	next := p.first()
+	for _, svc := range unassigned {
+		for next != nil && !claim(svc, next.String()) {
+			next = p.after(next)
+		}
+		if next == nil {
+			klog.Warningf("no free IP left in %s for service %s", p.cidr, serviceKey(svc))
+		}
+	}
+	return assigned
+}
+
+// usable returns whether ip is in the pool, excluding its network and broadcast addresses
+func (p *ipPool) usable(ip net.IP) bool {
+	v4 := ip.To4()
+	if v4 == nil || !p.cidr.Contains(v4) {
+		return false
+	}
+	ones, bits := p.cidr.Mask.Size()
+	if bits-ones < 2 {
+		return true
+	}
+	n := binary.BigEndian.Uint32(v4)
+	network := binary.BigEndian.Uint32(p.cidr.IP.To4())
+	broadcast := network | ^binary.BigEndian.Uint32(p.cidr.Mask)
+	return n != network && n != broadcast
+}
+
+// first returns the lowest usable IP of the pool
+func (p *ipPool) first() net.IP {
+	ip := p.cidr.IP.To4()
+	if ip == nil {
+		return nil
+	}
+	if p.usable(ip) {
+		return ip
+	}
+	return p.after(ip)
+}
+
+// after returns the next usable IP of the pool after ip, or nil if there is none
+func (p *ipPool) after(ip net.IP) net.IP {
+	n := binary.BigEndian.Uint32(ip.To4())
+	for {
+		if n == ^uint32(0) {
+			return nil
+		}
+		n++
+		next := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(next, n)
+		if !p.cidr.Contains(next) {
+			return nil
+		}
+		if p.usable(next) {
+			return next
+		}
+	}
+}
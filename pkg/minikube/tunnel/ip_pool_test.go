@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func lbService(name string, age time.Duration, ingressIP, requestedIP string) core.Service {
+	svc := core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: meta.NewTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Add(-age)),
+		},
+		Spec: core.ServiceSpec{
+			Type:           core.ServiceTypeLoadBalancer,
+			LoadBalancerIP: requestedIP,
+		},
+	}
+	if ingressIP != "" {
+		svc.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: ingressIP}}
+	}
+	return svc
+}
+
+func TestIPPoolAssign(t *testing.T) {
+	_, cidr, err := net.ParseCIDR("10.112.0.0/29")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	pool := &ipPool{cidr: cidr}
+
+	tests := []struct {
+		description string
+		services    []core.Service
+		want        map[string]string
+	}{
+		{
+			description: "lowest free IPs, oldest service first",
+			services: []core.Service{
+				lbService("new", time.Minute, "", ""),
+				lbService("old", time.Hour, "", ""),
+				{ObjectMeta: meta.ObjectMeta{Name: "clusterip", Namespace: "default"}},
+			},
+			want: map[string]string{"default/old": "10.112.0.1", "default/new": "10.112.0.2"},
+		},
+		{
+			description: "existing allocations are kept",
+			services: []core.Service{
+				lbService("a", time.Hour, "10.112.0.5", ""),
+				lbService("b", time.Minute, "", ""),
+			},
+			want: map[string]string{"default/a": "10.112.0.5", "default/b": "10.112.0.1"},
+		},
+		{
+			description: "cluster IPs and conflicting allocations are replaced",
+			services: []core.Service{
+				lbService("a", time.Hour, "10.96.0.12", ""),
+				lbService("b", 2*time.Hour, "10.112.0.1", ""),
+				lbService("c", time.Minute, "10.112.0.1", ""),
+			},
+			want: map[string]string{"default/b": "10.112.0.1", "default/a": "10.112.0.2", "default/c": "10.112.0.3"},
+		},
+		{
+			description: "requested IPs are honored if free and in the pool",
+			services: []core.Service{
+				lbService("a", time.Hour, "", "10.112.0.6"),
+				lbService("b", time.Minute, "", "192.168.1.1"),
+			},
+			want: map[string]string{"default/a": "10.112.0.6", "default/b": "10.112.0.1"},
+		},
+		{
+			description: "network and broadcast addresses are skipped, pool exhaustion leaves services pending",
+			services: []core.Service{
+				lbService("a", 8*time.Hour, "10.112.0.0", ""),
+				lbService("b", 7*time.Hour, "10.112.0.7", ""),
+				lbService("c", 6*time.Hour, "", ""),
+				lbService("d", 5*time.Hour, "", ""),
+				lbService("e", 4*time.Hour, "", ""),
+				lbService("f", 3*time.Hour, "", ""),
+				lbService("g", 2*time.Hour, "", ""),
+			},
+			want: map[string]string{
+				"default/a": "10.112.0.1",
+				"default/b": "10.112.0.2",
+				"default/c": "10.112.0.3",
+				"default/d": "10.112.0.4",
+				"default/e": "10.112.0.5",
+				"default/f": "10.112.0.6",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := pool.assign(tc.services)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("assign() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
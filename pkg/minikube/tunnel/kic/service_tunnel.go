@@ -22,6 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
 
@@ -65,8 +66,12 @@ func (t *ServiceTunnel) Start(svcName, namespace string) ([]string, error) {
 	}()
 
 	urls := make([]string, 0, len(svc.Spec.Ports))
-	for _, port := range t.sshConn.ports {
-		urls = append(urls, fmt.Sprintf("http://127.0.0.1:%d", port))
+	for _, p := range t.sshConn.ports {
+		scheme := "http"
+		if p.protocol == v1.ProtocolUDP {
+			scheme = "udp"
+		}
+		urls = append(urls, fmt.Sprintf("%s://127.0.0.1:%d", scheme, p.port))
 	}
 
 	return urls, nil
@@ -32,24 +32,56 @@ type sshConn struct {
 	name    string
 	service string
 	cmd     *exec.Cmd
-	ports   []int
+	ports   []localPort
+	relays  []*udpRelay
+	done    chan struct{} // closed once the ssh process has exited
 }
 
-func createSSHConn(name, sshPort, sshKey string, svc *v1.Service) *sshConn {
-	// extract sshArgs
-	sshArgs := []string{
+// localPort is a port on the host which is forwarded to a service port
+type localPort struct {
+	port     int
+	protocol v1.Protocol
+}
+
+// nodeSSHArgs returns the arguments to connect to the node, without a command or port forwards
+func nodeSSHArgs(sshPort, sshKey string) []string {
+	return []string{
 		// TODO: document the options here
 		"-o", "UserKnownHostsFile=/dev/null",
 		"-o", "StrictHostKeyChecking=no",
-		"-N",
 		"docker@127.0.0.1",
 		"-p", sshPort,
 		"-i", sshKey,
 	}
+}
+
+// warnUnsupportedProtocol warns about a port which can not be forwarded through ssh
+func warnUnsupportedProtocol(svc *v1.Service, port v1.ServicePort) {
+	out.WarningT("Port {{.port}}/{{.protocol}} of service {{.service}} can not be forwarded by the docker driver on {{.os}}", out.V{"port": port.Port, "protocol": port.Protocol, "service": svc.Name, "os": runtime.GOOS})
+}
+
+func createSSHConn(name, sshPort, sshKey string, svc *v1.Service) *sshConn {
+	// extract sshArgs
+	sshArgs := append([]string{"-N"}, nodeSSHArgs(sshPort, sshKey)...)
 
 	askForSudo := false
 	var privilegedPorts []int32
+	var relays []*udpRelay
 	for _, port := range svc.Spec.Ports {
+		switch port.Protocol {
+		case v1.ProtocolUDP:
+			r, err := newUDPRelay(nodeSSHArgs(sshPort, sshKey), int(port.Port), svc.Spec.ClusterIP, port.Port)
+			if err != nil {
+				out.WarningT("Unable to forward port {{.port}}/UDP of service {{.service}}: {{.error}}", out.V{"port": port.Port, "service": svc.Name, "error": err})
+				continue
+			}
+			relays = append(relays, r)
+			continue
+		case v1.ProtocolSCTP:
+			warnUnsupportedProtocol(svc, port)
+			continue
+		}
+
 		arg := fmt.Sprintf(
 			"-L %d:%s:%d",
 			port.Port,
@@ -90,26 +122,37 @@ func createSSHConn(name, sshPort, sshKey string, svc *v1.Service) *sshConn {
 		name:    name,
 		service: svc.Name,
 		cmd:     cmd,
+		relays:  relays,
+		done:    make(chan struct{}),
 	}
 }
 
 func createSSHConnWithRandomPorts(name, sshPort, sshKey string, svc *v1.Service) (*sshConn, error) {
 	// extract sshArgs
-	sshArgs := []string{
-		// TODO: document the options here
-		"-o", "UserKnownHostsFile=/dev/null",
-		"-o", "StrictHostKeyChecking=no",
-		"-N",
-		"docker@127.0.0.1",
-		"-p", sshPort,
-		"-i", sshKey,
-	}
+	sshArgs := append([]string{"-N"}, nodeSSHArgs(sshPort, sshKey)...)
 
-	usedPorts := make([]int, 0, len(svc.Spec.Ports))
+	usedPorts := make([]localPort, 0, len(svc.Spec.Ports))
+	var relays []*udpRelay
 
 	for _, port := range svc.Spec.Ports {
+		switch port.Protocol {
+		case v1.ProtocolUDP:
+			r, err := newUDPRelay(nodeSSHArgs(sshPort, sshKey), 0, svc.Spec.ClusterIP, port.Port)
+			if err != nil {
+				stopRelays(relays)
+				return nil, err
+			}
+			relays = append(relays, r)
+			usedPorts = append(usedPorts, localPort{r.port(), v1.ProtocolUDP})
+			continue
+		case v1.ProtocolSCTP:
+			warnUnsupportedProtocol(svc, port)
+			continue
+		}
+
 		freeport, err := freeport.GetFreePort()
 		if err != nil {
+			stopRelays(relays)
 			return nil, err
 		}
 
@@ -121,7 +164,7 @@ func createSSHConnWithRandomPorts(name, sshPort, sshKey string, svc *v1.Service)
 		)
 
 		sshArgs = append(sshArgs, arg)
-		usedPorts = append(usedPorts, freeport)
+		usedPorts = append(usedPorts, localPort{freeport, v1.ProtocolTCP})
 	}
 
 	cmd := exec.Command("ssh", sshArgs...)
@@ -131,12 +174,21 @@ func createSSHConnWithRandomPorts(name, sshPort, sshKey string, svc *v1.Service)
 		service: svc.Name,
 		cmd:     cmd,
 		ports:   usedPorts,
+		relays:  relays,
+		done:    make(chan struct{}),
 	}, nil
 }
 
 func (c *sshConn) startAndWait() error {
 	out.Step(style.Running, "Starting tunnel for service {{.service}}.", out.V{"service": c.service})
 
+	defer close(c.done)
+	// the relays are useless without the node, so stop them with the ssh process
+	defer stopRelays(c.relays)
+	for _, r := range c.relays {
+		go r.serve()
+	}
+
 	err := c.cmd.Start()
 	if err != nil {
 		return err
@@ -148,8 +200,28 @@ func (c *sshConn) startAndWait() error {
 	return nil
 }
 
+// exited returns whether the ssh process has exited, for example because the node was restarted
+func (c *sshConn) exited() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *sshConn) stop() error {
 	out.Step(style.Stopping, "Stopping tunnel for service {{.service}}.", out.V{"service": c.service})
 
+	stopRelays(c.relays)
+	if c.cmd.Process == nil || c.exited() {
+		return nil
+	}
 	return c.cmd.Process.Kill()
 }
+
+func stopRelays(relays []*udpRelay) {
+	for _, r := range relays {
+		r.stop()
+	}
+}
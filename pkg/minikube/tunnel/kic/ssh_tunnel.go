@@ -39,6 +39,7 @@ type SSHTunnel struct {
 	LoadBalancerEmulator tunnel.LoadBalancerEmulator
 	conns                map[string]*sshConn
 	connsToStop          map[string]*sshConn
+	sshPortLookup        func() (string, error)
 }
 
 // NewSSHTunnel ...
@@ -54,6 +55,12 @@ func NewSSHTunnel(ctx context.Context, sshPort, sshKey string, v1Core typed_core
 	}
 }
 
+// ReconnectWith makes the tunnel look up the ssh port of the node again before re-establishing
+// connections which dropped, as the port changes when the node container is restarted.
+func (t *SSHTunnel) ReconnectWith(sshPortLookup func() (string, error)) {
+	t.sshPortLookup = sshPortLookup
+}
+
 // Start ...
 func (t *SSHTunnel) Start() error {
 	for {
@@ -69,9 +76,13 @@ func (t *SSHTunnel) Start() error {
 
 		services, err := t.v1Core.Services("").List(context.Background(), metav1.ListOptions{})
 		if err != nil {
+			// the apiserver may be restarting, keep the existing connections until it is back
 			klog.Errorf("error listing services: %v", err)
+			time.Sleep(1 * time.Second)
+			continue
 		}
 
+		t.dropExitedConnections()
 		t.markConnectionsToBeStopped()
 
 		for _, svc := range services.Items {
@@ -87,6 +98,27 @@ func (t *SSHTunnel) Start() error {
 	}
 }
 
+// dropExitedConnections forgets about connections whose ssh process exited, so that they get re-established
+func (t *SSHTunnel) dropExitedConnections() {
+	dropped := false
+	for name, conn := range t.conns {
+		if conn.exited() {
+			klog.Infof("ssh tunnel %s exited, will reconnect", name)
+			delete(t.conns, name)
+			dropped = true
+		}
+	}
+	if !dropped || t.sshPortLookup == nil {
+		return
+	}
+	port, err := t.sshPortLookup()
+	if err != nil {
+		klog.Errorf("error looking up ssh port: %v", err)
+		return
+	}
+	t.sshPort = port
+}
+
 func (t *SSHTunnel) markConnectionsToBeStopped() {
 	for _, conn := range t.conns {
 		t.connsToStop[conn.name] = conn
@@ -143,6 +175,9 @@ func sshConnUniqName(service v1.Service) string {
 
 	for _, port := range service.Spec.Ports {
 		n = append(n, fmt.Sprintf("-%d", port.Port))
+		if port.Protocol != "" && port.Protocol != v1.ProtocolTCP {
+			n = append(n, "/", strings.ToLower(string(port.Protocol)))
+		}
 	}
 
 	return strings.Join(n, "")
@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kic
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// udpIdleTimeout is how long a client may stay silent before its relay session is closed
+const udpIdleTimeout = 2 * time.Minute
+
+// udpRelayScript runs on the node with the target address as argument. It sends every datagram framed on stdin
+// to the target from a single socket, and frames every response on stdout: each datagram is preceded by its
+// length as a big-endian uint16, as ssh only carries a byte stream which would merge or split them.
+// perl is part of the base system of the kicbase image, unlike any tool which could relay datagrams.
+const udpRelayScript = `use IO::Socket::INET;
+my $s = IO::Socket::INET->new(Proto => "udp", PeerAddr => $ARGV[0]) or die "connecting to $ARGV[0]: $!";
+binmode STDIN; binmode STDOUT; $| = 1;
+my $pid = fork() // die "fork: $!";
+if ($pid == 0) { my $d; while (defined $s->recv($d, 65535)) { print pack("n", length $d), $d } exit 0 }
+my ($h, $d);
+while (read(STDIN, $h, 2) == 2) { my $n = unpack("n", $h); last if read(STDIN, $d, $n) != $n; $s->send($d) }
+kill "KILL", $pid;`
+
+// udpRelay forwards the datagrams sent to a local UDP port to a service port in the cluster.
+// As ssh can only forward TCP, every client gets its own ssh session running udpRelayScript on the node,
+// which sends the datagrams it reads from stdin to the service and writes the responses to stdout.
+type udpRelay struct {
+	sshArgs []string
+	target  string
+	conn    *net.UDPConn
+
+	mu    sync.Mutex
+	flows map[string]*udpFlow
+}
+
+type udpFlow struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	timer *time.Timer
+	once  sync.Once
+}
+
+// newUDPRelay listens on localPort of 127.0.0.1, or on a random port if localPort is 0
+func newUDPRelay(sshArgs []string, localPort int, clusterIP string, port int32) (*udpRelay, error) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: localPort})
+	if err != nil {
+		if localPort > 0 && localPort < 1024 && errors.Is(err, os.ErrPermission) {
+			return nil, errors.Wrapf(err, "udp port %d is privileged, run 'minikube tunnel' as root to forward it", localPort)
+		}
+		return nil, errors.Wrapf(err, "listening on udp port %d", localPort)
+	}
+	return &udpRelay{
+		sshArgs: sshArgs,
+		target:  net.JoinHostPort(clusterIP, strconv.Itoa(int(port))),
+		conn:    conn,
+		flows:   make(map[string]*udpFlow),
+	}, nil
+}
+
+// port returns the local port the relay listens on
+func (r *udpRelay) port() int {
+	return r.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// writeDatagram writes a datagram to the byte stream of a relay session, preceded by its length
+func writeDatagram(w io.Writer, d []byte) error {
+	frame := make([]byte, 2+len(d))
+	binary.BigEndian.PutUint16(frame, uint16(len(d)))
+	copy(frame[2:], d)
+	_, err := w.Write(frame)
+	return err
+}
+
+// readDatagram reads a datagram written by writeDatagram from the byte stream of a relay session
+func readDatagram(r io.Reader, buf []byte) (int, error) {
+	var h [2]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return 0, err
+	}
+	n := int(binary.BigEndian.Uint16(h[:]))
+	if n > len(buf) {
+		return 0, errors.Errorf("datagram of %d bytes does not fit in %d", n, len(buf))
+	}
+	return io.ReadFull(r, buf[:n])
+}
+
+// serve relays datagrams until the relay is stopped
+func (r *udpRelay) serve() {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := r.conn.ReadFromUDP(buf)
+		if err != nil {
+			klog.Infof("udp relay to %s stopped: %v", r.target, err)
+			return
+		}
+		f, err := r.flow(addr)
+		if err != nil {
+			klog.Errorf("relaying udp from %s to %s: %v", addr, r.target, err)
+			continue
+		}
+		f.timer.Reset(udpIdleTimeout)
+		if err := writeDatagram(f.stdin, buf[:n]); err != nil {
+			klog.Warningf("relaying udp from %s to %s: %v", addr, r.target, err)
+			r.closeFlow(addr.String(), f)
+		}
+	}
+}
+
+// flow returns the ssh session relaying the datagrams of addr, starting it if needed
+func (r *udpRelay) flow(addr *net.UDPAddr) (*udpFlow, error) {
+	key := addr.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.flows[key]; ok {
+		return f, nil
+	}
+
+	// ssh passes the command to the shell of the node, which removes the quotes around the script
+	args := append(append([]string{}, r.sshArgs...), "perl", "-e", "'"+udpRelayScript+"'", r.target)
+	cmd := exec.Command("ssh", args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "stdin")
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "stdout")
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrap(err, "starting ssh")
+	}
+
+	f := &udpFlow{cmd: cmd, stdin: stdin}
+	f.timer = time.AfterFunc(udpIdleTimeout, func() { r.closeFlow(key, f) })
+	r.flows[key] = f
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, err := readDatagram(stdout, buf)
+			if err != nil {
+				break
+			}
+			if _, err := r.conn.WriteToUDP(buf[:n], addr); err != nil {
+				klog.Warningf("relaying udp from %s to %s: %v", r.target, addr, err)
+				break
+			}
+		}
+		r.closeFlow(key, f)
+	}()
+	return f, nil
+}
+
+func (r *udpRelay) closeFlow(key string, f *udpFlow) {
+	r.mu.Lock()
+	if r.flows[key] == f {
+		delete(r.flows, key)
+	}
+	r.mu.Unlock()
+
+	f.once.Do(func() {
+		f.timer.Stop()
+		_ = f.cmd.Process.Kill()
+		// reap the process, the error is expected as it was killed
+		go func() { _ = f.cmd.Wait() }()
+	})
+}
+
+// stop closes the local port and all ssh sessions
+func (r *udpRelay) stop() {
+	_ = r.conn.Close()
+
+	r.mu.Lock()
+	flows := r.flows
+	r.flows = make(map[string]*udpFlow)
+	r.mu.Unlock()
+
+	for key, f := range flows {
+		r.closeFlow(key, f)
+	}
+}
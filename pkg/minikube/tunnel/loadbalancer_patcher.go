@@ -19,6 +19,7 @@ package tunnel
 import (
 	"context"
 	"fmt"
+	"net"
 
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,11 +39,19 @@ type patchConverter interface {
 	convert(restClient rest.Interface, patch *Patch) *rest.Request
 }
 
-// LoadBalancerEmulator is the main struct for emulating the loadbalancer behavior. it sets the ingress to the cluster IP
+// LoadBalancerEmulator is the main struct for emulating the loadbalancer behavior. it sets the ingress to the cluster IP,
+// or to an IP allocated from a pool if one is set with UsePool
 type LoadBalancerEmulator struct {
 	coreV1Client   typed_core.CoreV1Interface
 	requestSender  requestSender
 	patchConverter patchConverter
+	pool           *ipPool
+	assigned       map[string]string
+}
+
+// UsePool makes the emulator allocate ingress IPs from cidr instead of using the ClusterIP of the services
+func (l *LoadBalancerEmulator) UsePool(cidr *net.IPNet) {
+	l.pool = &ipPool{cidr: cidr}
 }
 
 // PatchServices will update all load balancer services
@@ -70,6 +79,10 @@ func (l *LoadBalancerEmulator) applyOnLBServices(action func(restClient rest.Int
 	}
 	restClient := l.coreV1Client.RESTClient()
 
+	if l.pool != nil {
+		l.assigned = l.pool.assign(serviceList.Items)
+	}
+
 	var managedServices []string
 
 	for _, svc := range serviceList.Items {
@@ -91,19 +104,22 @@ func (l *LoadBalancerEmulator) applyOnLBServices(action func(restClient rest.Int
 }
 
 func (l *LoadBalancerEmulator) updateService(restClient rest.Interface, svc core.Service) ([]byte, error) {
-	clusterIP := svc.Spec.ClusterIP
+	ip := svc.Spec.ClusterIP
+	if l.pool != nil {
+		ip = l.assigned[serviceKey(svc)]
+	}
 	ingresses := svc.Status.LoadBalancer.Ingress
-	if len(ingresses) == 1 && ingresses[0].IP == clusterIP {
+	if len(ingresses) == 1 && ingresses[0].IP == ip {
 		return nil, nil
 	}
-	return l.updateServiceIP(restClient, svc, clusterIP)
+	return l.updateServiceIP(restClient, svc, ip)
 }
 
 func (l *LoadBalancerEmulator) updateServiceIP(restClient rest.Interface, svc core.Service, ip string) ([]byte, error) {
 	if len(ip) == 0 {
 		return nil, nil
 	}
-	klog.V(3).Infof("[%s] setting ClusterIP as the LoadBalancer Ingress", svc.Name)
+	klog.V(3).Infof("[%s] setting %s as the LoadBalancer Ingress", svc.Name, ip)
 	jsonPatch := fmt.Sprintf(`[{"op": "add", "path": "/status/loadBalancer/ingress", "value":  [ { "ip": "%s" } ] }]`, ip)
 	patch := &Patch{
 		Type:         types.JSONPatchType,
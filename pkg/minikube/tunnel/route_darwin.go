@@ -39,8 +39,11 @@ func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 	if exists {
 		return nil
 	}
-	if err := writeResolverFile(route); err != nil {
-		klog.Errorf("DNS forwarding unavailable: %v", err)
+	// LoadBalancer IP pool routes have no cluster domain to resolve
+	if route.ClusterDomain != "" {
+		if err := writeResolverFile(route); err != nil {
+			klog.Errorf("DNS forwarding unavailable: %v", err)
+		}
 	}
 
 	serviceCIDR := route.DestCIDR.String()
@@ -170,6 +173,9 @@ func (router *osRouter) Cleanup(route *Route) error {
 	if !re.MatchString(msg) {
 		return fmt.Errorf("error deleting route: %s, %d", msg, len(strings.Split(msg, "\n")))
 	}
+	if route.ClusterDomain == "" {
+		return nil
+	}
 	// idempotent removal of cluster domain dns
 	resolverFile := fmt.Sprintf("/etc/resolver/%s", route.ClusterDomain)
 	cmd = exec.Command("sudo", "rm", "-f", resolverFile)
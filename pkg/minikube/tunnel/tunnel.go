@@ -18,6 +18,7 @@ package tunnel
 
 import (
 	"fmt"
+	"net"
 	"os"
 
 	"os/exec"
@@ -38,6 +39,7 @@ import (
 type controller interface {
 	cleanup() *Status
 	update() *Status
+	refreshRoute() error
 }
 
 func errorTunnelAlreadyExists(id *ID) error {
@@ -68,7 +70,7 @@ func newTunnel(machineName string, machineAPI libmachine.API, configLoader confi
 		return nil, fmt.Errorf("another tunnel is already running, shut it down first: %s", runningTunnel)
 	}
 
-	return &tunnel{
+	t := &tunnel{
 		clusterInspector:     ci,
 		router:               router,
 		registry:             registry,
@@ -80,7 +82,26 @@ func newTunnel(machineName string, machineAPI libmachine.API, configLoader confi
 		reporter: &simpleReporter{
 			out: os.Stdout,
 		},
-	}, nil
+	}
+
+	// LoadBalancer IPs allocated from a pool need their own route
+	cc, err := configLoader.LoadConfigFromFile(machineName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %s", err)
+	}
+	if r := cc.KubernetesConfig.LoadBalancerIPRange; r != "" {
+		_, pool, err := net.ParseCIDR(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LoadBalancer IP range %q: %s", r, err)
+		}
+		t.LoadBalancerEmulator.UsePool(pool)
+		t.lbTunnelID = &ID{
+			Route:       &Route{Gateway: route.Gateway, DestCIDR: pool},
+			MachineName: machineName,
+			Pid:         id.Pid,
+		}
+	}
+	return t, nil
 
 }
 
@@ -92,10 +113,21 @@ type tunnel struct {
 	reporter             reporter
 	registry             *persistentRegistry
 
+	// lbTunnelID is the route to the LoadBalancer IP pool, nil if LoadBalancer services use their ClusterIP
+	lbTunnelID *ID
+
 	status *Status
 }
 
 func (t *tunnel) cleanup() *Status {
+	if t.lbTunnelID != nil {
+		klog.V(3).Infof("cleaning up %s", t.lbTunnelID.Route)
+		if err := t.router.Cleanup(t.lbTunnelID.Route); err != nil {
+			klog.Warningf("error cleaning up LoadBalancer route: %v", err)
+		} else if err := t.registry.Remove(t.lbTunnelID.Route); err != nil {
+			klog.V(3).Infof("error removing LoadBalancer route from registry: %v", err)
+		}
+	}
 	klog.V(3).Infof("cleaning up %s", t.status.TunnelID.Route)
 	err := t.router.Cleanup(t.status.TunnelID.Route)
 	if err != nil {
@@ -113,6 +145,22 @@ func (t *tunnel) cleanup() *Status {
 	return t.status
 }
 
+// refreshRoute recomputes the route from the current state of the cluster, whose IP can change when it restarts
+func (t *tunnel) refreshRoute() error {
+	state, route, err := t.clusterInspector.getStateAndRoute()
+	if err != nil {
+		return err
+	}
+	if state != Running {
+		return fmt.Errorf("minikube status: %s", state)
+	}
+	t.status.TunnelID.Route = route
+	if t.lbTunnelID != nil {
+		t.lbTunnelID.Route = &Route{Gateway: route.Gateway, DestCIDR: t.lbTunnelID.Route.DestCIDR}
+	}
+	return nil
+}
+
 func (t *tunnel) update() *Status {
 	klog.V(3).Info("updating tunnel status...")
 	var h *host.Host
@@ -121,6 +169,9 @@ func (t *tunnel) update() *Status {
 	if t.status.MinikubeState == Running {
 		klog.V(3).Infof("minikube is running, trying to add route%s", t.status.TunnelID.Route)
 		setupRoute(t, h)
+		if t.status.RouteError == nil && t.lbTunnelID != nil {
+			t.status.RouteError = setupLoadBalancerRoute(t)
+		}
 		if t.status.RouteError == nil {
 			t.status.PatchedServices, t.status.LoadBalancerEmulatorError = t.LoadBalancerEmulator.PatchServices()
 		}
@@ -191,6 +242,34 @@ func setupRoute(t *tunnel, h *host.Host) {
 
 }
 
+// setupLoadBalancerRoute adds the route to the LoadBalancer IP pool, kube-proxy forwarding the traffic to the services
+func setupLoadBalancerRoute(t *tunnel) error {
+	exists, conflict, _, err := t.router.Inspect(t.lbTunnelID.Route)
+	if err != nil {
+		return fmt.Errorf("error checking for LoadBalancer route state: %s", err)
+	}
+	if len(conflict) > 0 {
+		return fmt.Errorf("conflicting LoadBalancer route: %s", conflict)
+	}
+	if !exists {
+		if err := t.router.EnsureRouteIsAdded(t.lbTunnelID.Route); err != nil {
+			return err
+		}
+	}
+	// register the route unless another running tunnel owns it
+	existing, err := t.registry.IsAlreadyDefinedAndRunning(t.lbTunnelID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return t.registry.Register(t.lbTunnelID)
+	}
+	if existing.Pid != getPid() {
+		return errorTunnelAlreadyExists(existing)
+	}
+	return nil
+}
+
 func setupBridge(t *tunnel) {
 	command := exec.Command("ifconfig", "bridge100")
 	klog.Infof("About to run command: %s\n", command.Args)
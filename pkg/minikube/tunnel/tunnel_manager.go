@@ -37,6 +37,8 @@ type Manager struct {
 	delay    time.Duration
 	registry *persistentRegistry
 	router   router
+	// reconnect keeps the tunnel running while the cluster is stopped, re-establishing the route once it is back
+	reconnect bool
 }
 
 // stateCheckInterval defines how frequently the cluster and route states are checked
@@ -58,6 +60,12 @@ func NewManager() *Manager {
 	}
 }
 
+// EnableReconnect keeps tunnels started by this manager running while the cluster is stopped or
+// unreachable, instead of quitting, so that they can be used by long running background processes
+func (mgr *Manager) EnableReconnect() {
+	mgr.reconnect = true
+}
+
 // StartTunnel starts the tunnel
 func (mgr *Manager) StartTunnel(ctx context.Context, machineName string, machineAPI libmachine.API, configLoader config.Loader, v1Core typed_core.CoreV1Interface) (done chan bool, err error) {
 	tunnel, err := newTunnel(machineName, machineAPI, configLoader, v1Core, mgr.registry, mgr.router)
@@ -96,6 +104,7 @@ func (mgr *Manager) run(ctx context.Context, t controller, ready, check, done ch
 	defer func() {
 		done <- true
 	}()
+	wasDown := false
 	ready <- true
 	for {
 		select {
@@ -110,12 +119,33 @@ func (mgr *Manager) run(ctx context.Context, t controller, ready, check, done ch
 				return
 			default:
 			}
+			if wasDown {
+				// the cluster may be back with another IP, route through the current one
+				if err := t.refreshRoute(); err != nil {
+					klog.V(4).Infof("route not available yet: %v", err)
+					ready <- true
+					continue
+				}
+			}
 			status := t.update()
 			klog.V(4).Infof("minikube status: %s", status)
 			if status.MinikubeState != Running {
-				klog.Infof("minikube status: %s, cleaning up and quitting...", status.MinikubeState)
-				mgr.cleanup(t)
-				return
+				if !mgr.reconnect {
+					klog.Infof("minikube status: %s, cleaning up and quitting...", status.MinikubeState)
+					mgr.cleanup(t)
+					return
+				}
+				if !wasDown {
+					klog.Infof("minikube status: %s, removing route until it is running again...", status.MinikubeState)
+					mgr.cleanup(t)
+				}
+				wasDown = true
+				ready <- true
+				continue
+			}
+			if wasDown {
+				klog.Infof("minikube is running again, route re-established")
+				wasDown = false
 			}
 			ready <- true
 		}
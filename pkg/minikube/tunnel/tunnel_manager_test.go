@@ -172,6 +172,55 @@ func TestTunnelManagerDelayAndContext(t *testing.T) {
 	}
 }
 
+func TestTunnelManagerReconnect(t *testing.T) {
+	tunnelManager := &Manager{}
+	tunnelManager.EnableReconnect()
+	tunnel := &tunnelStub{
+		mockClusterInfo: &Status{
+			MinikubeState: Stopped,
+		},
+	}
+
+	ready := make(chan bool, 1)
+	check := make(chan bool, 1)
+	done := make(chan bool, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	go tunnelManager.run(ctx, tunnel, ready, check, done)
+
+	<-ready
+	check <- true
+	select {
+	case <-ready:
+	case <-done:
+		t.Fatal("tunnel quit on stopped minikube")
+	case <-time.After(1 * time.Second):
+		t.Fatal("tunnel did not become ready again")
+	}
+
+	tunnel.mockClusterInfo = &Status{
+		MinikubeState: Running,
+	}
+	check <- true
+	<-ready
+	if !tunnel.tunnelExists {
+		t.Error("tunnel should have been re-established")
+	}
+	if tunnel.timesRefreshed != 1 {
+		t.Errorf("expected the route to be recomputed once before re-establishing the tunnel, got %d", tunnel.timesRefreshed)
+	}
+
+	cancel()
+	check <- true
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Error("tunnel did not stop on ctrl c")
+	}
+	if tunnel.tunnelExists {
+		t.Error("tunnel should have been cleaned up")
+	}
+}
+
 func registerRunningTunnels(reg *persistentRegistry) (*ID, *ID, error) {
 	runningTunnel1 := &ID{
 		Route:       unsafeParseRoute("1.2.3.4", "5.6.7.8/9"),
@@ -288,6 +337,12 @@ type tunnelStub struct {
 	mockClusterInfo *Status
 	tunnelExists    bool
 	timesChecked    int
+	timesRefreshed  int
+}
+
+func (t *tunnelStub) refreshRoute() error {
+	t.timesRefreshed++
+	return nil
 }
 
 func (t *tunnelStub) update() *Status {
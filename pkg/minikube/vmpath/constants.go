@@ -31,6 +31,8 @@ const (
 	GuestCertAuthDir = "/usr/share/ca-certificates"
 	// GuestCertStoreDir is where system SSL certificates are installed
 	GuestCertStoreDir = "/etc/ssl/certs"
+	// GuestRegistryMirrorDir is where the registry-mirror addon caches images
+	GuestRegistryMirrorDir = GuestPersistentDir + "/registry-mirror"
 	// GuestGvisorDir is where gvisor bootstraps from
 	GuestGvisorDir = "/tmp/gvisor"
 )
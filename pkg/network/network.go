@@ -64,7 +64,8 @@ type Parameters struct {
 	CIDR      string // CIDR format ('a.b.c.d/n')
 	Gateway   string // taken from network interface address or assumed as first network IP address from given addr
 	ClientMin string // second IP address
-	ClientMax string // last IP address before broadcast
+	ClientMax string // last IP address handed out to clients, two before broadcast
+	VirtualIP string // last IP address before broadcast, reserved for virtual IPs and never handed out to clients
 	Broadcast string // last IP address
 	Interface
 }
@@ -157,12 +158,48 @@ func inspect(addr string) (*Parameters, error) {
 	n.ClientMin = min.String()
 
 	max := make(net.IP, 4)
-	binary.BigEndian.PutUint32(max, broadcastIP-1) // clients-from: last network IP address before broadcast
+	binary.BigEndian.PutUint32(max, broadcastIP-2) // clients-to: last network IP address before the reserved one
 	n.ClientMax = max.String()
 
+	vip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(vip, broadcastIP-1) // reserved: last network IP address before broadcast
+	n.VirtualIP = vip.String()
+
 	return n, nil
 }
 
+// VirtualIP returns an address for a virtual IP shared by nodes on the same network as ip.
+// It is the address networks created by minikube leave out of their DHCP range.
+func VirtualIP(ip string) (string, error) {
+	n, err := inspect(ip)
+	if err != nil {
+		return "", err
+	}
+	if n.VirtualIP == ip {
+		return "", fmt.Errorf("node %s holds the address of %s reserved for virtual IPs", ip, n.CIDR)
+	}
+	return n.VirtualIP, nil
+}
+
+// IPv6ForIPv4 returns the unique local IPv6 address paired with the IPv4 address ip on dual-stack networks,
+// so that a.b.c.d maps to fd00:a:b:c::d and addresses stay recognisable between the two families.
+func IPv6ForIPv4(ip string) (string, error) {
+	v4 := net.ParseIP(ip).To4()
+	if v4 == nil {
+		return "", fmt.Errorf("%q is not an IPv4 address", ip)
+	}
+	return fmt.Sprintf("fd00:%d:%d:%d::%d", v4[0], v4[1], v4[2], v4[3]), nil
+}
+
+// IPv6SubnetForIPv4 returns the /64 IPv6 subnet paired with the IPv4 address ip (see IPv6ForIPv4).
+func IPv6SubnetForIPv4(ip string) (string, error) {
+	v4 := net.ParseIP(ip).To4()
+	if v4 == nil {
+		return "", fmt.Errorf("%q is not an IPv4 address", ip)
+	}
+	return fmt.Sprintf("fd00:%d:%d:%d::/64", v4[0], v4[1], v4[2]), nil
+}
+
 // isSubnetTaken returns if local network subnet exists and any error occurred.
 // If will return false in case of an error.
 func isSubnetTaken(subnet string) (bool, error) {
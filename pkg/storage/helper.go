@@ -0,0 +1,216 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// helperCreate and helperDelete are the operations a volume helper pod can run
+	helperCreate = "create"
+	helperDelete = "delete"
+
+	helperTimeout = 2 * time.Minute
+)
+
+// volumeHelper sets up and removes volume directories on nodes other than the one the provisioner runs on
+type volumeHelper interface {
+	create(ctx context.Context, node string, path string, params volumeParameters) error
+	remove(ctx context.Context, node string, path string) error
+}
+
+// podHelper runs the provisioner binary as a short-lived pod on the target node
+type podHelper struct {
+	clientset kubernetes.Interface
+	namespace string
+	image     string
+	pvDir     string
+}
+
+var _ volumeHelper = &podHelper{}
+
+func (h *podHelper) create(ctx context.Context, node string, path string, params volumeParameters) error {
+	return h.run(ctx, node, helperArgs(helperCreate, path, params))
+}
+
+func (h *podHelper) remove(ctx context.Context, node string, path string) error {
+	return h.run(ctx, node, helperArgs(helperDelete, path, volumeParameters{}))
+}
+
+// helperArgs returns the provisioner command line running op on path
+func helperArgs(op string, path string, params volumeParameters) []string {
+	args := []string{"--volume-helper=" + op, "--volume-path=" + path}
+	if op != helperCreate {
+		return args
+	}
+	args = append(args, "--volume-mode="+formatMode(params.mode))
+	if params.uid != -1 {
+		args = append(args, fmt.Sprintf("--volume-uid=%d", params.uid))
+	}
+	if params.gid != -1 {
+		args = append(args, fmt.Sprintf("--volume-gid=%d", params.gid))
+	}
+	if params.seLinuxLabel != "" {
+		args = append(args, "--volume-selinux-label="+params.seLinuxLabel)
+	}
+	return args
+}
+
+// run starts a helper pod pinned to node and waits for it to complete
+func (h *podHelper) run(ctx context.Context, node string, args []string) error {
+	hostPathType := core.HostPathDirectoryOrCreate
+	automount := false
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			GenerateName: "storage-provisioner-helper-",
+			Namespace:    h.namespace,
+			Labels:       map[string]string{"app": "storage-provisioner-helper"},
+		},
+		Spec: core.PodSpec{
+			NodeName:                     node,
+			RestartPolicy:                core.RestartPolicyNever,
+			AutomountServiceAccountToken: &automount,
+			Tolerations:                  []core.Toleration{{Operator: core.TolerationOpExists}},
+			Containers: []core.Container{
+				{
+					Name:            "helper",
+					Image:           h.image,
+					ImagePullPolicy: core.PullIfNotPresent,
+					Command:         append([]string{"/storage-provisioner"}, args...),
+					VolumeMounts:    []core.VolumeMount{{Name: "pv-dir", MountPath: h.pvDir}},
+				},
+			},
+			Volumes: []core.Volume{
+				{
+					Name: "pv-dir",
+					VolumeSource: core.VolumeSource{
+						HostPath: &core.HostPathVolumeSource{Path: h.pvDir, Type: &hostPathType},
+					},
+				},
+			},
+		},
+	}
+
+	pods := h.clientset.CoreV1().Pods(h.namespace)
+	pod, err := pods.Create(ctx, pod, meta.CreateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "creating helper pod on %s", node)
+	}
+	defer func() {
+		if err := pods.Delete(context.Background(), pod.Name, meta.DeleteOptions{}); err != nil {
+			klog.Warningf("unable to delete helper pod %s: %v", pod.Name, err)
+		}
+	}()
+
+	klog.Infof("Waiting for helper pod %s on %s: %v", pod.Name, node, args)
+	err = wait.PollImmediate(time.Second, helperTimeout, func() (bool, error) {
+		p, err := pods.Get(ctx, pod.Name, meta.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		switch p.Status.Phase {
+		case core.PodSucceeded:
+			return true, nil
+		case core.PodFailed:
+			return false, fmt.Errorf("helper pod %s failed: %s", pod.Name, p.Status.Message)
+		}
+		return false, nil
+	})
+	return errors.Wrapf(err, "running helper pod on %s", node)
+}
+
+// ownPodImage returns the image the provisioner pod runs, so helper pods use the same binary
+func ownPodImage(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (string, error) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, meta.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == "storage-provisioner" {
+			return c.Image, nil
+		}
+	}
+	return "", fmt.Errorf("no storage-provisioner container in pod %s/%s", namespace, name)
+}
+
+// VolumeHelperConfig describes an operation run by a helper pod on the node a volume lives on
+type VolumeHelperConfig struct {
+	// The directory PV-backing directories are created in
+	PVDir string
+
+	// The operation to run, either "create" or "delete"
+	Operation string
+
+	// The volume directory to operate on, must be inside PVDir
+	Path string
+
+	// The octal mode, owner and SELinux label of a created directory, unset
+	// values are left alone
+	Mode         string
+	UID          string
+	GID          string
+	SELinuxLabel string
+}
+
+// RunVolumeHelper creates or removes a volume directory on the local filesystem
+func RunVolumeHelper(cfg VolumeHelperConfig) error {
+	path := filepath.Clean(cfg.Path)
+	if !strings.HasPrefix(path, filepath.Clean(cfg.PVDir)+string(filepath.Separator)) {
+		return fmt.Errorf("refusing to operate on %s: not inside %s", cfg.Path, cfg.PVDir)
+	}
+
+	switch cfg.Operation {
+	case helperCreate:
+		params := map[string]string{}
+		for k, v := range map[string]string{
+			modeParameter:         cfg.Mode,
+			uidParameter:          cfg.UID,
+			gidParameter:          cfg.GID,
+			seLinuxLabelParameter: cfg.SELinuxLabel,
+		} {
+			if v != "" {
+				params[k] = v
+			}
+		}
+		vp, err := parseVolumeParameters(params)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(path, vp.mode); err != nil {
+			return err
+		}
+		return errors.Wrapf(vp.apply(path), "setting up %s", path)
+	case helperDelete:
+		return errors.Wrapf(os.RemoveAll(path), "removing %s", path)
+	default:
+		return fmt.Errorf("unknown volume helper operation %q", cfg.Operation)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package journal keeps a short history of storage provisioner operations in a ConfigMap,
+// so that they can be inspected without digging through the provisioner logs.
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// Namespace is the namespace of the journal ConfigMap
+	Namespace = "kube-system"
+	// ConfigMapName is the name of the journal ConfigMap
+	ConfigMapName = "storage-provisioner-journal"
+
+	eventsKey = "events"
+	// maxEvents bounds the journal, so the ConfigMap stays well below the object size limit
+	maxEvents = 100
+)
+
+// Event is a single provisioner operation
+type Event struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Volume    string    `json:"volume"`
+	Claim     string    `json:"claim,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Failed returns whether the operation failed
+func (e Event) Failed() bool {
+	return e.Error != ""
+}
+
+// Journal records events into the journal ConfigMap
+type Journal struct {
+	client kubernetes.Interface
+}
+
+// New returns a Journal writing through client
+func New(client kubernetes.Interface) *Journal {
+	return &Journal{client: client}
+}
+
+// Record appends an event to the journal, dropping the oldest ones past maxEvents
+func (j *Journal) Record(ctx context.Context, e Event) error {
+	// another provisioner may update the journal, or create it first, in between
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	return retry.OnError(retry.DefaultRetry, retriable, func() error {
+		cms := j.client.CoreV1().ConfigMaps(Namespace)
+		cm, err := cms.Get(ctx, ConfigMapName, meta.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = &core.ConfigMap{ObjectMeta: meta.ObjectMeta{Name: ConfigMapName, Namespace: Namespace}}
+			if err := encode(cm, []Event{e}); err != nil {
+				return err
+			}
+			_, err = cms.Create(ctx, cm, meta.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return errors.Wrap(err, "get journal")
+		}
+
+		events, err := decode(cm)
+		if err != nil {
+			return err
+		}
+		events = append(events, e)
+		if len(events) > maxEvents {
+			events = events[len(events)-maxEvents:]
+		}
+		if err := encode(cm, events); err != nil {
+			return err
+		}
+		_, err = cms.Update(ctx, cm, meta.UpdateOptions{})
+		return err
+	})
+}
+
+// Read returns the journal events, oldest first
+func Read(ctx context.Context, client kubernetes.Interface) ([]Event, error) {
+	cm, err := client.CoreV1().ConfigMaps(Namespace).Get(ctx, ConfigMapName, meta.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "get journal")
+	}
+	return decode(cm)
+}
+
+func decode(cm *core.ConfigMap) ([]Event, error) {
+	var events []Event
+	data, ok := cm.Data[eventsKey]
+	if !ok {
+		return events, nil
+	}
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		return nil, errors.Wrap(err, "decode journal")
+	}
+	return events, nil
+}
+
+func encode(cm *core.ConfigMap, events []Event) error {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return errors.Wrap(err, "encode journal")
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[eventsKey] = string(data)
+	return nil
+}
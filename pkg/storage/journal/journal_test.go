@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRecord(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	j := New(client)
+	ctx := context.Background()
+
+	events, err := Read(ctx, client)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("Read on empty journal = %v, %v", events, err)
+	}
+
+	for i := 0; i < maxEvents+5; i++ {
+		e := Event{Operation: "provision", Volume: fmt.Sprintf("pvc-%d", i)}
+		if i%2 == 0 {
+			e.Error = errors.New("boom").Error()
+		}
+		if err := j.Record(ctx, e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	events, err = Read(ctx, client)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(events) != maxEvents {
+		t.Fatalf("got %d events, want %d", len(events), maxEvents)
+	}
+	if got, want := events[0].Volume, "pvc-5"; got != want {
+		t.Errorf("oldest event is %s, want %s", got, want)
+	}
+	if got, want := events[len(events)-1].Volume, fmt.Sprintf("pvc-%d", maxEvents+4); got != want {
+		t.Errorf("newest event is %s, want %s", got, want)
+	}
+	if !events[len(events)-1].Failed() {
+		t.Errorf("expected newest event to be a failure")
+	}
+}
+
+func TestRecordCreatedConcurrently(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	j := New(client)
+	ctx := context.Background()
+	if err := j.Record(ctx, Event{Operation: "provision", Volume: "pvc-0"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	// the journal is not found on the first get, as if another provisioner created it right after
+	missed := false
+	client.PrependReactor("get", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if missed {
+			return false, nil, nil
+		}
+		missed = true
+		return true, nil, apierrors.NewNotFound(core.Resource("configmaps"), ConfigMapName)
+	})
+	if err := j.Record(ctx, Event{Operation: "provision", Volume: "pvc-1"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	events, err := Read(ctx, client)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("got %d events, want 2", len(events))
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+)
+
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// runLeaderElected blocks campaigning for the configured Lease, calling run once this replica becomes leader
+func runLeaderElected(ctx context.Context, clientset kubernetes.Interface, cfg Config, run func(context.Context)) error {
+	id := os.Getenv(podNameEnv)
+	if id == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrap(err, "hostname")
+		}
+		// hostNetwork pods share the node hostname, so add a unique suffix
+		id = hostname + "_" + string(uuid.NewUUID())
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: meta.ObjectMeta{
+			Namespace: cfg.LeaseNamespace,
+			Name:      cfg.LeaseName,
+		},
+		Client: clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: id,
+		},
+	}
+
+	klog.Infof("Attempting to acquire lease %s/%s as %s", cfg.LeaseNamespace, cfg.LeaseName, id)
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		Name:            cfg.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: run,
+			OnStoppedLeading: func() {
+				// the controller can't be safely restarted in-process, let the kubelet do it
+				klog.Exitf("Lost lease %s/%s, exiting", cfg.LeaseNamespace, cfg.LeaseName)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != id {
+					klog.Infof("Storage provisioner leader is now %s", identity)
+				}
+			},
+		},
+	})
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const metricsNamespace = "storage_provisioner"
+
+// provisionedByAnnotation is set on PVs by the provision controller to the name of their provisioner
+const provisionedByAnnotation = "pv.kubernetes.io/provisioned-by"
+
+var (
+	operationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "operations_total",
+		Help:      "Number of provision and delete operations, by operation.",
+	}, []string{"operation"})
+
+	operationFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "operation_failures_total",
+		Help:      "Number of failed provision and delete operations, by operation.",
+	}, []string{"operation"})
+
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of provision and delete operations, by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	provisionedBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "provisioned_bytes",
+		Help:      "Total capacity requested by the volumes of this provisioner.",
+	})
+
+	volumeDirsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "volume_directories"),
+		"Number of volume directories in the PV directory, by namespace.",
+		[]string{"namespace"}, nil,
+	)
+)
+
+// pvDirCollector reports the volume directories currently present in the PV directory
+type pvDirCollector struct {
+	pvDir string
+}
+
+// Describe implements prometheus.Collector
+func (c *pvDirCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- volumeDirsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *pvDirCollector) Collect(ch chan<- prometheus.Metric) {
+	namespaces, err := ioutil.ReadDir(c.pvDir)
+	if err != nil {
+		klog.Warningf("unable to read %s: %v", c.pvDir, err)
+		return
+	}
+	for _, ns := range namespaces {
+		if !ns.IsDir() {
+			continue
+		}
+		volumes, err := ioutil.ReadDir(filepath.Join(c.pvDir, ns.Name()))
+		if err != nil {
+			klog.Warningf("unable to read %s: %v", ns.Name(), err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(volumeDirsDesc, prometheus.GaugeValue, float64(len(volumes)), ns.Name())
+	}
+}
+
+// observe records the outcome of a single provisioner operation
+func observe(operation string, start time.Time, err error) {
+	operationsTotal.WithLabelValues(operation).Inc()
+	operationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		operationFailuresTotal.WithLabelValues(operation).Inc()
+	}
+}
+
+// initProvisionedBytes sets provisionedBytes to the capacity of the volumes provisioned before this process started,
+// which would otherwise be missing from it, and subtracted from it when deleted
+func initProvisionedBytes(ctx context.Context, clientset kubernetes.Interface) error {
+	pvs, err := clientset.CoreV1().PersistentVolumes().List(ctx, meta.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing PVs")
+	}
+	var total int64
+	for _, pv := range pvs.Items {
+		if pv.Annotations[provisionedByAnnotation] != provisionerName {
+			continue
+		}
+		capacity := pv.Spec.Capacity[core.ResourceStorage]
+		total += capacity.Value()
+	}
+	provisionedBytes.Set(float64(total))
+	return nil
+}
+
+// serveMetrics registers the provisioner metrics and serves them on addr until the process exits
+func serveMetrics(addr string, pvDir string) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		operationsTotal,
+		operationFailuresTotal,
+		operationDuration,
+		provisionedBytes,
+		&pvDirCollector{pvDir: pvDir},
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	go func() {
+		klog.Infof("Serving metrics on %s/metrics", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			klog.Errorf("metrics listener on %s stopped: %v", addr, err)
+		}
+	}()
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestProvisionMetrics(t *testing.T) {
+	for _, node := range []string{"minikube", "minikube-m02"} {
+		t.Run(node, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "hostpath")
+			if err != nil {
+				t.Fatalf("tempdir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			provisions := testutil.ToFloat64(operationsTotal.WithLabelValues("provision"))
+			deletes := testutil.ToFloat64(operationsTotal.WithLabelValues("delete"))
+			bytes := testutil.ToFloat64(provisionedBytes)
+
+			p := &hostPathProvisioner{pvDir: dir, nodeName: "minikube", identity: "id", helper: &fakeHelper{}}
+			pv, _, err := p.Provision(context.Background(), provisionOptions(node))
+			if err != nil {
+				t.Fatalf("Provision: %v", err)
+			}
+			if got := testutil.ToFloat64(operationsTotal.WithLabelValues("provision")); got != provisions+1 {
+				t.Errorf("provision operations = %v, want %v", got, provisions+1)
+			}
+			if got := testutil.ToFloat64(provisionedBytes); got != bytes+1<<30 {
+				t.Errorf("provisioned bytes = %v, want %v", got, bytes+1<<30)
+			}
+
+			if err := p.Delete(context.Background(), pv); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if got := testutil.ToFloat64(operationsTotal.WithLabelValues("delete")); got != deletes+1 {
+				t.Errorf("delete operations = %v, want %v", got, deletes+1)
+			}
+			if got := testutil.ToFloat64(provisionedBytes); got != bytes {
+				t.Errorf("provisioned bytes after delete = %v, want %v", got, bytes)
+			}
+		})
+	}
+}
+
+func TestPVDirCollector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hostpath")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, v := range []string{"default/a", "default/b", "kube-system/c"} {
+		if err := os.MkdirAll(filepath.Join(dir, v), 0777); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(&pvDirCollector{pvDir: dir})
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	got := map[string]float64{}
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			got[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+		}
+	}
+	want := map[string]float64{"default": 2, "kube-system": 1}
+	for ns, n := range want {
+		if got[ns] != n {
+			t.Errorf("volume directories in %s = %v, want %v", ns, got[ns], n)
+		}
+	}
+}
+
+func TestInitProvisionedBytes(t *testing.T) {
+	pv := func(name string, provisioner string, size string) *core.PersistentVolume {
+		return &core.PersistentVolume{
+			ObjectMeta: meta.ObjectMeta{Name: name, Annotations: map[string]string{provisionedByAnnotation: provisioner}},
+			Spec:       core.PersistentVolumeSpec{Capacity: core.ResourceList{core.ResourceStorage: resource.MustParse(size)}},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		pv("a", provisionerName, "1Gi"),
+		pv("b", provisionerName, "2Gi"),
+		pv("c", "other.io/provisioner", "4Gi"),
+	)
+	if err := initProvisionedBytes(context.Background(), client); err != nil {
+		t.Fatalf("initProvisionedBytes: %v", err)
+	}
+	if got := testutil.ToFloat64(provisionedBytes); got != 3<<30 {
+		t.Errorf("provisioned bytes = %v, want %v", got, 3<<30)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// StorageClass parameters understood by the hostpath provisioner
+const (
+	modeParameter         = "mode"
+	uidParameter          = "uid"
+	gidParameter          = "gid"
+	seLinuxLabelParameter = "seLinuxLabel"
+)
+
+// defaultMode is used when the StorageClass doesn't set a mode, so volumes are writable by any pod
+const defaultMode os.FileMode = 0777
+
+// volumeParameters describes how a provisioned volume directory is set up
+type volumeParameters struct {
+	mode os.FileMode
+	// uid and gid are -1 when ownership should be left alone
+	uid          int
+	gid          int
+	seLinuxLabel string
+}
+
+// parseVolumeParameters reads volume directory settings from StorageClass parameters
+func parseVolumeParameters(params map[string]string) (volumeParameters, error) {
+	vp := volumeParameters{mode: defaultMode, uid: -1, gid: -1}
+	for k, v := range params {
+		switch k {
+		case modeParameter:
+			mode, err := parseMode(v)
+			if err != nil {
+				return vp, fmt.Errorf("invalid %s %q: must be an octal file mode", k, v)
+			}
+			vp.mode = mode
+		case uidParameter, gidParameter:
+			id, err := strconv.Atoi(v)
+			if err != nil || id < 0 {
+				return vp, fmt.Errorf("invalid %s %q: must be a non-negative integer", k, v)
+			}
+			if k == uidParameter {
+				vp.uid = id
+			} else {
+				vp.gid = id
+			}
+		case seLinuxLabelParameter:
+			vp.seLinuxLabel = v
+		default:
+			// StorageClasses may carry parameters meant for other tools, which must not prevent provisioning
+			klog.Warningf("ignoring unknown StorageClass parameter %q", k)
+		}
+	}
+	return vp, nil
+}
+
+// specialModeBits maps the octal setuid, setgid and sticky bits to their os.FileMode counterparts
+var specialModeBits = []struct {
+	octal uint64
+	mode  os.FileMode
+}{
+	{04000, os.ModeSetuid},
+	{02000, os.ModeSetgid},
+	{01000, os.ModeSticky},
+}
+
+// parseMode parses an octal file mode such as "0750" or "2775"
+func parseMode(s string) (os.FileMode, error) {
+	n, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, err
+	}
+	if n > 07777 {
+		return 0, fmt.Errorf("%#o is out of range", n)
+	}
+	mode := os.FileMode(n & 0777)
+	for _, b := range specialModeBits {
+		if n&b.octal != 0 {
+			mode |= b.mode
+		}
+	}
+	return mode, nil
+}
+
+// formatMode is the inverse of parseMode
+func formatMode(mode os.FileMode) string {
+	n := uint64(mode.Perm())
+	for _, b := range specialModeBits {
+		if mode&b.mode != 0 {
+			n |= b.octal
+		}
+	}
+	return fmt.Sprintf("%#o", n)
+}
+
+// apply sets up the volume directory at path according to the parameters
+func (vp volumeParameters) apply(path string) error {
+	// Explicitly chmod created dir, so we know the mode is set regardless of umask
+	if err := os.Chmod(path, vp.mode); err != nil {
+		return errors.Wrap(err, "chmod")
+	}
+	if vp.uid != -1 || vp.gid != -1 {
+		if err := os.Chown(path, vp.uid, vp.gid); err != nil {
+			return errors.Wrap(err, "chown")
+		}
+	}
+	if vp.seLinuxLabel != "" {
+		if err := setSELinuxLabel(path, vp.seLinuxLabel); err != nil {
+			return errors.Wrap(err, "setting SELinux label")
+		}
+	}
+	return nil
+}
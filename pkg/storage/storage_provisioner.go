@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -31,48 +32,115 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/storage/journal"
 	"sigs.k8s.io/sig-storage-lib-external-provisioner/v6/controller"
 )
 
-const provisionerName = "k8s.io/minikube-hostpath"
+const (
+	provisionerName = "k8s.io/minikube-hostpath"
+
+	// nodeNameEnv, podNameEnv and podNamespaceEnv are populated through the
+	// downward API with the node, name and namespace of the provisioner pod
+	nodeNameEnv     = "NODE_NAME"
+	podNameEnv      = "POD_NAME"
+	podNamespaceEnv = "POD_NAMESPACE"
+
+	identityAnnotation = "hostPathProvisionerIdentity"
+	nodeAnnotation     = "hostPathProvisionerNode"
+)
 
 type hostPathProvisioner struct {
 	// The directory to create PV-backing directories in
 	pvDir string
 
+	// The name of the node this provisioner runs on, empty if unknown
+	nodeName string
+
 	// Identity of this hostPathProvisioner, generated. Used to identify "this"
 	// provisioner's PVs.
 	identity types.UID
+
+	// Where operations are recorded for `minikube storage status`, optional
+	journal *journal.Journal
+
+	// Sets up and removes directories of volumes on other nodes, optional
+	helper volumeHelper
+
+	// Lists the nodes of the cluster, optional. Without it, volumes which do
+	// not record their node are never removed.
+	clientset kubernetes.Interface
+
+	// Whether replicas take turns through leader election, in which case the
+	// leader also deletes volumes provisioned by earlier leaders
+	leaderElected bool
 }
 
 // NewHostPathProvisioner creates a new Provisioner using host paths
 func NewHostPathProvisioner(pvDir string) controller.Provisioner {
+	return newHostPathProvisioner(pvDir, uuid.NewUUID())
+}
+
+func newHostPathProvisioner(pvDir string, identity types.UID) *hostPathProvisioner {
 	return &hostPathProvisioner{
 		pvDir:    pvDir,
-		identity: uuid.NewUUID(),
+		nodeName: os.Getenv(nodeNameEnv),
+		identity: identity,
 	}
 }
 
 var _ controller.Provisioner = &hostPathProvisioner{}
 
 // Provision creates a storage asset and returns a PV object representing it.
-func (p *hostPathProvisioner) Provision(ctx context.Context, options controller.ProvisionOptions) (*core.PersistentVolume, controller.ProvisioningState, error) {
+func (p *hostPathProvisioner) Provision(ctx context.Context, options controller.ProvisionOptions) (pv *core.PersistentVolume, state controller.ProvisioningState, err error) {
 	path := path.Join(p.pvDir, options.PVC.Namespace, options.PVC.Name)
+	node := selectedNodeName(options)
+	if node == "" {
+		// Immediate binding: the directory is created here, so the volume lives here
+		node = p.nodeName
+	}
+
+	start := time.Now()
+	defer func() {
+		observe("provision", start, err)
+		p.record(journal.Event{
+			Operation: "provision",
+			Volume:    options.PVName,
+			Claim:     options.PVC.Namespace + "/" + options.PVC.Name,
+			Node:      node,
+			Path:      path,
+		}, err)
+	}()
+
 	klog.Infof("Provisioning volume %v to %s", options, path)
-	if err := os.MkdirAll(path, 0777); err != nil {
+
+	params, err := parseVolumeParameters(options.StorageClass.Parameters)
+	if err != nil {
 		return nil, controller.ProvisioningFinished, err
 	}
 
-	// Explicitly chmod created dir, so we know mode is set to 0777 regardless of umask
-	if err := os.Chmod(path, 0777); err != nil {
-		return nil, controller.ProvisioningFinished, err
+	if p.isLocal(node) {
+		if err := os.MkdirAll(path, params.mode); err != nil {
+			return nil, controller.ProvisioningFinished, err
+		}
+		if err := params.apply(path); err != nil {
+			return nil, controller.ProvisioningFinished, errors.Wrapf(err, "setting up %s", path)
+		}
+	} else {
+		// The consumer was scheduled on another node, whose filesystem we can't
+		// reach from here: create the directory through a helper pod there.
+		if p.helper == nil {
+			return nil, controller.ProvisioningFinished, fmt.Errorf("unable to create %s on remote node %q: no volume helper", path, node)
+		}
+		if err := p.helper.create(ctx, node, path, params); err != nil {
+			return nil, controller.ProvisioningFinished, errors.Wrapf(err, "setting up %s on %s", path, node)
+		}
 	}
 
-	pv := &core.PersistentVolume{
+	pv = &core.PersistentVolume{
 		ObjectMeta: meta.ObjectMeta{
 			Name: options.PVName,
 			Annotations: map[string]string{
-				"hostPathProvisionerIdentity": string(p.identity),
+				identityAnnotation: string(p.identity),
 			},
 		},
 		Spec: core.PersistentVolumeSpec{
@@ -82,37 +150,165 @@ func (p *hostPathProvisioner) Provision(ctx context.Context, options controller.
 				core.ResourceStorage: options.PVC.Spec.Resources.Requests[core.ResourceStorage],
 			},
 			PersistentVolumeSource: core.PersistentVolumeSource{
-				HostPath: &core.HostPathVolumeSource{
-					Path: path,
-				},
+				HostPath: &core.HostPathVolumeSource{Path: path},
 			},
 		},
 	}
 
+	if node != "" {
+		pv.Annotations[nodeAnnotation] = node
+		pv.Spec.NodeAffinity = nodeAffinity(node)
+	}
+
+	capacity := pv.Spec.Capacity[core.ResourceStorage]
+	provisionedBytes.Add(float64(capacity.Value()))
+
 	return pv, controller.ProvisioningFinished, nil
 }
 
+// record adds the outcome of an operation to the journal, if there is one
+func (p *hostPathProvisioner) record(e journal.Event, err error) {
+	if p.journal == nil {
+		return
+	}
+	e.Time = time.Now()
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if err := p.journal.Record(context.Background(), e); err != nil {
+		klog.Warningf("unable to record %s of %s: %v", e.Operation, e.Volume, err)
+	}
+}
+
+// singleNode returns whether the cluster has a single node, false if the nodes can't be listed
+func (p *hostPathProvisioner) singleNode(ctx context.Context) (bool, error) {
+	if p.clientset == nil {
+		return false, nil
+	}
+	nodes, err := p.clientset.CoreV1().Nodes().List(ctx, meta.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	return len(nodes.Items) == 1, nil
+}
+
+// isLocal returns whether a volume bound to node can be created on this node's filesystem
+func (p *hostPathProvisioner) isLocal(node string) bool {
+	return node == "" || p.nodeName == "" || node == p.nodeName
+}
+
+// selectedNodeName returns the node picked by the scheduler for WaitForFirstConsumer storage classes
+func selectedNodeName(options controller.ProvisionOptions) string {
+	if options.SelectedNode == nil {
+		return ""
+	}
+	return options.SelectedNode.Name
+}
+
+// nodeAffinity pins a volume to the node its backing directory lives on
+func nodeAffinity(node string) *core.VolumeNodeAffinity {
+	return &core.VolumeNodeAffinity{
+		Required: &core.NodeSelector{
+			NodeSelectorTerms: []core.NodeSelectorTerm{
+				{
+					MatchExpressions: []core.NodeSelectorRequirement{
+						{
+							Key:      core.LabelHostname,
+							Operator: core.NodeSelectorOpIn,
+							Values:   []string{node},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 // Delete removes the storage asset that was created by Provision represented
 // by the given PV.
-func (p *hostPathProvisioner) Delete(ctx context.Context, volume *core.PersistentVolume) error {
+func (p *hostPathProvisioner) Delete(ctx context.Context, volume *core.PersistentVolume) (err error) {
+	start := time.Now()
+	defer func() {
+		// volumes owned by another provisioner instance are not our failures
+		if _, ignored := err.(*controller.IgnoredError); ignored {
+			return
+		}
+		observe("delete", start, err)
+		e := journal.Event{
+			Operation: "delete",
+			Volume:    volume.Name,
+			Node:      volume.Annotations[nodeAnnotation],
+		}
+		if ref := volume.Spec.ClaimRef; ref != nil {
+			e.Claim = ref.Namespace + "/" + ref.Name
+		}
+		if hp := volume.Spec.HostPath; hp != nil {
+			e.Path = hp.Path
+		}
+		p.record(e, err)
+	}()
+
 	klog.Infof("Deleting volume %v", volume)
-	ann, ok := volume.Annotations["hostPathProvisionerIdentity"]
+	ann, ok := volume.Annotations[identityAnnotation]
 	if !ok {
 		return errors.New("identity annotation not found on PV")
 	}
-	if ann != string(p.identity) {
+	if ann != string(p.identity) && !p.leaderElected {
 		return &controller.IgnoredError{Reason: "identity annotation on PV does not match ours"}
 	}
-
-	if err := os.RemoveAll(volume.Spec.PersistentVolumeSource.HostPath.Path); err != nil {
+	path := volume.Spec.PersistentVolumeSource.HostPath.Path
+	node, annotated := volume.Annotations[nodeAnnotation]
+	if !annotated && ann != string(p.identity) {
+		// earlier provisioners did not record the node the directory is on, which may not be this one
+		klog.Warningf("Volume %s of provisioner %s does not record its node, leaving %s in place", volume.Name, ann, path)
+		return &controller.IgnoredError{Reason: "PV of another provisioner has no node annotation"}
+	}
+	if !annotated {
+		// the directory is only known to be on this node if there is no other one
+		single, err := p.singleNode(ctx)
+		if err != nil {
+			return errors.Wrap(err, "listing nodes")
+		}
+		if !single {
+			klog.Warningf("Volume %s does not record its node in a multi-node cluster, leaving %s in place", volume.Name, path)
+			return &controller.IgnoredError{Reason: "PV has no node annotation in a multi-node cluster"}
+		}
+	}
+	if !p.isLocal(node) {
+		if p.helper == nil {
+			return fmt.Errorf("unable to remove %s on remote node %q: no volume helper", path, node)
+		}
+		if err := p.helper.remove(ctx, node, path); err != nil {
+			return errors.Wrap(err, "removing hostpath PV")
+		}
+	} else if err := os.RemoveAll(path); err != nil {
 		return errors.Wrap(err, "removing hostpath PV")
 	}
 
+	capacity := volume.Spec.Capacity[core.ResourceStorage]
+	provisionedBytes.Sub(float64(capacity.Value()))
+
 	return nil
 }
 
+// Config holds the settings for a storage provisioner server
+type Config struct {
+	// The directory to create PV-backing directories in
+	PVDir string
+
+	// The address to serve Prometheus metrics on, disabled if empty
+	MetricsAddress string
+
+	// Whether to coordinate with other replicas through a Lease before provisioning
+	LeaderElection bool
+
+	// The namespace and name of the Lease used for leader election
+	LeaseNamespace string
+	LeaseName      string
+}
+
 // StartStorageProvisioner will start storage provisioner server
-func StartStorageProvisioner(pvDir string) error {
+func StartStorageProvisioner(cfg Config) error {
 	klog.Infof("Initializing the minikube storage provisioner...")
 	config, err := rest.InClusterConfig()
 	if err != nil {
@@ -132,13 +328,43 @@ func StartStorageProvisioner(pvDir string) error {
 
 	// Create the provisioner: it implements the Provisioner interface expected by
 	// the controller
-	hostPathProvisioner := NewHostPathProvisioner(pvDir)
+	identity := uuid.NewUUID()
+	if cfg.LeaderElection {
+		// Record which replica provisioned each volume
+		if name := os.Getenv(podNameEnv); name != "" {
+			identity = types.UID(name)
+		}
+	}
+	hostPathProvisioner := newHostPathProvisioner(cfg.PVDir, identity)
+	hostPathProvisioner.leaderElected = cfg.LeaderElection
+	hostPathProvisioner.journal = journal.New(clientset)
+	hostPathProvisioner.clientset = clientset
+	if name, namespace := os.Getenv(podNameEnv), os.Getenv(podNamespaceEnv); name != "" && namespace != "" {
+		image, err := ownPodImage(context.Background(), clientset, namespace, name)
+		if err != nil {
+			klog.Warningf("volumes on remote nodes are unavailable: unable to look up own image: %v", err)
+		} else {
+			hostPathProvisioner.helper = &podHelper{clientset: clientset, namespace: namespace, image: image, pvDir: cfg.PVDir}
+		}
+	}
+
+	if cfg.MetricsAddress != "" {
+		if err := initProvisionedBytes(context.Background(), clientset); err != nil {
+			klog.Warningf("provisioned bytes only count volumes provisioned from now on: %v", err)
+		}
+		serveMetrics(cfg.MetricsAddress, cfg.PVDir)
+	}
 
 	// Start the provision controller which will dynamically provision hostPath
-	// PVs
-	pc := controller.NewProvisionController(clientset, provisionerName, hostPathProvisioner, serverVersion.GitVersion)
+	// PVs. Leader election is handled by us rather than the library, so that
+	// the lock is a Lease whose location can be configured.
+	pc := controller.NewProvisionController(clientset, provisionerName, hostPathProvisioner, serverVersion.GitVersion,
+		controller.LeaderElection(false))
 
 	klog.Info("Storage provisioner initialized, now starting service!")
-	pc.Run(context.Background())
-	return nil
+	if !cfg.LeaderElection {
+		pc.Run(context.Background())
+		return nil
+	}
+	return runLeaderElected(context.Background(), clientset, cfg, pc.Run)
 }
@@ -0,0 +1,325 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/sig-storage-lib-external-provisioner/v6/controller"
+)
+
+func provisionOptions(node string) controller.ProvisionOptions {
+	reclaim := core.PersistentVolumeReclaimDelete
+	opts := controller.ProvisionOptions{
+		StorageClass: &storagev1.StorageClass{ReclaimPolicy: &reclaim},
+		PVName:       "pvc-1234",
+		PVC: &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{Namespace: "default", Name: "claim"},
+			Spec: core.PersistentVolumeClaimSpec{
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{core.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		},
+	}
+	if node != "" {
+		opts.SelectedNode = &core.Node{ObjectMeta: meta.ObjectMeta{Name: node}}
+	}
+	return opts
+}
+
+// fakeHelper records the operations run on remote nodes
+type fakeHelper struct {
+	created []string
+	removed []string
+}
+
+func (h *fakeHelper) create(ctx context.Context, node string, path string, params volumeParameters) error {
+	h.created = append(h.created, node+":"+path)
+	return nil
+}
+
+func (h *fakeHelper) remove(ctx context.Context, node string, path string) error {
+	h.removed = append(h.removed, node+":"+path)
+	return nil
+}
+
+func TestProvision(t *testing.T) {
+	tests := []struct {
+		description  string
+		selectedNode string
+		wantNode     string
+		wantLocal    bool
+	}{
+		{"immediate binding", "", "minikube", true},
+		{"consumer on provisioner node", "minikube", "minikube", true},
+		{"consumer on remote node", "minikube-m02", "minikube-m02", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "hostpath")
+			if err != nil {
+				t.Fatalf("tempdir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			helper := &fakeHelper{}
+			p := &hostPathProvisioner{pvDir: dir, nodeName: "minikube", identity: "id", helper: helper}
+			pv, _, err := p.Provision(context.Background(), provisionOptions(tc.selectedNode))
+			if err != nil {
+				t.Fatalf("Provision: %v", err)
+			}
+
+			path := filepath.Join(dir, "default", "claim")
+			_, statErr := os.Stat(path)
+			if created := statErr == nil; created != tc.wantLocal {
+				t.Errorf("directory created = %v, want %v", created, tc.wantLocal)
+			}
+			if remote := len(helper.created) != 0; remote == tc.wantLocal {
+				t.Errorf("helper created %v, want remote creation = %v", helper.created, !tc.wantLocal)
+			}
+			if !tc.wantLocal && helper.created[0] != tc.wantNode+":"+path {
+				t.Errorf("helper created %v, want %s:%s", helper.created, tc.wantNode, path)
+			}
+
+			if got := pv.Annotations[nodeAnnotation]; got != tc.wantNode {
+				t.Errorf("node annotation = %q, want %q", got, tc.wantNode)
+			}
+			if pv.Spec.NodeAffinity == nil {
+				t.Fatalf("expected node affinity for %q", tc.wantNode)
+			}
+			values := pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values
+			if len(values) != 1 || values[0] != tc.wantNode {
+				t.Errorf("node affinity values = %v, want [%s]", values, tc.wantNode)
+			}
+		})
+	}
+}
+
+func TestProvisionRemoteWithoutHelper(t *testing.T) {
+	p := &hostPathProvisioner{pvDir: "/nonexistent", nodeName: "minikube", identity: "id"}
+	if _, _, err := p.Provision(context.Background(), provisionOptions("minikube-m02")); err == nil {
+		t.Errorf("expected an error provisioning on a remote node without a helper")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		description string
+		node        string
+		nodes       []string
+		wantRemote  bool
+		wantIgnored bool
+	}{
+		{"legacy volume without node", "", []string{"minikube"}, false, false},
+		{"legacy volume in multi-node cluster", "", []string{"minikube", "minikube-m02"}, false, true},
+		{"volume on provisioner node", "minikube", []string{"minikube", "minikube-m02"}, false, false},
+		{"volume on remote node", "minikube-m02", []string{"minikube", "minikube-m02"}, true, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "hostpath")
+			if err != nil {
+				t.Fatalf("tempdir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "default", "claim")
+			if err := os.MkdirAll(path, 0777); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+
+			helper := &fakeHelper{}
+			client := fake.NewSimpleClientset()
+			for _, n := range tc.nodes {
+				if _, err := client.CoreV1().Nodes().Create(context.Background(), &core.Node{ObjectMeta: meta.ObjectMeta{Name: n}}, meta.CreateOptions{}); err != nil {
+					t.Fatalf("creating node: %v", err)
+				}
+			}
+			p := &hostPathProvisioner{pvDir: dir, nodeName: "minikube", identity: "id", helper: helper, clientset: client}
+			pv := &core.PersistentVolume{
+				ObjectMeta: meta.ObjectMeta{
+					Name:        "pvc-1234",
+					Annotations: map[string]string{identityAnnotation: "id"},
+				},
+				Spec: core.PersistentVolumeSpec{
+					PersistentVolumeSource: core.PersistentVolumeSource{
+						HostPath: &core.HostPathVolumeSource{Path: path},
+					},
+				},
+			}
+			if tc.node != "" {
+				pv.Annotations[nodeAnnotation] = tc.node
+			}
+			err = p.Delete(context.Background(), pv)
+			if _, ignored := err.(*controller.IgnoredError); ignored != tc.wantIgnored {
+				t.Fatalf("Delete error = %v, want ignored = %v", err, tc.wantIgnored)
+			}
+			if err != nil && !tc.wantIgnored {
+				t.Fatalf("Delete: %v", err)
+			}
+
+			_, statErr := os.Stat(path)
+			wantLocal := !tc.wantRemote && !tc.wantIgnored
+			if removed := os.IsNotExist(statErr); removed != wantLocal {
+				t.Errorf("local directory removed = %v, want %v", removed, wantLocal)
+			}
+			if remote := len(helper.removed) != 0; remote != tc.wantRemote {
+				t.Errorf("helper removed %v, want remote removal = %v", helper.removed, tc.wantRemote)
+			}
+		})
+	}
+}
+
+func TestDeleteOtherProvisioner(t *testing.T) {
+	tests := []struct {
+		description string
+		node        string
+		wantRemoved bool
+	}{
+		{"legacy volume without node", "", false},
+		{"volume on leader node", "minikube", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "hostpath")
+			if err != nil {
+				t.Fatalf("tempdir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "default", "claim")
+			if err := os.MkdirAll(path, 0777); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+
+			p := &hostPathProvisioner{pvDir: dir, nodeName: "minikube", identity: "storage-provisioner-abcde", leaderElected: true}
+			pv := &core.PersistentVolume{
+				ObjectMeta: meta.ObjectMeta{
+					Name:        "pvc-1234",
+					Annotations: map[string]string{identityAnnotation: "storage-provisioner-fghij"},
+				},
+				Spec: core.PersistentVolumeSpec{
+					PersistentVolumeSource: core.PersistentVolumeSource{
+						HostPath: &core.HostPathVolumeSource{Path: path},
+					},
+				},
+			}
+			if tc.node != "" {
+				pv.Annotations[nodeAnnotation] = tc.node
+			}
+			err = p.Delete(context.Background(), pv)
+			if tc.wantRemoved && err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, ignored := err.(*controller.IgnoredError); !tc.wantRemoved && !ignored {
+				t.Errorf("Delete error = %v, want the volume to be ignored", err)
+			}
+
+			_, statErr := os.Stat(path)
+			if removed := os.IsNotExist(statErr); removed != tc.wantRemoved {
+				t.Errorf("local directory removed = %v, want %v", removed, tc.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestRunVolumeHelper(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hostpath")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "default", "claim")
+	if err := RunVolumeHelper(VolumeHelperConfig{PVDir: dir, Operation: "create", Path: path, Mode: "0777"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if fi.Mode().Perm() != 0777 {
+		t.Errorf("mode = %#o, want 0777", fi.Mode().Perm())
+	}
+
+	if err := RunVolumeHelper(VolumeHelperConfig{PVDir: dir, Operation: "delete", Path: filepath.Join(dir, "..")}); err == nil {
+		t.Errorf("expected an error deleting outside of the PV directory")
+	}
+	if err := RunVolumeHelper(VolumeHelperConfig{PVDir: dir, Operation: "delete", Path: path}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", path, err)
+	}
+}
+
+func TestParseVolumeParameters(t *testing.T) {
+	tests := []struct {
+		description string
+		params      map[string]string
+		want        volumeParameters
+		wantErr     bool
+	}{
+		{"defaults", nil, volumeParameters{mode: 0777, uid: -1, gid: -1}, false},
+		{"mode", map[string]string{"mode": "0750"}, volumeParameters{mode: 0750, uid: -1, gid: -1}, false},
+		{"ownership", map[string]string{"uid": "1000", "gid": "2000"}, volumeParameters{mode: 0777, uid: 1000, gid: 2000}, false},
+		{"selinux", map[string]string{"seLinuxLabel": "system_u:object_r:container_file_t:s0"}, volumeParameters{mode: 0777, uid: -1, gid: -1, seLinuxLabel: "system_u:object_r:container_file_t:s0"}, false},
+		{"setgid mode", map[string]string{"mode": "2775"}, volumeParameters{mode: 0775 | os.ModeSetgid, uid: -1, gid: -1}, false},
+		{"sticky mode", map[string]string{"mode": "1777"}, volumeParameters{mode: 0777 | os.ModeSticky, uid: -1, gid: -1}, false},
+		{"non-octal mode", map[string]string{"mode": "0999"}, volumeParameters{}, true},
+		{"out of range mode", map[string]string{"mode": "017777"}, volumeParameters{}, true},
+		{"negative uid", map[string]string{"uid": "-5"}, volumeParameters{}, true},
+		{"unknown parameter", map[string]string{"owner": "root", "mode": "0700"}, volumeParameters{mode: 0700, uid: -1, gid: -1}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := parseVolumeParameters(tc.params)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseVolumeParameters(%v) error = %v, wantErr %v", tc.params, err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want {
+				t.Errorf("parseVolumeParameters(%v) = %+v, want %+v", tc.params, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHelperArgs(t *testing.T) {
+	params := volumeParameters{mode: 0750 | os.ModeSetuid, uid: 1000, gid: -1, seLinuxLabel: "system_u:object_r:container_file_t:s0:c1,c2"}
+	got := helperArgs(helperCreate, "/tmp/hostpath-provisioner/default/claim", params)
+	want := []string{
+		"--volume-helper=create",
+		"--volume-path=/tmp/hostpath-provisioner/default/claim",
+		"--volume-mode=04750",
+		"--volume-uid=1000",
+		"--volume-selinux-label=system_u:object_r:container_file_t:s0:c1,c2",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("helperArgs() = %v, want %v", got, want)
+	}
+}
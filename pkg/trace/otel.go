@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/klog/v2"
+)
+
+// otelTracer records the steps of a minikube command as spans of an OpenTelemetry trace,
+// all children of a parent span named after the command.
+type otelTracer struct {
+	parentCtx context.Context
+	parent    trace.Span
+	trace.Tracer
+	// spans are accessed by steps running concurrently, such as enabling addons
+	mu      sync.Mutex
+	spans   map[string]trace.Span
+	cleanup func()
+}
+
+func newOtelTracer(t trace.Tracer, command string, cleanup func()) *otelTracer {
+	ctx, span := t.Start(context.Background(), command)
+	return &otelTracer{
+		parentCtx: ctx,
+		parent:    span,
+		Tracer:    t,
+		spans:     map[string]trace.Span{},
+		cleanup:   cleanup,
+	}
+}
+
+// StartSpan starts a span for the next step of the command
+func (t *otelTracer) StartSpan(name string) {
+	_, span := t.Tracer.Start(t.parentCtx, name)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans[name] = span
+}
+
+// EndSpan ends the span of a step, indicating that it has completed
+func (t *otelTracer) EndSpan(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span, ok := t.spans[name]
+	if !ok {
+		klog.Warningf("cannot end span %s as it was never started", name)
+		return
+	}
+	span.End()
+	delete(t.spans, name)
+}
+
+// SetAttribute describes the traced command, such as with the driver or Kubernetes version it uses
+func (t *otelTracer) SetAttribute(key, value string) {
+	t.parent.SetAttributes(label.String(key, value))
+}
+
+// Cleanup ends the spans left open and the parent span, then flushes the trace
+func (t *otelTracer) Cleanup() {
+	t.mu.Lock()
+	for _, span := range t.spans {
+		span.End()
+	}
+	t.spans = map[string]trace.Span{}
+	t.mu.Unlock()
+	t.parent.End()
+	t.cleanup()
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultOTLPEndpoint is the address OpenTelemetry collectors receive OTLP/gRPC on by default
+	DefaultOTLPEndpoint = "localhost:4317"
+	// otlpShutdownTimeout is how long exporting the remaining spans may take when the command exits
+	otlpShutdownTimeout = 10 * time.Second
+)
+
+// otlpDriverOptions returns the options to connect to an OTLP/gRPC endpoint,
+// which is plain text unless it is prefixed with https://
+func otlpDriverOptions(endpoint string) []otlpgrpc.Option {
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+	if strings.HasPrefix(endpoint, "https://") {
+		return []otlpgrpc.Option{
+			otlpgrpc.WithEndpoint(strings.TrimPrefix(endpoint, "https://")),
+			otlpgrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")),
+		}
+	}
+	return []otlpgrpc.Option{
+		otlpgrpc.WithEndpoint(strings.TrimPrefix(endpoint, "http://")),
+		otlpgrpc.WithInsecure(),
+	}
+}
+
+func initOTLPTracer(endpoint string, command string) (*otelTracer, error) {
+	ctx := context.Background()
+	exp, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(otlpDriverOptions(endpoint)...))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating OTLP exporter")
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithConfig(sdktrace.Config{
+			DefaultSampler: sdktrace.AlwaysSample(),
+		}),
+	)
+	otel.SetTracerProvider(tp)
+
+	shutdown := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), otlpShutdownTimeout)
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
+			klog.Warningf("failed to export traces to %s: %v", endpoint, err)
+		}
+	}
+	return newOtelTracer(tp.Tracer(command), command, shutdown), nil
+}
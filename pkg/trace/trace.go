@@ -29,12 +29,14 @@ var (
 type minikubeTracer interface {
 	StartSpan(string)
 	EndSpan(string)
+	SetAttribute(string, string)
 	Cleanup()
 }
 
-// Initialize intializes the global tracer variable
-func Initialize(t string) error {
-	tr, err := getTracer(t)
+// Initialize intializes the global tracer variable, tracing the given command.
+// The endpoint is the address traces are sent to, for tracers which support one.
+func Initialize(t string, endpoint string, command string) error {
+	tr, err := getTracer(t, endpoint, command)
 	if err != nil {
 		return errors.Wrap(err, "getting tracer")
 	}
@@ -42,14 +44,16 @@ func Initialize(t string) error {
 	return nil
 }
 
-func getTracer(t string) (minikubeTracer, error) {
+func getTracer(t string, endpoint string, command string) (minikubeTracer, error) {
 	switch t {
 	case "gcp":
-		return initGCPTracer()
+		return initGCPTracer(command)
+	case "otlp":
+		return initOTLPTracer(endpoint, command)
 	case "":
 		return nil, nil
 	}
-	return nil, fmt.Errorf("%s is not a valid tracer, valid tracers include: [gcp]", t)
+	return nil, fmt.Errorf("%s is not a valid tracer, valid tracers include: [gcp, otlp]", t)
 }
 
 // StartSpan starts a span with the given name
@@ -68,6 +72,14 @@ func EndSpan(name string) {
 	tracer.EndSpan(name)
 }
 
+// SetAttribute sets an attribute describing the traced command
+func SetAttribute(key, value string) {
+	if tracer == nil {
+		return
+	}
+	tracer.SetAttribute(key, value)
+}
+
 // Cleanup is responsible for trace related cleanup,
 // such as flushing all data
 func Cleanup() {
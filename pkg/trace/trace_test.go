@@ -0,0 +1,32 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"testing"
+)
+
+func TestGetTracer(t *testing.T) {
+	tr, err := getTracer("", "", "minikube start")
+	if err != nil || tr != nil {
+		t.Errorf("getTracer(\"\") = %v, %v, want no tracer", tr, err)
+	}
+
+	if _, err := getTracer("jaeger", "", "minikube start"); err == nil {
+		t.Errorf("getTracer(\"jaeger\") succeeded, want an invalid tracer error")
+	}
+}
@@ -18,6 +18,7 @@ package util
 
 import (
 	"net"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -44,28 +45,54 @@ var DefaultV114AdmissionControllers = []string{
 // DefaultLegacyAdmissionControllers are admission controllers we include with Kubernetes <1.14.0
 var DefaultLegacyAdmissionControllers = append([]string{"Initializers"}, DefaultV114AdmissionControllers...)
 
-// GetServiceClusterIP returns the first IP of the ServiceCIDR
+// GetServiceClusterIP returns the first IP of the ServiceCIDR.
+// For a dual-stack "v4,v6" ServiceCIDR, the first family listed is used.
 func GetServiceClusterIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	ip, err := serviceCIDRBase(serviceCIDR)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing default service cidr")
+		return nil, err
 	}
-	ip = ip.To4()
-	ip[3]++
+	ip[len(ip)-1]++
 	return ip, nil
 }
 
-// GetDNSIP returns x.x.x.10 of the service CIDR
+// GetDNSIP returns x.x.x.10 (or x::a for IPv6) of the service CIDR
 func GetDNSIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	ip, err := serviceCIDRBase(serviceCIDR)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing default service cidr")
+		return nil, err
 	}
-	ip = ip.To4()
-	ip[3] = 10
+	ip[len(ip)-1] = 10
 	return ip, nil
 }
 
+// SplitCIDRs splits a comma separated (dual-stack) list of CIDRs
+func SplitCIDRs(cidrs string) []string {
+	var out []string
+	for _, c := range strings.Split(cidrs, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// serviceCIDRBase returns a copy of the network address of the first CIDR in serviceCIDR
+func serviceCIDRBase(serviceCIDR string) (net.IP, error) {
+	cidrs := SplitCIDRs(serviceCIDR)
+	if len(cidrs) == 0 {
+		return nil, errors.Errorf("empty service cidr")
+	}
+	ip, _, err := net.ParseCIDR(cidrs[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing default service cidr")
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return v4, nil
+	}
+	return ip.To16(), nil
+}
+
 // GetAlternateDNS returns a list of alternate names for a domain
 func GetAlternateDNS(domain string) []string {
 	return []string{"kubernetes.default.svc." + domain, "kubernetes.default.svc", "kubernetes.default", "kubernetes", "localhost"}
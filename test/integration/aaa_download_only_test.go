@@ -30,12 +30,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
-	"strings"
 	"testing"
 
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/image"
 	"k8s.io/minikube/pkg/minikube/localpath"
 )
 
@@ -123,12 +123,10 @@ func TestDownloadOnly(t *testing.T) {
 					t.Errorf("failed to get kubeadm images for %v: %+v", v, err)
 				}
 
+				cacheDir := filepath.Join(localpath.MiniPath(), "cache", "images")
 				for _, img := range imgs {
-					img = strings.Replace(img, ":", "_", 1) // for example kube-scheduler:v1.15.2 --> kube-scheduler_v1.15.2
-					fp := filepath.Join(localpath.MiniPath(), "cache", "images", img)
-					_, err := os.Stat(fp)
-					if err != nil {
-						t.Errorf("expected image file exist at %q but got error: %v", fp, err)
+					if !image.IsCached(cacheDir, img) {
+						t.Errorf("expected image %s to be cached in %q", img, cacheDir)
 					}
 				}
 			})